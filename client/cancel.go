@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrClientClosed is the cancellation cause of server requests still being
+// handled when the client is closed.
+var ErrClientClosed = errors.New("client closed")
+
+// WithSamplingTimeout bounds how long the sampling handler may take to
+// answer one request. When it expires, the handler's context is cancelled
+// and the server receives an error. Zero, the default, sets no bound beyond
+// the server's own cancellation.
+func WithSamplingTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.samplingTimeout = timeout
+	}
+}
+
+// incomingRequests tracks the server requests the client is handling, so
+// that they can be cancelled when the server cancels them or the client is
+// closed. The zero value is ready for use.
+type incomingRequests struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelCauseFunc
+	closed  bool
+}
+
+// start returns the context to handle the request with id in, and a
+// function to call once it has been handled.
+func (r *incomingRequests) start(ctx context.Context, id mcp.RequestId) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := id.String()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		cancel(ErrClientClosed)
+		return ctx, func() {}
+	}
+	if r.cancels == nil {
+		r.cancels = make(map[string]context.CancelCauseFunc)
+	}
+	r.cancels[key] = cancel
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.cancels, key)
+		r.mu.Unlock()
+		cancel(context.Canceled)
+	}
+}
+
+// cancel cancels the request with id, if it is still being handled.
+func (r *incomingRequests) cancel(id mcp.RequestId, cause error) {
+	r.mu.Lock()
+	cancel, ok := r.cancels[id.String()]
+	delete(r.cancels, id.String())
+	r.mu.Unlock()
+	if ok {
+		cancel(cause)
+	}
+}
+
+// close cancels all requests being handled and those started later.
+func (r *incomingRequests) close() {
+	r.mu.Lock()
+	cancels := r.cancels
+	r.cancels, r.closed = nil, true
+	r.mu.Unlock()
+	for _, cancel := range cancels {
+		cancel(ErrClientClosed)
+	}
+}
+
+// handleCancelled cancels the server request named by a
+// notifications/cancelled notification.
+func (c *Client) handleCancelled(notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok || id == nil {
+		return
+	}
+	cause := errors.New("request cancelled by server")
+	if reason, _ := notification.Params.AdditionalFields["reason"].(string); reason != "" {
+		cause = fmt.Errorf("request cancelled by server: %s", reason)
+	}
+	c.incoming.cancel(mcp.NewRequestId(id), cause)
+}
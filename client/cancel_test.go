@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// blockingSamplingHandler blocks until its context is done and reports the
+// cause.
+type blockingSamplingHandler struct {
+	started chan struct{}
+	causes  chan error
+}
+
+func newBlockingSamplingHandler() *blockingSamplingHandler {
+	return &blockingSamplingHandler{started: make(chan struct{}, 1), causes: make(chan error, 1)}
+}
+
+func (h *blockingSamplingHandler) CreateMessage(ctx context.Context, _ mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	h.started <- struct{}{}
+	<-ctx.Done()
+	h.causes <- context.Cause(ctx)
+	return nil, ctx.Err()
+}
+
+func samplingRequest(id int64) transport.JSONRPCRequest {
+	return transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(id),
+		Method:  string(mcp.MethodSamplingCreateMessage),
+		Params: map[string]any{
+			"messages":  []any{map[string]any{"role": "user", "content": map[string]any{"type": "text", "text": "hi"}}},
+			"maxTokens": 10,
+		},
+	}
+}
+
+func TestClient_CancelledNotificationCancelsSampling(t *testing.T) {
+	handler := newBlockingSamplingHandler()
+	c := &Client{samplingHandler: handler}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.handleIncomingRequest(t.Context(), samplingRequest(5))
+		errs <- err
+	}()
+	<-handler.started
+
+	// Request IDs decoded from JSON are float64.
+	c.handleCancelled(mcp.JSONRPCNotification{
+		Notification: mcp.Notification{
+			Method: string(mcp.MethodNotificationCancelled),
+			Params: mcp.NotificationParams{AdditionalFields: map[string]any{"requestId": float64(5), "reason": "timeout"}},
+		},
+	})
+
+	assert.EqualError(t, <-handler.causes, "request cancelled by server: timeout")
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestClient_CloseCancelsServerRequests(t *testing.T) {
+	handler := newBlockingSamplingHandler()
+	c := NewClient(newMockTransport(), WithSamplingHandler(handler))
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.handleIncomingRequest(t.Context(), samplingRequest(1))
+		errs <- err
+	}()
+	<-handler.started
+
+	require.NoError(t, c.Close())
+	assert.ErrorIs(t, <-handler.causes, ErrClientClosed)
+	assert.Error(t, <-errs)
+
+	// Requests arriving after Close are cancelled from the start.
+	go func() {
+		_, err := c.handleIncomingRequest(t.Context(), samplingRequest(2))
+		errs <- err
+	}()
+	<-handler.started
+	assert.ErrorIs(t, <-handler.causes, ErrClientClosed)
+	<-errs
+}
+
+func TestWithSamplingTimeout(t *testing.T) {
+	handler := newBlockingSamplingHandler()
+	c := NewClient(newMockTransport(), WithSamplingHandler(handler), WithSamplingTimeout(20*time.Millisecond))
+
+	_, err := c.handleIncomingRequest(t.Context(), samplingRequest(1))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	<-handler.started
+	assert.ErrorIs(t, <-handler.causes, context.DeadlineExceeded)
+}
+
+func TestClient_ServerCancelsSampling(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.EnableSampling()
+	mcpServer.AddTool(mcp.NewTool("ask"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		_, err := mcpServer.RequestSampling(ctx, mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+			Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("hi")}},
+			MaxTokens: 10,
+		}})
+		return mcp.NewToolResultErrorFromErr("sampling failed", err), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer httpServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL, transport.WithContinuousListening())
+	require.NoError(t, err)
+	handler := newBlockingSamplingHandler()
+	c := NewClient(trans, WithSamplingHandler(handler))
+	require.NoError(t, c.Start(t.Context()))
+	defer c.Close()
+	_, err = c.Initialize(t.Context(), mcp.InitializeRequest{Params: mcp.InitializeParams{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ClientInfo:      mcp.Implementation{Name: "test", Version: "1.0.0"},
+	}})
+	require.NoError(t, err)
+
+	result, err := c.CallTool(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "ask"}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	select {
+	case cause := <-handler.causes:
+		assert.Contains(t, cause.Error(), "request cancelled by server")
+	case <-time.After(5 * time.Second):
+		t.Fatal("sampling handler was not cancelled")
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,7 +18,8 @@ import (
 
 // Client implements the MCP client.
 type Client struct {
-	transport transport.Interface
+	transport   transport.Interface
+	transportMu sync.RWMutex // guards transport when it is replaced on reconnect
 
 	initialized        bool
 	notifications      []func(mcp.JSONRPCNotification)
@@ -25,12 +28,40 @@ type Client struct {
 	clientCapabilities mcp.ClientCapabilities
 	serverCapabilities mcp.ServerCapabilities
 	protocolVersion    string
+	serverInstructions string
 	samplingHandler    SamplingHandler
 	rootsHandler       RootsHandler
 	elicitationHandler ElicitationHandler
 	tracer             tracing.Tracer
 	propagator         tracing.Propagator
 	metaPropagator     tracing.MetaPropagator
+	logger             *slog.Logger
+	extensions         map[string]any
+	serverExtensions   mcp.Extensions
+
+	reconnectPolicy       *ReconnectPolicy
+	reconnectMu           sync.Mutex
+	reconnectGen          atomic.Uint64 // incremented after each successful reconnect
+	initRequest           *mcp.InitializeRequest
+	connectionLostHandler func(error)
+
+	requestTimeout  time.Duration
+	defaultTimeout  time.Duration
+	latencyTimeouts map[mcp.LatencyClass]time.Duration
+	latencyHints    atomic.Pointer[mcp.LatencyHints]
+	retryPolicy     *RetryPolicy
+
+	preferredEncodings *mcp.EncodingOffer
+	encoding           *mcp.EncodingSelection
+
+	latency latencyWindow
+
+	incoming        incomingRequests
+	samplingTimeout time.Duration
+
+	state mcp.ConnectionStateMachine
+
+	panicHandler func(HandlerPanic)
 }
 
 // ClientOption configures a Client during construction.
@@ -57,6 +88,9 @@ func WithSamplingHandler(handler SamplingHandler) ClientOption {
 func WithRootsHandler(handler RootsHandler) ClientOption {
 	return func(c *Client) {
 		c.rootsHandler = handler
+		if fs, ok := handler.(*FSRootsHandler); ok {
+			fs.attach(c)
+		}
 	}
 }
 
@@ -72,6 +106,7 @@ func WithElicitationHandler(handler ElicitationHandler) ClientOption {
 func WithSession() ClientOption {
 	return func(c *Client) {
 		c.initialized = true
+		c.state.Transition(mcp.ConnectionReady, nil)
 	}
 }
 
@@ -93,6 +128,7 @@ func NewClient(transport transport.Interface, options ...ClientOption) *Client {
 	for _, opt := range options {
 		opt(client)
 	}
+	client.state.OnChange(client.logStateChange)
 
 	return client
 }
@@ -105,30 +141,56 @@ func (c *Client) Start(ctx context.Context) error {
 	}
 
 	// Start is idempotent - transports handle being called multiple times
-	err := c.transport.Start(ctx)
+	return c.startTransport(ctx, c.currentTransport())
+}
+
+// startTransport starts t and wires the client's handlers into it.
+func (c *Client) startTransport(ctx context.Context, t transport.Interface) error {
+	err := t.Start(ctx)
 	if err != nil {
 		return err
 	}
 
-	c.transport.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+	t.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == string(mcp.MethodNotificationCancelled) {
+			c.handleCancelled(notification)
+		}
 		c.notifyMu.RLock()
 		defer c.notifyMu.RUnlock()
 		for _, handler := range c.notifications {
-			handler(notification)
+			c.callNotificationHandler(handler, notification)
 		}
 	})
 
 	// Set up request handler for bidirectional communication (e.g., sampling)
-	if bidirectional, ok := c.transport.(transport.BidirectionalInterface); ok {
+	if bidirectional, ok := t.(transport.BidirectionalInterface); ok {
 		bidirectional.SetRequestHandler(c.handleIncomingRequest)
 	}
+	c.installConnectionLostHandler(t)
 
 	return nil
 }
 
-// Close shuts down the client and closes the transport.
+// callNotificationHandler calls handler, recovering from a panic in it.
+func (c *Client) callNotificationHandler(handler func(mcp.JSONRPCNotification), notification mcp.JSONRPCNotification) {
+	defer c.recoverHandler(context.Background(), "notification", notification.Method, nil)
+	handler(notification)
+}
+
+// currentTransport returns the transport in use, which may change when the
+// client reconnects.
+func (c *Client) currentTransport() transport.Interface {
+	c.transportMu.RLock()
+	defer c.transportMu.RUnlock()
+	return c.transport
+}
+
+// Close shuts down the client and closes the transport. Handlers still
+// working on server requests have their contexts cancelled.
 func (c *Client) Close() error {
-	return c.transport.Close()
+	c.state.Transition(mcp.ConnectionClosed, nil)
+	c.incoming.close()
+	return c.currentTransport().Close()
 }
 
 // OnNotification registers a handler function to be called when notifications are received.
@@ -143,13 +205,10 @@ func (c *Client) OnNotification(
 
 // OnConnectionLost registers a handler function to be called when the connection is lost.
 // This is useful for handling HTTP2 idle timeout disconnections that should not be treated as errors.
+// The connection state is degraded before the handler is called.
 func (c *Client) OnConnectionLost(handler func(error)) {
-	type connectionLostSetter interface {
-		SetConnectionLostHandler(func(error))
-	}
-	if setter, ok := c.transport.(connectionLostSetter); ok {
-		setter.SetConnectionLostHandler(handler)
-	}
+	c.connectionLostHandler = handler
+	c.installConnectionLostHandler(c.currentTransport())
 }
 
 // sendRequest sends a JSON-RPC request to the server and waits for a response.
@@ -164,9 +223,13 @@ func (c *Client) sendRequest(
 		return nil, fmt.Errorf("client not initialized")
 	}
 
+	ctx, cancel := c.withOperationTimeout(ctx, method, params)
+	defer cancel()
+
 	id := c.requestID.Add(1)
 
 	ctx, header, span := c.startSendSpan(ctx, method, header)
+	endLog := c.startRequestLog(ctx, logMessageRequest, method, id)
 
 	request := transport.JSONRPCRequest{
 		JSONRPC: mcp.JSONRPC_VERSION,
@@ -176,20 +239,25 @@ func (c *Client) sendRequest(
 		Header:  header,
 	}
 
-	response, err := c.transport.SendRequest(ctx, request)
+	response, err := c.sendWithRetry(ctx, request)
 	if err != nil {
-		err = transport.NewError(err)
+		err = transport.NewError(operationError(ctx, err))
 		endSendSpan(span, err)
+		endLog(err)
 		return nil, err
 	}
 
 	if response.Error != nil {
-		err := response.Error.AsError()
+		// A server that observed the cancellation may answer with an error
+		// instead, which is still reported as a timeout.
+		err := operationError(ctx, response.Error.AsError())
 		endSendSpan(span, err)
+		endLog(err)
 		return nil, err
 	}
 
 	endSendSpan(span, nil)
+	endLog(nil)
 	return &response.Result, nil
 }
 
@@ -225,16 +293,20 @@ func (c *Client) Initialize(
 	if c.elicitationHandler != nil {
 		capabilities.Elicitation = &mcp.ElicitationCapability{}
 	}
+	c.addEncodingOffer(&capabilities)
+	extensionBlocks := c.addExtensions(&capabilities)
 
 	// Ensure we send a params object with all required fields
 	params := struct {
 		ProtocolVersion string                 `json:"protocolVersion"`
 		ClientInfo      mcp.Implementation     `json:"clientInfo"`
 		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
+		Meta            *mcp.Meta              `json:"_meta,omitempty"`
 	}{
 		ProtocolVersion: request.Params.ProtocolVersion,
 		ClientInfo:      request.Params.ClientInfo,
 		Capabilities:    capabilities,
+		Meta:            mcp.WithExtensionsMeta(request.Request.Params.Meta, extensionBlocks),
 	}
 
 	// By default, use client supported latest protocol version if version not specified
@@ -260,11 +332,17 @@ func (c *Client) Initialize(
 	// Store serverCapabilities and protocol version
 	c.serverCapabilities = result.Capabilities
 	c.protocolVersion = result.ProtocolVersion
+	c.serverInstructions = result.Instructions
 
 	// Set protocol version on HTTP transports
-	if httpConn, ok := c.transport.(transport.HTTPConnection); ok {
+	if httpConn, ok := c.currentTransport().(transport.HTTPConnection); ok {
 		httpConn.SetProtocolVersion(result.ProtocolVersion)
 	}
+	c.applyEncoding(capabilities, &result)
+	if err := c.applyExtensions(&result); err != nil {
+		return nil, fmt.Errorf("failed to read server extensions: %w", err)
+	}
+	c.applyLatencyHints(ctx, &result)
 
 	// Send initialized notification
 	notification := mcp.JSONRPCNotification{
@@ -274,7 +352,7 @@ func (c *Client) Initialize(
 		},
 	}
 
-	err = c.transport.SendNotification(ctx, notification)
+	err = c.currentTransport().SendNotification(ctx, notification)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to send initialized notification: %w",
@@ -282,13 +360,16 @@ func (c *Client) Initialize(
 		)
 	}
 
+	c.initRequest = &request
 	c.initialized = true
+	c.state.Transition(mcp.ConnectionReady, nil)
 	return &result, nil
 }
 
-// Ping sends a ping request to verify the server is responsive.
+// Ping sends a ping request to verify the server is responsive. Use PingRTT
+// to also get the round-trip time; both are recorded in Stats.
 func (c *Client) Ping(ctx context.Context) error {
-	_, err := c.sendRequest(ctx, string(mcp.MethodPing), nil, nil)
+	_, err := c.PingRTT(ctx)
 	return err
 }
 
@@ -504,6 +585,34 @@ func (c *Client) CallTool(
 	return mcp.ParseCallToolResult(response)
 }
 
+// CallToolAsTask calls a tool as a task: the server accepts the call and
+// runs the tool in the background. The returned task's result is fetched
+// with TaskResult, which waits for the task to finish. request.Params.Task
+// defaults to an empty TaskParams. The server must support task-augmented
+// tool calls; see mcp.TasksCapability.
+func (c *Client) CallToolAsTask(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CreateTaskResult, error) {
+	if request.Params.Task == nil {
+		request.Params.Task = &mcp.TaskParams{}
+	}
+	request.Params.Meta = c.injectMeta(ctx, request.Params.Meta)
+	response, err := c.sendRequest(ctx, string(mcp.MethodToolsCall), request.Params, outboundHeader(request.Header, request.Method))
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.CreateTaskResult
+	if err := json.Unmarshal(*response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if result.Task.TaskId == "" {
+		return nil, fmt.Errorf("server did not create a task")
+	}
+	return &result, nil
+}
+
 // SetLevel sets the server logging level.
 func (c *Client) SetLevel(
 	ctx context.Context,
@@ -543,7 +652,7 @@ func (c *Client) RootListChanges(
 		},
 	}
 
-	err := c.transport.SendNotification(ctx, notification)
+	err := c.currentTransport().SendNotification(ctx, notification)
 	if err != nil {
 		return fmt.Errorf(
 			"failed to send root list change notification: %w",
@@ -555,7 +664,15 @@ func (c *Client) RootListChanges(
 
 // handleIncomingRequest processes incoming requests from the server.
 // This is the main entry point for server-to-client requests like sampling and elicitation.
-func (c *Client) handleIncomingRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+// The handlers' context is cancelled when the server cancels the request
+// with notifications/cancelled or the client is closed.
+func (c *Client) handleIncomingRequest(ctx context.Context, request transport.JSONRPCRequest) (resp *transport.JSONRPCResponse, err error) {
+	endLog := c.startRequestLog(ctx, logMessageServerRequest, request.Method, request.ID.Value())
+	defer func() { endLog(err) }()
+	ctx, done := c.incoming.start(ctx, request.ID)
+	defer done()
+	defer c.recoverHandler(ctx, incomingHandlerName(request.Method), request.Method, &err)
+
 	switch request.Method {
 	case string(mcp.MethodSamplingCreateMessage):
 		return c.handleSamplingRequestTransport(ctx, request)
@@ -610,7 +727,13 @@ func (c *Client) handleSamplingRequestTransport(ctx context.Context, request tra
 	}
 
 	// Call the sampling handler
-	result, err := c.samplingHandler.CreateMessage(ctx, mcpRequest)
+	if c.samplingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.samplingTimeout)
+		defer cancel()
+	}
+	ctx = c.extractMeta(ctx, params.Meta)
+	result, err := createMessage(ctx, c.samplingHandler, mcpRequest, c.sendNotification)
 	if err != nil {
 		return nil, err
 	}
@@ -627,6 +750,12 @@ func (c *Client) handleSamplingRequestTransport(ctx context.Context, request tra
 	return response, nil
 }
 
+// sendNotification sends a notification to the server over the current
+// transport.
+func (c *Client) sendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return c.currentTransport().SendNotification(ctx, notification)
+}
+
 // handleListRootsRequestTransport handles list roots requests at the transport level.
 func (c *Client) handleListRootsRequestTransport(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
 	if c.rootsHandler == nil {
@@ -689,6 +818,7 @@ func (c *Client) handleElicitationRequestTransport(ctx context.Context, request
 	}
 
 	// Call the elicitation handler
+	ctx = c.extractMeta(ctx, params.Meta)
 	result, err := c.elicitationHandler.Elicit(ctx, mcpRequest)
 	if err != nil {
 		return nil, err
@@ -734,7 +864,7 @@ func listByPage[T any](
 // GetTransport gives access to the underlying transport layer.
 // Cast it to the specific transport type and obtain the other helper methods.
 func (c *Client) GetTransport() transport.Interface {
-	return c.transport
+	return c.currentTransport()
 }
 
 // GetServerCapabilities returns the server capabilities.
@@ -742,6 +872,14 @@ func (c *Client) GetServerCapabilities() mcp.ServerCapabilities {
 	return c.serverCapabilities
 }
 
+// ServerInstructions returns the instructions the server sent in its
+// initialize response, or an empty string if it sent none. Servers may change
+// their instructions at runtime; the new text is only visible after the
+// client initializes again.
+func (c *Client) ServerInstructions() string {
+	return c.serverInstructions
+}
+
 // GetClientCapabilities returns the client capabilities.
 func (c *Client) GetClientCapabilities() mcp.ClientCapabilities {
 	return c.clientCapabilities
@@ -750,13 +888,16 @@ func (c *Client) GetClientCapabilities() mcp.ClientCapabilities {
 // GetSessionId returns the session ID of the transport.
 // If the transport does not support sessions, it returns an empty string.
 func (c *Client) GetSessionId() string {
-	if c.transport == nil {
+	t := c.currentTransport()
+	if t == nil {
 		return ""
 	}
-	return c.transport.GetSessionId()
+	return t.GetSessionId()
 }
 
-// IsInitialized returns true if the client has been initialized.
+// IsInitialized returns true if the client has been initialized. It stays
+// true when the transport fails afterwards; use State to observe transport
+// health.
 func (c *Client) IsInitialized() bool {
 	return c.initialized
 }
@@ -774,12 +915,43 @@ func (c *Client) CancelTask(
 	return mcp.ParseCancelTaskResult(response)
 }
 
+// SubscribeTasks selects which task status notifications the server sends to
+// this session. It requires a server advertising the tasks.subscribe
+// capability, which is an extension to the MCP specification.
+func (c *Client) SubscribeTasks(
+	ctx context.Context,
+	request mcp.SubscribeTasksRequest,
+) error {
+	_, err := c.sendRequest(ctx, string(mcp.MethodTasksSubscribe), request.Params, outboundHeader(request.Header, request.Method))
+	return err
+}
+
+// ClaimTask transfers a task created by another session of the same
+// authenticated user to this session, so it can be polled again after a
+// reconnect. It requires a server advertising the tasks.claim capability,
+// which is an extension to the MCP specification.
+func (c *Client) ClaimTask(
+	ctx context.Context,
+	request mcp.ClaimTaskRequest,
+) (*mcp.ClaimTaskResult, error) {
+	response, err := c.sendRequest(ctx, string(mcp.MethodTasksClaim), request.Params, outboundHeader(request.Header, request.Method))
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.ParseClaimTaskResult(response)
+}
+
 // ListTasks returns the list of tasks
 func (c *Client) ListTasks(
 	ctx context.Context,
 	request mcp.ListTasksRequest,
 ) (*mcp.ListTasksResult, error) {
-	response, err := c.sendRequest(ctx, string(mcp.MethodTasksList), request.Params, outboundHeader(request.Header, request.Method))
+	params := struct {
+		mcp.PaginatedParams
+		mcp.TaskFilter
+	}{request.Params, request.Filter}
+	response, err := c.sendRequest(ctx, string(mcp.MethodTasksList), params, outboundHeader(request.Header, request.Method))
 	if err != nil {
 		return nil, err
 	}
@@ -787,6 +959,33 @@ func (c *Client) ListTasks(
 	return mcp.ParseListTasksResult(response)
 }
 
+// ListTasksAll lists all tasks matching the request filters by following
+// paginated responses.
+func (c *Client) ListTasksAll(
+	ctx context.Context,
+	request mcp.ListTasksRequest,
+) (*mcp.ListTasksResult, error) {
+	result, err := c.ListTasks(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	for result.NextCursor != "" {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			request.Params.Cursor = result.NextCursor
+			newPageRes, err := c.ListTasks(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			result.Tasks = append(result.Tasks, newPageRes.Tasks...)
+			result.NextCursor = newPageRes.NextCursor
+		}
+	}
+	return result, nil
+}
+
 // TaskResult returns finished task result
 func (c *Client) TaskResult(
 	ctx context.Context,
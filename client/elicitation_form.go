@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FormFieldType is the type of a form field. Elicitation schemas only allow
+// flat objects of primitive properties.
+type FormFieldType string
+
+const (
+	FormFieldString  FormFieldType = "string"
+	FormFieldNumber  FormFieldType = "number"
+	FormFieldInteger FormFieldType = "integer"
+	FormFieldBoolean FormFieldType = "boolean"
+)
+
+// FormField describes one property of a form mode elicitation's requested
+// schema, in a form a host can render without inspecting JSON Schema.
+type FormField struct {
+	// Name is the property name, used as the key in the accepted content.
+	Name        string
+	Title       string
+	Description string
+	Type        FormFieldType
+	Required    bool
+	// Default is the schema's default value, if any.
+	Default any
+	// Format is the string format, e.g. "email", "uri", "date" or
+	// "date-time".
+	Format string
+	// Enum lists the allowed values. EnumNames, when set, holds their
+	// display names in the same order.
+	Enum      []any
+	EnumNames []string
+	MinLength *int
+	MaxLength *int
+	Minimum   *float64
+	Maximum   *float64
+}
+
+// ElicitationForm is the parsed representation of a form mode elicitation
+// request.
+type ElicitationForm struct {
+	Message string
+	// Fields holds the schema's properties sorted by name, since JSON
+	// objects do not preserve the order they were declared in.
+	Fields []FormField
+	// Request is the original request, for anything the form does not
+	// capture.
+	Request mcp.ElicitationRequest
+}
+
+// Field returns the field with the given name.
+func (f *ElicitationForm) Field(name string) (FormField, bool) {
+	for _, field := range f.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FormField{}, false
+}
+
+// Defaults returns the default value of every field that has one, as a
+// starting point for the accepted content.
+func (f *ElicitationForm) Defaults() map[string]any {
+	values := make(map[string]any)
+	for _, field := range f.Fields {
+		if field.Default != nil {
+			values[field.Name] = field.Default
+		}
+	}
+	return values
+}
+
+// formSchema mirrors the subset of JSON Schema allowed in elicitation
+// requests.
+type formSchema struct {
+	Type       string                        `json:"type"`
+	Properties map[string]formPropertySchema `json:"properties"`
+	Required   []string                      `json:"required"`
+}
+
+type formPropertySchema struct {
+	Type        FormFieldType `json:"type"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Default     any           `json:"default"`
+	Format      string        `json:"format"`
+	Enum        []any         `json:"enum"`
+	EnumNames   []string      `json:"enumNames"`
+	MinLength   *int          `json:"minLength"`
+	MaxLength   *int          `json:"maxLength"`
+	Minimum     *float64      `json:"minimum"`
+	Maximum     *float64      `json:"maximum"`
+}
+
+// ParseElicitationForm parses the requested schema of a form mode
+// elicitation request. It returns an error for URL mode requests and for
+// schemas that are not flat objects of primitive properties.
+func ParseElicitationForm(request mcp.ElicitationRequest) (*ElicitationForm, error) {
+	if request.Params.Mode != "" && request.Params.Mode != mcp.ElicitationModeForm {
+		return nil, fmt.Errorf("not a form elicitation: mode %q", request.Params.Mode)
+	}
+	data, err := json.Marshal(request.Params.RequestedSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal requested schema: %w", err)
+	}
+	var schema formSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid requested schema: %w", err)
+	}
+	if schema.Type != "object" {
+		return nil, fmt.Errorf("invalid requested schema: type must be \"object\", got %q", schema.Type)
+	}
+
+	form := &ElicitationForm{
+		Message: request.Params.Message,
+		Fields:  make([]FormField, 0, len(schema.Properties)),
+		Request: request,
+	}
+	for name, prop := range schema.Properties {
+		switch prop.Type {
+		case FormFieldString, FormFieldNumber, FormFieldInteger, FormFieldBoolean:
+		case "":
+			if len(prop.Enum) == 0 {
+				return nil, fmt.Errorf("invalid requested schema: property %q has no type", name)
+			}
+			prop.Type = FormFieldString
+		default:
+			return nil, fmt.Errorf("invalid requested schema: property %q has unsupported type %q", name, prop.Type)
+		}
+		form.Fields = append(form.Fields, FormField{
+			Name:        name,
+			Title:       prop.Title,
+			Description: prop.Description,
+			Type:        prop.Type,
+			Required:    slices.Contains(schema.Required, name),
+			Default:     prop.Default,
+			Format:      prop.Format,
+			Enum:        prop.Enum,
+			EnumNames:   prop.EnumNames,
+			MinLength:   prop.MinLength,
+			MaxLength:   prop.MaxLength,
+			Minimum:     prop.Minimum,
+			Maximum:     prop.Maximum,
+		})
+	}
+	sort.Slice(form.Fields, func(i, j int) bool {
+		return form.Fields[i].Name < form.Fields[j].Name
+	})
+	return form, nil
+}
+
+// FormElicitationHandlerFunc handles a form mode elicitation request
+// parsed by ParseElicitationForm. Build the result with
+// mcp.NewElicitationAcceptResult, mcp.NewElicitationDeclineResult or
+// mcp.NewElicitationCancelResult, or their WithReason variants to tell the
+// server why the user did not answer.
+type FormElicitationHandlerFunc func(ctx context.Context, form *ElicitationForm) (*mcp.ElicitationResult, error)
+
+// formElicitationHandler adapts a FormElicitationHandlerFunc to
+// ElicitationHandler.
+type formElicitationHandler struct {
+	handle FormElicitationHandlerFunc
+}
+
+// NewFormElicitationHandler returns an ElicitationHandler that parses form
+// mode requests and passes them to handle, so hosts can render forms
+// generically. URL mode requests and requests whose schema cannot be
+// parsed are declined with mcp.ElicitationDeclineReasonNotApplicable.
+func NewFormElicitationHandler(handle FormElicitationHandlerFunc) ElicitationHandler {
+	return &formElicitationHandler{handle: handle}
+}
+
+func (h *formElicitationHandler) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	form, err := ParseElicitationForm(request)
+	if err != nil {
+		return mcp.NewElicitationDeclineResultWithReason(mcp.ElicitationDeclineReasonNotApplicable), nil
+	}
+	return h.handle(ctx, form)
+}
+
+// WithElicitationFormHandler sets an elicitation handler that receives
+// parsed forms, see NewFormElicitationHandler. When set, the client will
+// declare elicitation capability during initialization.
+func WithElicitationFormHandler(handle FormElicitationHandlerFunc) ClientOption {
+	return WithElicitationHandler(NewFormElicitationHandler(handle))
+}
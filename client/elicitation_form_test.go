@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseElicitationForm(t *testing.T) {
+	request := mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+		Message: "Project details",
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"projectName":  map[string]any{"type": "string", "title": "Project", "minLength": 1},
+				"framework":    map[string]any{"type": "string", "enum": []string{"react", "vue"}, "enumNames": []string{"React", "Vue"}, "default": "react"},
+				"includeTests": map[string]any{"type": "boolean", "description": "Include test setup", "default": true},
+				"replicas":     map[string]any{"type": "integer", "minimum": 1, "maximum": 5},
+			},
+			"required": []string{"projectName"},
+		},
+	}}
+
+	form, err := ParseElicitationForm(request)
+	require.NoError(t, err)
+	assert.Equal(t, "Project details", form.Message)
+
+	names := make([]string, len(form.Fields))
+	for i, field := range form.Fields {
+		names[i] = field.Name
+	}
+	assert.Equal(t, []string{"framework", "includeTests", "projectName", "replicas"}, names)
+
+	project, ok := form.Field("projectName")
+	require.True(t, ok)
+	assert.Equal(t, FormFieldString, project.Type)
+	assert.Equal(t, "Project", project.Title)
+	assert.True(t, project.Required)
+	require.NotNil(t, project.MinLength)
+	assert.Equal(t, 1, *project.MinLength)
+
+	framework, _ := form.Field("framework")
+	assert.Equal(t, []any{"react", "vue"}, framework.Enum)
+	assert.Equal(t, []string{"React", "Vue"}, framework.EnumNames)
+	assert.False(t, framework.Required)
+
+	replicas, _ := form.Field("replicas")
+	assert.Equal(t, FormFieldInteger, replicas.Type)
+	assert.Equal(t, 5.0, *replicas.Maximum)
+
+	_, ok = form.Field("missing")
+	assert.False(t, ok)
+	assert.Equal(t, map[string]any{"framework": "react", "includeTests": true}, form.Defaults())
+}
+
+func TestParseElicitationForm_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  mcp.ElicitationParams
+		wantErr string
+	}{
+		{
+			name:    "url mode",
+			params:  mcp.ElicitationParams{Mode: mcp.ElicitationModeURL, URL: "https://example.com", ElicitationID: "1"},
+			wantErr: "not a form elicitation",
+		},
+		{
+			name:    "not an object",
+			params:  mcp.ElicitationParams{RequestedSchema: map[string]any{"type": "string"}},
+			wantErr: "type must be",
+		},
+		{
+			name: "nested object",
+			params: mcp.ElicitationParams{RequestedSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"address": map[string]any{"type": "object"}},
+			}},
+			wantErr: "unsupported type",
+		},
+		{
+			name: "untyped property",
+			params: mcp.ElicitationParams{RequestedSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"note": map[string]any{}},
+			}},
+			wantErr: "has no type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseElicitationForm(mcp.ElicitationRequest{Params: tt.params})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewFormElicitationHandler(t *testing.T) {
+	var got *ElicitationForm
+	handler := NewFormElicitationHandler(func(ctx context.Context, form *ElicitationForm) (*mcp.ElicitationResult, error) {
+		got = form
+		values := form.Defaults()
+		values["name"] = "demo"
+		return mcp.NewElicitationAcceptResult(values), nil
+	})
+
+	result, err := handler.Elicit(context.Background(), mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+		Message: "Name?",
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":    map[string]any{"type": "string"},
+				"confirm": map[string]any{"type": "boolean", "default": false},
+			},
+		},
+	}})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, mcp.ElicitationResponseActionAccept, result.Action)
+	assert.Equal(t, map[string]any{"name": "demo", "confirm": false}, result.Content)
+
+	got = nil
+	result, err = handler.Elicit(context.Background(), mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+		Mode: mcp.ElicitationModeURL, URL: "https://example.com", ElicitationID: "1",
+	}})
+	require.NoError(t, err)
+	assert.Nil(t, got, "URL mode requests do not reach the form handler")
+	assert.Equal(t, mcp.ElicitationResponseActionDecline, result.Action)
+	assert.Equal(t, mcp.ElicitationDeclineReasonNotApplicable, result.Reason)
+
+	assert.Equal(t, mcp.ElicitationResponseActionCancel, mcp.NewElicitationCancelResult().Action)
+
+	c := &Client{}
+	WithElicitationFormHandler(func(context.Context, *ElicitationForm) (*mcp.ElicitationResult, error) {
+		return mcp.NewElicitationDeclineResult(), nil
+	})(c)
+	assert.NotNil(t, c.elicitationHandler)
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"maps"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithEncodingNegotiation makes the client offer the experimental encoding
+// capability (see mcp.ExperimentalEncodingKey) at initialize time. Only the
+// compression schemes and codecs in preferred that the transport can apply
+// are offered, in the order given. If the server does not understand the
+// capability, or the transport cannot apply any encoding, the client keeps
+// using mcp.DefaultEncoding.
+func WithEncodingNegotiation(preferred mcp.EncodingOffer) ClientOption {
+	return func(c *Client) {
+		c.preferredEncodings = &preferred
+	}
+}
+
+// Encoding returns the wire encoding negotiated with the server during
+// initialization, or mcp.DefaultEncoding if none was negotiated.
+func (c *Client) Encoding() mcp.EncodingSelection {
+	if c.encoding == nil {
+		return mcp.DefaultEncoding
+	}
+	return *c.encoding
+}
+
+// addEncodingOffer adds the client's encoding offer to capabilities, if the
+// client and its transport support any encoding.
+func (c *Client) addEncodingOffer(capabilities *mcp.ClientCapabilities) {
+	conn, ok := c.currentTransport().(transport.EncodingConnection)
+	if c.preferredEncodings == nil || !ok {
+		return
+	}
+	offer := c.preferredEncodings.Intersect(conn.SupportedEncodings())
+	if len(offer.Compression) == 0 && len(offer.Codecs) == 0 {
+		return
+	}
+
+	experimental := maps.Clone(capabilities.Experimental)
+	if experimental == nil {
+		experimental = make(map[string]any, 1)
+	}
+	experimental[mcp.ExperimentalEncodingKey] = offer
+	capabilities.Experimental = experimental
+}
+
+// applyEncoding records the server's encoding choice and applies it to the
+// transport.
+func (c *Client) applyEncoding(capabilities mcp.ClientCapabilities, result *mcp.InitializeResult) {
+	selection := mcp.DefaultEncoding
+	if _, offered := capabilities.Experimental[mcp.ExperimentalEncodingKey]; offered {
+		selection, _ = mcp.EncodingSelectionFromExperimental(result.Capabilities.Experimental)
+	}
+	c.encoding = &selection
+	if conn, ok := c.currentTransport().(transport.EncodingConnection); ok {
+		conn.SetEncoding(selection)
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WithEncodingNegotiation(t *testing.T) {
+	newServer := func(t *testing.T, opts ...server.ServerOption) (string, func() []string) {
+		mcpServer := server.NewMCPServer("test", "1.0.0", opts...)
+		handler := server.NewStreamableHTTPServer(mcpServer)
+		var mu sync.Mutex
+		var encodings []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				mu.Lock()
+				encodings = append(encodings, r.Header.Get("Content-Encoding"))
+				mu.Unlock()
+			}
+			handler.ServeHTTP(w, r)
+		}))
+		t.Cleanup(ts.Close)
+		return ts.URL, func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]string(nil), encodings...)
+		}
+	}
+	gzipOnly := mcp.EncodingOffer{Compression: []string{mcp.CompressionGzip}, Codecs: []string{mcp.CodecJSON}}
+
+	t.Run("compresses requests after negotiation", func(t *testing.T) {
+		url, encodings := newServer(t, server.WithEncodingNegotiation(gzipOnly))
+		tr, err := transport.NewStreamableHTTP(url)
+		require.NoError(t, err)
+		c := NewClient(tr, WithEncodingNegotiation(gzipOnly))
+		defer c.Close()
+		initializeTestClient(t, c)
+
+		assert.Equal(t, mcp.EncodingSelection{Compression: mcp.CompressionGzip, Codec: mcp.CodecJSON}, c.Encoding())
+		require.NoError(t, c.Ping(t.Context()))
+		got := encodings()
+		assert.Equal(t, "", got[0], "initialize is sent before negotiation")
+		assert.Equal(t, "gzip", got[len(got)-1])
+	})
+
+	t.Run("falls back when the server does not negotiate", func(t *testing.T) {
+		url, encodings := newServer(t)
+		tr, err := transport.NewStreamableHTTP(url)
+		require.NoError(t, err)
+		c := NewClient(tr, WithEncodingNegotiation(gzipOnly))
+		defer c.Close()
+		initializeTestClient(t, c)
+
+		assert.Equal(t, mcp.DefaultEncoding, c.Encoding())
+		require.NoError(t, c.Ping(t.Context()))
+		for _, encoding := range encodings() {
+			assert.Empty(t, encoding)
+		}
+	})
+}
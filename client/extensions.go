@@ -0,0 +1,69 @@
+package client
+
+import (
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithExtension makes the client support the named extension: it is listed
+// in the Extensions of the client capabilities at initialize time, with
+// block attached to the request's _meta (see mcp.ExtensionsMetaKey). If
+// the server lists the extension too, its block is available from
+// ServerExtension after Initialize. A nil block sends none.
+func WithExtension(name string, block any) ClientOption {
+	return func(c *Client) {
+		if c.extensions == nil {
+			c.extensions = make(map[string]any)
+		}
+		c.extensions[name] = block
+	}
+}
+
+// ServerExtension unmarshals the server's block of the named extension into
+// v. It reports false if the extension was not negotiated.
+func (c *Client) ServerExtension(name string, v any) (bool, error) {
+	return c.serverExtensions.Decode(name, v)
+}
+
+// addExtensions lists the client's extensions in capabilities and returns
+// their blocks.
+func (c *Client) addExtensions(capabilities *mcp.ClientCapabilities) map[string]any {
+	if len(c.extensions) == 0 {
+		return nil
+	}
+	extensions := maps.Clone(capabilities.Extensions)
+	if extensions == nil {
+		extensions = make(map[string]any, len(c.extensions))
+	}
+	blocks := make(map[string]any, len(c.extensions))
+	for name, block := range c.extensions {
+		extensions[name] = map[string]any{}
+		if block != nil {
+			blocks[name] = block
+		}
+	}
+	capabilities.Extensions = extensions
+	return blocks
+}
+
+// applyExtensions records the server's blocks of the extensions both sides
+// list.
+func (c *Client) applyExtensions(result *mcp.InitializeResult) error {
+	c.serverExtensions = nil
+	if len(c.extensions) == 0 {
+		return nil
+	}
+	blocks, err := mcp.ExtensionsFromMeta(result.Meta)
+	if err != nil {
+		return err
+	}
+	agreed := make(mcp.Extensions)
+	for name := range result.Capabilities.Extensions {
+		if _, ok := c.extensions[name]; ok {
+			agreed[name] = blocks[name]
+		}
+	}
+	c.serverExtensions = agreed
+	return nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Extensions(t *testing.T) {
+	type myco struct {
+		Region string `json:"region,omitempty"`
+		Tier   string `json:"tier,omitempty"`
+	}
+	srv := server.NewMCPServer("test-server", "1.0.0",
+		server.WithExtension("x-myco", myco{Region: "eu"}),
+		server.WithToolCapabilities(false),
+	)
+	srv.AddTool(mcp.NewTool("tier"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var block myco
+		session := server.ClientSessionFromContext(ctx).(server.SessionWithExtensions)
+		if _, err := session.Extension("x-myco", &block); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(block.Tier), nil
+	})
+
+	httpServer := server.NewTestStreamableHTTPServer(srv)
+	defer httpServer.Close()
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	require.NoError(t, err)
+	c := NewClient(trans,
+		WithExtension("x-myco", myco{Tier: "gold"}),
+		WithExtension("x-unknown", true),
+	)
+	require.NoError(t, c.Start(t.Context()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = c.Initialize(t.Context(), initRequest)
+	require.NoError(t, err)
+
+	var block myco
+	ok, err := c.ServerExtension("x-myco", &block)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "eu", block.Region)
+
+	ok, err = c.ServerExtension("x-unknown", &block)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	result, err := c.CallTool(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "tier"}})
+	require.NoError(t, err)
+	assert.Equal(t, "gold", result.Content[0].(mcp.TextContent).Text)
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -17,7 +18,7 @@ func NewInProcessClient(server *server.MCPServer) (*Client, error) {
 // NewInProcessClientWithSamplingHandler creates an in-process client with sampling support
 func NewInProcessClientWithSamplingHandler(server *server.MCPServer, handler SamplingHandler) (*Client, error) {
 	// Create a wrapper that implements server.SamplingHandler
-	serverHandler := &inProcessSamplingHandlerWrapper{handler: handler}
+	serverHandler := &inProcessSamplingHandlerWrapper{handler: handler, server: server}
 
 	inProcessTransport := transport.NewInProcessTransportWithOptions(server,
 		transport.WithSamplingHandler(serverHandler))
@@ -31,8 +32,20 @@ func NewInProcessClientWithSamplingHandler(server *server.MCPServer, handler Sam
 // inProcessSamplingHandlerWrapper wraps client.SamplingHandler to implement server.SamplingHandler
 type inProcessSamplingHandlerWrapper struct {
 	handler SamplingHandler
+	server  *server.MCPServer
 }
 
 func (w *inProcessSamplingHandlerWrapper) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
-	return w.handler.CreateMessage(ctx, request)
+	return createMessage(ctx, w.handler, request, w.sendNotification)
+}
+
+// sendNotification delivers a notification to the server within the
+// session of the sampling request carried by ctx.
+func (w *inProcessSamplingHandlerWrapper) sendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	w.server.HandleMessage(ctx, data)
+	return nil
 }
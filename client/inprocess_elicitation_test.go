@@ -2,11 +2,14 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockElicitationHandler implements ElicitationHandler for testing
@@ -175,6 +178,63 @@ func TestInProcessElicitation(t *testing.T) {
 	}
 }
 
+func TestInProcessTypedElicitation(t *testing.T) {
+	type projectDetails struct {
+		ProjectName  string `json:"projectName" jsonschema:"Name of the project" minLength:"1"`
+		Framework    string `json:"framework,omitempty" enum:"react,vue,angular,none"`
+		IncludeTests *bool  `json:"includeTests,omitempty" default:"true"`
+	}
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithElicitation())
+	mcpServer.AddTool(mcp.NewTool("create_project"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		details, result, err := server.RequestTypedElicitation[projectDetails](ctx, mcpServer, "Project details?")
+		if err != nil {
+			return nil, err
+		}
+		if result.Action != mcp.ElicitationResponseActionAccept {
+			return mcp.NewToolResultError(string(result.Action)), nil
+		}
+		require.NotNil(t, details.IncludeTests)
+		return mcp.NewToolResultText(fmt.Sprintf("%s %s %v", details.ProjectName, details.Framework, *details.IncludeTests)), nil
+	})
+
+	var form *ElicitationForm
+	handler := NewFormElicitationHandler(func(ctx context.Context, f *ElicitationForm) (*mcp.ElicitationResult, error) {
+		form = f
+		values := f.Defaults()
+		values["projectName"] = "demo"
+		values["framework"] = "vue"
+		return mcp.NewElicitationAcceptResult(values), nil
+	})
+	client := NewInProcessClientWithElicitationHandler(mcpServer, handler)
+	defer client.Close()
+	require.NoError(t, client.Start(t.Context()))
+	_, err := client.Initialize(t.Context(), mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			ClientInfo:      mcp.Implementation{Name: "test-client", Version: "1.0.0"},
+			Capabilities:    mcp.ClientCapabilities{Elicitation: &mcp.ElicitationCapability{}},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := client.CallTool(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "create_project"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "elicitation was not accepted: %v", result.Content)
+	assert.Equal(t, "demo vue true", result.Content[0].(mcp.TextContent).Text)
+
+	require.NotNil(t, form)
+	name, ok := form.Field("projectName")
+	require.True(t, ok)
+	assert.True(t, name.Required)
+	require.NotNil(t, name.MinLength)
+	assert.Equal(t, 1, *name.MinLength)
+	tests, ok := form.Field("includeTests")
+	require.True(t, ok)
+	assert.Equal(t, FormFieldBoolean, tests.Type)
+	assert.Equal(t, true, tests.Default)
+}
+
 // NewInProcessClientWithElicitationHandler creates an in-process client with elicitation support
 func NewInProcessClientWithElicitationHandler(server *server.MCPServer, handler ElicitationHandler) *Client {
 	// Create a wrapper that implements server.ElicitationHandler
@@ -4,8 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInProcessMCPClient(t *testing.T) {
@@ -43,21 +47,9 @@ func TestInProcessMCPClient(t *testing.T) {
 		}, nil
 	})
 
-	mcpServer.AddResource(
-		mcp.Resource{
-			URI:  "resource://testresource",
-			Name: "My Resource",
-		},
-		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-			return []mcp.ResourceContents{
-				mcp.TextResourceContents{
-					URI:      "resource://testresource",
-					MIMEType: "text/plain",
-					Text:     "test content",
-				},
-			}, nil
-		},
-	)
+	fixtures, err := servertest.LoadFixtures("testdata/fixtures")
+	require.NoError(t, err)
+	fixtures.Register(mcpServer)
 
 	mcpServer.AddPrompt(
 		mcp.Prompt{
@@ -419,3 +411,77 @@ func TestInProcessMCPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestInProcessMCPClient_ServerInstructions(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithInstructions("use the tools"))
+
+	client, err := NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Start(t.Context()))
+
+	assert.Empty(t, client.ServerInstructions())
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(t.Context(), initRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, "use the tools", client.ServerInstructions())
+}
+
+func TestInProcessMCPClient_ListTasksAll(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithTaskCapabilities(true, true, true),
+		server.WithPaginationLimit(2),
+	)
+	release := make(chan struct{})
+	defer close(release)
+	for _, name := range []string{"build", "deploy"} {
+		mcpServer.AddTaskTool(
+			mcp.NewTool(name, mcp.WithTaskSupport(mcp.TaskSupportRequired)),
+			func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CreateTaskResult, error) {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return &mcp.CreateTaskResult{}, nil
+			},
+		)
+	}
+
+	client, err := NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Start(t.Context()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(t.Context(), initRequest)
+	require.NoError(t, err)
+
+	for i, name := range []string{"build", "build", "build", "deploy"} {
+		_, err := client.GetTransport().SendRequest(t.Context(), transport.JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      mcp.NewRequestId(int64(100 + i)),
+			Method:  string(mcp.MethodToolsCall),
+			Params:  map[string]any{"name": name, "task": map[string]any{}},
+		})
+		require.NoError(t, err)
+	}
+
+	page, err := client.ListTasks(t.Context(), mcp.ListTasksRequest{})
+	require.NoError(t, err)
+	assert.Len(t, page.Tasks, 2)
+	assert.NotEmpty(t, page.NextCursor)
+
+	all, err := client.ListTasksAll(t.Context(), mcp.ListTasksRequest{})
+	require.NoError(t, err)
+	assert.Len(t, all.Tasks, 4)
+	assert.Empty(t, all.NextCursor)
+
+	builds, err := client.ListTasksAll(t.Context(), mcp.ListTasksRequest{
+		Filter: mcp.TaskFilter{ToolName: "build", Status: []mcp.TaskStatus{mcp.TaskStatusWorking}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, builds.Tasks, 3)
+}
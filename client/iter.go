@@ -151,3 +151,39 @@ func (c *Client) IterPrompts(
 		}
 	}
 }
+
+// ListToolsAll returns every tool of the server, following nextCursor
+// until the last page.
+func (c *Client) ListToolsAll(ctx context.Context) ([]mcp.Tool, error) {
+	return collectAll(c.IterTools(ctx, mcp.ListToolsRequest{}))
+}
+
+// ListResourcesAll returns every resource of the server, following
+// nextCursor until the last page.
+func (c *Client) ListResourcesAll(ctx context.Context) ([]mcp.Resource, error) {
+	return collectAll(c.IterResources(ctx, mcp.ListResourcesRequest{}))
+}
+
+// ListResourceTemplatesAll returns every resource template of the server,
+// following nextCursor until the last page.
+func (c *Client) ListResourceTemplatesAll(ctx context.Context) ([]mcp.ResourceTemplate, error) {
+	return collectAll(c.IterResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{}))
+}
+
+// ListPromptsAll returns every prompt of the server, following nextCursor
+// until the last page.
+func (c *Client) ListPromptsAll(ctx context.Context) ([]mcp.Prompt, error) {
+	return collectAll(c.IterPrompts(ctx, mcp.ListPromptsRequest{}))
+}
+
+// collectAll drains seq, stopping at the first error.
+func collectAll[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
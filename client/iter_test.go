@@ -169,3 +169,30 @@ func TestClient_IterPrompts(t *testing.T) {
 	}
 	assert.Len(t, names, 5)
 }
+
+func TestClient_ListAll(t *testing.T) {
+	client := newIterTestClient(t, 2, 5)
+
+	tools, err := client.ListToolsAll(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, tools, 5)
+
+	resources, err := client.ListResourcesAll(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, resources, 5)
+
+	templates, err := client.ListResourceTemplatesAll(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, templates, 5)
+
+	prompts, err := client.ListPromptsAll(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, prompts, 5)
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, err := client.ListToolsAll(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultLatencyWindow is the number of ping round trips kept by a client
+// unless configured with WithLatencyWindow.
+const DefaultLatencyWindow = 32
+
+// WithLatencyWindow sets how many of the most recent ping round trips
+// Stats summarizes. Defaults to DefaultLatencyWindow.
+func WithLatencyWindow(n int) ClientOption {
+	return func(c *Client) {
+		c.latency.size = n
+	}
+}
+
+// LatencyStats summarizes the round-trip times of recent pings.
+type LatencyStats struct {
+	// Samples is the number of round trips in the window.
+	Samples int
+	Last    time.Duration
+	Min     time.Duration
+	Max     time.Duration
+	Mean    time.Duration
+	P50     time.Duration
+	P95     time.Duration
+}
+
+// ClientStats describes the quality of the connection to the server.
+type ClientStats struct {
+	// Latency summarizes the successful pings in the rolling window.
+	Latency LatencyStats
+	// LastPing is when the last ping completed, successfully or not.
+	LastPing time.Time
+	// FailedPings counts the pings that failed since the client was
+	// created.
+	FailedPings int
+}
+
+// latencyWindow is a ring of the most recent ping round trips.
+type latencyWindow struct {
+	mu       sync.Mutex
+	size     int
+	samples  []time.Duration
+	next     int
+	lastPing time.Time
+	last     time.Duration
+	failed   int
+}
+
+func (w *latencyWindow) record(rtt time.Duration, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastPing = time.Now()
+	if err != nil {
+		w.failed++
+		return
+	}
+	size := w.size
+	if size <= 0 {
+		size = DefaultLatencyWindow
+	}
+	w.last = rtt
+	if len(w.samples) < size {
+		w.samples = append(w.samples, rtt)
+		return
+	}
+	w.samples[w.next] = rtt
+	w.next = (w.next + 1) % size
+}
+
+func (w *latencyWindow) stats() ClientStats {
+	w.mu.Lock()
+	samples := slices.Clone(w.samples)
+	stats := ClientStats{LastPing: w.lastPing, FailedPings: w.failed}
+	stats.Latency.Last = w.last
+	w.mu.Unlock()
+
+	if len(samples) == 0 {
+		return stats
+	}
+	slices.Sort(samples)
+	var total time.Duration
+	for _, rtt := range samples {
+		total += rtt
+	}
+	stats.Latency.Samples = len(samples)
+	stats.Latency.Min = samples[0]
+	stats.Latency.Max = samples[len(samples)-1]
+	stats.Latency.Mean = total / time.Duration(len(samples))
+	stats.Latency.P50 = percentile(samples, 50)
+	stats.Latency.P95 = percentile(samples, 95)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// PingRTT sends a ping request and returns the measured round-trip time.
+// The result is recorded in the rolling window summarized by Stats.
+func (c *Client) PingRTT(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	_, err := c.sendRequest(ctx, string(mcp.MethodPing), nil, nil)
+	rtt := time.Since(start)
+	c.latency.record(rtt, err)
+	if err != nil {
+		return 0, err
+	}
+	return rtt, nil
+}
+
+// Stats returns the latency of the recent pings sent with Ping or PingRTT,
+// for example to show connection quality in a host UI.
+func (c *Client) Stats() ClientStats {
+	return c.latency.stats()
+}
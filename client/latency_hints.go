@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultLatencyTimeouts returns the timeouts WithLatencyTimeouts uses when
+// given none: 30 seconds for fast requests, 10 minutes for slow ones and no
+// timeout for long-running ones.
+func DefaultLatencyTimeouts() map[mcp.LatencyClass]time.Duration {
+	return map[mcp.LatencyClass]time.Duration{
+		mcp.LatencyFast:        30 * time.Second,
+		mcp.LatencySlow:        10 * time.Minute,
+		mcp.LatencyLongRunning: 0,
+	}
+}
+
+// WithLatencyTimeouts makes the client pick the default timeout of each
+// operation from the server's latency hints (see LatencyHint): operations
+// whose class is in timeouts are bounded by its duration instead of the one
+// set with WithDefaultTimeout, and a zero duration disables the timeout.
+// Operations without a hint keep the default timeout. A nil map uses
+// DefaultLatencyTimeouts. ContextWithTimeout still overrides both.
+func WithLatencyTimeouts(timeouts map[mcp.LatencyClass]time.Duration) ClientOption {
+	return func(c *Client) {
+		if timeouts == nil {
+			timeouts = DefaultLatencyTimeouts()
+		}
+		c.latencyTimeouts = timeouts
+	}
+}
+
+// LatencyHint returns the latency class the server advertised at
+// initialize time for requests of method and, for tools/call, for calls of
+// tool. It reports false if the server gave no hint. Hosts can use it to
+// show a spinner for fast and slow requests, and to run long-running tool
+// calls as tasks when the server supports them.
+func (c *Client) LatencyHint(method, tool string) (mcp.LatencyClass, bool) {
+	hints := c.latencyHints.Load()
+	if hints == nil {
+		return "", false
+	}
+	return hints.Class(method, tool)
+}
+
+// applyLatencyHints records the latency hints in the capabilities of the
+// initialize result. Hints are advisory, so malformed ones are logged and
+// ignored rather than failing the initialization.
+func (c *Client) applyLatencyHints(ctx context.Context, result *mcp.InitializeResult) {
+	hints, _, err := mcp.LatencyHintsFromMeta(result.Capabilities.Meta)
+	if err != nil {
+		c.logLatencyHint(ctx, err)
+		hints = mcp.LatencyHints{}
+	}
+	for method, class := range hints.Methods {
+		if !class.Valid() {
+			c.logLatencyHint(ctx, fmt.Errorf("unknown latency class %q for method %s", class, method))
+			delete(hints.Methods, method)
+		}
+	}
+	for tool, class := range hints.Tools {
+		if !class.Valid() {
+			c.logLatencyHint(ctx, fmt.Errorf("unknown latency class %q for tool %s", class, tool))
+			delete(hints.Tools, tool)
+		}
+	}
+	c.latencyHints.Store(&hints)
+}
+
+// logLatencyHint emits one line for a latency hint that was ignored.
+func (c *Client) logLatencyHint(ctx context.Context, err error) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelWarn, logMessageLatencyHints,
+		slog.String(logKeyError, err.Error()),
+	)
+}
+
+// latencyTimeout returns the timeout WithLatencyTimeouts picks for a
+// request, and false if it picks none.
+func (c *Client) latencyTimeout(method string, params any) (time.Duration, bool) {
+	if c.latencyTimeouts == nil {
+		return 0, false
+	}
+	var tool string
+	if p, ok := params.(mcp.CallToolParams); ok {
+		tool = p.Name
+	}
+	class, ok := c.LatencyHint(method, tool)
+	if !ok {
+		return 0, false
+	}
+	d, ok := c.latencyTimeouts[class]
+	return d, ok
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_LatencyHints(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithMethodLatency(string(mcp.MethodToolsCall), mcp.LatencyFast),
+		server.WithToolLatency("build", mcp.LatencySlow),
+	)
+	sleep := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return mcp.NewToolResultText("done"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	mcpServer.AddTool(mcp.NewTool("lookup"), sleep)
+	mcpServer.AddTool(mcp.NewTool("build"), sleep)
+	call := func(c *Client, name string) error {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		_, err := c.CallTool(t.Context(), request)
+		return err
+	}
+
+	c := NewClient(transport.NewInProcessTransport(mcpServer),
+		WithDefaultTimeout(time.Hour),
+		WithLatencyTimeouts(map[mcp.LatencyClass]time.Duration{
+			mcp.LatencyFast: 20 * time.Millisecond,
+			mcp.LatencySlow: 0,
+		}),
+	)
+	initializeTestClient(t, c)
+
+	class, ok := c.LatencyHint(string(mcp.MethodToolsCall), "build")
+	require.True(t, ok)
+	assert.Equal(t, mcp.LatencySlow, class)
+	class, ok = c.LatencyHint(string(mcp.MethodToolsCall), "lookup")
+	require.True(t, ok)
+	assert.Equal(t, mcp.LatencyFast, class)
+	_, ok = c.LatencyHint(string(mcp.MethodResourcesRead), "")
+	assert.False(t, ok)
+
+	assert.ErrorIs(t, call(c, "lookup"), ErrOperationTimeout, "fast calls get the fast timeout")
+	assert.NoError(t, call(c, "build"), "slow calls are not bounded")
+
+	ctx, cancel := c.withOperationTimeout(t.Context(), string(mcp.MethodPing), nil)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Greater(t, time.Until(deadline), time.Minute, "unhinted methods keep the default timeout")
+}
+
+func TestClient_LatencyHints_Invalid(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithMethodLatency(string(mcp.MethodResourcesRead), mcp.LatencySlow),
+		server.WithToolLatency("build", "glacial"),
+	)
+	var buf bytes.Buffer
+	c := NewClient(transport.NewInProcessTransport(mcpServer),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+	initializeTestClient(t, c)
+
+	class, ok := c.LatencyHint(string(mcp.MethodResourcesRead), "")
+	require.True(t, ok)
+	assert.Equal(t, mcp.LatencySlow, class)
+	_, ok = c.LatencyHint(string(mcp.MethodToolsCall), "build")
+	assert.False(t, ok, "unknown classes are ignored")
+	assert.Contains(t, buf.String(), `"msg":"mcp.latency_hints"`)
+	assert.Contains(t, buf.String(), "glacial")
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_PingStats(t *testing.T) {
+	flaky := &flakyTransport{Interface: transport.NewInProcessTransport(server.NewMCPServer("test-server", "1.0.0"))}
+	c := NewClient(flaky, WithLatencyWindow(3))
+	require.NoError(t, c.Start(t.Context()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := c.Initialize(t.Context(), initRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, ClientStats{}, c.Stats())
+
+	rtt, err := c.PingRTT(t.Context())
+	require.NoError(t, err)
+	assert.Positive(t, rtt)
+	require.NoError(t, c.Ping(t.Context()))
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Latency.Samples)
+	assert.LessOrEqual(t, stats.Latency.Min, stats.Latency.Mean)
+	assert.LessOrEqual(t, stats.Latency.Mean, stats.Latency.Max)
+	assert.False(t, stats.LastPing.IsZero())
+
+	for range 5 {
+		require.NoError(t, c.Ping(t.Context()))
+	}
+	assert.Equal(t, 3, c.Stats().Latency.Samples, "window keeps the most recent pings")
+
+	flaky.broken.Store(true)
+	_, err = c.PingRTT(t.Context())
+	require.Error(t, err)
+	assert.Equal(t, 1, c.Stats().FailedPings)
+	assert.Equal(t, 3, c.Stats().Latency.Samples)
+}
+
+func TestLatencyWindow_Stats(t *testing.T) {
+	var w latencyWindow
+	for _, ms := range []int{50, 10, 30, 20, 40} {
+		w.record(time.Duration(ms)*time.Millisecond, nil)
+	}
+	stats := w.stats().Latency
+	assert.Equal(t, LatencyStats{
+		Samples: 5,
+		Last:    40 * time.Millisecond,
+		Min:     10 * time.Millisecond,
+		Max:     50 * time.Millisecond,
+		Mean:    30 * time.Millisecond,
+		P50:     30 * time.Millisecond,
+		P95:     50 * time.Millisecond,
+	}, stats)
+}
+
+func TestPool_LatencyRouting(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	factory := func(ctx context.Context) (*Client, error) {
+		c, err := NewInProcessClient(mcpServer)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Start(ctx); err != nil {
+			return nil, err
+		}
+		initRequest := mcp.InitializeRequest{}
+		initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+		if _, err := c.Initialize(ctx, initRequest); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	pool, err := NewPool(factory, 3, WithPoolLatencyRouting())
+	require.NoError(t, err)
+	defer pool.Close()
+
+	clients := make([]*Client, len(pool.slots))
+	for i, slot := range pool.slots {
+		clients[i] = slot.get()
+	}
+	clients[0].latency.record(30*time.Millisecond, nil)
+	clients[1].latency.record(5*time.Millisecond, nil)
+	clients[2].latency.record(20*time.Millisecond, nil)
+
+	for range 3 {
+		c, err := pool.Client()
+		require.NoError(t, err)
+		assert.Same(t, clients[1], c)
+	}
+}
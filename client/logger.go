@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	logKeyMethod          = "mcp.method"
+	logKeyRequestID       = "mcp.request.id"
+	logKeySessionID       = "mcp.session.id"
+	logKeyDurationSeconds = "duration_s"
+	logKeyOutcome         = "outcome"
+	logKeyError           = "error"
+	logKeyAttempt         = "attempt"
+	logKeyFrom            = "from"
+	logKeyTo              = "to"
+
+	logMessageRequest       = "mcp.request"
+	logMessageServerRequest = "mcp.server_request"
+	logMessageReconnect     = "mcp.reconnect"
+	logMessageState         = "mcp.connection_state"
+	logMessageLatencyHints  = "mcp.latency_hints"
+
+	logOutcomeOK    = "ok"
+	logOutcomeError = "error"
+)
+
+// WithLogger installs a structured logger on the client. The client emits:
+//
+//   - One mcp.request line per request sent to the server, at level INFO,
+//     with attributes mcp.method, mcp.request.id, mcp.session.id (when
+//     set), duration_s, outcome (ok|error), and error (when set).
+//   - One mcp.server_request line per request received from the server,
+//     such as sampling or elicitation, with the same attributes.
+//   - One mcp.reconnect line per reconnection attempt, at level INFO when
+//     it succeeds and WARN when it fails, with attributes attempt and error.
+//   - One mcp.connection_state line per transition of the connection state
+//     (see Client.State), at level WARN when it degrades and INFO
+//     otherwise, with attributes from, to, and error (when set).
+//   - One mcp.latency_hints line per malformed latency hint the server
+//     advertised, at level WARN, with attribute error. The hint is ignored.
+//
+// A nil logger is treated as a no-op (no lines are emitted). The logger is
+// independent of the transport's; configure those with the transport's
+// logger option, such as transport.WithCommandLogger.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// startRequestLog returns a finalizer that emits one line for a request
+// with the given outcome. When no logger is installed the finalizer is a
+// no-op.
+func (c *Client) startRequestLog(ctx context.Context, msg, method string, id any) func(error) {
+	logger := c.logger
+	if logger == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		attrs := []slog.Attr{
+			slog.String(logKeyMethod, method),
+			slog.Any(logKeyRequestID, id),
+		}
+		if sessionID := c.GetSessionId(); sessionID != "" {
+			attrs = append(attrs, slog.String(logKeySessionID, sessionID))
+		}
+		attrs = append(attrs, slog.Float64(logKeyDurationSeconds, time.Since(start).Seconds()))
+		if err != nil {
+			attrs = append(attrs,
+				slog.String(logKeyOutcome, logOutcomeError),
+				slog.String(logKeyError, err.Error()),
+			)
+		} else {
+			attrs = append(attrs, slog.String(logKeyOutcome, logOutcomeOK))
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, msg, attrs...)
+	}
+}
+
+// logReconnect emits one line for a reconnection attempt.
+func (c *Client) logReconnect(ctx context.Context, attempt int, err error) {
+	if c.logger == nil {
+		return
+	}
+	if err != nil {
+		c.logger.LogAttrs(ctx, slog.LevelWarn, logMessageReconnect,
+			slog.Int(logKeyAttempt, attempt),
+			slog.String(logKeyOutcome, logOutcomeError),
+			slog.String(logKeyError, err.Error()),
+		)
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelInfo, logMessageReconnect,
+		slog.Int(logKeyAttempt, attempt),
+		slog.String(logKeyOutcome, logOutcomeOK),
+	)
+}
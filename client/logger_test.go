@@ -0,0 +1,59 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLogger_RequestLines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	c := NewClient(transport.NewInProcessTransport(server.NewMCPServer("test-server", "1.0.0")), WithLogger(logger))
+	require.NoError(t, c.Start(t.Context()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := c.Initialize(t.Context(), initRequest)
+	require.NoError(t, err)
+
+	_, err = c.ListTools(t.Context(), mcp.ListToolsRequest{})
+	require.Error(t, err, "server has no tool capability")
+
+	var lines []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		lines = append(lines, m)
+	}
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, logMessageRequest, lines[0]["msg"])
+	assert.Equal(t, "initialize", lines[0][logKeyMethod])
+	assert.EqualValues(t, 1, lines[0][logKeyRequestID])
+	assert.Equal(t, logOutcomeOK, lines[0][logKeyOutcome])
+	assert.Contains(t, lines[0], logKeyDurationSeconds)
+
+	assert.Equal(t, logMessageState, lines[1]["msg"])
+	assert.Equal(t, string(mcp.ConnectionConnecting), lines[1][logKeyFrom])
+	assert.Equal(t, string(mcp.ConnectionReady), lines[1][logKeyTo])
+
+	assert.Equal(t, "tools/list", lines[2][logKeyMethod])
+	assert.Equal(t, logOutcomeError, lines[2][logKeyOutcome])
+	assert.NotEmpty(t, lines[2][logKeyError])
+}
+
+func TestWithLogger_NilIsNoOp(t *testing.T) {
+	c := NewClient(transport.NewInProcessTransport(server.NewMCPServer("test-server", "1.0.0")), WithLogger(nil))
+	assert.Nil(t, c.logger)
+	c.startRequestLog(t.Context(), logMessageRequest, "ping", 1)(nil)
+	c.logReconnect(t.Context(), 1, nil)
+}
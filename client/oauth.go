@@ -22,6 +22,18 @@ type MemoryTokenStore = transport.MemoryTokenStore
 // NewMemoryTokenStore is a convenience function that wraps transport.NewMemoryTokenStore
 var NewMemoryTokenStore = transport.NewMemoryTokenStore
 
+// FileTokenStore is a convenience type that wraps transport.FileTokenStore
+type FileTokenStore = transport.FileTokenStore
+
+// NewFileTokenStore is a convenience function that wraps transport.NewFileTokenStore
+var NewFileTokenStore = transport.NewFileTokenStore
+
+// KeychainTokenStore is a convenience type that wraps transport.KeychainTokenStore
+type KeychainTokenStore = transport.KeychainTokenStore
+
+// NewKeychainTokenStore is a convenience function that wraps transport.NewKeychainTokenStore
+var NewKeychainTokenStore = transport.NewKeychainTokenStore
+
 // NewOAuthStreamableHttpClient creates a new streamable-http-based MCP client with OAuth support.
 // Returns an error if the URL is invalid.
 func NewOAuthStreamableHttpClient(baseURL string, oauthConfig OAuthConfig, options ...transport.StreamableHTTPCOption) (*Client, error) {
@@ -48,6 +60,12 @@ func NewOAuthSSEClient(baseURL string, oauthConfig OAuthConfig, options ...trans
 	return NewClient(trans), nil
 }
 
+// DeviceAuthorization is a convenience type that wraps transport.DeviceAuthorization
+type DeviceAuthorization = transport.DeviceAuthorization
+
+// GrantTypeClientCredentials selects the client credentials grant in OAuthConfig.GrantType
+const GrantTypeClientCredentials = transport.GrantTypeClientCredentials
+
 // GenerateCodeVerifier generates a code verifier for PKCE
 var GenerateCodeVerifier = transport.GenerateCodeVerifier
 
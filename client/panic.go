@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const logMessagePanic = "mcp.handler_panic"
+
+// HandlerPanic describes a panic recovered from a client handler.
+type HandlerPanic struct {
+	// Handler names the handler that panicked: "sampling", "elicitation",
+	// "roots", "ping", "notification" or "connection-lost".
+	Handler string
+	// Method is the method of the request or notification being handled.
+	// It is empty for connection-lost handlers.
+	Method string
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+// WithPanicHandler installs a hook called when a client handler panics.
+//
+// Panics in sampling, elicitation and roots handlers, in OnNotification
+// handlers and in the OnConnectionLost handler are always recovered, so a
+// bug in a host callback cannot take down the transport's reader goroutine.
+// A panicking request handler is answered with a JSON-RPC internal error; a
+// panicking notification handler is skipped and the remaining handlers still
+// run. The hook can report the panic, e.g. to crash reporting. Panics in the
+// hook itself are not recovered.
+//
+// Recovered panics are also logged as mcp.handler_panic at level ERROR when
+// a logger is installed with WithLogger.
+func WithPanicHandler(handler func(HandlerPanic)) ClientOption {
+	return func(c *Client) {
+		c.panicHandler = handler
+	}
+}
+
+// recoverHandler recovers a panic in the handler named handler, reports it
+// and, when errp is not nil, sets *errp to an error describing it. It must be
+// deferred directly.
+func (c *Client) recoverHandler(ctx context.Context, handler, method string, errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	p := HandlerPanic{Handler: handler, Method: method, Value: r, Stack: debug.Stack()}
+	if c.logger != nil {
+		c.logger.LogAttrs(ctx, slog.LevelError, logMessagePanic,
+			slog.String("handler", handler),
+			slog.String(logKeyMethod, method),
+			slog.Any("panic", r),
+			slog.String("stack", string(p.Stack)),
+		)
+	}
+	if c.panicHandler != nil {
+		c.panicHandler(p)
+	}
+	if errp != nil {
+		*errp = fmt.Errorf("panic in %s handler: %v", handler, r)
+	}
+}
+
+// incomingHandlerName returns the name of the handler serving method in
+// HandlerPanic.
+func incomingHandlerName(method string) string {
+	switch method {
+	case string(mcp.MethodSamplingCreateMessage):
+		return "sampling"
+	case string(mcp.MethodElicitationCreate):
+		return "elicitation"
+	case string(mcp.MethodListRoots):
+		return "roots"
+	case string(mcp.MethodPing):
+		return "ping"
+	}
+	return method
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingSamplingHandler struct{}
+
+func (panickingSamplingHandler) CreateMessage(context.Context, mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	panic("sampling bug")
+}
+
+// notifyingTransport records the notification handler the client installs.
+type notifyingTransport struct {
+	*mockTransport
+	handler func(mcp.JSONRPCNotification)
+}
+
+func (t *notifyingTransport) SetNotificationHandler(handler func(mcp.JSONRPCNotification)) {
+	t.handler = handler
+}
+
+func TestClient_HandlerPanics(t *testing.T) {
+	var panics []HandlerPanic
+	var logs bytes.Buffer
+	trans := &notifyingTransport{mockTransport: newMockTransport()}
+	c := NewClient(trans,
+		WithSamplingHandler(panickingSamplingHandler{}),
+		WithPanicHandler(func(p HandlerPanic) { panics = append(panics, p) }),
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+	)
+
+	t.Run("request handler", func(t *testing.T) {
+		panics = nil
+		resp, err := c.handleIncomingRequest(context.Background(), transport.JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      mcp.NewRequestId(int64(1)),
+			Method:  string(mcp.MethodSamplingCreateMessage),
+			Params:  map[string]any{"messages": []any{}, "maxTokens": 10},
+		})
+		assert.Nil(t, resp)
+		require.EqualError(t, err, "panic in sampling handler: sampling bug")
+		require.Len(t, panics, 1)
+		assert.Equal(t, "sampling", panics[0].Handler)
+		assert.Equal(t, string(mcp.MethodSamplingCreateMessage), panics[0].Method)
+		assert.Equal(t, "sampling bug", panics[0].Value)
+		assert.NotEmpty(t, panics[0].Stack)
+		assert.Contains(t, logs.String(), logMessagePanic)
+	})
+
+	t.Run("notification handler", func(t *testing.T) {
+		panics = nil
+		var delivered []string
+		c.OnNotification(func(mcp.JSONRPCNotification) { panic("notification bug") })
+		c.OnNotification(func(n mcp.JSONRPCNotification) { delivered = append(delivered, n.Method) })
+		require.NoError(t, c.Start(context.Background()))
+		require.NotNil(t, trans.handler)
+
+		trans.handler(mcp.JSONRPCNotification{
+			JSONRPC:      mcp.JSONRPC_VERSION,
+			Notification: mcp.Notification{Method: "notifications/message"},
+		})
+		assert.Equal(t, []string{"notifications/message"}, delivered, "later handlers still run")
+		require.Len(t, panics, 1)
+		assert.Equal(t, "notification", panics[0].Handler)
+		assert.Equal(t, "notification bug", panics[0].Value)
+	})
+
+	t.Run("connection-lost handler", func(t *testing.T) {
+		panics = nil
+		c.OnConnectionLost(func(error) { panic("lost bug") })
+		assert.NotPanics(t, func() { c.handleConnectionLost(assert.AnError) })
+		require.Len(t, panics, 1)
+		assert.Equal(t, "connection-lost", panics[0].Handler)
+	})
+}
@@ -0,0 +1,336 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	// ErrPoolClosed is returned when using a Pool after Close.
+	ErrPoolClosed = errors.New("client pool closed")
+	// ErrNoHealthyClients is returned by Pool.Client when every client in the
+	// pool is broken and waiting to be replaced.
+	ErrNoHealthyClients = errors.New("no healthy clients in pool")
+)
+
+// ClientFactory creates a started and initialized client for a Pool.
+//
+// ctx lives as long as the client: it is canceled when the client is
+// evicted from the pool or the pool is closed, so it may be used for
+// Start and anything else tied to the client's lifetime. It is also
+// canceled if the factory does not return within the dial timeout.
+type ClientFactory func(ctx context.Context) (*Client, error)
+
+// PoolOption configures a Pool.
+type PoolOption func(*Pool)
+
+// WithPoolHealthCheck pings every client in the pool at interval and
+// replaces those that fail to answer within timeout. Health checks are
+// disabled by default.
+func WithPoolHealthCheck(interval, timeout time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.healthInterval = interval
+		p.healthTimeout = timeout
+	}
+}
+
+// WithPoolDialTimeout bounds each call to the factory. The default is 30s.
+// A factory that does not return in time has its context canceled.
+func WithPoolDialTimeout(timeout time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.dialTimeout = timeout
+	}
+}
+
+// WithPoolReplaceBackoff sets the delay between failed attempts to replace
+// a broken client. The default is 1s.
+func WithPoolReplaceBackoff(backoff time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.replaceBackoff = backoff
+	}
+}
+
+// WithPoolLatencyRouting makes Client return the healthy client with the
+// lowest mean ping latency instead of rotating round-robin. Latency is
+// measured by the pings of WithPoolHealthCheck, so enable both; clients
+// without measurements yet are preferred, so every client gets measured.
+func WithPoolLatencyRouting() PoolOption {
+	return func(p *Pool) {
+		p.latencyRouting = true
+	}
+}
+
+// Pool manages a fixed number of initialized clients to a stateless server,
+// such as a streamable HTTP server, and hands them out round-robin. Broken
+// clients are closed and replaced in the background.
+//
+// Because requests may be served by any client, a Pool is only suitable for
+// servers that keep no per-session state.
+type Pool struct {
+	factory        ClientFactory
+	dialTimeout    time.Duration
+	healthInterval time.Duration
+	healthTimeout  time.Duration
+	replaceBackoff time.Duration
+	latencyRouting bool
+
+	slots []*poolSlot
+	next  atomic.Uint64
+
+	// ctx is the parent of every client's context and is canceled by Close.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex // guards isClosed and wg.Add against Close
+	isClosed bool
+	closed   chan struct{}
+	wg       sync.WaitGroup
+}
+
+type poolSlot struct {
+	mu     sync.RWMutex
+	client *Client            // nil while being replaced
+	cancel context.CancelFunc // cancels the context client was created with
+}
+
+func (s *poolSlot) get() *Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.client
+}
+
+// NewPool creates size clients with factory and returns a pool serving them.
+// If any client cannot be created, the clients created so far are closed and
+// the error is returned.
+func NewPool(factory ClientFactory, size int, opts ...PoolOption) (*Pool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("pool size must be positive, got %d", size)
+	}
+	p := &Pool{
+		factory:        factory,
+		dialTimeout:    30 * time.Second,
+		replaceBackoff: time.Second,
+		slots:          make([]*poolSlot, size),
+		closed:         make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for i := range p.slots {
+		c, cancel, err := p.dial()
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("failed to create pool client %d: %w", i, err)
+		}
+		p.slots[i] = &poolSlot{client: c, cancel: cancel}
+	}
+
+	if p.healthInterval > 0 {
+		p.wg.Add(1) // no concurrent Close yet
+		go p.healthLoop()
+	}
+	return p, nil
+}
+
+// Client returns the next healthy client in round-robin order, or the
+// fastest one with WithPoolLatencyRouting. The client
+// stays owned by the pool: do not close it. If a request on it fails with a
+// transport error, report it with MarkBroken.
+func (p *Pool) Client() (*Client, error) {
+	select {
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	default:
+	}
+	start := p.next.Add(1)
+	var best *Client
+	var bestLatency time.Duration
+	for i := range uint64(len(p.slots)) {
+		slot := p.slots[(start+i)%uint64(len(p.slots))]
+		c := slot.get()
+		if c == nil {
+			continue
+		}
+		if !p.latencyRouting {
+			return c, nil
+		}
+		latency := c.Stats().Latency.Mean
+		if best == nil || latency < bestLatency {
+			best, bestLatency = c, latency
+		}
+	}
+	if best == nil {
+		return nil, ErrNoHealthyClients
+	}
+	return best, nil
+}
+
+// MarkBroken takes c out of rotation, closes it and starts replacing it.
+// Clients that are not (or no longer) in the pool are ignored.
+func (p *Pool) MarkBroken(c *Client) {
+	for _, slot := range p.slots {
+		slot.mu.Lock()
+		if slot.client != c || c == nil {
+			slot.mu.Unlock()
+			continue
+		}
+		cancel := slot.cancel
+		slot.client, slot.cancel = nil, nil
+		slot.mu.Unlock()
+
+		_ = c.Close()
+		cancel()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if !p.isClosed {
+			p.wg.Add(1)
+			go p.replace(slot)
+		}
+		return
+	}
+}
+
+// CallTool calls a tool on the next healthy client. A client whose call
+// fails with a transport error is replaced.
+func (p *Pool) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	c, err := p.Client()
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.CallTool(ctx, request)
+	p.checkError(c, err)
+	return result, err
+}
+
+// Healthy returns the number of clients currently in rotation.
+func (p *Pool) Healthy() int {
+	n := 0
+	for _, slot := range p.slots {
+		if slot.get() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Close stops health checks and replacements and closes every client.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if !p.isClosed {
+		p.isClosed = true
+		close(p.closed)
+	}
+	p.mu.Unlock()
+	p.cancel()
+	p.wg.Wait()
+
+	var errs []error
+	for _, slot := range p.slots {
+		if slot == nil {
+			continue
+		}
+		slot.mu.Lock()
+		c := slot.client
+		slot.client, slot.cancel = nil, nil
+		slot.mu.Unlock()
+		if c != nil {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Pool) checkError(c *Client, err error) {
+	var transportErr *transport.Error
+	if errors.As(err, &transportErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		p.MarkBroken(c)
+	}
+}
+
+// dial creates a client with a context that outlives the call. The
+// returned cancel function must be called once the client is discarded.
+func (p *Pool) dial() (*Client, context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(p.ctx)
+	timer := time.AfterFunc(p.dialTimeout, cancel)
+	c, err := p.factory(ctx)
+	timedOut := !timer.Stop()
+	if err == nil && timedOut {
+		_ = c.Close()
+		err = fmt.Errorf("pool dial: %w", context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return c, cancel, nil
+}
+
+// replace creates a new client for slot, retrying until it succeeds or the
+// pool is closed.
+func (p *Pool) replace(slot *poolSlot) {
+	defer p.wg.Done()
+	for {
+		c, cancel, err := p.dial()
+		if err == nil {
+			select {
+			case <-p.closed:
+				_ = c.Close()
+				cancel()
+			default:
+				slot.mu.Lock()
+				slot.client, slot.cancel = c, cancel
+				slot.mu.Unlock()
+			}
+			return
+		}
+		select {
+		case <-time.After(p.replaceBackoff):
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+func (p *Pool) healthLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.checkHealth()
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+func (p *Pool) checkHealth() {
+	for _, slot := range p.slots {
+		c := slot.get()
+		if c == nil {
+			continue
+		}
+		ctx := context.Background()
+		var cancel context.CancelFunc = func() {}
+		if p.healthTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, p.healthTimeout)
+		}
+		err := c.Ping(ctx)
+		cancel()
+		if err != nil {
+			p.MarkBroken(c)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("echo"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	var mu sync.Mutex
+	var transports []*flakyTransport
+	var dials atomic.Int32
+	factory := func(ctx context.Context) (*Client, error) {
+		dials.Add(1)
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		c := NewClient(flaky)
+		if err := c.Start(ctx); err != nil {
+			return nil, err
+		}
+		initRequest := mcp.InitializeRequest{}
+		initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+		if _, err := c.Initialize(ctx, initRequest); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		transports = append(transports, flaky)
+		mu.Unlock()
+		return c, nil
+	}
+	callEcho := func(p *Pool) error {
+		_, err := p.CallTool(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "echo"}})
+		return err
+	}
+
+	t.Run("round robin", func(t *testing.T) {
+		pool, err := NewPool(factory, 3)
+		require.NoError(t, err)
+		defer pool.Close()
+
+		seen := map[*Client]bool{}
+		for range 3 {
+			c, err := pool.Client()
+			require.NoError(t, err)
+			seen[c] = true
+		}
+		assert.Len(t, seen, 3)
+		assert.Equal(t, 3, pool.Healthy())
+	})
+
+	t.Run("replaces broken clients", func(t *testing.T) {
+		transports = nil
+		dials.Store(0)
+		pool, err := NewPool(factory, 2, WithPoolReplaceBackoff(time.Millisecond))
+		require.NoError(t, err)
+		defer pool.Close()
+
+		for _, flaky := range transports {
+			flaky.broken.Store(true)
+		}
+		var transportErr *transport.Error
+		assert.ErrorAs(t, callEcho(pool), &transportErr)
+		assert.ErrorAs(t, callEcho(pool), &transportErr)
+
+		require.Eventually(t, func() bool { return pool.Healthy() == 2 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int32(4), dials.Load())
+		require.NoError(t, callEcho(pool))
+	})
+
+	t.Run("health check replaces unresponsive clients", func(t *testing.T) {
+		transports = nil
+		dials.Store(0)
+		pool, err := NewPool(factory, 1,
+			WithPoolHealthCheck(5*time.Millisecond, time.Second),
+			WithPoolReplaceBackoff(time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer pool.Close()
+
+		transports[0].broken.Store(true)
+		require.Eventually(t, func() bool { return dials.Load() == 2 && pool.Healthy() == 1 }, time.Second, 5*time.Millisecond)
+		require.NoError(t, callEcho(pool))
+	})
+
+	t.Run("factory failure", func(t *testing.T) {
+		_, err := NewPool(func(context.Context) (*Client, error) {
+			return nil, errors.New("dial failed")
+		}, 2)
+		assert.ErrorContains(t, err, "dial failed")
+
+		_, err = NewPool(factory, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("factory context lives as long as the client", func(t *testing.T) {
+		var ctxMu sync.Mutex
+		var ctxs []context.Context
+		canceled := func() (n, total int) {
+			ctxMu.Lock()
+			defer ctxMu.Unlock()
+			return countCanceled(ctxs), len(ctxs)
+		}
+		pool, err := NewPool(func(ctx context.Context) (*Client, error) {
+			ctxMu.Lock()
+			ctxs = append(ctxs, ctx)
+			ctxMu.Unlock()
+			return factory(ctx)
+		}, 2, WithPoolReplaceBackoff(time.Millisecond))
+		require.NoError(t, err)
+		require.Len(t, ctxs, 2)
+		for _, ctx := range ctxs {
+			assert.NoError(t, ctx.Err(), "context canceled when the factory returned")
+		}
+		require.NoError(t, callEcho(pool))
+
+		c, err := pool.Client()
+		require.NoError(t, err)
+		pool.MarkBroken(c)
+		n, _ := canceled()
+		assert.Equal(t, 1, n, "evicted client's context not canceled")
+
+		require.NoError(t, pool.Close())
+		n, total := canceled()
+		assert.Equal(t, total, n)
+	})
+
+	t.Run("dial timeout cancels the factory", func(t *testing.T) {
+		_, err := NewPool(func(ctx context.Context) (*Client, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, 1, WithPoolDialTimeout(10*time.Millisecond))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("closed pool", func(t *testing.T) {
+		pool, err := NewPool(factory, 1)
+		require.NoError(t, err)
+		require.NoError(t, pool.Close())
+		_, err = pool.Client()
+		assert.ErrorIs(t, err, ErrPoolClosed)
+	})
+}
+
+func countCanceled(ctxs []context.Context) int {
+	n := 0
+	for _, ctx := range ctxs {
+		if ctx.Err() != nil {
+			n++
+		}
+	}
+	return n
+}
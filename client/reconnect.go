@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReconnectPolicy controls how a Client re-establishes its transport after a
+// transport failure. Zero fields take the values of DefaultReconnectPolicy.
+type ReconnectPolicy struct {
+	// MaxAttempts is the number of reconnection attempts before giving up.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt; the first
+	// attempt is made immediately.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each failed attempt.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction (0 to 1) in either
+	// direction, so that many clients do not reconnect in lockstep.
+	Jitter float64
+	// NewTransport, if set, creates a fresh transport for each attempt and
+	// the previous one is closed. Transports that cannot recover in place,
+	// such as stdio after the server process exited, need this. When nil
+	// the existing transport is restarted.
+	NewTransport func(ctx context.Context) (transport.Interface, error)
+	// ShouldReconnect reports whether a request error warrants reconnecting.
+	// By default every transport error does, except context cancellation.
+	ShouldReconnect func(err error) bool
+	// OnReconnect, if set, is called after each attempt with its 1-based
+	// number and result.
+	OnReconnect func(attempt int, err error)
+}
+
+// DefaultReconnectPolicy returns the policy used to fill unset fields:
+// 5 attempts, backoff from 500ms doubling up to 30s with 20% jitter.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithReconnect makes the client reconnect automatically when a request fails
+// with a transport error. The client restarts or replaces its transport,
+// replays the last initialize request and keeps its registered notification
+// and request handlers. An idempotent request, such as ping or a list, get
+// or read request, is then resent once; any other request, such as
+// tools/call, fails with the transport error, since the server may already
+// have processed it. Reconnection is only attempted after a successful
+// Initialize.
+func WithReconnect(policy ReconnectPolicy) ClientOption {
+	return func(c *Client) {
+		policy = policy.withDefaults()
+		c.reconnectPolicy = &policy
+	}
+}
+
+// withDefaults returns p with unset fields filled from
+// DefaultReconnectPolicy.
+func (p ReconnectPolicy) withDefaults() ReconnectPolicy {
+	defaults := DefaultReconnectPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaults.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+	p.Jitter = min(max(p.Jitter, 0), 1)
+	if p.ShouldReconnect == nil {
+		p.ShouldReconnect = func(err error) bool {
+			return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+		}
+	}
+	return p
+}
+
+// ErrReconnectFailed is returned by Reconnect when every attempt failed.
+var ErrReconnectFailed = errors.New("reconnect failed")
+
+// Reconnect re-establishes the transport and replays initialize using the
+// client's reconnect policy, or DefaultReconnectPolicy if none was set. It
+// can be called from an OnConnectionLost handler to reconnect eagerly.
+// Without a policy set by WithReconnect, later requests still do not
+// reconnect automatically.
+func (c *Client) Reconnect(ctx context.Context) error {
+	policy := c.reconnectPolicy
+	if policy == nil {
+		defaults := ReconnectPolicy{}.withDefaults()
+		policy = &defaults
+	}
+	return c.reconnect(ctx, policy, c.reconnectGen.Load(), nil)
+}
+
+func (c *Client) shouldReconnect(ctx context.Context, method string, err error) bool {
+	return c.reconnectPolicy != nil &&
+		method != "initialize" &&
+		c.initRequest != nil &&
+		ctx.Err() == nil &&
+		c.reconnectPolicy.ShouldReconnect(err)
+}
+
+// reconnect re-establishes the connection according to policy unless another
+// caller already did so since generation gen was observed. cause is the
+// transport failure that prompted it, if any.
+func (c *Client) reconnect(ctx context.Context, policy *ReconnectPolicy, gen uint64, cause error) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	if c.reconnectGen.Load() != gen {
+		return nil
+	}
+	if c.initRequest == nil {
+		return fmt.Errorf("%w: client was never initialized", ErrReconnectFailed)
+	}
+
+	c.state.Transition(mcp.ConnectionReconnecting, cause)
+	err := c.reconnectAttempts(ctx, policy)
+	if err != nil {
+		c.state.TransitionFrom(mcp.ConnectionReconnecting, mcp.ConnectionDegraded, err)
+	}
+	return err
+}
+
+func (c *Client) reconnectAttempts(ctx context.Context, policy *ReconnectPolicy) error {
+	backoff := policy.InitialBackoff
+	var lastErr error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := time.Duration(float64(backoff) * (1 + policy.Jitter*(rand.Float64()*2-1)))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff = min(time.Duration(float64(backoff)*policy.Multiplier), policy.MaxBackoff)
+		}
+
+		lastErr = c.reconnectOnce(ctx, policy)
+		c.logReconnect(ctx, attempt, lastErr)
+		if policy.OnReconnect != nil {
+			policy.OnReconnect(attempt, lastErr)
+		}
+		if lastErr == nil {
+			c.reconnectGen.Add(1)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w after %d attempts: %w", ErrReconnectFailed, policy.MaxAttempts, lastErr)
+}
+
+func (c *Client) reconnectOnce(ctx context.Context, policy *ReconnectPolicy) error {
+	t := c.currentTransport()
+	if policy.NewTransport != nil {
+		fresh, err := policy.NewTransport(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create transport: %w", err)
+		}
+		_ = t.Close()
+		t = fresh
+		c.transportMu.Lock()
+		c.transport = t
+		c.transportMu.Unlock()
+	}
+
+	if err := c.startTransport(ctx, t); err != nil {
+		return fmt.Errorf("failed to start transport: %w", err)
+	}
+	if _, err := c.Initialize(ctx, *c.initRequest); err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	return nil
+}
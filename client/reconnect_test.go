@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyTransport fails requests while broken is set.
+type flakyTransport struct {
+	transport.Interface
+	broken atomic.Bool
+	// dropResponse makes the next request reach the server but fail as if
+	// the connection dropped before the response arrived.
+	dropResponse atomic.Bool
+	starts       atomic.Int32
+	// healOnStart clears broken when the transport is restarted.
+	healOnStart bool
+	onNotify    func(mcp.JSONRPCNotification)
+}
+
+func (f *flakyTransport) SetNotificationHandler(handler func(mcp.JSONRPCNotification)) {
+	f.onNotify = handler
+	f.Interface.SetNotificationHandler(handler)
+}
+
+func (f *flakyTransport) Start(ctx context.Context) error {
+	f.starts.Add(1)
+	if f.healOnStart {
+		f.broken.Store(false)
+	}
+	return f.Interface.Start(ctx)
+}
+
+func (f *flakyTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if f.broken.Load() {
+		return nil, errors.New("connection reset")
+	}
+	if f.dropResponse.CompareAndSwap(true, false) {
+		_, _ = f.Interface.SendRequest(ctx, request)
+		return nil, errors.New("connection reset")
+	}
+	return f.Interface.SendRequest(ctx, request)
+}
+
+func initializeTestClient(t *testing.T, c *Client) {
+	t.Helper()
+	require.NoError(t, c.Start(t.Context()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := c.Initialize(t.Context(), initRequest)
+	require.NoError(t, err)
+}
+
+func TestClient_WithReconnect(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+
+	t.Run("restarts the existing transport", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer), healOnStart: true}
+		c := NewClient(flaky, WithReconnect(ReconnectPolicy{}))
+		initializeTestClient(t, c)
+
+		flaky.broken.Store(true)
+		require.NoError(t, c.Ping(t.Context()))
+		assert.Equal(t, int32(2), flaky.starts.Load())
+	})
+
+	t.Run("replaces the transport", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		fresh := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		var attempts []error
+		c := NewClient(flaky, WithReconnect(ReconnectPolicy{
+			InitialBackoff: time.Millisecond,
+			NewTransport: func(context.Context) (transport.Interface, error) {
+				if len(attempts) == 0 {
+					return nil, errors.New("dial failed")
+				}
+				return fresh, nil
+			},
+			OnReconnect: func(_ int, err error) { attempts = append(attempts, err) },
+		}))
+		initializeTestClient(t, c)
+
+		var notified atomic.Bool
+		c.OnNotification(func(mcp.JSONRPCNotification) { notified.Store(true) })
+
+		flaky.broken.Store(true)
+		require.NoError(t, c.Ping(t.Context()))
+		require.Len(t, attempts, 2)
+		assert.Error(t, attempts[0])
+		assert.NoError(t, attempts[1])
+		assert.Same(t, fresh, c.GetTransport())
+
+		// Notification handlers survive the transport replacement.
+		require.NotNil(t, fresh.onNotify)
+		fresh.onNotify(mcp.JSONRPCNotification{})
+		assert.True(t, notified.Load())
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		c := NewClient(flaky, WithReconnect(ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+		initializeTestClient(t, c)
+
+		flaky.broken.Store(true)
+		err := c.Ping(t.Context())
+		require.Error(t, err)
+		assert.Equal(t, int32(3), flaky.starts.Load())
+
+		err = c.Reconnect(t.Context())
+		assert.ErrorIs(t, err, ErrReconnectFailed)
+	})
+
+	t.Run("does not resend non-idempotent requests", func(t *testing.T) {
+		var calls atomic.Int32
+		toolServer := server.NewMCPServer("test-server", "1.0.0")
+		toolServer.AddTool(mcp.NewTool("count"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls.Add(1)
+			return mcp.NewToolResultText("ok"), nil
+		})
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(toolServer)}
+		c := NewClient(flaky, WithReconnect(ReconnectPolicy{}))
+		initializeTestClient(t, c)
+
+		flaky.dropResponse.Store(true)
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "count"
+		_, err := c.CallTool(t.Context(), request)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load(), "the tool must not run twice")
+		assert.Equal(t, int32(2), flaky.starts.Load(), "the client still reconnects")
+
+		// Idempotent requests are resent.
+		flaky.dropResponse.Store(true)
+		require.NoError(t, c.Ping(t.Context()))
+	})
+
+	t.Run("manual reconnect leaves automatic reconnect off", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer), healOnStart: true}
+		c := NewClient(flaky)
+		initializeTestClient(t, c)
+
+		require.NoError(t, c.Reconnect(t.Context()))
+		assert.Equal(t, int32(2), flaky.starts.Load())
+		assert.Nil(t, c.reconnectPolicy)
+
+		flaky.broken.Store(true)
+		require.Error(t, c.Ping(t.Context()))
+		assert.Equal(t, int32(2), flaky.starts.Load())
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer), healOnStart: true}
+		c := NewClient(flaky)
+		initializeTestClient(t, c)
+
+		flaky.broken.Store(true)
+		require.Error(t, c.Ping(t.Context()))
+		assert.Equal(t, int32(1), flaky.starts.Load())
+	})
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReadResourceStream reads a resource as a stream, for resources too large
+// to hold in memory. For resources the server streams in chunks, the
+// returned reader requests each chunk as it is consumed, following the
+// nextCursor of each resources/read result; other resources are returned
+// whole. Blob contents are decoded, so the reader yields the raw bytes.
+//
+// The first chunk is requested before ReadResourceStream returns, so a
+// missing resource is reported immediately. Later chunks are requested with
+// ctx, which must stay valid while the stream is read.
+func (c *Client) ReadResourceStream(
+	ctx context.Context,
+	request mcp.ReadResourceRequest,
+) (io.ReadCloser, error) {
+	stream := &resourceStream{ctx: ctx, client: c, request: request}
+	if err := stream.fetch(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// resourceStream reads the chunks of a resource on demand.
+type resourceStream struct {
+	ctx     context.Context
+	client  *Client
+	request mcp.ReadResourceRequest
+	buf     []byte
+	done    bool
+	closed  bool
+}
+
+// Read implements io.Reader.
+func (s *resourceStream) Read(p []byte) (int, error) {
+	if s.closed {
+		return 0, errors.New("read from closed resource stream")
+	}
+	for len(s.buf) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+		if err := s.fetch(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// Close implements io.Closer. The server releases an abandoned stream on
+// its own after a while.
+func (s *resourceStream) Close() error {
+	s.closed = true
+	s.buf = nil
+	return nil
+}
+
+// fetch reads the next chunk of the resource.
+func (s *resourceStream) fetch() error {
+	result, err := s.client.ReadResource(s.ctx, s.request)
+	if err != nil {
+		return err
+	}
+	for _, content := range result.Contents {
+		switch content := content.(type) {
+		case mcp.BlobResourceContents:
+			data, err := base64.StdEncoding.DecodeString(content.Blob)
+			if err != nil {
+				return fmt.Errorf("failed to decode resource %s: %w", content.URI, err)
+			}
+			s.buf = append(s.buf, data...)
+		case mcp.TextResourceContents:
+			s.buf = append(s.buf, content.Text...)
+		}
+	}
+	s.request.Params.Cursor = result.NextCursor
+	s.done = result.NextCursor == ""
+	return nil
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ReadResourceStream(t *testing.T) {
+	content := bytes.Repeat([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 100)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithResourceChunkSize(64))
+	mcpServer.AddResourceStream(mcp.NewResource("file:///large.bin", "large"), func(context.Context, mcp.ReadResourceRequest) (io.Reader, error) {
+		return io.MultiReader(bytes.NewReader(content)), nil // hide io.Seeker
+	})
+	mcpServer.AddResource(mcp.NewResource("file:///small.txt", "small"), func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "hello"}}, nil
+	})
+
+	c := NewClient(transport.NewInProcessTransport(mcpServer))
+	require.NoError(t, c.Start(context.Background()))
+	defer c.Close()
+	_, err := c.Initialize(context.Background(), mcp.InitializeRequest{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		uri     string
+		want    []byte
+		wantErr bool
+	}{
+		{name: "chunked", uri: "file:///large.bin", want: content},
+		{name: "whole", uri: "file:///small.txt", want: []byte("hello")},
+		{name: "missing", uri: "file:///missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = tt.uri
+			stream, err := c.ReadResourceStream(context.Background(), request)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer stream.Close()
+
+			got, err := io.ReadAll(stream)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// idempotentMethods are the requests retried by default. They have no side
+// effects on the server, so sending them twice is harmless.
+var idempotentMethods = map[string]bool{
+	string(mcp.MethodPing):                   true,
+	string(mcp.MethodToolsList):              true,
+	string(mcp.MethodResourcesList):          true,
+	string(mcp.MethodResourcesTemplatesList): true,
+	string(mcp.MethodResourcesRead):          true,
+	string(mcp.MethodPromptsList):            true,
+	string(mcp.MethodPromptsGet):             true,
+	string(mcp.MethodCompletionComplete):     true,
+	string(mcp.MethodTasksGet):               true,
+	string(mcp.MethodTasksList):              true,
+}
+
+// RetryPolicy controls how the client retries failed requests. Zero fields
+// take the values of DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each retry.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction (0 to 1).
+	Jitter float64
+	// ShouldRetry reports whether a request that failed with a transport
+	// error may be retried. By default idempotent methods (ping and the list,
+	// get and read requests) are retried when IsRetryableError reports true.
+	// tools/call is not retried unless ShouldRetry allows it, since tools may
+	// have side effects. JSON-RPC errors returned by the server are never
+	// retried.
+	ShouldRetry func(method string, err error) bool
+}
+
+// DefaultRetryPolicy returns the policy used to fill unset fields: 3 retries,
+// backoff from 100ms doubling up to 2s with 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRequestTimeout bounds every request sent by the client, including each
+// retry attempt, to d. A shorter deadline already set on the caller's context
+// still applies.
+func WithRequestTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithRetryPolicy makes the client retry failed requests according to policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		defaults := DefaultRetryPolicy()
+		if policy.MaxRetries <= 0 {
+			policy.MaxRetries = defaults.MaxRetries
+		}
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = defaults.InitialBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = defaults.MaxBackoff
+		}
+		if policy.Multiplier < 1 {
+			policy.Multiplier = defaults.Multiplier
+		}
+		policy.Jitter = min(max(policy.Jitter, 0), 1)
+		if policy.ShouldRetry == nil {
+			policy.ShouldRetry = func(method string, err error) bool {
+				return idempotentMethods[method] && IsRetryableError(err)
+			}
+		}
+		c.retryPolicy = &policy
+	}
+}
+
+// IsRetryableError reports whether a transport error may be retried. Every
+// transport failure, including a per-request timeout, is retryable unless the
+// caller canceled the request.
+func IsRetryableError(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// sendWithRetry sends request, retrying it according to the client's retry
+// policy. Each attempt is bounded by the request timeout and uses a fresh
+// request ID.
+func (c *Client) sendWithRetry(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	policy := c.retryPolicy
+	var backoff time.Duration
+	if policy != nil {
+		backoff = policy.InitialBackoff
+	}
+	for retry := 0; ; retry++ {
+		// JSON-RPC errors are answers from the server and are never retried.
+		response, err := c.sendAttempt(ctx, request)
+		if err == nil ||
+			policy == nil ||
+			retry >= policy.MaxRetries ||
+			ctx.Err() != nil ||
+			!policy.ShouldRetry(request.Method, err) {
+			return response, err
+		}
+
+		delay := time.Duration(float64(backoff) * (1 + policy.Jitter*(rand.Float64()*2-1)))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff = min(time.Duration(float64(backoff)*policy.Multiplier), policy.MaxBackoff)
+		request.ID = mcp.NewRequestId(c.requestID.Add(1))
+	}
+}
+
+// sendAttempt sends request once, reconnecting if the client has a reconnect
+// policy and the transport failed. Only idempotent requests are resent over
+// the new connection: the server may have processed the failed request
+// before the connection dropped.
+func (c *Client) sendAttempt(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	gen := c.reconnectGen.Load()
+	response, err := c.currentTransport().SendRequest(ctx, request)
+	if err != nil && ctx.Err() == nil {
+		c.state.TransitionFrom(mcp.ConnectionReady, mcp.ConnectionDegraded, err)
+	}
+	if err != nil && c.shouldReconnect(ctx, request.Method, err) {
+		reconnectErr := c.reconnect(ctx, c.reconnectPolicy, gen, err)
+		if reconnectErr == nil && idempotentMethods[request.Method] {
+			response, err = c.currentTransport().SendRequest(ctx, request)
+		}
+	}
+	if err == nil {
+		c.state.TransitionFrom(mcp.ConnectionDegraded, mcp.ConnectionReady, nil)
+	}
+	return response, err
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingTransport fails the first failures requests after initialize, and
+// hangs instead of failing if hang is set.
+type failingTransport struct {
+	transport.Interface
+	failures atomic.Int32
+	hang     bool
+	sent     atomic.Int32
+	ids      []mcp.RequestId
+}
+
+func (f *failingTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if request.Method != "initialize" {
+		f.sent.Add(1)
+		f.ids = append(f.ids, request.ID)
+		if f.failures.Add(-1) >= 0 {
+			if f.hang {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return nil, errors.New("connection reset")
+		}
+	}
+	return f.Interface.SendRequest(ctx, request)
+}
+
+func TestClient_WithRetryPolicy(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(true))
+	policy := RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		failures  int32
+		call      func(c *Client) error
+		wantErr   bool
+		wantSends int32
+	}{
+		{
+			name:      "idempotent request recovers",
+			failures:  2,
+			call:      func(c *Client) error { return c.Ping(t.Context()) },
+			wantSends: 3,
+		},
+		{
+			name:      "gives up after max retries",
+			failures:  5,
+			call:      func(c *Client) error { return c.Ping(t.Context()) },
+			wantErr:   true,
+			wantSends: 3,
+		},
+		{
+			name:     "tool calls are not retried",
+			failures: 1,
+			call: func(c *Client) error {
+				_, err := c.CallTool(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "x"}})
+				return err
+			},
+			wantErr:   true,
+			wantSends: 1,
+		},
+		{
+			name:     "server errors are not retried",
+			failures: 0,
+			call: func(c *Client) error {
+				_, err := c.ReadResource(t.Context(), mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: "missing://x"}})
+				return err
+			},
+			wantErr:   true,
+			wantSends: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing := &failingTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+			failing.failures.Store(tt.failures)
+			c := NewClient(failing, WithRetryPolicy(policy))
+			initializeTestClient(t, c)
+
+			err := tt.call(c)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantSends, failing.sent.Load())
+		})
+	}
+
+	t.Run("retries use fresh request IDs", func(t *testing.T) {
+		failing := &failingTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		failing.failures.Store(1)
+		c := NewClient(failing, WithRetryPolicy(policy))
+		initializeTestClient(t, c)
+
+		require.NoError(t, c.Ping(t.Context()))
+		require.Len(t, failing.ids, 2)
+		assert.NotEqual(t, failing.ids[0], failing.ids[1])
+	})
+}
+
+func TestClient_WithRequestTimeout(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+
+	t.Run("enforces the deadline", func(t *testing.T) {
+		failing := &failingTransport{Interface: transport.NewInProcessTransport(mcpServer), hang: true}
+		failing.failures.Store(1)
+		c := NewClient(failing, WithRequestTimeout(20*time.Millisecond))
+		initializeTestClient(t, c)
+
+		err := c.Ping(t.Context())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("timed out attempts are retried", func(t *testing.T) {
+		failing := &failingTransport{Interface: transport.NewInProcessTransport(mcpServer), hang: true}
+		failing.failures.Store(1)
+		c := NewClient(failing,
+			WithRequestTimeout(20*time.Millisecond),
+			WithRetryPolicy(RetryPolicy{InitialBackoff: time.Millisecond}),
+		)
+		initializeTestClient(t, c)
+
+		require.NoError(t, c.Ping(t.Context()))
+		assert.Equal(t, int32(2), failing.sent.Load())
+	})
+}
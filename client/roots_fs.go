@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/internal/fileuri"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrRootNotFound is returned by FSRootsHandler.Remove for a path that is not
+// a root.
+var ErrRootNotFound = errors.New("root not found")
+
+// FSRootsHandler is a RootsHandler that exposes local directories as roots.
+// Roots can be added and removed while the client is connected; when the
+// handler is installed with WithRootsHandler, every change sends a roots
+// list-changed notification to the server.
+type FSRootsHandler struct {
+	mu     sync.RWMutex
+	roots  []mcp.Root
+	paths  []string // absolute paths, parallel to roots
+	client *Client
+}
+
+// NewFSRootsHandler creates a FSRootsHandler exposing paths, each of which
+// must be an existing directory. Relative paths are resolved against the
+// working directory.
+func NewFSRootsHandler(paths ...string) (*FSRootsHandler, error) {
+	h := &FSRootsHandler{}
+	for _, path := range paths {
+		if _, err := h.add(path); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// ListRoots implements RootsHandler.
+func (h *FSRootsHandler) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return &mcp.ListRootsResult{Roots: h.Roots()}, nil
+}
+
+// Roots returns the current roots.
+func (h *FSRootsHandler) Roots() []mcp.Root {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return slices.Clone(h.roots)
+}
+
+// Add exposes the directory at path as a root and notifies the server.
+// Adding a path that is already a root does nothing.
+func (h *FSRootsHandler) Add(ctx context.Context, path string) error {
+	added, err := h.add(path)
+	if err != nil || !added {
+		return err
+	}
+	return h.notify(ctx)
+}
+
+// Remove stops exposing the directory at path and notifies the server.
+func (h *FSRootsHandler) Remove(ctx context.Context, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid root %q: %w", path, err)
+	}
+
+	h.mu.Lock()
+	i := slices.Index(h.paths, abs)
+	if i < 0 {
+		h.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRootNotFound, path)
+	}
+	h.paths = slices.Delete(h.paths, i, i+1)
+	h.roots = slices.Delete(h.roots, i, i+1)
+	h.mu.Unlock()
+
+	return h.notify(ctx)
+}
+
+// add validates path and appends it, reporting whether it was new.
+func (h *FSRootsHandler) add(path string) (bool, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("invalid root %q: %w", path, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return false, fmt.Errorf("invalid root %q: %w", path, err)
+	}
+	if !info.IsDir() {
+		return false, fmt.Errorf("invalid root %q: not a directory", path)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if slices.Contains(h.paths, abs) {
+		return false, nil
+	}
+	h.paths = append(h.paths, abs)
+	h.roots = append(h.roots, mcp.Root{Name: filepath.Base(abs), URI: fileuri.FromPath(abs)})
+	return true, nil
+}
+
+// attach makes the handler notify c when the roots change.
+func (h *FSRootsHandler) attach(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.client = c
+}
+
+// notify sends a roots list-changed notification if the attached client is
+// connected.
+func (h *FSRootsHandler) notify(ctx context.Context) error {
+	h.mu.RLock()
+	c := h.client
+	h.mu.RUnlock()
+	if c == nil || !c.IsInitialized() {
+		return nil
+	}
+	return c.RootListChanges(ctx)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFSRootsHandler(t *testing.T) {
+	dir := t.TempDir()
+	spaced := filepath.Join(dir, "my project")
+	require.NoError(t, os.Mkdir(spaced, 0o755))
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    []mcp.Root
+		wantErr string
+	}{
+		{
+			name:  "escapes uri",
+			paths: []string{spaced},
+			want:  []mcp.Root{{Name: "my project", URI: "file://" + filepath.ToSlash(dir) + "/my%20project"}},
+		},
+		{
+			name:  "deduplicates",
+			paths: []string{spaced, spaced + string(filepath.Separator)},
+			want:  []mcp.Root{{Name: "my project", URI: "file://" + filepath.ToSlash(dir) + "/my%20project"}},
+		},
+		{
+			name:    "missing path",
+			paths:   []string{filepath.Join(dir, "missing")},
+			wantErr: "no such file",
+		},
+		{
+			name:    "file",
+			paths:   []string{file},
+			wantErr: "not a directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewFSRootsHandler(tt.paths...)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			result, err := h.ListRoots(context.Background(), mcp.ListRootsRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Roots)
+		})
+	}
+}
+
+func TestFSRootsHandler_NotifiesServer(t *testing.T) {
+	changed := make(chan struct{}, 4)
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithRoots())
+	mcpServer.AddNotificationHandler(mcp.MethodNotificationRootsListChanged, func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		changed <- struct{}{}
+	})
+
+	first, second := t.TempDir(), t.TempDir()
+	h, err := NewFSRootsHandler(first)
+	require.NoError(t, err)
+
+	c := NewClient(transport.NewInProcessTransport(mcpServer), WithRootsHandler(h))
+	require.NoError(t, c.Start(context.Background()))
+	defer c.Close()
+	_, err = c.Initialize(context.Background(), mcp.InitializeRequest{})
+	require.NoError(t, err)
+
+	expectNotification := func() {
+		t.Helper()
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+			t.Fatal("no roots/list_changed notification")
+		}
+	}
+
+	require.NoError(t, h.Add(context.Background(), second))
+	expectNotification()
+	assert.Len(t, h.Roots(), 2)
+
+	require.NoError(t, h.Remove(context.Background(), first))
+	expectNotification()
+	assert.Equal(t, filepath.Base(second), h.Roots()[0].Name)
+
+	assert.ErrorIs(t, h.Remove(context.Background(), first), ErrRootNotFound)
+}
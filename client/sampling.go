@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -18,3 +19,50 @@ type SamplingHandler interface {
 	// 5. Return the result with model information and stop reason
 	CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
 }
+
+// StreamingSamplingHandler is a SamplingHandler that can stream the
+// generated message. When the server asks for a streamed result, the client
+// calls CreateMessageStream instead of CreateMessage and forwards every delta
+// passed to send to the server as it is produced. Each delta should carry
+// only the new content; the returned result is the complete message.
+// Servers that did not ask for streaming are served by CreateMessage.
+type StreamingSamplingHandler interface {
+	SamplingHandler
+	CreateMessageStream(
+		ctx context.Context,
+		request mcp.CreateMessageRequest,
+		send func(delta *mcp.CreateMessageResult) error,
+	) (*mcp.CreateMessageResult, error)
+}
+
+// createMessage calls handler, streaming the result through sendDelta if
+// the request asks for it and handler supports streaming.
+func createMessage(
+	ctx context.Context,
+	handler SamplingHandler,
+	request mcp.CreateMessageRequest,
+	sendDelta func(ctx context.Context, notification mcp.JSONRPCNotification) error,
+) (*mcp.CreateMessageResult, error) {
+	streamID := mcp.SamplingStreamID(request)
+	streaming, ok := handler.(StreamingSamplingHandler)
+	if streamID == "" || !ok {
+		return handler.CreateMessage(ctx, request)
+	}
+
+	var sent int
+	result, err := streaming.CreateMessageStream(ctx, request, func(delta *mcp.CreateMessageResult) error {
+		if delta == nil {
+			return nil
+		}
+		if err := sendDelta(ctx, mcp.NewSamplingDeltaNotification(streamID, sent, *delta)); err != nil {
+			return fmt.Errorf("send sampling delta: %w", err)
+		}
+		sent++
+		return nil
+	})
+	if err != nil || result == nil {
+		return result, err
+	}
+	mcp.SetSamplingStreamDeltas(result, sent)
+	return result, nil
+}
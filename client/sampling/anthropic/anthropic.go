@@ -0,0 +1,275 @@
+// Package anthropic implements client.SamplingHandler on top of an
+// Anthropic-compatible Messages API, so a host can enable sampling with a
+// single option:
+//
+//	c := client.NewClient(t, client.WithSamplingHandler(anthropic.New(os.Getenv("ANTHROPIC_API_KEY"))))
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client/sampling"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Anthropic API.
+	DefaultBaseURL = "https://api.anthropic.com/v1"
+	// DefaultModel is used when no model hint matches an available model.
+	DefaultModel = "claude-3-5-haiku-latest"
+	// DefaultMaxTokens is sent when the request does not set max tokens,
+	// which the Messages API requires.
+	DefaultMaxTokens = 1024
+	// APIVersion is the value of the anthropic-version header.
+	APIVersion = "2023-06-01"
+)
+
+// Handler forwards sampling/createMessage requests to the Messages endpoint
+// of an Anthropic-compatible API.
+type Handler struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	models     []string
+	httpClient *http.Client
+	headers    http.Header
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithBaseURL sets the base URL of the API, for example to target a proxy.
+// Defaults to DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(h *Handler) {
+		h.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithModel sets the model used when the request's model hints match none of
+// the models configured with WithModels. Defaults to DefaultModel.
+func WithModel(model string) Option {
+	return func(h *Handler) {
+		h.model = model
+	}
+}
+
+// WithModels sets the models the request's model hints are matched against.
+func WithModels(models ...string) Option {
+	return func(h *Handler) {
+		h.models = models
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to call the API.
+func WithHTTPClient(client *http.Client) Option {
+	return func(h *Handler) {
+		h.httpClient = client
+	}
+}
+
+// WithHeader adds a header sent with every API request, such as
+// anthropic-beta.
+func WithHeader(key, value string) Option {
+	return func(h *Handler) {
+		h.headers.Add(key, value)
+	}
+}
+
+// New creates a Handler authenticating with apiKey.
+func New(apiKey string, opts ...Option) *Handler {
+	h := &Handler{
+		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
+		model:      DefaultModel,
+		httpClient: http.DefaultClient,
+		headers:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// APIError is returned when the API answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("anthropic: status %d: %s", e.StatusCode, e.Message)
+}
+
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+type contentBlock struct {
+	Type   string       `json:"type"`
+	Text   string       `json:"text,omitempty"`
+	Source *imageSource `json:"source,omitempty"`
+}
+
+type imageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+type messagesRequest struct {
+	Model         string    `json:"model"`
+	MaxTokens     int       `json:"max_tokens"`
+	System        string    `json:"system,omitempty"`
+	Messages      []message `json:"messages"`
+	Temperature   *float64  `json:"temperature,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+}
+
+type messagesResponse struct {
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      *struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// CreateMessage implements client.SamplingHandler. The model is chosen from
+// the request's model hints, and its system prompt, max tokens, temperature
+// and stop sequences are forwarded. Text and image content is supported.
+// Token usage reported by the API is recorded with mcp.SetSamplingUsage.
+func (h *Handler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	body := messagesRequest{
+		Model:         sampling.SelectModel(request.ModelPreferences, h.models, h.model),
+		MaxTokens:     request.MaxTokens,
+		System:        request.SystemPrompt,
+		StopSequences: request.StopSequences,
+	}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = DefaultMaxTokens
+	}
+	if request.Temperature != 0 {
+		temperature := request.Temperature
+		body.Temperature = &temperature
+	}
+	for i, m := range request.Messages {
+		block, err := convertContent(m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("anthropic: message %d: %w", i, err)
+		}
+		body.Messages = append(body.Messages, message{Role: string(m.Role), Content: []contentBlock{block}})
+	}
+
+	var response messagesResponse
+	if err := h.post(ctx, "/messages", body, &response); err != nil {
+		return nil, err
+	}
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	result := &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: mcp.NewTextContent(text.String()),
+		},
+		Model:      response.Model,
+		StopReason: stopReason(response.StopReason),
+	}
+	if response.Usage != nil {
+		mcp.SetSamplingUsage(result, mcp.SamplingUsage{
+			InputTokens:  response.Usage.InputTokens,
+			OutputTokens: response.Usage.OutputTokens,
+		})
+	}
+	return result, nil
+}
+
+// convertContent converts MCP message content to a Messages API block.
+func convertContent(content any) (contentBlock, error) {
+	switch c := content.(type) {
+	case mcp.TextContent:
+		return contentBlock{Type: "text", Text: c.Text}, nil
+	case mcp.ImageContent:
+		return contentBlock{
+			Type:   "image",
+			Source: &imageSource{Type: "base64", MediaType: c.MIMEType, Data: c.Data},
+		}, nil
+	default:
+		return contentBlock{}, fmt.Errorf("unsupported content type %T", content)
+	}
+}
+
+// stopReason maps a Messages API stop reason to an MCP stop reason.
+func stopReason(reason string) string {
+	switch reason {
+	case "end_turn":
+		return sampling.StopReasonEndTurn
+	case "max_tokens":
+		return sampling.StopReasonMaxTokens
+	case "stop_sequence":
+		return sampling.StopReasonStopSequence
+	default:
+		return reason
+	}
+}
+
+// post sends body as JSON to path and decodes the response into out.
+func (h *Handler) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("anthropic: marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("anthropic: create request: %w", err)
+	}
+	for key, values := range h.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("anthropic-version", APIVersion)
+	if h.apiKey != "" {
+		req.Header.Set("x-api-key", h.apiKey)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("anthropic: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Message: errorMessage(resp.Body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("anthropic: decode response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message of an API error body, falling back to
+// the raw body.
+func errorMessage(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, 1<<16))
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+		return apiErr.Error.Message
+	}
+	return strings.TrimSpace(string(data))
+}
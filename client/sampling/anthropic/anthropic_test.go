@@ -0,0 +1,73 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ client.SamplingHandler = (*Handler)(nil)
+
+func TestHandler_CreateMessage(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "sk-ant", r.Header.Get("x-api-key"))
+		assert.Equal(t, APIVersion, r.Header.Get("anthropic-version"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"model":"claude-3-5-sonnet-20241022","content":[{"type":"text","text":"Hi"},{"type":"text","text":" there"}],"stop_reason":"stop_sequence","usage":{"input_tokens":20,"output_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	h := New("sk-ant", WithBaseURL(srv.URL+"/v1"), WithModels("claude-3-5-haiku-latest", "claude-3-5-sonnet-20241022"))
+	result, err := h.CreateMessage(t.Context(), mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+		Messages: []mcp.SamplingMessage{
+			{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello")},
+			{Role: mcp.RoleUser, Content: mcp.NewImageContent("aGVsbG8=", "image/png")},
+		},
+		ModelPreferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "sonnet"}}},
+		SystemPrompt:     "Be brief.",
+		StopSequences:    []string{"END"},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "claude-3-5-sonnet-20241022", got["model"])
+	assert.Equal(t, float64(DefaultMaxTokens), got["max_tokens"])
+	assert.Equal(t, "Be brief.", got["system"])
+	assert.NotContains(t, got, "temperature")
+	assert.Equal(t, []any{"END"}, got["stop_sequences"])
+	assert.Equal(t, []any{
+		map[string]any{"role": "user", "content": []any{map[string]any{"type": "text", "text": "Hello"}}},
+		map[string]any{"role": "user", "content": []any{map[string]any{
+			"type":   "image",
+			"source": map[string]any{"type": "base64", "media_type": "image/png", "data": "aGVsbG8="},
+		}}},
+	}, got["messages"])
+
+	assert.Equal(t, "Hi there", result.Content.(mcp.TextContent).Text)
+	assert.Equal(t, "claude-3-5-sonnet-20241022", result.Model)
+	assert.Equal(t, "stopSequence", result.StopReason)
+	usage, ok := mcp.SamplingUsageFromMeta(result.Meta)
+	require.True(t, ok)
+	assert.Equal(t, mcp.SamplingUsage{InputTokens: 20, OutputTokens: 2}, usage)
+}
+
+func TestHandler_CreateMessage_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := New("key", WithBaseURL(srv.URL)).CreateMessage(t.Context(), mcp.CreateMessageRequest{})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.EqualError(t, err, "anthropic: status 429: slow down")
+}
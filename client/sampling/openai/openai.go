@@ -0,0 +1,266 @@
+// Package openai implements client.SamplingHandler on top of an
+// OpenAI-compatible chat completions API, so a host can enable sampling with
+// a single option:
+//
+//	c := client.NewClient(t, client.WithSamplingHandler(openai.New(os.Getenv("OPENAI_API_KEY"))))
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client/sampling"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the OpenAI API.
+	DefaultBaseURL = "https://api.openai.com/v1"
+	// DefaultModel is used when no model hint matches an available model.
+	DefaultModel = "gpt-4o-mini"
+)
+
+// Handler forwards sampling/createMessage requests to the chat completions
+// endpoint of an OpenAI-compatible API.
+type Handler struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	models     []string
+	httpClient *http.Client
+	headers    http.Header
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithBaseURL sets the base URL of the API, for example to target a local
+// or third-party OpenAI-compatible server. Defaults to DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(h *Handler) {
+		h.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithModel sets the model used when the request's model hints match none of
+// the models configured with WithModels. Defaults to DefaultModel.
+func WithModel(model string) Option {
+	return func(h *Handler) {
+		h.model = model
+	}
+}
+
+// WithModels sets the models the request's model hints are matched against.
+func WithModels(models ...string) Option {
+	return func(h *Handler) {
+		h.models = models
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to call the API.
+func WithHTTPClient(client *http.Client) Option {
+	return func(h *Handler) {
+		h.httpClient = client
+	}
+}
+
+// WithHeader adds a header sent with every API request, such as an
+// organization or project ID.
+func WithHeader(key, value string) Option {
+	return func(h *Handler) {
+		h.headers.Add(key, value)
+	}
+}
+
+// New creates a Handler authenticating with apiKey. An empty apiKey sends no
+// Authorization header, which some local servers expect.
+func New(apiKey string, opts ...Option) *Handler {
+	h := &Handler{
+		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
+		model:      DefaultModel,
+		httpClient: http.DefaultClient,
+		headers:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// APIError is returned when the API answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("openai: status %d: %s", e.StatusCode, e.Message)
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// CreateMessage implements client.SamplingHandler. The model is chosen from
+// the request's model hints, and its system prompt, max tokens, temperature
+// and stop sequences are forwarded. Text and image content is supported.
+// Token usage reported by the API is recorded with mcp.SetSamplingUsage.
+func (h *Handler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	body := chatRequest{
+		Model:     sampling.SelectModel(request.ModelPreferences, h.models, h.model),
+		MaxTokens: request.MaxTokens,
+		Stop:      request.StopSequences,
+	}
+	if request.Temperature != 0 {
+		temperature := request.Temperature
+		body.Temperature = &temperature
+	}
+	if request.SystemPrompt != "" {
+		body.Messages = append(body.Messages, chatMessage{Role: "system", Content: request.SystemPrompt})
+	}
+	for i, message := range request.Messages {
+		content, err := convertContent(message.Content)
+		if err != nil {
+			return nil, fmt.Errorf("openai: message %d: %w", i, err)
+		}
+		body.Messages = append(body.Messages, chatMessage{Role: string(message.Role), Content: content})
+	}
+
+	var response chatResponse
+	if err := h.post(ctx, "/chat/completions", body, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("openai: response has no choices")
+	}
+	choice := response.Choices[0]
+	result := &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: mcp.NewTextContent(choice.Message.Content),
+		},
+		Model:      response.Model,
+		StopReason: stopReason(choice.FinishReason),
+	}
+	if response.Usage != nil {
+		mcp.SetSamplingUsage(result, mcp.SamplingUsage{
+			InputTokens:  response.Usage.PromptTokens,
+			OutputTokens: response.Usage.CompletionTokens,
+		})
+	}
+	return result, nil
+}
+
+// convertContent converts MCP message content to chat completions content.
+func convertContent(content any) (any, error) {
+	switch c := content.(type) {
+	case mcp.TextContent:
+		return c.Text, nil
+	case mcp.ImageContent:
+		return []contentPart{{
+			Type:     "image_url",
+			ImageURL: &imageURL{URL: "data:" + c.MIMEType + ";base64," + c.Data},
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %T", content)
+	}
+}
+
+// stopReason maps a chat completions finish reason to an MCP stop reason.
+func stopReason(finishReason string) string {
+	switch finishReason {
+	case "stop":
+		return sampling.StopReasonEndTurn
+	case "length":
+		return sampling.StopReasonMaxTokens
+	default:
+		return finishReason
+	}
+}
+
+// post sends body as JSON to path and decodes the response into out.
+func (h *Handler) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("openai: marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("openai: create request: %w", err)
+	}
+	for key, values := range h.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("openai: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Message: errorMessage(resp.Body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("openai: decode response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message of an API error body, falling back to
+// the raw body.
+func errorMessage(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, 1<<16))
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+		return apiErr.Error.Message
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,86 @@
+package openai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ client.SamplingHandler = (*Handler)(nil)
+
+func TestHandler_CreateMessage(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		assert.Equal(t, "org-1", r.Header.Get("OpenAI-Organization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"model":"gpt-4o-2024-08-06","choices":[{"message":{"role":"assistant","content":"Hi!"},"finish_reason":"length"}],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`))
+	}))
+	defer srv.Close()
+
+	h := New("sk-test",
+		WithBaseURL(srv.URL+"/v1/"),
+		WithModels("gpt-4o-mini", "gpt-4o"),
+		WithHeader("OpenAI-Organization", "org-1"),
+	)
+	result, err := h.CreateMessage(t.Context(), mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+		Messages: []mcp.SamplingMessage{
+			{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello")},
+			{Role: mcp.RoleUser, Content: mcp.NewImageContent("aGVsbG8=", "image/png")},
+		},
+		ModelPreferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "mini"}}},
+		SystemPrompt:     "Be brief.",
+		MaxTokens:        50,
+		Temperature:      0.5,
+		StopSequences:    []string{"\n\n"},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "gpt-4o-mini", got["model"])
+	assert.Equal(t, 50.0, got["max_tokens"])
+	assert.Equal(t, 0.5, got["temperature"])
+	assert.Equal(t, []any{"\n\n"}, got["stop"])
+	assert.Equal(t, []any{
+		map[string]any{"role": "system", "content": "Be brief."},
+		map[string]any{"role": "user", "content": "Hello"},
+		map[string]any{"role": "user", "content": []any{
+			map[string]any{"type": "image_url", "image_url": map[string]any{"url": "data:image/png;base64,aGVsbG8="}},
+		}},
+	}, got["messages"])
+
+	assert.Equal(t, mcp.RoleAssistant, result.Role)
+	assert.Equal(t, "Hi!", result.Content.(mcp.TextContent).Text)
+	assert.Equal(t, "gpt-4o-2024-08-06", result.Model)
+	assert.Equal(t, "maxTokens", result.StopReason)
+	usage, ok := mcp.SamplingUsageFromMeta(result.Meta)
+	require.True(t, ok)
+	assert.Equal(t, mcp.SamplingUsage{InputTokens: 12, OutputTokens: 3}, usage)
+}
+
+func TestHandler_CreateMessage_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	}))
+	defer srv.Close()
+	request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+		Messages: []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello")}},
+	}}
+
+	_, err := New("bad", WithBaseURL(srv.URL)).CreateMessage(t.Context(), request)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "invalid api key", apiErr.Message)
+
+	request.Messages[0].Content = mcp.NewAudioContent("d2F2", "audio/wav")
+	_, err = New("key", WithBaseURL(srv.URL)).CreateMessage(t.Context(), request)
+	assert.ErrorContains(t, err, "unsupported content type")
+}
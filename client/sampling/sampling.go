@@ -0,0 +1,38 @@
+// Package sampling holds helpers shared by the client sampling adapters in
+// its subpackages, which implement client.SamplingHandler on top of
+// OpenAI- and Anthropic-compatible HTTP APIs.
+package sampling
+
+import (
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Stop reasons reported in mcp.CreateMessageResult.
+const (
+	StopReasonEndTurn      = "endTurn"
+	StopReasonMaxTokens    = "maxTokens"
+	StopReasonStopSequence = "stopSequence"
+)
+
+// SelectModel picks the model for a sampling request. The request's model
+// hints are evaluated in order and the first one that is a substring of an
+// available model wins. Without a match, or without available models,
+// fallback is returned.
+func SelectModel(preferences *mcp.ModelPreferences, models []string, fallback string) string {
+	if preferences == nil {
+		return fallback
+	}
+	for _, hint := range preferences.Hints {
+		if hint.Name == "" {
+			continue
+		}
+		for _, model := range models {
+			if strings.Contains(model, hint.Name) {
+				return model
+			}
+		}
+	}
+	return fallback
+}
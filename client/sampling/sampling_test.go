@@ -0,0 +1,37 @@
+package sampling
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectModel(t *testing.T) {
+	models := []string{"gpt-4o-mini", "claude-3-5-sonnet-20241022", "claude-3-haiku-20240307"}
+	hints := func(names ...string) *mcp.ModelPreferences {
+		prefs := &mcp.ModelPreferences{}
+		for _, name := range names {
+			prefs.Hints = append(prefs.Hints, mcp.ModelHint{Name: name})
+		}
+		return prefs
+	}
+
+	tests := []struct {
+		name        string
+		preferences *mcp.ModelPreferences
+		models      []string
+		want        string
+	}{
+		{name: "no preferences", models: models, want: "default"},
+		{name: "substring match", preferences: hints("sonnet"), models: models, want: "claude-3-5-sonnet-20241022"},
+		{name: "first matching hint wins", preferences: hints("gemini", "haiku", "sonnet"), models: models, want: "claude-3-haiku-20240307"},
+		{name: "no match", preferences: hints("gemini"), models: models, want: "default"},
+		{name: "no available models", preferences: hints("sonnet"), want: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SelectModel(tt.preferences, tt.models, "default"))
+		})
+	}
+}
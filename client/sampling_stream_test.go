@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingSamplingHandler streams its response one word at a time.
+type streamingSamplingHandler struct {
+	words []string
+}
+
+func (h *streamingSamplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent(strings.Join(h.words, ""))},
+		Model:           "buffered-model",
+		StopReason:      "endTurn",
+	}, nil
+}
+
+func (h *streamingSamplingHandler) CreateMessageStream(
+	ctx context.Context,
+	request mcp.CreateMessageRequest,
+	send func(delta *mcp.CreateMessageResult) error,
+) (*mcp.CreateMessageResult, error) {
+	for _, word := range h.words {
+		err := send(&mcp.CreateMessageResult{
+			SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent(word)},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent(strings.Join(h.words, ""))},
+		Model:           "streaming-model",
+		StopReason:      "endTurn",
+	}, nil
+}
+
+func TestInProcessSamplingStream(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.EnableSampling()
+	mcpServer.AddTool(mcp.NewTool("summarize"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+			Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("Summarize")}},
+			MaxTokens: 100,
+		}}
+		var parts []string
+		var last *mcp.CreateMessageResult
+		for delta, err := range mcpServer.RequestSamplingStream(ctx, request) {
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, delta.Content.(mcp.TextContent).Text)
+			last = delta
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("%s|%s|%s", strings.Join(parts, ","), last.Model, last.StopReason)), nil
+	})
+
+	tests := []struct {
+		name    string
+		handler SamplingHandler
+		want    string
+	}{
+		{
+			name:    "streaming handler",
+			handler: &streamingSamplingHandler{words: []string{"Hello", " wide", " world"}},
+			want:    "Hello, wide, world|streaming-model|endTurn",
+		},
+		{
+			name:    "buffered handler",
+			handler: &MockSamplingHandler{},
+			want:    "Mock response from sampling handler|mock-model|endTurn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewInProcessClientWithSamplingHandler(mcpServer, tt.handler)
+			require.NoError(t, err)
+			defer c.Close()
+			initializeTestClient(t, c)
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = "summarize"
+			result, err := c.CallTool(t.Context(), request)
+			require.NoError(t, err)
+			require.False(t, result.IsError, "%v", result.Content)
+			assert.Equal(t, tt.want, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// State returns the state of the client's connection: connecting until
+// Initialize succeeds, then ready, degraded after a transport failure,
+// reconnecting while Reconnect runs, and closed after Close.
+func (c *Client) State() mcp.ConnectionState {
+	return c.state.State()
+}
+
+// OnStateChange registers a handler function to be called after each
+// transition of the connection state. Multiple handlers can be registered
+// and will be called in the order they were added, on the goroutine that
+// caused the transition.
+func (c *Client) OnStateChange(handler func(change mcp.ConnectionStateChange)) {
+	c.state.OnChange(handler)
+}
+
+// handleConnectionLost is installed on transports that report losing their
+// connection. It degrades the state before calling the handler registered
+// with OnConnectionLost.
+func (c *Client) handleConnectionLost(err error) {
+	c.state.TransitionFrom(mcp.ConnectionReady, mcp.ConnectionDegraded, err)
+	if handler := c.connectionLostHandler; handler != nil {
+		defer c.recoverHandler(context.Background(), "connection-lost", "", nil)
+		handler(err)
+	}
+}
+
+// installConnectionLostHandler wires handleConnectionLost into t, if t can
+// report losing its connection.
+func (c *Client) installConnectionLostHandler(t any) {
+	type connectionLostSetter interface {
+		SetConnectionLostHandler(func(error))
+	}
+	if setter, ok := t.(connectionLostSetter); ok {
+		setter.SetConnectionLostHandler(c.handleConnectionLost)
+	}
+}
+
+// logStateChange emits one line for a transition of the connection state.
+func (c *Client) logStateChange(change mcp.ConnectionStateChange) {
+	if c.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String(logKeyFrom, string(change.From)),
+		slog.String(logKeyTo, string(change.To)),
+	}
+	if change.Reason != nil {
+		attrs = append(attrs, slog.String(logKeyError, change.Reason.Error()))
+	}
+	level := slog.LevelInfo
+	if change.To == mcp.ConnectionDegraded {
+		level = slog.LevelWarn
+	}
+	c.logger.LogAttrs(context.Background(), level, logMessageState, attrs...)
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestClient_State(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+
+	t.Run("degrades and recovers", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		c := NewClient(flaky)
+		var changes []mcp.ConnectionStateChange
+		c.OnStateChange(func(change mcp.ConnectionStateChange) { changes = append(changes, change) })
+		assert.Equal(t, mcp.ConnectionConnecting, c.State())
+
+		initializeTestClient(t, c)
+		assert.Equal(t, mcp.ConnectionReady, c.State())
+
+		flaky.broken.Store(true)
+		require.Error(t, c.Ping(t.Context()))
+		assert.Equal(t, mcp.ConnectionDegraded, c.State())
+		assert.True(t, c.IsInitialized())
+
+		flaky.broken.Store(false)
+		require.NoError(t, c.Ping(t.Context()))
+		require.NoError(t, c.Close())
+
+		var states []mcp.ConnectionState
+		for _, change := range changes {
+			states = append(states, change.To)
+		}
+		assert.Equal(t, []mcp.ConnectionState{
+			mcp.ConnectionReady, mcp.ConnectionDegraded, mcp.ConnectionReady, mcp.ConnectionClosed,
+		}, states)
+		assert.EqualError(t, changes[1].Reason, "connection reset")
+	})
+
+	t.Run("reconnects", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer), healOnStart: true}
+		c := NewClient(flaky, WithReconnect(ReconnectPolicy{}))
+		initializeTestClient(t, c)
+		var changes []mcp.ConnectionStateChange
+		c.OnStateChange(func(change mcp.ConnectionStateChange) { changes = append(changes, change) })
+
+		flaky.broken.Store(true)
+		require.NoError(t, c.Ping(t.Context()))
+		assert.Equal(t, mcp.ConnectionReady, c.State())
+		require.Len(t, changes, 3)
+		assert.Equal(t, mcp.ConnectionDegraded, changes[0].To)
+		assert.Equal(t, mcp.ConnectionReconnecting, changes[1].To)
+		assert.EqualError(t, changes[1].Reason, "connection reset")
+		assert.Equal(t, mcp.ConnectionReady, changes[2].To)
+	})
+
+	t.Run("reconnect fails", func(t *testing.T) {
+		flaky := &flakyTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		c := NewClient(flaky, WithReconnect(ReconnectPolicy{MaxAttempts: 1}))
+		initializeTestClient(t, c)
+
+		flaky.broken.Store(true)
+		require.Error(t, c.Ping(t.Context()))
+		assert.Equal(t, mcp.ConnectionDegraded, c.State())
+	})
+
+	t.Run("connection lost", func(t *testing.T) {
+		c := NewClient(newMockTransport(), WithSession())
+		var lost error
+		c.OnConnectionLost(func(err error) { lost = err })
+		assert.Equal(t, mcp.ConnectionReady, c.State())
+
+		c.handleConnectionLost(errors.New("EOF"))
+		assert.Equal(t, mcp.ConnectionDegraded, c.State())
+		assert.EqualError(t, lost, "EOF")
+	})
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
 func compileTestServer(outputPath string) error {
@@ -727,3 +728,38 @@ func TestStdio_NewStdioMCPClientWithOptions_FailsToStart(t *testing.T) {
 	require.EqualError(t, err, "failed to start stdio transport: failed to start command: fork/exec /nonexistent/bar: no such file or directory")
 	require.Nil(t, client)
 }
+
+func TestStdio_ContentLengthFraming(t *testing.T) {
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+	defer serverWrite.Close()
+	defer clientWrite.Close()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("report"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("line\n", 100000)), nil
+	})
+	stdioServer := server.NewStdioServer(mcpServer, server.WithFraming(server.FramingAuto))
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() { _ = stdioServer.Listen(ctx, serverRead, serverWrite) }()
+
+	stdioTransport := transport.NewIO(clientRead, clientWrite, io.NopCloser(strings.NewReader("")),
+		transport.WithStdioFraming(transport.FramingContentLength))
+	require.NoError(t, stdioTransport.Start(t.Context()))
+	c := NewClient(stdioTransport)
+	defer c.Close()
+
+	req := mcp.InitializeRequest{}
+	req.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	req.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "1.0"}
+	_, err := c.Initialize(t.Context(), req)
+	require.NoError(t, err)
+
+	call := mcp.CallToolRequest{}
+	call.Params.Name = "report"
+	result, err := c.CallTool(t.Context(), call)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	require.Equal(t, strings.Repeat("line\n", 100000), result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOperationTimeout is returned when an operation exceeds the client's
+// default timeout. The error also matches context.DeadlineExceeded.
+var ErrOperationTimeout = errors.New("operation timed out")
+
+// operationTimeoutKey is the context key of a per-call timeout override.
+type operationTimeoutKey struct{}
+
+// WithDefaultTimeout bounds every operation of the client, such as CallTool
+// or ListTools, to d, including its retries and reconnect attempts. The
+// timeout only ends the operation: it does not affect the context passed to
+// Start, which keeps the connection alive, so the same long-lived context can
+// safely be passed to individual calls.
+//
+// A shorter deadline on the caller's context still applies. Use
+// ContextWithTimeout to give a single call a different timeout. Unlike
+// WithRequestTimeout, which bounds each attempt separately, the default
+// timeout covers the whole operation.
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// ContextWithTimeout returns a copy of ctx that overrides the client's
+// default timeout for calls made with it. A zero or negative d disables the
+// default timeout for those calls.
+func ContextWithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, operationTimeoutKey{}, d)
+}
+
+// withOperationTimeout bounds ctx by the timeout that applies to an
+// operation for method started with it.
+func (c *Client) withOperationTimeout(ctx context.Context, method string, params any) (context.Context, context.CancelFunc) {
+	d := c.defaultTimeout
+	if hinted, ok := c.latencyTimeout(method, params); ok {
+		d = hinted
+	}
+	if override, ok := ctx.Value(operationTimeoutKey{}).(time.Duration); ok {
+		d = override
+	}
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, d, fmt.Errorf("%w after %s: %w", ErrOperationTimeout, d, context.DeadlineExceeded))
+}
+
+// operationError reports err as an operation timeout if ctx ended because
+// of the client's default timeout.
+func operationError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrOperationTimeout) && !errors.Is(err, ErrOperationTimeout) {
+		return fmt.Errorf("%w: %w", cause, err)
+	}
+	return err
+}
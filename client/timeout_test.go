@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WithDefaultTimeout(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("slow"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return mcp.NewToolResultText("done"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	callSlow := func(ctx context.Context, c *Client) error {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "slow"
+		_, err := c.CallTool(ctx, request)
+		return err
+	}
+
+	c := NewClient(transport.NewInProcessTransport(mcpServer), WithDefaultTimeout(20*time.Millisecond))
+	initializeTestClient(t, c)
+	connCtx := t.Context()
+
+	t.Run("bounds the operation", func(t *testing.T) {
+		err := callSlow(connCtx, c)
+		assert.ErrorIs(t, err, ErrOperationTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The connection context is untouched, so the client keeps working.
+		require.NoError(t, connCtx.Err())
+		assert.NoError(t, c.Ping(connCtx))
+	})
+
+	t.Run("overridden per call", func(t *testing.T) {
+		assert.NoError(t, callSlow(ContextWithTimeout(connCtx, time.Second), c))
+		assert.NoError(t, callSlow(ContextWithTimeout(connCtx, 0), c))
+	})
+
+	t.Run("shorter caller deadline wins", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(connCtx, time.Millisecond)
+		defer cancel()
+		err := callSlow(ctx, c)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrOperationTimeout)
+	})
+
+	t.Run("covers retries", func(t *testing.T) {
+		failing := &failingTransport{Interface: transport.NewInProcessTransport(mcpServer)}
+		failing.failures.Store(100)
+		c := NewClient(failing,
+			WithDefaultTimeout(50*time.Millisecond),
+			WithRetryPolicy(RetryPolicy{MaxRetries: 100, InitialBackoff: 20 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}),
+		)
+		initializeTestClient(t, c)
+
+		err := c.Ping(connCtx)
+		assert.ErrorIs(t, err, ErrOperationTimeout)
+		assert.Less(t, failing.sent.Load(), int32(10))
+	})
+}
@@ -0,0 +1,342 @@
+// Package toolargs turns a tool's input schema into command-line flags and
+// interactive prompts, for CLI clients and hosts that build quick UIs for
+// calling tools. Types, enums, defaults and required properties all come
+// from the schema.
+//
+// Scalars map to flags of the same name. Arrays of scalars take the flag
+// once per item, or a comma-separated list; objects and other arrays take
+// a JSON value.
+package toolargs
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Param is one property of a tool's input schema.
+type Param struct {
+	Name        string
+	Type        string // JSON Schema type: string, integer, number, boolean, array or object
+	ItemType    string // item type of arrays, empty if not a scalar
+	Description string
+	Enum        []any
+	Default     any
+	Required    bool
+}
+
+type propertySchema struct {
+	Type        any             `json:"type"`
+	Description string          `json:"description"`
+	Enum        []any           `json:"enum"`
+	Default     any             `json:"default"`
+	Items       *propertySchema `json:"items"`
+}
+
+// Params returns the properties of the tool's input schema, required ones
+// first and each group sorted by name.
+func Params(tool mcp.Tool) ([]Param, error) {
+	raw := tool.RawInputSchema
+	if raw == nil {
+		var err error
+		if raw, err = json.Marshal(tool.InputSchema); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+	}
+	var schema struct {
+		Properties map[string]propertySchema `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("tool %s: invalid input schema: %w", tool.Name, err)
+	}
+
+	params := make([]Param, 0, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		p := Param{
+			Name:        name,
+			Type:        schemaType(prop.Type),
+			Description: prop.Description,
+			Enum:        prop.Enum,
+			Default:     prop.Default,
+			Required:    slices.Contains(schema.Required, name),
+		}
+		if p.Type == "array" && prop.Items != nil {
+			if t := schemaType(prop.Items.Type); isScalar(t) {
+				p.ItemType = t
+			}
+		}
+		params = append(params, p)
+	}
+	slices.SortFunc(params, func(a, b Param) int {
+		if a.Required != b.Required {
+			if a.Required {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return params, nil
+}
+
+// schemaType returns the type of a property, the first non-null type for a
+// list of types, or "string" when the schema has none.
+func schemaType(t any) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return "string"
+}
+
+func isScalar(t string) bool {
+	switch t {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+	return false
+}
+
+// Parse converts the text form of a value to the param's type. Arrays of
+// scalars accept a comma-separated list, everything else that is not a
+// scalar must be JSON. The result is checked against the enum, if any.
+func (p Param) Parse(s string) (any, error) {
+	var v any
+	var err error
+	switch {
+	case isScalar(p.Type):
+		v, err = parseScalar(p.Type, s)
+	case p.Type == "array" && p.ItemType != "" && !strings.HasPrefix(strings.TrimSpace(s), "["):
+		items := []any{}
+		for item := range strings.SplitSeq(s, ",") {
+			iv, err := parseScalar(p.ItemType, strings.TrimSpace(item))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p.Name, err)
+			}
+			items = append(items, iv)
+		}
+		v = items
+	default:
+		err = json.Unmarshal([]byte(s), &v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Name, err)
+	}
+	if len(p.Enum) > 0 && !slices.ContainsFunc(p.Enum, func(e any) bool { return sameValue(e, v) }) {
+		return nil, fmt.Errorf("%s: %q is not one of %s", p.Name, s, p.choices())
+	}
+	return v, nil
+}
+
+func parseScalar(typ, s string) (any, error) {
+	switch typ {
+	case "integer":
+		return strconv.ParseInt(s, 10, 64)
+	case "number":
+		return strconv.ParseFloat(s, 64)
+	case "boolean":
+		return strconv.ParseBool(s)
+	default:
+		return s, nil
+	}
+}
+
+// sameValue reports whether an enum value decoded from JSON equals a
+// parsed value, comparing numbers by value.
+func sameValue(enum, v any) bool {
+	if n, ok := v.(int64); ok {
+		v = float64(n)
+	}
+	return fmt.Sprint(enum) == fmt.Sprint(v)
+}
+
+func (p Param) choices() string {
+	choices := make([]string, len(p.Enum))
+	for i, e := range p.Enum {
+		choices[i] = fmt.Sprint(e)
+	}
+	return strings.Join(choices, "|")
+}
+
+func (p Param) usage() string {
+	usage := p.Description
+	if len(p.Enum) > 0 {
+		usage = strings.TrimSpace(usage + " (" + p.choices() + ")")
+	}
+	if p.Required {
+		usage = strings.TrimSpace(usage + " [required]")
+	}
+	return usage
+}
+
+// FlagSet is a flag.FlagSet with one flag per property of a tool's input
+// schema.
+type FlagSet struct {
+	*flag.FlagSet
+	params []Param
+	values map[string]any
+}
+
+// NewFlagSet returns a flag set named after the tool, with one flag per
+// property of its input schema. Call Parse and then Arguments.
+func NewFlagSet(tool mcp.Tool, errorHandling flag.ErrorHandling) (*FlagSet, error) {
+	params, err := Params(tool)
+	if err != nil {
+		return nil, err
+	}
+	fs := &FlagSet{
+		FlagSet: flag.NewFlagSet(tool.Name, errorHandling),
+		params:  params,
+		values:  make(map[string]any),
+	}
+	for _, p := range params {
+		fs.Var(&flagValue{param: p, values: fs.values}, p.Name, p.usage())
+	}
+	return fs, nil
+}
+
+// Arguments returns the tool call arguments from the parsed flags, with
+// schema defaults for flags that were not set. It fails if a required
+// property has neither.
+func (fs *FlagSet) Arguments() (map[string]any, error) {
+	return arguments(fs.params, fs.values)
+}
+
+func arguments(params []Param, values map[string]any) (map[string]any, error) {
+	args := make(map[string]any, len(params))
+	var missing []string
+	for _, p := range params {
+		switch v, ok := values[p.Name]; {
+		case ok:
+			args[p.Name] = v
+		case p.Default != nil:
+			args[p.Name] = p.Default
+		case p.Required:
+			missing = append(missing, p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required arguments: %s", strings.Join(missing, ", "))
+	}
+	return args, nil
+}
+
+// flagValue is the flag.Value of one param. Arrays of scalars collect
+// their items across repeated flags.
+type flagValue struct {
+	param  Param
+	values map[string]any
+}
+
+func (f *flagValue) String() string {
+	if f == nil || f.param.Default == nil {
+		return ""
+	}
+	if isScalar(f.param.Type) {
+		return fmt.Sprint(f.param.Default)
+	}
+	b, _ := json.Marshal(f.param.Default)
+	return string(b)
+}
+
+func (f *flagValue) Set(s string) error {
+	v, err := f.param.Parse(s)
+	if err != nil {
+		return err
+	}
+	if items, ok := v.([]any); ok && f.param.ItemType != "" {
+		if prev, ok := f.values[f.param.Name].([]any); ok {
+			v = append(prev, items...)
+		}
+	}
+	f.values[f.param.Name] = v
+	return nil
+}
+
+// IsBoolFlag lets boolean flags be given without a value.
+func (f *flagValue) IsBoolFlag() bool {
+	return f.param.Type == "boolean"
+}
+
+// ErrAborted is returned by Prompt when input ends before all required
+// arguments were given.
+var ErrAborted = errors.New("toolargs: input ended")
+
+// Prompt asks for each property of the tool's input schema on out and
+// reads the answers from in, one line each. An empty answer takes the
+// schema default, or skips an optional property; invalid answers are
+// asked again.
+func Prompt(tool mcp.Tool, in io.Reader, out io.Writer) (map[string]any, error) {
+	params, err := Params(tool)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(in)
+	values := make(map[string]any, len(params))
+	for _, p := range params {
+		for {
+			fmt.Fprint(out, prompt(p))
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, ErrAborted
+			}
+			answer := strings.TrimSpace(scanner.Text())
+			if answer == "" {
+				if p.Required && p.Default == nil {
+					fmt.Fprintf(out, "%s is required\n", p.Name)
+					continue
+				}
+				break
+			}
+			v, err := p.Parse(answer)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			values[p.Name] = v
+			break
+		}
+	}
+	return arguments(params, values)
+}
+
+func prompt(p Param) string {
+	var b strings.Builder
+	if p.Description != "" {
+		fmt.Fprintf(&b, "# %s\n", p.Description)
+	}
+	b.WriteString(p.Name)
+	typ := p.Type
+	if p.ItemType != "" {
+		typ = p.ItemType + ", ..."
+	}
+	if len(p.Enum) > 0 {
+		typ = p.choices()
+	}
+	fmt.Fprintf(&b, " (%s)", typ)
+	if p.Required {
+		b.WriteString("*")
+	}
+	if p.Default != nil {
+		fmt.Fprintf(&b, " [%s]", (&flagValue{param: p}).String())
+	}
+	b.WriteString(": ")
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package toolargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchTool() mcp.Tool {
+	return mcp.NewTool("search",
+		mcp.WithString("query", mcp.Required(), mcp.Description("Text to search for")),
+		mcp.WithNumber("limit", mcp.DefaultNumber(10)),
+		mcp.WithString("order", mcp.Enum("asc", "desc")),
+		mcp.WithBoolean("exact"),
+		mcp.WithArray("tags", mcp.WithStringItems()),
+		mcp.WithObject("filter"),
+	)
+}
+
+func TestParams(t *testing.T) {
+	params, err := Params(searchTool())
+	require.NoError(t, err)
+
+	var names []string
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"query", "exact", "filter", "limit", "order", "tags"}, names)
+	assert.True(t, params[0].Required)
+	assert.Equal(t, "Text to search for", params[0].Description)
+	assert.Equal(t, "string", params[5].ItemType)
+	assert.Equal(t, []any{"asc", "desc"}, params[4].Enum)
+}
+
+func TestParams_RawSchema(t *testing.T) {
+	tool := mcp.NewToolWithRawSchema("raw", "", json.RawMessage(`{
+		"type": "object",
+		"properties": {"count": {"type": ["integer", "null"]}},
+		"required": ["count"]
+	}`))
+	params, err := Params(tool)
+	require.NoError(t, err)
+	require.Len(t, params, 1)
+	assert.Equal(t, Param{Name: "count", Type: "integer", Required: true}, params[0])
+}
+
+func TestFlagSet(t *testing.T) {
+	fs, err := NewFlagSet(searchTool(), flag.ContinueOnError)
+	require.NoError(t, err)
+	fs.SetOutput(&bytes.Buffer{})
+
+	require.NoError(t, fs.Parse([]string{
+		"-query", "mcp", "-exact", "-order", "desc",
+		"-tags", "a,b", "-tags", "c", "-filter", `{"lang":"go"}`,
+	}))
+	args, err := fs.Arguments()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"query":  "mcp",
+		"limit":  float64(10),
+		"order":  "desc",
+		"exact":  true,
+		"tags":   []any{"a", "b", "c"},
+		"filter": map[string]any{"lang": "go"},
+	}, args)
+}
+
+func TestFlagSet_Errors(t *testing.T) {
+	fs, err := NewFlagSet(searchTool(), flag.ContinueOnError)
+	require.NoError(t, err)
+	fs.SetOutput(&bytes.Buffer{})
+
+	assert.ErrorContains(t, fs.Parse([]string{"-order", "random"}), `"random" is not one of asc|desc`)
+	assert.ErrorContains(t, fs.Parse([]string{"-limit", "ten"}), "limit")
+
+	require.NoError(t, fs.Parse(nil))
+	_, err = fs.Arguments()
+	assert.EqualError(t, err, "missing required arguments: query")
+}
+
+func TestPrompt(t *testing.T) {
+	tool := mcp.NewTool("search",
+		mcp.WithString("query", mcp.Required()),
+		mcp.WithNumber("limit", mcp.DefaultNumber(10)),
+		mcp.WithString("order", mcp.Enum("asc", "desc")),
+	)
+	in := strings.NewReader("\nmcp\n\nup\ndesc\n")
+	var out bytes.Buffer
+
+	args, err := Prompt(tool, in, &out)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"query": "mcp", "limit": float64(10), "order": "desc"}, args)
+	assert.Contains(t, out.String(), "query (string)*: ")
+	assert.Contains(t, out.String(), "query is required")
+	assert.Contains(t, out.String(), "limit (number) [10]: ")
+	assert.Contains(t, out.String(), `"up" is not one of asc|desc`)
+}
+
+func TestPrompt_Aborted(t *testing.T) {
+	_, err := Prompt(searchTool(), strings.NewReader(""), &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrAborted)
+}
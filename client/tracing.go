@@ -35,8 +35,10 @@ func WithPropagator(p tracing.Propagator) ClientOption {
 }
 
 // WithMetaPropagator installs a propagator that injects trace context into
-// the MCP _meta property bag of outgoing requests (per SEP-414). It covers
-// all transports including stdio, where HTTP headers are unavailable.
+// the MCP _meta property bag of outgoing requests (per SEP-414), and
+// extracts it from the _meta of server-initiated sampling and elicitation
+// requests into the handler context. It covers all transports including
+// stdio, where HTTP headers are unavailable.
 // A nil propagator is treated as a no-op.
 func WithMetaPropagator(p tracing.MetaPropagator) ClientOption {
 	if p == nil {
@@ -55,6 +57,16 @@ func (c *Client) injectMeta(ctx context.Context, meta *mcp.Meta) *mcp.Meta {
 	return p.InjectMeta(ctx, meta)
 }
 
+// extractMeta returns ctx carrying the trace context found in the _meta bag
+// of a server-initiated request.
+func (c *Client) extractMeta(ctx context.Context, meta *mcp.Meta) context.Context {
+	p := c.metaPropagator
+	if p == nil {
+		return ctx
+	}
+	return p.ExtractMeta(ctx, meta)
+}
+
 func (c *Client) startSendSpan(
 	ctx context.Context,
 	method string,
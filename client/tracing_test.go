@@ -124,3 +124,45 @@ func TestNoOption_DoesNotInjectHeaders(t *testing.T) {
 
 	assert.Empty(t, wrapped.last.Get("X-Test-Propagated"))
 }
+
+type traceIDKey struct{}
+
+// traceIDMetaPropagator extracts the "traceid" _meta field into the context.
+type traceIDMetaPropagator struct{}
+
+func (traceIDMetaPropagator) InjectMeta(_ context.Context, meta *mcp.Meta) *mcp.Meta { return meta }
+
+func (traceIDMetaPropagator) ExtractMeta(ctx context.Context, meta *mcp.Meta) context.Context {
+	if meta == nil {
+		return ctx
+	}
+	if id, ok := meta.AdditionalFields["traceid"].(string); ok {
+		return context.WithValue(ctx, traceIDKey{}, id)
+	}
+	return ctx
+}
+
+type samplingHandlerFunc func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+func (f samplingHandlerFunc) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return f(ctx, request)
+}
+
+func TestWithMetaPropagator_ExtractsServerRequestMeta(t *testing.T) {
+	var got string
+	c := &Client{
+		metaPropagator: traceIDMetaPropagator{},
+		samplingHandler: samplingHandlerFunc(func(ctx context.Context, _ mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			got, _ = ctx.Value(traceIDKey{}).(string)
+			return &mcp.CreateMessageResult{}, nil
+		}),
+	}
+
+	request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+		Meta:      &mcp.Meta{AdditionalFields: map[string]any{"traceid": "abc123"}},
+		MaxTokens: 10,
+	}}
+	_, err := c.handleIncomingRequest(t.Context(), mockJSONRPCRequest(request))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", got)
+}
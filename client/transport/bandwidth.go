@@ -0,0 +1,82 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/internal/bandwidth"
+)
+
+// WithStdioBandwidthLimit caps the bytes per second the client reads from
+// the subprocess's stdout and writes to its stdin. Zero leaves a direction
+// unlimited.
+func WithStdioBandwidthLimit(readBytesPerSecond, writeBytesPerSecond int) StdioOption {
+	return func(s *Stdio) {
+		s.readLimit = readBytesPerSecond
+		s.writeLimit = writeBytesPerSecond
+	}
+}
+
+// WithSSEBandwidthLimit caps the bytes per second the client reads from the
+// server, on the event stream and in responses, and writes to it in request
+// bodies. It applies to the client set with WithHTTPClient as well, without
+// affecting its other users. Zero leaves a direction unlimited.
+func WithSSEBandwidthLimit(readBytesPerSecond, writeBytesPerSecond int) ClientOption {
+	return func(sc *SSE) {
+		sc.readLimit = readBytesPerSecond
+		sc.writeLimit = writeBytesPerSecond
+	}
+}
+
+// WithHTTPBandwidthLimit caps the bytes per second the client reads from the
+// server, in responses and SSE streams, and writes to it in request bodies.
+// It applies to the client set with WithHTTPBasicClient or WithHTTPPool as
+// well, without affecting its other users. Zero leaves a direction
+// unlimited.
+func WithHTTPBandwidthLimit(readBytesPerSecond, writeBytesPerSecond int) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.readLimit = readBytesPerSecond
+		sc.writeLimit = writeBytesPerSecond
+	}
+}
+
+// throttledHTTPClient returns a copy of client whose transport paces
+// response bodies to readLimit and request bodies to writeLimit bytes per
+// second, or client itself when both are unlimited.
+func throttledHTTPClient(client *http.Client, readLimit, writeLimit int) *http.Client {
+	if readLimit <= 0 && writeLimit <= 0 {
+		return client
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	throttled := *client
+	throttled.Transport = &throttledRoundTripper{
+		base:  base,
+		read:  bandwidth.NewLimiter(readLimit),
+		write: bandwidth.NewLimiter(writeLimit),
+	}
+	return &throttled
+}
+
+// throttledRoundTripper paces the bodies of the requests it sends and the
+// responses it receives. The limiters are shared by every request, so the
+// limits hold for the whole session.
+type throttledRoundTripper struct {
+	base  http.RoundTripper
+	read  *bandwidth.Limiter
+	write *bandwidth.Limiter
+}
+
+func (t *throttledRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && t.write != nil {
+		req = req.Clone(req.Context())
+		req.Body = bandwidth.NewReadCloser(req.Context(), req.Body, t.write)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = bandwidth.NewReadCloser(req.Context(), resp.Body, t.read)
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottledHTTPClient(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+		_, _ = io.WriteString(w, strings.Repeat("r", 1500))
+	}))
+	defer srv.Close()
+
+	base := &http.Client{Timeout: time.Minute}
+	assert.Same(t, base, throttledHTTPClient(base, 0, 0))
+
+	client := throttledHTTPClient(base, 1000, 1000)
+	assert.Nil(t, base.Transport, "the configured client is left untouched")
+	assert.Equal(t, time.Minute, client.Timeout)
+
+	start := time.Now()
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader(strings.Repeat("w", 1500)))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Len(t, body, 1500)
+	assert.Len(t, <-received, 1500)
+	// Each direction exceeds its one second burst by half a second.
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestStreamableHTTP_BandwidthLimitOption(t *testing.T) {
+	trans, err := NewStreamableHTTP("http://localhost", WithHTTPBandwidthLimit(1000, 0))
+	require.NoError(t, err)
+	_, ok := trans.httpClient.Transport.(*throttledRoundTripper)
+	assert.True(t, ok)
+
+	sse, err := NewSSE("http://localhost", WithHTTPClient(http.DefaultClient), WithSSEBandwidthLimit(0, 1000))
+	require.NoError(t, err)
+	_, ok = sse.httpClient.Transport.(*throttledRoundTripper)
+	assert.True(t, ok)
+	assert.Nil(t, http.DefaultClient.Transport)
+}
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPPool is a connection pool that can be shared by many StreamableHTTP
+// transports in the same process. Each transport keeps its own session,
+// headers and timeout, while the underlying TCP/TLS connections (and HTTP/2
+// streams, when the server negotiates HTTP/2) are reused across sessions to
+// the same origin.
+//
+// An HTTPPool is safe for concurrent use. Closing a transport does not close
+// the pool; call CloseIdleConnections when the pool is no longer needed.
+type HTTPPool struct {
+	transport *http.Transport
+}
+
+// HTTPPoolOption configures an HTTPPool.
+type HTTPPoolOption func(*http.Transport)
+
+// WithPoolMaxConnsPerHost limits the total number of connections per origin.
+// Zero means no limit.
+func WithPoolMaxConnsPerHost(n int) HTTPPoolOption {
+	return func(t *http.Transport) {
+		t.MaxConnsPerHost = n
+	}
+}
+
+// WithPoolMaxIdleConnsPerHost sets the number of idle connections kept per
+// origin for reuse.
+func WithPoolMaxIdleConnsPerHost(n int) HTTPPoolOption {
+	return func(t *http.Transport) {
+		t.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithPoolIdleConnTimeout sets how long an idle connection stays in the pool
+// before it is closed.
+func WithPoolIdleConnTimeout(timeout time.Duration) HTTPPoolOption {
+	return func(t *http.Transport) {
+		t.IdleConnTimeout = timeout
+	}
+}
+
+// WithPoolTLSConfig sets the TLS configuration of the pool's connections,
+// such as the client certificates presented to servers requiring mutual
+// TLS.
+func WithPoolTLSConfig(config *tls.Config) HTTPPoolOption {
+	return func(t *http.Transport) {
+		t.TLSClientConfig = config
+	}
+}
+
+// NewHTTPPool creates a connection pool based on http.DefaultTransport with
+// HTTP/2 enabled, so concurrent sessions to one origin are multiplexed over a
+// small number of connections.
+func NewHTTPPool(opts ...HTTPPoolOption) *HTTPPool {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConnsPerHost = 32
+	for _, opt := range opts {
+		opt(t)
+	}
+	return &HTTPPool{transport: t}
+}
+
+// RoundTrip implements http.RoundTripper using the shared connections.
+func (p *HTTPPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	return p.transport.RoundTrip(req)
+}
+
+// CloseIdleConnections closes any pooled connections that are not in use.
+func (p *HTTPPool) CloseIdleConnections() {
+	p.transport.CloseIdleConnections()
+}
+
+// WithHTTPPool makes the StreamableHTTP transport send its requests through
+// the shared pool. The pool is applied after all other options, so it works
+// with WithHTTPTimeout in any order, and the timeout stays local to this
+// transport. A client set with WithHTTPBasicClient keeps its settings and
+// has its requests sent through the pool; NewStreamableHTTP returns an
+// error if that client has a Transport of its own.
+func WithHTTPPool(pool *HTTPPool) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.httpPool = pool
+	}
+}
+
+// pooledHTTPClient returns a copy of client that sends its requests through
+// pool.
+func pooledHTTPClient(client *http.Client, pool *HTTPPool) (*http.Client, error) {
+	if client.Transport != nil && client.Transport != pool {
+		return nil, fmt.Errorf("WithHTTPPool conflicts with the %T of the HTTP client", client.Transport)
+	}
+	pooled := *client
+	pooled.Transport = pool
+	return &pooled, nil
+}
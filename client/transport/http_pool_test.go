@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPPool_SharesConnectionsAcrossTransports(t *testing.T) {
+	var newConns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]any{},
+		})
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	pool := NewHTTPPool()
+	defer pool.CloseIdleConnections()
+
+	for i := range 3 {
+		trans, err := NewStreamableHTTP(srv.URL, WithHTTPTimeout(5*time.Second), WithHTTPPool(pool))
+		require.NoError(t, err)
+		require.NoError(t, trans.Start(context.Background()))
+		assert.Equal(t, 5*time.Second, trans.httpClient.Timeout)
+
+		_, err = trans.SendRequest(context.Background(), JSONRPCRequest{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			ID:      mcp.NewRequestId(int64(i)),
+			Method:  "ping",
+		})
+		require.NoError(t, err)
+		require.NoError(t, trans.Close())
+	}
+
+	assert.Equal(t, int32(1), newConns.Load())
+}
+
+func TestWithHTTPPool_OptionOrder(t *testing.T) {
+	pool := NewHTTPPool()
+	defer pool.CloseIdleConnections()
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	for name, opts := range map[string][]StreamableHTTPCOption{
+		"pool last":  {WithHTTPBasicClient(&http.Client{Jar: jar}), WithHTTPTimeout(5 * time.Second), WithHTTPPool(pool)},
+		"pool first": {WithHTTPPool(pool), WithHTTPBasicClient(&http.Client{Jar: jar}), WithHTTPTimeout(5 * time.Second)},
+	} {
+		trans, err := NewStreamableHTTP("http://example.com", opts...)
+		require.NoError(t, err, name)
+		assert.Same(t, pool, trans.httpClient.Transport, name)
+		assert.Equal(t, 5*time.Second, trans.httpClient.Timeout, name)
+		assert.Same(t, jar, trans.httpClient.Jar, name)
+	}
+
+	for name, opts := range map[string][]StreamableHTTPCOption{
+		"pool last":  {WithHTTPBasicClient(&http.Client{Transport: http.DefaultTransport}), WithHTTPPool(pool)},
+		"pool first": {WithHTTPPool(pool), WithHTTPBasicClient(&http.Client{Transport: http.DefaultTransport})},
+	} {
+		_, err := NewStreamableHTTP("http://example.com", opts...)
+		assert.ErrorContains(t, err, "WithHTTPPool", name)
+	}
+}
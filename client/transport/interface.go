@@ -55,6 +55,18 @@ type HTTPConnection interface {
 	SetProtocolVersion(version string)
 }
 
+// EncodingConnection is a Transport that can apply a wire encoding
+// negotiated at initialize time (see mcp.ExperimentalEncodingKey).
+type EncodingConnection interface {
+	Interface
+	// SupportedEncodings returns the compression schemes and codecs the
+	// transport can apply, most preferred first.
+	SupportedEncodings() mcp.EncodingOffer
+	// SetEncoding applies the encoding chosen by the server to subsequent
+	// messages.
+	SetEncoding(encoding mcp.EncodingSelection)
+}
+
 type JSONRPCRequest struct {
 	JSONRPC string        `json:"jsonrpc"`
 	ID      mcp.RequestId `json:"id"`
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -49,8 +50,17 @@ type OAuthConfig struct {
 	// HTTPClient is an optional HTTP client to use for requests.
 	// If nil, a default HTTP client with a 30 second timeout will be used.
 	HTTPClient *http.Client
+	// GrantType selects how tokens are obtained. The default, empty value
+	// uses the interactive authorization code flow. GrantTypeClientCredentials
+	// obtains tokens with ClientID and ClientSecret alone, for services that
+	// call MCP servers without a user.
+	GrantType string
 }
 
+// GrantTypeClientCredentials is the OAuthConfig.GrantType for the OAuth 2.0
+// client credentials grant (RFC 6749 §4.4).
+const GrantTypeClientCredentials = "client_credentials"
+
 // TokenStore is an interface for storing and retrieving OAuth tokens.
 //
 // Implementations must:
@@ -166,6 +176,7 @@ type AuthServerMetadata struct {
 	IntrospectionEndpointAuthMethodsSupported          []string `json:"introspection_endpoint_auth_methods_supported,omitempty"`
 	IntrospectionEndpointAuthSigningAlgValuesSupported []string `json:"introspection_endpoint_auth_signing_alg_values_supported,omitempty"`
 	CodeChallengeMethodsSupported                      []string `json:"code_challenge_methods_supported,omitempty"`
+	DeviceAuthorizationEndpoint                        string   `json:"device_authorization_endpoint,omitempty"`
 }
 
 // OAuthHandler handles OAuth authentication for HTTP requests
@@ -193,6 +204,13 @@ type OAuthHandler struct {
 
 	mu            sync.RWMutex // Protects expectedState
 	expectedState string       // Expected state value for CSRF protection
+
+	// clientCredentialsMu serializes client credentials token requests so
+	// concurrent callers share one token. tokenRejected is set when the
+	// resource server answers 401, so the stored token is replaced even
+	// though it has not expired.
+	clientCredentialsMu sync.Mutex
+	tokenRejected       atomic.Bool
 }
 
 // NewOAuthHandler creates a new OAuth handler
@@ -233,9 +251,16 @@ func (h *OAuthHandler) getValidToken(ctx context.Context) (*Token, error) {
 	if err != nil && !errors.Is(err, ErrNoToken) {
 		return nil, err
 	}
-	if err == nil && !token.IsExpired() && token.AccessToken != "" {
+	if err == nil && !token.IsExpired() && token.AccessToken != "" && !h.tokenRejected.Load() {
 		return token, nil
 	}
+	if err != nil {
+		token = nil
+	}
+
+	if h.config.GrantType == GrantTypeClientCredentials {
+		return h.clientCredentialsToken(ctx, token)
+	}
 
 	// If we have a refresh token, try to use it
 	if err == nil && token.RefreshToken != "" {
@@ -389,11 +414,17 @@ func (h *OAuthHandler) SetProtectedResourceMetadataURL(u string) {
 // misconfigured resource from redirecting clients to an attacker's
 // metadata endpoint.
 //
+// With GrantTypeClientCredentials, the current token is also marked as
+// rejected, so the next request obtains a new one.
+//
 // It is safe to call with a nil response.
 func (h *OAuthHandler) HandleUnauthorizedResponse(resp *http.Response) {
 	if resp == nil {
 		return
 	}
+	if h.config.GrantType == GrantTypeClientCredentials {
+		h.tokenRejected.Store(true)
+	}
 	for _, header := range resp.Header.Values("WWW-Authenticate") {
 		for _, candidate := range extractResourceMetadataURLs(header) {
 			if err := h.validateAdvertisedPRMURL(candidate); err != nil {
@@ -878,6 +909,7 @@ func validateAuthServerMetadataURLs(m *AuthServerMetadata) error {
 		{"op_tos_uri", m.OpTOSURI},
 		{"revocation_endpoint", m.RevocationEndpoint},
 		{"introspection_endpoint", m.IntrospectionEndpoint},
+		{"device_authorization_endpoint", m.DeviceAuthorizationEndpoint},
 	}
 	for _, f := range fields {
 		if f.value == "" {
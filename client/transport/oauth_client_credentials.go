@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clientCredentialsToken returns a token obtained with the client
+// credentials grant, requesting a new one when current is missing, expired
+// or was rejected by the resource server. A refresh token, which servers
+// rarely issue for this grant, is tried first.
+func (h *OAuthHandler) clientCredentialsToken(ctx context.Context, current *Token) (*Token, error) {
+	h.clientCredentialsMu.Lock()
+	defer h.clientCredentialsMu.Unlock()
+
+	// Another caller may have replaced the token while we waited.
+	if token, err := h.config.TokenStore.GetToken(ctx); err == nil &&
+		!token.IsExpired() && token.AccessToken != "" && !h.tokenRejected.Load() {
+		return token, nil
+	}
+
+	if current != nil && current.RefreshToken != "" && !h.tokenRejected.Load() {
+		if token, err := h.refreshToken(ctx, current.RefreshToken); err == nil {
+			return token, nil
+		}
+	}
+	return h.requestClientCredentialsToken(ctx)
+}
+
+// requestClientCredentialsToken makes a client credentials token request
+// (RFC 6749 §4.4.2) and saves the token.
+func (h *OAuthHandler) requestClientCredentialsToken(ctx context.Context) (*Token, error) {
+	if h.config.ClientID == "" || h.config.ClientSecret == "" {
+		return nil, errors.New("client credentials grant requires ClientID and ClientSecret")
+	}
+	metadata, err := h.getServerMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server metadata: %w", err)
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", GrantTypeClientCredentials)
+	data.Set("client_id", h.config.ClientID)
+	data.Set("client_secret", h.config.ClientSecret)
+	if len(h.config.Scopes) > 0 {
+		data.Set("scope", strings.Join(h.config.Scopes, " "))
+	}
+	// RFC 8707: Include resource parameter in the token request
+	if resourceURL := h.getResourceURL(); resourceURL != "" {
+		data.Set("resource", resourceURL)
+	}
+
+	body, err := h.postForm(ctx, metadata.TokenEndpoint, data, "client credentials token request failed")
+	if err != nil {
+		return nil, err
+	}
+	var tokenResp Token
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, errors.New("client credentials token response has no access_token")
+	}
+	if tokenResp.ExpiresIn > 0 {
+		tokenResp.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	if err := h.config.TokenStore.SaveToken(ctx, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+	h.tokenRejected.Store(false)
+	return &tokenResp, nil
+}
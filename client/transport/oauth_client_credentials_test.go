@@ -0,0 +1,82 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthHandler_ClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/oauth-authorization-server":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"authorization_endpoint": serverURL + "/authorize",
+				"token_endpoint":         serverURL + "/token",
+			})
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, GrantTypeClientCredentials, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "backend", r.PostForm.Get("client_id"))
+			if r.PostForm.Get("client_secret") != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": "invalid_client"})
+				return
+			}
+			n := issued.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": fmt.Sprintf("token-%d", n),
+				"token_type":   "bearer",
+				"expires_in":   3600,
+			})
+		}
+	}))
+	serverURL = server.URL
+	defer server.Close()
+
+	newHandler := func(secret string) *OAuthHandler {
+		return NewOAuthHandler(OAuthConfig{
+			ClientID:              "backend",
+			ClientSecret:          secret,
+			GrantType:             GrantTypeClientCredentials,
+			Scopes:                []string{"mcp.read"},
+			AuthServerMetadataURL: server.URL + "/.well-known/oauth-authorization-server",
+		})
+	}
+
+	handler := newHandler("s3cret")
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			header, err := handler.GetAuthorizationHeader(t.Context())
+			assert.NoError(t, err)
+			assert.Equal(t, "Bearer token-1", header)
+		})
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, issued.Load(), "concurrent callers share one token")
+
+	// A 401 from the resource server replaces the token before it expires.
+	handler.HandleUnauthorizedResponse(&http.Response{Header: http.Header{}})
+	header, err := handler.GetAuthorizationHeader(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", header)
+	header, err = handler.GetAuthorizationHeader(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", header)
+
+	_, err = newHandler("wrong").GetAuthorizationHeader(t.Context())
+	var oauthErr OAuthError
+	require.ErrorAs(t, err, &oauthErr)
+	assert.Equal(t, "invalid_client", oauthErr.ErrorCode)
+}
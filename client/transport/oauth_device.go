@@ -0,0 +1,214 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// deviceCodeGrantType is the grant_type of the device access token request
+// (RFC 8628 §3.4).
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// defaultDevicePollInterval is the polling interval in seconds RFC 8628 §3.2
+// prescribes when the authorization server does not return one.
+const defaultDevicePollInterval = 5
+
+// devicePollUnit is the unit of device flow polling intervals. Tests shorten
+// it.
+var devicePollUnit = time.Second
+
+// ErrDeviceFlowUnsupported is returned by StartDeviceFlow when the
+// authorization server metadata has no device_authorization_endpoint.
+var ErrDeviceFlowUnsupported = errors.New("authorization server does not support the device authorization grant")
+
+// DeviceAuthorization is the response to a device authorization request
+// (RFC 8628 §3.2). Show the user VerificationURI and UserCode (or
+// VerificationURIComplete, e.g. as a QR code), then call Poll to wait for
+// them to approve the request on another device.
+type DeviceAuthorization struct {
+	// DeviceCode is the code the client exchanges for a token.
+	DeviceCode string `json:"device_code"`
+	// UserCode is the code the user enters at VerificationURI.
+	UserCode string `json:"user_code"`
+	// VerificationURI is where the user approves the request.
+	VerificationURI string `json:"verification_uri"`
+	// VerificationURIComplete is VerificationURI with the user code filled
+	// in, if the server provides it.
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	// ExpiresIn is the number of seconds the codes are valid for.
+	ExpiresIn int64 `json:"expires_in"`
+	// Interval is the minimum number of seconds between polls.
+	Interval int64 `json:"interval,omitempty"`
+	// ExpiresAt is when the codes expire.
+	ExpiresAt time.Time `json:"-"`
+
+	handler *OAuthHandler
+}
+
+// StartDeviceFlow starts the OAuth 2.0 device authorization grant (RFC 8628)
+// for environments that cannot receive a redirect, such as SSH sessions and
+// containers. It requests a device code and a user code from the
+// authorization server's device_authorization_endpoint; the returned
+// DeviceAuthorization completes the flow with Poll.
+//
+// Returns ErrDeviceFlowUnsupported if the server does not advertise the
+// endpoint.
+func (h *OAuthHandler) StartDeviceFlow(ctx context.Context) (*DeviceAuthorization, error) {
+	metadata, err := h.getServerMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server metadata: %w", err)
+	}
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return nil, ErrDeviceFlowUnsupported
+	}
+
+	data := url.Values{}
+	data.Set("client_id", h.config.ClientID)
+	if h.config.ClientSecret != "" {
+		data.Set("client_secret", h.config.ClientSecret)
+	}
+	if len(h.config.Scopes) > 0 {
+		data.Set("scope", strings.Join(h.config.Scopes, " "))
+	}
+	// RFC 8707: Include resource parameter in the device authorization request
+	if resourceURL := h.getResourceURL(); resourceURL != "" {
+		data.Set("resource", resourceURL)
+	}
+
+	body, err := h.postForm(ctx, metadata.DeviceAuthorizationEndpoint, data, "device authorization request failed")
+	if err != nil {
+		return nil, err
+	}
+	var auth DeviceAuthorization
+	if err := json.Unmarshal(body, &auth); err != nil {
+		return nil, fmt.Errorf("failed to decode device authorization response: %w", err)
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.VerificationURI == "" {
+		return nil, errors.New("device authorization response is missing device_code, user_code or verification_uri")
+	}
+	if auth.ExpiresIn > 0 {
+		auth.ExpiresAt = time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+	auth.handler = h
+	return &auth, nil
+}
+
+// Poll waits for the user to approve the device authorization, polling the
+// token endpoint at the interval requested by the server, and saves the
+// token to the handler's TokenStore. It returns an OAuthError with code
+// access_denied if the user declines, or expired_token if the codes expire
+// first, and ctx.Err() if ctx is done.
+func (d *DeviceAuthorization) Poll(ctx context.Context) (*Token, error) {
+	h := d.handler
+	if h == nil {
+		return nil, errors.New("device authorization was not started with StartDeviceFlow")
+	}
+	interval := defaultDevicePollInterval * devicePollUnit
+	if d.Interval > 0 {
+		interval = time.Duration(d.Interval) * devicePollUnit
+	}
+
+	for {
+		if !d.ExpiresAt.IsZero() && time.Now().After(d.ExpiresAt) {
+			return nil, fmt.Errorf("device authorization failed: %w", OAuthError{ErrorCode: "expired_token"})
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		token, err := d.exchange(ctx)
+		var oauthErr OAuthError
+		switch {
+		case err == nil:
+			return token, nil
+		case !errors.As(err, &oauthErr):
+			return nil, err
+		case oauthErr.ErrorCode == "authorization_pending":
+		case oauthErr.ErrorCode == "slow_down":
+			// RFC 8628 §3.5: increase the interval by 5 seconds for this and
+			// all subsequent requests.
+			interval += 5 * devicePollUnit
+		default:
+			return nil, err
+		}
+	}
+}
+
+// exchange makes one device access token request.
+func (d *DeviceAuthorization) exchange(ctx context.Context) (*Token, error) {
+	h := d.handler
+	metadata, err := h.getServerMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server metadata: %w", err)
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", deviceCodeGrantType)
+	data.Set("device_code", d.DeviceCode)
+	data.Set("client_id", h.config.ClientID)
+	if h.config.ClientSecret != "" {
+		data.Set("client_secret", h.config.ClientSecret)
+	}
+	if resourceURL := h.getResourceURL(); resourceURL != "" {
+		data.Set("resource", resourceURL)
+	}
+
+	body, err := h.postForm(ctx, metadata.TokenEndpoint, data, "device access token request failed")
+	if err != nil {
+		return nil, err
+	}
+	var tokenResp Token
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.ExpiresIn > 0 {
+		tokenResp.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	if err := h.config.TokenStore.SaveToken(ctx, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+	return &tokenResp, nil
+}
+
+// postForm posts form-encoded data to an authorization server endpoint and
+// returns the response body. Error responses, including errors returned with
+// a 2xx status as GitHub does, are returned as errors wrapping OAuthError
+// when the body has one.
+func (h *OAuthHandler) postForm(ctx context.Context, endpoint string, data url.Values, errContext string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, extractOAuthError(body, resp.StatusCode, errContext)
+	}
+	var oauthErr OAuthError
+	if err := json.Unmarshal(body, &oauthErr); err == nil && oauthErr.ErrorCode != "" {
+		return nil, fmt.Errorf("%s: %w", errContext, oauthErr)
+	}
+	return body, nil
+}
@@ -0,0 +1,121 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeviceFlowServer(t *testing.T, tokenResponses ...map[string]any) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var polls atomic.Int32
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/oauth-authorization-server":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"authorization_endpoint":        serverURL + "/authorize",
+				"token_endpoint":                serverURL + "/token",
+				"device_authorization_endpoint": serverURL + "/device",
+			})
+		case "/device":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "cli", r.PostForm.Get("client_id"))
+			assert.Equal(t, "mcp.read", r.PostForm.Get("scope"))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"device_code":               "device-123",
+				"user_code":                 "WDJB-MJHT",
+				"verification_uri":          serverURL + "/activate",
+				"verification_uri_complete": serverURL + "/activate?user_code=WDJB-MJHT",
+				"expires_in":                600,
+				"interval":                  1,
+			})
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, deviceCodeGrantType, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "device-123", r.PostForm.Get("device_code"))
+			n := int(polls.Add(1)) - 1
+			response := tokenResponses[min(n, len(tokenResponses)-1)]
+			if _, isErr := response["error"]; isErr {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		}
+	}))
+	serverURL = server.URL
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func TestOAuthHandler_DeviceFlow(t *testing.T) {
+	unit := devicePollUnit
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = unit })
+
+	t.Run("approved", func(t *testing.T) {
+		server, polls := newDeviceFlowServer(t,
+			map[string]any{"error": "authorization_pending"},
+			map[string]any{"error": "slow_down"},
+			map[string]any{"access_token": "device-token", "token_type": "Bearer", "expires_in": 3600},
+		)
+		store := NewMemoryTokenStore()
+		handler := NewOAuthHandler(OAuthConfig{
+			ClientID:              "cli",
+			Scopes:                []string{"mcp.read"},
+			TokenStore:            store,
+			AuthServerMetadataURL: server.URL + "/.well-known/oauth-authorization-server",
+		})
+
+		auth, err := handler.StartDeviceFlow(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "WDJB-MJHT", auth.UserCode)
+		assert.Equal(t, server.URL+"/activate", auth.VerificationURI)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), auth.ExpiresAt, time.Minute)
+
+		token, err := auth.Poll(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "device-token", token.AccessToken)
+		assert.EqualValues(t, 3, polls.Load())
+
+		header, err := handler.GetAuthorizationHeader(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer device-token", header)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		server, _ := newDeviceFlowServer(t, map[string]any{"error": "access_denied"})
+		handler := NewOAuthHandler(OAuthConfig{
+			ClientID:              "cli",
+			Scopes:                []string{"mcp.read"},
+			AuthServerMetadataURL: server.URL + "/.well-known/oauth-authorization-server",
+		})
+		auth, err := handler.StartDeviceFlow(t.Context())
+		require.NoError(t, err)
+
+		_, err = auth.Poll(t.Context())
+		var oauthErr OAuthError
+		require.True(t, errors.As(err, &oauthErr), "got %v", err)
+		assert.Equal(t, "access_denied", oauthErr.ErrorCode)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"authorization_endpoint": "https://auth.example.com/authorize",
+				"token_endpoint":         "https://auth.example.com/token",
+			})
+		}))
+		defer server.Close()
+		handler := NewOAuthHandler(OAuthConfig{ClientID: "cli", AuthServerMetadataURL: server.URL})
+		_, err := handler.StartDeviceFlow(t.Context())
+		assert.ErrorIs(t, err, ErrDeviceFlowUnsupported)
+	})
+}
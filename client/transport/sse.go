@@ -47,6 +47,11 @@ type SSE struct {
 	endpointTimeout time.Duration
 	responseTimeout time.Duration
 
+	// readLimit and writeLimit cap the bytes per second exchanged with the
+	// server. See WithSSEBandwidthLimit.
+	readLimit  int
+	writeLimit int
+
 	// OAuth support
 	oauthHandler *OAuthHandler
 }
@@ -147,6 +152,7 @@ func NewSSE(baseURL string, options ...ClientOption) (*SSE, error) {
 	for _, opt := range options {
 		opt(smc)
 	}
+	smc.httpClient = throttledHTTPClient(smc.httpClient, smc.readLimit, smc.writeLimit)
 
 	// If OAuth is configured, set the base URL for metadata discovery
 	if smc.oauthHandler != nil {
@@ -1,7 +1,6 @@
 package transport
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -16,6 +15,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/mark3labs/mcp-go/internal/bandwidth"
+	"github.com/mark3labs/mcp-go/internal/framing"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -41,7 +42,7 @@ type Stdio struct {
 	cmdFunc          CommandFunc
 	stdin            io.WriteCloser
 	stdinMu          sync.Mutex
-	stdout           *bufio.Reader
+	stdout           *framing.Reader
 	stderr           io.ReadCloser
 	responses        map[string]chan *JSONRPCResponse
 	mu               sync.RWMutex
@@ -57,6 +58,9 @@ type Stdio struct {
 	logger           *slog.Logger
 	started          bool
 	startedMu        sync.Mutex
+	readLimit        int
+	writeLimit       int
+	framing          Framing
 }
 
 const (
@@ -103,13 +107,44 @@ func WithCommandLogger(logger *slog.Logger) StdioOption {
 	}
 }
 
+// Framing selects how the JSON-RPC messages exchanged with the server are
+// delimited on its standard streams.
+type Framing int
+
+const (
+	// FramingNewline ends every message with a newline, as the MCP stdio
+	// transport specifies. It is the default.
+	FramingNewline Framing = iota
+	// FramingContentLength precedes every message with LSP-style headers:
+	// a Content-Length header giving the size of the message in bytes,
+	// followed by an empty line. Messages may then span several lines,
+	// which some hosts emit for large payloads.
+	FramingContentLength
+)
+
+// mode returns the framing package mode of f.
+func (f Framing) mode() framing.Mode {
+	if f == FramingContentLength {
+		return framing.ContentLength
+	}
+	return framing.Newline
+}
+
+// WithStdioFraming sets how messages are delimited on the server's standard
+// streams. The server must use the same framing; mcp-go servers detect it
+// when started with server.WithFraming(server.FramingAuto).
+func WithStdioFraming(f Framing) StdioOption {
+	return func(s *Stdio) {
+		s.framing = f
+	}
+}
+
 // NewIO returns a new stdio-based transport using existing input, output, and
 // logging streams instead of spawning a subprocess.
 // This is useful for testing and simulating client behavior.
-func NewIO(input io.Reader, output io.WriteCloser, logging io.ReadCloser) *Stdio {
-	return &Stdio{
+func NewIO(input io.Reader, output io.WriteCloser, logging io.ReadCloser, opts ...StdioOption) *Stdio {
+	s := &Stdio{
 		stdin:  output,
-		stdout: bufio.NewReader(input),
 		stderr: logging,
 
 		responses: make(map[string]chan *JSONRPCResponse),
@@ -117,6 +152,11 @@ func NewIO(input io.Reader, output io.WriteCloser, logging io.ReadCloser) *Stdio
 		ctx:       context.Background(),
 		logger:    slog.Default(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.stdout = framing.NewReader(input, s.framing.mode())
+	return s
 }
 
 // NewStdio creates a new stdio transport to communicate with a subprocess.
@@ -225,9 +265,9 @@ func (c *Stdio) spawnCommand(ctx context.Context) error {
 	}
 
 	c.cmd = cmd
-	c.stdin = stdin
+	c.stdin = bandwidth.NewWriteCloser(ctx, stdin, bandwidth.NewLimiter(c.writeLimit))
 	c.stderr = stderr
-	c.stdout = bufio.NewReader(stdout)
+	c.stdout = framing.NewReader(bandwidth.NewReader(ctx, stdout, bandwidth.NewLimiter(c.readLimit)), c.framing.mode())
 
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start command: %w", err)
@@ -348,7 +388,7 @@ func (c *Stdio) readResponses(ready chan struct{}) {
 		case <-c.done:
 			return
 		default:
-			line, err := c.stdout.ReadString('\n')
+			message, err := c.stdout.ReadMessage()
 			if err != nil {
 				if err != io.EOF && !errors.Is(err, context.Canceled) && !errors.Is(err, fs.ErrClosed) {
 					c.logger.Error("Error reading from stdout", "err", err)
@@ -359,7 +399,7 @@ func (c *Stdio) readResponses(ready chan struct{}) {
 				return
 			}
 
-			line = strings.TrimRight(line, "\r\n")
+			line := strings.TrimRight(string(message), "\r\n")
 			// First try to parse as a generic message to check for ID field
 			var baseMessage struct {
 				JSONRPC string         `json:"jsonrpc"`
@@ -458,13 +498,8 @@ func (c *Stdio) SendRequest(
 		c.mu.Unlock()
 	}
 
-	// Send request. stdinMu serializes frame writes so concurrent
-	// SendRequest/SendNotification/sendResponse calls cannot interleave
-	// JSON-RPC lines on the subprocess's stdin.
-	c.stdinMu.Lock()
-	_, err = c.stdin.Write(requestBytes)
-	c.stdinMu.Unlock()
-	if err != nil {
+	// Send request
+	if err := c.writeMessage(requestBytes); err != nil {
 		deleteResponseChan()
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
@@ -511,10 +546,7 @@ func (c *Stdio) SendNotification(
 	}
 	notificationBytes = append(notificationBytes, '\n')
 
-	c.stdinMu.Lock()
-	_, err = c.stdin.Write(notificationBytes)
-	c.stdinMu.Unlock()
-	if err != nil {
+	if err := c.writeMessage(notificationBytes); err != nil {
 		return fmt.Errorf("failed to write notification: %w", err)
 	}
 
@@ -577,14 +609,22 @@ func (c *Stdio) sendResponse(response JSONRPCResponse) {
 	}
 	responseBytes = append(responseBytes, '\n')
 
-	c.stdinMu.Lock()
-	_, err = c.stdin.Write(responseBytes)
-	c.stdinMu.Unlock()
-	if err != nil {
+	if err := c.writeMessage(responseBytes); err != nil {
 		c.logger.Error("Error writing response", "err", err)
 	}
 }
 
+// writeMessage writes a newline-terminated JSON-RPC message to the
+// subprocess's stdin in the configured framing. stdinMu serializes frame
+// writes so concurrent SendRequest/SendNotification/sendResponse calls
+// cannot interleave JSON-RPC messages on the subprocess's stdin.
+func (c *Stdio) writeMessage(message []byte) error {
+	c.stdinMu.Lock()
+	defer c.stdinMu.Unlock()
+	_, err := framing.NewWriter(c.stdin, c.framing.mode()).Write(message)
+	return err
+}
+
 // Stderr returns a reader for the stderr output of the subprocess.
 // This can be used to capture error messages or logs from the subprocess.
 func (c *Stdio) Stderr() io.Reader {
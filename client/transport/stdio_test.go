@@ -1,7 +1,6 @@
 package transport
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -18,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mark3labs/mcp-go/internal/framing"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/require"
 )
@@ -560,7 +560,7 @@ func TestStdio_ReadResponses_SuppressesClosedPipeShutdownNoise(t *testing.T) {
 	logChan := make(chan string, 1)
 	stdio := NewIO(io.NopCloser(strings.NewReader("")), nopWriteCloser{Writer: io.Discard}, io.NopCloser(strings.NewReader("")))
 	stdio.logger = newTestLogger(logChan)
-	stdio.stdout = bufio.NewReader(errReader{err: &fs.PathError{Op: "read", Path: "|0", Err: fs.ErrClosed}})
+	stdio.stdout = framing.NewReader(errReader{err: &fs.PathError{Op: "read", Path: "|0", Err: fs.ErrClosed}}, framing.Newline)
 
 	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
 	t.Cleanup(cancel)
@@ -3,7 +3,9 @@ package transport
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -128,6 +130,7 @@ type StreamableHTTP struct {
 
 	sessionID       atomic.Value // string
 	protocolVersion atomic.Value // string
+	encoding        atomic.Value // mcp.EncodingSelection
 
 	initialized     chan struct{}
 	initializedOnce sync.Once
@@ -142,6 +145,20 @@ type StreamableHTTP struct {
 	closed    chan struct{}
 	closeOnce sync.Once
 
+	// readLimit and writeLimit cap the bytes per second exchanged with the
+	// server. See WithHTTPBandwidthLimit.
+	readLimit  int
+	writeLimit int
+
+	// tlsConfig and clientCertificates configure the TLS connections to the
+	// server. See WithHTTPTLSConfig and WithHTTPClientCertificate.
+	tlsConfig          *tls.Config
+	clientCertificates []tls.Certificate
+
+	// httpPool is the connection pool the requests go through, if any. See
+	// WithHTTPPool.
+	httpPool *HTTPPool
+
 	// OAuth support
 	oauthHandler *OAuthHandler
 }
@@ -169,6 +186,17 @@ func NewStreamableHTTP(serverURL string, options ...StreamableHTTPCOption) (*Str
 			opt(smc)
 		}
 	}
+	if smc.httpPool != nil {
+		if smc.httpClient, err = pooledHTTPClient(smc.httpClient, smc.httpPool); err != nil {
+			return nil, err
+		}
+	}
+	if smc.tlsConfig != nil || len(smc.clientCertificates) > 0 {
+		if smc.httpClient, err = tlsHTTPClient(smc.httpClient, smc.tlsConfig, smc.clientCertificates); err != nil {
+			return nil, err
+		}
+	}
+	smc.httpClient = throttledHTTPClient(smc.httpClient, smc.readLimit, smc.writeLimit)
 
 	// If OAuth is configured, set the base URL for metadata discovery
 	if smc.oauthHandler != nil {
@@ -260,6 +288,20 @@ func (c *StreamableHTTP) SetProtocolVersion(version string) {
 	c.protocolVersion.Store(version)
 }
 
+// SupportedEncodings implements EncodingConnection. Request bodies can be
+// gzip-compressed; responses are decompressed by net/http.
+func (c *StreamableHTTP) SupportedEncodings() mcp.EncodingOffer {
+	return mcp.EncodingOffer{
+		Compression: []string{mcp.CompressionGzip, mcp.CompressionIdentity},
+		Codecs:      []string{mcp.CodecJSON},
+	}
+}
+
+// SetEncoding implements EncodingConnection.
+func (c *StreamableHTTP) SetEncoding(encoding mcp.EncodingSelection) {
+	c.encoding.Store(encoding)
+}
+
 // ErrOAuthAuthorizationRequired is a sentinel error for OAuth authorization required
 var ErrOAuthAuthorizationRequired = errors.New("no valid token available, authorization required")
 
@@ -628,6 +670,14 @@ func (c *StreamableHTTP) sendHTTP(
 	acceptType string,
 	header http.Header,
 ) (resp *http.Response, err error) {
+	encoding, _ := c.encoding.Load().(mcp.EncodingSelection)
+	compress := body != nil && encoding.Compression == mcp.CompressionGzip
+	if compress {
+		if body, err = gzipBody(body); err != nil {
+			return nil, fmt.Errorf("failed to compress request: %w", err)
+		}
+	}
+
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, method, c.serverURL.String(), body)
 	if err != nil {
@@ -641,6 +691,9 @@ func (c *StreamableHTTP) sendHTTP(
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
+	if compress {
+		req.Header.Set("Content-Encoding", mcp.CompressionGzip)
+	}
 	req.Header.Set("Accept", acceptType)
 	sessionID := c.sessionID.Load().(string)
 	if sessionID != "" {
@@ -1151,3 +1204,16 @@ func (c *StreamableHTTP) contextAwareOfClientClose(ctx context.Context) (context
 	}()
 	return newCtx, cancel
 }
+
+// gzipBody returns a gzip-compressed copy of body.
+func gzipBody(body io.Reader) (io.Reader, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := io.Copy(gz, body); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// WithHTTPTLSConfig sets the TLS configuration of the connections to the
+// server, for example RootCAs to trust a private certificate authority. It
+// applies to the client set with WithHTTPBasicClient as well, without
+// affecting its other users, as long as its Transport is an *http.Transport
+// or nil. Configure a shared HTTPPool with WithPoolTLSConfig instead.
+func WithHTTPTLSConfig(config *tls.Config) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.tlsConfig = config
+	}
+}
+
+// WithHTTPClientCertificate presents cert to servers that require mutual
+// TLS, such as a StreamableHTTPServer configured with server.WithClientCAs.
+// It is added to the certificates of WithHTTPTLSConfig and can be given
+// several times. Load the certificate with tls.LoadX509KeyPair.
+func WithHTTPClientCertificate(cert tls.Certificate) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.clientCertificates = append(sc.clientCertificates, cert)
+	}
+}
+
+// tlsHTTPClient returns a copy of client whose transport connects with
+// config and presents certs.
+func tlsHTTPClient(client *http.Client, config *tls.Config, certs []tls.Certificate) (*http.Client, error) {
+	var base *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		base = http.DefaultTransport.(*http.Transport)
+	case *http.Transport:
+		base = t
+	case *HTTPPool:
+		return nil, fmt.Errorf("TLS options cannot change a shared HTTPPool; use WithPoolTLSConfig")
+	default:
+		return nil, fmt.Errorf("TLS options require an *http.Transport, got %T", t)
+	}
+
+	if config != nil {
+		config = config.Clone()
+	} else if base.TLSClientConfig != nil {
+		config = base.TLSClientConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	config.Certificates = append(config.Certificates, certs...)
+
+	transport := base.Clone()
+	transport.TLSClientConfig = config
+	tlsClient := *client
+	tlsClient.Transport = transport
+	return &tlsClient, nil
+}
@@ -0,0 +1,223 @@
+package transport
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// tokenFileVersion prefixes every token file so the format can change
+// without misreading older files.
+const tokenFileVersion byte = 1
+
+// tokenKeyService is the keychain service under which FileTokenStore keeps
+// generated encryption keys, one per token file.
+const tokenKeyService = "mcp-go token encryption key"
+
+// FileTokenStore is a token store that persists the token to a file, so a
+// CLI client can reuse it across runs. The file is encrypted with AES-256-GCM
+// and written with mode 0600.
+//
+// The encryption key is never stored on disk by the store. By default it is
+// generated on first use and kept in the OS keychain (see
+// KeychainTokenStore), under the absolute path of the token file; where no
+// keychain is available, the store fails with an error wrapping
+// ErrKeychainUnavailable, and the key must be supplied with
+// WithTokenEncryptionKey, for example from an environment variable.
+type FileTokenStore struct {
+	path string
+	key  []byte
+	mu   sync.Mutex
+
+	// keychainGet and keychainSet access the keychain holding the
+	// generated key.
+	keychainGet func(ctx context.Context, service, account string) (string, error)
+	keychainSet func(ctx context.Context, service, account, secret string) error
+}
+
+// FileTokenStoreOption configures a FileTokenStore.
+type FileTokenStoreOption func(*FileTokenStore)
+
+// WithTokenEncryptionKey sets the 32-byte AES-256 key used to encrypt the
+// token file instead of a key generated and kept in the OS keychain.
+func WithTokenEncryptionKey(key []byte) FileTokenStoreOption {
+	return func(s *FileTokenStore) {
+		s.key = key
+	}
+}
+
+// NewFileTokenStore creates a token store backed by the file at path. The
+// file and its directory are created on the first SaveToken.
+func NewFileTokenStore(path string, opts ...FileTokenStoreOption) *FileTokenStore {
+	s := &FileTokenStore{path: path, keychainGet: keychainGet, keychainSet: keychainSet}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetToken returns the token stored in the file.
+// Returns ErrNoToken if the file does not exist.
+// Returns context.Canceled or context.DeadlineExceeded if ctx is cancelled.
+func (s *FileTokenStore) GetToken(ctx context.Context) (*Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	key, err := s.encryptionKey(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decryptToken(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token file %s: %w", s.path, err)
+	}
+	var token Token
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token file %s: %w", s.path, err)
+	}
+	return &token, nil
+}
+
+// SaveToken encrypts the token and replaces the file atomically.
+// Returns context.Canceled or context.DeadlineExceeded if ctx is cancelled.
+func (s *FileTokenStore) SaveToken(ctx context.Context, token *Token) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	key, err := s.encryptionKey(ctx, true)
+	if err != nil {
+		return err
+	}
+	data, err := encryptToken(key, plaintext)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// encryptionKey returns the configured key, or the key kept in the
+// keychain for the token file. When create is set, a missing key is
+// generated and stored in the keychain.
+func (s *FileTokenStore) encryptionKey(ctx context.Context, create bool) ([]byte, error) {
+	if s.key != nil {
+		if len(s.key) != 32 {
+			return nil, fmt.Errorf("token encryption key must be 32 bytes, got %d", len(s.key))
+		}
+		return s.key, nil
+	}
+	account, err := filepath.Abs(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token file path: %w", err)
+	}
+	secret, err := s.keychainGet(ctx, tokenKeyService, account)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("token encryption key in keychain for %s is not a 32-byte key", account)
+		}
+		return key, nil
+	}
+	if errors.Is(err, ErrKeychainUnavailable) {
+		return nil, fmt.Errorf("no place to keep the token encryption key, set one with WithTokenEncryptionKey: %w", err)
+	}
+	if !errors.Is(err, errKeychainNotFound) || !create {
+		return nil, fmt.Errorf("failed to read token encryption key from keychain: %w", err)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate token key: %w", err)
+	}
+	if err := s.keychainSet(ctx, tokenKeyService, account, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("failed to save token encryption key to keychain: %w", err)
+	}
+	return key, nil
+}
+
+func encryptToken(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newTokenCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	data := append([]byte{tokenFileVersion}, nonce...)
+	return gcm.Seal(data, nonce, plaintext, []byte{tokenFileVersion}), nil
+}
+
+func decryptToken(key, data []byte) ([]byte, error) {
+	gcm, err := newTokenCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 1+gcm.NonceSize() || data[0] != tokenFileVersion {
+		return nil, errors.New("unrecognized token file format")
+	}
+	nonce, ciphertext := data[1:1+gcm.NonceSize()], data[1+gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, data[:1])
+}
+
+func newTokenCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic writes data to a temporary file next to path with mode
+// 0600 and renames it over path, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
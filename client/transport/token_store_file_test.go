@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeychain replaces the OS keychain of file token stores.
+type fakeKeychain struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func (k *fakeKeychain) use(s *FileTokenStore) *FileTokenStore {
+	s.keychainGet = func(_ context.Context, service, account string) (string, error) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		secret, ok := k.secrets[service+"\x00"+account]
+		if !ok {
+			return "", errKeychainNotFound
+		}
+		return secret, nil
+	}
+	s.keychainSet = func(_ context.Context, service, account, secret string) error {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		k.secrets[service+"\x00"+account] = secret
+		return nil
+	}
+	return s
+}
+
+func TestFileTokenStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	path := filepath.Join(dir, "server.json")
+	token := &Token{
+		AccessToken:  "secret-access-token",
+		TokenType:    "Bearer",
+		RefreshToken: "secret-refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).Round(0),
+	}
+	keychain := &fakeKeychain{secrets: map[string]string{}}
+
+	store := keychain.use(NewFileTokenStore(path))
+	_, err := store.GetToken(t.Context())
+	require.ErrorIs(t, err, ErrNoToken)
+
+	require.NoError(t, store.SaveToken(t.Context(), token))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(data, []byte("secret")), "token file is not encrypted")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the key is not written next to the token")
+	assert.Len(t, keychain.secrets, 1, "the key is kept in the keychain")
+
+	// A new store, as in the next run of a CLI, reads the same token.
+	got, err := keychain.use(NewFileTokenStore(path)).GetToken(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, token.AccessToken, got.AccessToken)
+	assert.Equal(t, token.RefreshToken, got.RefreshToken)
+	assert.True(t, token.ExpiresAt.Equal(got.ExpiresAt))
+}
+
+func TestFileTokenStore_EncryptionKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	key := bytes.Repeat([]byte{7}, 32)
+
+	keychain := &fakeKeychain{secrets: map[string]string{}}
+	store := keychain.use(NewFileTokenStore(path, WithTokenEncryptionKey(key)))
+	require.NoError(t, store.SaveToken(t.Context(), &Token{AccessToken: "abc"}))
+	assert.Empty(t, keychain.secrets)
+
+	got, err := NewFileTokenStore(path, WithTokenEncryptionKey(key)).GetToken(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", got.AccessToken)
+
+	_, err = NewFileTokenStore(path, WithTokenEncryptionKey(bytes.Repeat([]byte{8}, 32))).GetToken(t.Context())
+	assert.ErrorContains(t, err, "failed to decrypt")
+
+	err = NewFileTokenStore(path, WithTokenEncryptionKey([]byte("short"))).SaveToken(t.Context(), &Token{})
+	assert.ErrorContains(t, err, "must be 32 bytes")
+}
+
+func TestFileTokenStore_KeychainUnavailable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	store := NewFileTokenStore(path)
+	store.keychainGet = func(context.Context, string, string) (string, error) {
+		return "", ErrKeychainUnavailable
+	}
+
+	err := store.SaveToken(t.Context(), &Token{AccessToken: "abc"})
+	assert.ErrorIs(t, err, ErrKeychainUnavailable)
+	assert.ErrorContains(t, err, "WithTokenEncryptionKey")
+	assert.NoFileExists(t, path)
+}
+
+func TestFileTokenStore_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token"))
+	assert.ErrorIs(t, store.SaveToken(ctx, &Token{}), context.Canceled)
+	_, err := store.GetToken(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrKeychainUnavailable is returned by KeychainTokenStore when the operating
+// system has no supported credential store, or its tooling is not installed.
+var ErrKeychainUnavailable = errors.New("os keychain not available")
+
+// ErrTokenTooLarge is returned by KeychainTokenStore when the encoded token
+// exceeds what the credential store can hold, such as the 2560 bytes of a
+// Windows Credential Manager entry. Tokens carrying large ID tokens may hit
+// this limit; a FileTokenStore holds tokens of any size.
+var ErrTokenTooLarge = errors.New("token too large for os keychain")
+
+// errKeychainNotFound is returned by the platform backends when no secret is
+// stored for the service and account.
+var errKeychainNotFound = errors.New("keychain item not found")
+
+// KeychainTokenStore is a token store backed by the operating system's
+// credential store: the login Keychain on macOS (through the security tool),
+// the Secret Service on Linux and the BSDs (through secret-tool from
+// libsecret), and the Credential Manager on Windows. Other platforms return
+// ErrKeychainUnavailable.
+//
+// The token is stored as a generic password identified by service and
+// account, so several servers or users can share one keychain.
+type KeychainTokenStore struct {
+	service string
+	account string
+}
+
+// NewKeychainTokenStore creates a token store that keeps the token under
+// service and account in the OS keychain, for example
+// NewKeychainTokenStore("my-cli", "https://mcp.example.com").
+func NewKeychainTokenStore(service, account string) *KeychainTokenStore {
+	return &KeychainTokenStore{service: service, account: account}
+}
+
+// GetToken returns the token stored in the keychain.
+// Returns ErrNoToken if no token is stored.
+// Returns context.Canceled or context.DeadlineExceeded if ctx is cancelled.
+func (s *KeychainTokenStore) GetToken(ctx context.Context) (*Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	secret, err := keychainGet(ctx, s.service, s.account)
+	if errors.Is(err, errKeychainNotFound) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token from keychain: %w", err)
+	}
+	// Secrets are stored base64 encoded so every backend sees printable text.
+	data, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token from keychain: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token from keychain: %w", err)
+	}
+	return &token, nil
+}
+
+// SaveToken saves the token to the keychain, replacing any stored one.
+// Returns context.Canceled or context.DeadlineExceeded if ctx is cancelled.
+func (s *KeychainTokenStore) SaveToken(ctx context.Context, token *Token) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+	if err := keychainSet(ctx, s.service, s.account, base64.StdEncoding.EncodeToString(data)); err != nil {
+		return fmt.Errorf("failed to save token to keychain: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityItemNotFound is the exit status of security(1) when no matching
+// keychain item exists.
+const securityItemNotFound = 44
+
+func keychainGet(ctx context.Context, service, account string) (string, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrKeychainUnavailable, err)
+	}
+	out, err := exec.CommandContext(ctx, "security", "find-generic-password",
+		"-s", service, "-a", account, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+		return "", errKeychainNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func keychainSet(ctx context.Context, service, account, secret string) error {
+	if _, err := exec.LookPath("security"); err != nil {
+		return fmt.Errorf("%w: %w", ErrKeychainUnavailable, err)
+	}
+	// The command is read from stdin in interactive mode so the secret does
+	// not appear in the process list.
+	cmd := exec.CommandContext(ctx, "security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		securityQuote(service), securityQuote(account), securityQuote(secret)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// securityQuote quotes s for the command line parser of security -i.
+func securityQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
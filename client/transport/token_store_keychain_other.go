@@ -0,0 +1,13 @@
+//go:build !darwin && !windows && !linux && !freebsd && !openbsd && !netbsd && !dragonfly
+
+package transport
+
+import "context"
+
+func keychainGet(ctx context.Context, service, account string) (string, error) {
+	return "", ErrKeychainUnavailable
+}
+
+func keychainSet(ctx context.Context, service, account, secret string) error {
+	return ErrKeychainUnavailable
+}
@@ -0,0 +1,47 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func keychainGet(ctx context.Context, service, account string) (string, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrKeychainUnavailable, err)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	// secret-tool exits with status 1 and prints nothing when no item
+	// matches.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(out) == 0 && stderr.Len() == 0 {
+		return "", errKeychainNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func keychainSet(ctx context.Context, service, account, secret string) error {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return fmt.Errorf("%w: %w", ErrKeychainUnavailable, err)
+	}
+	// secret-tool reads the secret from stdin so it does not appear in the
+	// process list.
+	cmd := exec.CommandContext(ctx, "secret-tool", "store",
+		"--label", service+" ("+account+")", "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package clientcompat keeps handler shapes from releases of package client
+// that predate URL elicitation, so that existing handlers keep working while
+// they are migrated. Everything in this package is deprecated; each
+// identifier documents its replacement.
+package clientcompat
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/compat/mcpcompat"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ElicitationHandlerFunc is an elicitation handler written before URL
+// elicitation, returning a response in its earlier shape. It implements
+// client.ElicitationHandler. Such handlers only know how to fill in forms,
+// so URL elicitation requests are declined without calling the function.
+//
+//	client.WithElicitationHandler(clientcompat.ElicitationHandlerFunc(oldHandler))
+//
+// Deprecated: Implement [client.ElicitationHandler] and handle
+// [mcp.ElicitationModeURL] requests.
+type ElicitationHandlerFunc func(ctx context.Context, request mcp.ElicitationRequest) (mcpcompat.ElicitationResponse, error)
+
+var _ client.ElicitationHandler = ElicitationHandlerFunc(nil)
+
+// Elicit implements client.ElicitationHandler.
+func (f ElicitationHandlerFunc) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	if request.Params.Mode == mcp.ElicitationModeURL {
+		return &mcp.ElicitationResult{
+			ElicitationResponse: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline},
+		}, nil
+	}
+	response, err := f(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return mcpcompat.NewElicitationResult(response), nil
+}
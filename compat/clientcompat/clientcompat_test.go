@@ -0,0 +1,32 @@
+package clientcompat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/compat/mcpcompat"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElicitationHandlerFunc(t *testing.T) {
+	calls := 0
+	handler := ElicitationHandlerFunc(func(_ context.Context, request mcp.ElicitationRequest) (mcpcompat.ElicitationResponse, error) {
+		calls++
+		return mcpcompat.ElicitationResponse{Type: mcpcompat.ElicitationResponseTypeAccept, Value: request.Params.Message}, nil
+	})
+
+	result, err := handler.Elicit(context.Background(), mcpcompat.NewElicitationRequest("hello", map[string]any{"type": "object"}))
+	require.NoError(t, err)
+	assert.Equal(t, mcp.ElicitationResponseActionAccept, result.Action)
+	assert.Equal(t, "hello", result.Content)
+
+	urlRequest := mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+		Mode: mcp.ElicitationModeURL, URL: "https://example.com", ElicitationID: "e1",
+	}}
+	result, err = handler.Elicit(context.Background(), urlRequest)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.ElicitationResponseActionDecline, result.Action)
+	assert.Equal(t, 1, calls, "URL requests do not reach the legacy handler")
+}
@@ -0,0 +1,78 @@
+// Package mcpcompat keeps names and signatures from releases of package mcp
+// that predate URL elicitation and tasks, so that code written against them
+// can be migrated call site by call site. Everything in this package is
+// deprecated; each identifier documents its replacement.
+//
+// Tasks were added without changing existing signatures: a tool that does
+// not declare task support is never run as a task, so pre-task code needs no
+// shim. The elicitation response, however, moved from Type/Value to
+// Action/Content when URL elicitation was introduced.
+package mcpcompat
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ElicitationResponseType is the earlier name of ElicitationResponseAction.
+//
+// Deprecated: Use [mcp.ElicitationResponseAction].
+type ElicitationResponseType = mcp.ElicitationResponseAction
+
+const (
+	// Deprecated: Use [mcp.ElicitationResponseActionAccept].
+	ElicitationResponseTypeAccept = mcp.ElicitationResponseActionAccept
+	// Deprecated: Use [mcp.ElicitationResponseActionDecline].
+	ElicitationResponseTypeDecline = mcp.ElicitationResponseActionDecline
+	// Deprecated: Use [mcp.ElicitationResponseActionCancel].
+	ElicitationResponseTypeCancel = mcp.ElicitationResponseActionCancel
+)
+
+// ElicitationResponse is the earlier shape of an elicitation response, with
+// a Type and a Value rather than an Action and Content.
+//
+// Deprecated: Use [mcp.ElicitationResponse].
+type ElicitationResponse struct {
+	Type  ElicitationResponseType
+	Value any
+}
+
+// ResponseOf returns the response of result in its earlier shape, for code
+// that read result.Response.Type and result.Response.Value.
+//
+// Deprecated: Read result.Action and result.Content.
+func ResponseOf(result *mcp.ElicitationResult) ElicitationResponse {
+	if result == nil {
+		return ElicitationResponse{}
+	}
+	return ElicitationResponse{Type: result.Action, Value: result.Content}
+}
+
+// NewElicitationResult builds an elicitation result from a response in its
+// earlier shape.
+//
+// Deprecated: Build an [mcp.ElicitationResult] with Action and Content.
+func NewElicitationResult(response ElicitationResponse) *mcp.ElicitationResult {
+	return &mcp.ElicitationResult{
+		ElicitationResponse: mcp.ElicitationResponse{
+			Action:  response.Type,
+			Content: response.Value,
+		},
+	}
+}
+
+// NewElicitationRequest builds a form elicitation request, the only kind
+// that existed before URL elicitation.
+//
+// Deprecated: Build an [mcp.ElicitationRequest] with Mode
+// [mcp.ElicitationModeForm], or use server.RequestURLElicitation for URL
+// elicitation.
+func NewElicitationRequest(message string, requestedSchema any) mcp.ElicitationRequest {
+	return mcp.ElicitationRequest{
+		Request: mcp.Request{Method: string(mcp.MethodElicitationCreate)},
+		Params: mcp.ElicitationParams{
+			Mode:            mcp.ElicitationModeForm,
+			Message:         message,
+			RequestedSchema: requestedSchema,
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package mcpcompat
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElicitationResponseRoundTrip(t *testing.T) {
+	result := NewElicitationResult(ElicitationResponse{
+		Type:  ElicitationResponseTypeAccept,
+		Value: map[string]any{"name": "x"},
+	})
+	assert.Equal(t, mcp.ElicitationResponseActionAccept, result.Action)
+	assert.Equal(t, map[string]any{"name": "x"}, result.Content)
+
+	response := ResponseOf(result)
+	assert.Equal(t, ElicitationResponseTypeAccept, response.Type)
+	assert.Equal(t, result.Content, response.Value)
+	assert.Equal(t, ElicitationResponse{}, ResponseOf(nil))
+}
+
+func TestNewElicitationRequest(t *testing.T) {
+	request := NewElicitationRequest("Your name?", map[string]any{"type": "object"})
+	assert.Equal(t, string(mcp.MethodElicitationCreate), request.Method)
+	assert.Equal(t, mcp.ElicitationModeForm, request.Params.Mode)
+	require.NoError(t, request.Params.Validate())
+}
@@ -0,0 +1,90 @@
+package everything
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var styles = []string{"formal", "casual", "technical", "creative"}
+
+// PromptCompletionProvider completes the style argument of the complex
+// prompt.
+func PromptCompletionProvider() server.PromptCompletionProvider {
+	return &promptCompletionProvider{}
+}
+
+// ResourceCompletionProvider completes the id argument of
+// DynamicResourceTemplate.
+func ResourceCompletionProvider() server.ResourceCompletionProvider {
+	return &resourceCompletionProvider{}
+}
+
+type promptCompletionProvider struct{}
+
+func (p *promptCompletionProvider) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	switch promptName {
+	case string(PromptComplex):
+		if argument.Name == "style" {
+			var suggestions []string
+			for _, style := range styles {
+				if argument.Value == "" || strings.HasPrefix(style, argument.Value) {
+					suggestions = append(suggestions, style)
+				}
+			}
+			return &mcp.Completion{
+				Values: suggestions,
+			}, nil
+		}
+		fallthrough
+	default:
+		return &mcp.Completion{
+			Values: []string{},
+		}, nil
+	}
+}
+
+type resourceCompletionProvider struct{}
+
+// CompleteResourceArgument returns suggestions for the "id" argument of the
+// "test://dynamic/resource/{id}" template.
+func (p *resourceCompletionProvider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	if uri == DynamicResourceTemplate && argument.Name == "id" {
+		if len(argument.Value) == 0 {
+			return &mcp.Completion{
+				Values:  getIdSuggestions(0),
+				Total:   maxId,
+				HasMore: true,
+			}, nil
+		}
+
+		// If the input is not a number, return empty suggestion.
+		intArgVal, err := strconv.Atoi(argument.Value)
+		if err != nil {
+			return &mcp.Completion{
+				Values: []string{},
+			}, nil
+		}
+
+		digits := len(argument.Value)
+
+		// If the input is out of range, return empty suggestion.
+		if !isIdInRange(intArgVal) {
+			return &mcp.Completion{
+				Values: []string{},
+			}, nil
+		}
+
+		return &mcp.Completion{
+			Values:  getIdSuggestions(intArgVal),
+			Total:   getTotalIds(digits),
+			HasMore: hasMoreIds(digits),
+		}, nil
+	}
+	return &mcp.Completion{
+		Values: []string{},
+	}, nil
+}
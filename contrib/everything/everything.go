@@ -0,0 +1,76 @@
+// Package everything is the MCP "everything" reference server: a set of
+// tools, resources, resource templates, prompts and completions that
+// exercise most of the protocol. Mount it into a server to test hosts and
+// clients against the full reference surface:
+//
+//	s := server.NewMCPServer("my-server", "1.0.0", everything.ServerOptions()...)
+//	everything.Register(s)
+//
+// or use NewMCPServer for a standalone server. The individual pieces are
+// also available through Tools, Resources, ResourceTemplates and Prompts,
+// e.g. for mcptest.Server.
+package everything
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolName names the tools of the reference server.
+type ToolName string
+
+const (
+	ToolEcho                 ToolName = "echo"
+	ToolAdd                  ToolName = "add"
+	ToolNotify               ToolName = "notify"
+	ToolLongRunningOperation ToolName = "longRunningOperation"
+	ToolGetTinyImage         ToolName = "getTinyImage"
+	ToolGetResourceLink      ToolName = "get_resource_link"
+)
+
+// PromptName names the prompts of the reference server.
+type PromptName string
+
+const (
+	PromptSimple  PromptName = "simple_prompt"
+	PromptComplex PromptName = "complex_prompt"
+)
+
+// Name and Version identify the server built by NewMCPServer.
+const (
+	Name    = "example-servers/everything"
+	Version = "1.0.0"
+)
+
+// ServerOptions returns the options the reference surface relies on:
+// resource, prompt and tool capabilities with list-changed notifications,
+// logging, and completions backed by PromptCompletionProvider and
+// ResourceCompletionProvider.
+func ServerOptions() []server.ServerOption {
+	return []server.ServerOption{
+		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(true),
+		server.WithToolCapabilities(true),
+		server.WithLogging(),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(PromptCompletionProvider()),
+		server.WithResourceCompletionProvider(ResourceCompletionProvider()),
+	}
+}
+
+// Register adds the tools, resources, resource templates and prompts of the
+// reference server to s. s should be created with ServerOptions for
+// completions to work.
+func Register(s *server.MCPServer) {
+	s.AddResources(Resources()...)
+	s.AddResourceTemplates(ResourceTemplates()...)
+	s.AddPrompts(Prompts()...)
+	s.AddTools(Tools()...)
+}
+
+// NewMCPServer creates a standalone reference server. opts are applied after
+// ServerOptions.
+func NewMCPServer(opts ...server.ServerOption) *server.MCPServer {
+	s := server.NewMCPServer(Name, Version, append(ServerOptions(), opts...)...)
+	Register(s)
+	return s
+}
@@ -0,0 +1,55 @@
+package everything_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/contrib/everything"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/mcptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountIntoMCPTest(t *testing.T) {
+	srv := mcptest.NewUnstartedServer(t)
+	srv.AddServerOptions(everything.ServerOptions()...)
+	srv.AddTools(everything.Tools()...)
+	srv.AddResources(everything.Resources()...)
+	srv.AddResourceTemplates(everything.ResourceTemplates()...)
+	srv.AddPrompts(everything.Prompts()...)
+	require.NoError(t, srv.Start(t.Context()))
+	defer srv.Close()
+	c := srv.Client()
+
+	tools, err := c.ListTools(t.Context(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, tools.Tools, len(everything.Tools()))
+
+	var call mcp.CallToolRequest
+	call.Params.Name = string(everything.ToolAdd)
+	call.Params.Arguments = map[string]any{"a": 1, "b": 2}
+	result, err := c.CallTool(t.Context(), call)
+	require.NoError(t, err)
+	assert.Equal(t, "The sum of 1.000000 and 2.000000 is 3.000000.", result.Content[0].(mcp.TextContent).Text)
+
+	var read mcp.ReadResourceRequest
+	read.Params.URI = "test://static/resource/2"
+	contents, err := c.ReadResource(t.Context(), read)
+	require.NoError(t, err)
+	require.Len(t, contents.Contents, 1)
+	assert.IsType(t, mcp.BlobResourceContents{}, contents.Contents[0])
+
+	var complete mcp.CompleteRequest
+	complete.Params.Ref = mcp.PromptReference{Type: "ref/prompt", Name: string(everything.PromptComplex)}
+	complete.Params.Argument.Name = "style"
+	complete.Params.Argument.Value = "c"
+	completion, err := c.Complete(t.Context(), complete)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"casual", "creative"}, completion.Completion.Values)
+}
+
+func TestNewMCPServer(t *testing.T) {
+	s := everything.NewMCPServer()
+	assert.Len(t, s.ListTools(), len(everything.Tools()))
+	assert.NotNil(t, s.GetTool(string(everything.ToolEcho)))
+}
@@ -0,0 +1,92 @@
+package everything
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Prompts returns the prompts of the reference server.
+func Prompts() []server.ServerPrompt {
+	return []server.ServerPrompt{
+		{
+			Prompt: mcp.NewPrompt(string(PromptSimple),
+				mcp.WithPromptDescription("A simple prompt"),
+				mcp.WithPromptIcons(mcp.Icon{Src: "https://example.com/prompt.png"}),
+			),
+			Handler: handleSimplePrompt,
+		},
+		{
+			Prompt: mcp.NewPrompt(string(PromptComplex),
+				mcp.WithPromptDescription("A complex prompt"),
+				mcp.WithArgument("temperature",
+					mcp.ArgumentDescription("The temperature parameter for generation"),
+					mcp.RequiredArgument(),
+				),
+				mcp.WithArgument("style",
+					mcp.ArgumentDescription("The style to use for the response"),
+					mcp.RequiredArgument(),
+				),
+			),
+			Handler: handleComplexPrompt,
+		},
+	}
+}
+
+func handleSimplePrompt(
+	ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	return &mcp.GetPromptResult{
+		Description: "A simple prompt without arguments",
+		Messages: []mcp.PromptMessage{
+			{
+				Role: mcp.RoleUser,
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: "This is a simple prompt without arguments.",
+				},
+			},
+		},
+	}, nil
+}
+
+func handleComplexPrompt(
+	ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	arguments := request.Params.Arguments
+	return &mcp.GetPromptResult{
+		Description: "A complex prompt with arguments",
+		Messages: []mcp.PromptMessage{
+			{
+				Role: mcp.RoleUser,
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(
+						"This is a complex prompt with arguments: temperature=%s, style=%s",
+						arguments["temperature"],
+						arguments["style"],
+					),
+				},
+			},
+			{
+				Role: mcp.RoleAssistant,
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: "I understand. You've provided a complex prompt with temperature and style arguments. How would you like me to proceed?",
+				},
+			},
+			{
+				Role: mcp.RoleUser,
+				Content: mcp.ImageContent{
+					Type:     "image",
+					Data:     TinyImage,
+					MIMEType: "image/png",
+				},
+			},
+		},
+	}, nil
+}
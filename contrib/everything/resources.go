@@ -0,0 +1,127 @@
+package everything
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DynamicResourceTemplate is the URI template of the reference server's
+// dynamic resource, whose id argument supports completion.
+const DynamicResourceTemplate = "test://dynamic/resource/{id}"
+
+// Resources returns the static resources of the reference server: one text
+// resource plus 100 generated resources alternating between text and binary
+// content.
+func Resources() []server.ServerResource {
+	resources := []server.ServerResource{{
+		Resource: mcp.NewResource("test://static/resource",
+			"Static Resource",
+			mcp.WithMIMEType("text/plain"),
+			mcp.WithResourceIcons(mcp.Icon{Src: "https://example.com/icon.png", MIMEType: "image/png"}),
+		),
+		Handler: handleReadResource,
+	}}
+	for _, resource := range generateResources() {
+		resources = append(resources, server.ServerResource{Resource: resource, Handler: handleGeneratedResource})
+	}
+	return resources
+}
+
+// ResourceTemplates returns the resource templates of the reference server.
+func ResourceTemplates() []server.ServerResourceTemplate {
+	return []server.ServerResourceTemplate{{
+		Template: mcp.NewResourceTemplate(
+			DynamicResourceTemplate,
+			"Dynamic Resource",
+		),
+		Handler: handleResourceTemplate,
+	}}
+}
+
+func generateResources() []mcp.Resource {
+	resources := make([]mcp.Resource, 100)
+	for i := range 100 {
+		uri := fmt.Sprintf("test://static/resource/%d", i+1)
+		if i%2 == 0 {
+			resources[i] = mcp.Resource{
+				URI:      uri,
+				Name:     fmt.Sprintf("Resource %d", i+1),
+				MIMEType: "text/plain",
+			}
+		} else {
+			resources[i] = mcp.Resource{
+				URI:      uri,
+				Name:     fmt.Sprintf("Resource %d", i+1),
+				MIMEType: "application/octet-stream",
+			}
+		}
+	}
+	return resources
+}
+
+func handleReadResource(
+	ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      "test://static/resource",
+			MIMEType: "text/plain",
+			Text:     "This is a sample resource",
+		},
+	}, nil
+}
+
+func handleResourceTemplate(
+	ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/plain",
+			Text:     "This is a sample resource",
+		},
+	}, nil
+}
+
+func handleGeneratedResource(
+	ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+
+	var resourceNumber string
+	if _, err := fmt.Sscanf(uri, "test://static/resource/%s", &resourceNumber); err != nil {
+		return nil, fmt.Errorf("invalid resource URI format: %w", err)
+	}
+
+	num, err := strconv.Atoi(resourceNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource number: %w", err)
+	}
+
+	index := num - 1
+
+	if index%2 == 0 {
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "text/plain",
+				Text:     fmt.Sprintf("Text content for resource %d", num),
+			},
+		}, nil
+	}
+	return []mcp.ResourceContents{
+		mcp.BlobResourceContents{
+			URI:      uri,
+			MIMEType: "application/octet-stream",
+			Blob:     base64.StdEncoding.EncodeToString(fmt.Appendf(nil, "Binary content for resource %d", num)),
+		},
+	}, nil
+}
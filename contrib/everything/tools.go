@@ -0,0 +1,244 @@
+package everything
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tools returns the tools of the reference server.
+func Tools() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool(string(ToolEcho),
+				mcp.WithDescription("Echoes back the input"),
+				mcp.WithString("message",
+					mcp.Description("Message to echo"),
+					mcp.Required(),
+				),
+				mcp.WithToolIcons(mcp.Icon{Src: "https://example.com/tool.png", Sizes: []string{"32x32"}}),
+			),
+			Handler: handleEchoTool,
+		},
+		{
+			Tool:    mcp.NewTool(string(ToolNotify)),
+			Handler: handleSendNotification,
+		},
+		{
+			Tool: mcp.NewTool(string(ToolAdd),
+				mcp.WithDescription("Adds two numbers"),
+				mcp.WithNumber("a",
+					mcp.Description("First number"),
+					mcp.Required(),
+				),
+				mcp.WithNumber("b",
+					mcp.Description("Second number"),
+					mcp.Required(),
+				),
+			),
+			Handler: handleAddTool,
+		},
+		{
+			Tool: mcp.NewTool(string(ToolLongRunningOperation),
+				mcp.WithDescription(
+					"Demonstrates a long running operation with progress updates",
+				),
+				mcp.WithNumber("duration",
+					mcp.Description("Duration of the operation in seconds"),
+					mcp.DefaultNumber(10),
+				),
+				mcp.WithNumber("steps",
+					mcp.Description("Number of steps in the operation"),
+					mcp.DefaultNumber(5),
+				),
+			),
+			Handler: handleLongRunningOperationTool,
+		},
+		{
+			Tool: mcp.NewTool(string(ToolGetTinyImage),
+				mcp.WithDescription("Returns the MCP_TINY_IMAGE"),
+			),
+			Handler: handleGetTinyImageTool,
+		},
+		{
+			Tool: mcp.NewTool(string(ToolGetResourceLink),
+				mcp.WithDescription("Returns a resource link example"),
+				mcp.WithString("resource_type",
+					mcp.Description("Type of resource to link to"),
+					mcp.DefaultString("document")),
+			),
+			Handler: handleGetResourceLinkTool,
+		},
+	}
+}
+
+func handleEchoTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	message, ok := arguments["message"].(string)
+	if !ok {
+		return mcp.NewToolResultError("invalid message argument: expected string"), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Echo: %s", message),
+			},
+		},
+	}, nil
+}
+
+func handleAddTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	a, ok1 := arguments["a"].(float64)
+	b, ok2 := arguments["b"].(float64)
+	if !ok1 || !ok2 {
+		return mcp.NewToolResultError("invalid number arguments: expected numeric values for 'a' and 'b'"), nil
+	}
+	sum := a + b
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("The sum of %f and %f is %f.", a, b, sum),
+			},
+		},
+	}, nil
+}
+
+func handleSendNotification(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	server := server.ServerFromContext(ctx)
+
+	err := server.SendNotificationToClient(
+		ctx,
+		"notifications/progress",
+		map[string]any{
+			"progress":      10,
+			"total":         10,
+			"progressToken": 0,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "notification sent successfully",
+			},
+		},
+	}, nil
+}
+
+func handleLongRunningOperationTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	var progressToken mcp.ProgressToken
+	if request.Params.Meta != nil {
+		progressToken = request.Params.Meta.ProgressToken
+	}
+	duration, _ := arguments["duration"].(float64)
+	steps, _ := arguments["steps"].(float64)
+	stepDuration := duration / steps
+	server := server.ServerFromContext(ctx)
+
+	for i := 1; i < int(steps)+1; i++ {
+		select {
+		case <-time.After(time.Duration(stepDuration * float64(time.Second))):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if progressToken != nil {
+			err := server.SendNotificationToClient(
+				ctx,
+				"notifications/progress",
+				map[string]any{
+					"progress":      i,
+					"total":         int(steps),
+					"progressToken": progressToken,
+					"message":       fmt.Sprintf("Server progress %v%%", int(float64(i)*100/steps)),
+				},
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to send notification: %w", err)
+			}
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf(
+					"Long running operation completed. Duration: %f seconds, Steps: %d.",
+					duration,
+					int(steps),
+				),
+			},
+		},
+	}, nil
+}
+
+func handleGetTinyImageTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: "This is a tiny image:",
+			},
+			mcp.ImageContent{
+				Type:     "image",
+				Data:     TinyImage,
+				MIMEType: "image/png",
+			},
+			mcp.TextContent{
+				Type: "text",
+				Text: "The image above is the MCP tiny image.",
+			},
+		},
+	}, nil
+}
+
+func handleGetResourceLinkTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	resourceType := request.GetString("resource_type", "document")
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Here's a link to a %s resource:", resourceType),
+			},
+			mcp.NewResourceLink(
+				fmt.Sprintf("file:///example/%s.pdf", resourceType),
+				fmt.Sprintf("Sample %s", resourceType),
+				fmt.Sprintf("A sample %s for demonstration", resourceType),
+				"application/pdf",
+			),
+			mcp.TextContent{
+				Type: "text",
+				Text: "You can access this resource using the provided URI.",
+			},
+		},
+	}, nil
+}
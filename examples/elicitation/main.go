@@ -13,39 +13,24 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// projectDetails is the form the user fills in. Its schema is generated from
+// the struct: fields without omitempty are required, the jsonschema tag sets
+// the description, the enum tag lists the allowed values and the default and
+// minLength tags set the default value and minimum length.
+type projectDetails struct {
+	ProjectName  string `json:"projectName" jsonschema:"Name of the project" minLength:"1"`
+	Framework    string `json:"framework,omitempty" jsonschema:"Frontend framework to use" enum:"react,vue,angular,none"`
+	IncludeTests *bool  `json:"includeTests,omitempty" jsonschema:"Include test setup" default:"true"`
+}
+
 // demoElicitationHandler demonstrates how to use elicitation in a tool
 func demoElicitationHandler(s *server.MCPServer) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		// Create an elicitation request to get project details
-		elicitationRequest := mcp.ElicitationRequest{
-			Params: mcp.ElicitationParams{
-				Message: "I need some information to set up your project. Please provide the project details.",
-				RequestedSchema: map[string]any{
-					"type": "object",
-					"properties": map[string]any{
-						"projectName": map[string]any{
-							"type":        "string",
-							"description": "Name of the project",
-							"minLength":   1,
-						},
-						"framework": map[string]any{
-							"type":        "string",
-							"description": "Frontend framework to use",
-							"enum":        []string{"react", "vue", "angular", "none"},
-						},
-						"includeTests": map[string]any{
-							"type":        "boolean",
-							"description": "Include test setup",
-							"default":     true,
-						},
-					},
-					"required": []string{"projectName"},
-				},
-			},
-		}
-
-		// Request elicitation from the client
-		result, err := s.RequestElicitation(ctx, elicitationRequest)
+		// Request project details from the client, decoded into the struct
+		details, result, err := server.RequestTypedElicitation[projectDetails](
+			ctx, s,
+			"I need some information to set up your project. Please provide the project details.",
+		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to request elicitation: %w", err)
 		}
@@ -54,48 +39,19 @@ func demoElicitationHandler(s *server.MCPServer) server.ToolHandlerFunc {
 		switch result.Action {
 		case mcp.ElicitationResponseActionAccept:
 			// User provided the information
-			data, ok := result.Content.(map[string]any)
-			if !ok {
-				return nil, fmt.Errorf("unexpected response format: expected map[string]any, got %T", result.Content)
-			}
-
-			// Safely extract projectName (required field)
-			projectNameRaw, exists := data["projectName"]
-			if !exists {
-				return nil, fmt.Errorf("required field 'projectName' is missing from response")
-			}
-			projectName, ok := projectNameRaw.(string)
-			if !ok {
-				return nil, fmt.Errorf("field 'projectName' must be a string, got %T", projectNameRaw)
-			}
-			if projectName == "" {
+			if details.ProjectName == "" {
 				return nil, fmt.Errorf("field 'projectName' cannot be empty")
 			}
-
-			// Safely extract framework (optional field)
-			framework := "none"
-			if frameworkRaw, exists := data["framework"]; exists {
-				if fw, ok := frameworkRaw.(string); ok {
-					framework = fw
-				} else {
-					return nil, fmt.Errorf("field 'framework' must be a string, got %T", frameworkRaw)
-				}
-			}
-
-			// Safely extract includeTests (optional field)
-			includeTests := true
-			if testsRaw, exists := data["includeTests"]; exists {
-				if tests, ok := testsRaw.(bool); ok {
-					includeTests = tests
-				} else {
-					return nil, fmt.Errorf("field 'includeTests' must be a boolean, got %T", testsRaw)
-				}
+			framework := details.Framework
+			if framework == "" {
+				framework = "none"
 			}
+			includeTests := details.IncludeTests == nil || *details.IncludeTests
 
 			// Create project based on user input
 			message := fmt.Sprintf(
 				"Created project '%s' with framework: %s, tests: %v",
-				projectName, framework, includeTests,
+				details.ProjectName, framework, includeTests,
 			)
 
 			return &mcp.CallToolResult{
@@ -162,20 +118,10 @@ func main() {
 				elicitationRequest := mcp.ElicitationRequest{
 					Params: mcp.ElicitationParams{
 						Message: fmt.Sprintf("The data is %d characters long. Do you want to proceed with processing?", len(data)),
-						RequestedSchema: map[string]any{
-							"type": "object",
-							"properties": map[string]any{
-								"proceed": map[string]any{
-									"type":        "boolean",
-									"description": "Whether to proceed with processing",
-								},
-								"reason": map[string]any{
-									"type":        "string",
-									"description": "Optional reason for your decision",
-								},
-							},
-							"required": []string{"proceed"},
-						},
+						RequestedSchema: mcp.NewElicitationSchema(
+							mcp.ConfirmField("proceed", mcp.Description("Whether to proceed with processing")),
+							mcp.TextField("reason", mcp.Description("Optional reason for your decision")),
+						),
 					},
 				}
 
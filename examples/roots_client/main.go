@@ -4,11 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"strings"
 	"syscall"
 
 	"github.com/mark3labs/mcp-go/client"
@@ -16,46 +13,9 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-// fileURI returns a file:// URI for both Unix and Windows absolute paths.
-func fileURI(p string) string {
-	p = filepath.ToSlash(p)
-	if !strings.HasPrefix(p, "/") { // e.g., "C:/Users/..." on Windows
-		p = "/" + p
-	}
-	return (&url.URL{Scheme: "file", Path: p}).String()
-}
-
-// MockRootsHandler implements client.RootsHandler for demonstration.
-// In a real implementation, this would enumerate workspace/project roots.
-type MockRootsHandler struct{}
-
-// ListRoots implements client.RootsHandler by returning example workspace roots.
-func (h *MockRootsHandler) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Printf("Warning: failed to get home directory: %v", err)
-		home = "/tmp" // fallback for demonstration
-	}
-	app := filepath.ToSlash(filepath.Join(home, "app"))
-	proj := filepath.ToSlash(filepath.Join(home, "projects", "test-project"))
-	result := &mcp.ListRootsResult{
-		Roots: []mcp.Root{
-			{
-				Name: "app",
-				URI:  fileURI(app),
-			},
-			{
-				Name: "test-project",
-				URI:  fileURI(proj),
-			},
-		},
-	}
-	return result, nil
-}
-
-// main starts a mock MCP roots client that communicates with a subprocess over stdio.
+// main starts an MCP roots client that communicates with a subprocess over stdio.
 // It expects the server command as the first command-line argument, creates a stdio
-// transport and an MCP client with a MockRootsHandler, starts and initializes the
+// transport and an MCP client with a FSRootsHandler, starts and initializes the
 // client, logs server info and available tools, notifies the server of root list
 // changes, invokes the "roots" tool and prints any text content returned, and
 // shuts down the client gracefully on SIGINT or SIGTERM.
@@ -70,8 +30,11 @@ func main() {
 	// Create stdio transport to communicate with the server
 	stdio := transport.NewStdio(serverCommand, nil, serverArgs...)
 
-	// Create roots handler
-	rootsHandler := &MockRootsHandler{}
+	// Expose the working directory as a root
+	rootsHandler, err := client.NewFSRootsHandler(".")
+	if err != nil {
+		log.Fatalf("Failed to create roots handler: %v", err)
+	}
 
 	// Create client with roots capability
 	mcpClient := client.NewClient(stdio, client.WithRootsHandler(rootsHandler))
@@ -4,11 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"strings"
 	"syscall"
 
 	"github.com/mark3labs/mcp-go/client"
@@ -16,49 +13,15 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-// fileURI returns a file:// URI for both Unix and Windows absolute paths.
-func fileURI(p string) string {
-	p = filepath.ToSlash(p)
-	if !strings.HasPrefix(p, "/") { // e.g., "C:/Users/..." on Windows
-		p = "/" + p
-	}
-	return (&url.URL{Scheme: "file", Path: p}).String()
-}
-
-// MockRootsHandler implements client.RootsHandler for demonstration.
-// In a real implementation, this would enumerate workspace/project roots.
-type MockRootsHandler struct{}
-
-// ListRoots implements client.RootsHandler by returning example workspace roots.
-func (h *MockRootsHandler) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Printf("Warning: failed to get home directory: %v", err)
-		home = "/tmp" // fallback for demonstration
-	}
-	app := filepath.ToSlash(filepath.Join(home, "app"))
-	proj := filepath.ToSlash(filepath.Join(home, "projects", "test-project"))
-	result := &mcp.ListRootsResult{
-		Roots: []mcp.Root{
-			{
-				Name: "app",
-				URI:  fileURI(app),
-			},
-			{
-				Name: "test-project",
-				URI:  fileURI(proj),
-			},
-		},
-	}
-	return result, nil
-}
-
-// main starts a mock MCP roots client over HTTP.
+// main starts an MCP roots client over HTTP.
 // The server tool triggers a roots/list request on the client.
 // The client shuts down gracefully on SIGINT or SIGTERM.
 func main() {
-	// Create roots handler
-	rootsHandler := &MockRootsHandler{}
+	// Expose the working directory as a root
+	rootsHandler, err := client.NewFSRootsHandler(".")
+	if err != nil {
+		log.Fatalf("Failed to create roots handler: %v", err)
+	}
 
 	// Create HTTP transport directly
 	httpTransport, err := transport.NewStreamableHTTP(
@@ -108,14 +71,14 @@ func main() {
 
 	log.Println("HTTP MCP client with roots support started successfully!")
 	log.Println("The client is now ready to handle roots requests from the server.")
-	log.Println("When the server sends a roots request, the MockRootsHandler will process it.")
+	log.Println("When the server sends a roots request, the FSRootsHandler will process it.")
 
 	// In a real application, you would keep the client running to handle roots requests
 	// For this example, we'll just demonstrate that it's working
 
-	// mock the root change
-	if err := mcpClient.RootListChanges(ctx); err != nil {
-		log.Printf("failed to notify root list change: %v", err)
+	// Adding a root notifies the server that the roots changed
+	if err := rootsHandler.Add(ctx, os.TempDir()); err != nil {
+		log.Printf("failed to add root: %v", err)
 	}
 
 	// call server tool
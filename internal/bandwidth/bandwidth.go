@@ -0,0 +1,149 @@
+// Package bandwidth paces byte streams to a maximum rate. It backs the
+// bandwidth limit options of the client and server transports.
+package bandwidth
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket of bytes. It allows bursts of up to one
+// second's worth of bytes and then paces reads or writes to its rate. A
+// Limiter is safe for concurrent use, and a nil Limiter never waits.
+type Limiter struct {
+	rate  float64 // bytes per second
+	burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter that allows bytesPerSecond bytes per second,
+// or nil if bytesPerSecond is not positive.
+func NewLimiter(bytesPerSecond int) *Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &Limiter{
+		rate:   float64(bytesPerSecond),
+		burst:  bytesPerSecond,
+		tokens: float64(bytesPerSecond),
+		last:   time.Now(),
+	}
+}
+
+// WaitN blocks until n bytes may pass, or ctx is done. Concurrent callers
+// are served in the order they call WaitN.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+	deficit := -l.tokens
+	l.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewReader returns a reader that paces reads from r to l. It returns r
+// itself if l is nil.
+func NewReader(ctx context.Context, r io.Reader, l *Limiter) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &reader{ctx: ctx, r: r, l: l}
+}
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(p) > r.l.burst {
+		p = p[:r.l.burst]
+	}
+	n, err := r.r.Read(p)
+	if waitErr := r.l.WaitN(r.ctx, n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// NewWriter returns a writer that paces writes to w to l. Each Write is
+// passed to w in chunks of at most l.Burst bytes without interleaving
+// with concurrent Writes. It returns w itself if l is nil.
+func NewWriter(ctx context.Context, w io.Writer, l *Limiter) io.Writer {
+	if l == nil {
+		return w
+	}
+	return &writer{ctx: ctx, w: w, l: l}
+}
+
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+	l   *Limiter
+	mu  sync.Mutex
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), w.l.burst)]
+		if err := w.l.WaitN(w.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// NewReadCloser is NewReader for an io.ReadCloser; closing the result
+// closes rc.
+func NewReadCloser(ctx context.Context, rc io.ReadCloser, l *Limiter) io.ReadCloser {
+	if l == nil {
+		return rc
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{NewReader(ctx, rc, l), rc}
+}
+
+// NewWriteCloser is NewWriter for an io.WriteCloser; closing the result
+// closes wc.
+func NewWriteCloser(ctx context.Context, wc io.WriteCloser, l *Limiter) io.WriteCloser {
+	if l == nil {
+		return wc
+	}
+	return struct {
+		io.Writer
+		io.Closer
+	}{NewWriter(ctx, wc, l), wc}
+}
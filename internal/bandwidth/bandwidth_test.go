@@ -0,0 +1,76 @@
+package bandwidth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLimiter_Unlimited(t *testing.T) {
+	assert.Nil(t, NewLimiter(0))
+	assert.Nil(t, NewLimiter(-1))
+
+	var l *Limiter
+	assert.NoError(t, l.WaitN(t.Context(), 1<<20))
+
+	r := strings.NewReader("data")
+	assert.Same(t, r, NewReader(t.Context(), r, nil))
+}
+
+func TestWriter_PacesToRate(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(t.Context(), &buf, NewLimiter(1000))
+
+	start := time.Now()
+	// The first 1000 bytes pass as a burst, the next 500 take half a second.
+	n, err := w.Write(bytes.Repeat([]byte("x"), 1500))
+	require.NoError(t, err)
+	assert.Equal(t, 1500, n)
+	assert.Equal(t, 1500, buf.Len())
+	assert.GreaterOrEqual(t, time.Since(start), 450*time.Millisecond)
+}
+
+func TestWriter_DoesNotInterleave(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(t.Context(), &buf, NewLimiter(400))
+
+	// Both frames are larger than the burst, so they are written in chunks.
+	a, b := strings.Repeat("a", 500), strings.Repeat("b", 500)
+	var wg sync.WaitGroup
+	for _, frame := range []string{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := w.Write([]byte(frame + "\n"))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, []string{a, b, ""}, strings.Split(buf.String(), "\n"))
+}
+
+func TestReader_PacesToRate(t *testing.T) {
+	r := NewReader(t.Context(), strings.NewReader(strings.Repeat("x", 1500)), NewLimiter(1000))
+
+	start := time.Now()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Len(t, data, 1500)
+	assert.GreaterOrEqual(t, time.Since(start), 450*time.Millisecond)
+}
+
+func TestLimiter_WaitN_Canceled(t *testing.T) {
+	l := NewLimiter(10)
+	require.NoError(t, l.WaitN(t.Context(), 10))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	assert.ErrorIs(t, l.WaitN(ctx, 10), context.Canceled)
+}
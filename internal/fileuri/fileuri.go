@@ -0,0 +1,39 @@
+// Package fileuri converts between local paths and file:// URIs. It is
+// shared by the client roots handler, the server workspace and the
+// filesystem resource provider so they agree on the URI of every path.
+package fileuri
+
+import (
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// FromPath converts an absolute local path to a file:// URI, using forward
+// slashes and a leading slash before Windows drive letters.
+func FromPath(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
+
+// ToPath converts a file:// URI to a clean absolute local path. It reports
+// false for other URIs and for URIs that do not name an absolute path.
+func ToPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	p := u.Path
+	if runtime.GOOS == "windows" {
+		p = strings.TrimPrefix(p, "/")
+	}
+	p = filepath.Clean(filepath.FromSlash(p))
+	if !filepath.IsAbs(p) {
+		return "", false
+	}
+	return p, true
+}
@@ -0,0 +1,31 @@
+package fileuri
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "with space", "é")
+	uri := FromPath(dir)
+	assert.Contains(t, uri, "file:///")
+	assert.Contains(t, uri, "with%20space")
+
+	path, ok := ToPath(uri)
+	assert.True(t, ok)
+	assert.Equal(t, dir, path)
+}
+
+func TestToPath_Rejects(t *testing.T) {
+	for _, uri := range []string{
+		"https://example.com/repo",
+		"file://",
+		"file:relative/path",
+		"%zz",
+	} {
+		_, ok := ToPath(uri)
+		assert.False(t, ok, uri)
+	}
+}
@@ -0,0 +1,209 @@
+// Package framing delimits JSON-RPC messages on a byte stream. It backs the
+// framing options of the stdio server and client transports.
+package framing
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Mode selects how messages are delimited.
+type Mode int
+
+const (
+	// Newline ends every message with a newline. The message itself must
+	// not contain one, which holds for compact JSON.
+	Newline Mode = iota
+	// ContentLength precedes every message with LSP-style headers, a
+	// Content-Length header giving the size of the message in bytes and an
+	// empty line, so messages may span lines.
+	ContentLength
+	// Auto detects the mode from the first message read: a message starting
+	// with a header selects ContentLength, anything else Newline.
+	Auto
+)
+
+// MaxContentLength is the largest message a Reader accepts in ContentLength
+// mode, guarding against a peer announcing an absurd size.
+const MaxContentLength = 256 << 20
+
+// ErrMalformedHeader is returned by a Reader in ContentLength mode when the
+// headers of a message cannot be parsed.
+var ErrMalformedHeader = errors.New("framing: malformed message header")
+
+// Reader reads framed messages.
+type Reader struct {
+	r    *bufio.Reader
+	mode atomic.Int64
+}
+
+// NewReader returns a Reader reading messages framed with mode from r.
+func NewReader(r io.Reader, mode Mode) *Reader {
+	reader := &Reader{r: bufio.NewReader(r)}
+	reader.mode.Store(int64(mode))
+	return reader
+}
+
+// Mode returns the mode of r, which for a Reader created in Auto mode is
+// Auto until the first message has been read. It is safe to call
+// concurrently with ReadMessage.
+func (r *Reader) Mode() Mode {
+	return Mode(r.mode.Load())
+}
+
+// ReadMessage returns the next message. In Newline mode the message keeps
+// its trailing newline; at the end of the stream it returns the remaining
+// bytes together with io.EOF, as bufio.Reader.ReadBytes does. In
+// ContentLength mode it returns io.EOF only between messages, and
+// io.ErrUnexpectedEOF if the stream ends within one.
+func (r *Reader) ReadMessage() ([]byte, error) {
+	switch r.Mode() {
+	case ContentLength:
+		return r.readContentLength("")
+	case Auto:
+		return r.detect()
+	default:
+		return r.r.ReadBytes('\n')
+	}
+}
+
+// detect reads the first message of a Reader in Auto mode and settles its
+// mode.
+func (r *Reader) detect() ([]byte, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			if err != nil {
+				return line, err
+			}
+			continue
+		}
+		if trimmed[0] == '{' || trimmed[0] == '[' {
+			r.mode.Store(int64(Newline))
+			return line, err
+		}
+		r.mode.Store(int64(ContentLength))
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		return r.readContentLength(string(line))
+	}
+}
+
+// readContentLength reads a message in ContentLength mode. first is the
+// first header line if it has already been read.
+func (r *Reader) readContentLength(first string) ([]byte, error) {
+	length := -1
+	headers := false
+	for {
+		line := first
+		first = ""
+		if line == "" {
+			var err error
+			if line, err = r.r.ReadString('\n'); err != nil {
+				if err == io.EOF && (headers || line != "") {
+					return nil, io.ErrUnexpectedEOF
+				}
+				return nil, err
+			}
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if !headers {
+				// Tolerate blank lines between messages.
+				continue
+			}
+			break
+		}
+		headers = true
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMalformedHeader, line)
+		}
+		if !strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrMalformedHeader, line)
+		}
+		if n > MaxContentLength {
+			return nil, fmt.Errorf("%w: message of %d bytes exceeds the limit of %d", ErrMalformedHeader, n, MaxContentLength)
+		}
+		length = n
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("%w: missing Content-Length", ErrMalformedHeader)
+	}
+
+	// Read incrementally rather than allocating the announced length up
+	// front, so a peer cannot claim a huge message it never sends.
+	message, err := io.ReadAll(io.LimitReader(r.r, int64(length)))
+	if err != nil {
+		return nil, err
+	}
+	if len(message) < length {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return message, nil
+}
+
+// NewWriter returns a writer that frames every Write call as one message
+// with mode. Writers of newline-delimited messages can therefore switch
+// modes unchanged: in ContentLength mode the trailing newline of a message
+// is dropped and the message is written after its headers in a single Write
+// to w. In Newline mode NewWriter returns w itself. Writes in Auto mode are
+// newline-delimited; use NewReplyWriter to follow the mode of the peer.
+func NewWriter(w io.Writer, mode Mode) io.Writer {
+	if mode != ContentLength {
+		return w
+	}
+	return &contentLengthWriter{w: w}
+}
+
+// NewReplyWriter returns a writer that frames every Write call as one
+// message in the mode of r, so replies use the framing the peer chose.
+// Writes made before r has detected the mode are newline-delimited.
+func NewReplyWriter(w io.Writer, r *Reader) io.Writer {
+	return &replyWriter{w: w, contentLength: &contentLengthWriter{w: w}, reader: r}
+}
+
+type replyWriter struct {
+	w             io.Writer
+	contentLength *contentLengthWriter
+	reader        *Reader
+}
+
+func (w *replyWriter) Write(p []byte) (int, error) {
+	if w.reader.Mode() == ContentLength {
+		return w.contentLength.Write(p)
+	}
+	return w.w.Write(p)
+}
+
+type contentLengthWriter struct {
+	w io.Writer
+}
+
+func (w *contentLengthWriter) Write(p []byte) (int, error) {
+	message := bytes.TrimRight(p, "\r\n")
+	framed := make([]byte, 0, len(message)+32)
+	framed = append(framed, "Content-Length: "...)
+	framed = strconv.AppendInt(framed, int64(len(message)), 10)
+	framed = append(framed, "\r\n\r\n"...)
+	framed = append(framed, message...)
+	if _, err := w.w.Write(framed); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
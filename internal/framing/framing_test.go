@@ -0,0 +1,95 @@
+package framing
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentLength_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, ContentLength)
+	messages := []string{`{"id":1}`, "{\n  \"id\": 2\n}", `{"text":"héllo"}`}
+	for _, m := range messages {
+		n, err := w.Write([]byte(m + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, len(m)+1, n)
+	}
+	assert.True(t, strings.HasPrefix(buf.String(), "Content-Length: 8\r\n\r\n{\"id\":1}Content-Length: "))
+
+	r := NewReader(&buf, ContentLength)
+	for _, m := range messages {
+		message, err := r.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, m, string(message))
+	}
+	_, err := r.ReadMessage()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestContentLength_Headers(t *testing.T) {
+	input := "\r\ncontent-type: application/vscode-jsonrpc; charset=utf-8\r\ncontent-length:  2\r\n\r\n{}"
+	message, err := NewReader(strings.NewReader(input), ContentLength).ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(message))
+
+	for input, want := range map[string]error{
+		"Content-Type: x\r\n\r\n{}":           ErrMalformedHeader,
+		"Content-Length: -1\r\n\r\n":          ErrMalformedHeader,
+		"Content-Length: many\r\n\r\n":        ErrMalformedHeader,
+		"not a header\r\n\r\n":                ErrMalformedHeader,
+		"Content-Length: 10\r\n\r\n{}":        io.ErrUnexpectedEOF,
+		"Content-Length: 10\r\n":              io.ErrUnexpectedEOF,
+		"Content-Length: 999999999\r\n":       ErrMalformedHeader,
+		"Content-Length: 268435456\r\n\r\n{}": io.ErrUnexpectedEOF,
+	} {
+		_, err := NewReader(strings.NewReader(input), ContentLength).ReadMessage()
+		assert.True(t, errors.Is(err, want), "%q: %v", input, err)
+	}
+}
+
+func TestNewline(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Same(t, &buf, NewWriter(&buf, Newline))
+
+	r := NewReader(strings.NewReader("{\"id\":1}\n{\"id\":2}"), Newline)
+	message, err := r.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":1}\n", string(message))
+	message, err = r.ReadMessage()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, `{"id":2}`, string(message))
+}
+
+func TestAuto(t *testing.T) {
+	for input, want := range map[string]Mode{
+		"\n{\"id\":1}\n":                      Newline,
+		"[{\"id\":1}]\n":                      Newline,
+		"Content-Length: 8\r\n\r\n{\"id\":1}": ContentLength,
+	} {
+		r := NewReader(strings.NewReader(input), Auto)
+		assert.Equal(t, Auto, r.Mode())
+		var buf bytes.Buffer
+		w := NewReplyWriter(&buf, r)
+		message, err := r.ReadMessage()
+		require.NoError(t, err, input)
+		assert.Contains(t, string(message), `{"id":1}`)
+		assert.Equal(t, want, r.Mode(), input)
+
+		_, err = w.Write([]byte("{}\n"))
+		require.NoError(t, err)
+		if want == ContentLength {
+			assert.Equal(t, "Content-Length: 2\r\n\r\n{}", buf.String())
+		} else {
+			assert.Equal(t, "{}\n", buf.String())
+		}
+	}
+
+	_, err := NewReader(strings.NewReader(""), Auto).ReadMessage()
+	assert.Equal(t, io.EOF, err)
+}
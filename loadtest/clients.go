@@ -0,0 +1,34 @@
+package loadtest
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// InProcess returns a ClientFactory connecting sessions directly to s,
+// without a transport. It measures the server's own overhead and is useful
+// to compare against a networked run. Server notifications are always
+// delivered, so Session.Listen has no effect.
+func InProcess(s *server.MCPServer) ClientFactory {
+	return func(ctx context.Context, session Session) (*client.Client, error) {
+		return client.NewInProcessClient(s)
+	}
+}
+
+// StreamableHTTP returns a ClientFactory connecting sessions to the
+// streamable HTTP server at baseURL. Listening sessions also open the
+// standalone SSE stream for server notifications. Share a
+// transport.HTTPPool through opts to bound the connections a large run
+// opens.
+func StreamableHTTP(baseURL string, opts ...transport.StreamableHTTPCOption) ClientFactory {
+	return func(ctx context.Context, session Session) (*client.Client, error) {
+		sessionOpts := opts
+		if session.Listen {
+			sessionOpts = append(sessionOpts[:len(sessionOpts):len(sessionOpts)], transport.WithContinuousListening())
+		}
+		return client.NewStreamableHttpClient(baseURL, sessionOpts...)
+	}
+}
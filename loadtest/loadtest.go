@@ -0,0 +1,275 @@
+// Package loadtest drives MCP servers with many concurrent client sessions to
+// size deployments before they go live. A Scenario describes the load: how
+// many sessions connect, how many operations per second they issue, which
+// operations, and for how long. Run executes it against any transport through
+// a ClientFactory and returns a Report with latency percentiles per operation
+// and the resources the process consumed.
+//
+//	report, err := loadtest.Run(ctx, loadtest.StreamableHTTP("http://localhost:8080/mcp"), loadtest.Scenario{
+//	    Sessions:  200,
+//	    Listeners: 50,
+//	    Rate:      1000,
+//	    Duration:  10 * time.Minute,
+//	    Operations: []loadtest.Operation{
+//	        loadtest.CallTool("search", map[string]any{"query": "mcp"}).WithWeight(8),
+//	        loadtest.TaskChurn("reindex", nil).WithWeight(1),
+//	        loadtest.ListTools().WithWeight(1),
+//	    },
+//	})
+//	fmt.Println(report)
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Session describes a session the harness asks a ClientFactory to connect.
+type Session struct {
+	// Index numbers the sessions of a run from 0.
+	Index int
+	// Listen asks for a standalone stream of server notifications, such as
+	// the SSE stream of the streamable HTTP transport.
+	Listen bool
+}
+
+// ClientFactory creates the client of a session. The harness starts and
+// initializes it, and closes it at the end of the run.
+type ClientFactory func(ctx context.Context, session Session) (*client.Client, error)
+
+// Scenario describes the load of a run.
+type Scenario struct {
+	// Sessions is the number of concurrent client sessions. Defaults to 1.
+	Sessions int
+	// Listeners is the number of sessions that also hold a notification
+	// stream (see Session.Listen).
+	Listeners int
+	// Rate is the number of operations per second issued across all
+	// sessions. Operations that come due while every session is busy are
+	// counted as dropped in the Report rather than queued, so a growing
+	// number of dropped operations means the server cannot keep up. Zero
+	// makes every session issue operations back to back.
+	Rate float64
+	// Duration is how long the load runs, including RampUp.
+	Duration time.Duration
+	// RampUp spreads the connection of the sessions over this period
+	// instead of connecting them all at once.
+	RampUp time.Duration
+	// Operations is the mix of operations each session picks from at
+	// random, in proportion to their weights. Defaults to Ping.
+	Operations []Operation
+	// ClientInfo identifies the harness's sessions to the server.
+	// Defaults to mcp-go-loadtest.
+	ClientInfo mcp.Implementation
+}
+
+// Operation is a unit of load a session issues against the server.
+type Operation struct {
+	// Name identifies the operation in the Report.
+	Name string
+	// Weight is the relative frequency of the operation in the mix.
+	// Defaults to 1.
+	Weight int
+	// Run performs the operation with the session's client.
+	Run func(ctx context.Context, c *client.Client) error
+}
+
+// WithWeight returns a copy of the operation with the given weight.
+func (o Operation) WithWeight(weight int) Operation {
+	o.Weight = weight
+	return o
+}
+
+// initializeOperation is the Report name of session initialization.
+const initializeOperation = "initialize"
+
+// Run executes scenario against the sessions newClient creates and reports
+// the results. It returns an error if the scenario is invalid or no session
+// could connect; individual failures are counted in the Report. Cancelling
+// ctx ends the run early.
+func Run(ctx context.Context, newClient ClientFactory, scenario Scenario) (*Report, error) {
+	if newClient == nil {
+		return nil, errors.New("loadtest: nil ClientFactory")
+	}
+	if scenario.Duration <= 0 {
+		return nil, errors.New("loadtest: Duration must be positive")
+	}
+	if scenario.Sessions <= 0 {
+		scenario.Sessions = 1
+	}
+	if len(scenario.Operations) == 0 {
+		scenario.Operations = []Operation{Ping()}
+	}
+	totalWeight := 0
+	for i, op := range scenario.Operations {
+		if op.Run == nil {
+			return nil, fmt.Errorf("loadtest: operation %q has no Run function", op.Name)
+		}
+		if op.Weight <= 0 {
+			scenario.Operations[i].Weight = 1
+		}
+		totalWeight += scenario.Operations[i].Weight
+	}
+	if scenario.ClientInfo.Name == "" {
+		scenario.ClientInfo = mcp.Implementation{Name: "mcp-go-loadtest", Version: "1.0.0"}
+	}
+
+	r := &run{
+		scenario:    scenario,
+		totalWeight: totalWeight,
+		stats:       make(map[string]*operationStats),
+		tokens:      make(chan struct{}),
+	}
+	r.stats[initializeOperation] = &operationStats{}
+	for _, op := range scenario.Operations {
+		if _, ok := r.stats[op.Name]; !ok {
+			r.stats[op.Name] = &operationStats{}
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, scenario.Duration)
+	defer cancel()
+	sampler := startResourceSampler(runCtx)
+	start := time.Now()
+
+	if scenario.Rate > 0 {
+		go r.dispatch(runCtx)
+	}
+	var wg sync.WaitGroup
+	for i := range scenario.Sessions {
+		wg.Go(func() {
+			if scenario.RampUp > 0 {
+				delay := scenario.RampUp * time.Duration(i) / time.Duration(scenario.Sessions)
+				select {
+				case <-time.After(delay):
+				case <-runCtx.Done():
+					return
+				}
+			}
+			r.session(runCtx, newClient, Session{Index: i, Listen: i < scenario.Listeners})
+		})
+	}
+	wg.Wait()
+
+	report := r.report(time.Since(start), sampler.stop())
+	if report.Sessions == 0 {
+		return report, fmt.Errorf("loadtest: no session connected: %s", r.stats[initializeOperation].firstError)
+	}
+	return report, nil
+}
+
+// run is the state of a running scenario.
+type run struct {
+	scenario    Scenario
+	totalWeight int
+	stats       map[string]*operationStats // fixed after Run starts the sessions
+
+	tokens        chan struct{} // paces operations when Rate is set
+	connected     atomic.Int64
+	failed        atomic.Int64
+	dropped       atomic.Int64
+	notifications atomic.Int64
+}
+
+// dispatch hands out one token per operation due at the scenario's rate.
+func (r *run) dispatch(ctx context.Context) {
+	const tick = 10 * time.Millisecond
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	start := time.Now()
+	var issued int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			due := int64(now.Sub(start).Seconds()*r.scenario.Rate) - issued
+			for ; due > 0; due-- {
+				issued++
+				select {
+				case r.tokens <- struct{}{}:
+				default:
+					r.dropped.Add(1)
+				}
+			}
+		}
+	}
+}
+
+// session connects one session and issues operations until ctx is done.
+func (r *run) session(ctx context.Context, newClient ClientFactory, session Session) {
+	c, err := r.connect(ctx, newClient, session)
+	if err != nil {
+		r.failed.Add(1)
+		return
+	}
+	defer func() { _ = c.Close() }()
+	r.connected.Add(1)
+
+	for {
+		if r.scenario.Rate > 0 {
+			select {
+			case <-r.tokens:
+			case <-ctx.Done():
+				return
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+		op := r.pick()
+		start := time.Now()
+		err := op.Run(ctx, c)
+		if err != nil && ctx.Err() != nil {
+			// Interrupted by the end of the run.
+			return
+		}
+		r.stats[op.Name].record(time.Since(start), err)
+	}
+}
+
+// connect creates, starts and initializes the client of session.
+func (r *run) connect(ctx context.Context, newClient ClientFactory, session Session) (*client.Client, error) {
+	start := time.Now()
+	c, err := newClient(ctx, session)
+	if err == nil {
+		c.OnNotification(func(mcp.JSONRPCNotification) { r.notifications.Add(1) })
+		err = c.Start(ctx)
+	}
+	if err == nil {
+		request := mcp.InitializeRequest{}
+		request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+		request.Params.ClientInfo = r.scenario.ClientInfo
+		_, err = c.Initialize(ctx, request)
+	}
+	if err != nil {
+		if c != nil {
+			_ = c.Close()
+		}
+		if ctx.Err() == nil {
+			r.stats[initializeOperation].record(time.Since(start), err)
+		}
+		return nil, err
+	}
+	r.stats[initializeOperation].record(time.Since(start), nil)
+	return c, nil
+}
+
+// pick returns a random operation of the mix.
+func (r *run) pick() Operation {
+	n := rand.IntN(r.totalWeight)
+	for _, op := range r.scenario.Operations {
+		if n < op.Weight {
+			return op
+		}
+		n -= op.Weight
+	}
+	return r.scenario.Operations[len(r.scenario.Operations)-1]
+}
@@ -0,0 +1,134 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newTestServer() *server.MCPServer {
+	s := server.NewMCPServer("loadtest", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithTaskCapabilities(true, true, true),
+	)
+	s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	s.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("failed"), nil
+	})
+	s.AddTaskTool(
+		mcp.NewTool("job", mcp.WithTaskSupport(mcp.TaskSupportRequired)),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CreateTaskResult, error) {
+			time.Sleep(time.Millisecond)
+			return &mcp.CreateTaskResult{}, nil
+		},
+	)
+	return s
+}
+
+func TestRun_InProcess(t *testing.T) {
+	report, err := Run(context.Background(), InProcess(newTestServer()), Scenario{
+		Sessions: 4,
+		Duration: 300 * time.Millisecond,
+		Operations: []Operation{
+			Ping(),
+			CallTool("echo", nil).WithWeight(2),
+			TaskChurn("job", nil),
+			CallTool("fail", nil),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Sessions)
+	assert.Zero(t, report.SessionErrors)
+	assert.Equal(t, int64(4), report.Operations["initialize"].Count)
+	for _, name := range []string{"ping", "tools/call echo", "task job"} {
+		op := report.Operations[name]
+		require.NotNil(t, op, name)
+		assert.Positive(t, op.Count, name)
+		assert.Zero(t, op.Errors, "%s: %s", name, op.FirstError)
+		assert.Positive(t, op.Throughput, name)
+		assert.LessOrEqual(t, op.Latency.Min, op.Latency.P50, name)
+		assert.LessOrEqual(t, op.Latency.P50, op.Latency.P99, name)
+		assert.LessOrEqual(t, op.Latency.P99, op.Latency.Max, name)
+	}
+	fail := report.Operations["tools/call fail"]
+	assert.Equal(t, fail.Count, fail.Errors)
+	assert.Contains(t, fail.FirstError, "error result")
+
+	var count int64
+	for name, op := range report.Operations {
+		if name != "initialize" {
+			count += op.Count
+		}
+	}
+	assert.Equal(t, count, report.Total.Count)
+	assert.Positive(t, report.Resources.PeakGoroutines)
+	assert.Positive(t, report.Resources.PeakHeapBytes)
+	assert.Contains(t, report.String(), "tools/call echo")
+}
+
+func TestRun_StreamableHTTPRate(t *testing.T) {
+	s := newTestServer()
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(s))
+	defer httpServer.Close()
+
+	report, err := Run(context.Background(), StreamableHTTP(httpServer.URL), Scenario{
+		Sessions:   3,
+		Listeners:  2,
+		Rate:       100,
+		Duration:   500 * time.Millisecond,
+		RampUp:     100 * time.Millisecond,
+		Operations: []Operation{Ping(), ListTools()},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Sessions)
+	assert.Zero(t, report.Total.Errors, report.Total.FirstError)
+	// 100 ops/s for 500ms, minus ticks lost to scheduling.
+	assert.InDelta(t, 50, report.Total.Count+report.Dropped, 15)
+}
+
+func TestRun_NoSessionConnected(t *testing.T) {
+	factory := func(ctx context.Context, session Session) (*client.Client, error) {
+		return nil, errors.New("connection refused")
+	}
+	report, err := Run(context.Background(), factory, Scenario{Sessions: 2, Duration: 50 * time.Millisecond})
+	require.ErrorContains(t, err, "connection refused")
+	assert.Equal(t, 2, report.SessionErrors)
+	assert.Equal(t, int64(2), report.Operations["initialize"].Errors)
+}
+
+func TestRun_InvalidScenario(t *testing.T) {
+	_, err := Run(context.Background(), InProcess(newTestServer()), Scenario{})
+	require.Error(t, err)
+	_, err = Run(context.Background(), InProcess(newTestServer()), Scenario{
+		Duration:   time.Second,
+		Operations: []Operation{{Name: "noop"}},
+	})
+	require.ErrorContains(t, err, "noop")
+}
+
+func TestHistogramPercentiles(t *testing.T) {
+	var h histogram
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+	summary := h.summary()
+	assert.Equal(t, time.Millisecond, summary.Min)
+	assert.Equal(t, time.Second, summary.Max)
+	assert.Equal(t, 500500*time.Microsecond, summary.Mean)
+	assert.InEpsilon(t, float64(500*time.Millisecond), float64(summary.P50), 0.05)
+	assert.InEpsilon(t, float64(900*time.Millisecond), float64(summary.P90), 0.05)
+	assert.InEpsilon(t, float64(990*time.Millisecond), float64(summary.P99), 0.05)
+}
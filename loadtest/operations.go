@@ -0,0 +1,81 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Ping returns an operation that pings the server, measuring the round trip
+// of the transport and the server's request handling.
+func Ping() Operation {
+	return Operation{
+		Name: "ping",
+		Run: func(ctx context.Context, c *client.Client) error {
+			return c.Ping(ctx)
+		},
+	}
+}
+
+// ListTools returns an operation that lists the server's tools.
+func ListTools() Operation {
+	return Operation{
+		Name: "tools/list",
+		Run: func(ctx context.Context, c *client.Client) error {
+			_, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+			return err
+		},
+	}
+}
+
+// CallTool returns an operation that calls the tool name with args. A tool
+// result with IsError set counts as a failure.
+func CallTool(name string, args map[string]any) Operation {
+	return Operation{
+		Name: "tools/call " + name,
+		Run: func(ctx context.Context, c *client.Client) error {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = name
+			request.Params.Arguments = args
+			result, err := c.CallTool(ctx, request)
+			if err != nil {
+				return err
+			}
+			if result.IsError {
+				return fmt.Errorf("tool %s returned an error result", name)
+			}
+			return nil
+		},
+	}
+}
+
+// TaskChurn returns an operation that calls the tool name with args as a
+// task and waits for the task's result, exercising the creation, tracking
+// and cleanup of tasks on the server. The tool must support task-augmented
+// calls. A task that fails or returns an error result counts as a failure.
+func TaskChurn(name string, args map[string]any) Operation {
+	return Operation{
+		Name: "task " + name,
+		Run: func(ctx context.Context, c *client.Client) error {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = name
+			request.Params.Arguments = args
+			created, err := c.CallToolAsTask(ctx, request)
+			if err != nil {
+				return err
+			}
+			result, err := c.TaskResult(ctx, mcp.TaskResultRequest{
+				Params: mcp.TaskResultParams{TaskId: created.Task.TaskId},
+			})
+			if err != nil {
+				return err
+			}
+			if result.IsError {
+				return fmt.Errorf("task %s returned an error result", created.Task.TaskId)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,355 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the result of a run.
+type Report struct {
+	// Sessions is the number of sessions that connected.
+	Sessions int
+	// SessionErrors is the number of sessions that failed to connect.
+	SessionErrors int
+	// Duration is the wall time of the run.
+	Duration time.Duration
+	// Operations holds the results of each operation by name, including
+	// the initialization of the sessions under "initialize".
+	Operations map[string]*OperationReport
+	// Total aggregates the operations of the scenario, excluding
+	// initialization.
+	Total OperationReport
+	// Dropped is the number of operations that came due at the scenario's
+	// rate while every session was busy.
+	Dropped int64
+	// Notifications is the number of server notifications the sessions
+	// received.
+	Notifications int64
+	// Resources is what the process consumed during the run. It includes
+	// the server only when the server runs in the same process, as with
+	// InProcess or an httptest server.
+	Resources ResourceUsage
+}
+
+// OperationReport is the result of one operation.
+type OperationReport struct {
+	// Count is the number of completed operations, including failures.
+	Count int64
+	// Errors is the number of failed operations.
+	Errors int64
+	// FirstError is the error of the first failure, if any.
+	FirstError string
+	// Throughput is the number of completed operations per second of the
+	// run.
+	Throughput float64
+	// Latency summarizes how long the operations took.
+	Latency LatencySummary
+}
+
+// LatencySummary summarizes a latency distribution. Percentiles are
+// accurate to within about 5%.
+type LatencySummary struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// ResourceUsage is the resource consumption of the process during a run,
+// sampled periodically.
+type ResourceUsage struct {
+	// PeakGoroutines is the highest number of goroutines observed.
+	PeakGoroutines int
+	// PeakHeapBytes is the highest heap in use observed.
+	PeakHeapBytes uint64
+	// TotalAllocBytes is the number of bytes allocated during the run.
+	TotalAllocBytes uint64
+	// GCCycles is the number of garbage collections during the run.
+	GCCycles uint32
+}
+
+// String formats the report as a table.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sessions: %d connected, %d failed  duration: %s  dropped: %d  notifications: %d\n",
+		r.Sessions, r.SessionErrors, r.Duration.Round(time.Millisecond), r.Dropped, r.Notifications)
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "operation\tcount\terrors\tops/s\tmin\tmean\tp50\tp90\tp99\tmax\t")
+	row := func(name string, op *OperationReport) {
+		l := op.Latency
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t%s\t\n", name, op.Count, op.Errors, op.Throughput,
+			formatLatency(l.Min), formatLatency(l.Mean), formatLatency(l.P50), formatLatency(l.P90), formatLatency(l.P99), formatLatency(l.Max))
+	}
+	names := make([]string, 0, len(r.Operations))
+	for name := range r.Operations {
+		if name != initializeOperation {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	if op, ok := r.Operations[initializeOperation]; ok {
+		row(initializeOperation, op)
+	}
+	for _, name := range names {
+		row(name, r.Operations[name])
+	}
+	row("total", &r.Total)
+	_ = w.Flush()
+
+	fmt.Fprintf(&b, "resources: peak goroutines %d, peak heap %s, allocated %s, GC cycles %d\n",
+		r.Resources.PeakGoroutines, formatBytes(r.Resources.PeakHeapBytes), formatBytes(r.Resources.TotalAllocBytes), r.Resources.GCCycles)
+	for _, name := range append([]string{initializeOperation}, names...) {
+		if op, ok := r.Operations[name]; ok && op.FirstError != "" {
+			fmt.Fprintf(&b, "first %s error: %s\n", name, op.FirstError)
+		}
+	}
+	return b.String()
+}
+
+func formatLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// report assembles the Report of the run.
+func (r *run) report(elapsed time.Duration, resources ResourceUsage) *Report {
+	report := &Report{
+		Sessions:      int(r.connected.Load()),
+		SessionErrors: int(r.failed.Load()),
+		Duration:      elapsed,
+		Operations:    make(map[string]*OperationReport, len(r.stats)),
+		Dropped:       r.dropped.Load(),
+		Notifications: r.notifications.Load(),
+		Resources:     resources,
+	}
+	var total operationStats
+	for name, stats := range r.stats {
+		report.Operations[name] = stats.report(elapsed)
+		if name != initializeOperation {
+			total.merge(stats)
+		}
+	}
+	report.Total = *total.report(elapsed)
+	return report
+}
+
+// operationStats accumulates the results of one operation.
+type operationStats struct {
+	mu         sync.Mutex
+	errors     int64
+	firstError string
+	latency    histogram
+}
+
+func (s *operationStats) record(d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency.record(d)
+	if err != nil {
+		s.errors++
+		if s.firstError == "" {
+			s.firstError = err.Error()
+		}
+	}
+}
+
+func (s *operationStats) merge(other *operationStats) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	s.errors += other.errors
+	if s.firstError == "" {
+		s.firstError = other.firstError
+	}
+	s.latency.merge(&other.latency)
+}
+
+func (s *operationStats) report(elapsed time.Duration) *OperationReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := &OperationReport{
+		Count:      int64(s.latency.count),
+		Errors:     s.errors,
+		FirstError: s.firstError,
+		Latency:    s.latency.summary(),
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(s.latency.count) / elapsed.Seconds()
+	}
+	return report
+}
+
+// histogramResolution is the number of buckets per doubling of latency.
+// Bucket bounds grow by a factor of 2^(1/16), about 4.4%.
+const histogramResolution = 16
+
+// histogramBuckets covers latencies from 1µs to over an hour.
+const histogramBuckets = 33 * histogramResolution
+
+// histogram records latencies in logarithmic buckets, so a run of any
+// length uses constant memory.
+type histogram struct {
+	buckets  [histogramBuckets]uint64
+	count    uint64
+	sum      time.Duration
+	min, max time.Duration
+}
+
+func (h *histogram) record(d time.Duration) {
+	h.buckets[bucketOf(d)]++
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+func (h *histogram) merge(other *histogram) {
+	if other.count == 0 {
+		return
+	}
+	for i, n := range other.buckets {
+		h.buckets[i] += n
+	}
+	if h.count == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.count += other.count
+	h.sum += other.sum
+}
+
+func (h *histogram) summary() LatencySummary {
+	if h.count == 0 {
+		return LatencySummary{}
+	}
+	return LatencySummary{
+		Min:  h.min,
+		Mean: h.sum / time.Duration(h.count),
+		P50:  h.percentile(0.50),
+		P90:  h.percentile(0.90),
+		P99:  h.percentile(0.99),
+		Max:  h.max,
+	}
+}
+
+// percentile returns the upper bound of the bucket holding the q-th
+// quantile, clamped to the observed range.
+func (h *histogram) percentile(q float64) time.Duration {
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	var seen uint64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			return min(max(bucketUpper(i), h.min), h.max)
+		}
+	}
+	return h.max
+}
+
+// bucketOf returns the bucket of d. Bucket 0 holds latencies under 1µs and
+// bucket i holds latencies in [2^((i-1)/16), 2^(i/16)) µs.
+func bucketOf(d time.Duration) int {
+	us := float64(d) / float64(time.Microsecond)
+	if us < 1 {
+		return 0
+	}
+	return min(int(math.Log2(us)*histogramResolution)+1, histogramBuckets-1)
+}
+
+func bucketUpper(i int) time.Duration {
+	return time.Duration(math.Exp2(float64(i)/histogramResolution) * float64(time.Microsecond))
+}
+
+// resourceSampleInterval is how often the process's resources are sampled
+// during a run.
+const resourceSampleInterval = 100 * time.Millisecond
+
+// resourceSampler tracks the resource consumption of the process.
+type resourceSampler struct {
+	start runtime.MemStats
+	done  chan struct{}
+
+	mu    sync.Mutex
+	usage ResourceUsage
+}
+
+func startResourceSampler(ctx context.Context) *resourceSampler {
+	s := &resourceSampler{done: make(chan struct{})}
+	runtime.ReadMemStats(&s.start)
+	s.sample(nil)
+	go func() {
+		ticker := time.NewTicker(resourceSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sample(nil)
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// sample records the current consumption, reading into stats if non-nil.
+func (s *resourceSampler) sample(stats *runtime.MemStats) {
+	if stats == nil {
+		stats = new(runtime.MemStats)
+	}
+	runtime.ReadMemStats(stats)
+	goroutines := runtime.NumGoroutine()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage.PeakGoroutines = max(s.usage.PeakGoroutines, goroutines)
+	s.usage.PeakHeapBytes = max(s.usage.PeakHeapBytes, stats.HeapInuse)
+}
+
+// stop takes a last sample and returns the consumption since the start.
+func (s *resourceSampler) stop() ResourceUsage {
+	close(s.done)
+	var end runtime.MemStats
+	s.sample(&end)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage := s.usage
+	usage.TotalAllocBytes = end.TotalAlloc - s.start.TotalAlloc
+	usage.GCCycles = end.NumGC - s.start.NumGC
+	return usage
+}
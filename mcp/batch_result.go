@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ItemStatus is the outcome of one item of a batch tool call.
+type ItemStatus string
+
+const (
+	ItemStatusOK      ItemStatus = "ok"
+	ItemStatusError   ItemStatus = "error"
+	ItemStatusSkipped ItemStatus = "skipped"
+)
+
+// ItemResult is the outcome of one item of a batch tool call, such as one
+// file of a bulk upload.
+type ItemResult struct {
+	// ID identifies the item within the batch, such as its index or the
+	// key the client gave it.
+	ID     string     `json:"id"`
+	Status ItemStatus `json:"status"`
+	// Result is the item's output, for items that succeeded.
+	Result any `json:"result,omitempty"`
+	// Error describes why the item failed or was skipped.
+	Error *ItemError `json:"error,omitempty"`
+}
+
+// ItemError describes the failure of one item of a batch.
+type ItemError struct {
+	Message string `json:"message"`
+	// Code is a machine-readable reason, such as "not_found".
+	Code string `json:"code,omitempty"`
+	// Retryable reports whether retrying the item may succeed.
+	Retryable bool `json:"retryable,omitempty"`
+}
+
+// ItemOK returns the result of an item that succeeded.
+func ItemOK(id string, result any) ItemResult {
+	return ItemResult{ID: id, Status: ItemStatusOK, Result: result}
+}
+
+// ItemFailed returns the result of an item that failed with err.
+func ItemFailed(id string, err error) ItemResult {
+	message := "unknown error"
+	if err != nil {
+		message = err.Error()
+	}
+	return ItemResult{ID: id, Status: ItemStatusError, Error: &ItemError{Message: message}}
+}
+
+// ItemSkipped returns the result of an item that was not processed, for
+// example because an earlier item failed.
+func ItemSkipped(id, reason string) ItemResult {
+	return ItemResult{ID: id, Status: ItemStatusSkipped, Error: &ItemError{Message: reason}}
+}
+
+// BatchSummary counts the items of a batch by status.
+type BatchSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// BatchResult is the structured content of a batch tool result: the outcome
+// of every item and their counts.
+type BatchResult struct {
+	Items   []ItemResult `json:"items"`
+	Summary BatchSummary `json:"summary"`
+}
+
+// Failed returns the items that failed.
+func (b *BatchResult) Failed() []ItemResult {
+	return b.withStatus(ItemStatusError)
+}
+
+// Succeeded returns the items that succeeded.
+func (b *BatchResult) Succeeded() []ItemResult {
+	return b.withStatus(ItemStatusOK)
+}
+
+func (b *BatchResult) withStatus(status ItemStatus) []ItemResult {
+	var items []ItemResult
+	for _, item := range b.Items {
+		if item.Status == status {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// NewBatchResult creates a CallToolResult reporting the outcome of each item
+// of a batch. The structured content is a BatchResult, the text content a
+// summary listing the failed items. The result is an error only if every
+// item failed; partial failures are reported per item, so that clients can
+// retry just those.
+func NewBatchResult(items []ItemResult) *CallToolResult {
+	batch := BatchResult{Items: items, Summary: BatchSummary{Total: len(items)}}
+	if batch.Items == nil {
+		batch.Items = []ItemResult{}
+	}
+	var failures []string
+	for _, item := range items {
+		switch item.Status {
+		case ItemStatusOK:
+			batch.Summary.Succeeded++
+		case ItemStatusSkipped:
+			batch.Summary.Skipped++
+		default:
+			batch.Summary.Failed++
+			if item.Error != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", item.ID, item.Error.Message))
+			} else {
+				failures = append(failures, item.ID)
+			}
+		}
+	}
+
+	s := batch.Summary
+	text := fmt.Sprintf("%d of %d items succeeded", s.Succeeded, s.Total)
+	if s.Failed > 0 {
+		text += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	if s.Skipped > 0 {
+		text += fmt.Sprintf(", %d skipped", s.Skipped)
+	}
+	if len(failures) > 0 {
+		text += "\n" + strings.Join(failures, "\n")
+	}
+	return &CallToolResult{
+		Content:           []Content{NewTextContent(text)},
+		StructuredContent: batch,
+		IsError:           s.Total > 0 && s.Failed == s.Total,
+	}
+}
+
+// ErrNotBatchResult is returned by ParseBatchResult for results without
+// batch structured content.
+var ErrNotBatchResult = errors.New("tool result is not a batch result")
+
+// ParseBatchResult returns the BatchResult in the structured content of a
+// tool result created with NewBatchResult, on either side of the
+// connection.
+func ParseBatchResult(result *CallToolResult) (*BatchResult, error) {
+	if result == nil || result.StructuredContent == nil {
+		return nil, ErrNotBatchResult
+	}
+	switch batch := result.StructuredContent.(type) {
+	case BatchResult:
+		return &batch, nil
+	case *BatchResult:
+		return batch, nil
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotBatchResult, err)
+	}
+	var batch struct {
+		BatchResult
+		Items *[]ItemResult `json:"items"`
+	}
+	if err := json.Unmarshal(data, &batch); err != nil || batch.Items == nil {
+		return nil, ErrNotBatchResult
+	}
+	batch.BatchResult.Items = *batch.Items
+	return &batch.BatchResult, nil
+}
+
+// ParseItemResult decodes the Result of an item into T.
+func ParseItemResult[T any](item ItemResult) (T, error) {
+	var v T
+	if item.Result == nil {
+		return v, nil
+	}
+	if typed, ok := item.Result.(T); ok {
+		return typed, nil
+	}
+	data, err := json.Marshal(item.Result)
+	if err != nil {
+		return v, err
+	}
+	err = json.Unmarshal(data, &v)
+	return v, err
+}
+
+// WithBatchOutputSchema sets the tool's output schema to the shape of the
+// results of NewBatchResult.
+func WithBatchOutputSchema() ToolOption {
+	return WithRawOutputSchema(json.RawMessage(batchResultSchema))
+}
+
+const batchResultSchema = `{
+  "type": "object",
+  "properties": {
+    "items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "status": {"type": "string", "enum": ["ok", "error", "skipped"]},
+          "result": {},
+          "error": {
+            "type": "object",
+            "properties": {
+              "message": {"type": "string"},
+              "code": {"type": "string"},
+              "retryable": {"type": "boolean"}
+            },
+            "required": ["message"]
+          }
+        },
+        "required": ["id", "status"]
+      }
+    },
+    "summary": {
+      "type": "object",
+      "properties": {
+        "total": {"type": "integer"},
+        "succeeded": {"type": "integer"},
+        "failed": {"type": "integer"},
+        "skipped": {"type": "integer"}
+      },
+      "required": ["total", "succeeded", "failed", "skipped"]
+    }
+  },
+  "required": ["items", "summary"]
+}`
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBatchResult(t *testing.T) {
+	result := NewBatchResult([]ItemResult{
+		ItemOK("a.txt", map[string]any{"bytes": 12}),
+		ItemFailed("b.txt", errors.New("permission denied")),
+		ItemSkipped("c.txt", "quota reached"),
+	})
+
+	assert.False(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "1 of 3 items succeeded, 1 failed, 1 skipped\nb.txt: permission denied",
+		result.Content[0].(TextContent).Text)
+
+	batch, err := ParseBatchResult(result)
+	require.NoError(t, err)
+	assert.Equal(t, BatchSummary{Total: 3, Succeeded: 1, Failed: 1, Skipped: 1}, batch.Summary)
+	require.Len(t, batch.Failed(), 1)
+	assert.Equal(t, "b.txt", batch.Failed()[0].ID)
+	require.Len(t, batch.Succeeded(), 1)
+}
+
+func TestNewBatchResult_AllFailed(t *testing.T) {
+	result := NewBatchResult([]ItemResult{ItemFailed("a", nil)})
+	assert.True(t, result.IsError)
+
+	result = NewBatchResult(nil)
+	assert.False(t, result.IsError)
+	batch, err := ParseBatchResult(result)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Items)
+}
+
+func TestParseBatchResult_RoundTrip(t *testing.T) {
+	type upload struct {
+		Bytes int `json:"bytes"`
+	}
+	data, err := json.Marshal(NewBatchResult([]ItemResult{
+		ItemOK("a.txt", upload{Bytes: 12}),
+		{ID: "b.txt", Status: ItemStatusError, Error: &ItemError{Message: "busy", Code: "locked", Retryable: true}},
+	}))
+	require.NoError(t, err)
+	raw := json.RawMessage(data)
+	result, err := ParseCallToolResult(&raw)
+	require.NoError(t, err)
+
+	batch, err := ParseBatchResult(result)
+	require.NoError(t, err)
+	assert.Equal(t, BatchSummary{Total: 2, Succeeded: 1, Failed: 1}, batch.Summary)
+	got, err := ParseItemResult[upload](batch.Items[0])
+	require.NoError(t, err)
+	assert.Equal(t, upload{Bytes: 12}, got)
+	assert.Equal(t, &ItemError{Message: "busy", Code: "locked", Retryable: true}, batch.Items[1].Error)
+}
+
+func TestParseBatchResult_NotBatch(t *testing.T) {
+	_, err := ParseBatchResult(NewToolResultText("hi"))
+	assert.ErrorIs(t, err, ErrNotBatchResult)
+	_, err = ParseBatchResult(NewToolResultStructuredOnly(map[string]any{"count": 1}))
+	assert.ErrorIs(t, err, ErrNotBatchResult)
+}
+
+func TestWithBatchOutputSchema(t *testing.T) {
+	tool := NewTool("upload", WithBatchOutputSchema())
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(tool.RawOutputSchema, &schema))
+	assert.Equal(t, "object", schema["type"])
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ToolCacheabilityMetaKey is the Tool _meta key under which a tool's
+// cacheability is published, so clients and HTTP caches in front of a
+// stateless server know how long a result may be reused.
+const ToolCacheabilityMetaKey = "io.github.mark3labs.mcp-go/cacheability"
+
+// ToolCacheability declares that successful results of a tool depend only on
+// its arguments for a while, so identical calls may be answered from a
+// cache. Results with IsError set are never cacheable.
+type ToolCacheability struct {
+	// MaxAgeSeconds is how long a result may be reused.
+	MaxAgeSeconds int64 `json:"maxAgeSeconds"`
+	// Private restricts reuse to the calling client: shared caches such as
+	// CDNs must not store the result.
+	Private bool `json:"private,omitempty"`
+}
+
+// MaxAge returns MaxAgeSeconds as a duration.
+func (c ToolCacheability) MaxAge() time.Duration {
+	return time.Duration(c.MaxAgeSeconds) * time.Second
+}
+
+// WithCacheability marks the tool's results as reusable for maxAge and
+// publishes it in the tool's _meta under ToolCacheabilityMetaKey. Use
+// WithToolCacheability to restrict reuse to the calling client.
+func WithCacheability(maxAge time.Duration) ToolOption {
+	return WithToolCacheability(ToolCacheability{MaxAgeSeconds: int64(maxAge / time.Second)})
+}
+
+// WithToolCacheability attaches cacheability to the tool and publishes it in
+// the tool's _meta under ToolCacheabilityMetaKey.
+func WithToolCacheability(cacheability ToolCacheability) ToolOption {
+	return func(t *Tool) {
+		if t.Meta == nil {
+			t.Meta = &Meta{}
+		}
+		if t.Meta.AdditionalFields == nil {
+			t.Meta.AdditionalFields = make(map[string]any)
+		}
+		t.Meta.AdditionalFields[ToolCacheabilityMetaKey] = cacheability
+	}
+}
+
+// ToolCacheabilityFromTool returns the cacheability of tool, whether it was
+// set with WithToolCacheability or decoded from a tools/list response.
+func ToolCacheabilityFromTool(tool Tool) (ToolCacheability, bool) {
+	if tool.Meta == nil {
+		return ToolCacheability{}, false
+	}
+	switch v := tool.Meta.AdditionalFields[ToolCacheabilityMetaKey].(type) {
+	case ToolCacheability:
+		return v, true
+	case nil:
+		return ToolCacheability{}, false
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ToolCacheability{}, false
+		}
+		var cacheability ToolCacheability
+		if err := json.Unmarshal(data, &cacheability); err != nil {
+			return ToolCacheability{}, false
+		}
+		return cacheability, true
+	}
+}
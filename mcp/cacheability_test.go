@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCacheability(t *testing.T) {
+	tool := NewTool("weather", WithCacheability(5*time.Minute))
+
+	got, ok := ToolCacheabilityFromTool(tool)
+	require.True(t, ok)
+	assert.Equal(t, ToolCacheability{MaxAgeSeconds: 300}, got)
+	assert.Equal(t, 5*time.Minute, got.MaxAge())
+
+	// The cacheability survives a round trip through tools/list.
+	data, err := json.Marshal(tool)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"io.github.mark3labs.mcp-go/cacheability":{"maxAgeSeconds":300}`)
+	var decoded Tool
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	got, ok = ToolCacheabilityFromTool(decoded)
+	require.True(t, ok)
+	assert.Equal(t, ToolCacheability{MaxAgeSeconds: 300}, got)
+
+	_, ok = ToolCacheabilityFromTool(NewTool("plain"))
+	assert.False(t, ok)
+}
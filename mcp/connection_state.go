@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnectionState is the health of the connection between a client and a
+// server, as seen by either side.
+type ConnectionState string
+
+const (
+	// ConnectionConnecting is the state until initialization completes.
+	ConnectionConnecting ConnectionState = "connecting"
+	// ConnectionReady means the connection is initialized and healthy.
+	ConnectionReady ConnectionState = "ready"
+	// ConnectionDegraded means the connection is initialized but the
+	// transport failed to deliver a message, such as a lost stream or a
+	// full notification queue. It returns to ready once a message goes
+	// through again.
+	ConnectionDegraded ConnectionState = "degraded"
+	// ConnectionReconnecting means the transport is being re-established.
+	ConnectionReconnecting ConnectionState = "reconnecting"
+	// ConnectionClosed means the connection was closed. It is left only if
+	// the connection is re-established.
+	ConnectionClosed ConnectionState = "closed"
+)
+
+// ConnectionStateChange describes a transition between connection states.
+type ConnectionStateChange struct {
+	From ConnectionState
+	To   ConnectionState
+	// Reason is the error that caused the transition, or nil for
+	// transitions that are not caused by a failure, such as completing
+	// initialization or closing.
+	Reason error
+	At     time.Time
+}
+
+// ConnectionStateMachine holds a connection state and notifies subscribers
+// of its transitions. The zero value is in ConnectionConnecting and ready
+// for use.
+type ConnectionStateMachine struct {
+	mu          sync.Mutex
+	state       ConnectionState
+	subscribers []func(ConnectionStateChange)
+}
+
+// State returns the current state.
+func (m *ConnectionStateMachine) State() ConnectionState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current()
+}
+
+func (m *ConnectionStateMachine) current() ConnectionState {
+	if m.state == "" {
+		return ConnectionConnecting
+	}
+	return m.state
+}
+
+// OnChange registers fn to be called after each transition, in the order
+// the functions were registered. It is called on the goroutine making the
+// transition.
+func (m *ConnectionStateMachine) OnChange(fn func(ConnectionStateChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Transition moves to state to for reason. It reports false, without
+// notifying subscribers, if the state is already to.
+func (m *ConnectionStateMachine) Transition(to ConnectionState, reason error) bool {
+	return m.transition(nil, to, reason)
+}
+
+// TransitionFrom moves from state from to state to for reason. It reports
+// false if the current state is not from.
+func (m *ConnectionStateMachine) TransitionFrom(from, to ConnectionState, reason error) bool {
+	return m.transition(&from, to, reason)
+}
+
+func (m *ConnectionStateMachine) transition(from *ConnectionState, to ConnectionState, reason error) bool {
+	m.mu.Lock()
+	current := m.current()
+	if current == to || (from != nil && current != *from) {
+		m.mu.Unlock()
+		return false
+	}
+	m.state = to
+	subscribers := m.subscribers
+	m.mu.Unlock()
+
+	change := ConnectionStateChange{From: current, To: to, Reason: reason, At: time.Now()}
+	for _, fn := range subscribers {
+		fn(change)
+	}
+	return true
+}
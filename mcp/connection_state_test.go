@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionStateMachine(t *testing.T) {
+	var m ConnectionStateMachine
+	assert.Equal(t, ConnectionConnecting, m.State())
+
+	var changes []ConnectionStateChange
+	m.OnChange(func(change ConnectionStateChange) { changes = append(changes, change) })
+
+	lost := errors.New("stream lost")
+	assert.True(t, m.Transition(ConnectionReady, nil))
+	assert.False(t, m.Transition(ConnectionReady, nil), "no transition to the current state")
+	assert.False(t, m.TransitionFrom(ConnectionDegraded, ConnectionReady, nil))
+	assert.True(t, m.TransitionFrom(ConnectionReady, ConnectionDegraded, lost))
+	assert.True(t, m.Transition(ConnectionClosed, nil))
+	assert.Equal(t, ConnectionClosed, m.State())
+
+	if assert.Len(t, changes, 3) {
+		assert.Equal(t, ConnectionConnecting, changes[0].From)
+		assert.Equal(t, ConnectionReady, changes[0].To)
+		assert.Equal(t, ConnectionDegraded, changes[1].To)
+		assert.Equal(t, lost, changes[1].Reason)
+		assert.False(t, changes[1].At.IsZero())
+		assert.Equal(t, ConnectionDegraded, changes[2].From)
+	}
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import "slices"
+
+// ContentOption configures the annotations of content built by
+// NewTextContent, NewImageContent, NewAudioContent, NewResourceLink and
+// NewEmbeddedResource.
+type ContentOption func(*Annotated)
+
+// WithContentAnnotations sets the audience, priority and last modified
+// timestamp of the content, like WithAnnotations does for resources.
+// lastModified is an ISO 8601 timestamp and may be empty.
+func WithContentAnnotations(audience []Role, priority float64, lastModified string) ContentOption {
+	return func(a *Annotated) {
+		annotations := a.ensureAnnotations()
+		annotations.Audience = audience
+		annotations.Priority = &priority
+		annotations.LastModified = lastModified
+	}
+}
+
+// WithContentAudience sets who the content is intended for, e.g. only the
+// user for content the model does not need to see.
+func WithContentAudience(audience ...Role) ContentOption {
+	return func(a *Annotated) {
+		a.ensureAnnotations().Audience = audience
+	}
+}
+
+// WithContentPriority sets how important the content is, from 0 (entirely
+// optional) to 1 (effectively required).
+func WithContentPriority(priority float64) ContentOption {
+	return func(a *Annotated) {
+		a.ensureAnnotations().Priority = &priority
+	}
+}
+
+func (a *Annotated) ensureAnnotations() *Annotations {
+	if a.Annotations == nil {
+		a.Annotations = &Annotations{}
+	}
+	return a.Annotations
+}
+
+func (a Annotated) annotations() *Annotations {
+	return a.Annotations
+}
+
+func applyContentOptions(a *Annotated, opts []ContentOption) {
+	for _, opt := range opts {
+		opt(a)
+	}
+}
+
+// GetContentAnnotations returns the annotations of content, or nil if it
+// has none.
+func GetContentAnnotations(content Content) *Annotations {
+	if c, ok := content.(interface{ annotations() *Annotations }); ok {
+		return c.annotations()
+	}
+	return nil
+}
+
+// IsContentForAudience reports whether content is intended for role.
+// Content without an audience annotation is intended for everyone.
+func IsContentForAudience(content Content, role Role) bool {
+	annotations := GetContentAnnotations(content)
+	if annotations == nil || len(annotations.Audience) == 0 {
+		return true
+	}
+	return slices.Contains(annotations.Audience, role)
+}
+
+// FilterContentByAudience returns the content intended for role, keeping
+// its order. Content without an audience annotation is always kept.
+func FilterContentByAudience(content []Content, role Role) []Content {
+	filtered := make([]Content, 0, len(content))
+	for _, c := range content {
+		if IsContentForAudience(c, role) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// ContentForAudience returns the result's content intended for role, e.g.
+// RoleAssistant for what to pass to the model and RoleUser for what to
+// display.
+func (r *CallToolResult) ContentForAudience(role Role) []Content {
+	if r == nil {
+		return nil
+	}
+	return FilterContentByAudience(r.Content, role)
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentOptions(t *testing.T) {
+	text := NewTextContent("for the user",
+		WithContentAnnotations([]Role{RoleUser}, 0.8, "2025-01-12T15:00:58Z"),
+	)
+	data, err := json.Marshal(text)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "text",
+		"text": "for the user",
+		"annotations": {"audience": ["user"], "priority": 0.8, "lastModified": "2025-01-12T15:00:58Z"}
+	}`, string(data))
+
+	image := NewImageContent("aGVsbG8=", "image/png", WithContentAudience(RoleAssistant), WithContentPriority(0.2))
+	require.NotNil(t, image.Annotations)
+	assert.Equal(t, []Role{RoleAssistant}, image.Annotations.Audience)
+	assert.Equal(t, 0.2, *image.Annotations.Priority)
+
+	assert.Nil(t, NewTextContent("plain").Annotations)
+	assert.NotNil(t, NewAudioContent("", "audio/wav", WithContentPriority(1)).Annotations)
+	assert.NotNil(t, NewResourceLink("file:///a", "a", "", "", WithContentAudience(RoleUser)).Annotations)
+	assert.NotNil(t, NewEmbeddedResource(TextResourceContents{URI: "file:///a"}, WithContentAudience(RoleUser)).Annotations)
+}
+
+func TestFilterContentByAudience(t *testing.T) {
+	userOnly := NewTextContent("user", WithContentAudience(RoleUser))
+	assistantOnly := NewTextContent("assistant", WithContentAudience(RoleAssistant))
+	both := NewImageContent("", "image/png", WithContentAudience(RoleUser, RoleAssistant))
+	everyone := NewTextContent("everyone")
+	pointer := &TextContent{Type: ContentTypeText, Text: "pointer", Annotated: Annotated{Annotations: &Annotations{Audience: []Role{RoleUser}}}}
+
+	result := &CallToolResult{Content: []Content{userOnly, assistantOnly, both, everyone, pointer}}
+
+	tests := []struct {
+		name string
+		role Role
+		want []Content
+	}{
+		{name: "user", role: RoleUser, want: []Content{userOnly, both, everyone, pointer}},
+		{name: "assistant", role: RoleAssistant, want: []Content{assistantOnly, both, everyone}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, result.ContentForAudience(tt.role))
+			assert.Equal(t, tt.want, FilterContentByAudience(result.Content, tt.role))
+		})
+	}
+
+	assert.Nil(t, (*CallToolResult)(nil).ContentForAudience(RoleUser))
+	assert.Nil(t, GetContentAnnotations(everyone))
+	assert.Equal(t, []Role{RoleUser}, GetContentAnnotations(pointer).Audience)
+}
+
+func TestFilterContentByAudience_ParsedResult(t *testing.T) {
+	raw := json.RawMessage(`{"content":[
+		{"type":"text","text":"for the model","annotations":{"audience":["assistant"]}},
+		{"type":"text","text":"for the user","annotations":{"audience":["user"]}}
+	]}`)
+	result, err := ParseCallToolResult(&raw)
+	require.NoError(t, err)
+
+	content := result.ContentForAudience(RoleUser)
+	require.Len(t, content, 1)
+	assert.Equal(t, "for the user", content[0].(TextContent).Text)
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// NewElicitationRequest creates a form mode elicitation request whose
+// requestedSchema is derived from the Go struct T, following the same rules
+// as [NewToolFromStruct]. Elicitation forms only support flat objects with
+// primitive properties, so T should not contain nested structs or slices
+// other than enums. The generated schema is reduced to that subset: pointer
+// fields get their primitive type instead of a ["null", type] union, and
+// keywords clients do not understand, such as additionalProperties, are
+// dropped.
+//
+// Pair it with server.RequestTypedElicitation, or decode the accepted
+// content with [ParseElicitationContent], to get the user's answer back as
+// a T.
+func NewElicitationRequest[T any](message string) ElicitationRequest {
+	request := ElicitationRequest{
+		Request: Request{
+			Method: string(MethodElicitationCreate),
+		},
+		Params: ElicitationParams{
+			Mode:    ElicitationModeForm,
+			Message: message,
+		},
+	}
+	raw, err := structSchemaFor[T]()
+	if err != nil {
+		slog.Error("mcp: cannot generate elicitation schema", "error", err)
+		return request
+	}
+	// Decode the schema so handlers inspecting it, such as in-process
+	// clients, see the same map[string]any they would get over the wire.
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		slog.Error("mcp: cannot decode elicitation schema", "error", err)
+		return request
+	}
+	request.Params.RequestedSchema = elicitationSchema(schema)
+	return request
+}
+
+// elicitationPropertyKeys are the keywords allowed in the properties of an
+// elicitation schema.
+var elicitationPropertyKeys = map[string]bool{
+	"type": true, "title": true, "description": true, "default": true,
+	"format": true, "enum": true, "enumNames": true,
+	"minLength": true, "maxLength": true, "minimum": true, "maximum": true,
+}
+
+// elicitationSchema reduces a schema generated from a struct to the subset
+// of JSON Schema allowed in elicitation requests.
+func elicitationSchema(schema map[string]any) map[string]any {
+	out := map[string]any{"type": "object"}
+	if required, ok := schema["required"]; ok {
+		out["required"] = required
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	outProperties := make(map[string]any, len(properties))
+	for name, p := range properties {
+		prop, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		outProp := make(map[string]any, len(prop))
+		for key, value := range prop {
+			if elicitationPropertyKeys[key] {
+				outProp[key] = value
+			}
+		}
+		if types, ok := prop["type"].([]any); ok {
+			delete(outProp, "type")
+			for _, t := range types {
+				if t != "null" {
+					outProp["type"] = t
+					break
+				}
+			}
+		}
+		outProperties[name] = outProp
+	}
+	out["properties"] = outProperties
+	return out
+}
+
+// ParseElicitationContent decodes the content of an accepted elicitation
+// into T. It returns an error if the user did not accept the request or the
+// content does not match T.
+func ParseElicitationContent[T any](result *ElicitationResult) (T, error) {
+	var content T
+	if result == nil {
+		return content, fmt.Errorf("elicitation result is nil")
+	}
+	if result.Action != ElicitationResponseActionAccept {
+		return content, fmt.Errorf("elicitation was not accepted: %s", result.Action)
+	}
+	raw, err := json.Marshal(result.Content)
+	if err != nil {
+		return content, fmt.Errorf("marshal elicitation content: %w", err)
+	}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return content, fmt.Errorf("decode elicitation content: %w", err)
+	}
+	return content, nil
+}
+
+// NewElicitationAcceptResult returns the result of an elicitation the user
+// accepted, with the values they entered.
+func NewElicitationAcceptResult(content any) *ElicitationResult {
+	return &ElicitationResult{
+		ElicitationResponse: ElicitationResponse{
+			Action:  ElicitationResponseActionAccept,
+			Content: content,
+		},
+	}
+}
+
+// NewElicitationDeclineResult returns the result of an elicitation the user
+// explicitly declined.
+func NewElicitationDeclineResult() *ElicitationResult {
+	return &ElicitationResult{
+		ElicitationResponse: ElicitationResponse{Action: ElicitationResponseActionDecline},
+	}
+}
+
+// NewElicitationDeclineResultWithReason returns the result of an
+// elicitation the user explicitly declined, for the given reason.
+func NewElicitationDeclineResultWithReason(reason ElicitationDeclineReason) *ElicitationResult {
+	return &ElicitationResult{
+		ElicitationResponse: ElicitationResponse{
+			Action: ElicitationResponseActionDecline,
+			Reason: reason,
+		},
+	}
+}
+
+// NewElicitationCancelResult returns the result of an elicitation the user
+// dismissed without making a choice.
+func NewElicitationCancelResult() *ElicitationResult {
+	return &ElicitationResult{
+		ElicitationResponse: ElicitationResponse{Action: ElicitationResponseActionCancel},
+	}
+}
+
+// NewElicitationCancelResultWithReason returns the result of an elicitation
+// the user dismissed without making a choice, for the given reason.
+func NewElicitationCancelResultWithReason(reason ElicitationDeclineReason) *ElicitationResult {
+	return &ElicitationResult{
+		ElicitationResponse: ElicitationResponse{
+			Action: ElicitationResponseActionCancel,
+			Reason: reason,
+		},
+	}
+}
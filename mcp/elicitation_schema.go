@@ -0,0 +1,136 @@
+package mcp
+
+import "maps"
+
+//
+// Elicitation Form Builders
+//
+
+// ElicitationField adds a field to an elicitation form built with
+// NewElicitationSchema. The field constructors below accept the same
+// PropertyOptions as tool parameters, such as Required, Title, Description
+// and DefaultString.
+type ElicitationField func(schema map[string]any)
+
+// NewElicitationSchema builds the requestedSchema of a form mode
+// elicitation from fields, in the flat object shape elicitation forms
+// require.
+//
+// Example:
+//
+//	schema := mcp.NewElicitationSchema(
+//	    mcp.EmailField("email", mcp.Required()),
+//	    mcp.SelectField("plan", []string{"free", "pro"}, mcp.DefaultString("free")),
+//	    mcp.ConfirmField("agree", mcp.Description("Accept the terms of service")),
+//	)
+func NewElicitationSchema(fields ...ElicitationField) map[string]any {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{},
+	}
+	for _, field := range fields {
+		field(schema)
+	}
+	return schema
+}
+
+// NewElicitationFormRequest creates a form mode elicitation request asking
+// the user for fields.
+func NewElicitationFormRequest(message string, fields ...ElicitationField) ElicitationRequest {
+	return ElicitationRequest{
+		Request: Request{
+			Method: string(MethodElicitationCreate),
+		},
+		Params: ElicitationParams{
+			Mode:            ElicitationModeForm,
+			Message:         message,
+			RequestedSchema: NewElicitationSchema(fields...),
+		},
+	}
+}
+
+// newElicitationField returns a field with the given base property schema,
+// customized by opts.
+func newElicitationField(name string, base map[string]any, opts []PropertyOption) ElicitationField {
+	return func(schema map[string]any) {
+		property := maps.Clone(base)
+		for _, opt := range opts {
+			opt(property)
+		}
+
+		// Move required from the property to the form's required list
+		if required, ok := property["required"].(bool); ok {
+			delete(property, "required")
+			if required {
+				names, _ := schema["required"].([]string)
+				schema["required"] = append(names, name)
+			}
+		}
+
+		schema["properties"].(map[string]any)[name] = property
+	}
+}
+
+// TextField adds a free text field.
+func TextField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "string"}, opts)
+}
+
+// NumberField adds a number field.
+func NumberField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "number"}, opts)
+}
+
+// IntegerField adds a whole number field.
+func IntegerField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "integer"}, opts)
+}
+
+// BooleanField adds a yes/no field.
+func BooleanField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "boolean"}, opts)
+}
+
+// ConfirmField adds a required yes/no field asking the user to confirm an
+// action, unchecked by default.
+func ConfirmField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{
+		"type":     "boolean",
+		"default":  false,
+		"required": true,
+	}, opts)
+}
+
+// EmailField adds a field for an email address.
+func EmailField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "string", "format": "email"}, opts)
+}
+
+// URLField adds a field for an absolute URL.
+func URLField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "string", "format": "uri"}, opts)
+}
+
+// SecretField adds a field for a secret, such as an API key. It is marked
+// writeOnly, hinting clients to mask the input and not to display or log
+// the value.
+//
+// Elicitation is not meant for sensitive credentials; prefer URL mode
+// elicitation for those where the specification requires it.
+func SecretField(name string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "string", "writeOnly": true}, opts)
+}
+
+// SelectField adds a field letting the user choose one of options.
+// Use EnumNames to give the options display labels.
+func SelectField(name string, options []string, opts ...PropertyOption) ElicitationField {
+	return newElicitationField(name, map[string]any{"type": "string", "enum": options}, opts)
+}
+
+// EnumNames sets display labels for the values of an enum property, in the
+// same order as the values.
+func EnumNames(names ...string) PropertyOption {
+	return func(schema map[string]any) {
+		schema["enumNames"] = names
+	}
+}
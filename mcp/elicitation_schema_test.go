@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewElicitationSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []ElicitationField
+		expected map[string]any
+	}{
+		{
+			name:   "empty form",
+			fields: nil,
+			expected: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+		{
+			name: "presets",
+			fields: []ElicitationField{
+				EmailField("email", Required(), Title("Email")),
+				URLField("website"),
+				SecretField("apiKey", Required()),
+				SelectField("plan", []string{"free", "pro"}, EnumNames("Free", "Pro"), DefaultString("free")),
+			},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"email":   map[string]any{"type": "string", "format": "email", "title": "Email"},
+					"website": map[string]any{"type": "string", "format": "uri"},
+					"apiKey":  map[string]any{"type": "string", "writeOnly": true},
+					"plan": map[string]any{
+						"type":      "string",
+						"enum":      []string{"free", "pro"},
+						"enumNames": []string{"Free", "Pro"},
+						"default":   "free",
+					},
+				},
+				"required": []string{"email", "apiKey"},
+			},
+		},
+		{
+			name: "confirm field is required and unchecked",
+			fields: []ElicitationField{
+				ConfirmField("proceed", Description("Continue?")),
+				IntegerField("count", Min(1)),
+			},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"proceed": map[string]any{"type": "boolean", "default": false, "description": "Continue?"},
+					"count":   map[string]any{"type": "integer", "minimum": 1},
+				},
+				"required": []string{"proceed"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewElicitationSchema(tt.fields...))
+		})
+	}
+}
+
+func TestElicitationField_Reusable(t *testing.T) {
+	email := EmailField("email", Required())
+	first := NewElicitationSchema(email)
+	second := NewElicitationSchema(email, TextField("name"))
+
+	first["properties"].(map[string]any)["email"].(map[string]any)["title"] = "Changed"
+	assert.NotContains(t, second["properties"].(map[string]any)["email"], "title")
+	assert.Equal(t, []string{"email"}, first["required"])
+	assert.Equal(t, []string{"email"}, second["required"])
+}
+
+func TestNewElicitationFormRequest(t *testing.T) {
+	request := NewElicitationFormRequest("Confirm deletion", ConfirmField("confirm"))
+	assert.Equal(t, string(MethodElicitationCreate), request.Method)
+	assert.Equal(t, ElicitationModeForm, request.Params.Mode)
+	assert.Equal(t, "Confirm deletion", request.Params.Message)
+	assert.NoError(t, request.Params.Validate())
+}
@@ -92,3 +92,95 @@ func TestElicitationCapabilitySerialization(t *testing.T) {
 	require.NoError(t, err)
 	assert.JSONEq(t, `{"url":{}}`, string(data))
 }
+
+func TestNewElicitationRequest(t *testing.T) {
+	type contact struct {
+		Email   string `json:"email" jsonschema:"Where to reach you" minLength:"3"`
+		Channel string `json:"channel,omitempty" enum:"email,phone"`
+		Updates *bool  `json:"updates,omitempty" default:"true"`
+		Age     *int   `json:"age,omitempty" minimum:"18"`
+	}
+
+	request := NewElicitationRequest[contact]("How can we reach you?")
+	assert.Equal(t, string(MethodElicitationCreate), request.Method)
+	assert.Equal(t, ElicitationModeForm, request.Params.Mode)
+	assert.Equal(t, "How can we reach you?", request.Params.Message)
+	require.NoError(t, request.Params.Validate())
+
+	data, err := json.Marshal(request.Params.RequestedSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string", "description": "Where to reach you", "minLength": 3},
+			"channel": {"type": "string", "enum": ["email", "phone"]},
+			"updates": {"type": "boolean", "default": true},
+			"age": {"type": "integer", "minimum": 18}
+		},
+		"required": ["email"]
+	}`, string(data))
+}
+
+func TestParseElicitationContent(t *testing.T) {
+	type answer struct {
+		Confirm bool `json:"confirm"`
+	}
+
+	tests := []struct {
+		name    string
+		result  *ElicitationResult
+		want    answer
+		wantErr string
+	}{
+		{
+			name:   "accepted",
+			result: &ElicitationResult{ElicitationResponse: ElicitationResponse{Action: ElicitationResponseActionAccept, Content: map[string]any{"confirm": true}}},
+			want:   answer{Confirm: true},
+		},
+		{
+			name:    "declined",
+			result:  &ElicitationResult{ElicitationResponse: ElicitationResponse{Action: ElicitationResponseActionDecline}},
+			wantErr: "not accepted",
+		},
+		{
+			name:    "wrong type",
+			result:  &ElicitationResult{ElicitationResponse: ElicitationResponse{Action: ElicitationResponseActionAccept, Content: map[string]any{"confirm": "yes"}}},
+			wantErr: "decode elicitation content",
+		},
+		{
+			name:    "nil result",
+			wantErr: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseElicitationContent[answer](tt.result)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestElicitationResultReason(t *testing.T) {
+	data, err := json.Marshal(NewElicitationDeclineResultWithReason(ElicitationDeclineReasonUserBusy))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"action":"decline","reason":"user_busy"}`, string(data))
+
+	data, err = json.Marshal(NewElicitationCancelResultWithReason(ElicitationDeclineReasonNotApplicable))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"action":"cancel","reason":"not_applicable"}`, string(data))
+
+	data, err = json.Marshal(NewElicitationDeclineResult())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"action":"decline"}`, string(data))
+
+	var result ElicitationResult
+	require.NoError(t, json.Unmarshal([]byte(`{"action":"decline","reason":"privacy"}`), &result))
+	assert.Equal(t, ElicitationResponseActionDecline, result.Action)
+	assert.Equal(t, ElicitationDeclineReasonPrivacy, result.Reason)
+}
@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// ExperimentalEncodingKey is the experimental capability used to negotiate
+// wire compression and codecs at initialize time. The client lists what it
+// supports as an EncodingOffer under this key of its experimental
+// capabilities; a server that understands the capability answers with the
+// chosen EncodingSelection under the same key. A peer that does not
+// understand it simply ignores the key, and both sides fall back to
+// DefaultEncoding.
+const ExperimentalEncodingKey = "encoding"
+
+const (
+	// CompressionIdentity means messages are sent uncompressed.
+	CompressionIdentity = "identity"
+	// CompressionGzip means message bodies are gzip-compressed.
+	CompressionGzip = "gzip"
+	// CodecJSON is the standard JSON encoding of JSON-RPC messages.
+	CodecJSON = "json"
+)
+
+// DefaultEncoding is the encoding used when none was negotiated.
+var DefaultEncoding = EncodingSelection{Compression: CompressionIdentity, Codec: CodecJSON}
+
+// EncodingOffer lists the compression schemes and codecs a peer supports,
+// most preferred first.
+type EncodingOffer struct {
+	Compression []string `json:"compression,omitempty"`
+	Codecs      []string `json:"codecs,omitempty"`
+}
+
+// Intersect returns the compression schemes and codecs of o that other also
+// lists, in the order of o.
+func (o EncodingOffer) Intersect(other EncodingOffer) EncodingOffer {
+	return EncodingOffer{
+		Compression: intersect(o.Compression, other.Compression),
+		Codecs:      intersect(o.Codecs, other.Codecs),
+	}
+}
+
+func intersect(a, b []string) []string {
+	var out []string
+	for _, v := range a {
+		if slices.Contains(b, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// EncodingSelection is the compression scheme and codec chosen by the server.
+type EncodingSelection struct {
+	Compression string `json:"compression"`
+	Codec       string `json:"codec"`
+}
+
+// NegotiateEncoding picks the first compression scheme and codec of the
+// client's offer that the server also supports, falling back to
+// DefaultEncoding for either when there is no match.
+func NegotiateEncoding(offer, supported EncodingOffer) EncodingSelection {
+	selection := DefaultEncoding
+	for _, compression := range offer.Compression {
+		if slices.Contains(supported.Compression, compression) {
+			selection.Compression = compression
+			break
+		}
+	}
+	for _, codec := range offer.Codecs {
+		if slices.Contains(supported.Codecs, codec) {
+			selection.Codec = codec
+			break
+		}
+	}
+	return selection
+}
+
+// EncodingOfferFromExperimental extracts the client's offer from its
+// experimental capabilities. It reports false if the client made none.
+func EncodingOfferFromExperimental(experimental map[string]any) (EncodingOffer, bool) {
+	var offer EncodingOffer
+	return offer, decodeExperimental(experimental, &offer)
+}
+
+// EncodingSelectionFromExperimental extracts the server's choice from its
+// experimental capabilities. It reports false if the server made none, in
+// which case the peer should use DefaultEncoding.
+func EncodingSelectionFromExperimental(experimental map[string]any) (EncodingSelection, bool) {
+	var selection EncodingSelection
+	if !decodeExperimental(experimental, &selection) {
+		return DefaultEncoding, false
+	}
+	if selection.Compression == "" {
+		selection.Compression = CompressionIdentity
+	}
+	if selection.Codec == "" {
+		selection.Codec = CodecJSON
+	}
+	return selection, true
+}
+
+// decodeExperimental decodes the ExperimentalEncodingKey entry into v. The
+// entry is a map[string]any after a JSON round trip, or the typed value when
+// peers share a process.
+func decodeExperimental(experimental map[string]any, v any) bool {
+	raw, ok := experimental[ExperimentalEncodingKey]
+	if !ok || raw == nil {
+		return false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
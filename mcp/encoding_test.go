@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := EncodingOffer{Compression: []string{CompressionGzip, CompressionIdentity}, Codecs: []string{CodecJSON}}
+	tests := []struct {
+		name  string
+		offer EncodingOffer
+		want  EncodingSelection
+	}{
+		{"empty offer", EncodingOffer{}, DefaultEncoding},
+		{"client preference wins", EncodingOffer{Compression: []string{"zstd", CompressionGzip}}, EncodingSelection{CompressionGzip, CodecJSON}},
+		{"no common codec", EncodingOffer{Codecs: []string{"cbor"}}, DefaultEncoding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NegotiateEncoding(tt.offer, supported))
+		})
+	}
+}
+
+func TestEncodingFromExperimental(t *testing.T) {
+	_, ok := EncodingOfferFromExperimental(nil)
+	assert.False(t, ok)
+
+	offer, ok := EncodingOfferFromExperimental(map[string]any{
+		ExperimentalEncodingKey: map[string]any{"compression": []any{"gzip"}},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, []string{CompressionGzip}, offer.Compression)
+
+	selection, ok := EncodingSelectionFromExperimental(map[string]any{"other": true})
+	assert.False(t, ok)
+	assert.Equal(t, DefaultEncoding, selection)
+
+	selection, ok = EncodingSelectionFromExperimental(map[string]any{
+		ExperimentalEncodingKey: EncodingSelection{Compression: CompressionGzip},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, EncodingSelection{CompressionGzip, CodecJSON}, selection)
+}
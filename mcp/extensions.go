@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// ExtensionsMetaKey is the _meta key of the initialize request and result
+// under which client and server attach named extension blocks, such as
+// {"x-myco": {"region": "eu"}}. An extension is in use for a session only
+// if both sides list it in the Extensions of their capabilities; each side
+// then reads the other's block, which may be missing.
+//
+// InitializeParams has no _meta field of its own, so the _meta of an
+// initialize request is found in InitializeRequest.Request.Params.Meta.
+const ExtensionsMetaKey = "io.github.mark3labs.mcp-go/extensions"
+
+// Extensions maps extension names to their JSON blocks.
+type Extensions map[string]json.RawMessage
+
+// Decode unmarshals the block of the named extension into v. It reports
+// false, and leaves v untouched, if there is no such extension. A null
+// block leaves v untouched too.
+func (e Extensions) Decode(name string, v any) (bool, error) {
+	raw, ok := e[name]
+	if !ok {
+		return false, nil
+	}
+	if len(raw) == 0 {
+		return true, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("extension %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// ExtensionsFromMeta returns the extension blocks attached to meta under
+// ExtensionsMetaKey, or nil if there are none.
+func ExtensionsFromMeta(meta *Meta) (Extensions, error) {
+	if meta == nil {
+		return nil, nil
+	}
+	v, ok := meta.AdditionalFields[ExtensionsMetaKey]
+	if !ok {
+		return nil, nil
+	}
+	// Blocks are plain values when the meta did not come off the wire, as
+	// with in-process transports.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("extensions: %w", err)
+	}
+	var extensions Extensions
+	if err := json.Unmarshal(data, &extensions); err != nil {
+		return nil, fmt.Errorf("extensions: %w", err)
+	}
+	return extensions, nil
+}
+
+// WithExtensionsMeta returns a copy of meta, which may be nil, with blocks
+// attached under ExtensionsMetaKey. It returns meta itself if blocks is
+// empty.
+func WithExtensionsMeta(meta *Meta, blocks map[string]any) *Meta {
+	if len(blocks) == 0 {
+		return meta
+	}
+	out := &Meta{AdditionalFields: make(map[string]any, 1)}
+	if meta != nil {
+		out.ProgressToken = meta.ProgressToken
+		maps.Copy(out.AdditionalFields, meta.AdditionalFields)
+	}
+	out.AdditionalFields[ExtensionsMetaKey] = blocks
+	return out
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LatencyHintsMetaKey is the _meta key of the server capabilities under
+// which a server publishes its LatencyHints.
+const LatencyHintsMetaKey = "io.github.mark3labs.mcp-go/latency"
+
+// LatencyClass is how long a server expects a request to take, so that
+// clients can pick a timeout and a UI affordance without measuring it
+// first.
+type LatencyClass string
+
+const (
+	// LatencyFast requests answer within a few seconds; a spinner is
+	// enough.
+	LatencyFast LatencyClass = "fast"
+	// LatencySlow requests may take minutes; show progress and let the
+	// user cancel.
+	LatencySlow LatencyClass = "slow"
+	// LatencyLongRunning requests may take much longer; run them as tasks
+	// where the server supports it instead of waiting on the response.
+	LatencyLongRunning LatencyClass = "long-running"
+)
+
+// Valid reports whether c is one of the defined latency classes.
+func (c LatencyClass) Valid() bool {
+	switch c {
+	case LatencyFast, LatencySlow, LatencyLongRunning:
+		return true
+	}
+	return false
+}
+
+// LatencyHints are the latency classes a server expects for its methods and
+// tools. A tool's class takes precedence over the class of tools/call.
+type LatencyHints struct {
+	// Methods maps method names, such as "resources/read", to classes.
+	Methods map[string]LatencyClass `json:"methods,omitempty"`
+	// Tools maps tool names to classes.
+	Tools map[string]LatencyClass `json:"tools,omitempty"`
+}
+
+// Class returns the class of a request for method, and for tools/call, of a
+// call to tool. It reports false if there is no hint.
+func (h LatencyHints) Class(method, tool string) (LatencyClass, bool) {
+	if method == string(MethodToolsCall) && tool != "" {
+		if class, ok := h.Tools[tool]; ok {
+			return class, true
+		}
+	}
+	class, ok := h.Methods[method]
+	return class, ok
+}
+
+// IsZero reports whether h has no hints.
+func (h LatencyHints) IsZero() bool {
+	return len(h.Methods) == 0 && len(h.Tools) == 0
+}
+
+// LatencyHintsFromMeta returns the hints attached to meta under
+// LatencyHintsMetaKey. It reports false if there are none.
+func LatencyHintsFromMeta(meta *Meta) (LatencyHints, bool, error) {
+	if meta == nil {
+		return LatencyHints{}, false, nil
+	}
+	switch v := meta.AdditionalFields[LatencyHintsMetaKey].(type) {
+	case nil:
+		return LatencyHints{}, false, nil
+	case LatencyHints:
+		return v, true, nil
+	default:
+		// Decoded from the wire as a map.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return LatencyHints{}, false, fmt.Errorf("latency hints: %w", err)
+		}
+		var hints LatencyHints
+		if err := json.Unmarshal(data, &hints); err != nil {
+			return LatencyHints{}, false, fmt.Errorf("latency hints: %w", err)
+		}
+		return hints, true, nil
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHints(t *testing.T) {
+	hints := LatencyHints{
+		Methods: map[string]LatencyClass{"tools/call": LatencyFast, "resources/read": LatencySlow},
+		Tools:   map[string]LatencyClass{"train": LatencyLongRunning},
+	}
+	result := InitializeResult{Capabilities: ServerCapabilities{Meta: &Meta{AdditionalFields: map[string]any{LatencyHintsMetaKey: hints}}}}
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var decoded InitializeResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	got, ok, err := LatencyHintsFromMeta(decoded.Capabilities.Meta)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, hints, got)
+
+	for _, tc := range []struct {
+		method, tool string
+		want         LatencyClass
+		ok           bool
+	}{
+		{"tools/call", "train", LatencyLongRunning, true},
+		{"tools/call", "lookup", LatencyFast, true},
+		{"resources/read", "", LatencySlow, true},
+		{"prompts/get", "", "", false},
+	} {
+		class, ok := got.Class(tc.method, tc.tool)
+		assert.Equal(t, tc.ok, ok, tc.method+" "+tc.tool)
+		assert.Equal(t, tc.want, class, tc.method+" "+tc.tool)
+	}
+
+	_, ok, err = LatencyHintsFromMeta(&Meta{})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, LatencySlow.Valid())
+	assert.False(t, LatencyClass("glacial").Valid())
+}
@@ -0,0 +1,7 @@
+package mcp
+
+// RATE_LIMITED is the error code of requests that were rejected because the
+// caller exceeded the server's rate limit. The error data carries the number
+// of seconds to wait before retrying under "retryAfter". This is an
+// extension to the MCP specification.
+const RATE_LIMITED = -32029
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SamplingStreamMetaKey is the _meta key under which a sampling/createMessage
+// request carries the ID of the stream the client may send partial results
+// to. On the result it carries the number of deltas the client sent.
+const SamplingStreamMetaKey = "io.github.mark3labs.mcp-go/sampling-stream"
+
+// MethodNotificationSamplingDelta carries a partial result of a streamed
+// sampling request from the client to the server.
+const MethodNotificationSamplingDelta MCPMethod = "notifications/sampling/delta"
+
+// SamplingStreamID returns the stream ID requested in the _meta of a
+// sampling request, or "" if the server did not ask for a streamed result.
+func SamplingStreamID(request CreateMessageRequest) string {
+	if request.Meta == nil {
+		return ""
+	}
+	id, _ := request.Meta.AdditionalFields[SamplingStreamMetaKey].(string)
+	return id
+}
+
+// SamplingStreamDeltas returns the number of deltas the client reported
+// sending for a streamed sampling result.
+func SamplingStreamDeltas(result *CreateMessageResult) int {
+	if result == nil || result.Meta == nil {
+		return 0
+	}
+	switch n := result.Meta.AdditionalFields[SamplingStreamMetaKey].(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// SetSamplingStreamDeltas records in the result's _meta how many deltas were
+// sent for it, so the server can wait for deltas that arrive after the result.
+func SetSamplingStreamDeltas(result *CreateMessageResult, deltas int) {
+	if result.Meta == nil {
+		result.Meta = &Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[SamplingStreamMetaKey] = deltas
+}
+
+// NewSamplingDeltaNotification creates the notification carrying the
+// index-th partial result of the stream with the given ID.
+func NewSamplingDeltaNotification(streamID string, index int, delta CreateMessageResult) JSONRPCNotification {
+	return JSONRPCNotification{
+		JSONRPC: JSONRPC_VERSION,
+		Notification: Notification{
+			Method: string(MethodNotificationSamplingDelta),
+			Params: NotificationParams{
+				AdditionalFields: map[string]any{
+					"streamId": streamID,
+					"index":    index,
+					"delta":    delta,
+				},
+			},
+		},
+	}
+}
+
+// ParseSamplingDeltaNotification extracts the stream ID, index and partial
+// result from a notifications/sampling/delta notification.
+func ParseSamplingDeltaNotification(notification JSONRPCNotification) (string, int, *CreateMessageResult, error) {
+	fields := notification.Params.AdditionalFields
+	streamID, _ := fields["streamId"].(string)
+	if streamID == "" {
+		return "", 0, nil, fmt.Errorf("sampling delta without streamId")
+	}
+	var index int
+	switch n := fields["index"].(type) {
+	case int:
+		index = n
+	case float64:
+		index = int(n)
+	default:
+		return "", 0, nil, fmt.Errorf("sampling delta without index")
+	}
+
+	data, err := json.Marshal(fields["delta"])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("marshal sampling delta: %w", err)
+	}
+	var delta CreateMessageResult
+	if err := json.Unmarshal(data, &delta); err != nil {
+		return "", 0, nil, fmt.Errorf("unmarshal sampling delta: %w", err)
+	}
+	if contentMap, ok := delta.Content.(map[string]any); ok {
+		content, err := ParseContent(contentMap)
+		if err != nil {
+			return "", 0, nil, fmt.Errorf("parse sampling delta content: %w", err)
+		}
+		delta.Content = content
+	}
+	return streamID, index, &delta, nil
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingDeltaNotification_RoundTrip(t *testing.T) {
+	delta := CreateMessageResult{SamplingMessage: SamplingMessage{Role: RoleAssistant, Content: NewTextContent("Hel")}}
+	data, err := json.Marshal(NewSamplingDeltaNotification("stream-1", 2, delta))
+	require.NoError(t, err)
+
+	var notification JSONRPCNotification
+	require.NoError(t, json.Unmarshal(data, &notification))
+	assert.Equal(t, string(MethodNotificationSamplingDelta), notification.Method)
+
+	streamID, index, parsed, err := ParseSamplingDeltaNotification(notification)
+	require.NoError(t, err)
+	assert.Equal(t, "stream-1", streamID)
+	assert.Equal(t, 2, index)
+	assert.Equal(t, RoleAssistant, parsed.Role)
+	assert.Equal(t, "Hel", parsed.Content.(TextContent).Text)
+
+	_, _, _, err = ParseSamplingDeltaNotification(JSONRPCNotification{})
+	assert.ErrorContains(t, err, "streamId")
+}
+
+func TestSamplingStreamMeta(t *testing.T) {
+	request := CreateMessageRequest{}
+	assert.Empty(t, SamplingStreamID(request))
+	request.CreateMessageParams.Meta = &Meta{AdditionalFields: map[string]any{SamplingStreamMetaKey: "stream-1"}}
+	assert.Equal(t, "stream-1", SamplingStreamID(request))
+
+	result := &CreateMessageResult{}
+	assert.Zero(t, SamplingStreamDeltas(result))
+	SetSamplingStreamDeltas(result, 3)
+	assert.Equal(t, 3, SamplingStreamDeltas(result))
+
+	// The count survives a JSON round trip as a float64.
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var decoded CreateMessageResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 3, SamplingStreamDeltas(&decoded))
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"encoding/json"
+)
+
+// SamplingUsageMetaKey is the _meta key under which a sampling result
+// carries the tokens the model consumed, as a SamplingUsage. Servers also
+// attach it to tool results, with the tokens consumed by the sampling
+// requests made while handling the call.
+const SamplingUsageMetaKey = "io.github.mark3labs.mcp-go/usage"
+
+// SAMPLING_QUOTA_EXCEEDED is the error code of requests that were rejected
+// because the session used up its sampling token budget. This is an
+// extension to the MCP specification.
+const SAMPLING_QUOTA_EXCEEDED = -32030
+
+// SamplingUsage counts the tokens consumed by sampling requests.
+type SamplingUsage struct {
+	InputTokens  int64 `json:"inputTokens"`
+	OutputTokens int64 `json:"outputTokens"`
+}
+
+// TotalTokens returns the number of input and output tokens.
+func (u SamplingUsage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Add returns the sum of u and other.
+func (u SamplingUsage) Add(other SamplingUsage) SamplingUsage {
+	return SamplingUsage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+	}
+}
+
+// SetSamplingUsage records in the result's _meta how many tokens the model
+// consumed. Sampling handlers call it so the server can account for usage.
+func SetSamplingUsage(result *CreateMessageResult, usage SamplingUsage) {
+	result.Meta = withSamplingUsage(result.Meta, usage)
+}
+
+// SamplingUsageFromMeta returns the usage recorded under
+// SamplingUsageMetaKey in meta, if any.
+func SamplingUsageFromMeta(meta *Meta) (SamplingUsage, bool) {
+	if meta == nil {
+		return SamplingUsage{}, false
+	}
+	switch value := meta.AdditionalFields[SamplingUsageMetaKey].(type) {
+	case nil:
+		return SamplingUsage{}, false
+	case SamplingUsage:
+		return value, true
+	default:
+		// Decoded from JSON as a map.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return SamplingUsage{}, false
+		}
+		var usage SamplingUsage
+		if err := json.Unmarshal(data, &usage); err != nil {
+			return SamplingUsage{}, false
+		}
+		return usage, true
+	}
+}
+
+// SamplingUsageFromResult returns the usage the sampling result reports in
+// its _meta, if any.
+func SamplingUsageFromResult(result *CreateMessageResult) (SamplingUsage, bool) {
+	return SamplingUsageFromMeta(result.Meta)
+}
+
+func withSamplingUsage(meta *Meta, usage SamplingUsage) *Meta {
+	if meta == nil {
+		meta = &Meta{}
+	}
+	if meta.AdditionalFields == nil {
+		meta.AdditionalFields = make(map[string]any)
+	}
+	meta.AdditionalFields[SamplingUsageMetaKey] = usage
+	return meta
+}
+
+// SetToolSamplingUsage records in the tool result's _meta how many sampling
+// tokens were consumed while handling the call.
+func SetToolSamplingUsage(result *CallToolResult, usage SamplingUsage) {
+	result.Meta = withSamplingUsage(result.Meta, usage)
+}
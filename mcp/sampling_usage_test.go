@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingUsage_RoundTrip(t *testing.T) {
+	result := &CreateMessageResult{Model: "test-model"}
+	_, ok := SamplingUsageFromMeta(result.Meta)
+	assert.False(t, ok)
+
+	SetSamplingUsage(result, SamplingUsage{InputTokens: 5, OutputTokens: 7})
+	usage, ok := SamplingUsageFromMeta(result.Meta)
+	require.True(t, ok)
+	assert.Equal(t, int64(12), usage.TotalTokens())
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var decoded CreateMessageResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	usage, ok = SamplingUsageFromMeta(decoded.Meta)
+	require.True(t, ok)
+	assert.Equal(t, SamplingUsage{InputTokens: 5, OutputTokens: 7}, usage)
+}
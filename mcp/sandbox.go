@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SandboxProfileMetaKey is the Tool _meta key under which a tool's sandbox
+// profile is published, so clients can show how the tool is confined.
+const SandboxProfileMetaKey = "io.github.mark3labs.mcp-go/sandbox"
+
+// SandboxProfile declares the execution limits a tool expects to run under.
+// The profile is descriptive: the server maps it to real enforcement
+// (rlimits, containers, WASM runtimes, ...) through a sandbox enforcer.
+type SandboxProfile struct {
+	// Name identifies a deployment-defined profile, e.g. "restricted".
+	Name string `json:"name,omitempty"`
+	// TimeoutMillis bounds the execution time of one call.
+	TimeoutMillis int64 `json:"timeoutMs,omitempty"`
+	// MemoryBytes bounds the memory available to one call.
+	MemoryBytes int64 `json:"memoryBytes,omitempty"`
+	// ReadPaths lists the filesystem paths the tool may read.
+	ReadPaths []string `json:"readPaths,omitempty"`
+	// WritePaths lists the filesystem paths the tool may write.
+	WritePaths []string `json:"writePaths,omitempty"`
+	// Network reports whether the tool may open network connections.
+	Network bool `json:"network"`
+}
+
+// Timeout returns TimeoutMillis as a duration, or 0 if unset.
+func (p SandboxProfile) Timeout() time.Duration {
+	return time.Duration(p.TimeoutMillis) * time.Millisecond
+}
+
+// WithSandboxProfile attaches a sandbox profile to the tool and publishes it
+// in the tool's _meta under SandboxProfileMetaKey.
+func WithSandboxProfile(profile SandboxProfile) ToolOption {
+	return func(t *Tool) {
+		if t.Meta == nil {
+			t.Meta = &Meta{}
+		}
+		if t.Meta.AdditionalFields == nil {
+			t.Meta.AdditionalFields = make(map[string]any)
+		}
+		t.Meta.AdditionalFields[SandboxProfileMetaKey] = profile
+	}
+}
+
+// SandboxProfileFromTool returns the sandbox profile of tool, whether it was
+// set with WithSandboxProfile or decoded from a tools/list response.
+func SandboxProfileFromTool(tool Tool) (SandboxProfile, bool) {
+	if tool.Meta == nil {
+		return SandboxProfile{}, false
+	}
+	switch v := tool.Meta.AdditionalFields[SandboxProfileMetaKey].(type) {
+	case SandboxProfile:
+		return v, true
+	case nil:
+		return SandboxProfile{}, false
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return SandboxProfile{}, false
+		}
+		var profile SandboxProfile
+		if err := json.Unmarshal(data, &profile); err != nil {
+			return SandboxProfile{}, false
+		}
+		return profile, true
+	}
+}
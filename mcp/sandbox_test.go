@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxProfile(t *testing.T) {
+	profile := SandboxProfile{
+		Name:          "restricted",
+		TimeoutMillis: 1500,
+		MemoryBytes:   64 << 20,
+		ReadPaths:     []string{"/data"},
+	}
+	tool := NewTool("query", WithSandboxProfile(profile))
+
+	got, ok := SandboxProfileFromTool(tool)
+	require.True(t, ok)
+	assert.Equal(t, profile, got)
+	assert.Equal(t, 1500*time.Millisecond, got.Timeout())
+
+	// The profile survives a round trip through tools/list.
+	data, err := json.Marshal(tool)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"_meta":{"io.github.mark3labs.mcp-go/sandbox":{"name":"restricted","timeoutMs":1500`)
+	var decoded Tool
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	got, ok = SandboxProfileFromTool(decoded)
+	require.True(t, ok)
+	assert.Equal(t, profile, got)
+
+	_, ok = SandboxProfileFromTool(NewTool("plain"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// NewToolFromStruct creates a tool whose input schema is derived entirely
+// from the Go struct Args, the same type a TypedToolHandlerFunc[Args]
+// decodes its arguments into. The schema follows the struct's layout:
+//
+//   - property names and types come from the fields and their json tags;
+//   - fields without omitempty or omitzero are required;
+//   - the jsonschema tag sets the property description;
+//   - the enum tag lists the allowed values, comma separated, converted to
+//     the field's type (e.g. `enum:"asc,desc"` or `enum:"1,2,3"`);
+//   - the default tag sets the default value, converted the same way;
+//   - the minLength, maxLength, minimum and maximum tags set the
+//     corresponding bounds.
+//
+// opts are applied after the schema is set, so they can add a description,
+// annotations or an output schema. Prefer [NewTypedTool], which also builds
+// the handler, so the schema and the decoding struct cannot drift apart.
+func NewToolFromStruct[Args any](name string, opts ...ToolOption) Tool {
+	tool := NewTool(name)
+	raw, err := structSchemaFor[Args]()
+	if err != nil {
+		slog.Error("mcp: cannot generate input schema", "tool", name, "error", err)
+	} else {
+		tool.InputSchema.Type = ""
+		tool.RawInputSchema = raw
+	}
+	for _, opt := range opts {
+		opt(&tool)
+	}
+	return tool
+}
+
+// NewTypedTool returns a tool built by [NewToolFromStruct] together with a
+// handler built by [NewTypedToolHandler] for the same Args type, ready to be
+// registered with the server.
+func NewTypedTool[Args any](
+	name string,
+	handler TypedToolHandlerFunc[Args],
+	opts ...ToolOption,
+) (Tool, func(ctx context.Context, request CallToolRequest) (*CallToolResult, error)) {
+	return NewToolFromStruct[Args](name, opts...), NewTypedToolHandler(handler)
+}
+
+// structSchemaFor reflects the schema for T and applies the struct tags
+// that jsonschema.For does not understand.
+func structSchemaFor[T any]() (json.RawMessage, error) {
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{IgnoreInvalidTypes: true})
+	if err != nil {
+		return nil, fmt.Errorf("generate schema: %w", err)
+	}
+	if err := applyStructTags(schema, reflect.TypeFor[T]()); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return raw, nil
+}
+
+// applyStructTags walks t alongside its reflected schema and sets enums,
+// defaults and bounds from the struct tags, descending into nested structs, pointers and slices.
+func applyStructTags(schema *jsonschema.Schema, t reflect.Type) error {
+	for schema != nil {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		} else if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+			schema = schema.Items
+		} else {
+			break
+		}
+	}
+	if schema == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			if err := applyStructTags(schema, field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		prop := schema.Properties[name]
+		if prop == nil {
+			continue
+		}
+		if tag, ok := field.Tag.Lookup("enum"); ok {
+			values, err := parseEnumTag(tag, field.Type)
+			if err != nil {
+				return fmt.Errorf("field %s.%s: %w", t, field.Name, err)
+			}
+			if prop.Items != nil {
+				prop.Items.Enum = values
+			} else {
+				prop.Enum = values
+			}
+		}
+		if err := applyBoundTags(prop, field); err != nil {
+			return fmt.Errorf("field %s.%s: %w", t, field.Name, err)
+		}
+		if err := applyStructTags(prop, field.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyBoundTags sets the default value and the length and range bounds of
+// prop from the tags of field.
+func applyBoundTags(prop *jsonschema.Schema, field reflect.StructField) error {
+	if tag, ok := field.Tag.Lookup("default"); ok {
+		v, err := parseTagValue(tag, field.Type)
+		if err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+		if prop.Default, err = json.Marshal(v); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	for name, bound := range map[string]**int{"minLength": &prop.MinLength, "maxLength": &prop.MaxLength} {
+		if tag, ok := field.Tag.Lookup(name); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(tag))
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", name, tag, err)
+			}
+			*bound = &n
+		}
+	}
+	for name, bound := range map[string]**float64{"minimum": &prop.Minimum, "maximum": &prop.Maximum} {
+		if tag, ok := field.Tag.Lookup(name); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(tag), 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", name, tag, err)
+			}
+			*bound = &n
+		}
+	}
+	return nil
+}
+
+// parseEnumTag converts the comma separated values of an enum tag to the
+// JSON type of t (or of its elements, for slices).
+func parseEnumTag(tag string, t reflect.Type) ([]any, error) {
+	parts := strings.Split(tag, ",")
+	values := make([]any, 0, len(parts))
+	for _, part := range parts {
+		v, err := parseTagValue(part, t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enum value %q: %w", strings.TrimSpace(part), err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// parseTagValue converts a struct tag value to the JSON type of t (or of
+// its elements, for slices).
+func parseTagValue(value string, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	value = strings.TrimSpace(value)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	}
+	return value, nil
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchFilter struct {
+	Field string   `json:"field" enum:"title,body"`
+	Tags  []string `json:"tags,omitempty" enum:"red,green"`
+}
+
+type searchArgs struct {
+	Query   string         `json:"query" jsonschema:"Full-text query"`
+	Order   string         `json:"order,omitempty" enum:"asc, desc"`
+	Limit   int            `json:"limit,omitempty" enum:"10,50,100"`
+	Filters []searchFilter `json:"filters,omitempty"`
+	Scope   *searchFilter  `json:"scope,omitempty"`
+}
+
+func TestNewToolFromStruct(t *testing.T) {
+	tool := NewToolFromStruct[searchArgs]("search", WithDescription("Search documents"))
+	assert.Equal(t, "Search documents", tool.Description)
+
+	data, err := json.Marshal(tool)
+	require.NoError(t, err)
+	var decoded struct {
+		InputSchema struct {
+			Type       string                    `json:"type"`
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"inputSchema"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	schema := decoded.InputSchema
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"query"}, schema.Required)
+	assert.Equal(t, "string", schema.Properties["query"]["type"])
+	assert.Equal(t, "Full-text query", schema.Properties["query"]["description"])
+	assert.Equal(t, []any{"asc", "desc"}, schema.Properties["order"]["enum"])
+	assert.Equal(t, []any{10.0, 50.0, 100.0}, schema.Properties["limit"]["enum"])
+
+	filter := schema.Properties["filters"]["items"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, []any{"title", "body"}, filter["field"].(map[string]any)["enum"])
+	tags := filter["tags"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, []any{"red", "green"}, tags["enum"])
+
+	scope := schema.Properties["scope"]["properties"].(map[string]any)
+	assert.Equal(t, []any{"title", "body"}, scope["field"].(map[string]any)["enum"])
+}
+
+func TestNewTypedTool(t *testing.T) {
+	tool, handler := NewTypedTool("search", func(_ context.Context, _ CallToolRequest, args searchArgs) (*CallToolResult, error) {
+		return NewToolResultText(args.Query + "/" + args.Order), nil
+	})
+	assert.Equal(t, "search", tool.Name)
+	assert.NotEmpty(t, tool.RawInputSchema)
+
+	request := CallToolRequest{}
+	request.Params.Arguments = map[string]any{"query": "mcp", "order": "asc"}
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "mcp/asc", result.Content[0].(TextContent).Text)
+}
@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -107,6 +109,13 @@ func NewCancelTaskResult(task Task) CancelTaskResult {
 	}
 }
 
+// NewClaimTaskResult creates a result for a claimed task.
+func NewClaimTaskResult(task Task) ClaimTaskResult {
+	return ClaimTaskResult{
+		Task: task,
+	}
+}
+
 // NewTaskStatusNotification creates a notification for a task status change.
 func NewTaskStatusNotification(task Task) TaskStatusNotification {
 	return TaskStatusNotification{
@@ -206,3 +215,109 @@ func WithModelImmediateResponse(message string) *Meta {
 		},
 	}
 }
+
+//
+// Task Scheduling Metadata Functions
+//
+
+const (
+	// TaskPriorityMetaKey is the TaskParams metadata key for the priority
+	// of a task. Servers that queue tasks start those with a higher
+	// priority first; the default priority is 0.
+	TaskPriorityMetaKey = "io.github.mark3labs.mcp-go/priority"
+	// TaskDeadlineMetaKey is the TaskParams metadata key for the absolute
+	// deadline of a task, as an RFC 3339 timestamp. Servers fail tasks that
+	// have not finished by then.
+	TaskDeadlineMetaKey = "io.github.mark3labs.mcp-go/deadline"
+)
+
+// WithTaskScheduling returns TaskParams metadata requesting the given
+// priority and deadline. A zero deadline sets no deadline.
+//
+// Example:
+//
+//	request.Params.Task = &mcp.TaskParams{
+//	    Meta: mcp.WithTaskScheduling(10, time.Now().Add(time.Minute)),
+//	}
+func WithTaskScheduling(priority int, deadline time.Time) *Meta {
+	fields := map[string]any{TaskPriorityMetaKey: priority}
+	if !deadline.IsZero() {
+		fields[TaskDeadlineMetaKey] = deadline.UTC().Format(time.RFC3339Nano)
+	}
+	return &Meta{AdditionalFields: fields}
+}
+
+// TaskPriority returns the priority requested in params, or 0.
+func TaskPriority(params *TaskParams) int {
+	if params == nil || params.Meta == nil {
+		return 0
+	}
+	switch priority := params.Meta.AdditionalFields[TaskPriorityMetaKey].(type) {
+	case int:
+		return priority
+	case float64:
+		return int(priority)
+	case json.Number:
+		n, _ := priority.Int64()
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// TaskDeadline returns the deadline requested in params, if any.
+func TaskDeadline(params *TaskParams) (time.Time, bool) {
+	if params == nil || params.Meta == nil {
+		return time.Time{}, false
+	}
+	value, ok := params.Meta.AdditionalFields[TaskDeadlineMetaKey].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	deadline, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return deadline, true
+}
+
+// ParseTaskResultAs decodes the structured content of a task result into T,
+// typically the type the tool declared with WithOutputSchema. When the
+// structured content is absent, the first text content is decoded instead,
+// for servers that only send the JSON text form. It returns an error if the
+// task reported a tool error or the result carries neither.
+func ParseTaskResultAs[T any](result *TaskResultResult) (T, error) {
+	var value T
+	if result == nil {
+		return value, fmt.Errorf("task result is nil")
+	}
+	if result.IsError {
+		return value, fmt.Errorf("task failed: %s", taskResultText(result))
+	}
+
+	var raw []byte
+	if result.StructuredContent != nil {
+		var err error
+		if raw, err = json.Marshal(result.StructuredContent); err != nil {
+			return value, fmt.Errorf("marshal task result: %w", err)
+		}
+	} else if text := taskResultText(result); text != "" {
+		raw = []byte(text)
+	} else {
+		return value, fmt.Errorf("task result has no content to decode")
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return value, fmt.Errorf("decode task result: %w", err)
+	}
+	return value, nil
+}
+
+// taskResultText returns the first text content of result.
+func taskResultText(result *TaskResultResult) string {
+	for _, content := range result.Content {
+		if text, ok := AsTextContent(content); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
@@ -1,9 +1,12 @@
 package mcp
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRelatedTaskMeta(t *testing.T) {
@@ -77,3 +80,99 @@ func TestCreateTaskResultWithModelImmediateResponse(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, message, immediateResponse)
 }
+
+func TestParseTaskResultAs(t *testing.T) {
+	type weather struct {
+		Temperature float64 `json:"temperature"`
+		Unit        string  `json:"unit"`
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    weather
+		wantErr string
+	}{
+		{
+			name: "structured content",
+			raw:  `{"content":[{"type":"text","text":"21.5 C"}],"structuredContent":{"temperature":21.5,"unit":"C"}}`,
+			want: weather{Temperature: 21.5, Unit: "C"},
+		},
+		{
+			name: "text fallback",
+			raw:  `{"content":[{"type":"text","text":"{\"temperature\":3,\"unit\":\"F\"}"}]}`,
+			want: weather{Temperature: 3, Unit: "F"},
+		},
+		{
+			name: "legacy nested result",
+			raw:  `{"result":{"structuredContent":{"temperature":1,"unit":"K"}}}`,
+			want: weather{Temperature: 1, Unit: "K"},
+		},
+		{
+			name:    "tool error",
+			raw:     `{"isError":true,"content":[{"type":"text","text":"sensor offline"}]}`,
+			wantErr: "task failed: sensor offline",
+		},
+		{
+			name:    "wrong type",
+			raw:     `{"structuredContent":{"temperature":"hot"}}`,
+			wantErr: "decode task result",
+		},
+		{
+			name:    "empty",
+			raw:     `{}`,
+			wantErr: "no content",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := json.RawMessage(tt.raw)
+			result, err := ParseTaskResultResult(&raw)
+			require.NoError(t, err)
+
+			got, err := ParseTaskResultAs[weather](result)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithTaskScheduling(t *testing.T) {
+	deadline := time.Date(2025, 6, 1, 12, 0, 0, 500, time.UTC)
+	data, err := json.Marshal(TaskParams{Meta: WithTaskScheduling(7, deadline)})
+	require.NoError(t, err)
+
+	var params TaskParams
+	require.NoError(t, json.Unmarshal(data, &params))
+	assert.Equal(t, 7, TaskPriority(&params))
+	got, ok := TaskDeadline(&params)
+	require.True(t, ok)
+	assert.True(t, deadline.Equal(got))
+
+	assert.Equal(t, 0, TaskPriority(nil))
+	_, ok = TaskDeadline(&TaskParams{Meta: WithTaskScheduling(1, time.Time{})})
+	assert.False(t, ok)
+}
+
+func TestListTasksRequest_FilterInParams(t *testing.T) {
+	request := ListTasksRequest{
+		PaginatedRequest: PaginatedRequest{
+			Request: Request{Method: string(MethodTasksList)},
+			Params:  PaginatedParams{Cursor: "next"},
+		},
+		Filter: TaskFilter{Status: []TaskStatus{TaskStatusFailed}, ToolName: "build"},
+	}
+
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"method":"tasks/list","params":{"cursor":"next","status":["failed"],"toolName":"build"}}`, string(data))
+
+	var decoded ListTasksRequest
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, request, decoded)
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import "encoding/json"
+
+// ToolAvailabilityMetaKey is the Tool _meta key under which the server
+// publishes that a listed tool is temporarily unavailable.
+const ToolAvailabilityMetaKey = "io.github.mark3labs.mcp-go/availability"
+
+// TOOL_DISABLED is the error code a server answers tools/call with when the
+// tool is listed but temporarily disabled. The error data is the tool's
+// ToolAvailability.
+const TOOL_DISABLED = -32010
+
+// ToolAvailability describes whether a listed tool can currently be called.
+type ToolAvailability struct {
+	// Disabled reports whether calls to the tool are currently rejected.
+	Disabled bool `json:"disabled"`
+	// Reason is a human-readable explanation, e.g. "upstream maintenance".
+	Reason string `json:"reason,omitempty"`
+}
+
+// ToolAvailabilityFromTool returns the availability the server published in
+// the tool's _meta. Tools without one are available.
+func ToolAvailabilityFromTool(tool Tool) ToolAvailability {
+	if tool.Meta == nil {
+		return ToolAvailability{}
+	}
+	switch v := tool.Meta.AdditionalFields[ToolAvailabilityMetaKey].(type) {
+	case ToolAvailability:
+		return v
+	case nil:
+		return ToolAvailability{}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ToolAvailability{}
+		}
+		var availability ToolAvailability
+		if err := json.Unmarshal(data, &availability); err != nil {
+			return ToolAvailability{}
+		}
+		return availability
+	}
+}
@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ToolResultBuilder builds a CallToolResult from content added in order,
+// checking that the result is well formed. Methods record the first
+// problem they find and return the builder, so calls can be chained; Build
+// reports it.
+//
+//	result, err := mcp.NewToolResultBuilder().
+//		Text("Rendered the chart:").
+//		Image(png, "image/png").
+//		Structured(stats).
+//		Build()
+type ToolResultBuilder struct {
+	content       []Content
+	structured    any
+	hasStructured bool
+	isError       bool
+	err           error
+}
+
+// NewToolResultBuilder returns an empty ToolResultBuilder.
+func NewToolResultBuilder() *ToolResultBuilder {
+	return &ToolResultBuilder{}
+}
+
+func (b *ToolResultBuilder) fail(err error) *ToolResultBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Text appends a text content.
+func (b *ToolResultBuilder) Text(text string, opts ...ContentOption) *ToolResultBuilder {
+	b.content = append(b.content, NewTextContent(text, opts...))
+	return b
+}
+
+// Textf appends a text content formatted with fmt.Sprintf.
+func (b *ToolResultBuilder) Textf(format string, a ...any) *ToolResultBuilder {
+	return b.Text(fmt.Sprintf(format, a...))
+}
+
+// Image appends an image content with base64-encoded data.
+func (b *ToolResultBuilder) Image(data, mimeType string, opts ...ContentOption) *ToolResultBuilder {
+	if data == "" || mimeType == "" {
+		return b.fail(errors.New("image content requires data and a MIME type"))
+	}
+	b.content = append(b.content, NewImageContent(data, mimeType, opts...))
+	return b
+}
+
+// Audio appends an audio content with base64-encoded data.
+func (b *ToolResultBuilder) Audio(data, mimeType string, opts ...ContentOption) *ToolResultBuilder {
+	if data == "" || mimeType == "" {
+		return b.fail(errors.New("audio content requires data and a MIME type"))
+	}
+	b.content = append(b.content, NewAudioContent(data, mimeType, opts...))
+	return b
+}
+
+// ResourceLink appends a link to a resource the client can read.
+func (b *ToolResultBuilder) ResourceLink(uri, name, description, mimeType string, opts ...ContentOption) *ToolResultBuilder {
+	if uri == "" || name == "" {
+		return b.fail(errors.New("resource link requires a URI and a name"))
+	}
+	b.content = append(b.content, NewResourceLink(uri, name, description, mimeType, opts...))
+	return b
+}
+
+// Resource appends an embedded resource.
+func (b *ToolResultBuilder) Resource(resource ResourceContents, opts ...ContentOption) *ToolResultBuilder {
+	if resource == nil {
+		return b.fail(errors.New("embedded resource requires contents"))
+	}
+	b.content = append(b.content, NewEmbeddedResource(resource, opts...))
+	return b
+}
+
+// Content appends already built content.
+func (b *ToolResultBuilder) Content(content ...Content) *ToolResultBuilder {
+	for _, c := range content {
+		if c == nil {
+			return b.fail(errors.New("content must not be nil"))
+		}
+	}
+	b.content = append(b.content, content...)
+	return b
+}
+
+// Structured sets the structured content, which must encode to a JSON
+// object. A result has at most one structured content. If no other content
+// is added, Build adds its JSON encoding as a text fallback for clients
+// that do not support structured content.
+func (b *ToolResultBuilder) Structured(v any) *ToolResultBuilder {
+	if b.hasStructured {
+		return b.fail(errors.New("structured content is already set"))
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return b.fail(fmt.Errorf("unable to marshal structured content: %w", err))
+	}
+	if !bytes.HasPrefix(data, []byte("{")) {
+		return b.fail(fmt.Errorf("structured content must be a JSON object, got %s", data))
+	}
+	b.structured, b.hasStructured = v, true
+	return b
+}
+
+// AsError marks the result as a tool execution error.
+func (b *ToolResultBuilder) AsError() *ToolResultBuilder {
+	b.isError = true
+	return b
+}
+
+// Build returns the result, or the first problem found while building it.
+// A result must have at least one content or a structured content.
+func (b *ToolResultBuilder) Build() (*CallToolResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	content := append([]Content(nil), b.content...)
+	if len(content) == 0 {
+		if !b.hasStructured {
+			return nil, errors.New("tool result has no content")
+		}
+		// Marshaling succeeded in Structured.
+		data, _ := json.Marshal(b.structured)
+		content = append(content, NewTextContent(string(data)))
+	}
+	return &CallToolResult{
+		Content:           content,
+		StructuredContent: b.structured,
+		IsError:           b.isError,
+	}, nil
+}
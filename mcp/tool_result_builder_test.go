@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolResultBuilder(t *testing.T) {
+	result, err := NewToolResultBuilder().
+		Text("Rendered the chart:").
+		Image("aGVsbG8=", "image/png", WithContentAudience(RoleUser)).
+		ResourceLink("file:///chart.csv", "chart.csv", "Raw data", "text/csv").
+		Resource(TextResourceContents{URI: "file:///notes.txt", Text: "notes"}).
+		Audio("d2F2", "audio/wav").
+		Content(NewTextContent("done")).
+		Structured(map[string]any{"points": 3}).
+		Build()
+	require.NoError(t, err)
+
+	require.Len(t, result.Content, 6)
+	assert.Equal(t, "Rendered the chart:", result.Content[0].(TextContent).Text)
+	assert.Equal(t, []Role{RoleUser}, result.Content[1].(ImageContent).Annotations.Audience)
+	assert.Equal(t, "file:///chart.csv", result.Content[2].(ResourceLink).URI)
+	assert.IsType(t, EmbeddedResource{}, result.Content[3])
+	assert.IsType(t, AudioContent{}, result.Content[4])
+	assert.Equal(t, "done", result.Content[5].(TextContent).Text)
+	assert.Equal(t, map[string]any{"points": 3}, result.StructuredContent)
+	assert.False(t, result.IsError)
+}
+
+func TestToolResultBuilder_StructuredFallback(t *testing.T) {
+	type stats struct {
+		Count int `json:"count"`
+	}
+	result, err := NewToolResultBuilder().Structured(stats{Count: 2}).Build()
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, `{"count":2}`, result.Content[0].(TextContent).Text)
+
+	result, err = NewToolResultBuilder().Textf("failed after %d tries", 3).AsError().Build()
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "failed after 3 tries", result.Content[0].(TextContent).Text)
+}
+
+func TestToolResultBuilder_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *ToolResultBuilder
+		wantErr string
+	}{
+		{name: "empty", builder: NewToolResultBuilder(), wantErr: "no content"},
+		{name: "two structured contents", builder: NewToolResultBuilder().Structured(map[string]int{}).Structured(map[string]int{}), wantErr: "already set"},
+		{name: "structured array", builder: NewToolResultBuilder().Structured([]int{1}), wantErr: "JSON object"},
+		{name: "unmarshalable structured", builder: NewToolResultBuilder().Structured(map[string]any{"c": make(chan int)}), wantErr: "marshal"},
+		{name: "image without MIME type", builder: NewToolResultBuilder().Image("aGVsbG8=", ""), wantErr: "image content"},
+		{name: "audio without data", builder: NewToolResultBuilder().Audio("", "audio/wav"), wantErr: "audio content"},
+		{name: "link without URI", builder: NewToolResultBuilder().ResourceLink("", "name", "", ""), wantErr: "resource link"},
+		{name: "nil resource", builder: NewToolResultBuilder().Resource(nil), wantErr: "embedded resource"},
+		{name: "nil content", builder: NewToolResultBuilder().Content(nil), wantErr: "must not be nil"},
+		{name: "first error wins", builder: NewToolResultBuilder().Image("", "").Audio("", "").Text("ok"), wantErr: "image content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.builder.Build()
+			assert.Nil(t, result)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
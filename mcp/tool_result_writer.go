@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MethodNotificationToolResultChunk carries partial output of a tool call
+// that is still running. It is an extension notification: clients that do
+// not understand it ignore it and only see the final CallToolResult.
+const MethodNotificationToolResultChunk MCPMethod = "notifications/tools/resultChunk"
+
+// ToolResultWriter lets a tool handler stream incremental output to the
+// client before it returns its final CallToolResult. Each write is sent
+// immediately as a notifications/tools/resultChunk notification.
+type ToolResultWriter interface {
+	// Write sends p as a text content chunk.
+	io.Writer
+	// WriteContent sends content as one chunk.
+	WriteContent(content ...Content) error
+}
+
+// ToolResultChunkParams are the params of a tool result chunk notification.
+type ToolResultChunkParams struct {
+	// ProgressToken is the progress token of the tools/call request, if it
+	// set one, so that clients can correlate chunks with their request.
+	ProgressToken ProgressToken `json:"progressToken,omitempty"`
+	// Sequence numbers chunks of one call, starting at 1.
+	Sequence int `json:"sequence"`
+	// Content is the partial output.
+	Content []Content `json:"content"`
+}
+
+// ParseToolResultChunk decodes the params of a
+// notifications/tools/resultChunk notification.
+func ParseToolResultChunk(notification JSONRPCNotification) (*ToolResultChunkParams, error) {
+	if notification.Method != string(MethodNotificationToolResultChunk) {
+		return nil, fmt.Errorf("unexpected notification method %q", notification.Method)
+	}
+	data, err := json.Marshal(notification.Params.AdditionalFields)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		ProgressToken ProgressToken    `json:"progressToken"`
+		Sequence      int              `json:"sequence"`
+		Content       []map[string]any `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	params := &ToolResultChunkParams{ProgressToken: raw.ProgressToken, Sequence: raw.Sequence}
+	for _, contentMap := range raw.Content {
+		content, err := ParseContent(contentMap)
+		if err != nil {
+			return nil, err
+		}
+		params.Content = append(params.Content, content)
+	}
+	return params, nil
+}
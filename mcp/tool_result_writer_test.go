@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolResultChunk(t *testing.T) {
+	notification := JSONRPCNotification{
+		JSONRPC: JSONRPC_VERSION,
+		Notification: Notification{
+			Method: string(MethodNotificationToolResultChunk),
+			Params: NotificationParams{AdditionalFields: map[string]any{
+				"progressToken": "tok",
+				"sequence":      2,
+				"content":       []Content{NewTextContent("partial")},
+			}},
+		},
+	}
+	chunk, err := ParseToolResultChunk(notification)
+	require.NoError(t, err)
+	assert.Equal(t, "tok", chunk.ProgressToken)
+	assert.Equal(t, 2, chunk.Sequence)
+	assert.Equal(t, []Content{NewTextContent("partial")}, chunk.Content)
+
+	_, err = ParseToolResultChunk(JSONRPCNotification{Notification: Notification{Method: "other"}})
+	assert.Error(t, err)
+}
@@ -4,8 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
-	"os"
 	"reflect"
 	"strconv"
 
@@ -653,13 +654,31 @@ type Tool struct {
 	Icons []Icon `json:"icons,omitempty"`
 	// Execution describes execution behavior for the tool
 	Execution *ToolExecution `json:"execution,omitempty"`
+	// Namespace identifies the source of the tool, such as a plugin or a
+	// proxied server, when tool sets are composed. It is not sent to
+	// clients; servers use it to resolve tool name collisions.
+	Namespace string `json:"-"`
 }
 
+// ToolNamespaceSeparator joins a tool's namespace and name in its qualified
+// name.
+const ToolNamespaceSeparator = "_"
+
 // GetName returns the name of the tool.
 func (t Tool) GetName() string {
 	return t.Name
 }
 
+// QualifiedName returns the name of the tool prefixed with its namespace,
+// such as github_search_issues, or just the name if the tool has no
+// namespace.
+func (t Tool) QualifiedName() string {
+	if t.Namespace == "" {
+		return t.Name
+	}
+	return t.Namespace + ToolNamespaceSeparator + t.Name
+}
+
 // MarshalJSON implements the json.Marshaler interface for Tool.
 // It handles marshaling either InputSchema or RawInputSchema based on which is set.
 func (t Tool) MarshalJSON() ([]byte, error) {
@@ -916,7 +935,7 @@ func WithInputSchema[T any]() ToolOption {
 	return func(t *Tool) {
 		schema, err := jsonschema.For[T](&jsonschema.ForOptions{IgnoreInvalidTypes: true})
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
+			slog.Error("mcp: cannot generate input schema", "tool", t.Name, "error", err)
 			return
 		}
 
@@ -930,6 +949,14 @@ func WithInputSchema[T any]() ToolOption {
 	}
 }
 
+// WithToolNamespace sets the namespace of the Tool, identifying the tool
+// set it belongs to.
+func WithToolNamespace(namespace string) ToolOption {
+	return func(t *Tool) {
+		t.Namespace = namespace
+	}
+}
+
 // WithToolIcons adds icons to the Tool.
 // Icons provide visual identifiers for the tool.
 func WithToolIcons(icons ...Icon) ToolOption {
@@ -967,7 +994,7 @@ func WithOutputSchema[T any]() ToolOption {
 	return func(t *Tool) {
 		schema, err := jsonschema.For[T](&jsonschema.ForOptions{IgnoreInvalidTypes: true})
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
+			slog.Error("mcp: cannot generate output schema", "tool", t.Name, "error", err)
 			return
 		}
 
@@ -986,6 +1013,31 @@ func WithOutputSchema[T any]() ToolOption {
 	}
 }
 
+// WithOutputSchemaMap sets the tool's output schema from a JSON Schema
+// document held in a map, for schemas built at runtime or loaded from
+// configuration. The schema's type defaults to "object", as required by the
+// current MCP spec. Combine with server.WithOutputSchemaValidation to have
+// the server check each result's StructuredContent against it before
+// sending.
+func WithOutputSchemaMap(schema map[string]any) ToolOption {
+	return func(t *Tool) {
+		if _, ok := schema["type"]; !ok {
+			schema = maps.Clone(schema)
+			if schema == nil {
+				schema = make(map[string]any, 1)
+			}
+			schema["type"] = "object"
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			slog.Error("mcp: cannot encode output schema", "tool", t.Name, "error", err)
+			return
+		}
+		t.OutputSchema = ToolOutputSchema{}
+		t.RawOutputSchema = data
+	}
+}
+
 // WithRawOutputSchema sets a raw JSON schema for the tool's output.
 // Use this when you need full control over the schema or when working with
 // complex schemas that can't be generated from Go types. The jsonschema library
@@ -4,8 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
+	"log/slog"
 	"strings"
 	"testing"
 
@@ -763,7 +762,7 @@ func TestToolWithInputSchema(t *testing.T) {
 }
 
 // TestToolWithInputSchemaJsonSchemaTagError tests that the WithInputSchema
-// function prints an error to stderr if a jsonschema tag is invalid.
+// function logs an error if a jsonschema tag is invalid.
 func TestToolWithInputSchemaJsonSchemaTagError(t *testing.T) {
 	type TestInput struct {
 		Name  string `json:"name" jsonschema:"description=Person's name"` // Invalid jsonschema tag
@@ -772,36 +771,26 @@ func TestToolWithInputSchemaJsonSchemaTagError(t *testing.T) {
 	}
 
 	var (
-		expectedStderrPrefix = "For[mcp.TestInput]():" // jsonschema.For[T] error prefix
-		buf                  bytes.Buffer
-		origStderr, r, w     *os.File
-		err                  error
+		expectedError = "For[mcp.TestInput]():" // jsonschema.For[T] error prefix
+		buf           bytes.Buffer
 	)
 
-	// Redirect stderr
-	origStderr = os.Stderr
-	r, w, err = os.Pipe()
-	require.NoError(t, err)
-	os.Stderr = w
+	// Capture the default logger
+	origLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(origLogger) })
 
 	_ = NewTool("test_tool",
 		WithDescription("Test tool with input schema that has an invalid jsonschema tag"),
 		WithInputSchema[TestInput](),
 	)
 
-	// Restore stderr
-	err = w.Close()
-	require.NoError(t, err)
-	os.Stderr = origStderr
-
-	// Read stderr output into buffer
-	_, err = io.Copy(&buf, r)
-	require.NoError(t, err)
-	err = r.Close()
-	require.NoError(t, err)
-
-	if !strings.HasPrefix(buf.String(), expectedStderrPrefix) {
-		t.Errorf("expected stderr to have prefix %q, got %q", expectedStderrPrefix, buf.String())
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, "test_tool", line["tool"])
+	if errMsg, _ := line["error"].(string); !strings.HasPrefix(errMsg, expectedError) {
+		t.Errorf("expected logged error to have prefix %q, got %q", expectedError, errMsg)
 	}
 }
 
@@ -898,7 +887,7 @@ func TestToolWithOutputSchema(t *testing.T) {
 }
 
 // TestToolWithOutputSchemaJsonSchemaTagError tests that the WithOutputSchema
-// function prints an error to stderr if a jsonschema tag is invalid.
+// function logs an error if a jsonschema tag is invalid.
 func TestToolWithOutputSchemaJsonSchemaTagError(t *testing.T) {
 	type TestOutput struct {
 		Name  string `json:"name" jsonschema:"description=Person's name"` // Invalid jsonschema tag
@@ -907,36 +896,26 @@ func TestToolWithOutputSchemaJsonSchemaTagError(t *testing.T) {
 	}
 
 	var (
-		expectedStderrPrefix = "For[mcp.TestOutput]():" // jsonschema.For[T] error prefix
-		buf                  bytes.Buffer
-		origStderr, r, w     *os.File
-		err                  error
+		expectedError = "For[mcp.TestOutput]():" // jsonschema.For[T] error prefix
+		buf           bytes.Buffer
 	)
 
-	// Redirect stderr
-	origStderr = os.Stderr
-	r, w, err = os.Pipe()
-	require.NoError(t, err)
-	os.Stderr = w
+	// Capture the default logger
+	origLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(origLogger) })
 
 	_ = NewTool("test_tool",
 		WithDescription("Test tool with output schema that has an invalid jsonschema tag"),
 		WithOutputSchema[TestOutput](),
 	)
 
-	// Restore stderr
-	err = w.Close()
-	require.NoError(t, err)
-	os.Stderr = origStderr
-
-	// Read stderr output into buffer
-	_, err = io.Copy(&buf, r)
-	require.NoError(t, err)
-	err = r.Close()
-	require.NoError(t, err)
-
-	if !strings.HasPrefix(buf.String(), expectedStderrPrefix) {
-		t.Errorf("expected stderr to have prefix %q, got %q", expectedStderrPrefix, buf.String())
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, "test_tool", line["tool"])
+	if errMsg, _ := line["error"].(string); !strings.HasPrefix(errMsg, expectedError) {
+		t.Errorf("expected logged error to have prefix %q, got %q", expectedError, errMsg)
 	}
 }
 
@@ -2715,3 +2694,26 @@ func TestCallToolResultPreservesLargeIntegerStructuredContent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, string(parsed.RawStructuredContent), "9223372036854775807")
 }
+
+func TestWithOutputSchemaMap(t *testing.T) {
+	schema := map[string]any{
+		"properties": map[string]any{
+			"total": map[string]any{"type": "number"},
+		},
+		"required": []any{"total"},
+	}
+	tool := NewTool("sum", WithOutputSchemaMap(schema))
+
+	_, hasType := schema["type"]
+	assert.False(t, hasType, "the caller's map is not modified")
+
+	data, err := json.Marshal(tool)
+	require.NoError(t, err)
+	var toolData struct {
+		OutputSchema map[string]any `json:"outputSchema"`
+	}
+	require.NoError(t, json.Unmarshal(data, &toolData))
+	assert.Equal(t, "object", toolData.OutputSchema["type"])
+	assert.Equal(t, []any{"total"}, toolData.OutputSchema["required"])
+	assert.Contains(t, toolData.OutputSchema["properties"], "total")
+}
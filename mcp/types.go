@@ -8,6 +8,7 @@ import (
 	"maps"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/yosida95/uritemplate/v3"
 )
@@ -70,6 +71,11 @@ const (
 	// MethodNotificationElicitationComplete notifies when a URL mode elicitation completes.
 	MethodNotificationElicitationComplete MCPMethod = "notifications/elicitation/complete"
 
+	// MethodNotificationElicitationReminder reminds the client that an
+	// elicitation is still waiting for the user. This is an mcp-go extension;
+	// clients that do not know it ignore it.
+	MethodNotificationElicitationReminder MCPMethod = "notifications/elicitation/reminder"
+
 	// MethodListRoots requests roots list from the client during interactions.
 	// https://modelcontextprotocol.io/specification/2025-06-18/client/roots
 	MethodListRoots MCPMethod = "roots/list"
@@ -90,6 +96,16 @@ const (
 	// https://modelcontextprotocol.io/specification/2025-11-25/basic/utilities/tasks
 	MethodTasksCancel MCPMethod = "tasks/cancel"
 
+	// MethodTasksSubscribe narrows the task status notifications sent to the
+	// session. It is an extension to the MCP specification, available when
+	// the server advertises the tasks.subscribe capability.
+	MethodTasksSubscribe MCPMethod = "tasks/subscribe"
+
+	// MethodTasksClaim transfers a task to the requesting session. It is an
+	// extension to the MCP specification, available when the server
+	// advertises the tasks.claim capability.
+	MethodTasksClaim MCPMethod = "tasks/claim"
+
 	// MethodNotificationInitialized indicates that the client completed initialization.
 	// https://modelcontextprotocol.io/specification/2024-11-05/basic/lifecycle/#initialization
 	MethodNotificationInitialized MCPMethod = "notifications/initialized"
@@ -609,6 +625,9 @@ type ServerCapabilities struct {
 	Tasks *TasksCapability `json:"tasks,omitempty"`
 	// Present if the server supports completions requests to the client.
 	Completions *struct{} `json:"completions,omitempty"`
+	// Meta carries non-standard metadata about the capabilities, such as the
+	// LatencyHints of the server.
+	Meta *Meta `json:"_meta,omitempty"`
 }
 
 // IconTheme is the background theme an icon is designed to be displayed on.
@@ -758,6 +777,10 @@ type ReadResourceParams struct {
 	URI string `json:"uri"`
 	// Arguments to pass to the resource handler
 	Arguments map[string]any `json:"arguments,omitempty"`
+	// Cursor continues reading a resource streamed in chunks, as returned
+	// in ReadResourceResult.NextCursor. This is an extension to the MCP
+	// specification.
+	Cursor Cursor `json:"cursor,omitempty"`
 	// Meta carries protocol-level metadata (e.g. W3C traceparent, progressToken).
 	Meta *Meta `json:"_meta,omitempty"`
 }
@@ -767,6 +790,10 @@ type ReadResourceParams struct {
 type ReadResourceResult struct {
 	Result
 	Contents []ResourceContents `json:"contents"` // Can be TextResourceContents or BlobResourceContents
+	// NextCursor is set when Contents is one chunk of a larger resource;
+	// pass it in ReadResourceParams.Cursor to read the next chunk. This is
+	// an extension to the MCP specification.
+	NextCursor Cursor `json:"nextCursor,omitempty"`
 }
 
 // ResourceListChangedNotification is an optional notification from the server
@@ -1064,6 +1091,10 @@ type ElicitationResponse struct {
 	// Content contains the user's response data if they accepted.
 	// Should conform to the requestedSchema from the ElicitationRequest.
 	Content any `json:"content,omitempty"`
+	// Reason optionally explains why the user declined or cancelled, so
+	// servers can adapt instead of treating every non-accept identically.
+	// It is not set when the user accepted.
+	Reason ElicitationDeclineReason `json:"reason,omitempty"`
 }
 
 // ElicitationResponseAction indicates how the user responded to an elicitation request.
@@ -1078,6 +1109,27 @@ const (
 	ElicitationResponseActionCancel ElicitationResponseAction = "cancel"
 )
 
+// ElicitationDeclineReason is a machine-readable reason for declining or
+// cancelling an elicitation request. Clients may send values other than
+// the predefined ones.
+type ElicitationDeclineReason string
+
+const (
+	// ElicitationDeclineReasonUserBusy indicates the user cannot answer right
+	// now but may answer if asked again later.
+	ElicitationDeclineReasonUserBusy ElicitationDeclineReason = "user_busy"
+	// ElicitationDeclineReasonPrivacy indicates the user does not want to
+	// share the requested information.
+	ElicitationDeclineReasonPrivacy ElicitationDeclineReason = "privacy"
+	// ElicitationDeclineReasonNotApplicable indicates the request does not
+	// apply to the user or the client cannot present it.
+	ElicitationDeclineReasonNotApplicable ElicitationDeclineReason = "not_applicable"
+	// ElicitationDeclineReasonTimeout indicates the user did not answer in
+	// time. Servers set it on the cancel result they synthesize when an
+	// elicitation expires.
+	ElicitationDeclineReasonTimeout ElicitationDeclineReason = "timeout"
+)
+
 /* Sampling */
 
 const (
@@ -1095,6 +1147,8 @@ type CreateMessageRequest struct {
 }
 
 type CreateMessageParams struct {
+	// Meta carries protocol-level metadata (e.g. W3C traceparent, baggage).
+	Meta             *Meta             `json:"_meta,omitempty"`
 	Messages         []SamplingMessage `json:"messages"`
 	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
 	SystemPrompt     string            `json:"systemPrompt,omitempty"`
@@ -1549,6 +1603,13 @@ type TasksCapability struct {
 	Cancel *struct{} `json:"cancel,omitempty"`
 	// Requests that can be augmented with task metadata.
 	Requests *TaskRequestsCapability `json:"requests,omitempty"`
+	// Whether the server supports tasks/subscribe to filter task status
+	// notifications. This is an extension to the MCP specification.
+	Subscribe *struct{} `json:"subscribe,omitempty"`
+	// Whether the server supports tasks/claim to take over tasks created by
+	// another session of the same user. This is an extension to the MCP
+	// specification.
+	Claim *struct{} `json:"claim,omitempty"`
 }
 
 // TaskRequestsCapability indicates which request types support task augmentation.
@@ -1622,6 +1683,9 @@ func (t Task) GetName() string {
 type TaskParams struct {
 	// Requested duration in milliseconds to retain task from creation.
 	TTL *int64 `json:"ttl,omitempty"`
+	// Meta carries extension fields such as the scheduling hints set with
+	// WithTaskScheduling.
+	Meta *Meta `json:"_meta,omitempty"`
 }
 
 // CreateTaskResult is returned immediately when a task-augmented request is accepted.
@@ -1655,6 +1719,51 @@ type GetTaskResult struct {
 type ListTasksRequest struct {
 	PaginatedRequest
 	Header http.Header `json:"-"`
+	// Filter narrows down the listed tasks. It is sent in params, next to
+	// the cursor.
+	Filter TaskFilter `json:"-"`
+}
+
+// TaskFilter holds the filters of tasks/list, which are an extension to the
+// MCP specification; servers that don't support them ignore them. Filters
+// combine with AND.
+type TaskFilter struct {
+	// Status only lists tasks in one of the given statuses.
+	Status []TaskStatus `json:"status,omitempty"`
+	// ToolName only lists tasks created by calls to the given tool.
+	ToolName string `json:"toolName,omitempty"`
+	// CreatedAfter only lists tasks created strictly after the given ISO
+	// 8601 (RFC 3339) timestamp.
+	CreatedAfter string `json:"createdAfter,omitempty"`
+}
+
+// listTasksParams are the params of tasks/list on the wire.
+type listTasksParams struct {
+	PaginatedParams
+	TaskFilter
+}
+
+// MarshalJSON sends the filter in params.
+func (r ListTasksRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Method string          `json:"method"`
+		Params listTasksParams `json:"params,omitzero"`
+	}{r.Method, listTasksParams{r.Params, r.Filter}})
+}
+
+// UnmarshalJSON reads the filter from params.
+func (r *ListTasksRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Method string          `json:"method"`
+		Params listTasksParams `json:"params"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Method = raw.Method
+	r.Params = raw.Params.PaginatedParams
+	r.Filter = raw.Params.TaskFilter
+	return nil
 }
 
 // ListTasksResult returns a list of tasks.
@@ -1701,6 +1810,62 @@ type CancelTaskResult struct {
 	Task
 }
 
+// TaskSubscriptionScope selects the tasks whose status changes are notified
+// to a session.
+type TaskSubscriptionScope string
+
+const (
+	// TaskSubscriptionAll notifies status changes of every task visible to
+	// the session. This is the default.
+	TaskSubscriptionAll TaskSubscriptionScope = "all"
+	// TaskSubscriptionOwn only notifies status changes of tasks created by
+	// the session.
+	TaskSubscriptionOwn TaskSubscriptionScope = "own"
+	// TaskSubscriptionNone notifies no status changes, except for the tasks
+	// listed in SubscribeTasksParams.TaskIds.
+	TaskSubscriptionNone TaskSubscriptionScope = "none"
+)
+
+// SubscribeTasksRequest selects which notifications/tasks/status
+// notifications the server sends to the session. Each request replaces the
+// previous subscription. This is an extension to the MCP specification;
+// servers advertise it with the tasks.subscribe capability.
+type SubscribeTasksRequest struct {
+	Request
+	Header http.Header          `json:"-"`
+	Params SubscribeTasksParams `json:"params"`
+}
+
+type SubscribeTasksParams struct {
+	// Scope selects the tasks whose status changes are notified. Defaults
+	// to TaskSubscriptionAll.
+	Scope TaskSubscriptionScope `json:"scope,omitempty"`
+	// TaskIds lists tasks whose status changes are notified regardless of
+	// Scope.
+	TaskIds []string `json:"taskIds,omitempty"`
+}
+
+// ClaimTaskRequest transfers a task created by another session of the same
+// authenticated user to the requesting session, for example after the user
+// restarted their host and reconnected. This is an extension to the MCP
+// specification; servers advertise it with the tasks.claim capability.
+type ClaimTaskRequest struct {
+	Request
+	Header http.Header     `json:"-"`
+	Params ClaimTaskParams `json:"params"`
+}
+
+type ClaimTaskParams struct {
+	// TaskId is the ID of the task to claim.
+	TaskId string `json:"taskId"`
+}
+
+// ClaimTaskResult returns the state of the claimed task.
+type ClaimTaskResult struct {
+	Result
+	Task
+}
+
 // TaskStatusNotification is sent when a task's status changes.
 type TaskStatusNotification struct {
 	Notification
@@ -1824,3 +1989,26 @@ func NewElicitationCompleteNotification(elicitationID string) JSONRPCNotificatio
 		},
 	}
 }
+
+// NewElicitationReminderNotification creates a reminder for a pending
+// elicitation. message repeats the request's message, elicitationID is set
+// for URL mode requests only, and remaining is how long the server keeps
+// waiting before giving up.
+func NewElicitationReminderNotification(message string, elicitationID string, remaining time.Duration) JSONRPCNotification {
+	fields := map[string]any{
+		"message":          message,
+		"remainingSeconds": int(remaining.Round(time.Second) / time.Second),
+	}
+	if elicitationID != "" {
+		fields["elicitationId"] = elicitationID
+	}
+	return JSONRPCNotification{
+		JSONRPC: JSONRPC_VERSION,
+		Notification: Notification{
+			Method: string(MethodNotificationElicitationReminder),
+			Params: NotificationParams{
+				AdditionalFields: fields,
+			},
+		},
+	}
+}
@@ -232,49 +232,59 @@ func NewPromptMessage(role Role, content Content) PromptMessage {
 
 // NewTextContent
 // Helper function to create a new TextContent
-func NewTextContent(text string) TextContent {
-	return TextContent{
+func NewTextContent(text string, opts ...ContentOption) TextContent {
+	content := TextContent{
 		Type: ContentTypeText,
 		Text: text,
 	}
+	applyContentOptions(&content.Annotated, opts)
+	return content
 }
 
 // NewImageContent
 // Helper function to create a new ImageContent
-func NewImageContent(data, mimeType string) ImageContent {
-	return ImageContent{
+func NewImageContent(data, mimeType string, opts ...ContentOption) ImageContent {
+	content := ImageContent{
 		Type:     ContentTypeImage,
 		Data:     data,
 		MIMEType: mimeType,
 	}
+	applyContentOptions(&content.Annotated, opts)
+	return content
 }
 
 // Helper function to create a new AudioContent
-func NewAudioContent(data, mimeType string) AudioContent {
-	return AudioContent{
+func NewAudioContent(data, mimeType string, opts ...ContentOption) AudioContent {
+	content := AudioContent{
 		Type:     ContentTypeAudio,
 		Data:     data,
 		MIMEType: mimeType,
 	}
+	applyContentOptions(&content.Annotated, opts)
+	return content
 }
 
 // Helper function to create a new ResourceLink
-func NewResourceLink(uri, name, description, mimeType string) ResourceLink {
-	return ResourceLink{
+func NewResourceLink(uri, name, description, mimeType string, opts ...ContentOption) ResourceLink {
+	content := ResourceLink{
 		Type:        ContentTypeLink,
 		URI:         uri,
 		Name:        name,
 		Description: description,
 		MIMEType:    mimeType,
 	}
+	applyContentOptions(&content.Annotated, opts)
+	return content
 }
 
 // Helper function to create a new EmbeddedResource
-func NewEmbeddedResource(resource ResourceContents) EmbeddedResource {
-	return EmbeddedResource{
+func NewEmbeddedResource(resource ResourceContents, opts ...ContentOption) EmbeddedResource {
+	content := EmbeddedResource{
 		Type:     ContentTypeResource,
 		Resource: resource,
 	}
+	applyContentOptions(&content.Annotated, opts)
+	return content
 }
 
 // NewToolUseContent creates a new ToolUseContent with the given id, tool name, and input arguments.
@@ -878,6 +888,10 @@ func ParseReadResourceResult(rawMessage *json.RawMessage) (*ReadResourceResult,
 		}
 	}
 
+	if nextCursor, ok := jsonContent["nextCursor"].(string); ok {
+		result.NextCursor = Cursor(nextCursor)
+	}
+
 	contents, ok := jsonContent["contents"]
 	if !ok {
 		return nil, fmt.Errorf("contents is missing")
@@ -1127,6 +1141,31 @@ func ParseCancelTaskResult(rawMessage *json.RawMessage) (*CancelTaskResult, erro
 	return &cancelResult, nil
 }
 
+// ParseClaimTaskResult parses a JSON message and converts it to a ClaimTaskResult.
+func ParseClaimTaskResult(rawMessage *json.RawMessage) (*ClaimTaskResult, error) {
+	if rawMessage == nil {
+		return nil, fmt.Errorf("response is nil")
+	}
+
+	var jsonContent map[string]any
+	if err := json.Unmarshal(*rawMessage, &jsonContent); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	convertResult := GetTaskResult{}
+	jsonToTask(jsonContent, &convertResult)
+	claimResult := ClaimTaskResult(convertResult)
+
+	meta, ok := jsonContent["_meta"]
+	if ok {
+		if metaMap, ok := meta.(map[string]any); ok {
+			claimResult.Meta = NewMetaFromMap(metaMap)
+		}
+	}
+
+	return &claimResult, nil
+}
+
 // ParseListTasksResult parses a JSON message and converts it to a ListTasksResult.
 func ParseListTasksResult(rawMessage *json.RawMessage) (*ListTasksResult, error) {
 	if rawMessage == nil {
@@ -1189,25 +1228,27 @@ func ParseTaskResultResult(rawMessage *json.RawMessage) (*TaskResultResult, erro
 		}
 	}
 
-	result, ok := jsonContent["result"]
-	if ok {
-		if resultMap, ok := result.(map[string]any); ok {
-			if isError, ok := resultMap["isError"].(bool); ok {
-				resultResult.IsError = isError
-			}
-			if contents, ok := resultMap["content"].([]any); ok {
-				for _, content := range contents {
-					if contentMap, ok := content.(map[string]any); ok {
-						parsedContent, err := ParseContent(contentMap)
-						if err != nil {
-							return nil, err
-						}
-						resultResult.Content = append(resultResult.Content, parsedContent)
-					}
+	// The tool result fields are sent at the top level; older servers
+	// nested them under "result".
+	resultMap := jsonContent
+	if nested, ok := jsonContent["result"].(map[string]any); ok {
+		resultMap = nested
+	}
+	if isError, ok := resultMap["isError"].(bool); ok {
+		resultResult.IsError = isError
+	}
+	if contents, ok := resultMap["content"].([]any); ok {
+		for _, content := range contents {
+			if contentMap, ok := content.(map[string]any); ok {
+				parsedContent, err := ParseContent(contentMap)
+				if err != nil {
+					return nil, err
 				}
+				resultResult.Content = append(resultResult.Content, parsedContent)
 			}
 		}
 	}
+	resultResult.StructuredContent = resultMap["structuredContent"]
 
 	return &resultResult, nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/server/servertest"
 )
 
 // Server encapsulates an MCP server and manages resources like pipes and context.
@@ -113,6 +114,12 @@ func (s *Server) AddResources(resources ...server.ServerResource) {
 	s.resources = append(s.resources, resources...)
 }
 
+// AddFixtures adds the resources and prompts of f to an unstarted server.
+func (s *Server) AddFixtures(f *servertest.Fixtures) {
+	s.AddResources(f.Resources...)
+	s.AddPrompts(f.Prompts...)
+}
+
 // AddResourceTemplate adds a resource template to an unstarted server.
 func (s *Server) AddResourceTemplate(template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc) {
 	s.resourceTemplates = append(s.resourceTemplates, server.ServerResourceTemplate{
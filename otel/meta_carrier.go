@@ -10,9 +10,13 @@ import (
 )
 
 // NewMetaPropagator returns a tracing.MetaPropagator backed by W3C TraceContext
-// that reads and writes trace context in the MCP _meta property bag per SEP-414.
+// and W3C Baggage that reads and writes trace context and baggage in the MCP
+// _meta property bag per SEP-414.
 func NewMetaPropagator() tracing.MetaPropagator {
-	return WrapMetaPropagator(propagation.TraceContext{})
+	return WrapMetaPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
 }
 
 // WrapMetaPropagator adapts any TextMapPropagator as a tracing.MetaPropagator
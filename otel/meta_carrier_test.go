@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 
@@ -284,3 +285,20 @@ func splitTraceparent(s string) []string {
 	parts = append(parts, s[start:])
 	return parts
 }
+
+// TestNewMetaPropagator_CarriesBaggage checks that the default _meta
+// propagator round-trips W3C baggage alongside trace context.
+func TestNewMetaPropagator_CarriesBaggage(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+
+	p := otelmcp.NewMetaPropagator()
+	meta := p.InjectMeta(baggage.ContextWithBaggage(t.Context(), bag), nil)
+	require.NotNil(t, meta)
+	assert.Equal(t, "tenant=acme", meta.AdditionalFields["baggage"])
+
+	ctx := p.ExtractMeta(t.Context(), meta)
+	assert.Equal(t, "acme", baggage.FromContext(ctx).Member("tenant").Value())
+}
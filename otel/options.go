@@ -9,7 +9,10 @@ import (
 )
 
 // WithServerTracing installs an OpenTelemetry tracer, a W3C TraceContext header
-// propagator, and a W3C TraceContext _meta propagator (SEP-414) on the server.
+// propagator, and a W3C TraceContext and Baggage _meta propagator (SEP-414) on
+// the server. Trace context and baggage found in the _meta of inbound requests
+// are extracted into the handler context, and injected into the _meta of
+// sampling and elicitation requests the server sends.
 func WithServerTracing(t trace.Tracer) server.ServerOption {
 	tracer := NewTracer(t)
 	propagator := NewPropagator()
@@ -42,7 +45,10 @@ func WithServerMetaPropagator(p propagation.TextMapPropagator) server.ServerOpti
 }
 
 // WithClientTracing installs an OpenTelemetry tracer, a W3C TraceContext header
-// propagator, and a W3C TraceContext _meta propagator (SEP-414) on the client.
+// propagator, and a W3C TraceContext and Baggage _meta propagator (SEP-414) on
+// the client. Trace context and baggage are injected into the _meta of
+// outgoing requests and extracted from the _meta of sampling and elicitation
+// requests into the handler context.
 func WithClientTracing(t trace.Tracer) client.ClientOption {
 	tracer := NewTracer(t)
 	propagator := NewPropagator()
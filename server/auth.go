@@ -0,0 +1,326 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrUnauthenticated is returned by the authenticators of this package when
+// a request carries no credentials or unknown ones.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Subject identifies the caller, such as a user ID or the name of an
+	// API key.
+	Subject string
+	// Scopes are the permissions granted to the caller.
+	Scopes []string
+	// Claims holds further attributes of the caller, such as those of a
+	// token.
+	Claims map[string]any
+}
+
+// HasScope reports whether the identity was granted scope.
+func (i Identity) HasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator authenticates an HTTP request. It returns an error if the
+// request must be rejected.
+type Authenticator func(r *http.Request) (Identity, error)
+
+// WithAuthenticator makes the SSE and streamable HTTP transports
+// authenticate every request with authenticate before handling it, and so
+// before any session is created. Requests it returns ErrUnauthenticated for
+// are rejected with 401 Unauthorized and those it returns a *TokenError for
+// as the error says. Any other error means the authenticator could not
+// decide, for example because the key set of a JWTValidator is unreachable,
+// and is answered with 503 Service Unavailable. For the accepted requests,
+// the identity is stored in the request context, where handlers and hooks
+// find it with IdentityFromContext. CORS preflight requests are not
+// authenticated.
+//
+// The WWW-Authenticate challenge of rejected requests points to the
+// server's protected resource metadata, when the transport serves it (see
+// WithProtectedResourceMetadata), as the MCP authorization spec requires.
+//
+// A session is bound to the Subject of the identity that created it: later
+// requests of the session must authenticate as the same subject, or are
+// rejected with 403 Forbidden. Since requests are authenticated one by one,
+// a session survives the renewal of an expiring token for the same subject.
+// Sessions report their subject through SessionWithSubject, ExportSession
+// carries it to another instance, and a SessionStore implementing
+// SessionSubjectStore shares it between replicas.
+func WithAuthenticator(authenticate Authenticator) ServerOption {
+	return func(s *MCPServer) {
+		s.authenticator = authenticate
+	}
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity of the caller stored by
+// WithAuthenticator, and false if there is none.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Error codes of TokenError, from RFC 6750 section 3.1.
+const (
+	TokenErrorInvalidRequest    = "invalid_request"
+	TokenErrorInvalidToken      = "invalid_token"
+	TokenErrorInsufficientScope = "insufficient_scope"
+)
+
+// TokenError is an error of an authenticator about the bearer token of a
+// request. It is reported to the client in the WWW-Authenticate challenge
+// of the response, which is 403 Forbidden for insufficient_scope, 400 Bad
+// Request for invalid_request and 401 Unauthorized otherwise.
+type TokenError struct {
+	// Code is one of the TokenError* constants.
+	Code        string
+	Description string
+	// Scope lists the scopes, space-separated, that the request needs.
+	Scope string
+}
+
+func invalidToken(description string) *TokenError {
+	return &TokenError{Code: TokenErrorInvalidToken, Description: description}
+}
+
+func (e *TokenError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+func (e *TokenError) statusCode() int {
+	switch e.Code {
+	case TokenErrorInsufficientScope:
+		return http.StatusForbidden
+	case TokenErrorInvalidRequest:
+		return http.StatusBadRequest
+	}
+	return http.StatusUnauthorized
+}
+
+// bearerChallenge returns the WWW-Authenticate challenge for a request
+// rejected with err. resourceMetadata is the URL of the server's protected
+// resource metadata, if any, which MCP clients use to discover the
+// authorization server.
+func bearerChallenge(err error, resourceMetadata string) string {
+	var params []string
+	var tokenErr *TokenError
+	if errors.As(err, &tokenErr) {
+		params = append(params, authParam("error", tokenErr.Code))
+		if tokenErr.Description != "" {
+			params = append(params, authParam("error_description", tokenErr.Description))
+		}
+		if tokenErr.Scope != "" {
+			params = append(params, authParam("scope", tokenErr.Scope))
+		}
+	}
+	if resourceMetadata != "" {
+		params = append(params, authParam("resource_metadata", resourceMetadata))
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+func authParam(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return name + `="` + value + `"`
+}
+
+// authenticate runs the server's authenticator on r and returns ctx with
+// the caller's identity. It returns false, after writing the error response
+// with its challenge to w, if the request is rejected. Without an
+// authenticator it returns ctx unchanged.
+func (s *MCPServer) authenticate(ctx context.Context, w HTTPResponseWriter, r *http.Request, resourceMetadata string) (context.Context, bool) {
+	if s.authenticator == nil {
+		return ctx, true
+	}
+	identity, err := s.authenticator(r)
+	if err != nil {
+		var tokenErr *TokenError
+		status := http.StatusServiceUnavailable
+		switch {
+		case errors.As(err, &tokenErr):
+			status = tokenErr.statusCode()
+		case errors.Is(err, ErrUnauthenticated):
+			status = http.StatusUnauthorized
+		}
+		if status == http.StatusServiceUnavailable {
+			s.logger().Error("failed to authenticate request", "error", err)
+		} else {
+			s.logger().Warn("rejected unauthenticated request", "error", err)
+			w.Header().Set("WWW-Authenticate", bearerChallenge(err, resourceMetadata))
+		}
+		writeHTTPError(w, http.StatusText(status), status)
+		return ctx, false
+	}
+	return context.WithValue(ctx, identityKey{}, identity), true
+}
+
+// errSessionOwner is the response to requests for a session created by
+// another subject.
+const errSessionOwner = "Session belongs to another identity"
+
+// ownsSession reports whether the caller authenticated in ctx may use a
+// session created by subject.
+func ownsSession(ctx context.Context, subject string) bool {
+	identity, _ := IdentityFromContext(ctx)
+	return identity.Subject == subject
+}
+
+// SessionWithSubject is implemented by sessions that can be bound to the
+// subject of the identity that created them; see WithAuthenticator.
+type SessionWithSubject interface {
+	ClientSession
+	// Subject returns the subject the session is bound to, or "" if it is
+	// bound to none.
+	Subject() string
+	// SetSubject binds the session to subject, for example when it is
+	// restored by ImportSession. A session already bound keeps its subject.
+	SetSubject(subject string) error
+}
+
+// SessionSubjectStore is implemented by a SessionStore that shares the
+// subject each session is bound to between replicas, so every replica
+// rejects requests of other subjects, not only the one that created the
+// session.
+type SessionSubjectStore interface {
+	// BindSubject binds sessionID to subject.
+	BindSubject(ctx context.Context, sessionID, subject string) error
+	// Subject returns the subject sessionID is bound to, and false if it is
+	// bound to none.
+	Subject(ctx context.Context, sessionID string) (subject string, ok bool, err error)
+}
+
+// sessionSubjectsStore holds the subject each session of a transport is
+// bound to. Bindings are cached locally and, with a shared store, looked up
+// there when another replica created the session.
+type sessionSubjectsStore struct {
+	local  sync.Map // sessionID -> subject
+	shared SessionSubjectStore
+}
+
+// bind binds sessionID to subject unless it is bound already.
+func (s *sessionSubjectsStore) bind(ctx context.Context, sessionID, subject string) error {
+	if _, loaded := s.local.LoadOrStore(sessionID, subject); loaded || s.shared == nil {
+		return nil
+	}
+	if err := s.shared.BindSubject(ctx, sessionID, subject); err != nil {
+		s.local.CompareAndDelete(sessionID, subject)
+		return err
+	}
+	return nil
+}
+
+// get returns the subject sessionID is bound to, as known locally.
+func (s *sessionSubjectsStore) get(sessionID string) (string, bool) {
+	subject, ok := s.local.Load(sessionID)
+	if !ok {
+		return "", false
+	}
+	return subject.(string), true
+}
+
+// lookup returns the subject sessionID is bound to, asking the shared store
+// if it is not known locally.
+func (s *sessionSubjectsStore) lookup(ctx context.Context, sessionID string) (string, bool, error) {
+	if subject, ok := s.get(sessionID); ok || s.shared == nil {
+		return subject, ok, nil
+	}
+	subject, ok, err := s.shared.Subject(ctx, sessionID)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	actual, _ := s.local.LoadOrStore(sessionID, subject)
+	return actual.(string), true, nil
+}
+
+func (s *sessionSubjectsStore) delete(sessionID string) {
+	s.local.Delete(sessionID)
+}
+
+// bindSessionSubject binds sessionID to the subject of the identity
+// authenticated in ctx, if any.
+func (s *StreamableHTTPServer) bindSessionSubject(ctx context.Context, sessionID string) error {
+	if identity, ok := IdentityFromContext(ctx); ok && sessionID != "" {
+		return s.sessionSubjects.bind(ctx, sessionID, identity.Subject)
+	}
+	return nil
+}
+
+// authorizeSession checks that the caller authenticated in ctx created the
+// session sessionID. It writes the error response and returns false if the
+// request must be rejected.
+func (s *StreamableHTTPServer) authorizeSession(ctx context.Context, w HTTPResponseWriter, sessionID string) bool {
+	if sessionID == "" {
+		return true
+	}
+	subject, ok, err := s.sessionSubjects.lookup(ctx, sessionID)
+	if err != nil {
+		s.logger.Error("Failed to look up session subject", "session", sessionID, "err", err)
+		writeHTTPError(w, "Failed to look up session", http.StatusServiceUnavailable)
+		return false
+	}
+	if ok && !ownsSession(ctx, subject) {
+		writeHTTPError(w, errSessionOwner, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// BearerAuthenticator returns an Authenticator that passes the bearer token
+// of the Authorization header to validate. Requests without one are
+// rejected with ErrUnauthenticated.
+func BearerAuthenticator(validate func(ctx context.Context, token string) (Identity, error)) Authenticator {
+	return func(r *http.Request) (Identity, error) {
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return Identity{}, ErrUnauthenticated
+		}
+		return validate(r.Context(), strings.TrimSpace(token))
+	}
+}
+
+// APIKeyAuthenticator returns an Authenticator that looks up the API key in
+// the given request header, such as "X-API-Key", in keys. Requests without
+// a known key are rejected with ErrUnauthenticated.
+func APIKeyAuthenticator(header string, keys map[string]Identity) Authenticator {
+	return func(r *http.Request) (Identity, error) {
+		key := r.Header.Get(header)
+		if key == "" {
+			return Identity{}, ErrUnauthenticated
+		}
+		// Compare against every key so that timing does not reveal which
+		// prefix matched.
+		var match Identity
+		found := 0
+		for k, identity := range keys {
+			if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+				match, found = identity, 1
+			}
+		}
+		if found == 0 {
+			return Identity{}, ErrUnauthenticated
+		}
+		return match, nil
+	}
+}
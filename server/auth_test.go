@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBearerAuthenticator(t *testing.T) {
+	errBadToken := errors.New("bad token")
+	authenticate := BearerAuthenticator(func(_ context.Context, token string) (Identity, error) {
+		if token != "secret" {
+			return Identity{}, errBadToken
+		}
+		return Identity{Subject: "alice", Scopes: []string{"read"}}, nil
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   Identity
+		err    error
+	}{
+		{name: "valid", header: "Bearer secret", want: Identity{Subject: "alice", Scopes: []string{"read"}}},
+		{name: "scheme is case insensitive", header: "bearer secret", want: Identity{Subject: "alice", Scopes: []string{"read"}}},
+		{name: "invalid token", header: "Bearer nope", err: errBadToken},
+		{name: "missing", err: ErrUnauthenticated},
+		{name: "other scheme", header: "Basic c2VjcmV0", err: ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			identity, err := authenticate(r)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, identity)
+			assert.True(t, identity.HasScope("read"))
+			assert.False(t, identity.HasScope("write"))
+		})
+	}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticate := APIKeyAuthenticator("X-API-Key", map[string]Identity{
+		"key-1": {Subject: "ci"},
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	_, err := authenticate(r)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	r.Header.Set("X-API-Key", "key-2")
+	_, err = authenticate(r)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	r.Header.Set("X-API-Key", "key-1")
+	identity, err := authenticate(r)
+	require.NoError(t, err)
+	assert.Equal(t, "ci", identity.Subject)
+}
+
+func TestWithAuthenticator_StreamableHTTP(t *testing.T) {
+	identities := make(chan Identity, 1)
+	mcpServer := NewMCPServer("test", "1.0.0",
+		WithAuthenticator(APIKeyAuthenticator("X-API-Key", map[string]Identity{
+			"key-1":   {Subject: "ci"},
+			"key-1b":  {Subject: "ci"},
+			"key-bob": {Subject: "bob"},
+		})),
+	)
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		identity, _ := IdentityFromContext(ctx)
+		identities <- identity
+		return mcp.NewToolResultText(identity.Subject), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	post := func(key, session string, body any) *http.Response {
+		t.Helper()
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(b))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		if session != "" {
+			req.Header.Set(HeaderKeySessionID, session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("rejects before creating a session", func(t *testing.T) {
+		resp := post("", "", initRequest)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+		assert.Empty(t, resp.Header.Get(HeaderKeySessionID))
+
+		resp = post("wrong", "", initRequest)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("propagates the identity to handlers", func(t *testing.T) {
+		resp := post("key-1", "", initRequest)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		session := resp.Header.Get(HeaderKeySessionID)
+		require.NotEmpty(t, session)
+
+		resp = post("key-1", session, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      2,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "whoami"},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "ci", (<-identities).Subject)
+
+		resp = post("", session, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      3,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "whoami"},
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("binds the session to its subject", func(t *testing.T) {
+		resp := post("key-1", "", initRequest)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		session := resp.Header.Get(HeaderKeySessionID)
+		ping := map[string]any{"jsonrpc": "2.0", "id": 2, "method": "ping"}
+
+		resp = post("key-bob", session, ping)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// Other credentials of the same subject, such as a renewed token,
+		// may use the session.
+		resp = post("key-1b", session, ping)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestWithAuthenticator_Unavailable(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0",
+		WithAuthenticator(func(r *http.Request) (Identity, error) {
+			switch r.Header.Get("X-Outcome") {
+			case "expired":
+				return Identity{}, invalidToken("token expired")
+			case "missing":
+				return Identity{}, ErrUnauthenticated
+			}
+			return Identity{}, errors.New("fetch JWKS: connection refused")
+		}),
+	)
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	for outcome, status := range map[string]int{
+		"expired":     http.StatusUnauthorized,
+		"missing":     http.StatusUnauthorized,
+		"unreachable": http.StatusServiceUnavailable,
+	} {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Outcome", outcome)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode, outcome)
+		if status == http.StatusServiceUnavailable {
+			assert.Empty(t, resp.Header.Get("WWW-Authenticate"), "the client is not asked for other credentials")
+		}
+	}
+}
+
+func TestWithAuthenticator_Handle(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0",
+		WithAuthenticator(APIKeyAuthenticator("X-API-Key", nil)),
+	)
+	httpServer := NewStreamableHTTPServer(mcpServer)
+
+	rec := httptest.NewRecorder()
+	httpServer.Handle(newHTTPResponseWriterAdapter(rec), &HTTPRequest{
+		Method: http.MethodPost,
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+		Body:   []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`),
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestWithAuthenticator_SSE(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0",
+		WithAuthenticator(APIKeyAuthenticator("X-API-Key", nil)),
+	)
+	server := NewTestServer(mcpServer)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/sse")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/message?sessionId=x", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestWithAuthenticator_SSESessionSubject(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0",
+		WithAuthenticator(APIKeyAuthenticator("X-API-Key", map[string]Identity{
+			"key-alice": {Subject: "alice"},
+			"key-bob":   {Subject: "bob"},
+		})),
+	)
+	server := NewTestServer(mcpServer)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/sse", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "key-alice")
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	_, data := readSSEEvents(t, bufio.NewScanner(stream.Body), 1)
+	endpoint := data[0]
+	if !strings.HasPrefix(endpoint, "http") {
+		endpoint = server.URL + endpoint
+	}
+
+	post := func(key string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusForbidden, post("key-bob"))
+	assert.Equal(t, http.StatusAccepted, post("key-alice"))
+}
+
+func TestIdentityFromContext_Missing(t *testing.T) {
+	_, ok := IdentityFromContext(t.Context())
+	assert.False(t, ok)
+}
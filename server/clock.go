@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Clock tells the current time. Inject one with WithClock to make task
+// timestamps deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// IDSource generates unique identifiers. Inject one with WithIDSource to
+// make task and session IDs deterministic in tests.
+type IDSource interface {
+	NewID() string
+}
+
+// WithClock sets the clock the server reads the time from: task timestamps
+// (createdAt, lastUpdatedAt), task durations reported to task hooks and task
+// expiry, as well as the durations in metrics, request logs and replay
+// reports, the timestamps of the stats and health endpoints and debug
+// stream, and the budgets of log and progress rate limits. The default is
+// the system clock. Timers, such as task TTL cleanup, still run on real time.
+func WithClock(clock Clock) ServerOption {
+	return func(s *MCPServer) {
+		s.clock = clock
+	}
+}
+
+// WithIDSource sets the generator of task IDs and of session IDs minted by
+// the built-in transports: in-process sessions, StreamServer sessions, SSE
+// sessions unless WithSessionIDGenerator is set, and streamable HTTP
+// sessions using StatelessGeneratingSessionIdManager or
+// InsecureStatefulSessionIdManager. Session IDs keep their transport
+// prefix. The default generates random UUIDs.
+//
+// Generated IDs must be unique for the lifetime of the server. Only use
+// predictable IDs in tests: session IDs act as bearer credentials.
+func WithIDSource(ids IDSource) ServerOption {
+	return func(s *MCPServer) {
+		s.idSource = ids
+	}
+}
+
+// now returns the current time according to the configured clock.
+func (s *MCPServer) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// newID returns a new unique ID from the configured source.
+func (s *MCPServer) newID() string {
+	if s.idSource == nil {
+		return uuid.New().String()
+	}
+	return s.idSource.NewID()
+}
+
+// ManualClock is a Clock that only moves when told to. It is safe for
+// concurrent use.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a ManualClock set to start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to t.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// SequentialIDSource is an IDSource returning prefix-1, prefix-2, and so on.
+// It is safe for concurrent use.
+type SequentialIDSource struct {
+	prefix string
+	next   atomic.Uint64
+}
+
+// NewSequentialIDSource returns a SequentialIDSource whose IDs start with
+// prefix.
+func NewSequentialIDSource(prefix string) *SequentialIDSource {
+	return &SequentialIDSource{prefix: prefix}
+}
+
+// NewID returns the next ID in the sequence.
+func (s *SequentialIDSource) NewID() string {
+	return fmt.Sprintf("%s-%d", s.prefix, s.next.Add(1))
+}
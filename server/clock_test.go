@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithClockAndIDSource_Tasks(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := NewManualClock(start)
+	server := NewMCPServer("test", "1.0.0",
+		WithTaskCapabilities(true, true, true),
+		WithToolCapabilities(false),
+		WithClock(clock),
+		WithIDSource(NewSequentialIDSource("task")),
+	)
+	release := make(chan struct{})
+	server.AddTaskTool(
+		mcp.NewTool("build", mcp.WithTaskSupport(mcp.TaskSupportRequired)),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CreateTaskResult, error) {
+			<-release
+			return &mcp.CreateTaskResult{}, nil
+		},
+	)
+
+	var taskIDs []string
+	for range 2 {
+		resp, ok := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"build","task":{}}}`)).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		task := resp.Result.(*mcp.CreateTaskResult).Task
+		assert.Equal(t, "2025-01-02T03:04:05Z", task.CreatedAt)
+		assert.Equal(t, task.CreatedAt, task.LastUpdatedAt)
+		taskIDs = append(taskIDs, task.TaskId)
+	}
+	assert.Equal(t, []string{"task-1", "task-2"}, taskIDs)
+
+	clock.Advance(time.Minute)
+	close(release)
+	require.Eventually(t, func() bool {
+		task, _, _ := server.getTask(context.Background(), "task-1")
+		return task.Status == mcp.TaskStatusCompleted
+	}, time.Second, 10*time.Millisecond)
+	task, _, err := server.getTask(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-02T03:04:05Z", task.CreatedAt)
+	assert.Equal(t, "2025-01-02T03:05:05Z", task.LastUpdatedAt)
+}
+
+func TestWithIDSource_SessionIDs(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithIDSource(NewSequentialIDSource("id")))
+	assert.Equal(t, "inprocess-id-1", server.GenerateInProcessSessionID())
+
+	sse := NewSSEServer(server)
+	sessionID, err := sse.sessionIDGenFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "id-2", sessionID)
+
+	tests := []struct {
+		name string
+		opts []StreamableHTTPOption
+	}{
+		{name: "default manager"},
+		{name: "stateful manager", opts: []StreamableHTTPOption{WithStateful(true)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpServer := NewStreamableHTTPServer(server, tt.opts...)
+			sessionID := httpServer.sessionIdManager.Generate()
+			assert.Regexp(t, `^mcp-session-id-\d+$`, sessionID)
+			isTerminated, err := httpServer.sessionIdManager.Validate(sessionID)
+			require.NoError(t, err)
+			assert.False(t, isTerminated)
+			_, err = httpServer.sessionIdManager.Validate("mcp-session-")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDefaultClockAndIDSource(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0")
+	assert.WithinDuration(t, time.Now(), server.now(), time.Second)
+	assert.NotEqual(t, server.newID(), server.newID())
+
+	httpServer := NewStreamableHTTPServer(server, WithStateful(true))
+	sessionID := httpServer.sessionIdManager.Generate()
+	_, err := httpServer.sessionIdManager.Validate(sessionID)
+	require.NoError(t, err)
+	_, err = httpServer.sessionIdManager.Validate("mcp-session-not-a-uuid")
+	assert.Error(t, err)
+}
+
+func TestWithClock_Durations(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	server := NewMCPServer("test", "1.0.0", WithClock(clock), WithMetrics())
+	server.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		clock.Advance(3 * time.Second)
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`))
+
+	var metrics strings.Builder
+	require.NoError(t, server.Metrics().Write(&metrics))
+	assert.Contains(t, metrics.String(), `mcp_server_tool_call_duration_seconds_sum{tool="slow",status="ok"} 3`)
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionWithConnectionState is an extension of ClientSession that tracks
+// the health of the connection to the client. The server moves a session
+// to ready when it is initialized, to degraded when a notification cannot
+// be delivered and back to ready when one is, and to closed when the
+// session is unregistered. The built-in sessions implement it.
+type SessionWithConnectionState interface {
+	ClientSession
+	// ConnectionState returns the current state of the connection.
+	ConnectionState() mcp.ConnectionState
+	// OnConnectionStateChange registers fn to be called after each
+	// transition of the connection state.
+	OnConnectionStateChange(fn func(change mcp.ConnectionStateChange))
+	// SetConnectionState moves the connection to state for reason.
+	SetConnectionState(state mcp.ConnectionState, reason error)
+	// TransitionConnectionState moves the connection from one state to
+	// another for reason, atomically, and reports whether it did. It does
+	// nothing unless the connection is in from.
+	TransitionConnectionState(from, to mcp.ConnectionState, reason error) bool
+}
+
+// connectionStateStore implements the state methods of
+// SessionWithConnectionState for embedding in session types. The zero value
+// is ready for use.
+type connectionStateStore struct {
+	machine mcp.ConnectionStateMachine
+}
+
+func (s *connectionStateStore) ConnectionState() mcp.ConnectionState {
+	return s.machine.State()
+}
+
+func (s *connectionStateStore) OnConnectionStateChange(fn func(change mcp.ConnectionStateChange)) {
+	s.machine.OnChange(fn)
+}
+
+func (s *connectionStateStore) SetConnectionState(state mcp.ConnectionState, reason error) {
+	s.machine.Transition(state, reason)
+}
+
+func (s *connectionStateStore) TransitionConnectionState(from, to mcp.ConnectionState, reason error) bool {
+	return s.machine.TransitionFrom(from, to, reason)
+}
+
+// degradeConnection marks the session's connection degraded after a failed
+// delivery, if it was ready.
+func degradeConnection(session ClientSession, reason error) {
+	if s, ok := session.(SessionWithConnectionState); ok {
+		s.TransitionConnectionState(mcp.ConnectionReady, mcp.ConnectionDegraded, reason)
+	}
+}
+
+// recoverConnection marks the session's connection ready after a successful
+// delivery, if it was degraded.
+func recoverConnection(session ClientSession) {
+	if s, ok := session.(SessionWithConnectionState); ok {
+		s.TransitionConnectionState(mcp.ConnectionDegraded, mcp.ConnectionReady, nil)
+	}
+}
+
+// setConnectionState moves the session's connection to state, if the
+// session tracks it.
+func setConnectionState(session ClientSession, state mcp.ConnectionState, reason error) {
+	if s, ok := session.(SessionWithConnectionState); ok {
+		s.SetConnectionState(state, reason)
+	}
+}
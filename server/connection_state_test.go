@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionConnectionState(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	session := &sseSession{
+		sessionID:           "s1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 1),
+		done:                make(chan struct{}),
+	}
+	var changes []mcp.ConnectionStateChange
+	session.OnConnectionStateChange(func(change mcp.ConnectionStateChange) { changes = append(changes, change) })
+
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	assert.Equal(t, mcp.ConnectionConnecting, session.ConnectionState())
+
+	ctx := s.WithContext(context.Background(), session)
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"c","version":"1"}}}`))
+	assert.Equal(t, mcp.ConnectionReady, session.ConnectionState())
+
+	// The first notification fills the channel, the second is dropped.
+	require.NoError(t, s.SendNotificationToSpecificClient("s1", "test/one", nil))
+	assert.ErrorIs(t, s.SendNotificationToSpecificClient("s1", "test/two", nil), ErrNotificationChannelBlocked)
+	assert.Equal(t, mcp.ConnectionDegraded, session.ConnectionState())
+
+	<-session.notificationChannel
+	require.NoError(t, s.SendNotificationToSpecificClient("s1", "test/three", nil))
+	assert.Equal(t, mcp.ConnectionReady, session.ConnectionState())
+
+	s.UnregisterSession(context.Background(), "s1")
+	assert.Equal(t, mcp.ConnectionClosed, session.ConnectionState())
+	// Late deliveries do not reopen a closed connection.
+	degradeConnection(session, ErrNotificationChannelBlocked)
+	recoverConnection(session)
+	assert.Equal(t, mcp.ConnectionClosed, session.ConnectionState())
+
+	var states []mcp.ConnectionState
+	for _, change := range changes {
+		states = append(states, change.To)
+	}
+	assert.Equal(t, []mcp.ConnectionState{
+		mcp.ConnectionReady, mcp.ConnectionDegraded, mcp.ConnectionReady, mcp.ConnectionClosed,
+	}, states)
+	assert.ErrorIs(t, changes[1].Reason, ErrNotificationChannelBlocked)
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Directions of the messages reported on the debug endpoint.
+const (
+	DebugDirectionClientToServer = "client-to-server"
+	DebugDirectionServerToClient = "server-to-client"
+)
+
+// DebugMessage is one JSON-RPC message observed on a session, as streamed
+// by the debug endpoint. Each message is sent as an SSE "message" event
+// whose data is the JSON encoding of DebugMessage, the shape consumed by
+// MCP inspector tools that follow a live server.
+type DebugMessage struct {
+	SessionID string          `json:"sessionId"`
+	Direction string          `json:"direction"`
+	Timestamp time.Time       `json:"timestamp"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// DebugAuthorizer decides whether a request may watch the debug endpoint.
+type DebugAuthorizer func(r *http.Request) bool
+
+// debugSubscriberBuffer is the number of messages buffered per debug
+// subscriber. Messages are dropped for subscribers that fall further behind
+// so that watching a session never slows it down.
+const debugSubscriberBuffer = 256
+
+// WithDebugEndpoint exposes the live message stream of the server's
+// sessions, read-only, as an SSE stream at path. Every GET to path must be
+// accepted by authorize; a nil authorize rejects every request, so the
+// endpoint is never accidentally left open. The stream can be narrowed to a
+// single session with the sessionId query parameter or the Mcp-Session-Id
+// header.
+//
+// The endpoint is served by ServeHTTP (and Start); it is not available
+// through Handle.
+func WithDebugEndpoint(path string, authorize DebugAuthorizer) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.debugPath = path
+		s.debugAuthorize = authorize
+		s.debugTap = &debugTap{now: s.server.now, subscribers: make(map[*debugSubscriber]struct{})}
+	}
+}
+
+// debugTap fans session traffic out to debug endpoint subscribers.
+type debugTap struct {
+	now func() time.Time
+
+	mu          sync.RWMutex
+	subscribers map[*debugSubscriber]struct{}
+}
+
+type debugSubscriber struct {
+	sessionID string // empty for all sessions
+	messages  chan DebugMessage
+}
+
+func (t *debugTap) subscribe(sessionID string) *debugSubscriber {
+	sub := &debugSubscriber{sessionID: sessionID, messages: make(chan DebugMessage, debugSubscriberBuffer)}
+	t.mu.Lock()
+	t.subscribers[sub] = struct{}{}
+	t.mu.Unlock()
+	return sub
+}
+
+func (t *debugTap) unsubscribe(sub *debugSubscriber) {
+	t.mu.Lock()
+	delete(t.subscribers, sub)
+	t.mu.Unlock()
+}
+
+// publish reports a message to the subscribers watching sessionID. It is
+// a no-op on a nil tap or when nobody is watching.
+func (t *debugTap) publish(sessionID, direction string, message any) {
+	if t == nil {
+		return
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.subscribers) == 0 {
+		return
+	}
+
+	raw, ok := message.([]byte)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(message); err != nil {
+			return
+		}
+	}
+	msg := DebugMessage{
+		SessionID: sessionID,
+		Direction: direction,
+		Timestamp: t.now(),
+		Message:   json.RawMessage(raw),
+	}
+	for sub := range t.subscribers {
+		if sub.sessionID != "" && sub.sessionID != sessionID {
+			continue
+		}
+		select {
+		case sub.messages <- msg:
+		default:
+		}
+	}
+}
+
+// handleDebugStream serves the debug endpoint configured by
+// WithDebugEndpoint.
+func (s *StreamableHTTPServer) handleDebugStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.debugAuthorize == nil || !s.debugAuthorize(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sessionID := r.URL.Query().Get("sessionId")
+	if sessionID == "" {
+		sessionID = r.Header.Get(HeaderKeySessionID)
+	}
+	sub := s.debugTap.subscribe(sessionID)
+	defer s.debugTap.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case msg := <-sub.messages:
+			if err := writeSSEEvent(w, msg); err != nil {
+				s.logger.Error("Failed to write debug event", "err", err)
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// debugPublish is shorthand for publishing to the debug tap, if any.
+func (s *StreamableHTTPServer) debugPublish(sessionID, direction string, message any) {
+	s.debugTap.publish(sessionID, direction, message)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDebugEndpoint(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	httpServer := NewStreamableHTTPServer(mcpServer,
+		WithDebugEndpoint("/debug", func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer secret"
+		}),
+	)
+	server := httptest.NewServer(httpServer)
+	defer server.Close()
+
+	t.Run("rejects unauthenticated requests", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/debug")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("nil authorizer rejects everything", func(t *testing.T) {
+		closed := httptest.NewServer(NewStreamableHTTPServer(mcpServer, WithDebugEndpoint("/debug", nil)))
+		defer closed.Close()
+		req, _ := http.NewRequest(http.MethodGet, closed.URL+"/debug", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("streams session traffic", func(t *testing.T) {
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/debug", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		debugResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer debugResp.Body.Close()
+		require.Equal(t, http.StatusOK, debugResp.StatusCode)
+		assert.Equal(t, "text/event-stream", debugResp.Header.Get("Content-Type"))
+
+		// Wait for the subscription to register before generating traffic.
+		require.Eventually(t, func() bool {
+			httpServer.debugTap.mu.RLock()
+			defer httpServer.debugTap.mu.RUnlock()
+			return len(httpServer.debugTap.subscribers) == 1
+		}, time.Second, 5*time.Millisecond)
+
+		resp, err := postJSON(server.URL+"/mcp", initRequest)
+		require.NoError(t, err)
+		resp.Body.Close()
+		sessionID := resp.Header.Get(HeaderKeySessionID)
+
+		events := make(chan DebugMessage, 2)
+		go func() {
+			scanner := bufio.NewScanner(debugResp.Body)
+			for scanner.Scan() {
+				data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+				if !ok {
+					continue
+				}
+				var msg DebugMessage
+				if json.Unmarshal([]byte(data), &msg) == nil {
+					events <- msg
+				}
+			}
+		}()
+
+		var got []DebugMessage
+		for range 2 {
+			select {
+			case msg := <-events:
+				got = append(got, msg)
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for debug events")
+			}
+		}
+		assert.Equal(t, DebugDirectionClientToServer, got[0].Direction)
+		assert.Contains(t, string(got[0].Message), `"method":"initialize"`)
+		assert.Equal(t, DebugDirectionServerToClient, got[1].Direction)
+		assert.Contains(t, string(got[1].Message), `"protocolVersion":"`+mcp.LATEST_PROTOCOL_VERSION+`"`)
+		assert.Equal(t, sessionID, got[1].SessionID)
+	})
+}
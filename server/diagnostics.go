@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// DiagnosticsToolName is the name of the tool registered by WithDiagnostics.
+	DiagnosticsToolName = "server_diagnostics"
+	// DiagnosticsResourceURI is the URI of the resource registered by
+	// WithDiagnostics.
+	DiagnosticsResourceURI = "diagnostics://server/requirements"
+	// DefaultDiagnosticsTimeout bounds CheckRequirements when its context
+	// has no deadline.
+	DefaultDiagnosticsTimeout = 10 * time.Second
+)
+
+// Requirement is a runtime dependency of the server, such as a binary on
+// PATH, an environment variable or a reachable network service.
+type Requirement struct {
+	// Name identifies the requirement, e.g. "git".
+	Name string
+	// Description explains what the requirement is needed for.
+	Description string
+	// Hint tells the user how to fix a failed check.
+	Hint string
+	// Check returns nil if the requirement is met. It should return when
+	// ctx is done; a check still running then is reported as failed.
+	Check func(ctx context.Context) error
+}
+
+// RequireBinary declares that the named executable must be found on PATH.
+func RequireBinary(name, description string) Requirement {
+	return Requirement{
+		Name:        name,
+		Description: description,
+		Hint:        fmt.Sprintf("install %s and make sure it is on PATH", name),
+		Check: func(context.Context) error {
+			if _, err := exec.LookPath(name); err != nil {
+				return fmt.Errorf("binary %q not found on PATH", name)
+			}
+			return nil
+		},
+	}
+}
+
+// RequireEnv declares that the environment variable must be set and
+// non-empty.
+func RequireEnv(name, description string) Requirement {
+	return Requirement{
+		Name:        name,
+		Description: description,
+		Hint:        fmt.Sprintf("set the %s environment variable", name),
+		Check: func(context.Context) error {
+			if os.Getenv(name) == "" {
+				return fmt.Errorf("environment variable %s is not set", name)
+			}
+			return nil
+		},
+	}
+}
+
+// RequireReachable declares that a network address must accept connections,
+// e.g. RequireReachable("api", "tcp", "api.example.com:443", ...).
+func RequireReachable(name, network, address, description string, timeout time.Duration) Requirement {
+	return Requirement{
+		Name:        name,
+		Description: description,
+		Hint:        fmt.Sprintf("check network access to %s", address),
+		Check: func(ctx context.Context) error {
+			dialer := net.Dialer{Timeout: timeout}
+			conn, err := dialer.DialContext(ctx, network, address)
+			if err != nil {
+				return fmt.Errorf("%s is unreachable: %w", address, err)
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// RequirementStatus is the outcome of checking one Requirement.
+type RequirementStatus struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	OK          bool   `json:"ok"`
+	Error       string `json:"error,omitempty"`
+	Hint        string `json:"hint,omitempty"`
+}
+
+// DiagnosticsReport is the result of checking all declared requirements.
+type DiagnosticsReport struct {
+	Healthy      bool                `json:"healthy"`
+	CheckedAt    time.Time           `json:"checkedAt"`
+	Requirements []RequirementStatus `json:"requirements"`
+}
+
+// String renders the report as human readable lines, one per requirement.
+func (r DiagnosticsReport) String() string {
+	var b strings.Builder
+	if r.Healthy {
+		b.WriteString("server healthy")
+	} else {
+		b.WriteString("server unhealthy")
+	}
+	for _, status := range r.Requirements {
+		if status.OK {
+			fmt.Fprintf(&b, "\nok   %s", status.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "\nFAIL %s: %s", status.Name, status.Error)
+		if status.Hint != "" {
+			fmt.Fprintf(&b, " (%s)", status.Hint)
+		}
+	}
+	return b.String()
+}
+
+// WithDiagnostics declares the server's runtime requirements. They are
+// checked once when the server is created, and again whenever a client calls
+// the server_diagnostics tool or reads the diagnostics://server/requirements
+// resource, so hosts can show why a server is unhealthy.
+func WithDiagnostics(requirements ...Requirement) ServerOption {
+	return func(s *MCPServer) {
+		s.requirements = append(s.requirements, requirements...)
+
+		s.AddTool(
+			mcp.NewTool(DiagnosticsToolName,
+				mcp.WithDescription("Check the server's runtime requirements and report what is missing."),
+				mcp.WithReadOnlyHintAnnotation(true),
+			),
+			func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				report := s.CheckRequirements(ctx)
+				return mcp.NewToolResultStructured(report, report.String()), nil
+			},
+		)
+		s.AddResource(
+			mcp.NewResource(DiagnosticsResourceURI, "Server diagnostics",
+				mcp.WithResourceDescription("Status of the server's runtime requirements"),
+				mcp.WithMIMEType("application/json"),
+			),
+			func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				data, err := json.Marshal(s.CheckRequirements(ctx))
+				if err != nil {
+					return nil, err
+				}
+				return []mcp.ResourceContents{mcp.TextResourceContents{
+					URI:      DiagnosticsResourceURI,
+					MIMEType: "application/json",
+					Text:     string(data),
+				}}, nil
+			},
+		)
+	}
+}
+
+// CheckRequirements runs every requirement declared with WithDiagnostics
+// and records the report returned by LastDiagnostics. Without a deadline on
+// ctx, the checks are bounded by DefaultDiagnosticsTimeout.
+func (s *MCPServer) CheckRequirements(ctx context.Context) DiagnosticsReport {
+	s.diagnosticsMu.RLock()
+	requirements := s.requirements
+	s.diagnosticsMu.RUnlock()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultDiagnosticsTimeout)
+		defer cancel()
+	}
+
+	report := DiagnosticsReport{Healthy: true, CheckedAt: s.now()}
+	for _, req := range requirements {
+		status := RequirementStatus{Name: req.Name, Description: req.Description, OK: true}
+		if req.Check != nil {
+			if err := checkRequirement(ctx, req); err != nil {
+				status.OK = false
+				status.Error = err.Error()
+				status.Hint = req.Hint
+				report.Healthy = false
+			}
+		}
+		report.Requirements = append(report.Requirements, status)
+	}
+
+	s.diagnosticsMu.Lock()
+	s.lastDiagnostics = &report
+	s.diagnosticsMu.Unlock()
+	return report
+}
+
+// checkRequirement runs the check of req, giving up when ctx is done even if
+// the check ignores ctx.
+func checkRequirement(ctx context.Context, req Requirement) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- req.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check did not complete: %w", ctx.Err())
+	}
+}
+
+// LastDiagnostics returns the most recent requirements report, or nil if no
+// requirements were declared.
+func (s *MCPServer) LastDiagnostics() *DiagnosticsReport {
+	s.diagnosticsMu.RLock()
+	defer s.diagnosticsMu.RUnlock()
+	return s.lastDiagnostics
+}
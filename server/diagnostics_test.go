@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirements(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	t.Setenv("MCP_DIAG_SET", "1")
+
+	tests := []struct {
+		name    string
+		req     Requirement
+		wantErr bool
+	}{
+		{"binary present", RequireBinary("go", "build tool"), false},
+		{"binary missing", RequireBinary("definitely-not-a-real-binary", ""), true},
+		{"env set", RequireEnv("MCP_DIAG_SET", ""), false},
+		{"env missing", RequireEnv("MCP_DIAG_UNSET", ""), true},
+		{"unreachable", RequireReachable("db", "tcp", addr, "", 100*time.Millisecond), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Check(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithDiagnostics(t *testing.T) {
+	ok := Requirement{Name: "ok", Check: func(context.Context) error { return nil }}
+	git := Requirement{
+		Name: "git",
+		Hint: "install git",
+		Check: func(context.Context) error {
+			return errors.New("binary \"git\" not found on PATH")
+		},
+	}
+	s := NewMCPServer("test", "1.0.0", WithDiagnostics(ok, git))
+
+	last := s.LastDiagnostics()
+	require.NotNil(t, last, "requirements are checked at startup")
+	assert.False(t, last.Healthy)
+	require.Len(t, last.Requirements, 2)
+	assert.True(t, last.Requirements[0].OK)
+	assert.False(t, last.Requirements[1].OK)
+	assert.Equal(t, "install git", last.Requirements[1].Hint)
+
+	tool := s.GetTool(DiagnosticsToolName)
+	require.NotNil(t, tool)
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "server unhealthy")
+	assert.Contains(t, text, "FAIL git: binary \"git\" not found on PATH (install git)")
+
+	response := s.HandleMessage(context.Background(), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "resources/read",
+		"params": {"uri": "`+DiagnosticsResourceURI+`"}
+	}`))
+	resp, isResp := response.(mcp.JSONRPCResponse)
+	require.True(t, isResp, "unexpected response %#v", response)
+	read := resp.Result.(mcp.ReadResourceResult)
+	var report DiagnosticsReport
+	require.NoError(t, json.Unmarshal([]byte(read.Contents[0].(mcp.TextResourceContents).Text), &report))
+	assert.False(t, report.Healthy)
+	assert.Equal(t, "git", report.Requirements[1].Name)
+}
+
+func TestLastDiagnostics_NoRequirements(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	assert.Nil(t, s.LastDiagnostics())
+	assert.Nil(t, s.GetTool(DiagnosticsToolName))
+}
+
+func TestCheckRequirements_Deadline(t *testing.T) {
+	var hasDeadline bool
+	deadline := Requirement{Name: "deadline", Check: func(ctx context.Context) error {
+		_, hasDeadline = ctx.Deadline()
+		return nil
+	}}
+	release := make(chan struct{})
+	defer close(release)
+	stuck := Requirement{Name: "stuck", Check: func(context.Context) error {
+		<-release
+		return nil
+	}}
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewMCPServer("test", "1.0.0", WithClock(clock), WithDiagnostics(deadline))
+
+	assert.True(t, hasDeadline, "checks get a default deadline")
+	assert.Equal(t, clock.Now(), s.LastDiagnostics().CheckedAt)
+
+	s.requirements = append(s.requirements, stuck)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report := s.CheckRequirements(ctx)
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Requirements, 2)
+	assert.Contains(t, report.Requirements[1].Error, context.DeadlineExceeded.Error())
+}
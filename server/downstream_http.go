@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+// ErrCircuitOpen is returned by clients built with NewDownstreamHTTPClient
+// when the circuit breaker for the target backend is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// DownstreamIdentityFunc decorates an outgoing request with the identity of
+// the MCP caller found in ctx, for example by setting an Authorization or
+// X-On-Behalf-Of header.
+type DownstreamIdentityFunc func(ctx context.Context, req *http.Request)
+
+// DownstreamOption configures a client built by NewDownstreamHTTPClient.
+type DownstreamOption func(*downstreamTransport)
+
+// WithDownstreamTransport sets the underlying round tripper. The default is
+// http.DefaultTransport.
+func WithDownstreamTransport(rt http.RoundTripper) DownstreamOption {
+	return func(t *downstreamTransport) {
+		t.base = rt
+	}
+}
+
+// WithDownstreamPropagator injects the trace context of the tool call into
+// outgoing request headers. Use the same propagator passed to WithPropagator
+// so that downstream spans join the MCP request's trace.
+func WithDownstreamPropagator(p tracing.Propagator) DownstreamOption {
+	return func(t *downstreamTransport) {
+		if p == nil {
+			p = tracing.NoopPropagator()
+		}
+		t.propagator = p
+	}
+}
+
+// WithDownstreamIdentity sets the function that propagates the caller's
+// identity to the downstream request.
+func WithDownstreamIdentity(fn DownstreamIdentityFunc) DownstreamOption {
+	return func(t *downstreamTransport) {
+		t.identity = fn
+	}
+}
+
+// WithDownstreamTimeout bounds requests whose context carries no deadline.
+// Requests made with the tool handler's context inherit the MCP request's
+// cancellation and deadline instead.
+func WithDownstreamTimeout(timeout time.Duration) DownstreamOption {
+	return func(t *downstreamTransport) {
+		t.timeout = timeout
+	}
+}
+
+// WithDownstreamCircuitBreaker opens the circuit for a backend (host) after
+// threshold consecutive failures, failing fast with ErrCircuitOpen for
+// cooldown before letting a single trial request through. Transport errors
+// and 5xx responses count as failures, except those of requests whose
+// context was canceled or exceeded its deadline, such as calls abandoned by
+// the MCP client.
+func WithDownstreamCircuitBreaker(threshold int, cooldown time.Duration) DownstreamOption {
+	return func(t *downstreamTransport) {
+		t.breakerThreshold = threshold
+		t.breakerCooldown = cooldown
+	}
+}
+
+// NewDownstreamHTTPClient builds an *http.Client for tool handlers calling
+// external APIs. Build requests with the handler's context:
+//
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	resp, err := client.Do(req)
+//
+// so that the request is canceled with the MCP call, carries its trace
+// context and caller identity, and is subject to per-backend circuit breaking.
+func NewDownstreamHTTPClient(opts ...DownstreamOption) *http.Client {
+	t := &downstreamTransport{
+		base:       http.DefaultTransport,
+		propagator: tracing.NoopPropagator(),
+		breakers:   make(map[string]*circuitBreaker),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return &http.Client{Transport: t}
+}
+
+type downstreamTransport struct {
+	base             http.RoundTripper
+	propagator       tracing.Propagator
+	identity         DownstreamIdentityFunc
+	timeout          time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func (t *downstreamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var cancel context.CancelFunc
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	}
+
+	// RoundTrippers must not modify the caller's request.
+	out := req.Clone(ctx)
+	t.propagator.Inject(ctx, out.Header)
+	if t.identity != nil {
+		t.identity(ctx, out)
+	}
+
+	breaker := t.breaker(out.URL.Host)
+	if breaker != nil && !breaker.allow(time.Now()) {
+		if cancel != nil {
+			cancel()
+		}
+		return nil, fmt.Errorf("%w for %s", ErrCircuitOpen, out.URL.Host)
+	}
+
+	resp, err := t.base.RoundTrip(out)
+	if breaker != nil {
+		if req.Context().Err() != nil {
+			// The caller gave up; that says nothing about the backend.
+			breaker.abandon()
+		} else {
+			breaker.record(err == nil && resp.StatusCode < http.StatusInternalServerError, time.Now())
+		}
+	}
+	if cancel != nil {
+		if err != nil {
+			cancel()
+		} else {
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+		}
+	}
+	return resp, err
+}
+
+func (t *downstreamTransport) breaker(host string) *circuitBreaker {
+	if t.breakerThreshold <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.breakers[host]
+	if !ok {
+		b = &circuitBreaker{threshold: t.breakerThreshold, cooldown: t.breakerCooldown}
+		t.breakers[host] = b
+	}
+	return b
+}
+
+// circuitBreaker is a consecutive-failure circuit breaker for one backend.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool // a half-open trial request is in flight
+}
+
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *circuitBreaker) record(success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
+
+// abandon ends a request without recording its outcome, letting another
+// trial through if it was the half-open trial.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// cancelOnCloseBody releases the timeout context once the body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
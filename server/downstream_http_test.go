@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headerPropagator struct{}
+
+func (headerPropagator) Inject(ctx context.Context, header http.Header) {
+	if id, ok := ctx.Value(testTraceKey{}).(string); ok {
+		header.Set("Traceparent", id)
+	}
+}
+
+func (headerPropagator) Extract(ctx context.Context, _ http.Header) context.Context { return ctx }
+
+type testTraceKey struct{}
+
+func TestNewDownstreamHTTPClient_Propagation(t *testing.T) {
+	var gotTrace, gotCaller string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTrace = r.Header.Get("Traceparent")
+		gotCaller = r.Header.Get("X-On-Behalf-Of")
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer backend.Close()
+
+	client := NewDownstreamHTTPClient(
+		WithDownstreamPropagator(headerPropagator{}),
+		WithDownstreamIdentity(func(ctx context.Context, req *http.Request) {
+			req.Header.Set("X-On-Behalf-Of", "alice")
+		}),
+		WithDownstreamTimeout(time.Second),
+	)
+
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "trace-123")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backend.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, "trace-123", gotTrace)
+	assert.Equal(t, "alice", gotCaller)
+	assert.Empty(t, req.Header.Get("X-On-Behalf-Of"), "caller's request must not be modified")
+
+	// A canceled MCP request cancels the downstream call.
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err = http.NewRequestWithContext(canceled, http.MethodGet, backend.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewDownstreamHTTPClient_CircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer backend.Close()
+
+	client := NewDownstreamHTTPClient(WithDownstreamCircuitBreaker(2, 50*time.Millisecond))
+	get := func() error {
+		resp, err := client.Get(backend.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	require.NoError(t, get())
+	require.NoError(t, get())
+	// Two consecutive 5xx responses open the circuit.
+	assert.ErrorIs(t, get(), ErrCircuitOpen)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// After the cooldown a trial request is let through and closes the circuit.
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, get())
+	require.NoError(t, get())
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestNewDownstreamHTTPClient_CircuitBreakerIgnoresCanceledRequests(t *testing.T) {
+	var calls atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-r.Context().Done()
+	}))
+	defer backend.Close()
+
+	client := NewDownstreamHTTPClient(WithDownstreamCircuitBreaker(1, time.Minute))
+	for range 3 {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, backend.URL, nil)
+		require.NoError(t, err)
+		_, err = client.Do(req)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, int32(3), calls.Load())
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	// ErrRequestUndeliverable is returned when a server-initiated request
+	// (sampling, elicitation or roots) could not be delivered to the client.
+	// The error also matches the reason, such as ErrNoClientStream.
+	ErrRequestUndeliverable = errors.New("request could not be delivered to the client")
+	// ErrNoClientStream reports that no stream to the client picked up the
+	// request, for example because the client never opened its SSE stream.
+	ErrNoClientStream = errors.New("no open stream to the client")
+	// ErrRequestQueueFull reports that the session's outgoing request queue
+	// is full.
+	ErrRequestQueueFull = errors.New("request queue is full")
+	// ErrSessionClosed reports that the session can no longer send requests.
+	ErrSessionClosed = errors.New("session is closed")
+)
+
+// DroppedRequest describes a server-initiated request that could not be
+// delivered to the client.
+type DroppedRequest struct {
+	SessionID string
+	Method    mcp.MCPMethod
+	// Params is the payload of the request, such as mcp.CreateMessageParams.
+	Params any
+	// Err matches ErrRequestUndeliverable and the reason of the drop.
+	Err error
+}
+
+// OnRequestDroppedHookFunc is called for every server-initiated request that
+// could not be delivered to the client.
+type OnRequestDroppedHookFunc func(ctx context.Context, dropped DroppedRequest)
+
+// WithRequestDroppedHook registers a hook called whenever a sampling,
+// elicitation or roots request cannot be delivered: no stream to the client
+// is open, the session's request queue is full, or the session is closed.
+// Delivery problems are otherwise only visible to the handler that issued
+// the request. Drops are also counted in ServerStats.DroppedRequests when
+// WithStatsResource is enabled.
+func WithRequestDroppedHook(hook OnRequestDroppedHookFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.requestDroppedHooks = append(s.requestDroppedHooks, hook)
+	}
+}
+
+// undeliverable wraps the reason a request could not be delivered.
+func undeliverable(reason error) error {
+	return fmt.Errorf("%w: %w", ErrRequestUndeliverable, reason)
+}
+
+// reportDropped records err if it reports an undeliverable request.
+func (s *MCPServer) reportDropped(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any, err error) {
+	if !errors.Is(err, ErrRequestUndeliverable) {
+		return
+	}
+	if s.stats != nil {
+		s.stats.mu.Lock()
+		s.stats.dropped[string(method)]++
+		s.stats.mu.Unlock()
+	}
+
+	dropped := DroppedRequest{
+		Method: method,
+		Params: params,
+		Err:    err,
+	}
+	if session != nil {
+		dropped.SessionID = session.SessionID()
+	}
+	for _, hook := range s.requestDroppedHooks {
+		hook(ctx, dropped)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequestDroppedHook(t *testing.T) {
+	var mu sync.Mutex
+	var dropped []DroppedRequest
+	s := NewMCPServer("test", "1.0.0",
+		WithStatsResource(0),
+		WithRequestDroppedHook(func(_ context.Context, d DroppedRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped = append(dropped, d)
+		}),
+	)
+
+	t.Run("queue full", func(t *testing.T) {
+		session := newStreamableHttpSession("full", nil, nil, nil, nil)
+		for range cap(session.samplingRequestChan) {
+			session.samplingRequestChan <- samplingRequestItem{}
+		}
+		for range cap(session.rootsRequestChan) {
+			session.rootsRequestChan <- rootsRequestItem{}
+		}
+		ctx := s.WithContext(t.Context(), session)
+
+		request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{MaxTokens: 10}}
+		_, err := s.RequestSampling(ctx, request)
+		assert.ErrorIs(t, err, ErrRequestUndeliverable)
+		assert.ErrorIs(t, err, ErrRequestQueueFull)
+
+		_, err = s.RequestRoots(ctx, mcp.ListRootsRequest{})
+		assert.ErrorIs(t, err, ErrRequestQueueFull)
+	})
+
+	t.Run("no client stream", func(t *testing.T) {
+		session := newStreamableHttpSession("unlistened", nil, nil, nil, nil)
+		ctx, cancel := context.WithTimeout(s.WithContext(t.Context(), session), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := s.RequestElicitation(ctx, mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+			Message:         "Name?",
+			RequestedSchema: map[string]any{"type": "object"},
+		}})
+		assert.ErrorIs(t, err, ErrRequestUndeliverable)
+		assert.ErrorIs(t, err, ErrNoClientStream)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("other errors are not drops", func(t *testing.T) {
+		session := &mockSamplingSession{mockSession: mockSession{sessionID: "failing"}, err: errors.New("model unavailable")}
+		_, err := s.RequestSampling(s.WithContext(t.Context(), session), mcp.CreateMessageRequest{})
+		assert.EqualError(t, err, "model unavailable")
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, dropped, 3)
+	assert.Equal(t, "full", dropped[0].SessionID)
+	assert.Equal(t, mcp.MethodSamplingCreateMessage, dropped[0].Method)
+	assert.Equal(t, 10, dropped[0].Params.(mcp.CreateMessageParams).MaxTokens)
+	assert.ErrorIs(t, dropped[0].Err, ErrRequestQueueFull)
+	assert.Equal(t, mcp.MethodListRoots, dropped[1].Method)
+	assert.Equal(t, "unlistened", dropped[2].SessionID)
+	assert.Equal(t, mcp.MethodElicitationCreate, dropped[2].Method)
+	assert.Equal(t, "Name?", dropped[2].Params.(mcp.ElicitationParams).Message)
+
+	assert.Equal(t, map[string]int64{
+		string(mcp.MethodSamplingCreateMessage): 1,
+		string(mcp.MethodListRoots):             1,
+		string(mcp.MethodElicitationCreate):     1,
+	}, s.Stats().DroppedRequests)
+}
@@ -1,8 +1,15 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,8 +19,142 @@ var (
 	ErrNoActiveSession = errors.New("no active session")
 	// ErrElicitationNotSupported is returned when the session does not support elicitation
 	ErrElicitationNotSupported = errors.New("session does not support elicitation")
+	// ErrElicitationSchemaViolation is returned when the content of an
+	// accepted elicitation does not match the requested schema
+	ErrElicitationSchemaViolation = errors.New("elicitation response does not match requested schema")
+	// ErrElicitationTimeout is returned when the user does not answer an
+	// elicitation within the timeout set with WithElicitationTimeout
+	ErrElicitationTimeout = errors.New("elicitation timed out")
 )
 
+// ElicitationTimeoutOption configures how WithElicitationTimeout handles
+// pending elicitations.
+type ElicitationTimeoutOption func(*MCPServer)
+
+// WithElicitationReminder sends a notifications/elicitation/reminder to the
+// client every interval while an elicitation is pending, so hosts can nudge
+// a user who walked away before the request expires.
+func WithElicitationReminder(interval time.Duration) ElicitationTimeoutOption {
+	return func(s *MCPServer) {
+		s.elicitationReminder = interval
+	}
+}
+
+// WithElicitationCancelOnExpiry makes expired elicitations resolve to a
+// cancel result with reason mcp.ElicitationDeclineReasonTimeout instead of
+// failing with ErrElicitationTimeout, so tool handlers take their usual
+// cancel path.
+func WithElicitationCancelOnExpiry() ElicitationTimeoutOption {
+	return func(s *MCPServer) {
+		s.elicitationCancelOnExpiry = true
+	}
+}
+
+// WithElicitationTimeout bounds how long RequestElicitation and
+// RequestURLElicitation wait for the user to respond. Each elicitation gets
+// its own timer. When the timeout expires the request fails with an error
+// matching both ErrElicitationTimeout and context.DeadlineExceeded, unless
+// WithElicitationCancelOnExpiry is set, and the session drops its pending
+// state for the request, so a tool handler is not blocked until the session
+// dies. A deadline already set on the caller's context still applies; zero
+// disables the timeout.
+func WithElicitationTimeout(timeout time.Duration, opts ...ElicitationTimeoutOption) ServerOption {
+	return func(s *MCPServer) {
+		s.elicitationTimeout = timeout
+		for _, opt := range opts {
+			opt(s)
+		}
+	}
+}
+
+// requestElicitationFrom sends request through session, running the server
+// request hooks around it.
+func (s *MCPServer) requestElicitationFrom(
+	ctx context.Context,
+	session SessionWithElicitation,
+	request mcp.ElicitationRequest,
+) (*mcp.ElicitationResult, error) {
+	s.hooks.beforeServerRequest(ctx, session, mcp.MethodElicitationCreate, request.Params)
+	result, err := s.sendElicitation(ctx, session, request)
+	s.hooks.afterServerRequest(ctx, session, mcp.MethodElicitationCreate, request.Params, resultOrNil(result, err), err)
+	return result, err
+}
+
+// sendElicitation sends request through session, applying the elicitation
+// timeout and reminder policy.
+func (s *MCPServer) sendElicitation(
+	ctx context.Context,
+	session SessionWithElicitation,
+	request mcp.ElicitationRequest,
+) (*mcp.ElicitationResult, error) {
+	if s.elicitationTimeout <= 0 {
+		result, err := session.RequestElicitation(ctx, request)
+		if err != nil {
+			s.reportDropped(ctx, session, mcp.MethodElicitationCreate, request.Params, err)
+		}
+		return result, err
+	}
+	timeoutCtx, cancel := context.WithTimeoutCause(ctx, s.elicitationTimeout, ErrElicitationTimeout)
+	defer cancel()
+	if s.elicitationReminder > 0 {
+		go s.remindElicitation(timeoutCtx, session, request.Params)
+	}
+	result, err := session.RequestElicitation(timeoutCtx, request)
+	if err != nil {
+		s.reportDropped(ctx, session, mcp.MethodElicitationCreate, request.Params, err)
+	}
+	if err != nil && ctx.Err() == nil && errors.Is(context.Cause(timeoutCtx), ErrElicitationTimeout) {
+		if s.elicitationCancelOnExpiry {
+			return mcp.NewElicitationCancelResultWithReason(mcp.ElicitationDeclineReasonTimeout), nil
+		}
+		return nil, fmt.Errorf("%w after %s: %w", ErrElicitationTimeout, s.elicitationTimeout, context.DeadlineExceeded)
+	}
+	return result, err
+}
+
+// remindElicitation sends a reminder for params every reminder interval
+// until ctx, which carries the elicitation deadline, is done.
+func (s *MCPServer) remindElicitation(ctx context.Context, session ClientSession, params mcp.ElicitationParams) {
+	deadline, _ := ctx.Deadline()
+	ticker := time.NewTicker(s.elicitationReminder)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return
+			}
+			notification := mcp.NewElicitationReminderNotification(params.Message, params.ElicitationID, remaining)
+			_ = s.sendNotificationCore(ctx, session, notification)
+		}
+	}
+}
+
+// ElicitationSchemaError reports the content of an accepted elicitation that
+// does not satisfy the requested schema, with one entry per violated
+// constraint. It matches ErrElicitationSchemaViolation with errors.Is.
+type ElicitationSchemaError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Error renders every field error as `<field>: <message>`, joined with
+// semicolons.
+func (e *ElicitationSchemaError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return ErrElicitationSchemaViolation.Error() + ": " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns ErrElicitationSchemaViolation.
+func (e *ElicitationSchemaError) Unwrap() error {
+	return ErrElicitationSchemaViolation
+}
+
 // RequestElicitation sends an elicitation request to the client.
 // The client must have declared elicitation capability during initialization.
 // The session must implement SessionWithElicitation to support this operation.
@@ -28,14 +169,95 @@ func (s *MCPServer) RequestElicitation(ctx context.Context, request mcp.Elicitat
 		if err := request.Params.Validate(); err != nil {
 			return nil, err
 		}
-		return elicitationSession.RequestElicitation(ctx, request)
+		request.Params.Meta = s.injectMeta(ctx, request.Params.Meta)
+		var result *mcp.ElicitationResult
+		var err error
+		if s.elicitationDedup != nil && request.Params.Mode != mcp.ElicitationModeURL {
+			result, err = s.requestDeduplicatedElicitation(ctx, elicitationSession, request)
+		} else {
+			result, err = s.requestElicitationFrom(ctx, elicitationSession, request)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := validateElicitationContent(request.Params, result); err != nil {
+			return nil, err
+		}
+		return result, nil
 	}
 
 	return nil, ErrElicitationNotSupported
 }
 
+// validateElicitationContent checks the content of an accepted form mode
+// elicitation against the requested schema, so handlers can trust its
+// shape. Schemas that cannot be compiled are not enforced.
+func validateElicitationContent(params mcp.ElicitationParams, result *mcp.ElicitationResult) error {
+	if result == nil || result.Action != mcp.ElicitationResponseActionAccept {
+		return nil
+	}
+	if params.Mode != "" && params.Mode != mcp.ElicitationModeForm {
+		return nil
+	}
+	schemaJSON, err := json.Marshal(params.RequestedSchema)
+	if err != nil {
+		return nil
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil
+	}
+	const resourceURL = "mem:///mcp-go/elicitation/requested-schema.json"
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(resourceURL, doc); err != nil {
+		return nil
+	}
+	compiled, err := c.Compile(resourceURL)
+	if err != nil {
+		return nil
+	}
+
+	content, err := normalizeArgumentsForValidation(result.Content)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrElicitationSchemaViolation, err)
+	}
+	err = compiled.Validate(content)
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return fmt.Errorf("%w: %w", ErrElicitationSchemaViolation, err)
+	}
+	return &ElicitationSchemaError{Errors: collectFieldErrors(verr)}
+}
+
+// RequestTypedElicitation asks the client for a T, with a form generated from
+// the struct by mcp.NewElicitationRequest, and decodes the accepted content
+// into it. The result is returned as well so callers can tell a decline from
+// a cancel and read the reason the client gave; when the user did not
+// accept, the returned T is the zero value and the error is nil.
+func RequestTypedElicitation[T any](ctx context.Context, s *MCPServer, message string) (T, *mcp.ElicitationResult, error) {
+	var content T
+	result, err := s.RequestElicitation(ctx, mcp.NewElicitationRequest[T](message))
+	if err != nil {
+		return content, nil, err
+	}
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return content, result, nil
+	}
+	content, err = mcp.ParseElicitationContent[T](result)
+	if err != nil {
+		return content, result, err
+	}
+	return content, result, nil
+}
+
 // RequestURLElicitation sends a URL mode elicitation request to the client.
 // This is used when the server needs the user to perform an out-of-band interaction.
+// The elicitation is tracked by elicitationID, so the code handling the
+// interaction can call CompleteElicitation and the tool handler can wait
+// for it with AwaitElicitation.
 func (s *MCPServer) RequestURLElicitation(
 	ctx context.Context,
 	session ClientSession,
@@ -65,13 +287,27 @@ func (s *MCPServer) RequestURLElicitation(
 		Params: params,
 	}
 
-	if elicitationSession, ok := session.(SessionWithElicitation); ok {
-		return elicitationSession.RequestElicitation(ctx, request)
+	elicitationSession, ok := session.(SessionWithElicitation)
+	if !ok {
+		return nil, ErrElicitationNotSupported
 	}
-	return nil, ErrElicitationNotSupported
+
+	// Track the elicitation before sending it, so a callback completing it
+	// right away is not lost.
+	s.trackURLElicitation(elicitationID, session.SessionID())
+	result, err := s.requestElicitationFrom(ctx, elicitationSession, request)
+	switch {
+	case err != nil:
+		_, _ = s.finishURLElicitation(elicitationID, nil, err)
+	case result.Action != mcp.ElicitationResponseActionAccept:
+		_, _ = s.finishURLElicitation(elicitationID, nil, &ElicitationNotAcceptedError{
+			Action: result.Action,
+			Reason: result.Reason,
+		})
+	}
+	return result, err
 }
 
-// SendElicitationComplete sends a notification that a URL mode elicitation has completed
 // SendElicitationComplete sends a notification that a URL mode elicitation has completed
 func (s *MCPServer) SendElicitationComplete(
 	ctx context.Context,
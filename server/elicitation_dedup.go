@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// elicitationDeduper coalesces identical form mode elicitations pending in
+// the same session.
+type elicitationDeduper struct {
+	mu      sync.Mutex
+	pending map[string]*pendingElicitation
+}
+
+// pendingElicitation is an elicitation shared by every caller waiting for
+// the user's answer to it.
+type pendingElicitation struct {
+	done    chan struct{}
+	result  *mcp.ElicitationResult
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// WithElicitationDeduplication coalesces form mode elicitations that a
+// session requests while an identical one, with the same message and
+// requested schema, is still pending. Only one request reaches the client
+// and every caller receives the user's answer, which avoids prompting the
+// user repeatedly when an agent loop fires the same tool call in parallel.
+//
+// The shared request is cancelled once every caller waiting for it has
+// given up. URL mode elicitations, which carry their own IDs, are never
+// coalesced.
+func WithElicitationDeduplication() ServerOption {
+	return func(s *MCPServer) {
+		s.elicitationDedup = &elicitationDeduper{pending: make(map[string]*pendingElicitation)}
+	}
+}
+
+// elicitationKey identifies the elicitations of a session that are
+// semantically identical. It returns false if the schema cannot be hashed.
+func elicitationKey(sessionID string, params mcp.ElicitationParams) (string, bool) {
+	schema, err := json.Marshal(params.RequestedSchema)
+	if err != nil {
+		return "", false
+	}
+	h := sha256.New()
+	h.Write([]byte(params.Message))
+	h.Write([]byte{0})
+	h.Write(schema)
+	return sessionID + "/" + hex.EncodeToString(h.Sum(nil)), true
+}
+
+// requestDeduplicatedElicitation sends request through session, joining an
+// identical pending elicitation if there is one.
+func (s *MCPServer) requestDeduplicatedElicitation(
+	ctx context.Context,
+	session SessionWithElicitation,
+	request mcp.ElicitationRequest,
+) (*mcp.ElicitationResult, error) {
+	key, ok := elicitationKey(session.SessionID(), request.Params)
+	if !ok {
+		return s.requestElicitationFrom(ctx, session, request)
+	}
+
+	d := s.elicitationDedup
+	d.mu.Lock()
+	p, ok := d.pending[key]
+	if !ok {
+		// The shared request outlives the caller that started it, so it
+		// keeps the context values but not the cancellation.
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		p = &pendingElicitation{done: make(chan struct{}), cancel: cancel}
+		d.pending[key] = p
+		go func() {
+			result, err := s.requestElicitationFrom(runCtx, session, request)
+			d.mu.Lock()
+			if d.pending[key] == p {
+				delete(d.pending, key)
+			}
+			p.result, p.err = result, err
+			d.mu.Unlock()
+			close(p.done)
+			cancel()
+		}()
+	}
+	p.waiters++
+	d.mu.Unlock()
+
+	select {
+	case <-p.done:
+		if p.err != nil || p.result == nil {
+			return p.result, p.err
+		}
+		// Each caller gets its own copy, so handlers may modify it.
+		result := *p.result
+		return &result, nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		p.waiters--
+		if p.waiters == 0 {
+			if d.pending[key] == p {
+				delete(d.pending, key)
+			}
+			p.cancel()
+		}
+		d.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedElicitationSession answers every elicitation with result once
+// release is closed, counting the requests it receives.
+type gatedElicitationSession struct {
+	mockElicitationSession
+	release  chan struct{}
+	requests atomic.Int32
+}
+
+func (m *gatedElicitationSession) RequestElicitation(ctx context.Context, _ mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	m.requests.Add(1)
+	select {
+	case <-m.release:
+		return m.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestMCPServer_ElicitationDeduplication(t *testing.T) {
+	request := func(message string) mcp.ElicitationRequest {
+		return mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+			Message: message,
+			RequestedSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"name": map[string]any{"type": "string"}},
+			},
+		}}
+	}
+	newSession := func(id string) *gatedElicitationSession {
+		return &gatedElicitationSession{
+			mockElicitationSession: mockElicitationSession{
+				sessionID: id,
+				result:    mcp.NewElicitationAcceptResult(map[string]any{"name": "demo"}),
+			},
+			release: make(chan struct{}),
+		}
+	}
+
+	t.Run("identical requests share one prompt", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation(), WithElicitationDeduplication())
+		session := newSession("s1")
+		ctx := s.WithContext(t.Context(), session)
+
+		var wg sync.WaitGroup
+		results := make([]*mcp.ElicitationResult, 3)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := s.RequestElicitation(ctx, request("Name?"))
+				assert.NoError(t, err)
+				results[i] = result
+			}()
+		}
+		require.Eventually(t, func() bool {
+			s.elicitationDedup.mu.Lock()
+			defer s.elicitationDedup.mu.Unlock()
+			for _, p := range s.elicitationDedup.pending {
+				return p.waiters == len(results)
+			}
+			return false
+		}, time.Second, time.Millisecond)
+		close(session.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), session.requests.Load())
+		for _, result := range results {
+			require.NotNil(t, result)
+			assert.Equal(t, map[string]any{"name": "demo"}, result.Content)
+		}
+		assert.NotSame(t, results[0], results[1])
+	})
+
+	t.Run("different requests and sessions are not coalesced", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation(), WithElicitationDeduplication())
+		first, second := newSession("s1"), newSession("s2")
+		close(first.release)
+		close(second.release)
+
+		_, err := s.RequestElicitation(s.WithContext(t.Context(), first), request("Name?"))
+		require.NoError(t, err)
+		_, err = s.RequestElicitation(s.WithContext(t.Context(), first), request("Your name?"))
+		require.NoError(t, err)
+		_, err = s.RequestElicitation(s.WithContext(t.Context(), second), request("Name?"))
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), first.requests.Load())
+		assert.Equal(t, int32(1), second.requests.Load())
+	})
+
+	t.Run("a waiter giving up does not cancel the others", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation(), WithElicitationDeduplication())
+		session := newSession("s1")
+		ctx := s.WithContext(t.Context(), session)
+
+		impatient, cancel := context.WithCancel(ctx)
+		errs := make(chan error, 1)
+		go func() {
+			_, err := s.RequestElicitation(impatient, request("Name?"))
+			errs <- err
+		}()
+		require.Eventually(t, func() bool { return session.requests.Load() == 1 }, time.Second, time.Millisecond)
+
+		done := make(chan *mcp.ElicitationResult, 1)
+		go func() {
+			result, _ := s.RequestElicitation(ctx, request("Name?"))
+			done <- result
+		}()
+		require.Eventually(t, func() bool {
+			s.elicitationDedup.mu.Lock()
+			defer s.elicitationDedup.mu.Unlock()
+			for _, p := range s.elicitationDedup.pending {
+				return p.waiters == 2
+			}
+			return false
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		close(session.release)
+		result := <-done
+		require.NotNil(t, result)
+		assert.Equal(t, mcp.ElicitationResponseActionAccept, result.Action)
+		assert.Equal(t, int32(1), session.requests.Load())
+	})
+}
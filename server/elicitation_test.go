@@ -244,6 +244,141 @@ func TestRequestElicitation(t *testing.T) {
 	}
 }
 
+func TestMCPServer_RequestElicitation_ValidatesContent(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":      map[string]any{"type": "string", "minLength": 1},
+			"framework": map[string]any{"type": "string", "enum": []string{"react", "vue"}},
+			"tests":     map[string]any{"type": "boolean"},
+		},
+		"required": []string{"name"},
+	}
+
+	tests := []struct {
+		name       string
+		params     mcp.ElicitationParams
+		response   mcp.ElicitationResponse
+		wantFields []string
+	}{
+		{
+			name:     "valid content",
+			params:   mcp.ElicitationParams{Message: "details", RequestedSchema: schema},
+			response: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"name": "demo", "framework": "vue", "tests": true}},
+		},
+		{
+			name:       "missing required field",
+			params:     mcp.ElicitationParams{Message: "details", RequestedSchema: schema},
+			response:   mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"tests": true}},
+			wantFields: []string{"<root>"},
+		},
+		{
+			name:       "wrong type, enum and minLength",
+			params:     mcp.ElicitationParams{Message: "details", RequestedSchema: schema},
+			response:   mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"name": "", "framework": "svelte", "tests": "yes"}},
+			wantFields: []string{"/framework", "/name", "/tests"},
+		},
+		{
+			name:     "declined responses are not validated",
+			params:   mcp.ElicitationParams{Message: "details", RequestedSchema: schema},
+			response: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline},
+		},
+		{
+			name:     "uncompilable schemas are not enforced",
+			params:   mcp.ElicitationParams{Message: "details", RequestedSchema: map[string]any{"type": 42}},
+			response: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"anything": 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test", "1.0.0", WithElicitation())
+			session := &mockElicitationSession{
+				sessionID: "validate",
+				result:    &mcp.ElicitationResult{ElicitationResponse: tt.response},
+			}
+			ctx := server.WithContext(t.Context(), session)
+
+			result, err := server.RequestElicitation(ctx, mcp.ElicitationRequest{Params: tt.params})
+			if tt.wantFields == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.response.Action, result.Action)
+				return
+			}
+			require.ErrorIs(t, err, ErrElicitationSchemaViolation)
+			assert.Nil(t, result)
+			var schemaErr *ElicitationSchemaError
+			require.ErrorAs(t, err, &schemaErr)
+			var fields []string
+			for _, fe := range schemaErr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestRequestTypedElicitation(t *testing.T) {
+	type project struct {
+		Name  string `json:"name"`
+		Tests bool   `json:"tests,omitempty"`
+	}
+
+	tests := []struct {
+		name    string
+		result  *mcp.ElicitationResult
+		want    project
+		wantErr string
+	}{
+		{
+			name: "accepted",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]any{"name": "demo", "tests": true},
+			}},
+			want: project{Name: "demo", Tests: true},
+		},
+		{
+			name: "declined",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action: mcp.ElicitationResponseActionDecline,
+			}},
+		},
+		{
+			name: "mismatched content",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]any{"name": 42},
+			}},
+			wantErr: "does not match requested schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test", "1.0.0", WithElicitation())
+			session := &mockElicitationSession{sessionID: "typed", result: tt.result}
+			ctx := server.WithContext(t.Context(), session)
+
+			got, result, err := RequestTypedElicitation[project](ctx, server, "Describe the project")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.result.Action, result.Action)
+			assert.Equal(t, tt.want, got)
+
+			params := session.lastRequest.Params
+			assert.Equal(t, mcp.ElicitationModeForm, params.Mode)
+			assert.Equal(t, "Describe the project", params.Message)
+			schema, ok := params.RequestedSchema.(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, []any{"name"}, schema["required"])
+		})
+	}
+}
+
 func TestRequestURLElicitation(t *testing.T) {
 	s := NewMCPServer("test", "1.0", WithElicitation())
 
@@ -309,3 +444,113 @@ func TestSendElicitationComplete_NoPriorRequest(t *testing.T) {
 		t.Fatal("Expected notification was not received")
 	}
 }
+
+// blockingElicitationSession never answers, like a user who walked away.
+type blockingElicitationSession struct {
+	mockElicitationSession
+	released chan error
+}
+
+func (m *blockingElicitationSession) RequestElicitation(ctx context.Context, _ mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	<-ctx.Done()
+	m.released <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestMCPServer_WithElicitationTimeout(t *testing.T) {
+	request := mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+		Message:         "Still there?",
+		RequestedSchema: map[string]any{"type": "object"},
+	}}
+
+	t.Run("times out", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0", WithElicitation(), WithElicitationTimeout(20*time.Millisecond))
+		session := &blockingElicitationSession{mockElicitationSession: mockElicitationSession{sessionID: "away"}, released: make(chan error, 1)}
+		ctx := server.WithContext(t.Context(), session)
+
+		start := time.Now()
+		result, err := server.RequestElicitation(ctx, request)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrElicitationTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+		assert.ErrorIs(t, <-session.released, context.DeadlineExceeded, "session sees the cancellation")
+
+		_, err = server.RequestURLElicitation(ctx, session, "id-1", "https://example.com/auth", "Sign in")
+		assert.ErrorIs(t, err, ErrElicitationTimeout)
+	})
+
+	t.Run("cancel on expiry", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0", WithElicitation(),
+			WithElicitationTimeout(20*time.Millisecond, WithElicitationCancelOnExpiry()))
+		session := &blockingElicitationSession{mockElicitationSession: mockElicitationSession{sessionID: "away"}, released: make(chan error, 1)}
+		ctx := server.WithContext(t.Context(), session)
+
+		result, err := server.RequestElicitation(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, mcp.ElicitationResponseActionCancel, result.Action)
+		assert.Equal(t, mcp.ElicitationDeclineReasonTimeout, result.Reason)
+		assert.ErrorIs(t, <-session.released, context.DeadlineExceeded)
+
+		_, err = server.RequestURLElicitation(ctx, session, "id-1", "https://example.com/auth", "Sign in")
+		require.NoError(t, err)
+		<-session.released
+		_, err = server.AwaitElicitation(ctx, "id-1")
+		var notAccepted *ElicitationNotAcceptedError
+		require.ErrorAs(t, err, &notAccepted)
+		assert.Equal(t, mcp.ElicitationDeclineReasonTimeout, notAccepted.Reason)
+	})
+
+	t.Run("sends reminders", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0", WithElicitation(),
+			WithElicitationTimeout(time.Second, WithElicitationReminder(10*time.Millisecond)))
+		session := &blockingElicitationSession{
+			mockElicitationSession: mockElicitationSession{sessionID: "away", notifyChan: make(chan mcp.JSONRPCNotification, 100)},
+			released:               make(chan error, 1),
+		}
+		ctx, cancel := context.WithCancel(server.WithContext(t.Context(), session))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = server.RequestElicitation(ctx, request)
+		}()
+
+		select {
+		case notification := <-session.notifyChan:
+			assert.Equal(t, string(mcp.MethodNotificationElicitationReminder), notification.Method)
+			assert.Equal(t, "Still there?", notification.Params.AdditionalFields["message"])
+			assert.NotContains(t, notification.Params.AdditionalFields, "elicitationId")
+			remaining, ok := notification.Params.AdditionalFields["remainingSeconds"].(int)
+			require.True(t, ok)
+			assert.LessOrEqual(t, remaining, 1)
+		case <-time.After(time.Second):
+			t.Fatal("expected a reminder notification")
+		}
+		cancel()
+		<-done
+	})
+
+	t.Run("caller cancellation is not a timeout", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0", WithElicitation(), WithElicitationTimeout(time.Minute))
+		session := &blockingElicitationSession{mockElicitationSession: mockElicitationSession{sessionID: "away"}, released: make(chan error, 1)}
+		ctx, cancel := context.WithTimeout(server.WithContext(t.Context(), session), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := server.RequestElicitation(ctx, request)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrElicitationTimeout)
+	})
+
+	t.Run("answers within the timeout", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0", WithElicitation(), WithElicitationTimeout(time.Minute))
+		session := &mockElicitationSession{sessionID: "here", result: &mcp.ElicitationResult{
+			ElicitationResponse: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline},
+		}}
+		ctx := server.WithContext(t.Context(), session)
+
+		result, err := server.RequestElicitation(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, mcp.ElicitationResponseActionDecline, result.Action)
+	})
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithEncodingNegotiation enables the experimental encoding capability (see
+// mcp.ExperimentalEncodingKey). When a client offers compression schemes or
+// codecs at initialize time, the server picks the first one listed in
+// supported that the session's transport can also apply, and reports its
+// choice in the initialize result. Clients that make no offer, and sessions
+// whose transport cannot apply an encoding, keep mcp.DefaultEncoding.
+//
+// The streamable HTTP transport supports gzip compression and the JSON codec.
+func WithEncodingNegotiation(supported mcp.EncodingOffer) ServerOption {
+	return func(s *MCPServer) {
+		s.capabilities.encodings = &supported
+	}
+}
+
+// negotiateEncoding answers the client's encoding offer, if any, recording
+// the selection on the session and in the server capabilities.
+func (s *MCPServer) negotiateEncoding(session ClientSession, request mcp.InitializeRequest, capabilities *mcp.ServerCapabilities) {
+	if s.capabilities.encodings == nil {
+		return
+	}
+	encodingSession, ok := session.(SessionWithEncoding)
+	if !ok {
+		return
+	}
+	encodingSession.SetEncoding(mcp.DefaultEncoding)
+	offer, ok := mcp.EncodingOfferFromExperimental(request.Params.Capabilities.Experimental)
+	if !ok {
+		return
+	}
+
+	supported := s.capabilities.encodings.Intersect(encodingSession.SupportedEncodings())
+	selection := mcp.NegotiateEncoding(offer, supported)
+	encodingSession.SetEncoding(selection)
+
+	// Copy so the shared experimental map is not modified per session.
+	experimental := maps.Clone(capabilities.Experimental)
+	if experimental == nil {
+		experimental = make(map[string]any, 1)
+	}
+	experimental[mcp.ExperimentalEncodingKey] = selection
+	capabilities.Experimental = experimental
+}
+
+// writeEncodedJSON writes v as a 200 JSON response, gzip-compressed if the
+// session negotiated gzip and the request accepts it.
+func writeEncodedJSON(w HTTPResponseWriter, r *HTTPRequest, encoding mcp.EncodingSelection, v any) error {
+	if encoding.Compression != mcp.CompressionGzip || !acceptsGzip(r.header()) {
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	w.Header().Set("Content-Encoding", mcp.CompressionGzip)
+	w.Header().Add("Vary", "Accept-Encoding")
+	w.WriteHeader(http.StatusOK)
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(v); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip.
+func acceptsGzip(header http.Header) bool {
+	for _, value := range header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if strings.EqualFold(strings.TrimSpace(coding), mcp.CompressionGzip) &&
+				strings.ReplaceAll(params, " ", "") != "q=0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptsGzipBody reports whether a gzip body may be decompressed for r:
+// either WithGzipRequests is set, or the session of r negotiated gzip.
+func (s *StreamableHTTPServer) acceptsGzipBody(r *HTTPRequest) bool {
+	if s.gzipRequests {
+		return true
+	}
+	sessionValue, ok := s.server.sessions.Load(r.header().Get(HeaderKeySessionID))
+	if !ok {
+		return false
+	}
+	session, ok := sessionValue.(*streamableHttpSession)
+	return ok && session.Encoding().Compression == mcp.CompressionGzip
+}
+
+// errBodyTooLarge is returned by gunzip when the decompressed body exceeds
+// its limit.
+var errBodyTooLarge = errors.New("request body too large")
+
+// gunzip decompresses data, failing with errBodyTooLarge once more than
+// limit bytes come out.
+func gunzip(data []byte, limit int64) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(out)) > limit {
+		return nil, errBodyTooLarge
+	}
+	return out, nil
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamableHTTP_EncodingNegotiation(t *testing.T) {
+	initWithOffer := func(offer any) map[string]any {
+		return map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "initialize",
+			"params": map[string]any{
+				"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+				"clientInfo":      map[string]any{"name": "test-client", "version": "1.0.0"},
+				"capabilities": map[string]any{
+					"experimental": map[string]any{mcp.ExperimentalEncodingKey: offer},
+				},
+			},
+		}
+	}
+	initialize := func(t *testing.T, url string, request map[string]any) (string, mcp.InitializeResult) {
+		t.Helper()
+		resp, err := postJSON(url, request)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body struct {
+			Result mcp.InitializeResult `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.Header.Get(HeaderKeySessionID), body.Result
+	}
+
+	t.Run("negotiates gzip", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0", WithEncodingNegotiation(mcp.EncodingOffer{
+			Compression: []string{mcp.CompressionGzip},
+			Codecs:      []string{mcp.CodecJSON},
+		}))
+		server := httptest.NewServer(NewStreamableHTTPServer(mcpServer))
+		defer server.Close()
+
+		sessionID, result := initialize(t, server.URL, initWithOffer(mcp.EncodingOffer{
+			Compression: []string{"zstd", mcp.CompressionGzip},
+		}))
+		selection, ok := mcp.EncodingSelectionFromExperimental(result.Capabilities.Experimental)
+		require.True(t, ok)
+		assert.Equal(t, mcp.EncodingSelection{Compression: mcp.CompressionGzip, Codec: mcp.CodecJSON}, selection)
+
+		// A gzip request body is accepted and the response is compressed.
+		var body bytes.Buffer
+		gz := gzip.NewWriter(&body)
+		_, _ = gz.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+		require.NoError(t, gz.Close())
+		req, err := http.NewRequest(http.MethodPost, server.URL, &body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "gzip")
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set(HeaderKeySessionID, sessionID)
+		resp, err := (&http.Transport{DisableCompression: true}).RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		zr, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		data, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"result":{}}`, string(data))
+	})
+
+	t.Run("falls back without offer", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0", WithEncodingNegotiation(mcp.EncodingOffer{
+			Compression: []string{mcp.CompressionGzip},
+		}))
+		server := httptest.NewServer(NewStreamableHTTPServer(mcpServer))
+		defer server.Close()
+
+		_, result := initialize(t, server.URL, initRequest)
+		_, ok := mcp.EncodingSelectionFromExperimental(result.Capabilities.Experimental)
+		assert.False(t, ok)
+	})
+
+	t.Run("ignores offer when disabled", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0", WithExperimental(map[string]any{"feature": true}))
+		server := httptest.NewServer(NewStreamableHTTPServer(mcpServer))
+		defer server.Close()
+
+		_, result := initialize(t, server.URL, initWithOffer(mcp.EncodingOffer{Compression: []string{mcp.CompressionGzip}}))
+		_, ok := mcp.EncodingSelectionFromExperimental(result.Capabilities.Experimental)
+		assert.False(t, ok)
+		assert.Equal(t, map[string]any{"feature": true}, result.Capabilities.Experimental)
+	})
+}
+
+func TestStreamableHTTP_GzipRequestBodies(t *testing.T) {
+	gzipped := func(t *testing.T, data []byte) *bytes.Buffer {
+		t.Helper()
+		var body bytes.Buffer
+		gz := gzip.NewWriter(&body)
+		_, err := gz.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return &body
+	}
+	post := func(t *testing.T, url string, body io.Reader, gzipped bool) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if gzipped {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	initBody, err := json.Marshal(initRequest)
+	require.NoError(t, err)
+
+	t.Run("rejects gzip without negotiation", func(t *testing.T) {
+		server := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0.0")))
+		defer server.Close()
+
+		resp := post(t, server.URL, gzipped(t, initBody), true)
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("accepts gzip when enabled", func(t *testing.T) {
+		server := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0.0"), WithGzipRequests(true)))
+		defer server.Close()
+
+		resp := post(t, server.URL, gzipped(t, initBody), true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("limits decompressed size", func(t *testing.T) {
+		server := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0.0"),
+			WithGzipRequests(true), WithMaxRequestBodySize(1024)))
+		defer server.Close()
+
+		bomb := gzipped(t, bytes.Repeat([]byte(" "), 64<<10))
+		require.Less(t, bomb.Len(), 1024)
+		resp := post(t, server.URL, bomb, true)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("limits plain size", func(t *testing.T) {
+		server := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0.0"), WithMaxRequestBodySize(16)))
+		defer server.Close()
+
+		resp := post(t, server.URL, bytes.NewReader(initBody), false)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+}
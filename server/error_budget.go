@@ -0,0 +1,204 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// OutcomeClass classifies the outcome of a request by who is at fault.
+type OutcomeClass string
+
+const (
+	// OutcomeSuccess is a request that produced a result. Tool calls that
+	// return a result with IsError set are successes at the protocol level.
+	OutcomeSuccess OutcomeClass = "success"
+	// OutcomeClientError is a request rejected because of the client: it was
+	// malformed, named an unknown method, tool or resource, had invalid
+	// params, or was cancelled.
+	OutcomeClientError OutcomeClass = "client_error"
+	// OutcomeServerError is a request that failed because of the server,
+	// such as a handler error or an internal error.
+	OutcomeServerError OutcomeClass = "server_error"
+)
+
+// ClassifyCode returns the outcome class of a JSON-RPC error code. Codes
+// other than the well-known client error codes, including INTERNAL_ERROR and
+// implementation-defined codes, are server errors.
+func ClassifyCode(code int) OutcomeClass {
+	switch code {
+	case mcp.PARSE_ERROR,
+		mcp.INVALID_REQUEST,
+		mcp.METHOD_NOT_FOUND,
+		mcp.INVALID_PARAMS,
+		mcp.RESOURCE_NOT_FOUND,
+		mcp.REQUEST_INTERRUPTED,
+		mcp.URL_ELICITATION_REQUIRED,
+		mcp.RATE_LIMITED:
+		return OutcomeClientError
+	default:
+		return OutcomeServerError
+	}
+}
+
+// ErrorCode returns the JSON-RPC error code the server responds with for
+// err, as passed to OnError hooks.
+func ErrorCode(err error) (int, bool) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.code, true
+	}
+	return 0, false
+}
+
+// ClassifyError returns the outcome class of the error passed to an OnError
+// hook. A nil error is a success and an error without a JSON-RPC code is a
+// server error.
+//
+//	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+//	  if server.ClassifyError(err) == server.OutcomeServerError {
+//	    alert(method, err)
+//	  }
+//	})
+func ClassifyError(err error) OutcomeClass {
+	if err == nil {
+		return OutcomeSuccess
+	}
+	if code, ok := ErrorCode(err); ok {
+		return ClassifyCode(code)
+	}
+	return OutcomeServerError
+}
+
+// classifyResponse returns the outcome class of a response message.
+func classifyResponse(resp mcp.JSONRPCMessage) OutcomeClass {
+	if e, ok := resp.(mcp.JSONRPCError); ok {
+		return ClassifyCode(e.Error.Code)
+	}
+	return OutcomeSuccess
+}
+
+// ErrorBudget tracks request outcomes over a rolling window and measures
+// the server error rate against an availability objective. Client errors
+// are counted but do not consume the budget, so that misbehaving clients
+// sending invalid params do not page operators.
+//
+// An ErrorBudget is safe for concurrent use.
+type ErrorBudget struct {
+	objective float64
+	window    time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	buckets []outcomeBucket
+}
+
+// outcomeBucket counts the outcomes of one second of the window.
+type outcomeBucket struct {
+	second int64
+	counts [3]int
+}
+
+// ErrorBudgetStatus is a snapshot of an ErrorBudget.
+type ErrorBudgetStatus struct {
+	Window       time.Duration `json:"window"`
+	Objective    float64       `json:"objective"`
+	Total        int           `json:"total"`
+	Successes    int           `json:"successes"`
+	ClientErrors int           `json:"clientErrors"`
+	ServerErrors int           `json:"serverErrors"`
+	// ServerErrorRate is ServerErrors / Total, or 0 without requests.
+	ServerErrorRate float64 `json:"serverErrorRate"`
+	// Remaining is the fraction of the error budget left: 1 with no server
+	// errors, 0 when the server error rate equals 1 - Objective, and negative
+	// once the budget is overspent.
+	Remaining float64 `json:"remaining"`
+}
+
+// Exhausted reports whether the budget is spent.
+func (s ErrorBudgetStatus) Exhausted() bool {
+	return s.Remaining <= 0
+}
+
+// NewErrorBudget returns an ErrorBudget for an availability objective, the
+// fraction of requests expected not to fail because of the server (e.g.
+// 0.999), measured over a rolling window with one-second resolution.
+func NewErrorBudget(objective float64, window time.Duration) *ErrorBudget {
+	window = max(window, time.Second)
+	return &ErrorBudget{
+		objective: objective,
+		window:    window,
+		now:       time.Now,
+		buckets:   make([]outcomeBucket, int(window/time.Second)),
+	}
+}
+
+// Record counts one request outcome.
+func (b *ErrorBudget) Record(class OutcomeClass) {
+	idx := outcomeIndex(class)
+	second := b.now().Unix()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bucket := &b.buckets[int(second%int64(len(b.buckets)))]
+	if bucket.second != second {
+		*bucket = outcomeBucket{second: second}
+	}
+	bucket.counts[idx]++
+}
+
+// Status returns the outcomes counted in the current window.
+func (b *ErrorBudget) Status() ErrorBudgetStatus {
+	oldest := b.now().Unix() - int64(len(b.buckets)) + 1
+	status := ErrorBudgetStatus{Window: b.window, Objective: b.objective}
+	b.mu.Lock()
+	for _, bucket := range b.buckets {
+		if bucket.second < oldest {
+			continue
+		}
+		status.Successes += bucket.counts[0]
+		status.ClientErrors += bucket.counts[1]
+		status.ServerErrors += bucket.counts[2]
+	}
+	b.mu.Unlock()
+
+	status.Total = status.Successes + status.ClientErrors + status.ServerErrors
+	status.Remaining = 1
+	if status.Total > 0 {
+		status.ServerErrorRate = float64(status.ServerErrors) / float64(status.Total)
+		if allowed := 1 - b.objective; allowed > 0 {
+			status.Remaining = 1 - status.ServerErrorRate/allowed
+		} else if status.ServerErrors > 0 {
+			status.Remaining = 0
+		}
+	}
+	return status
+}
+
+func outcomeIndex(class OutcomeClass) int {
+	switch class {
+	case OutcomeSuccess:
+		return 0
+	case OutcomeClientError:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// WithErrorBudget records the outcome of every request the server answers
+// in budget. Notifications are not counted.
+func WithErrorBudget(budget *ErrorBudget) ServerOption {
+	return func(s *MCPServer) {
+		s.errorBudget = budget
+	}
+}
+
+// recordOutcome counts a response in the error budget, if any.
+func (s *MCPServer) recordOutcome(resp mcp.JSONRPCMessage) {
+	if s.errorBudget == nil || resp == nil {
+		return
+	}
+	s.errorBudget.Record(classifyResponse(resp))
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyCode(t *testing.T) {
+	tests := []struct {
+		code int
+		want OutcomeClass
+	}{
+		{mcp.PARSE_ERROR, OutcomeClientError},
+		{mcp.INVALID_REQUEST, OutcomeClientError},
+		{mcp.METHOD_NOT_FOUND, OutcomeClientError},
+		{mcp.INVALID_PARAMS, OutcomeClientError},
+		{mcp.RESOURCE_NOT_FOUND, OutcomeClientError},
+		{mcp.REQUEST_INTERRUPTED, OutcomeClientError},
+		{mcp.INTERNAL_ERROR, OutcomeServerError},
+		{-32050, OutcomeServerError},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ClassifyCode(tt.code), "code %d", tt.code)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	assert.Equal(t, OutcomeSuccess, ClassifyError(nil))
+	assert.Equal(t, OutcomeServerError, ClassifyError(errors.New("boom")))
+	assert.Equal(t, OutcomeClientError, ClassifyError(&requestError{code: mcp.INVALID_PARAMS, err: ErrToolNotFound}))
+
+	code, ok := ErrorCode(&requestError{code: mcp.INTERNAL_ERROR, err: errors.New("boom")})
+	assert.True(t, ok)
+	assert.Equal(t, mcp.INTERNAL_ERROR, code)
+}
+
+func TestErrorBudget_RollingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	budget := NewErrorBudget(0.9, 10*time.Second)
+	budget.now = func() time.Time { return now }
+
+	for range 8 {
+		budget.Record(OutcomeSuccess)
+	}
+	budget.Record(OutcomeClientError)
+	budget.Record(OutcomeServerError)
+
+	status := budget.Status()
+	assert.Equal(t, 10, status.Total)
+	assert.Equal(t, 1, status.ClientErrors)
+	assert.Equal(t, 1, status.ServerErrors)
+	assert.InDelta(t, 0.1, status.ServerErrorRate, 1e-9)
+	assert.InDelta(t, 0, status.Remaining, 1e-9)
+	assert.True(t, status.Exhausted())
+
+	// Client errors do not consume the budget.
+	now = now.Add(5 * time.Second)
+	for range 10 {
+		budget.Record(OutcomeClientError)
+	}
+	status = budget.Status()
+	assert.Equal(t, 20, status.Total)
+	assert.InDelta(t, 0.5, status.Remaining, 1e-9)
+
+	// Outcomes older than the window are forgotten.
+	now = now.Add(6 * time.Second)
+	status = budget.Status()
+	assert.Equal(t, 10, status.Total)
+	assert.Equal(t, 0, status.ServerErrors)
+	assert.InDelta(t, 1, status.Remaining, 1e-9)
+	assert.False(t, status.Exhausted())
+}
+
+func TestWithErrorBudget(t *testing.T) {
+	budget := NewErrorBudget(0.99, time.Minute)
+	var classes []OutcomeClass
+	hooks := &Hooks{}
+	hooks.AddOnError(func(_ context.Context, _ any, _ mcp.MCPMethod, _ any, err error) {
+		classes = append(classes, ClassifyError(err))
+	})
+	server := NewMCPServer("test", "1.0.0", WithToolCapabilities(false), WithHooks(hooks), WithErrorBudget(budget))
+	server.AddTool(mcp.NewTool("ok"), okHandler)
+	server.AddTool(mcp.NewTool("fail"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("database unavailable")
+	})
+
+	ctx := context.Background()
+	server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"ok"}}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"missing"}}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fail"}}`))
+	server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+
+	status := budget.Status()
+	require.Equal(t, 3, status.Total)
+	assert.Equal(t, 1, status.Successes)
+	assert.Equal(t, 1, status.ClientErrors)
+	assert.Equal(t, 1, status.ServerErrors)
+	assert.Equal(t, []OutcomeClass{OutcomeClientError, OutcomeServerError}, classes)
+}
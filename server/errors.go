@@ -11,6 +11,7 @@ var (
 	ErrResourceNotFound = errors.New("resource not found")
 	ErrPromptNotFound   = errors.New("prompt not found")
 	ErrToolNotFound     = errors.New("tool not found")
+	ErrToolDisabled     = errors.New("tool disabled")
 
 	// Session-related errors
 	ErrSessionNotFound                        = errors.New("session not found")
@@ -21,6 +22,11 @@ var (
 	ErrSessionDoesNotSupportResourceTemplates = errors.New("session does not support resource templates")
 	ErrSessionDoesNotSupportLogging           = errors.New("session does not support setting logging level")
 
+	// Task-related errors
+	ErrTaskNotFound = errors.New("task not found")
+	ErrTaskExpired  = errors.New("task has expired")
+	ErrNotInTask    = errors.New("not running as a task")
+
 	// Notification-related errors
 	ErrNotificationNotInitialized = errors.New("notification channel not initialized")
 	ErrNotificationChannelBlocked = errors.New("notification channel queue is full - client may not be processing notifications fast enough")
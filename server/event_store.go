@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HeaderKeyLastEventID is the header an SSE client sends when reconnecting to
+// resume a stream after the given event.
+const HeaderKeyLastEventID = "Last-Event-ID"
+
+// ErrUnknownEventID is returned by EventStore.ReplayEventsAfter when the event
+// ID is malformed, belongs to another stream or is no longer retained.
+var ErrUnknownEventID = errors.New("unknown event id")
+
+// EventStore persists messages written to StreamableHTTPServer SSE streams so
+// that a client reconnecting with a Last-Event-ID header can receive the
+// messages it missed. Implementations must be safe for concurrent use.
+type EventStore interface {
+	// StoreEvent records message as the next event of streamID and returns
+	// the event ID to send to the client.
+	StoreEvent(ctx context.Context, streamID string, message json.RawMessage) (eventID string, err error)
+	// ReplayEventsAfter calls send, in order, for each event of streamID
+	// stored after lastEventID. It returns ErrUnknownEventID if lastEventID
+	// does not belong to streamID or is no longer retained.
+	ReplayEventsAfter(ctx context.Context, streamID, lastEventID string, send func(eventID string, message json.RawMessage) error) error
+}
+
+// WithEventStore enables stream resumability on the listening (GET) stream.
+// Every message is stored and sent with an SSE event ID, and a client that
+// reconnects with a Last-Event-ID header is first sent the messages that
+// followed that event. Passing nil uses an InMemoryEventStore with the default
+// buffer size.
+func WithEventStore(store EventStore) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		if store == nil {
+			store = NewInMemoryEventStore(0)
+		}
+		s.eventStore = store
+	}
+}
+
+// defaultEventBufferSize is the number of events InMemoryEventStore keeps per
+// stream when no size is given.
+const defaultEventBufferSize = 100
+
+type storedEvent struct {
+	seq     uint64
+	message json.RawMessage
+}
+
+type eventStream struct {
+	nextSeq uint64
+	events  []storedEvent
+}
+
+// InMemoryEventStore is an EventStore keeping a bounded replay buffer per
+// stream in memory. Older events are discarded once the buffer is full.
+type InMemoryEventStore struct {
+	mu      sync.Mutex
+	size    int
+	streams map[string]*eventStream
+}
+
+// NewInMemoryEventStore creates an InMemoryEventStore retaining up to size
+// events per stream. A size of zero or less uses a default of 100.
+func NewInMemoryEventStore(size int) *InMemoryEventStore {
+	if size <= 0 {
+		size = defaultEventBufferSize
+	}
+	return &InMemoryEventStore{size: size, streams: make(map[string]*eventStream)}
+}
+
+// StoreEvent appends message to the stream's replay buffer.
+func (s *InMemoryEventStore) StoreEvent(_ context.Context, streamID string, message json.RawMessage) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.streams[streamID]
+	if !ok {
+		stream = &eventStream{}
+		s.streams[streamID] = stream
+	}
+	stream.nextSeq++
+	stream.events = append(stream.events, storedEvent{seq: stream.nextSeq, message: message})
+	if len(stream.events) > s.size {
+		stream.events = stream.events[len(stream.events)-s.size:]
+	}
+	return streamID + "_" + strconv.FormatUint(stream.nextSeq, 10), nil
+}
+
+// ReplayEventsAfter sends the buffered events following lastEventID.
+func (s *InMemoryEventStore) ReplayEventsAfter(_ context.Context, streamID, lastEventID string, send func(eventID string, message json.RawMessage) error) error {
+	seqText, ok := strings.CutPrefix(lastEventID, streamID+"_")
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownEventID, lastEventID)
+	}
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownEventID, lastEventID)
+	}
+
+	s.mu.Lock()
+	stream, ok := s.streams[streamID]
+	var pending []storedEvent
+	if ok {
+		for _, event := range stream.events {
+			if event.seq > seq {
+				pending = append(pending, event)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownEventID, lastEventID)
+	}
+
+	for _, event := range pending {
+		if err := send(streamID+"_"+strconv.FormatUint(event.seq, 10), event.message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteStream discards the replay buffer of streamID.
+func (s *InMemoryEventStore) DeleteStream(streamID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.streams, streamID)
+}
+
+// writeSSEEventWithID writes an SSE event carrying the given event ID.
+func writeSSEEventWithID(w io.Writer, eventID string, message json.RawMessage) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", eventID, message)
+	if err != nil {
+		return fmt.Errorf("failed to write SSE event: %w", err)
+	}
+	return nil
+}
+
+// writeStreamEvent writes data to the SSE stream, recording it in the event
+// store first when resumability is enabled.
+func (s *StreamableHTTPServer) writeStreamEvent(ctx context.Context, w io.Writer, streamID string, data any) error {
+	if s.eventStore == nil || streamID == "" {
+		return writeSSEEvent(w, data)
+	}
+	message, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+	eventID, err := s.eventStore.StoreEvent(ctx, streamID, message)
+	if err != nil {
+		s.logger.Error("Failed to store SSE event", "stream", streamID, "err", err)
+		return writeSSEEvent(w, data)
+	}
+	return writeSSEEventWithID(w, eventID, message)
+}
+
+// replayStreamEvents sends the events a reconnecting client missed.
+func (s *StreamableHTTPServer) replayStreamEvents(ctx context.Context, w HTTPResponseWriter, streamID, lastEventID string) {
+	err := s.eventStore.ReplayEventsAfter(ctx, streamID, lastEventID, func(eventID string, message json.RawMessage) error {
+		return writeSSEEventWithID(w, eventID, message)
+	})
+	if err != nil {
+		s.logger.Warn("Failed to replay SSE events", "stream", streamID, "lastEventId", lastEventID, "err", err)
+	}
+	w.Flush()
+}
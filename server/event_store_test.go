@@ -0,0 +1,124 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryEventStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryEventStore(2)
+
+	first, err := store.StoreEvent(ctx, "s1", json.RawMessage(`1`))
+	require.NoError(t, err)
+	_, err = store.StoreEvent(ctx, "s1", json.RawMessage(`2`))
+	require.NoError(t, err)
+	_, err = store.StoreEvent(ctx, "s1", json.RawMessage(`3`))
+	require.NoError(t, err)
+	other, err := store.StoreEvent(ctx, "s2", json.RawMessage(`x`))
+	require.NoError(t, err)
+
+	var replayed []string
+	err = store.ReplayEventsAfter(ctx, "s1", first, func(_ string, message json.RawMessage) error {
+		replayed = append(replayed, string(message))
+		return nil
+	})
+	require.NoError(t, err)
+	// The buffer holds two events, so event 1 has already been evicted but
+	// events 2 and 3 still follow it.
+	assert.Equal(t, []string{"2", "3"}, replayed)
+
+	tests := []struct {
+		name        string
+		streamID    string
+		lastEventID string
+	}{
+		{name: "malformed", streamID: "s1", lastEventID: "garbage"},
+		{name: "other stream", streamID: "s1", lastEventID: other},
+		{name: "unknown stream", streamID: "s3", lastEventID: "s3_1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.ReplayEventsAfter(ctx, tt.streamID, tt.lastEventID, func(string, json.RawMessage) error {
+				t.Error("no events must be replayed")
+				return nil
+			})
+			assert.ErrorIs(t, err, ErrUnknownEventID)
+		})
+	}
+}
+
+// readSSEEvents reads n events from an SSE body and returns their ids and data.
+func readSSEEvents(t *testing.T, scanner *bufio.Scanner, n int) (ids, data []string) {
+	t.Helper()
+	for len(data) < n && scanner.Scan() {
+		line := scanner.Text()
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+		if d, ok := strings.CutPrefix(line, "data: "); ok {
+			data = append(data, d)
+		}
+	}
+	require.Len(t, data, n)
+	return ids, data
+}
+
+func TestStreamableHTTP_EventStoreReplay(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	server := httptest.NewServer(NewStreamableHTTPServer(mcpServer, WithEventStore(nil)))
+	defer server.Close()
+
+	const sessionID = "mcp-session-11111111-1111-1111-1111-111111111111"
+	openStream := func(ctx context.Context, lastEventID string) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderKeySessionID, sessionID)
+		if lastEventID != "" {
+			req.Header.Set(HeaderKeyLastEventID, lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resp := openStream(ctx, "")
+	require.Eventually(t, func() bool {
+		_, ok := mcpServer.sessions.Load(sessionID)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, mcpServer.SendNotificationToSpecificClient(sessionID, "first", nil))
+	require.NoError(t, mcpServer.SendNotificationToSpecificClient(sessionID, "second", nil))
+
+	ids, data := readSSEEvents(t, bufio.NewScanner(resp.Body), 2)
+	require.Len(t, ids, 2)
+	assert.Contains(t, data[0], `"first"`)
+	cancel()
+	resp.Body.Close()
+
+	// Wait for the first stream to be torn down before reconnecting.
+	require.Eventually(t, func() bool {
+		_, ok := mcpServer.sessions.Load(sessionID)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	resp = openStream(ctx, ids[0])
+	defer resp.Body.Close()
+
+	replayedIDs, replayed := readSSEEvents(t, bufio.NewScanner(resp.Body), 1)
+	assert.Equal(t, ids[1:], replayedIDs)
+	assert.Contains(t, replayed[0], `"second"`)
+}
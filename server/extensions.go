@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"maps"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithExtension makes the server support the named extension: it is listed
+// in the Extensions of the server capabilities, and clients that list it
+// too receive block in the _meta of the initialize result (see
+// mcp.ExtensionsMetaKey). The client's block is then available from the
+// session; see SessionWithExtensions. Extension names should be
+// namespaced, such as "x-myco". A nil block sends none.
+func WithExtension(name string, block any) ServerOption {
+	return func(s *MCPServer) {
+		if s.extensions == nil {
+			s.extensions = make(map[string]any)
+		}
+		s.extensions[name] = block
+	}
+}
+
+// SessionWithExtensions is an extension of ClientSession that records the
+// extensions negotiated at initialize time. The built-in sessions implement
+// it.
+type SessionWithExtensions interface {
+	ClientSession
+	// Extension unmarshals the client's block of the named extension into
+	// v. It reports false if the extension was not negotiated.
+	Extension(name string, v any) (bool, error)
+	// Extensions returns the client's blocks of all negotiated extensions.
+	Extensions() mcp.Extensions
+	// SetExtensions replaces the negotiated extensions.
+	SetExtensions(extensions mcp.Extensions)
+}
+
+// extensionStore implements SessionWithExtensions for embedding in
+// session types. The zero value is ready for use.
+type extensionStore struct {
+	extensions atomic.Pointer[mcp.Extensions]
+}
+
+func (s *extensionStore) Extension(name string, v any) (bool, error) {
+	return s.Extensions().Decode(name, v)
+}
+
+func (s *extensionStore) Extensions() mcp.Extensions {
+	if extensions := s.extensions.Load(); extensions != nil {
+		return *extensions
+	}
+	return nil
+}
+
+func (s *extensionStore) SetExtensions(extensions mcp.Extensions) {
+	s.extensions.Store(&extensions)
+}
+
+// initializeMeta returns the _meta of a raw initialize request, which
+// mcp.InitializeParams does not decode.
+func initializeMeta(message json.RawMessage) *mcp.Meta {
+	var request struct {
+		Params struct {
+			Meta *mcp.Meta `json:"_meta"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(message, &request) != nil {
+		return nil
+	}
+	return request.Params.Meta
+}
+
+// negotiateExtensions advertises the server's extensions in result and,
+// for those the client lists too, answers with the server's blocks and
+// records the client's blocks on the session.
+func (s *MCPServer) negotiateExtensions(session ClientSession, request mcp.InitializeRequest, result *mcp.InitializeResult) {
+	if len(s.extensions) == 0 {
+		return
+	}
+	// Copy so that a map set by the caller is not modified.
+	advertised := maps.Clone(result.Capabilities.Extensions)
+	if advertised == nil {
+		advertised = make(map[string]any, len(s.extensions))
+	}
+	for name := range s.extensions {
+		advertised[name] = map[string]any{}
+	}
+	result.Capabilities.Extensions = advertised
+
+	blocks, err := mcp.ExtensionsFromMeta(request.Request.Params.Meta)
+	if err != nil {
+		s.logger().Warn("ignoring malformed initialize extensions", "error", err)
+		blocks = nil
+	}
+	agreed := make(mcp.Extensions)
+	answers := make(map[string]any)
+	for name := range request.Params.Capabilities.Extensions {
+		answer, ok := s.extensions[name]
+		if !ok {
+			continue
+		}
+		agreed[name] = blocks[name]
+		if answer != nil {
+			answers[name] = answer
+		}
+	}
+	if extSession, ok := session.(SessionWithExtensions); ok {
+		extSession.SetExtensions(agreed)
+	}
+	result.Meta = mcp.WithExtensionsMeta(result.Meta, answers)
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExtension_Negotiation(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0",
+		WithExtension("x-myco", map[string]any{"region": "eu"}),
+		WithExtension("x-unused", true),
+	)
+	session := NewInProcessSession("s1", nil)
+	ctx := s.WithContext(t.Context(), session)
+
+	resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{
+		"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},
+		"capabilities":{"extensions":{"x-myco":{},"x-other":{}}},
+		"_meta":{"`+mcp.ExtensionsMetaKey+`":{"x-myco":{"tier":"gold"},"x-other":{}}}
+	}}`))
+	require.IsType(t, mcp.JSONRPCResponse{}, resp)
+
+	data, err := json.Marshal(resp.(mcp.JSONRPCResponse).Result)
+	require.NoError(t, err)
+	var result mcp.InitializeResult
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, map[string]any{"x-myco": map[string]any{}, "x-unused": map[string]any{}}, result.Capabilities.Extensions)
+	answers, err := mcp.ExtensionsFromMeta(result.Meta)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.Extensions{"x-myco": json.RawMessage(`{"region":"eu"}`)}, answers,
+		"only extensions listed by the client are answered")
+
+	var block struct {
+		Tier string `json:"tier"`
+	}
+	ok, err := session.Extension("x-myco", &block)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "gold", block.Tier)
+
+	ok, err = session.Extension("x-other", &block)
+	require.NoError(t, err)
+	assert.False(t, ok, "extensions unknown to the server are not recorded")
+}
+
+func TestWithExtension_NoOffer(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithExtension("x-myco", true))
+	result, reqErr := s.handleInitialize(t.Context(), 1, mcp.InitializeRequest{})
+	require.Nil(t, reqErr)
+	assert.Nil(t, result.Meta)
+	assert.Contains(t, result.Capabilities.Extensions, "x-myco")
+}
+
+func TestSessionSnapshot_Extensions(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	session := NewInProcessSession("s1", nil)
+	session.SetExtensions(mcp.Extensions{"x-myco": json.RawMessage(`{"tier":"gold"}`)})
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+
+	data, err := s.ExportSession("s1")
+	require.NoError(t, err)
+	restored := NewInProcessSession("s2", nil)
+	require.NoError(t, s.ImportSession(restored, data, nil))
+	assert.Equal(t, session.Extensions(), restored.Extensions())
+}
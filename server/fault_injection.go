@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FaultRule describes the faults injected for one method. Rates are
+// probabilities from 0 to 1; a zero rule injects nothing.
+type FaultRule struct {
+	// Delay is added before the request is handled.
+	Delay time.Duration
+	// Jitter adds a uniformly distributed extra delay between 0 and Jitter.
+	Jitter time.Duration
+	// ErrorRate is the share of requests answered with a JSON-RPC error
+	// instead of being handled.
+	ErrorRate float64
+	// ErrorCode is the code of injected errors. The default is
+	// mcp.INTERNAL_ERROR.
+	ErrorCode int
+	// ErrorMessage is the message of injected errors. The default is
+	// "fault injected".
+	ErrorMessage string
+	// MalformedRate is the share of handled requests whose response is
+	// replaced by a malformed one: a result carrying the wrong ID and a
+	// non-object payload.
+	MalformedRate float64
+	// DropRate is the share of notifications with this method that are
+	// silently discarded instead of being sent to the client.
+	DropRate float64
+}
+
+// FaultConfig configures WithFaultInjection.
+type FaultConfig struct {
+	// Methods maps a request or notification method, such as "tools/call" or
+	// "notifications/progress", to the faults injected for it.
+	Methods map[string]FaultRule
+	// Default applies to methods missing from Methods.
+	Default FaultRule
+	// Rand is the source of randomness. Set it to a seeded generator for
+	// reproducible runs; the default is the global generator.
+	Rand *rand.Rand
+}
+
+// faultInjector applies a FaultConfig. rand.Rand is not safe for concurrent
+// use, so draws are serialized.
+type faultInjector struct {
+	cfg FaultConfig
+	mu  sync.Mutex
+}
+
+// WithFaultInjection injects latency, errors, malformed responses and
+// dropped notifications at configurable rates per method. It is meant for
+// testing how hosts cope with a misbehaving server and must not be enabled
+// in production.
+//
+// Request faults are applied by a request middleware, so they affect every
+// incoming request except notifications and responses from the client.
+// Dropped notifications apply to every notification the server sends,
+// including progress, logging and list-changed notifications; sending a
+// dropped notification reports success.
+func WithFaultInjection(cfg FaultConfig) ServerOption {
+	return func(s *MCPServer) {
+		f := &faultInjector{cfg: cfg}
+		s.faultInjector = f
+		s.requestMiddlewares = append(s.requestMiddlewares, f.middleware)
+	}
+}
+
+func (f *faultInjector) rule(method string) FaultRule {
+	if rule, ok := f.cfg.Methods[method]; ok {
+		return rule
+	}
+	return f.cfg.Default
+}
+
+// chance reports whether an event with the given probability happens.
+func (f *faultInjector) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	return f.float64() < rate
+}
+
+func (f *faultInjector) float64() float64 {
+	if f.cfg.Rand == nil {
+		return rand.Float64()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cfg.Rand.Float64()
+}
+
+func (f *faultInjector) delay(rule FaultRule) time.Duration {
+	d := rule.Delay
+	if rule.Jitter > 0 {
+		d += time.Duration(f.float64() * float64(rule.Jitter))
+	}
+	return d
+}
+
+// dropNotification reports whether a notification with the given method
+// should be discarded.
+func (f *faultInjector) dropNotification(method string) bool {
+	return f != nil && f.chance(f.rule(method).DropRate)
+}
+
+func (f *faultInjector) middleware(next RequestHandler) RequestHandler {
+	return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+		rule := f.rule(request.Method)
+		if d := f.delay(rule); d > 0 {
+			timer := time.NewTimer(d)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return mcp.NewJSONRPCError(request.ID, mcp.REQUEST_INTERRUPTED, ctx.Err().Error(), nil)
+			}
+		}
+		if f.chance(rule.ErrorRate) {
+			code := rule.ErrorCode
+			if code == 0 {
+				code = mcp.INTERNAL_ERROR
+			}
+			message := rule.ErrorMessage
+			if message == "" {
+				message = "fault injected"
+			}
+			return mcp.NewJSONRPCError(request.ID, code, message, nil)
+		}
+		response := next(ctx, request)
+		if f.chance(rule.MalformedRate) {
+			return mcp.JSONRPCResponse{
+				JSONRPC: mcp.JSONRPC_VERSION,
+				ID:      mcp.NewRequestId("fault-injected"),
+				Result:  "fault injected: malformed response",
+			}
+		}
+		return response
+	}
+}
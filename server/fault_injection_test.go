@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFaultInjection_Requests(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  FaultRule
+		check func(t *testing.T, resp mcp.JSONRPCMessage, elapsed time.Duration)
+	}{
+		{
+			name: "no faults",
+			check: func(t *testing.T, resp mcp.JSONRPCMessage, _ time.Duration) {
+				assert.IsType(t, mcp.JSONRPCResponse{}, resp)
+			},
+		},
+		{
+			name: "delay",
+			rule: FaultRule{Delay: 20 * time.Millisecond},
+			check: func(t *testing.T, resp mcp.JSONRPCMessage, elapsed time.Duration) {
+				assert.IsType(t, mcp.JSONRPCResponse{}, resp)
+				assert.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+			},
+		},
+		{
+			name: "error with default code",
+			rule: FaultRule{ErrorRate: 1},
+			check: func(t *testing.T, resp mcp.JSONRPCMessage, _ time.Duration) {
+				errResp, ok := resp.(mcp.JSONRPCError)
+				require.True(t, ok, "got %T", resp)
+				assert.Equal(t, mcp.INTERNAL_ERROR, errResp.Error.Code)
+				assert.Equal(t, "fault injected", errResp.Error.Message)
+			},
+		},
+		{
+			name: "error with custom code",
+			rule: FaultRule{ErrorRate: 1, ErrorCode: -32050, ErrorMessage: "overloaded"},
+			check: func(t *testing.T, resp mcp.JSONRPCMessage, _ time.Duration) {
+				errResp, ok := resp.(mcp.JSONRPCError)
+				require.True(t, ok, "got %T", resp)
+				assert.Equal(t, -32050, errResp.Error.Code)
+				assert.Equal(t, "overloaded", errResp.Error.Message)
+			},
+		},
+		{
+			name: "malformed response",
+			rule: FaultRule{MalformedRate: 1},
+			check: func(t *testing.T, resp mcp.JSONRPCMessage, _ time.Duration) {
+				result, ok := resp.(mcp.JSONRPCResponse)
+				require.True(t, ok, "got %T", resp)
+				assert.Equal(t, mcp.NewRequestId("fault-injected"), result.ID)
+				assert.IsType(t, "", result.Result)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMCPServer("test", "1.0.0", WithFaultInjection(FaultConfig{
+				Methods: map[string]FaultRule{string(mcp.MethodPing): tt.rule},
+			}))
+			start := time.Now()
+			resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			tt.check(t, resp, time.Since(start))
+		})
+	}
+}
+
+func TestWithFaultInjection_DefaultRuleAndRates(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithToolCapabilities(true), WithFaultInjection(FaultConfig{
+		Methods: map[string]FaultRule{string(mcp.MethodPing): {}},
+		Default: FaultRule{ErrorRate: 0.5},
+		Rand:    rand.New(rand.NewPCG(1, 2)),
+	}))
+
+	// Methods with an explicit rule are unaffected by the default.
+	for range 20 {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		assert.IsType(t, mcp.JSONRPCResponse{}, resp)
+	}
+
+	failures := 0
+	for range 200 {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		if errResp, ok := resp.(mcp.JSONRPCError); ok {
+			require.Equal(t, mcp.INTERNAL_ERROR, errResp.Error.Code)
+			failures++
+		}
+	}
+	assert.InDelta(t, 100, failures, 30)
+}
+
+func TestWithFaultInjection_CanceledDuringDelay(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithFaultInjection(FaultConfig{
+		Default: FaultRule{Delay: time.Hour},
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	errResp, ok := resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.REQUEST_INTERRUPTED, errResp.Error.Code)
+}
+
+func TestWithFaultInjection_DropNotifications(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithFaultInjection(FaultConfig{
+		Methods: map[string]FaultRule{"notifications/dropped": {DropRate: 1}},
+	}))
+	session := &fakeSession{
+		sessionID:           "s1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+
+	require.NoError(t, s.SendNotificationToSpecificClient("s1", "notifications/dropped", nil))
+	require.NoError(t, s.SendNotificationToSpecificClient("s1", "notifications/kept", nil))
+	s.SendNotificationToAllClients("notifications/dropped", nil)
+	ctx := s.WithContext(context.Background(), session)
+	require.NoError(t, s.SendNotificationToClient(ctx, "notifications/dropped", nil))
+
+	require.Len(t, session.notificationChannel, 1)
+	assert.Equal(t, "notifications/kept", (<-session.notificationChannel).Method)
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Default paths of the endpoints enabled by WithHealthEndpoints.
+const (
+	DefaultLivenessPath  = "/healthz"
+	DefaultReadinessPath = "/readyz"
+)
+
+// Values of HealthStatus.Status.
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthStatus is the JSON body of the health endpoints.
+type HealthStatus struct {
+	// Status is "ok", or "unavailable" when the readiness endpoint answers
+	// 503 Service Unavailable.
+	Status        string  `json:"status"`
+	UptimeSeconds float64 `json:"uptimeSeconds"`
+	// Sessions is the number of registered client sessions.
+	Sessions int `json:"sessions"`
+	// TaskBacklog is the number of tasks that have not finished, and Tasks
+	// counts all tasks held by the server by status.
+	TaskBacklog int                    `json:"taskBacklog"`
+	Tasks       map[mcp.TaskStatus]int `json:"tasks"`
+	// Errors explains why the server is not ready.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// HealthCheck reports whether a dependency of the server is ready to serve
+// traffic. It returns nil if it is.
+type HealthCheck func(ctx context.Context) error
+
+// HealthOption configures the endpoints enabled by WithHealthEndpoints.
+type HealthOption func(*healthEndpoints)
+
+// WithLivenessPath sets the path of the liveness endpoint. The default is
+// /healthz; an empty path disables the endpoint.
+func WithLivenessPath(path string) HealthOption {
+	return func(h *healthEndpoints) {
+		h.livenessPath = path
+	}
+}
+
+// WithReadinessPath sets the path of the readiness endpoint. The default is
+// /readyz; an empty path disables the endpoint.
+func WithReadinessPath(path string) HealthOption {
+	return func(h *healthEndpoints) {
+		h.readinessPath = path
+	}
+}
+
+// WithReadinessCheck adds a check the readiness endpoint runs on every
+// probe, such as pinging a database the server's tools depend on. A
+// failing check makes the server unready.
+func WithReadinessCheck(name string, check HealthCheck) HealthOption {
+	return func(h *healthEndpoints) {
+		h.checks = append(h.checks, namedHealthCheck{name: name, check: check})
+	}
+}
+
+// WithMaxTaskBacklog makes the server unready while more than n tasks are
+// unfinished, so load balancers stop routing new sessions to a pod that is
+// falling behind. Zero, the default, sets no limit.
+func WithMaxTaskBacklog(n int) HealthOption {
+	return func(h *healthEndpoints) {
+		h.maxTaskBacklog = n
+	}
+}
+
+// WithHealthEndpoints serves liveness and readiness endpoints for
+// Kubernetes probes and load balancers, by default at /healthz and /readyz.
+// Both answer GET and HEAD requests with a JSON HealthStatus reporting the
+// server's uptime, session count and task backlog.
+//
+// The liveness endpoint answers 200 OK as long as the server can handle
+// requests. The readiness endpoint answers 503 Service Unavailable once
+// Shutdown has been called, while the requirements declared with
+// WithDiagnostics are not met, while a check added with WithReadinessCheck
+// fails, or while the task backlog exceeds WithMaxTaskBacklog.
+//
+// The endpoints are served by ServeHTTP (and Start) ahead of
+// authentication, so probes need no credentials; they are not available
+// through Handle.
+func WithHealthEndpoints(opts ...HealthOption) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		h := &healthEndpoints{
+			livenessPath:  DefaultLivenessPath,
+			readinessPath: DefaultReadinessPath,
+			startedAt:     s.server.now(),
+		}
+		for _, opt := range opts {
+			opt(h)
+		}
+		s.health = h
+	}
+}
+
+// healthEndpoints is the configuration of WithHealthEndpoints.
+type healthEndpoints struct {
+	livenessPath   string
+	readinessPath  string
+	checks         []namedHealthCheck
+	maxTaskBacklog int
+	startedAt      time.Time
+}
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// serveHealth answers r if it targets a health endpoint and reports
+// whether it did.
+func (s *StreamableHTTPServer) serveHealth(w http.ResponseWriter, r *http.Request) bool {
+	h := s.health
+	if h == nil || r.URL.Path == "" {
+		return false
+	}
+	var ready bool
+	switch r.URL.Path {
+	case h.livenessPath:
+	case h.readinessPath:
+		ready = true
+	default:
+		return false
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return true
+	}
+
+	status := s.healthStatus()
+	if ready {
+		status.Errors = s.readinessErrors(r.Context(), status)
+	}
+	code := http.StatusOK
+	if len(status.Errors) > 0 {
+		status.Status = HealthStatusUnavailable
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if r.Method == http.MethodGet {
+		_ = json.NewEncoder(w).Encode(status)
+	}
+	return true
+}
+
+// healthStatus reports the server's uptime, sessions and tasks.
+func (s *StreamableHTTPServer) healthStatus() HealthStatus {
+	status := HealthStatus{
+		Status:        HealthStatusOK,
+		UptimeSeconds: s.server.now().Sub(s.health.startedAt).Seconds(),
+		Sessions:      s.server.sessionCount(),
+		Tasks:         s.server.taskCounts(),
+	}
+	for taskStatus, n := range status.Tasks {
+		if !taskStatus.IsTerminal() {
+			status.TaskBacklog += n
+		}
+	}
+	return status
+}
+
+// readinessErrors returns the reasons the server is not ready, if any.
+func (s *StreamableHTTPServer) readinessErrors(ctx context.Context, status HealthStatus) []string {
+	var errs []string
+	if s.shuttingDown.Load() {
+		errs = append(errs, "server is shutting down")
+	}
+	if report := s.server.LastDiagnostics(); report != nil && !report.Healthy {
+		for _, requirement := range report.Requirements {
+			if !requirement.OK {
+				errs = append(errs, "requirement "+requirement.Name+": "+requirement.Error)
+			}
+		}
+	}
+	if limit := s.health.maxTaskBacklog; limit > 0 && status.TaskBacklog > limit {
+		errs = append(errs, "task backlog exceeds limit")
+	}
+	for _, c := range s.health.checks {
+		if err := c.check(ctx); err != nil {
+			errs = append(errs, c.name+": "+err.Error())
+		}
+	}
+	return errs
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func getHealth(t *testing.T, url string) (int, HealthStatus) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var status HealthStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	return resp.StatusCode, status
+}
+
+func TestStreamableHTTP_HealthEndpoints(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true))
+	release := make(chan struct{})
+	mcpServer.AddTaskTool(
+		mcp.NewTool("build", mcp.WithTaskSupport(mcp.TaskSupportRequired)),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CreateTaskResult, error) {
+			<-release
+			return &mcp.CreateTaskResult{}, nil
+		},
+	)
+	var databaseDown atomic.Bool
+	httpServer := NewStreamableHTTPServer(mcpServer, WithHealthEndpoints(
+		WithReadinessCheck("database", func(ctx context.Context) error {
+			if databaseDown.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		}),
+		WithMaxTaskBacklog(1),
+	))
+	ts := httptest.NewServer(httpServer)
+	defer ts.Close()
+
+	body, err := json.Marshal(initRequest)
+	require.NoError(t, err)
+	resp, err := http.Post(ts.URL+"/mcp", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	code, status := getHealth(t, ts.URL+"/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusOK, status.Status)
+	assert.Equal(t, 1, status.Sessions)
+	assert.Positive(t, status.UptimeSeconds)
+
+	code, status = getHealth(t, ts.URL+"/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, status.Errors)
+
+	databaseDown.Store(true)
+	code, status = getHealth(t, ts.URL+"/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusUnavailable, status.Status)
+	assert.Equal(t, []string{"database: connection refused"}, status.Errors)
+	code, _ = getHealth(t, ts.URL+"/healthz")
+	assert.Equal(t, http.StatusOK, code, "liveness ignores readiness checks")
+	databaseDown.Store(false)
+
+	for range 2 {
+		mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"build","task":{}}}`))
+	}
+	code, status = getHealth(t, ts.URL+"/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, 2, status.TaskBacklog)
+	assert.Equal(t, 2, status.Tasks[mcp.TaskStatusWorking])
+	assert.Equal(t, []string{"task backlog exceeds limit"}, status.Errors)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		code, _ := getHealth(t, ts.URL+"/readyz")
+		return code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, httpServer.Shutdown(context.Background()))
+	code, status = getHealth(t, ts.URL+"/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, []string{"server is shutting down"}, status.Errors)
+	code, _ = getHealth(t, ts.URL+"/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestStreamableHTTP_HealthEndpoints_Paths(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0", WithDiagnostics(Requirement{
+		Name:  "git",
+		Check: func(context.Context) error { return errors.New("not found") },
+	}))
+	httpServer := NewStreamableHTTPServer(mcpServer, WithHealthEndpoints(
+		WithLivenessPath("/live"),
+		WithReadinessPath(""),
+	))
+
+	rr := httptest.NewRecorder()
+	httpServer.ServeHTTP(rr, httptest.NewRequest(http.MethodHead, "/live", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	httpServer.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/live", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	// Without a readiness endpoint, /readyz is handled as an MCP request.
+	rr = httptest.NewRecorder()
+	httpServer.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/readyz", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	httpServer = NewStreamableHTTPServer(mcpServer, WithHealthEndpoints())
+	rr = httptest.NewRecorder()
+	httpServer.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), "requirement git: not found")
+}
@@ -71,6 +71,26 @@ type OnErrorHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, mes
 // Should any errors arise during func execution, the service will promptly return the corresponding error message.
 type OnRequestInitializationFunc func(ctx context.Context, id any, message any) error
 
+// BeforeSendNotificationHookFunc is called before a notification is sent to
+// a session, for notifications sent to a single session and broadcasts
+// alike.
+type BeforeSendNotificationHookFunc func(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification)
+
+// AfterSendNotificationHookFunc is called after a notification was handed
+// to a session. err is non-nil if it could not be delivered, for example
+// ErrNotificationChannelBlocked.
+type AfterSendNotificationHookFunc func(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification, err error)
+
+// BeforeServerRequestHookFunc is called before the server sends a request
+// to the client: sampling, elicitation or roots. params is the payload of
+// the request, such as mcp.CreateMessageParams.
+type BeforeServerRequestHookFunc func(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any)
+
+// AfterServerRequestHookFunc is called once the client answered a request
+// sent by the server, or the request failed. result is the typed result,
+// such as *mcp.CreateMessageResult, and is nil when err is set.
+type AfterServerRequestHookFunc func(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any, result any, err error)
+
 type OnBeforeInitializeFunc func(ctx context.Context, id any, message *mcp.InitializeRequest)
 type OnAfterInitializeFunc func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult)
 
@@ -119,6 +139,12 @@ type OnAfterTaskResultFunc func(ctx context.Context, id any, message *mcp.TaskRe
 type OnBeforeCancelTaskFunc func(ctx context.Context, id any, message *mcp.CancelTaskRequest)
 type OnAfterCancelTaskFunc func(ctx context.Context, id any, message *mcp.CancelTaskRequest, result *mcp.CancelTaskResult)
 
+type OnBeforeSubscribeTasksFunc func(ctx context.Context, id any, message *mcp.SubscribeTasksRequest)
+type OnAfterSubscribeTasksFunc func(ctx context.Context, id any, message *mcp.SubscribeTasksRequest, result *mcp.EmptyResult)
+
+type OnBeforeClaimTaskFunc func(ctx context.Context, id any, message *mcp.ClaimTaskRequest)
+type OnAfterClaimTaskFunc func(ctx context.Context, id any, message *mcp.ClaimTaskRequest, result *mcp.ClaimTaskResult)
+
 type OnBeforeCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest)
 type OnAfterCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult)
 
@@ -163,6 +189,10 @@ type Hooks struct {
 	OnSuccess                     []OnSuccessHookFunc
 	OnError                       []OnErrorHookFunc
 	OnRequestInitialization       []OnRequestInitializationFunc
+	OnBeforeSendNotification      []BeforeSendNotificationHookFunc
+	OnAfterSendNotification       []AfterSendNotificationHookFunc
+	OnBeforeServerRequest         []BeforeServerRequestHookFunc
+	OnAfterServerRequest          []AfterServerRequestHookFunc
 	OnBeforeInitialize            []OnBeforeInitializeFunc
 	OnAfterInitialize             []OnAfterInitializeFunc
 	OnBeforePing                  []OnBeforePingFunc
@@ -195,6 +225,10 @@ type Hooks struct {
 	OnAfterTaskResult             []OnAfterTaskResultFunc
 	OnBeforeCancelTask            []OnBeforeCancelTaskFunc
 	OnAfterCancelTask             []OnAfterCancelTaskFunc
+	OnBeforeSubscribeTasks        []OnBeforeSubscribeTasksFunc
+	OnAfterSubscribeTasks         []OnAfterSubscribeTasksFunc
+	OnBeforeClaimTask             []OnBeforeClaimTaskFunc
+	OnAfterClaimTask              []OnAfterClaimTaskFunc
 	OnBeforeComplete              []OnBeforeCompleteFunc
 	OnAfterComplete               []OnAfterCompleteFunc
 }
@@ -339,6 +373,58 @@ func (c *Hooks) onRequestInitialization(ctx context.Context, id any, message any
 	}
 	return nil
 }
+
+func (c *Hooks) AddBeforeSendNotification(hook BeforeSendNotificationHookFunc) {
+	c.OnBeforeSendNotification = append(c.OnBeforeSendNotification, hook)
+}
+
+func (c *Hooks) AddAfterSendNotification(hook AfterSendNotificationHookFunc) {
+	c.OnAfterSendNotification = append(c.OnAfterSendNotification, hook)
+}
+
+func (c *Hooks) beforeSendNotification(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeSendNotification {
+		hook(ctx, session, notification)
+	}
+}
+
+func (c *Hooks) afterSendNotification(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification, err error) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterSendNotification {
+		hook(ctx, session, notification, err)
+	}
+}
+
+func (c *Hooks) AddBeforeServerRequest(hook BeforeServerRequestHookFunc) {
+	c.OnBeforeServerRequest = append(c.OnBeforeServerRequest, hook)
+}
+
+func (c *Hooks) AddAfterServerRequest(hook AfterServerRequestHookFunc) {
+	c.OnAfterServerRequest = append(c.OnAfterServerRequest, hook)
+}
+
+func (c *Hooks) beforeServerRequest(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeServerRequest {
+		hook(ctx, session, method, params)
+	}
+}
+
+func (c *Hooks) afterServerRequest(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any, result any, err error) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterServerRequest {
+		hook(ctx, session, method, params, result, err)
+	}
+}
 func (c *Hooks) AddBeforeInitialize(hook OnBeforeInitializeFunc) {
 	c.OnBeforeInitialize = append(c.OnBeforeInitialize, hook)
 }
@@ -771,6 +857,60 @@ func (c *Hooks) afterCancelTask(ctx context.Context, id any, message *mcp.Cancel
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeSubscribeTasks(hook OnBeforeSubscribeTasksFunc) {
+	c.OnBeforeSubscribeTasks = append(c.OnBeforeSubscribeTasks, hook)
+}
+
+func (c *Hooks) AddAfterSubscribeTasks(hook OnAfterSubscribeTasksFunc) {
+	c.OnAfterSubscribeTasks = append(c.OnAfterSubscribeTasks, hook)
+}
+
+func (c *Hooks) beforeSubscribeTasks(ctx context.Context, id any, message *mcp.SubscribeTasksRequest) {
+	c.beforeAny(ctx, id, mcp.MethodTasksSubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeSubscribeTasks {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterSubscribeTasks(ctx context.Context, id any, message *mcp.SubscribeTasksRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodTasksSubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterSubscribeTasks {
+		hook(ctx, id, message, result)
+	}
+}
+func (c *Hooks) AddBeforeClaimTask(hook OnBeforeClaimTaskFunc) {
+	c.OnBeforeClaimTask = append(c.OnBeforeClaimTask, hook)
+}
+
+func (c *Hooks) AddAfterClaimTask(hook OnAfterClaimTaskFunc) {
+	c.OnAfterClaimTask = append(c.OnAfterClaimTask, hook)
+}
+
+func (c *Hooks) beforeClaimTask(ctx context.Context, id any, message *mcp.ClaimTaskRequest) {
+	c.beforeAny(ctx, id, mcp.MethodTasksClaim, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeClaimTask {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterClaimTask(ctx context.Context, id any, message *mcp.ClaimTaskRequest, result *mcp.ClaimTaskResult) {
+	c.onSuccess(ctx, id, mcp.MethodTasksClaim, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterClaimTask {
+		hook(ctx, id, message, result)
+	}
+}
 func (c *Hooks) AddBeforeComplete(hook OnBeforeCompleteFunc) {
 	c.OnBeforeComplete = append(c.OnBeforeComplete, hook)
 }
@@ -13,7 +13,9 @@ import (
 //
 // CORS handling is opt-in: when no origins are configured, the transports do
 // not emit any Access-Control-* response headers and preflight (OPTIONS)
-// requests are passed through to the underlying handlers unchanged.
+// requests are passed through to the underlying handlers unchanged. The
+// exception is StrictOrigin, which without allowed origins rejects every
+// request that carries an Origin header.
 type CORSConfig struct {
 	// AllowedOrigins is the list of origins permitted to access the server.
 	// The literal value "*" allows any origin, but is incompatible with
@@ -43,6 +45,14 @@ type CORSConfig struct {
 	// MaxAge is the number of seconds browsers may cache the preflight
 	// response. Zero (or negative) omits the header.
 	MaxAge int
+
+	// StrictOrigin, when true, rejects requests whose Origin header is not
+	// in AllowedOrigins with 403 Forbidden instead of merely omitting the
+	// Access-Control-* headers. Browsers then cannot even send requests
+	// from other origins, which also defeats DNS rebinding attacks on
+	// servers reachable under another name. Requests without an Origin
+	// header, such as those of non-browser clients, are not affected.
+	StrictOrigin bool
 }
 
 // CORSOption configures a CORSConfig. It is consumed by the per-transport
@@ -100,10 +110,19 @@ func WithCORSMaxAge(seconds int) CORSOption {
 	}
 }
 
+// WithCORSStrictOrigin rejects requests from origins that are not allowed
+// with 403 Forbidden. See CORSConfig.StrictOrigin.
+func WithCORSStrictOrigin() CORSOption {
+	return func(c *CORSConfig) {
+		c.StrictOrigin = true
+	}
+}
+
 // enabled reports whether CORS handling should be performed for this
-// configuration. A nil receiver or empty AllowedOrigins disables CORS.
+// configuration. A nil receiver or empty AllowedOrigins disables CORS,
+// unless StrictOrigin is set so that it denies every origin.
 func (c *CORSConfig) enabled() bool {
-	return c != nil && len(c.AllowedOrigins) > 0
+	return c != nil && (len(c.AllowedOrigins) > 0 || c.StrictOrigin)
 }
 
 // resolveOrigin returns the value to set on Access-Control-Allow-Origin for
@@ -178,7 +197,7 @@ func (c *CORSConfig) handlePreflight(w http.ResponseWriter, r *http.Request) boo
 
 	headers := c.AllowedHeaders
 	if len(headers) == 0 {
-		headers = []string{"Content-Type", HeaderKeySessionID, "Last-Event-ID", "Authorization"}
+		headers = []string{"Content-Type", HeaderKeySessionID, HeaderKeyProtocolVersion, "Last-Event-ID", "Authorization"}
 	}
 	h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
 
@@ -190,6 +209,28 @@ func (c *CORSConfig) handlePreflight(w http.ResponseWriter, r *http.Request) boo
 	return true
 }
 
+// handle applies the configuration to r. It returns true when it has
+// written the complete response, for a preflight request or a request from
+// an origin rejected by StrictOrigin, and the caller must skip its normal
+// request handling. It is a no-op when CORS is not enabled.
+func (c *CORSConfig) handle(w http.ResponseWriter, r *http.Request) bool {
+	if !c.enabled() {
+		return false
+	}
+	if c.StrictOrigin {
+		if origin := r.Header.Get("Origin"); origin != "" && c.resolveOrigin(origin) == "" {
+			w.Header().Add("Vary", "Origin")
+			http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+			return true
+		}
+	}
+	if c.handlePreflight(w, r) {
+		return true
+	}
+	c.applySimple(w, r)
+	return false
+}
+
 // applySimple writes the headers for a non-preflight (simple) cross-origin
 // response. It is a no-op when CORS is not enabled.
 func (c *CORSConfig) applySimple(w http.ResponseWriter, r *http.Request) {
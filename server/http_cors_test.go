@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -171,6 +172,80 @@ func TestStreamableHTTP_CORS_PreflightDisallowedOrigin(t *testing.T) {
 	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
 }
 
+func TestStreamableHTTP_WithCORS(t *testing.T) {
+	t.Parallel()
+
+	mcp := NewMCPServer("test", "1.0.0")
+	srv := NewStreamableHTTPServer(mcp,
+		WithCORS([]string{"https://app.example.com"}, []string{"X-Tenant"}, 10*time.Minute),
+	)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	do := func(t *testing.T, method, origin string) *http.Response {
+		t.Helper()
+		body := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		req, err := http.NewRequestWithContext(t.Context(), method, ts.URL, body)
+		require.NoError(t, err)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		} else {
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json, text/event-stream")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	resp := do(t, http.MethodOptions, "https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+	allowed := resp.Header.Get("Access-Control-Allow-Headers")
+	for _, header := range []string{"Content-Type", "Mcp-Session-Id", "Mcp-Protocol-Version", "X-Tenant"} {
+		assert.Contains(t, allowed, header)
+	}
+	assert.Equal(t, "Mcp-Session-Id", resp.Header.Get("Access-Control-Expose-Headers"))
+
+	assert.Equal(t, http.StatusForbidden, do(t, http.MethodOptions, "https://attacker.com").StatusCode)
+	assert.Equal(t, http.StatusForbidden, do(t, http.MethodPost, "https://attacker.com").StatusCode,
+		"requests from other origins are rejected, not just left without CORS headers")
+	assert.NotEqual(t, http.StatusForbidden, do(t, http.MethodPost, "").StatusCode, "non-browser clients send no Origin")
+	assert.NotEqual(t, http.StatusForbidden, do(t, http.MethodPost, "https://app.example.com").StatusCode)
+}
+
+func TestStreamableHTTP_WithCORS_NoOrigins(t *testing.T) {
+	t.Parallel()
+
+	srv := NewStreamableHTTPServer(NewMCPServer("test", "1.0.0"), WithCORS(nil, nil, 0))
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	for origin, forbidden := range map[string]bool{
+		"https://app.example.com": true,
+		"":                        false,
+	} {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, ts.URL,
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, forbidden, resp.StatusCode == http.StatusForbidden, "origin %q", origin)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	}
+}
+
 func TestStreamableHTTP_CORS_SimpleRequest(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"strings"
 )
 
@@ -32,25 +33,108 @@ func isLoopbackHost(addr string) bool {
 	return ip.IsLoopback()
 }
 
-// rejectDNSRebinding applies DNS rebinding protection to r. When the
-// connection's local address (http.LocalAddrContextKey) is a loopback
-// address but the request's Host header is not a loopback value, it writes a
-// 403 Forbidden response and returns true. Otherwise it returns false and
-// writes nothing.
+// originGuard validates the Host and Origin headers of incoming requests,
+// as the streamable HTTP security guidance requires, to protect servers
+// against DNS rebinding and cross-site requests from browsers.
+type originGuard struct {
+	// allowedHosts, when set, lists the only Host header values accepted
+	// on any connection. See WithAllowedHosts.
+	allowedHosts []string
+	// allowedOrigins, when set, lists the only Origin header values
+	// accepted on any connection. See WithAllowedOrigins.
+	allowedOrigins []string
+	// disableLocalhostProtection turns off the checks applied by default
+	// to loopback connections. Explicit allow lists are still enforced.
+	disableLocalhostProtection bool
+}
+
+// reject validates r and, if it must be refused, writes a 403 Forbidden
+// response and returns true. Otherwise it returns false and writes nothing.
+//
+// The Host header must match allowedHosts when set, and otherwise be a
+// loopback value on loopback connections. A request with an Origin header
+// must come from an origin in allowedOrigins or allowed by cors, or, when
+// allowedOrigins is not set, from a loopback origin on loopback
+// connections. Requests without an Origin header are not sent by browsers
+// on behalf of other sites and are never rejected because of it.
 //
-// Requests arriving via non-loopback addresses are never rejected: DNS
-// rebinding attacks only target servers reachable at localhost from the
-// victim's browser.
+// Requests arriving via non-loopback addresses are only checked against
+// the explicit allow lists: DNS rebinding attacks only target servers
+// reachable at localhost from the victim's browser.
 //
 // See https://modelcontextprotocol.io/specification/2025-11-25/basic/security_best_practices#local-mcp-server-compromise
-func rejectDNSRebinding(w http.ResponseWriter, r *http.Request) bool {
-	localAddr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
-	if !ok || localAddr == nil {
-		return false
+func (g *originGuard) reject(w http.ResponseWriter, r *http.Request, cors *CORSConfig) bool {
+	loopback := false
+	if !g.disableLocalhostProtection {
+		localAddr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		loopback = ok && localAddr != nil && isLoopbackHost(localAddr.String())
 	}
-	if isLoopbackHost(localAddr.String()) && !isLoopbackHost(r.Host) {
+
+	if len(g.allowedHosts) > 0 {
+		if !hostAllowed(r.Host, g.allowedHosts) {
+			http.Error(w, fmt.Sprintf("Forbidden: invalid Host header %q", r.Host), http.StatusForbidden)
+			return true
+		}
+	} else if loopback && !isLoopbackHost(r.Host) {
 		http.Error(w, fmt.Sprintf("Forbidden: invalid Host header %q", r.Host), http.StatusForbidden)
 		return true
 	}
+
+	// Preflight requests have no effect on the server and are left to the
+	// CORS handling; the request they announce is validated here.
+	origin := r.Header.Get("Origin")
+	if origin == "" || r.Method == http.MethodOptions || (cors.enabled() && cors.resolveOrigin(origin) != "") {
+		return false
+	}
+	var allowed bool
+	switch {
+	case len(g.allowedOrigins) > 0:
+		allowed = originAllowed(origin, g.allowedOrigins)
+	case loopback:
+		allowed = isLoopbackOrigin(origin)
+	default:
+		allowed = true
+	}
+	if !allowed {
+		http.Error(w, fmt.Sprintf("Forbidden: invalid Origin header %q", origin), http.StatusForbidden)
+		return true
+	}
+	return false
+}
+
+// hostAllowed reports whether host, with or without its port, matches one
+// of allowed. Matching is case-insensitive.
+func hostAllowed(host string, allowed []string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	hostname = strings.Trim(hostname, "[]")
+	for _, a := range allowed {
+		if strings.EqualFold(a, host) || strings.EqualFold(strings.Trim(a, "[]"), hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// originAllowed reports whether origin matches one of allowed, where "*"
+// matches any origin. Matching is case-insensitive.
+func originAllowed(origin string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(a, origin) {
+			return true
+		}
+	}
 	return false
 }
+
+// isLoopbackOrigin reports whether origin is an http or https origin on a
+// loopback host, such as http://localhost:3000.
+func isLoopbackOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return isLoopbackHost(u.Host)
+}
@@ -196,3 +196,170 @@ func TestStreamableHTTP_LocalhostProtection_NonLoopbackUnaffected(t *testing.T)
 	httpServer.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
+
+// TestStreamableHTTP_OriginValidation verifies that requests over loopback
+// connections are only accepted from loopback origins unless an allow list
+// is configured, and that WithAllowedOrigins is enforced.
+func TestStreamableHTTP_OriginValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []StreamableHTTPOption
+		origin        string
+		wantForbidden bool
+	}{
+		{name: "accepts requests without Origin"},
+		{name: "accepts localhost origin", origin: "http://localhost:3000"},
+		{name: "accepts 127.0.0.1 origin", origin: "https://127.0.0.1:8443"},
+		{name: "rejects foreign origin", origin: "https://evil.com", wantForbidden: true},
+		{name: "rejects null origin", origin: "null", wantForbidden: true},
+		{
+			name:   "accepts origin allowed by CORS",
+			opts:   []StreamableHTTPOption{WithStreamableHTTPCORS(WithCORSAllowedOrigins("https://app.example.com"))},
+			origin: "https://app.example.com",
+		},
+		{
+			name:   "accepts allowed origin",
+			opts:   []StreamableHTTPOption{WithAllowedOrigins("https://app.example.com")},
+			origin: "https://APP.example.com",
+		},
+		{
+			name:          "allow list replaces localhost origins",
+			opts:          []StreamableHTTPOption{WithAllowedOrigins("https://app.example.com")},
+			origin:        "http://localhost:3000",
+			wantForbidden: true,
+		},
+		{
+			name:   "disabled accepts foreign origin",
+			opts:   []StreamableHTTPOption{WithDisableLocalhostProtection(true)},
+			origin: "https://evil.com",
+		},
+		{
+			name:          "disabled still enforces allow list",
+			opts:          []StreamableHTTPOption{WithDisableLocalhostProtection(true), WithAllowedOrigins("https://app.example.com")},
+			origin:        "https://evil.com",
+			wantForbidden: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := NewMCPServer("test", "1.0.0")
+			httpServer := NewStreamableHTTPServer(mcpServer, append([]StreamableHTTPOption{WithStateLess(true)}, tt.opts...)...)
+			addr, client := startLoopbackServer(t, httpServer)
+
+			body, err := json.Marshal(initRequest)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/mcp", addr), bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			if tt.wantForbidden {
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				payload, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Contains(t, string(payload), "invalid Origin header")
+			} else {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// TestStreamableHTTP_AllowedHosts verifies that WithAllowedHosts applies to
+// requests arriving via non-loopback addresses, with or without a port.
+func TestStreamableHTTP_AllowedHosts(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	httpServer := NewStreamableHTTPServer(mcpServer, WithStateLess(true), WithAllowedHosts("mcp.example.com"))
+	localAddr := &net.TCPAddr{IP: net.ParseIP("192.168.1.10"), Port: 8080}
+
+	for host, want := range map[string]int{
+		"mcp.example.com":     http.StatusOK,
+		"MCP.example.com:443": http.StatusOK,
+		"evil.com":            http.StatusForbidden,
+		"localhost":           http.StatusForbidden,
+	} {
+		body, err := json.Marshal(initRequest)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "http://"+host+"/mcp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), http.LocalAddrContextKey, localAddr))
+
+		rr := httptest.NewRecorder()
+		httpServer.ServeHTTP(rr, req)
+		assert.Equal(t, want, rr.Code, host)
+	}
+}
+
+// TestStreamableHTTP_Handle_AllowedOrigins verifies that the framework
+// agnostic Handle entry point enforces the allow lists.
+func TestStreamableHTTP_Handle_AllowedOrigins(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	httpServer := NewStreamableHTTPServer(mcpServer,
+		WithStateLess(true),
+		WithAllowedHosts("mcp.example.com"),
+		WithAllowedOrigins("https://app.example.com"),
+	)
+	body, err := json.Marshal(initRequest)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		host, origin string
+		want         int
+	}{
+		{"mcp.example.com", "https://app.example.com", http.StatusOK},
+		{"mcp.example.com", "https://evil.com", http.StatusForbidden},
+		{"evil.com", "", http.StatusForbidden},
+	} {
+		header := http.Header{"Content-Type": {"application/json"}, "Host": {tt.host}}
+		if tt.origin != "" {
+			header.Set("Origin", tt.origin)
+		}
+		rr := httptest.NewRecorder()
+		httpServer.Handle(newHTTPResponseWriterAdapter(rr), &HTTPRequest{
+			Method:  http.MethodPost,
+			Header:  header,
+			Body:    body,
+			Context: context.Background(),
+		})
+		assert.Equal(t, tt.want, rr.Code, "%s %s", tt.host, tt.origin)
+	}
+}
+
+// TestSSE_AllowedOrigins verifies that the SSE transport enforces
+// WithSSEAllowedOrigins and rejects foreign origins on loopback connections.
+func TestSSE_AllowedOrigins(t *testing.T) {
+	for _, tt := range []struct {
+		opts          []SSEOption
+		origin        string
+		wantForbidden bool
+	}{
+		{origin: "http://localhost:3000"},
+		{origin: "https://evil.com", wantForbidden: true},
+		{opts: []SSEOption{WithSSEAllowedOrigins("https://app.example.com")}, origin: "https://app.example.com"},
+		{opts: []SSEOption{WithSSEAllowedHosts("mcp.example.com")}, wantForbidden: true},
+	} {
+		sseServer := NewSSEServer(NewMCPServer("test", "1.0.0"), tt.opts...)
+		addr, client := startLoopbackServer(t, sseServer)
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/message", addr), bytes.NewReader(nil))
+		require.NoError(t, err)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		if tt.wantForbidden {
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, tt.origin)
+		} else {
+			assert.NotEqual(t, http.StatusForbidden, resp.StatusCode, tt.origin)
+		}
+	}
+}
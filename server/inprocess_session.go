@@ -26,7 +26,9 @@ type RootsHandler interface {
 }
 
 type InProcessSession struct {
-	clientInfoStore // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	clientInfoStore      // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	extensionStore       // provides Extension, Extensions and SetExtensions via method promotion
+	connectionStateStore // provides ConnectionState, OnConnectionStateChange and SetConnectionState via method promotion
 
 	sessionID          string
 	notifications      chan mcp.JSONRPCNotification
@@ -130,10 +132,12 @@ func GenerateInProcessSessionID() string {
 
 // Ensure interface compliance
 var (
-	_ ClientSession          = (*InProcessSession)(nil)
-	_ SessionWithLogging     = (*InProcessSession)(nil)
-	_ SessionWithClientInfo  = (*InProcessSession)(nil)
-	_ SessionWithSampling    = (*InProcessSession)(nil)
-	_ SessionWithElicitation = (*InProcessSession)(nil)
-	_ SessionWithRoots       = (*InProcessSession)(nil)
+	_ ClientSession              = (*InProcessSession)(nil)
+	_ SessionWithLogging         = (*InProcessSession)(nil)
+	_ SessionWithClientInfo      = (*InProcessSession)(nil)
+	_ SessionWithExtensions      = (*InProcessSession)(nil)
+	_ SessionWithConnectionState = (*InProcessSession)(nil)
+	_ SessionWithSampling        = (*InProcessSession)(nil)
+	_ SessionWithElicitation     = (*InProcessSession)(nil)
+	_ SessionWithRoots           = (*InProcessSession)(nil)
 )
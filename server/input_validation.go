@@ -236,45 +236,69 @@ func normalizeArgumentsForValidation(rawArgs any) (any, error) {
 	return jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
 }
 
-// formatValidationError turns the validator's structured error into a single
-// flat message that's friendly for an LLM to read. Each individual constraint
-// violation is rendered as `<path>: <message>` and joined with semicolons.
+// FieldError describes one argument that failed input schema validation.
+// Field is the RFC 6901 JSON Pointer of the offending value within the
+// arguments object, or "<root>" for the arguments object itself.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SchemaValidationError reports tool call arguments that do not satisfy the
+// tool's input schema, with one entry per violated constraint.
+type SchemaValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Error renders every field error as `<field>: <message>`, joined with
+// semicolons.
+func (e *SchemaValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return "input schema validation failed: " + strings.Join(parts, "; ")
+}
+
+// formatValidationError turns the validator's structured error into a
+// *SchemaValidationError whose message is friendly for an LLM to read. Each
+// individual constraint violation is rendered as `<path>: <message>` and
+// joined with semicolons.
 func formatValidationError(err error) error {
 	var verr *jsonschema.ValidationError
 	if !errors.As(err, &verr) {
 		return fmt.Errorf("input schema validation failed: %w", err)
 	}
-	parts := collectValidationMessages(verr)
-	if len(parts) == 0 {
+	fields := collectFieldErrors(verr)
+	if len(fields) == 0 {
 		return fmt.Errorf("input schema validation failed: %s", verr.Error())
 	}
-	return fmt.Errorf("input schema validation failed: %s", strings.Join(parts, "; "))
+	return &SchemaValidationError{Errors: fields}
 }
 
-// collectValidationMessages walks the leaf causes of a validation error tree
-// and produces one message per leaf. Non-leaf nodes don't carry useful
+// collectFieldErrors walks the leaf causes of a validation error tree and
+// produces one field error per leaf. Non-leaf nodes don't carry useful
 // information for the model (they're aggregator nodes like "anyOf failed").
-func collectValidationMessages(verr *jsonschema.ValidationError) []string {
+func collectFieldErrors(verr *jsonschema.ValidationError) []FieldError {
 	if verr == nil {
 		return nil
 	}
-	var out []string
 	if len(verr.Causes) == 0 {
-		out = append(out, formatLeafMessage(verr))
-		return out
+		return []FieldError{leafFieldError(verr)}
 	}
+	var out []FieldError
 	for _, cause := range verr.Causes {
-		out = append(out, collectValidationMessages(cause)...)
+		out = append(out, collectFieldErrors(cause)...)
 	}
 	return out
 }
 
-func formatLeafMessage(verr *jsonschema.ValidationError) string {
+func leafFieldError(verr *jsonschema.ValidationError) FieldError {
 	location := jsonPointer(verr.InstanceLocation)
 	if location == "" {
 		location = "<root>"
 	}
-	return fmt.Sprintf("%s: %+v", location, verr.ErrorKind)
+	return FieldError{Field: location, Message: fmt.Sprintf("%+v", verr.ErrorKind)}
 }
 
 // jsonPointer renders a list of path segments as a RFC 6901 JSON Pointer.
@@ -305,3 +329,15 @@ func escapeJSONPointer(seg string) string {
 func validationToolResult(err error) *mcp.CallToolResult {
 	return mcp.NewToolResultError(err.Error())
 }
+
+// validationRequestError builds the INVALID_PARAMS error returned instead
+// of a tool execution error when WithSchemaValidation is enabled. The error
+// data carries the per-field messages of a *SchemaValidationError.
+func validationRequestError(id any, err error) *requestError {
+	reqErr := &requestError{id: id, code: mcp.INVALID_PARAMS, err: err}
+	var schemaErr *SchemaValidationError
+	if errors.As(err, &schemaErr) {
+		reqErr.data = schemaErr
+	}
+	return reqErr
+}
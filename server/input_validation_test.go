@@ -402,3 +402,34 @@ func TestInputSchemaValidation_ErrorKindReadable(t *testing.T) {
 		})
 	}
 }
+
+func TestWithSchemaValidation_InvalidParams(t *testing.T) {
+	srv := NewMCPServer("test", "1.0.0", WithSchemaValidation())
+	srv.AddTool(mcp.NewTool("create_user",
+		mcp.WithString("name", mcp.Required()),
+		mcp.WithNumber("age", mcp.Min(0)),
+	), okHandler)
+
+	resp := callTool(t, srv, "create_user", map[string]any{"age": -1})
+	jerr, ok := resp.(mcp.JSONRPCError)
+	require.True(t, ok, "expected JSON-RPC error, got %T", resp)
+	assert.Equal(t, mcp.INVALID_PARAMS, jerr.Error.Code)
+	assert.Contains(t, jerr.Error.Message, "input schema validation failed")
+
+	schemaErr, ok := jerr.Error.Data.(*SchemaValidationError)
+	require.True(t, ok, "expected *SchemaValidationError data, got %T", jerr.Error.Data)
+	fields := map[string]string{}
+	for _, fe := range schemaErr.Errors {
+		fields[fe.Field] = fe.Message
+	}
+	assert.Contains(t, fields, "<root>", "missing required property is reported on the root")
+	assert.Contains(t, fields["<root>"], "name")
+	assert.Contains(t, fields, "/age")
+
+	// The data serializes as a list of field errors.
+	raw, err := json.Marshal(jerr)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"data":{"errors":[{"field":`)
+
+	requireToolSuccess(t, callTool(t, srv, "create_user", map[string]any{"name": "ada", "age": 36}))
+}
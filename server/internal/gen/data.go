@@ -10,8 +10,9 @@ type MCPRequestType struct {
 	GroupHookName  string
 	UnmarshalError string
 	HandlerFunc    string
-	ResultIsAny    bool // If true, result type is 'any' instead of '*mcp.ResultType'
-	HasMeta        bool // If true, request.Params.Meta holds _meta and is extracted into ctx
+	ResultIsAny    bool   // If true, result type is 'any' instead of '*mcp.ResultType'
+	HasMeta        bool   // If true, request.Params.Meta holds _meta and is extracted into ctx
+	MetaFunc       string // If set, called with the raw message to fill request.Request.Params.Meta
 }
 
 var MCPRequestTypes = []MCPRequestType{
@@ -22,6 +23,7 @@ var MCPRequestTypes = []MCPRequestType{
 		HookName:       "Initialize",
 		UnmarshalError: "invalid initialize request",
 		HandlerFunc:    "handleInitialize",
+		MetaFunc:       "initializeMeta",
 	}, {
 		MethodName:     "MethodPing",
 		ParamType:      "PingRequest",
@@ -177,6 +179,26 @@ var MCPRequestTypes = []MCPRequestType{
 		HookName:       "CancelTask",
 		UnmarshalError: "invalid cancel task request",
 		HandlerFunc:    "handleCancelTask",
+	}, {
+		MethodName:     "MethodTasksSubscribe",
+		ParamType:      "SubscribeTasksRequest",
+		ResultType:     "EmptyResult",
+		Group:          "tasks",
+		GroupName:      "Tasks",
+		GroupHookName:  "Task",
+		HookName:       "SubscribeTasks",
+		UnmarshalError: "invalid subscribe tasks request",
+		HandlerFunc:    "handleSubscribeTasks",
+	}, {
+		MethodName:     "MethodTasksClaim",
+		ParamType:      "ClaimTaskRequest",
+		ResultType:     "ClaimTaskResult",
+		Group:          "tasks",
+		GroupName:      "Tasks",
+		GroupHookName:  "Task",
+		HookName:       "ClaimTask",
+		UnmarshalError: "invalid claim task request",
+		HandlerFunc:    "handleClaimTask",
 	}, {
 		MethodName:     "MethodCompletionComplete",
 		ParamType:      "CompleteRequest",
@@ -0,0 +1,508 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JWTValidatorConfig configures a JWTValidator.
+type JWTValidatorConfig struct {
+	// Issuer is the expected "iss" claim: the issuer identifier of the
+	// authorization server. Optional but recommended.
+	Issuer string
+
+	// Audience is the identifier the tokens must be issued for, usually the
+	// Resource of the server's protected resource metadata. Tokens whose
+	// "aud" claim does not contain it are rejected. Required.
+	Audience string
+
+	// RequiredScopes are scopes every token must carry. Tokens missing one
+	// are rejected with an insufficient_scope error, which is answered with
+	// 403 Forbidden.
+	RequiredScopes []string
+
+	// JWKSURI is the URL of the authorization server's JSON Web Key Set,
+	// fetched on first use, refreshed after JWKSCacheTTL and when a token
+	// names an unknown key. After a failed fetch the key set is fetched
+	// again only after a backoff of up to five minutes. Either JWKSURI or
+	// Keys is required.
+	JWKSURI string
+
+	// Keys are static verification keys by key ID, used instead of
+	// JWKSURI. The key with ID "" verifies tokens without a "kid" header.
+	// Supported are *rsa.PublicKey, *ecdsa.PublicKey and ed25519.PublicKey.
+	Keys map[string]crypto.PublicKey
+
+	// Algorithms restricts the accepted signing algorithms. The default
+	// accepts RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512
+	// and EdDSA. Symmetric algorithms and "none" are never accepted.
+	Algorithms []string
+
+	// Leeway is the clock skew tolerated when checking "exp" and "nbf".
+	// Defaults to one minute.
+	Leeway time.Duration
+
+	// JWKSCacheTTL is how long fetched keys are used before the key set is
+	// fetched again. Defaults to one hour.
+	JWKSCacheTTL time.Duration
+
+	// HTTPClient fetches the key set. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// JWTValidator validates JWT access tokens for a resource server, as the
+// MCP authorization spec requires: the signature against the authorization
+// server's keys, the issuer, the audience, the validity period and the
+// required scopes. Use it with BearerAuthenticator:
+//
+//	validator, err := server.NewJWTValidator(server.JWTValidatorConfig{
+//		Issuer:   "https://auth.example.com",
+//		Audience: "https://mcp.example.com",
+//		JWKSURI:  "https://auth.example.com/.well-known/jwks.json",
+//	})
+//	...
+//	server.WithAuthenticator(server.BearerAuthenticator(validator.Validate))
+//
+// Errors about the token are *TokenError values.
+type JWTValidator struct {
+	config     JWTValidatorConfig
+	algorithms []string
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	// minRefresh is the least time between two fetches of the key set, so
+	// that tokens with made-up key IDs cannot make the server hammer it.
+	minRefresh time.Duration
+	// fetch is the fetch of the key set in flight, shared by every request
+	// needing it.
+	fetch *jwksFetch
+	// failures counts the consecutive failed fetches, and failedAt and
+	// fetchErr describe the last one. Fetches back off after failures.
+	failures int
+	failedAt time.Time
+	fetchErr error
+}
+
+// jwksFetch is a fetch of the key set; done is closed once it completed.
+type jwksFetch struct {
+	done chan struct{}
+}
+
+const (
+	// jwksFetchTimeout bounds a fetch of the key set. Fetches are shared
+	// between requests, so they do not use the context of any one of them.
+	jwksFetchTimeout = 30 * time.Second
+	// jwksMinBackoff and jwksMaxBackoff bound the time between fetches after
+	// a failure; it doubles with every consecutive failure.
+	jwksMinBackoff = time.Second
+	jwksMaxBackoff = 5 * time.Minute
+)
+
+var defaultJWTAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// NewJWTValidator returns a validator for config. It fails if the audience
+// or both key sources are missing, or if Algorithms lists an unsupported
+// algorithm.
+func NewJWTValidator(config JWTValidatorConfig) (*JWTValidator, error) {
+	if config.Audience == "" {
+		return nil, errors.New("jwt validator: audience is required")
+	}
+	if config.JWKSURI == "" && len(config.Keys) == 0 {
+		return nil, errors.New("jwt validator: either JWKSURI or Keys is required")
+	}
+	algorithms := config.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = defaultJWTAlgorithms
+	}
+	for _, alg := range algorithms {
+		if !slices.Contains(defaultJWTAlgorithms, alg) {
+			return nil, fmt.Errorf("jwt validator: unsupported algorithm %q", alg)
+		}
+	}
+	if config.Leeway == 0 {
+		config.Leeway = time.Minute
+	}
+	if config.JWKSCacheTTL == 0 {
+		config.JWKSCacheTTL = time.Hour
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &JWTValidator{
+		config:     config,
+		algorithms: algorithms,
+		keys:       config.Keys,
+		minRefresh: 30 * time.Second,
+	}, nil
+}
+
+// Validate checks token and returns the identity it grants: the "sub"
+// claim as Subject, the scopes of the "scope" or "scp" claim, and all
+// claims.
+func (v *JWTValidator) Validate(ctx context.Context, token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, invalidToken("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return Identity{}, invalidToken("malformed token header")
+	}
+	if !slices.Contains(v.algorithms, header.Alg) {
+		return Identity{}, invalidToken(fmt.Sprintf("signing algorithm %q not accepted", header.Alg))
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, invalidToken("malformed token signature")
+	}
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return Identity{}, err
+	}
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return Identity{}, invalidToken(err.Error())
+	}
+
+	var claims map[string]any
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return Identity{}, invalidToken("malformed token claims")
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return Identity{}, err
+	}
+	identity := Identity{Claims: claims, Scopes: tokenScopes(claims)}
+	identity.Subject, _ = claims["sub"].(string)
+	for _, scope := range v.config.RequiredScopes {
+		if !identity.HasScope(scope) {
+			return Identity{}, &TokenError{
+				Code:        TokenErrorInsufficientScope,
+				Description: fmt.Sprintf("token lacks scope %q", scope),
+				Scope:       strings.Join(v.config.RequiredScopes, " "),
+			}
+		}
+	}
+	return identity, nil
+}
+
+func (v *JWTValidator) checkClaims(claims map[string]any) error {
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return invalidToken("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(v.config.Leeway)) {
+		return invalidToken("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.config.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return invalidToken("token not yet valid")
+	}
+	if v.config.Issuer != "" && claims["iss"] != v.config.Issuer {
+		return invalidToken("token issued by another issuer")
+	}
+	var audiences []any
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = []any{aud}
+	case []any:
+		audiences = aud
+	}
+	if !slices.Contains(audiences, any(v.config.Audience)) {
+		return invalidToken("token issued for another audience")
+	}
+	return nil
+}
+
+// tokenScopes returns the scopes of a space-separated "scope" claim
+// (RFC 8693), or of a "scp" claim as used by some authorization servers.
+func tokenScopes(claims map[string]any) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	switch scp := claims["scp"].(type) {
+	case string:
+		return strings.Fields(scp)
+	case []any:
+		scopes := make([]string, 0, len(scp))
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+// key returns the verification key with the given ID, fetching the key set
+// when it is stale or does not hold the key. A stale key set keeps serving
+// the keys it holds while it is refreshed in the background; requests for
+// an unknown key wait for the fetch.
+func (v *JWTValidator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	if v.config.JWKSURI == "" {
+		defer v.mu.Unlock()
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		return nil, invalidToken("unknown signing key")
+	}
+
+	since := time.Since(v.fetchedAt)
+	key, ok := v.keys[kid]
+	stale := v.fetchedAt.IsZero() || since > v.config.JWKSCacheTTL || (!ok && since > v.minRefresh)
+	if !stale {
+		v.mu.Unlock()
+		return key, nil
+	}
+	fetch := v.startFetch()
+	fetchErr := v.fetchErr
+	v.mu.Unlock()
+
+	if ok {
+		// Keep using the cached key while the key set is refreshed or
+		// unreachable.
+		return key, nil
+	}
+	if fetch == nil {
+		// Backing off after a failed fetch.
+		return nil, fetchErr
+	}
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if v.fetchErr != nil && v.failedAt.After(v.fetchedAt) {
+		return nil, v.fetchErr
+	}
+	return nil, invalidToken("unknown signing key")
+}
+
+// startFetch returns the fetch of the key set in flight, starting one
+// unless the last fetch failed too recently, in which case it returns nil.
+// v.mu must be held.
+func (v *JWTValidator) startFetch() *jwksFetch {
+	if v.fetch != nil {
+		return v.fetch
+	}
+	if v.failures > 0 {
+		backoff := min(jwksMinBackoff<<min(v.failures-1, 20), jwksMaxBackoff)
+		if time.Since(v.failedAt) < backoff {
+			return nil
+		}
+	}
+	fetch := &jwksFetch{done: make(chan struct{})}
+	v.fetch = fetch
+	go func() {
+		defer close(fetch.done)
+		ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+		defer cancel()
+		keys, err := v.fetchKeys(ctx)
+
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		v.fetch = nil
+		if err != nil {
+			v.failures++
+			v.failedAt, v.fetchErr = time.Now(), err
+			return
+		}
+		v.keys, v.fetchedAt = keys, time.Now()
+		v.failures, v.fetchErr = 0, nil
+	}()
+	return fetch
+}
+
+func (v *JWTValidator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS: unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	return ParseJWKS(body)
+}
+
+// ParseJWKS parses a JSON Web Key Set (RFC 7517) into verification keys by
+// key ID. RSA, EC (P-256, P-384, P-521) and Ed25519 keys are kept; keys for
+// other uses than signing, of other types and keys that are malformed are
+// skipped, so that one bad key does not disable the others. It fails only if
+// data is not a key set.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			n, errN := decodeBigInt(k.N)
+			e, errE := decodeBigInt(k.E)
+			if errN != nil || errE != nil || !e.IsInt64() {
+				continue
+			}
+			keys[k.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := decodeBigInt(k.X)
+			y, errY := decodeBigInt(k.Y)
+			if errX != nil || errY != nil || !curve.IsOnCurve(x, y) {
+				continue
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		case "OKP":
+			if k.Crv != "Ed25519" {
+				continue
+			}
+			x, err := base64.RawURLEncoding.DecodeString(k.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				continue
+			}
+			keys[k.Kid] = ed25519.PublicKey(x)
+		}
+	}
+	return keys, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// verifyJWTSignature checks the JWS signature of signingInput made with
+// alg.
+func verifyJWTSignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	errInvalid := errors.New("invalid token signature")
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, []byte(signingInput), signature) {
+			return errInvalid
+		}
+		return nil
+	}
+
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	}
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(pub, hash, digest, signature) != nil {
+			return errInvalid
+		}
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPSS(pub, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) != nil {
+			return errInvalid
+		}
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		// ES512 uses P-521; the other algorithms name their curve's size.
+		curveSize := map[crypto.Hash]int{crypto.SHA256: 256, crypto.SHA384: 384, crypto.SHA512: 521}[hash]
+		if !ok || pub.Curve.Params().BitSize != curveSize {
+			return errInvalid
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errInvalid
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errInvalid
+		}
+	default:
+		return errInvalid
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signJWT returns a token with the given header and claims, signed with
+// key for the header's alg.
+func signJWT(t *testing.T, key crypto.Signer, header, claims map[string]any) string {
+	t.Helper()
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	var err error
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest[:])
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(input))
+	}
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":   "https://auth.example.com",
+		"aud":   "https://mcp.example.com",
+		"sub":   "alice",
+		"scope": "mcp:read mcp:write",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTValidator_Validate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	validator, err := NewJWTValidator(JWTValidatorConfig{
+		Issuer:   "https://auth.example.com",
+		Audience: "https://mcp.example.com",
+		Keys: map[string]crypto.PublicKey{
+			"rsa": &rsaKey.PublicKey,
+			"ec":  &ecKey.PublicKey,
+			"ed":  edKey.Public(),
+		},
+		RequiredScopes: []string{"mcp:read"},
+	})
+	require.NoError(t, err)
+
+	with := func(key string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		key    crypto.Signer
+		header map[string]any
+		claims map[string]any
+		code   string
+	}{
+		{name: "RS256", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"}, claims: validClaims()},
+		{name: "ES256", key: ecKey, header: map[string]any{"alg": "ES256", "kid": "ec"}, claims: validClaims()},
+		{name: "EdDSA", key: edKey, header: map[string]any{"alg": "EdDSA", "kid": "ed"}, claims: validClaims()},
+		{name: "audience list", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("aud", []string{"other", "https://mcp.example.com"})},
+		{name: "wrong signer", key: otherKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: validClaims(), code: TokenErrorInvalidToken},
+		{name: "key of another type", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "ec"},
+			claims: validClaims(), code: TokenErrorInvalidToken},
+		{name: "unknown key", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "nope"},
+			claims: validClaims(), code: TokenErrorInvalidToken},
+		{name: "alg none", key: rsaKey, header: map[string]any{"alg": "none", "kid": "rsa"},
+			claims: validClaims(), code: TokenErrorInvalidToken},
+		{name: "symmetric alg", key: rsaKey, header: map[string]any{"alg": "HS256", "kid": "rsa"},
+			claims: validClaims(), code: TokenErrorInvalidToken},
+		{name: "expired", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("exp", time.Now().Add(-time.Hour).Unix()), code: TokenErrorInvalidToken},
+		{name: "no expiry", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("exp", nil), code: TokenErrorInvalidToken},
+		{name: "not yet valid", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("nbf", time.Now().Add(time.Hour).Unix()), code: TokenErrorInvalidToken},
+		{name: "wrong issuer", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("iss", "https://evil.example.com"), code: TokenErrorInvalidToken},
+		{name: "wrong audience", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("aud", "https://other.example.com"), code: TokenErrorInvalidToken},
+		{name: "missing scope", key: rsaKey, header: map[string]any{"alg": "RS256", "kid": "rsa"},
+			claims: with("scope", "mcp:write"), code: TokenErrorInsufficientScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := validator.Validate(t.Context(), signJWT(t, tt.key, tt.header, tt.claims))
+			if tt.code != "" {
+				var tokenErr *TokenError
+				require.ErrorAs(t, err, &tokenErr)
+				assert.Equal(t, tt.code, tokenErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", identity.Subject)
+			assert.Equal(t, []string{"mcp:read", "mcp:write"}, identity.Scopes)
+			assert.Equal(t, "https://auth.example.com", identity.Claims["iss"])
+		})
+	}
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := validator.Validate(t.Context(), "not-a-jwt")
+		var tokenErr *TokenError
+		require.ErrorAs(t, err, &tokenErr)
+		assert.Equal(t, TokenErrorInvalidToken, tokenErr.Code)
+	})
+}
+
+func TestNewJWTValidator_Config(t *testing.T) {
+	_, err := NewJWTValidator(JWTValidatorConfig{JWKSURI: "https://auth.example.com/jwks"})
+	assert.Error(t, err)
+	_, err = NewJWTValidator(JWTValidatorConfig{Audience: "https://mcp.example.com"})
+	assert.Error(t, err)
+	_, err = NewJWTValidator(JWTValidatorConfig{
+		Audience:   "https://mcp.example.com",
+		JWKSURI:    "https://auth.example.com/jwks",
+		Algorithms: []string{"HS256"},
+	})
+	assert.Error(t, err)
+}
+
+func TestJWTValidator_JWKS(t *testing.T) {
+	key1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk := func(kid string, key *ecdsa.PrivateKey) map[string]any {
+		return map[string]any{
+			"kty": "EC", "kid": kid, "use": "sig", "crv": "P-256",
+			"x": base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y": base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}
+	}
+
+	var fetches atomic.Int32
+	var rotated atomic.Bool
+	release := make(chan struct{})
+	close(release)
+	var gate atomic.Pointer[chan struct{}]
+	gate.Store(&release)
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-*gate.Load()
+		keys := []any{
+			jwk("k1", key1),
+			map[string]any{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+			map[string]any{"kty": "EC", "kid": "broken", "crv": "P-256", "x": "AA", "y": "AA"},
+		}
+		if rotated.Load() {
+			keys = append(keys, jwk("k2", key2))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	defer jwks.Close()
+
+	validator, err := NewJWTValidator(JWTValidatorConfig{
+		Audience: "https://mcp.example.com",
+		JWKSURI:  jwks.URL,
+	})
+	require.NoError(t, err)
+	validator.minRefresh = 0
+
+	_, err = validator.Validate(t.Context(), signJWT(t, key1, map[string]any{"alg": "ES256", "kid": "k1"}, validClaims()))
+	require.NoError(t, err)
+	_, err = validator.Validate(t.Context(), signJWT(t, key1, map[string]any{"alg": "ES256", "kid": "k1"}, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "keys are cached")
+
+	rotated.Store(true)
+	_, err = validator.Validate(t.Context(), signJWT(t, key2, map[string]any{"alg": "ES256", "kid": "k2"}, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load(), "an unknown key refreshes the key set")
+
+	// A slow refresh is shared by the requests waiting for it and does not
+	// hold up requests for cached keys.
+	key3, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	blocked := make(chan struct{})
+	gate.Store(&blocked)
+	errs := make(chan error, 3)
+	for range 3 {
+		go func() {
+			_, err := validator.Validate(t.Context(), signJWT(t, key3, map[string]any{"alg": "ES256", "kid": "k3"}, validClaims()))
+			errs <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return fetches.Load() == 3 }, time.Second, time.Millisecond)
+	_, err = validator.Validate(t.Context(), signJWT(t, key1, map[string]any{"alg": "ES256", "kid": "k1"}, validClaims()))
+	require.NoError(t, err)
+	close(blocked)
+	for range 3 {
+		var tokenErr *TokenError
+		assert.ErrorAs(t, <-errs, &tokenErr)
+	}
+	assert.Equal(t, int32(3), fetches.Load(), "concurrent requests share one fetch")
+}
+
+func TestJWTValidator_JWKSUnavailable(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	var fetches atomic.Int32
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer jwks.Close()
+
+	validator, err := NewJWTValidator(JWTValidatorConfig{
+		Audience: "https://mcp.example.com",
+		JWKSURI:  jwks.URL,
+	})
+	require.NoError(t, err)
+
+	token := signJWT(t, key, map[string]any{"alg": "ES256", "kid": "k1"}, validClaims())
+	for range 3 {
+		_, err = validator.Validate(t.Context(), token)
+		assert.ErrorContains(t, err, "503")
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "failed fetches back off")
+}
+
+func TestParseJWKS_SkipsMalformedKeys(t *testing.T) {
+	keys, err := ParseJWKS([]byte(`{"keys":[
+		{"kty":"RSA","kid":"bad-rsa","n":"","e":"AQAB"},
+		{"kty":"OKP","kid":"bad-ed","crv":"Ed25519","x":"AA"},
+		{"kty":"OKP","kid":"good","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+	]}`))
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Contains(t, keys, "good")
+
+	_, err = ParseJWKS([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestWithAuthenticator_BearerChallenge(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	validator, err := NewJWTValidator(JWTValidatorConfig{
+		Audience:       "https://mcp.example.com/mcp",
+		Keys:           map[string]crypto.PublicKey{"": &key.PublicKey},
+		RequiredScopes: []string{"mcp:admin"},
+	})
+	require.NoError(t, err)
+	mcpServer := NewMCPServer("test", "1.0.0", WithAuthenticator(BearerAuthenticator(validator.Validate)))
+	server := NewTestStreamableHTTPServer(mcpServer, WithProtectedResourceMetadata(ProtectedResourceMetadataConfig{
+		Resource:             "https://mcp.example.com/mcp",
+		AuthorizationServers: []string{"https://auth.example.com"},
+	}))
+	defer server.Close()
+	const metadata = `resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`
+
+	post := func(token string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := post("")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "Bearer "+metadata, resp.Header.Get("WWW-Authenticate"))
+
+	resp = post("garbage")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Bearer error="invalid_token", error_description="malformed token", `+metadata,
+		resp.Header.Get("WWW-Authenticate"))
+
+	claims := validClaims()
+	claims["aud"] = "https://mcp.example.com/mcp"
+	resp = post(signJWT(t, key, map[string]any{"alg": "ES256"}, claims))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, `Bearer error="insufficient_scope", error_description="token lacks scope \"mcp:admin\"", scope="mcp:admin", `+metadata,
+		resp.Header.Get("WWW-Authenticate"))
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithMethodLatency advertises that requests for method, such as
+// "resources/read" or "tools/call", fall in class. The hints are published
+// in the _meta of the server capabilities under mcp.LatencyHintsMetaKey;
+// clients may use them to pick timeouts and to decide between a spinner and
+// a task.
+func WithMethodLatency(method string, class mcp.LatencyClass) ServerOption {
+	return func(s *MCPServer) {
+		if s.latencyHints.Methods == nil {
+			s.latencyHints.Methods = make(map[string]mcp.LatencyClass)
+		}
+		s.latencyHints.Methods[method] = class
+	}
+}
+
+// WithToolLatency advertises that calls of the named tool fall in class,
+// overriding the class of tools/call. See WithMethodLatency.
+func WithToolLatency(tool string, class mcp.LatencyClass) ServerOption {
+	return func(s *MCPServer) {
+		if s.latencyHints.Tools == nil {
+			s.latencyHints.Tools = make(map[string]mcp.LatencyClass)
+		}
+		s.latencyHints.Tools[tool] = class
+	}
+}
+
+// addLatencyHints attaches the server's latency hints to the _meta of the
+// capabilities in result.
+func (s *MCPServer) addLatencyHints(result *mcp.InitializeResult) {
+	if s.latencyHints.IsZero() {
+		return
+	}
+	meta := &mcp.Meta{AdditionalFields: make(map[string]any, 1)}
+	if caps := result.Capabilities.Meta; caps != nil {
+		meta.ProgressToken = caps.ProgressToken
+		maps.Copy(meta.AdditionalFields, caps.AdditionalFields)
+	}
+	meta.AdditionalFields[mcp.LatencyHintsMetaKey] = s.latencyHints
+	result.Capabilities.Meta = meta
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Levels for the MCP log levels that slog has no constant for. Use them
+// with the loggers returned by Logger, as in
+// logger.Log(ctx, server.LevelCritical, "disk full").
+const (
+	LevelNotice    slog.Level = slog.LevelInfo + 2
+	LevelCritical  slog.Level = slog.LevelError + 4
+	LevelAlert     slog.Level = slog.LevelError + 8
+	LevelEmergency slog.Level = slog.LevelError + 12
+)
+
+// Logger returns a logger whose records are sent to the session in ctx as
+// notifications/message. Records below the level the client chose with
+// logging/setLevel are discarded without being formatted, and the server's
+// log rate limits apply (see WithLogRateLimits).
+//
+// The notification's data is an object holding the record's message under
+// "msg" and its attributes, with groups as nested objects.
+//
+// Outside of a request, or for sessions that do not support logging, the
+// returned logger discards everything.
+func Logger(ctx context.Context) *slog.Logger {
+	h := &notificationLogHandler{ctx: ctx, server: ServerFromContext(ctx)}
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithLogging); ok {
+		h.session = session
+	}
+	return slog.New(h)
+}
+
+// loggingLevelFromSlog maps a slog level to the MCP level with the same
+// severity, rounding down between levels.
+func loggingLevelFromSlog(level slog.Level) mcp.LoggingLevel {
+	switch {
+	case level >= LevelEmergency:
+		return mcp.LoggingLevelEmergency
+	case level >= LevelAlert:
+		return mcp.LoggingLevelAlert
+	case level >= LevelCritical:
+		return mcp.LoggingLevelCritical
+	case level >= slog.LevelError:
+		return mcp.LoggingLevelError
+	case level >= slog.LevelWarn:
+		return mcp.LoggingLevelWarning
+	case level >= LevelNotice:
+		return mcp.LoggingLevelNotice
+	case level >= slog.LevelInfo:
+		return mcp.LoggingLevelInfo
+	default:
+		return mcp.LoggingLevelDebug
+	}
+}
+
+// notificationLogHandler is the slog.Handler behind Logger.
+type notificationLogHandler struct {
+	ctx     context.Context
+	server  *MCPServer
+	session SessionWithLogging
+
+	attrs  []slog.Attr // attributes from WithAttrs, nested in their groups
+	groups []string    // groups opened by WithGroup
+}
+
+func (h *notificationLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.server == nil || h.session == nil || !h.session.Initialized() {
+		return false
+	}
+	return loggingLevelFromSlog(level).ShouldSendTo(h.session.GetLogLevel())
+}
+
+func (h *notificationLogHandler) Handle(_ context.Context, r slog.Record) error {
+	data := map[string]any{"msg": r.Message}
+	for _, a := range h.attrs {
+		addLogAttr(data, a)
+	}
+	record := data
+	for _, g := range h.groups {
+		group, ok := record[g].(map[string]any)
+		if !ok {
+			group = map[string]any{}
+			record[g] = group
+		}
+		record = group
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addLogAttr(record, a)
+		return true
+	})
+	return h.server.SendLogMessageToClient(h.ctx,
+		mcp.NewLoggingMessageNotification(loggingLevelFromSlog(r.Level), "", data))
+}
+
+func (h *notificationLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+	h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+	return &h2
+}
+
+func (h *notificationLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// addLogAttr adds a to m the way slog's JSON handler would, except that
+// values are left for the transport to encode.
+func addLogAttr(m map[string]any, a slog.Attr) {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		group := m
+		if a.Key != "" {
+			g, ok := m[a.Key].(map[string]any)
+			if !ok {
+				g = map[string]any{}
+				m[a.Key] = g
+			}
+			group = g
+		}
+		for _, ga := range attrs {
+			addLogAttr(group, ga)
+		}
+	default:
+		if a.Key == "" {
+			return
+		}
+		if err, ok := v.Any().(error); ok {
+			m[a.Key] = err.Error()
+			return
+		}
+		m[a.Key] = v.Any()
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_SendsNotifications(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithLogging(), WithToolCapabilities(false))
+	session := &sessionTestClientWithLogging{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+	}
+	session.Initialize()
+	session.SetLogLevel(mcp.LoggingLevelInfo)
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+
+	s.AddTool(mcp.NewTool("fetch"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := Logger(ctx).With("tool", "fetch")
+		logger.Debug("dropped")
+		logger.WithGroup("http").Info("fetching", "status", 200, "err", errors.New("slow"))
+		logger.Log(ctx, LevelCritical, "disk full")
+		return &mcp.CallToolResult{}, nil
+	})
+	s.HandleMessage(s.WithContext(t.Context(), session),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fetch"}}`))
+
+	require.Len(t, session.notificationChannel, 2)
+	n := <-session.notificationChannel
+	assert.Equal(t, "notifications/message", n.Method)
+	assert.Equal(t, mcp.LoggingLevelInfo, n.Params.AdditionalFields["level"])
+	assert.Equal(t, map[string]any{
+		"msg":  "fetching",
+		"tool": "fetch",
+		"http": map[string]any{"status": int64(200), "err": "slow"},
+	}, n.Params.AdditionalFields["data"])
+
+	n = <-session.notificationChannel
+	assert.Equal(t, mcp.LoggingLevelCritical, n.Params.AdditionalFields["level"])
+	assert.Equal(t, map[string]any{"msg": "disk full", "tool": "fetch"}, n.Params.AdditionalFields["data"])
+}
+
+func TestLogger_NoSession(t *testing.T) {
+	logger := Logger(t.Context())
+	assert.False(t, logger.Enabled(t.Context(), slog.LevelError))
+	logger.Error("discarded")
+}
+
+func TestLoggingLevelFromSlog(t *testing.T) {
+	tests := map[slog.Level]mcp.LoggingLevel{
+		slog.LevelDebug:     mcp.LoggingLevelDebug,
+		slog.LevelInfo:      mcp.LoggingLevelInfo,
+		slog.LevelInfo + 1:  mcp.LoggingLevelInfo,
+		LevelNotice:         mcp.LoggingLevelNotice,
+		slog.LevelWarn:      mcp.LoggingLevelWarning,
+		slog.LevelError:     mcp.LoggingLevelError,
+		LevelCritical:       mcp.LoggingLevelCritical,
+		LevelAlert:          mcp.LoggingLevelAlert,
+		LevelEmergency:      mcp.LoggingLevelEmergency,
+		LevelEmergency + 10: mcp.LoggingLevelEmergency,
+	}
+	for level, want := range tests {
+		assert.Equal(t, want, loggingLevelFromSlog(level), level.String())
+	}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// LogRateLimit is a token bucket budget for log notifications of one level:
+// Burst messages may be sent at once, refilled at Rate messages per second.
+type LogRateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultLogRateLimits returns the per-level budgets used by
+// WithLogRateLimits when given nil: debug is throttled heavily, info and
+// notice moderately and warning lightly.
+func DefaultLogRateLimits() map[mcp.LoggingLevel]LogRateLimit {
+	return map[mcp.LoggingLevel]LogRateLimit{
+		mcp.LoggingLevelDebug:   {Rate: 2, Burst: 10},
+		mcp.LoggingLevelInfo:    {Rate: 10, Burst: 50},
+		mcp.LoggingLevelNotice:  {Rate: 10, Burst: 50},
+		mcp.LoggingLevelWarning: {Rate: 50, Burst: 100},
+	}
+}
+
+// WithLogRateLimits throttles notifications/message per session with a
+// budget for each level, so that chatty handlers cannot flood a client's
+// stream. Levels without a budget are not throttled, and error and more
+// severe messages are never dropped. When messages have been suppressed, a
+// summary notification reporting how many were dropped is sent as soon as
+// the budget allows another message, or before the next message of that
+// level if it comes first. A nil map uses DefaultLogRateLimits.
+func WithLogRateLimits(limits map[mcp.LoggingLevel]LogRateLimit) ServerOption {
+	return func(s *MCPServer) {
+		if limits == nil {
+			limits = DefaultLogRateLimits()
+		}
+		s.logRateLimits = limits
+	}
+}
+
+// logLimiter holds the per-level token buckets of one session.
+type logLimiter struct {
+	mu      sync.Mutex
+	buckets map[mcp.LoggingLevel]*logBucket
+	closed  bool
+}
+
+type logBucket struct {
+	tokens     float64
+	last       time.Time
+	suppressed int
+	// flush sends the summary of the suppressed messages when the budget
+	// allows another message; nil when none is pending.
+	flush *time.Timer
+}
+
+// allowLogMessage reports whether a log message of level may be sent to the
+// session, and how many messages of that level were suppressed since the
+// last one allowed.
+func (s *MCPServer) allowLogMessage(sessionID string, level mcp.LoggingLevel) (allowed bool, suppressed int) {
+	limit, ok := s.logRateLimits[level]
+	if !ok || level.ShouldSendTo(mcp.LoggingLevelError) {
+		return true, 0
+	}
+
+	value, _ := s.logLimiters.LoadOrStore(sessionID, &logLimiter{buckets: make(map[mcp.LoggingLevel]*logBucket)})
+	limiter := value.(*logLimiter)
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := s.now()
+	bucket, ok := limiter.buckets[level]
+	if !ok {
+		bucket = &logBucket{tokens: float64(limit.Burst), last: now}
+		limiter.buckets[level] = bucket
+	}
+	bucket.tokens = min(float64(limit.Burst), bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		bucket.suppressed++
+		if bucket.flush == nil && limit.Rate > 0 && !limiter.closed {
+			wait := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+			bucket.flush = time.AfterFunc(wait, func() {
+				s.flushSuppressedLogs(sessionID, limiter, level)
+			})
+		}
+		return false, 0
+	}
+	bucket.tokens--
+	suppressed, bucket.suppressed = bucket.suppressed, 0
+	if bucket.flush != nil {
+		bucket.flush.Stop()
+		bucket.flush = nil
+	}
+	return true, suppressed
+}
+
+// flushSuppressedLogs sends the summary of the messages of level suppressed
+// for the session, once the window that suppressed them has closed.
+func (s *MCPServer) flushSuppressedLogs(sessionID string, limiter *logLimiter, level mcp.LoggingLevel) {
+	limiter.mu.Lock()
+	bucket := limiter.buckets[level]
+	suppressed := bucket.suppressed
+	bucket.suppressed = 0
+	bucket.flush = nil
+	limiter.mu.Unlock()
+	if suppressed == 0 {
+		return
+	}
+
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return
+	}
+	session := value.(ClientSession)
+	if !session.Initialized() {
+		return
+	}
+	// Blocked channels are reported through the OnError hook.
+	_ = s.sendNotificationToSpecificClient(session, s.buildLogNotification(suppressedLogsSummary(level, suppressed)))
+}
+
+// dropLogLimiter releases the log budgets of an ended session.
+func (s *MCPServer) dropLogLimiter(sessionID string) {
+	value, ok := s.logLimiters.LoadAndDelete(sessionID)
+	if !ok {
+		return
+	}
+	limiter := value.(*logLimiter)
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.closed = true
+	for _, bucket := range limiter.buckets {
+		if bucket.flush != nil {
+			bucket.flush.Stop()
+			bucket.flush = nil
+		}
+	}
+}
+
+// sendRateLimitedLog sends notification through send unless the session's
+// budget for its level is exhausted, reporting suppressed messages first.
+func (s *MCPServer) sendRateLimitedLog(sessionID string, notification mcp.LoggingMessageNotification, send func(mcp.JSONRPCNotification) error) error {
+	level := notification.Params.Level
+	allowed, suppressed := s.allowLogMessage(sessionID, level)
+	if !allowed {
+		return nil
+	}
+	if suppressed > 0 {
+		if err := send(s.buildLogNotification(suppressedLogsSummary(level, suppressed))); err != nil {
+			return err
+		}
+	}
+	return send(s.buildLogNotification(notification))
+}
+
+// suppressedLogsSummary reports that suppressed messages of level were
+// dropped.
+func suppressedLogsSummary(level mcp.LoggingLevel, suppressed int) mcp.LoggingMessageNotification {
+	return mcp.NewLoggingMessageNotification(level, "mcp-go", map[string]any{
+		"message":    fmt.Sprintf("%d %s log messages suppressed by rate limit", suppressed, level),
+		"suppressed": suppressed,
+	})
+}
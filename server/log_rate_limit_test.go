@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLogRateLimits(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0",
+		WithLogging(),
+		WithLogRateLimits(map[mcp.LoggingLevel]LogRateLimit{
+			mcp.LoggingLevelDebug: {Rate: 20, Burst: 2},
+			mcp.LoggingLevelError: {Rate: 0, Burst: 0}, // ignored: errors are never dropped
+		}),
+	)
+	session := &sessionTestClientWithLogging{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 100),
+	}
+	session.Initialize()
+	session.SetLogLevel(mcp.LoggingLevelDebug)
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	ctx := server.WithContext(context.Background(), session)
+
+	send := func(level mcp.LoggingLevel, n int) {
+		for range n {
+			require.NoError(t, server.SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(level, "test", "msg")))
+		}
+	}
+	drain := func() []mcp.JSONRPCNotification {
+		var out []mcp.JSONRPCNotification
+		for {
+			select {
+			case n := <-session.notificationChannel:
+				out = append(out, n)
+			default:
+				return out
+			}
+		}
+	}
+
+	send(mcp.LoggingLevelDebug, 5)
+	assert.Len(t, drain(), 2, "debug messages beyond the burst are dropped")
+
+	send(mcp.LoggingLevelError, 20)
+	assert.Len(t, drain(), 20, "error messages are never dropped")
+
+	send(mcp.LoggingLevelInfo, 20)
+	assert.Len(t, drain(), 20, "levels without a budget are not throttled")
+
+	// Once the bucket refills, the summary is sent without waiting for the
+	// next message.
+	var summary mcp.JSONRPCNotification
+	select {
+	case summary = <-session.notificationChannel:
+	case <-time.After(time.Second):
+		t.Fatal("no summary of the suppressed messages")
+	}
+	assert.Equal(t, mcp.LoggingLevelDebug, summary.Params.AdditionalFields["level"])
+	assert.Equal(t, 3, summary.Params.AdditionalFields["data"].(map[string]any)["suppressed"])
+	time.Sleep(10 * time.Millisecond)
+	send(mcp.LoggingLevelDebug, 1)
+	got := drain()
+	require.Len(t, got, 1)
+	assert.Equal(t, "msg", got[0].Params.AdditionalFields["data"])
+
+	// Limiter state is released with the session.
+	server.UnregisterSession(context.Background(), session.sessionID)
+	_, ok := server.logLimiters.Load(session.sessionID)
+	assert.False(t, ok)
+}
+
+func TestLogRateLimits_PerSession(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithLogging(), WithLogRateLimits(nil))
+	for _, id := range []string{"a", "b"} {
+		session := &sessionTestClientWithLogging{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 100),
+		}
+		session.Initialize()
+		session.SetLogLevel(mcp.LoggingLevelDebug)
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		for range 20 {
+			require.NoError(t, server.SendLogMessageToSpecificClient(id, mcp.NewLoggingMessageNotification(mcp.LoggingLevelDebug, "test", "msg")))
+		}
+		assert.Len(t, session.notificationChannel, DefaultLogRateLimits()[mcp.LoggingLevelDebug].Burst)
+	}
+}
@@ -13,10 +13,12 @@ const (
 	logKeyMethod          = "mcp.method"
 	logKeyToolName        = "mcp.tool.name"
 	logKeySessionID       = "mcp.session.id"
+	logKeyRequestID       = "mcp.request.id"
 	logKeyProtocolVersion = "mcp.protocol.version"
 	logKeyDurationSeconds = "duration_s"
 	logKeyOutcome         = "outcome"
 	logKeyError           = "error"
+	logKeyErrorClass      = "error_class"
 
 	logMessageRequest = "mcp.request"
 	logMessageTool    = "mcp.tool"
@@ -29,12 +31,21 @@ const (
 // WithLogger installs a structured logger on the server. The server emits:
 //
 //   - One mcp.request line per dispatched JSON-RPC method, at level INFO,
-//     with attributes mcp.method, mcp.session.id (when set),
-//     mcp.protocol.version (from the Mcp-Protocol-Version header),
-//     duration_s, outcome (ok|error), and error (when set).
+//     with attributes mcp.method, mcp.request.id, mcp.session.id (when
+//     set), mcp.protocol.version (from the Mcp-Protocol-Version header),
+//     duration_s, outcome (ok|error), and error and error_class
+//     (client_error|server_error, see ClassifyCode) when set.
 //   - One mcp.tool line per tool handler invocation, at level INFO, with
 //     attributes mcp.tool.name, duration_s, outcome
 //     (ok|error|error_result), and error (when set).
+//   - Internal errors, such as panics recovered from hooks or responses
+//     that could not be delivered, at level ERROR or WARN. Without a logger
+//     these go to slog.Default.
+//
+// The logger is also the default of the stdio, SSE and streamable HTTP
+// transports, so a stdio server configured with a JSON handler on stderr
+// never writes log output to stdout. Handlers can log with the
+// request-scoped attributes through LoggerFromContext.
 //
 // A nil logger is treated as a no-op (no lines are emitted).
 //
@@ -48,44 +59,82 @@ func WithLogger(logger *slog.Logger) ServerOption {
 		s.requestLogger = logger
 		if logger != nil {
 			s.toolMiddlewareMu.Lock()
-			s.toolHandlerMiddlewares = append(s.toolHandlerMiddlewares, toolLoggingMiddleware(logger))
+			s.toolHandlerMiddlewares = append(s.toolHandlerMiddlewares, toolLoggingMiddleware(logger, s.now))
 			s.toolMiddlewareMu.Unlock()
 		}
 	}
 }
 
-// startMessageLog opens a per-request log scope and returns a finalizer
-// that emits one line keyed by method outcome. When no logger is installed
-// the finalizer is a no-op.
+type loggerContextKey struct{}
+
+// LoggerFromContext returns the logger installed with WithLogger, annotated
+// with the mcp.method, mcp.request.id and mcp.session.id of the request
+// being handled in ctx. It returns slog.Default when the server has no
+// logger.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// logger returns the logger for the server's internal errors.
+func (s *MCPServer) logger() *slog.Logger {
+	if s.requestLogger != nil {
+		return s.requestLogger
+	}
+	return slog.Default()
+}
+
+// sessionLogger returns the logger for internal errors of a session.
+func (s *MCPServer) sessionLogger(sessionID string) *slog.Logger {
+	return s.logger().With(slog.String(logKeySessionID, sessionID))
+}
+
+// startMessageLog opens a per-request log scope, returning a context that
+// carries the request-scoped logger and a finalizer that emits one line
+// keyed by method outcome. When no logger is installed the context is
+// unchanged and the finalizer is a no-op.
 func (s *MCPServer) startMessageLog(
 	ctx context.Context,
 	headers http.Header,
 	method string,
-) func(mcp.JSONRPCMessage) {
+	id any,
+) (context.Context, func(mcp.JSONRPCMessage)) {
 	logger := s.requestLogger
 	if logger == nil {
-		return func(mcp.JSONRPCMessage) {}
+		return ctx, func(mcp.JSONRPCMessage) {}
 	}
-	start := time.Now()
+	start := s.now()
 
-	attrs := []slog.Attr{slog.String(logKeyMethod, method)}
+	scope := []any{slog.String(logKeyMethod, method)}
+	if id != nil {
+		scope = append(scope, slog.Any(logKeyRequestID, id))
+	}
 	if session := ClientSessionFromContext(ctx); session != nil {
 		if id := session.SessionID(); id != "" {
-			attrs = append(attrs, slog.String(logKeySessionID, id))
+			scope = append(scope, slog.String(logKeySessionID, id))
 		}
 	}
+	ctx = context.WithValue(ctx, loggerContextKey{}, logger.With(scope...))
+
+	attrs := make([]slog.Attr, 0, len(scope)+1)
+	for _, a := range scope {
+		attrs = append(attrs, a.(slog.Attr))
+	}
 	if v := headers.Get(HeaderKeyProtocolVersion); v != "" {
 		attrs = append(attrs, slog.String(logKeyProtocolVersion, v))
 	}
 
-	return func(resp mcp.JSONRPCMessage) {
+	return ctx, func(resp mcp.JSONRPCMessage) {
 		final := append(attrs[:len(attrs):len(attrs)],
-			slog.Float64(logKeyDurationSeconds, time.Since(start).Seconds()),
+			slog.Float64(logKeyDurationSeconds, s.now().Sub(start).Seconds()),
 		)
 		if e, ok := resp.(mcp.JSONRPCError); ok {
 			final = append(final,
 				slog.String(logKeyOutcome, logOutcomeError),
 				slog.String(logKeyError, e.Error.Message),
+				slog.String(logKeyErrorClass, string(ClassifyCode(e.Error.Code))),
 			)
 		} else {
 			final = append(final, slog.String(logKeyOutcome, logOutcomeOK))
@@ -94,14 +143,14 @@ func (s *MCPServer) startMessageLog(
 	}
 }
 
-func toolLoggingMiddleware(logger *slog.Logger) ToolHandlerMiddleware {
+func toolLoggingMiddleware(logger *slog.Logger, now func() time.Time) ToolHandlerMiddleware {
 	return func(next ToolHandlerFunc) ToolHandlerFunc {
 		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			start := time.Now()
+			start := now()
 			result, err := next(ctx, request)
 			attrs := []slog.Attr{
 				slog.String(logKeyToolName, request.Params.Name),
-				slog.Float64(logKeyDurationSeconds, time.Since(start).Seconds()),
+				slog.Float64(logKeyDurationSeconds, now().Sub(start).Seconds()),
 			}
 			switch {
 			case err != nil:
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
@@ -77,7 +78,7 @@ func TestWithLogger_RequestLine(t *testing.T) {
 			var buf bytes.Buffer
 			s := NewMCPServer("logger-srv", "1.0", WithLogger(newLoggerCapturingTo(&buf)))
 
-			end := s.startMessageLog(t.Context(), nil, tc.method)
+			_, end := s.startMessageLog(t.Context(), nil, tc.method, 1)
 			end(tc.resp)
 
 			line := findLine(decodeLines(t, &buf), logMessageRequest)
@@ -128,7 +129,7 @@ func TestWithLogger_ToolMiddleware(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			wrapped := toolLoggingMiddleware(newLoggerCapturingTo(&buf))(tc.handler)
+			wrapped := toolLoggingMiddleware(newLoggerCapturingTo(&buf), time.Now)(tc.handler)
 			_, _ = wrapped(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "list_pods"}})
 
 			line := findLine(decodeLines(t, &buf), logMessageTool)
@@ -174,3 +175,41 @@ func TestWithLogger_RegistersToolMiddleware(t *testing.T) {
 	assert.Equal(t, "list_pods", line[logKeyToolName])
 	assert.Equal(t, logOutcomeOK, line[logKeyOutcome])
 }
+
+// TestWithLogger_LoggerFromContext asserts that handlers see a logger
+// carrying the request's method, ID and session ID.
+func TestWithLogger_LoggerFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewMCPServer("logger-srv", "1.0",
+		WithLogger(newLoggerCapturingTo(&buf)),
+		WithToolCapabilities(false),
+	)
+	s.AddTool(mcp.NewTool("log_something"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		LoggerFromContext(ctx).Info("from handler")
+		return &mcp.CallToolResult{}, nil
+	})
+
+	ctx := s.WithContext(t.Context(), &fakeSession{sessionID: "sess-1", initialized: true})
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"log_something"}}`))
+
+	line := findLine(decodeLines(t, &buf), "from handler")
+	require.NotNil(t, line, "handler line missing; buf=%s", buf.String())
+	assert.Equal(t, "tools/call", line[logKeyMethod])
+	assert.EqualValues(t, 7, line[logKeyRequestID])
+	assert.Equal(t, "sess-1", line[logKeySessionID])
+}
+
+func TestLoggerFromContext_Default(t *testing.T) {
+	assert.Same(t, slog.Default(), LoggerFromContext(t.Context()))
+}
+
+func TestWithLogger_StdioErrorLogger(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewMCPServer("logger-srv", "1.0", WithLogger(newLoggerCapturingTo(&buf)))
+
+	NewStdioServer(s).errLogger.Printf("read failed")
+
+	line := findLine(decodeLines(t, &buf), "read failed")
+	require.NotNil(t, line, "stdio error not routed to the logger; buf=%s", buf.String())
+	assert.Equal(t, "ERROR", line["level"])
+}
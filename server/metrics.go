@@ -0,0 +1,388 @@
+package server
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultToolLatencyBuckets are the upper bounds, in seconds, of the tool
+// call latency histogram unless configured with WithMetricsBuckets.
+var DefaultToolLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Metrics collects the server's internal metrics and serves them in the
+// Prometheus text exposition format. Mount it on the metrics endpoint of
+// the process, for example http.Handle("/metrics", s.Metrics()).
+//
+// The exported metrics are:
+//
+//   - mcp_server_requests_total{method,status}: requests handled, by
+//     method and status (ok or error); methods the server does not
+//     implement are counted as method "other"
+//   - mcp_server_tool_call_duration_seconds{tool,status}: histogram of tool
+//     handler latency; status is error for handler errors and error results
+//   - mcp_server_active_sessions: registered client sessions
+//   - mcp_server_sse_connections{transport}: open SSE streams of the SSE
+//     and streamable HTTP transports
+//   - mcp_server_tasks{status}: tasks held by the server, by status
+//   - mcp_server_notifications_dropped_total{method}: notifications dropped
+//     because the session's notification queue was full
+//   - mcp_server_sampling_tokens_total{type}: sampling tokens reported by
+//     clients, by type (input or output)
+//   - mcp_server_sampling_tool_tokens_total{tool,type}: the sampling tokens
+//     of requests made during tool calls, by tool
+//   - mcp_server_sampling_budget_rejections_total: sampling requests
+//     rejected because the session's budget was used up (see
+//     WithSamplingBudget)
+//
+// The requests and tool call metrics have further dimensions for the session
+// labels selected with WithMetricsSessionLabels.
+//
+// A nil *Metrics serves no metrics.
+type Metrics struct {
+	server        *MCPServer
+	buckets       []float64
+	sessionLabels []string // keys of the session labels used as dimensions
+
+	mu                   sync.Mutex
+	requests             map[seriesKey]uint64 // {method, status}
+	toolCalls            map[seriesKey]*histogram
+	sseConnections       map[string]int64
+	notificationsDropped map[string]uint64
+}
+
+// MetricsOption configures the metrics enabled by WithMetrics.
+type MetricsOption func(*Metrics)
+
+// WithMetricsBuckets sets the upper bounds, in seconds, of the tool call
+// latency histogram.
+func WithMetricsBuckets(buckets ...float64) MetricsOption {
+	return func(m *Metrics) {
+		m.buckets = slices.Sorted(slices.Values(buckets))
+	}
+}
+
+// WithMetricsSessionLabels adds the session labels with the given keys, as
+// set by WithSessionLabeler or SetSessionLabels, as dimensions of the
+// requests and tool call metrics. Only the listed keys are used, since every
+// distinct value adds series: pick labels with few values, such as a tenant
+// or a client name. The dimension of a key is named label_ followed by the
+// key with the characters Prometheus does not allow replaced by underscores,
+// so client.name becomes label_client_name. Sessions without the label
+// report an empty value.
+func WithMetricsSessionLabels(keys ...string) MetricsOption {
+	return func(m *Metrics) {
+		m.sessionLabels = slices.Clone(keys)
+	}
+}
+
+// WithMetrics enables the collection of the metrics served by Metrics.
+func WithMetrics(opts ...MetricsOption) ServerOption {
+	return func(s *MCPServer) {
+		m := &Metrics{
+			server:               s,
+			buckets:              DefaultToolLatencyBuckets,
+			requests:             make(map[seriesKey]uint64),
+			toolCalls:            make(map[seriesKey]*histogram),
+			sseConnections:       make(map[string]int64),
+			notificationsDropped: make(map[string]uint64),
+		}
+		for _, opt := range opts {
+			opt(m)
+		}
+		s.metrics = m
+		s.requestMiddlewares = append(s.requestMiddlewares, m.requestMiddleware)
+		s.toolMiddlewareMu.Lock()
+		s.toolHandlerMiddlewares = append(s.toolHandlerMiddlewares, m.toolMiddleware)
+		s.toolMiddlewareMu.Unlock()
+	}
+}
+
+// Metrics returns the server's metrics collector, or nil unless the server
+// was created with WithMetrics.
+func (s *MCPServer) Metrics() *Metrics {
+	return s.metrics
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.Write(w)
+}
+
+// Write writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) Write(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	s := m.server
+
+	m.mu.Lock()
+	writeHeader(bw, "mcp_server_requests_total", "counter", "Requests handled by the server, by method and status.")
+	for _, key := range sortedSeries(m.requests) {
+		writeSample(bw, "mcp_server_requests_total", labels(m.labelPairs(key, "method", "status")...), float64(m.requests[key]))
+	}
+
+	writeHeader(bw, "mcp_server_tool_call_duration_seconds", "histogram", "Latency of tool handlers.")
+	for _, key := range sortedSeries(m.toolCalls) {
+		m.toolCalls[key].write(bw, "mcp_server_tool_call_duration_seconds", m.buckets, m.labelPairs(key, "tool", "status")...)
+	}
+
+	writeHeader(bw, "mcp_server_sse_connections", "gauge", "Open SSE streams, by transport.")
+	for _, transport := range slices.Sorted(maps.Keys(m.sseConnections)) {
+		writeSample(bw, "mcp_server_sse_connections", labels("transport", transport), float64(m.sseConnections[transport]))
+	}
+
+	writeHeader(bw, "mcp_server_notifications_dropped_total", "counter", "Notifications dropped because the session's queue was full, by method.")
+	for _, method := range slices.Sorted(maps.Keys(m.notificationsDropped)) {
+		writeSample(bw, "mcp_server_notifications_dropped_total", labels("method", method), float64(m.notificationsDropped[method]))
+	}
+	m.mu.Unlock()
+
+	sessions := 0
+	s.sessions.Range(func(_, _ any) bool {
+		sessions++
+		return true
+	})
+	writeHeader(bw, "mcp_server_active_sessions", "gauge", "Registered client sessions.")
+	writeSample(bw, "mcp_server_active_sessions", "", float64(sessions))
+
+	tasks := make(map[mcp.TaskStatus]int)
+	s.tasksMu.RLock()
+	for _, entry := range s.tasks {
+		tasks[entry.task.Status]++
+	}
+	s.tasksMu.RUnlock()
+	writeHeader(bw, "mcp_server_tasks", "gauge", "Tasks held by the server, by status.")
+	for _, status := range slices.Sorted(maps.Keys(tasks)) {
+		writeSample(bw, "mcp_server_tasks", labels("status", string(status)), float64(tasks[status]))
+	}
+
+	usage, byTool := s.samplingTotals()
+	writeHeader(bw, "mcp_server_sampling_tokens_total", "counter", "Sampling tokens reported by clients, by type.")
+	writeUsage(bw, "mcp_server_sampling_tokens_total", usage)
+	writeHeader(bw, "mcp_server_sampling_tool_tokens_total", "counter", "Sampling tokens of requests made during tool calls, by tool and type.")
+	for _, tool := range slices.Sorted(maps.Keys(byTool)) {
+		writeUsage(bw, "mcp_server_sampling_tool_tokens_total", byTool[tool], "tool", tool)
+	}
+	writeHeader(bw, "mcp_server_sampling_budget_rejections_total", "counter", "Sampling requests rejected because the session's token budget was used up.")
+	writeSample(bw, "mcp_server_sampling_budget_rejections_total", "", float64(s.samplingRejections()))
+
+	return bw.Flush()
+}
+
+// requestMiddleware counts requests by method and status.
+func (m *Metrics) requestMiddleware(next RequestHandler) RequestHandler {
+	return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+		resp := next(ctx, request)
+		status := "ok"
+		if _, isError := resp.(mcp.JSONRPCError); isError {
+			status = "error"
+		}
+		key := m.seriesKey(ctx, methodLabel(request.Method), status)
+		m.mu.Lock()
+		m.requests[key]++
+		m.mu.Unlock()
+		return resp
+	}
+}
+
+// otherMethod labels the requests for methods the server does not
+// implement.
+const otherMethod = "other"
+
+// methodLabel returns the label under which requests for method are
+// counted. The method is chosen by the client, so unknown methods share a
+// single label rather than each adding a series.
+func methodLabel(method string) string {
+	if knownMethods[method] {
+		return method
+	}
+	return otherMethod
+}
+
+// toolMiddleware observes the latency of tool handlers.
+func (m *Metrics) toolMiddleware(next ToolHandlerFunc) ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := m.server.now()
+		result, err := next(ctx, request)
+		elapsed := m.server.now().Sub(start).Seconds()
+
+		status := "ok"
+		if err != nil || result != nil && result.IsError {
+			status = "error"
+		}
+		key := m.seriesKey(ctx, request.Params.Name, status)
+		m.mu.Lock()
+		h, ok := m.toolCalls[key]
+		if !ok {
+			h = &histogram{counts: make([]uint64, len(m.buckets))}
+			m.toolCalls[key] = h
+		}
+		h.observe(m.buckets, elapsed)
+		m.mu.Unlock()
+		return result, err
+	}
+}
+
+// seriesKey identifies a series of the requests or tool call metrics.
+type seriesKey struct {
+	first, second string
+	// session holds the values of the selected session labels, separated
+	// by zero bytes.
+	session string
+}
+
+// seriesKey returns the key of the series for first and second and the
+// selected labels of the session in ctx.
+func (m *Metrics) seriesKey(ctx context.Context, first, second string) seriesKey {
+	key := seriesKey{first: first, second: second}
+	if len(m.sessionLabels) == 0 {
+		return key
+	}
+	sessionLabels := m.server.SessionLabels(getSessionID(ctx))
+	values := make([]string, len(m.sessionLabels))
+	for i, name := range m.sessionLabels {
+		values[i] = sessionLabels[name]
+	}
+	key.session = strings.Join(values, "\x00")
+	return key
+}
+
+// labelPairs returns the label name/value pairs of the series key, named
+// first and second followed by the selected session labels.
+func (m *Metrics) labelPairs(key seriesKey, first, second string) []string {
+	pairs := []string{first, key.first, second, key.second}
+	if len(m.sessionLabels) == 0 {
+		return pairs
+	}
+	values := strings.Split(key.session, "\x00")
+	for i, name := range m.sessionLabels {
+		pairs = append(pairs, sessionLabelName(name), values[i])
+	}
+	return pairs
+}
+
+// sessionLabelName returns the name of the dimension for the session label
+// key.
+func sessionLabelName(key string) string {
+	return "label_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// trackSSEConnection counts an open SSE stream of transport and returns
+// the function to call when it closes. Safe to call on a nil receiver.
+func (m *Metrics) trackSSEConnection(transport string) func() {
+	if m == nil {
+		return func() {}
+	}
+	m.mu.Lock()
+	m.sseConnections[transport]++
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		m.sseConnections[transport]--
+		m.mu.Unlock()
+	}
+}
+
+// notificationDropped counts a notification dropped because the session's
+// queue was full. Safe to call on a nil receiver.
+func (m *Metrics) notificationDropped(method string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.notificationsDropped[method]++
+	m.mu.Unlock()
+}
+
+// histogram holds the per-bucket counts of a Prometheus histogram.
+type histogram struct {
+	counts []uint64 // non-cumulative, one per bucket
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	if i, _ := slices.BinarySearch(buckets, v); i < len(buckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+func (h *histogram) write(w *bufio.Writer, name string, buckets []float64, labelPairs ...string) {
+	base := labels(labelPairs...)
+	var cumulative uint64
+	for i, bound := range buckets {
+		cumulative += h.counts[i]
+		writeSample(w, name+"_bucket", labels(append(labelPairs, "le", formatFloat(bound))...), float64(cumulative))
+	}
+	writeSample(w, name+"_bucket", labels(append(labelPairs, "le", "+Inf")...), float64(h.count))
+	writeSample(w, name+"_sum", base, h.sum)
+	writeSample(w, name+"_count", base, float64(h.count))
+}
+
+// writeUsage writes the input and output tokens of usage as samples of
+// name.
+func writeUsage(w *bufio.Writer, name string, usage mcp.SamplingUsage, labelPairs ...string) {
+	writeSample(w, name, labels(append(slices.Clip(labelPairs), "type", "input")...), float64(usage.InputTokens))
+	writeSample(w, name, labels(append(slices.Clip(labelPairs), "type", "output")...), float64(usage.OutputTokens))
+}
+
+func writeHeader(w *bufio.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeSample(w *bufio.Writer, name, labels string, v float64) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(v))
+}
+
+// labels formats name/value pairs as a Prometheus label set.
+func labels(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedSeries returns the keys of m in order, so scrapes are stable.
+func sortedSeries[V any](m map[seriesKey]V) []seriesKey {
+	return slices.SortedFunc(maps.Keys(m), func(a, b seriesKey) int {
+		return cmp.Or(strings.Compare(a.first, b.first), strings.Compare(a.second, b.second), strings.Compare(a.session, b.session))
+	})
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_Metrics(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithMetrics(WithMetricsBuckets(1, 0.5)))
+	s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	s.AddTool(mcp.NewTool("broken"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	})
+
+	// A session whose notification queue is already full.
+	notifications := make(chan mcp.JSONRPCNotification, 1)
+	notifications <- mcp.JSONRPCNotification{}
+	session := &fakeSession{sessionID: "metrics", notificationChannel: notifications, initialized: true}
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+	ctx := s.WithContext(t.Context(), session)
+
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo"}}`))
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"broken"}}`))
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"missing"}}`))
+	for i := range 100 {
+		s.HandleMessage(ctx, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"junk/%d"}`, 5+i, i)))
+	}
+	assert.ErrorIs(t, s.SendNotificationToSpecificClient("metrics", "notifications/message", nil), ErrNotificationChannelBlocked)
+
+	rec := httptest.NewRecorder()
+	s.Metrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+
+	for _, line := range []string{
+		"# TYPE mcp_server_requests_total counter",
+		`mcp_server_requests_total{method="ping",status="ok"} 1`,
+		`mcp_server_requests_total{method="tools/call",status="ok"} 1`,
+		`mcp_server_requests_total{method="tools/call",status="error"} 2`,
+		`mcp_server_requests_total{method="other",status="error"} 100`,
+		"# TYPE mcp_server_tool_call_duration_seconds histogram",
+		`mcp_server_tool_call_duration_seconds_bucket{tool="echo",status="ok",le="0.5"} 1`,
+		`mcp_server_tool_call_duration_seconds_bucket{tool="echo",status="ok",le="1"} 1`,
+		`mcp_server_tool_call_duration_seconds_bucket{tool="echo",status="ok",le="+Inf"} 1`,
+		`mcp_server_tool_call_duration_seconds_count{tool="echo",status="ok"} 1`,
+		`mcp_server_tool_call_duration_seconds_count{tool="broken",status="error"} 1`,
+		"mcp_server_active_sessions 1",
+		`mcp_server_notifications_dropped_total{method="notifications/message"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, `tool="missing"`, "unknown tools never reach a handler")
+	assert.NotContains(t, body, "junk/", "unknown methods share the other label")
+
+	t.Run("sse connections", func(t *testing.T) {
+		ts := NewTestServer(s)
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/sse", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var out strings.Builder
+		require.NoError(t, s.Metrics().Write(&out))
+		assert.Contains(t, out.String(), `mcp_server_sse_connections{transport="sse"} 1`)
+		assert.Contains(t, out.String(), "mcp_server_active_sessions 2")
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			out.Reset()
+			require.NoError(t, s.Metrics().Write(&out))
+			return strings.Contains(out.String(), `mcp_server_sse_connections{transport="sse"} 0`)
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestMCPServer_Metrics_SessionLabels(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithMetrics(
+		WithMetricsBuckets(1),
+		WithMetricsSessionLabels("tenant", "client.name"),
+	))
+	s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(sessionID string, labels map[string]string) {
+		session := &fakeSession{sessionID: sessionID, notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+		require.NoError(t, s.RegisterSession(t.Context(), session))
+		if labels != nil {
+			require.NoError(t, s.SetSessionLabels(sessionID, labels))
+		}
+		ctx := s.WithContext(t.Context(), session)
+		s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`))
+	}
+	call("a", map[string]string{"tenant": "acme", "client.name": "cli", "user": "ada"})
+	call("b", map[string]string{"tenant": "acme", "client.name": "cli", "user": "bob"})
+	call("c", nil)
+
+	var out strings.Builder
+	require.NoError(t, s.Metrics().Write(&out))
+	body := out.String()
+	for _, line := range []string{
+		`mcp_server_requests_total{method="tools/call",status="ok",label_tenant="acme",label_client_name="cli"} 2`,
+		`mcp_server_requests_total{method="tools/call",status="ok",label_tenant="",label_client_name=""} 1`,
+		`mcp_server_tool_call_duration_seconds_bucket{tool="echo",status="ok",label_tenant="acme",label_client_name="cli",le="+Inf"} 2`,
+		`mcp_server_tool_call_duration_seconds_count{tool="echo",status="ok",label_tenant="acme",label_client_name="cli"} 2`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, "ada", "labels outside the allow-list are not dimensions")
+}
+
+func TestMCPServer_Metrics_Disabled(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	assert.Nil(t, s.Metrics())
+
+	var out strings.Builder
+	require.NoError(t, s.Metrics().Write(&out))
+	assert.Empty(t, out.String())
+}
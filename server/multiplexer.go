@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Multiplexer hosts several MCP servers on one HTTP listener, each under
+// its own path prefix:
+//
+//	mux := server.NewMultiplexer().
+//	    Mount("/github", githubServer).
+//	    Mount("/jira", jiraServer)
+//	http.ListenAndServe(":8080", mux)
+//
+// Each mount is served by its own StreamableHTTPServer, so the sessions,
+// GET streams and DELETE session terminations of a path reach the server
+// mounted there. A Multiplexer is safe for concurrent use.
+type Multiplexer struct {
+	mu     sync.RWMutex
+	mounts []*mount // longest prefix first
+}
+
+type mount struct {
+	prefix string
+	server *StreamableHTTPServer
+}
+
+// NewMultiplexer creates an empty Multiplexer.
+func NewMultiplexer() *Multiplexer {
+	return &Multiplexer{}
+}
+
+// Mount serves s under prefix, configured with opts, and returns m for
+// chaining. The prefix is stripped before the request reaches the
+// StreamableHTTPServer, so the server's own paths, such as the health
+// endpoints of WithHealthEndpoints, are relative to it: with
+// Mount("/github", s, WithHealthEndpoints()) the liveness endpoint is
+// /github/healthz. WithEndpointPath has no effect on a mounted server.
+//
+// Requests go to the mount with the longest matching prefix. Like
+// http.ServeMux.Handle, Mount panics if prefix is empty or already
+// mounted.
+func (m *Multiplexer) Mount(prefix string, s *MCPServer, opts ...StreamableHTTPOption) *Multiplexer {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		panic("server: Multiplexer.Mount requires a non-root path prefix")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.mounts {
+		if existing.prefix == prefix {
+			panic("server: multiple mounts for " + prefix)
+		}
+	}
+	// ServeHTTP reads m.mounts without holding the lock while it serves, so
+	// it is replaced rather than modified in place.
+	mounts := append(slices.Clone(m.mounts), &mount{prefix: prefix, server: NewStreamableHTTPServer(s, opts...)})
+	slices.SortStableFunc(mounts, func(a, b *mount) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+	m.mounts = mounts
+	return m
+}
+
+// Server returns the StreamableHTTPServer mounted at prefix, or nil if
+// there is none.
+func (m *Multiplexer) Server(prefix string) *StreamableHTTPServer {
+	prefix = "/" + strings.Trim(prefix, "/")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, mt := range m.mounts {
+		if mt.prefix == prefix {
+			return mt.server
+		}
+	}
+	return nil
+}
+
+// ServeHTTP routes r to the server mounted at the longest prefix of its
+// path, answering 404 Not Found when no prefix matches. A request carrying
+// the Mcp-Session-Id of a session that belongs to another mount is answered
+// 404 Not Found as well, as for an unknown session, rather than reaching a
+// server that never initialized it.
+func (m *Multiplexer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	mounts := m.mounts
+	m.mu.RUnlock()
+
+	var target *mount
+	for _, mt := range mounts {
+		if r.URL.Path == mt.prefix || strings.HasPrefix(r.URL.Path, mt.prefix+"/") {
+			target = mt
+			break
+		}
+	}
+	if target == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if sessionID := r.Header.Get(HeaderKeySessionID); sessionID != "" && !target.server.hasSession(sessionID) {
+		for _, mt := range mounts {
+			if mt != target && mt.server.hasSession(sessionID) {
+				http.Error(w, "Session not found", http.StatusNotFound)
+				return
+			}
+		}
+	}
+
+	http.StripPrefix(target.prefix, target.server).ServeHTTP(w, r)
+}
+
+// Shutdown shuts down every mounted server. It does not close the HTTP
+// listener serving the Multiplexer, which belongs to the caller.
+func (m *Multiplexer) Shutdown(ctx context.Context) error {
+	m.mu.RLock()
+	mounts := m.mounts
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, mt := range mounts {
+		if err := mt.server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
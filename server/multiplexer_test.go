@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newMountedServer(tool string) *MCPServer {
+	s := NewMCPServer(tool, "1.0.0", WithToolCapabilities(false))
+	s.AddTool(mcp.NewTool(tool), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(tool), nil
+	})
+	return s
+}
+
+func postMCP(t *testing.T, url, sessionID string, message any) *http.Response {
+	t.Helper()
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(HeaderKeySessionID, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestMultiplexer(t *testing.T) {
+	mux := NewMultiplexer().
+		Mount("/github", newMountedServer("search_issues"), WithHealthEndpoints()).
+		Mount("/jira/", newMountedServer("create_ticket"))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	sessions := map[string]string{}
+	for _, prefix := range []string{"/github", "/jira"} {
+		resp := postMCP(t, ts.URL+prefix, "", initRequest)
+		require.Equal(t, http.StatusOK, resp.StatusCode, prefix)
+		sessions[prefix] = resp.Header.Get(HeaderKeySessionID)
+		require.NotEmpty(t, sessions[prefix])
+	}
+
+	listTools := map[string]any{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}
+	for prefix, tool := range map[string]string{"/github": "search_issues", "/jira": "create_ticket"} {
+		resp := postMCP(t, ts.URL+prefix, sessions[prefix], listTools)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Result mcp.ListToolsResult `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Result.Tools, 1)
+		assert.Equal(t, tool, result.Result.Tools[0].Name)
+	}
+
+	// A session belongs to the mount that initialized it.
+	resp := postMCP(t, ts.URL+"/jira", sessions["/github"], listTools)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Paths below a prefix reach the mounted server with the prefix
+	// stripped.
+	resp, err := http.Get(ts.URL + "/github/healthz")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, path := range []string{"/", "/gitlab", "/githubx"} {
+		resp := postMCP(t, ts.URL+path, "", initRequest)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL+"/github", nil)
+	require.NoError(t, err)
+	req.Header.Set(HeaderKeySessionID, sessions["/github"])
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.False(t, mux.Server("/github").hasSession(sessions["/github"]))
+	assert.True(t, mux.Server("/jira").hasSession(sessions["/jira"]))
+
+	require.NoError(t, mux.Shutdown(context.Background()))
+}
+
+func TestMultiplexer_LongestPrefix(t *testing.T) {
+	mux := NewMultiplexer().
+		Mount("/tools", newMountedServer("outer")).
+		Mount("/tools/inner", newMountedServer("inner"))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for path, name := range map[string]string{"/tools": "outer", "/tools/inner": "inner", "/tools/other": "outer"} {
+		resp := postMCP(t, ts.URL+path, "", initRequest)
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+		var result struct {
+			Result mcp.InitializeResult `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, name, result.Result.ServerInfo.Name, path)
+	}
+}
+
+func TestMultiplexer_MountPanics(t *testing.T) {
+	assert.Panics(t, func() { NewMultiplexer().Mount("/", newMountedServer("a")) })
+	assert.Panics(t, func() {
+		NewMultiplexer().Mount("/a", newMountedServer("a")).Mount("a/", newMountedServer("b"))
+	})
+	assert.Nil(t, NewMultiplexer().Server("/a"))
+}
@@ -4,6 +4,7 @@ package server
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -65,22 +66,40 @@ func (v *outputSchemaValidator) validate(tool mcp.Tool, result *mcp.CallToolResu
 	return v.validateStructured(tool, result.StructuredContent, result.IsError)
 }
 
-// validateCreateTaskResult is the *mcp.CreateTaskResult counterpart to
-// validate. Task-augmented tool calls produce a CreateTaskResult whose
-// StructuredContent carries the structured payload that will eventually be
-// surfaced to the client via tasks/result; validating it before the result
-// is persisted to the task entry keeps the task path consistent with the
-// synchronous path's WithOutputSchemaValidation contract.
-func (v *outputSchemaValidator) validateCreateTaskResult(tool mcp.Tool, result *mcp.CreateTaskResult) (bool, error) {
-	if v == nil || result == nil {
-		return false, nil
+// ErrTaskResultNotStructured is reported when a task-augmented call of a tool
+// that declares an output schema completes without StructuredContent.
+var ErrTaskResultNotStructured = errors.New("tool declares an output schema but the task result has no structured content")
+
+// validateTaskResult enforces the tool's declared output schema on a task
+// result before it is stored, unless disabled with WithTaskResultValidation.
+// Unlike synchronous results, task results are checked even without
+// WithOutputSchemaValidation: clients decode them later with
+// mcp.ParseTaskResultAs, long after the handler that produced them has
+// returned, so a mismatch must surface as a tool error rather than a decode
+// failure on the client.
+func (s *MCPServer) validateTaskResult(tool mcp.Tool, structured any, isError bool) error {
+	if isError || s.skipTaskResultValidation {
+		return nil
 	}
-	return v.validateStructured(tool, result.StructuredContent, result.IsError)
+	if _, ok := outputSchemaJSONFor(tool); !ok {
+		return nil
+	}
+	if structured == nil {
+		return ErrTaskResultNotStructured
+	}
+	v := s.outputValidator
+	if v == nil {
+		// Task completions are rare enough to compile the schema each time
+		// rather than keep a cache that tool updates would need to evict.
+		v = newOutputSchemaValidator()
+	}
+	_, err := v.validateStructured(tool, structured, false)
+	return err
 }
 
 // validateStructured runs the actual schema check against the structured
-// content and IsError flag extracted from a tool result. Both *CallToolResult
-// and *CreateTaskResult delegate here so the skip rules and error formatting
+// content and IsError flag extracted from a tool result. Synchronous results
+// and task results delegate here so the skip rules and error formatting
 // stay in one place.
 func (v *outputSchemaValidator) validateStructured(tool mcp.Tool, structured any, isError bool) (bool, error) {
 	// Error results carry diagnostic content that need not match the
@@ -450,3 +450,81 @@ func TestOutputSchemaValidation_TaskAugmented_HappyPath(t *testing.T) {
 	assert.False(t, taskResult.IsError)
 	require.NotNil(t, taskResult.StructuredContent)
 }
+
+func TestOutputSchemaValidation_SchemaMap(t *testing.T) {
+	srv := NewMCPServer("test", "1.0.0", WithOutputSchemaValidation())
+	var structured any
+	srv.AddTool(mcp.NewTool("sum", mcp.WithOutputSchemaMap(map[string]any{
+		"properties": map[string]any{"total": map[string]any{"type": "number"}},
+		"required":   []string{"total"},
+	})), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultStructuredOnly(structured), nil
+	})
+
+	structured = map[string]any{"total": 3}
+	requireToolSuccess(t, callTool(t, srv, "sum", nil))
+
+	structured = map[string]any{"total": "three"}
+	requireToolErrorContaining(t, callTool(t, srv, "sum", nil), "/total")
+}
+
+// TestTaskResultValidation confirms task results are checked against a
+// declared output schema by default, even without WithOutputSchemaValidation,
+// since clients decode them later with mcp.ParseTaskResultAs.
+func TestTaskResultValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		disabled   bool
+		structured any
+		wantError  string
+	}{
+		{name: "conforming", structured: weatherOutput{Temperature: 21.5, Unit: "C"}},
+		{name: "wrong type", structured: map[string]any{"temperature": "hot", "unit": "C"}, wantError: "temperature"},
+		{name: "missing", structured: nil, wantError: ErrTaskResultNotStructured.Error()},
+		{name: "disabled", disabled: true, structured: map[string]any{"temperature": "hot", "unit": "C"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewMCPServer("test", "1.0.0",
+				WithTaskCapabilities(true, true, true),
+				WithTaskResultValidation(!tt.disabled),
+			)
+			tool := mcp.NewTool("get_weather",
+				mcp.WithOutputSchema[weatherOutput](),
+				mcp.WithTaskSupport(mcp.TaskSupportOptional),
+			)
+			srv.AddTool(tool, func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{StructuredContent: tt.structured}, nil
+			})
+
+			ctx := t.Context()
+			createRes, reqErr := srv.handleToolCall(ctx, 1, mcp.CallToolRequest{
+				Params: mcp.CallToolParams{Name: "get_weather", Task: &mcp.TaskParams{}},
+			})
+			require.Nil(t, reqErr)
+			taskID := createRes.(*mcp.CreateTaskResult).Task.TaskId
+			waitForTaskTerminal(t, srv, ctx, taskID)
+
+			taskResult, resErr := srv.handleTaskResult(ctx, 2, mcp.TaskResultRequest{
+				Params: mcp.TaskResultParams{TaskId: taskID},
+			})
+			require.Nil(t, resErr)
+
+			if tt.disabled {
+				assert.False(t, taskResult.IsError)
+				assert.Equal(t, tt.structured, taskResult.StructuredContent)
+				return
+			}
+			if tt.wantError == "" {
+				got, err := mcp.ParseTaskResultAs[weatherOutput](taskResult)
+				require.NoError(t, err)
+				assert.Equal(t, tt.structured, got)
+				return
+			}
+			assert.True(t, taskResult.IsError)
+			_, err := mcp.ParseTaskResultAs[weatherOutput](taskResult)
+			assert.ErrorContains(t, err, tt.wantError)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithProgressRateLimit throttles notifications/progress to at most rate
+// notifications per second for each request and progress token, so that a
+// handler reporting progress in a tight loop cannot flood the client's
+// stream. Progress sent outside of a request is throttled per session, and
+// not at all for sessions without an ID, such as those of stateless
+// transports, which cannot be told apart.
+//
+// Throttling never loses the latest value: an update arriving too early
+// replaces any update still waiting and is sent once the interval has
+// passed, and updates whose progress reaches their total are sent at once.
+// When the request that carried the progress token completes, its waiting
+// update is sent before the response.
+func WithProgressRateLimit(rate float64) ServerOption {
+	return func(s *MCPServer) {
+		if rate <= 0 {
+			return
+		}
+		t := &progressThrottle{
+			interval: time.Duration(float64(time.Second) / rate),
+			now:      s.now,
+			streams:  make(map[progressKey]*progressStream),
+		}
+		s.progressThrottle = t
+		s.requestMiddlewares = append(s.requestMiddlewares, t.middleware)
+	}
+}
+
+// progressThrottle holds the state of each progress stream, by request or
+// session and progress token.
+type progressThrottle struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	streams map[progressKey]*progressStream
+	// swept is when idle streams were last evicted.
+	swept time.Time
+}
+
+type progressKey struct {
+	session string
+	// request identifies the request the progress is reported for, nil for
+	// progress sent outside of a request.
+	request *progressRequest
+	// token is the progress token formatted with fmt.Sprint, so that
+	// numbers compare equal whether decoded as int or float64.
+	token string
+}
+
+// progressRequest identifies a request in its context, so that progress
+// streams of concurrent requests never mix, even when their sessions share
+// an empty ID.
+type progressRequest struct{}
+
+type progressRequestKey struct{}
+
+type progressStream struct {
+	last    time.Time
+	pending *mcp.JSONRPCNotification
+	send    func(mcp.JSONRPCNotification) error
+	timer   *time.Timer
+}
+
+// isProgressFinal reports whether params report progress that reached its
+// total.
+func isProgressFinal(params map[string]any) bool {
+	progress, ok := toFloat(params["progress"])
+	if !ok {
+		return false
+	}
+	total, ok := toFloat(params["total"])
+	return ok && progress >= total
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// send sends notification through send right away if the stream's
+// interval has passed or the progress is final, and otherwise holds it
+// until then. Notifications without a progress token, or that can be told
+// apart neither by request nor by session, are not throttled.
+func (t *progressThrottle) send(ctx context.Context, sessionID string, notification mcp.JSONRPCNotification, send func(mcp.JSONRPCNotification) error) error {
+	params := notification.Params.AdditionalFields
+	token, ok := params["progressToken"]
+	if !ok || token == nil {
+		return send(notification)
+	}
+	request, _ := ctx.Value(progressRequestKey{}).(*progressRequest)
+	if request == nil && sessionID == "" {
+		return send(notification)
+	}
+	key := progressKey{session: sessionID, request: request, token: fmt.Sprint(token)}
+
+	t.mu.Lock()
+	now := t.now()
+	t.sweep(now)
+	stream, ok := t.streams[key]
+	if !ok {
+		stream = &progressStream{}
+		t.streams[key] = stream
+	}
+	if wait := t.interval - now.Sub(stream.last); wait > 0 && !isProgressFinal(params) {
+		stream.pending = &notification
+		stream.send = send
+		if stream.timer == nil {
+			stream.timer = time.AfterFunc(wait, func() { t.flush(key) })
+		}
+		t.mu.Unlock()
+		return nil
+	}
+	// This update supersedes any waiting one.
+	stream.pending = nil
+	if stream.timer != nil {
+		stream.timer.Stop()
+		stream.timer = nil
+	}
+	stream.last = now
+	t.mu.Unlock()
+	return send(notification)
+}
+
+// flush sends the waiting update of a stream, if any.
+func (t *progressThrottle) flush(key progressKey) {
+	t.mu.Lock()
+	stream, ok := t.streams[key]
+	if !ok || stream.pending == nil {
+		if ok {
+			stream.timer = nil
+		}
+		t.mu.Unlock()
+		return
+	}
+	notification, send := *stream.pending, stream.send
+	stream.pending, stream.send = nil, nil
+	if stream.timer != nil {
+		stream.timer.Stop()
+		stream.timer = nil
+	}
+	stream.last = t.now()
+	t.mu.Unlock()
+	_ = send(notification)
+}
+
+// sweep evicts the streams that have nothing waiting and whose interval has
+// passed, such as those of tasks that keep reporting progress after their
+// request completed, at most once per interval. The caller holds t.mu.
+func (t *progressThrottle) sweep(now time.Time) {
+	if now.Sub(t.swept) < t.interval {
+		return
+	}
+	t.swept = now
+	for key, stream := range t.streams {
+		if stream.pending == nil && now.Sub(stream.last) >= t.interval {
+			delete(t.streams, key)
+		}
+	}
+}
+
+// finish flushes and forgets a stream once its request has completed.
+func (t *progressThrottle) finish(key progressKey) {
+	t.flush(key)
+	t.mu.Lock()
+	if stream, ok := t.streams[key]; ok && stream.pending == nil {
+		delete(t.streams, key)
+	}
+	t.mu.Unlock()
+}
+
+// forget drops the streams of a session that is gone, discarding their
+// waiting updates.
+func (t *progressThrottle) forget(sessionID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, stream := range t.streams {
+		if key.session == sessionID {
+			if stream.timer != nil {
+				stream.timer.Stop()
+			}
+			delete(t.streams, key)
+		}
+	}
+}
+
+func (t *progressThrottle) middleware(next RequestHandler) RequestHandler {
+	return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+		token := requestProgressToken(request)
+		if token == nil {
+			return next(ctx, request)
+		}
+		scope := &progressRequest{}
+		response := next(context.WithValue(ctx, progressRequestKey{}, scope), request)
+		var sessionID string
+		if session := ClientSessionFromContext(ctx); session != nil {
+			sessionID = session.SessionID()
+		}
+		t.finish(progressKey{session: sessionID, request: scope, token: fmt.Sprint(token)})
+		return response
+	}
+}
+
+// requestProgressToken returns the progress token in the _meta of a
+// request's params, or nil.
+func requestProgressToken(request *mcp.JSONRPCRequest) mcp.ProgressToken {
+	var params struct {
+		Meta struct {
+			ProgressToken mcp.ProgressToken `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if decodeRequestParams(request, &params) != nil {
+		return nil
+	}
+	return params.Meta.ProgressToken
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// progressValues drains the progress values sent to ch until it stays
+// empty for quiet.
+func progressValues(ch chan mcp.JSONRPCNotification, quiet time.Duration) []any {
+	var values []any
+	for {
+		select {
+		case n := <-ch:
+			if n.Method == string(mcp.MethodNotificationProgress) {
+				values = append(values, n.Params.AdditionalFields["progress"])
+			}
+		case <-time.After(quiet):
+			return values
+		}
+	}
+}
+
+func TestWithProgressRateLimit_KeepsLastValue(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithProgressRateLimit(10))
+	session := &fakeSession{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 1000), initialized: true}
+	ctx := s.WithContext(t.Context(), session)
+
+	for i := 1; i <= 500; i++ {
+		require.NoError(t, s.SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+			"progressToken": "tok",
+			"progress":      i,
+		}))
+	}
+
+	values := progressValues(session.notificationChannel, 300*time.Millisecond)
+	require.Len(t, values, 2)
+	assert.Equal(t, 1, values[0])
+	assert.Equal(t, 500, values[1])
+}
+
+func TestWithProgressRateLimit_FinalValueImmediately(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithProgressRateLimit(0.1))
+	session := &fakeSession{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	ctx := s.WithContext(t.Context(), session)
+
+	for _, progress := range []int{1, 2, 10} {
+		require.NoError(t, s.SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+			"progressToken": 7,
+			"progress":      progress,
+			"total":         10,
+		}))
+	}
+	assert.Equal(t, []any{1, 10}, progressValues(session.notificationChannel, 50*time.Millisecond))
+}
+
+func TestWithProgressRateLimit_TokensAreIndependent(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithProgressRateLimit(0.1))
+	session := &fakeSession{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	ctx := s.WithContext(t.Context(), session)
+
+	for _, token := range []string{"a", "b"} {
+		require.NoError(t, s.SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+			"progressToken": token,
+			"progress":      1,
+		}))
+	}
+	assert.Len(t, progressValues(session.notificationChannel, 50*time.Millisecond), 2)
+}
+
+func TestWithProgressRateLimit_FlushesWhenRequestCompletes(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithProgressRateLimit(0.1))
+	s.AddTool(mcp.NewTool("work"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for i := 1; i <= 100; i++ {
+			_ = ServerFromContext(ctx).SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+				"progressToken": req.Params.Meta.ProgressToken,
+				"progress":      i,
+			})
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+	session := &fakeSession{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+	ctx := s.WithContext(t.Context(), session)
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":  "work",
+			"_meta": map[string]any{"progressToken": 42},
+		},
+	})
+	require.NoError(t, err)
+	response := s.HandleMessage(ctx, message)
+	require.IsType(t, mcp.JSONRPCResponse{}, response)
+
+	// The last update is delivered by the time the response is returned.
+	assert.Equal(t, []any{1, 100}, progressValues(session.notificationChannel, 10*time.Millisecond))
+}
+
+func TestWithProgressRateLimit_StatelessRequestsAreIndependent(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithProgressRateLimit(0.1))
+	s.AddTool(mcp.NewTool("work"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for i := 1; i <= 10; i++ {
+			_ = ServerFromContext(ctx).SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+				"progressToken": req.Params.Meta.ProgressToken,
+				"progress":      i,
+			})
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":  "work",
+			"_meta": map[string]any{"progressToken": 1},
+		},
+	})
+	require.NoError(t, err)
+
+	// Two clients of a stateless transport: both sessions have an empty ID
+	// and use the same progress token.
+	var sessions []*fakeSession
+	for range 2 {
+		session := &fakeSession{notificationChannel: make(chan mcp.JSONRPCNotification, 20), initialized: true}
+		sessions = append(sessions, session)
+		s.HandleMessage(s.WithContext(t.Context(), session), message)
+	}
+	for _, session := range sessions {
+		assert.Equal(t, []any{1, 10}, progressValues(session.notificationChannel, 10*time.Millisecond))
+	}
+
+	s.progressThrottle.mu.Lock()
+	defer s.progressThrottle.mu.Unlock()
+	assert.Empty(t, s.progressThrottle.streams)
+}
+
+func TestWithProgressRateLimit_EvictsIdleStreams(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithProgressRateLimit(100))
+	session := &fakeSession{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	ctx := s.WithContext(t.Context(), session)
+
+	send := func(token string) {
+		require.NoError(t, s.SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+			"progressToken": token,
+			"progress":      1,
+		}))
+	}
+	send("a")
+	time.Sleep(20 * time.Millisecond)
+	send("b")
+
+	s.progressThrottle.mu.Lock()
+	defer s.progressThrottle.mu.Unlock()
+	require.Len(t, s.progressThrottle.streams, 1)
+	for key := range s.progressThrottle.streams {
+		assert.Equal(t, "b", key.token)
+	}
+}
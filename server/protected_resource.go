@@ -109,6 +109,20 @@ func ProtectedResourceMetadataPath(resource string) string {
 	return path.Join(WellKnownProtectedResourcePath, p)
 }
 
+// protectedResourceMetadataURL returns the absolute URL config is served at,
+// for the resource_metadata parameter of WWW-Authenticate challenges. It
+// returns "" for a nil config or a resource that is not an absolute URI.
+func protectedResourceMetadataURL(config *ProtectedResourceMetadataConfig) string {
+	if config == nil {
+		return ""
+	}
+	u, err := url.Parse(config.Resource)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return ""
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: ProtectedResourceMetadataPath(config.Resource)}).String()
+}
+
 // NewProtectedResourceMetadataHandler returns an http.Handler that serves
 // the given OAuth 2.0 Protected Resource Metadata as JSON.
 //
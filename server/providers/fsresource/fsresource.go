@@ -0,0 +1,521 @@
+// Package fsresource exposes a directory tree as MCP resources. Every file
+// becomes a resource, a resource template serves any path below the
+// directory, and changes on disk are announced to clients:
+//
+//	s := server.NewMCPServer("files", "1.0.0", server.WithResourceCapabilities(true, true))
+//	p, err := fsresource.New(s, "./docs")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer p.Close()
+//
+// Changes are detected with the platform's file system notifications, or by
+// polling the tree where those are unavailable. New and deleted files trigger
+// notifications/resources/list_changed when the server enables listChanged
+// for resources, and modified files trigger notifications/resources/updated
+// for the sessions subscribed to them.
+package fsresource
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/mark3labs/mcp-go/internal/fileuri"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultPollInterval is how often the directory is scanned for changes
+	// when file system notifications are unavailable.
+	DefaultPollInterval = 2 * time.Second
+	// DefaultMaxFileSize is the size of the largest file a read returns.
+	DefaultMaxFileSize = 10 << 20
+)
+
+// settleDelay is how long the watcher waits for further events before it
+// rescans, so a burst of writes causes a single scan.
+const settleDelay = 100 * time.Millisecond
+
+var (
+	// ErrOutsideRoot is returned when a resource URI does not name a file
+	// inside the provider's directory.
+	ErrOutsideRoot = errors.New("path is outside the resource directory")
+	// ErrFileTooLarge is returned when reading a file larger than the
+	// provider's maximum file size.
+	ErrFileTooLarge = errors.New("file is too large")
+)
+
+// Provider serves the files of a directory as resources of an MCPServer.
+type Provider struct {
+	server       *server.MCPServer
+	dir          string
+	root         *os.Root
+	uriPrefix    string
+	pollInterval time.Duration
+	maxFileSize  int64
+	filter       func(relPath string, entry fs.DirEntry) bool
+
+	mu    sync.Mutex
+	files map[string]fileState // slash-separated relative path -> state
+	dirs  map[string]bool      // slash-separated relative paths of exposed directories
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithURIPrefix sets the prefix of the resource URIs; a file's URI is the
+// prefix, a slash and its path relative to the directory. Defaults to the
+// file:// URI of the directory.
+func WithURIPrefix(prefix string) Option {
+	return func(p *Provider) {
+		p.uriPrefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithPollInterval sets how often the directory is scanned for changes
+// when file system notifications are unavailable. A non-positive interval
+// disables watching altogether; call Refresh to pick up changes. Defaults to
+// DefaultPollInterval.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		p.pollInterval = interval
+	}
+}
+
+// WithMaxFileSize sets the size of the largest file a read returns; reading
+// a larger one fails with ErrFileTooLarge. Defaults to DefaultMaxFileSize.
+func WithMaxFileSize(size int64) Option {
+	return func(p *Provider) {
+		p.maxFileSize = size
+	}
+}
+
+// WithFilter sets which files and directories are exposed. relPath is
+// slash-separated and relative to the directory; returning false for a
+// directory skips its whole subtree. The template does not serve filtered
+// files either. By default, entries whose name starts with a dot are
+// skipped.
+func WithFilter(filter func(relPath string, entry fs.DirEntry) bool) Option {
+	return func(p *Provider) {
+		p.filter = filter
+	}
+}
+
+// New registers the files below dir as resources of s, together with a
+// resource template for every path below dir, and starts watching dir for
+// changes. Call Close to stop watching and unregister the resources.
+func New(s *server.MCPServer, dir string, opts ...Option) (*Provider, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("fsresource: %w", err)
+	}
+	root, err := os.OpenRoot(abs)
+	if err != nil {
+		return nil, fmt.Errorf("fsresource: %w", err)
+	}
+
+	p := &Provider{
+		server:       s,
+		dir:          abs,
+		root:         root,
+		uriPrefix:    strings.TrimSuffix(fileuri.FromPath(abs), "/"),
+		pollInterval: DefaultPollInterval,
+		maxFileSize:  DefaultMaxFileSize,
+		filter:       skipHidden,
+		files:        make(map[string]fileState),
+		dirs:         make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(p.templateURI(), filepath.Base(abs),
+			mcp.WithTemplateDescription("Files below "+abs),
+		),
+		p.handleRead,
+	)
+	if err := p.Refresh(); err != nil {
+		p.unregister()
+		root.Close()
+		return nil, err
+	}
+
+	if p.pollInterval > 0 {
+		p.stop = make(chan struct{})
+		p.stopped = make(chan struct{})
+		go p.watch()
+	}
+	return p, nil
+}
+
+// Close stops watching the directory and unregisters its resources and
+// template.
+func (p *Provider) Close() error {
+	if p.stop != nil {
+		close(p.stop)
+		<-p.stopped
+		p.stop = nil
+	}
+	p.unregister()
+	return p.root.Close()
+}
+
+// URI returns the resource URI of the file at relPath, a slash-separated
+// path relative to the directory.
+func (p *Provider) URI(relPath string) string {
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return p.uriPrefix + "/" + strings.Join(segments, "/")
+}
+
+// Refresh scans the directory and applies the changes since the previous
+// scan: new files are registered, deleted ones unregistered, and
+// subscribers of modified files notified.
+func (p *Provider) Refresh() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current, dirs, err := p.scan()
+	if err != nil {
+		return err
+	}
+	p.dirs = dirs
+
+	var added []server.ServerResource
+	var removed []string
+	for relPath := range current {
+		if _, ok := p.files[relPath]; !ok {
+			added = append(added, p.resource(relPath))
+		}
+	}
+	for relPath, previous := range p.files {
+		state, ok := current[relPath]
+		if !ok {
+			removed = append(removed, p.URI(relPath))
+		} else if state != previous {
+			p.server.NotifyResourceUpdated(p.URI(relPath))
+		}
+	}
+	p.files = current
+
+	if len(removed) > 0 {
+		p.server.DeleteResources(removed...)
+	}
+	if len(added) > 0 {
+		slices.SortFunc(added, func(a, b server.ServerResource) int {
+			return strings.Compare(a.Resource.URI, b.Resource.URI)
+		})
+		p.server.AddResources(added...)
+	}
+	return nil
+}
+
+// watch refreshes the directory on file system notifications until Close is
+// called. It falls back to polling if notifications are unavailable or fail.
+func (p *Provider) watch() {
+	defer close(p.stopped)
+	if p.notify() {
+		return
+	}
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			// A directory that is temporarily unreadable is retried on the
+			// next tick.
+			_ = p.Refresh()
+		}
+	}
+}
+
+// notify refreshes the directory whenever the watcher reports a change. It
+// returns true when Close was called, and false when the caller must poll
+// instead.
+func (p *Provider) notify() bool {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return false
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool)
+	// addWatches watches the directories found by the last scan. Watches of
+	// removed directories are dropped by the watcher itself.
+	addWatches := func() error {
+		p.mu.Lock()
+		dirs := make([]string, 0, len(p.dirs)+1)
+		dirs = append(dirs, ".")
+		for dir := range p.dirs {
+			dirs = append(dirs, dir)
+		}
+		p.mu.Unlock()
+		current := make(map[string]bool, len(dirs))
+		for _, dir := range dirs {
+			current[dir] = true
+			if watched[dir] {
+				continue
+			}
+			if err := watcher.Add(filepath.Join(p.dir, filepath.FromSlash(dir))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		watched = current
+		return nil
+	}
+	if addWatches() != nil {
+		return false
+	}
+	// Changes between the initial scan and the first watch are picked up
+	// here.
+	_ = p.Refresh()
+
+	settle := time.NewTimer(settleDelay)
+	settle.Stop()
+	defer settle.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return true
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return false
+			}
+			settle.Reset(settleDelay)
+		case _, ok := <-watcher.Errors:
+			// A dropped event, such as an overflowing queue, may hide a
+			// change, so polling takes over.
+			if !ok {
+				return false
+			}
+			_ = p.Refresh()
+			return false
+		case <-settle.C:
+			_ = p.Refresh()
+			if addWatches() != nil {
+				return false
+			}
+		}
+	}
+}
+
+// scan returns the state of every exposed file below the directory, and
+// the exposed directories.
+func (p *Provider) scan() (map[string]fileState, map[string]bool, error) {
+	files := make(map[string]fileState)
+	dirs := make(map[string]bool)
+	err := fs.WalkDir(p.root.FS(), ".", func(relPath string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && relPath != "." {
+			// The entry was removed during the scan.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if !p.filter(relPath, entry) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			dirs[relPath] = true
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// The file was removed during the scan.
+			return nil
+		}
+		files[relPath] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("fsresource: scan %s: %w", p.dir, err)
+	}
+	return files, dirs, nil
+}
+
+// resource describes the file at relPath.
+func (p *Provider) resource(relPath string) server.ServerResource {
+	var opts []mcp.ResourceOption
+	if mimeType := mimeTypeByName(relPath); mimeType != "" {
+		opts = append(opts, mcp.WithMIMEType(mimeType))
+	}
+	return server.ServerResource{
+		Resource: mcp.NewResource(p.URI(relPath), relPath, opts...),
+		Handler:  p.handleRead,
+	}
+}
+
+// handleRead reads the file named by the request URI.
+func (p *Provider) handleRead(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	relPath, err := p.relPath(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !p.allowed(relPath) {
+		return nil, fmt.Errorf("%w: %s", server.ErrResourceNotFound, request.Params.URI)
+	}
+	data, err := p.readFile(relPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", server.ErrResourceNotFound, request.Params.URI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fsresource: %w", err)
+	}
+	return []mcp.ResourceContents{contents(request.Params.URI, relPath, data)}, nil
+}
+
+// readFile reads the file at relPath, failing with ErrFileTooLarge instead
+// of reading more than the maximum file size.
+func (p *Provider) readFile(relPath string) ([]byte, error) {
+	f, err := p.root.Open(filepath.FromSlash(relPath))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > p.maxFileSize {
+		return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrFileTooLarge, relPath, info.Size(), p.maxFileSize)
+	}
+	// The file may have grown since the Stat.
+	data, err := io.ReadAll(io.LimitReader(f, p.maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > p.maxFileSize {
+		return nil, fmt.Errorf("%w: %s exceeds the limit of %d bytes", ErrFileTooLarge, relPath, p.maxFileSize)
+	}
+	return data, nil
+}
+
+// relPath extracts the relative path from a resource URI.
+func (p *Provider) relPath(uri string) (string, error) {
+	escaped, ok := strings.CutPrefix(uri, p.uriPrefix+"/")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrOutsideRoot, uri)
+	}
+	relPath, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", fmt.Errorf("fsresource: invalid uri %q: %w", uri, err)
+	}
+	if !fs.ValidPath(relPath) || relPath == "." {
+		return "", fmt.Errorf("%w: %s", ErrOutsideRoot, uri)
+	}
+	return relPath, nil
+}
+
+// allowed reports whether the filter admits relPath and every directory
+// above it.
+func (p *Provider) allowed(relPath string) bool {
+	for dir := path.Dir(relPath); dir != "."; dir = path.Dir(dir) {
+		info, err := p.root.Stat(filepath.FromSlash(dir))
+		if err != nil || !p.filter(dir, fs.FileInfoToDirEntry(info)) {
+			return false
+		}
+	}
+	info, err := p.root.Stat(filepath.FromSlash(relPath))
+	if err != nil {
+		// Let the read report the missing file.
+		return true
+	}
+	return p.filter(relPath, fs.FileInfoToDirEntry(info))
+}
+
+func (p *Provider) templateURI() string {
+	return p.uriPrefix + "/{+path}"
+}
+
+func (p *Provider) unregister() {
+	p.mu.Lock()
+	uris := make([]string, 0, len(p.files))
+	for relPath := range p.files {
+		uris = append(uris, p.URI(relPath))
+	}
+	p.files = make(map[string]fileState)
+	p.dirs = make(map[string]bool)
+	p.mu.Unlock()
+
+	p.server.DeleteResources(uris...)
+	p.server.DeleteResourceTemplates(p.templateURI())
+}
+
+// contents returns data as text if it is textual, and as a blob otherwise.
+func contents(uri, relPath string, data []byte) mcp.ResourceContents {
+	mimeType := mimeTypeByName(relPath)
+	if mimeType == "" {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	if isText(mimeType) && utf8.Valid(data) {
+		return mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}
+	}
+	return mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}
+}
+
+// mimeTypeByName returns the MIME type registered for the extension of
+// name, without parameters, or "" if there is none.
+func mimeTypeByName(name string) string {
+	mimeType, _, err := mime.ParseMediaType(mime.TypeByExtension(path.Ext(name)))
+	if err != nil {
+		return ""
+	}
+	return mimeType
+}
+
+func isText(mimeType string) bool {
+	switch {
+	case strings.HasPrefix(mimeType, "text/"),
+		strings.HasSuffix(mimeType, "+json"),
+		strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/javascript", "application/x-sh", "application/toml", "application/yaml":
+		return true
+	}
+	return false
+}
+
+func skipHidden(relPath string, entry fs.DirEntry) bool {
+	return !strings.HasPrefix(entry.Name(), ".")
+}
@@ -0,0 +1,233 @@
+package fsresource
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string                                   { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func request(t *testing.T, s *server.MCPServer, ctx context.Context, method string, params any) json.RawMessage {
+	t.Helper()
+	msg, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	data, err := json.Marshal(s.HandleMessage(ctx, msg))
+	require.NoError(t, err)
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(data, &resp))
+	if resp.Error != nil {
+		return json.RawMessage(`{"error":` + string(mustJSON(t, resp.Error.Message)) + `}`)
+	}
+	return resp.Result
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+func TestProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "readme.txt", "# Hello")
+	writeFile(t, dir, "data/my config.json", `{"a":1}`)
+	writeFile(t, dir, "image.bin", "\x00\x01\x02")
+	writeFile(t, dir, ".git/HEAD", "ref")
+
+	s := server.NewMCPServer("files", "1.0.0", server.WithResourceCapabilities(true, true))
+	p, err := New(s, dir, WithURIPrefix("docs://"), WithPollInterval(0))
+	require.NoError(t, err)
+
+	session := &testSession{id: "s1", notifications: make(chan mcp.JSONRPCNotification, 16)}
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+	ctx := s.WithContext(t.Context(), session)
+
+	t.Run("lists files", func(t *testing.T) {
+		var list mcp.ListResourcesResult
+		require.NoError(t, json.Unmarshal(request(t, s, ctx, "resources/list", map[string]any{}), &list))
+		uris := map[string]string{}
+		for _, r := range list.Resources {
+			uris[r.URI] = r.MIMEType
+		}
+		assert.Equal(t, map[string]string{
+			"docs://data/my%20config.json": "application/json",
+			"docs://image.bin":             "application/octet-stream",
+			"docs://readme.txt":            "text/plain",
+		}, uris)
+	})
+
+	read := func(uri string) (text, blob, errMsg string) {
+		var result struct {
+			Contents []struct {
+				Text string `json:"text"`
+				Blob string `json:"blob"`
+			} `json:"contents"`
+			Error string `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(request(t, s, ctx, "resources/read", map[string]any{"uri": uri}), &result))
+		if result.Error != "" {
+			return "", "", result.Error
+		}
+		require.Len(t, result.Contents, 1)
+		return result.Contents[0].Text, result.Contents[0].Blob, ""
+	}
+
+	t.Run("reads", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			uri     string
+			text    string
+			blob    string
+			wantErr string
+		}{
+			{name: "text", uri: "docs://readme.txt", text: "# Hello"},
+			{name: "escaped path", uri: "docs://data/my%20config.json", text: `{"a":1}`},
+			{name: "binary", uri: "docs://image.bin", blob: "AAEC"},
+			{name: "traversal", uri: "docs://../etc/passwd", wantErr: "outside the resource directory"},
+			{name: "hidden", uri: "docs://.git/HEAD", wantErr: "not found"},
+			{name: "missing", uri: "docs://missing.txt", wantErr: "not found"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				text, blob, errMsg := read(tt.uri)
+				if tt.wantErr != "" {
+					assert.Contains(t, errMsg, tt.wantErr)
+					return
+				}
+				assert.Empty(t, errMsg)
+				assert.Equal(t, tt.text, text)
+				assert.Equal(t, tt.blob, blob)
+			})
+		}
+	})
+
+	t.Run("template serves files created since the last scan", func(t *testing.T) {
+		writeFile(t, dir, "new.txt", "fresh")
+		text, _, errMsg := read("docs://new.txt")
+		assert.Empty(t, errMsg)
+		assert.Equal(t, "fresh", text)
+	})
+
+	drain := func() []mcp.JSONRPCNotification {
+		var got []mcp.JSONRPCNotification
+		for {
+			select {
+			case n := <-session.notifications:
+				got = append(got, n)
+			default:
+				return got
+			}
+		}
+	}
+
+	t.Run("refresh announces changes", func(t *testing.T) {
+		request(t, s, ctx, "resources/subscribe", map[string]any{"uri": "docs://readme.txt"})
+		require.NoError(t, p.Refresh()) // picks up new.txt
+		drain()
+
+		writeFile(t, dir, "readme.txt", "# Hello, world")
+		require.NoError(t, p.Refresh())
+		notifications := drain()
+		require.Len(t, notifications, 1)
+		assert.Equal(t, mcp.MethodNotificationResourceUpdated, notifications[0].Method)
+		assert.Equal(t, "docs://readme.txt", notifications[0].Params.AdditionalFields["uri"])
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "image.bin")))
+		require.NoError(t, p.Refresh())
+		notifications = drain()
+		require.Len(t, notifications, 1)
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notifications[0].Method)
+		assert.NotContains(t, s.ListResources(), "docs://image.bin")
+	})
+
+	t.Run("close unregisters", func(t *testing.T) {
+		require.NoError(t, p.Close())
+		assert.Empty(t, s.ListResources())
+		_, _, errMsg := read("docs://readme.txt")
+		assert.NotEmpty(t, errMsg)
+	})
+}
+
+func TestProvider_Watch(t *testing.T) {
+	dir := t.TempDir()
+	s := server.NewMCPServer("files", "1.0.0")
+	// Polling is too slow to pass, so the changes must be noticed through
+	// file system notifications.
+	p, err := New(s, dir, WithPollInterval(time.Hour))
+	require.NoError(t, err)
+	defer p.Close()
+
+	writeFile(t, dir, "later.txt", "hi")
+	assert.Eventually(t, func() bool {
+		_, ok := s.ListResources()[p.URI("later.txt")]
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Directories created later are watched too.
+	writeFile(t, dir, "sub/new.txt", "hi")
+	assert.Eventually(t, func() bool {
+		_, ok := s.ListResources()[p.URI("sub/new.txt")]
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+	writeFile(t, dir, "sub/newer.txt", "hi")
+	assert.Eventually(t, func() bool {
+		_, ok := s.ListResources()[p.URI("sub/newer.txt")]
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestProvider_MaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "small.txt", "1234")
+	writeFile(t, dir, "large.txt", "12345")
+
+	s := server.NewMCPServer("files", "1.0.0")
+	p, err := New(s, dir, WithPollInterval(0), WithMaxFileSize(4))
+	require.NoError(t, err)
+	defer p.Close()
+
+	read := func(relPath string) ([]mcp.ResourceContents, error) {
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = p.URI(relPath)
+		return p.handleRead(t.Context(), request)
+	}
+	contents, err := read("small.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "1234", contents[0].(mcp.TextResourceContents).Text)
+	_, err = read("large.txt")
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+func TestNew_InvalidDir(t *testing.T) {
+	_, err := New(server.NewMCPServer("files", "1.0.0"), filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// member is a connection to one of the servers of an upstream, its primary
+// or one of its backups.
+type member struct {
+	client *client.Client
+
+	// initialized is only used by New and then by the health check
+	// goroutine.
+	initialized bool
+
+	// guarded by the upstream's mu
+	healthy bool
+	caps    mcp.ServerCapabilities
+}
+
+// isActive reports whether m is the member serving up.
+func (up *upstream) isActive(m *member) bool {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	return up.active == m
+}
+
+// isHealthy reports whether m answered the last health check.
+func (up *upstream) isHealthy(m *member) bool {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	return m.healthy
+}
+
+// setHealthy records whether m answered the last health check.
+func (up *upstream) setHealthy(m *member, healthy bool) {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	m.healthy = healthy
+}
+
+// timeout bounds every health check and resynchronization call: it is the
+// health check interval, or DefaultHealthCheckInterval with health checks
+// disabled.
+func (p *Proxy) timeout() time.Duration {
+	if p.healthInterval > 0 {
+		return p.healthInterval
+	}
+	return DefaultHealthCheckInterval
+}
+
+// initialize establishes a session with the server of m: it starts and
+// initializes the client the first time, and reconnects it with a new
+// session afterwards.
+func (p *Proxy) initialize(ctx context.Context, up *upstream, m *member) error {
+	var err error
+	if m.initialized {
+		err = m.client.Reconnect(ctx)
+	} else {
+		err = m.client.Start(ctx)
+		if err == nil {
+			request := mcp.InitializeRequest{}
+			request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+			request.Params.ClientInfo = p.clientInfo
+			_, err = m.client.Initialize(ctx, request)
+			m.initialized = err == nil
+		}
+	}
+	if err != nil {
+		up.setHealthy(m, false)
+		return err
+	}
+	caps := m.client.GetServerCapabilities()
+	up.mu.Lock()
+	m.healthy = true
+	m.caps = caps
+	up.mu.Unlock()
+	return nil
+}
+
+// healthCheck checks every upstream each health check interval until the
+// proxy is closed.
+func (p *Proxy) healthCheck() {
+	defer p.checking.Done()
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			for _, up := range p.upstreams {
+				p.check(up)
+			}
+		}
+	}
+}
+
+// check pings the members of up and re-establishes the sessions of those
+// that are down. If the active member is down, or came back with a new
+// session, the first healthy member in order of preference takes over.
+func (p *Proxy) check(up *upstream) {
+	up.mu.Lock()
+	active := up.active
+	up.mu.Unlock()
+
+	renewed := false
+	for _, m := range up.members {
+		if up.isHealthy(m) {
+			ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+			up.setHealthy(m, m.client.Ping(ctx) == nil)
+			cancel()
+		}
+		if !up.isHealthy(m) {
+			ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+			if p.initialize(ctx, up, m) == nil && m == active {
+				renewed = true
+			}
+			cancel()
+		}
+	}
+	if up.isHealthy(active) && !renewed {
+		return
+	}
+	for _, m := range up.members {
+		if up.isHealthy(m) {
+			p.activate(up, active, m)
+			return
+		}
+	}
+	up.mu.Lock()
+	reported := up.unreachable
+	up.unreachable = true
+	up.mu.Unlock()
+	if !reported {
+		p.logError(up, "proxy: no server of the upstream is reachable")
+	}
+}
+
+// activate makes m serve up in place of previous, registering the entries
+// m lists and renewing the resource subscriptions of the clients with it.
+// Calls in flight on previous are not retried.
+func (p *Proxy) activate(up *upstream, previous, m *member) {
+	up.mu.Lock()
+	up.active = m
+	up.unreachable = false
+	uris := make([]string, 0, len(up.subscriptions))
+	for uri := range up.subscriptions {
+		uris = append(uris, uri)
+	}
+	caps := m.caps
+	up.mu.Unlock()
+	if m != previous {
+		p.log(up, mcp.LoggingLevelWarning, "proxy: upstream failed over to another server")
+	}
+
+	for _, sync := range []func(context.Context, *upstream) error{
+		p.syncTools, p.syncPrompts, p.syncResources, p.syncResourceTemplates,
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+		if err := sync(ctx, up); err != nil {
+			p.logError(up, "proxy: resynchronizing upstream: "+err.Error())
+		}
+		cancel()
+	}
+	if caps.Resources == nil || !caps.Resources.Subscribe {
+		return
+	}
+	for _, uri := range uris {
+		request := mcp.SubscribeRequest{}
+		request.Params.URI = uri
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+		if err := m.client.Subscribe(ctx, request); err != nil {
+			p.logError(up, fmt.Sprintf("proxy: resubscribing to %s: %v", uri, err))
+		}
+		cancel()
+	}
+}
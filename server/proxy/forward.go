@@ -0,0 +1,300 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// call is a downstream request being served by an upstream.
+type call struct {
+	ctx       context.Context
+	sessionID string
+}
+
+// downstreamKey is the context key under which a forwarded call carries the
+// context of the downstream request, so that the requests an upstream makes
+// while serving it can be routed back to the client that made it.
+type downstreamKey struct{}
+
+// begin records a call to up made with ctx and returns the context to
+// forward it with. The returned function ends the call.
+func (up *upstream) begin(ctx context.Context) (context.Context, func()) {
+	c := &call{ctx: ctx}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		c.sessionID = session.SessionID()
+	}
+	up.mu.Lock()
+	up.calls[c] = struct{}{}
+	up.mu.Unlock()
+	return context.WithValue(ctx, downstreamKey{}, ctx), func() {
+		up.mu.Lock()
+		delete(up.calls, c)
+		up.mu.Unlock()
+	}
+}
+
+// downstream returns the context of the downstream request a request of
+// up, made with ctx, belongs to. Transports that do not carry the context
+// of the call through to the request fall back to the one client with calls
+// in flight on up.
+func (up *upstream) downstream(ctx context.Context) (context.Context, error) {
+	if dctx, ok := ctx.Value(downstreamKey{}).(context.Context); ok {
+		return dctx, nil
+	}
+	if dctx, ok := up.soleCaller(); ok {
+		return dctx, nil
+	}
+	return nil, ErrNoDownstream
+}
+
+// soleCaller returns the context of a call in flight on up when all of
+// them come from the same client. Calls without a session ID cannot be told
+// apart from those of other clients, so any such call makes it refuse.
+func (up *upstream) soleCaller() (context.Context, bool) {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	var only *call
+	for c := range up.calls {
+		if c.sessionID == "" || only != nil && only.sessionID != c.sessionID {
+			return nil, false
+		}
+		only = c
+	}
+	if only == nil {
+		return nil, false
+	}
+	return only.ctx, true
+}
+
+func (p *Proxy) forwardTool(up *upstream, name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, end := up.begin(ctx)
+		defer end()
+
+		forwarded := mcp.CallToolRequest{}
+		forwarded.Params.Name = name
+		forwarded.Params.Arguments = request.Params.Arguments
+		if meta := request.Params.Meta; meta != nil {
+			forwarded.Params.Meta = &mcp.Meta{
+				ProgressToken:    meta.ProgressToken,
+				AdditionalFields: meta.AdditionalFields,
+			}
+			if meta.ProgressToken != nil {
+				token, release := p.progress.route(ctx, meta.ProgressToken)
+				defer release()
+				forwarded.Params.Meta.ProgressToken = token
+			}
+		}
+		return up.client().CallTool(ctx, forwarded)
+	}
+}
+
+func (p *Proxy) forwardPrompt(up *upstream, name string) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx, end := up.begin(ctx)
+		defer end()
+
+		forwarded := mcp.GetPromptRequest{}
+		forwarded.Params.Name = name
+		forwarded.Params.Arguments = request.Params.Arguments
+		return up.client().GetPrompt(ctx, forwarded)
+	}
+}
+
+func (p *Proxy) forwardResource(up *upstream) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		_, uri, ok := p.resolveURI(request.Params.URI)
+		if !ok {
+			return nil, fmt.Errorf("proxy: resource %s does not belong to upstream %q", request.Params.URI, up.name)
+		}
+		ctx, end := up.begin(ctx)
+		defer end()
+
+		forwarded := mcp.ReadResourceRequest{}
+		forwarded.Params.URI = uri
+		forwarded.Params.Arguments = request.Params.Arguments
+		result, err := up.client().ReadResource(ctx, forwarded)
+		if err != nil {
+			return nil, err
+		}
+		contents := make([]mcp.ResourceContents, 0, len(result.Contents))
+		for _, content := range result.Contents {
+			switch c := content.(type) {
+			case mcp.TextResourceContents:
+				c.URI = resourceURI(up, c.URI)
+				content = c
+			case mcp.BlobResourceContents:
+				c.URI = resourceURI(up, c.URI)
+				content = c
+			}
+			contents = append(contents, content)
+		}
+		return contents, nil
+	}
+}
+
+// router serves the sampling, elicitation and roots requests of an
+// upstream by making them of the downstream client they belong to.
+type router struct {
+	proxy    *Proxy
+	upstream *upstream
+}
+
+func (r *router) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	dctx, err := r.upstream.downstream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxy.server.RequestSampling(dctx, request)
+}
+
+func (r *router) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	dctx, err := r.upstream.downstream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxy.server.RequestElicitation(dctx, request)
+}
+
+func (r *router) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	dctx, err := r.upstream.downstream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxy.server.RequestRoots(dctx, request)
+}
+
+// progressRouter maps the progress tokens the proxy sends upstream to the
+// tokens and clients of the downstream calls. Downstream tokens are only
+// unique per client, so each forwarded call gets a token of its own.
+type progressRouter struct {
+	mu     sync.Mutex
+	next   uint64
+	routes map[string]progressRoute
+}
+
+type progressRoute struct {
+	ctx   context.Context
+	token mcp.ProgressToken
+}
+
+// route returns the upstream token for a call made with ctx and the
+// downstream token. The returned function removes the route; progress
+// arriving for the token afterwards is dropped, since it must not reach the
+// client once the call has completed.
+func (r *progressRouter) route(ctx context.Context, token mcp.ProgressToken) (mcp.ProgressToken, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	key := "proxy-" + strconv.FormatUint(r.next, 10)
+	r.routes[key] = progressRoute{ctx: ctx, token: token}
+	return key, func() {
+		r.mu.Lock()
+		delete(r.routes, key)
+		r.mu.Unlock()
+	}
+}
+
+func (r *progressRouter) lookup(token any) (progressRoute, bool) {
+	key, ok := token.(string)
+	if !ok {
+		return progressRoute{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	route, ok := r.routes[key]
+	return route, ok
+}
+
+// handleNotification relays a notification of up to the downstream
+// clients.
+func (p *Proxy) handleNotification(up *upstream, n mcp.JSONRPCNotification) {
+	params := n.Params.AdditionalFields
+	switch n.Method {
+	case mcp.MethodNotificationToolsListChanged:
+		p.resync(up, p.syncTools)
+	case mcp.MethodNotificationPromptsListChanged:
+		p.resync(up, p.syncPrompts)
+	case mcp.MethodNotificationResourcesListChanged:
+		p.resync(up, p.syncResources)
+		p.resync(up, p.syncResourceTemplates)
+	case mcp.MethodNotificationResourceUpdated:
+		if uri, ok := params["uri"].(string); ok {
+			p.server.NotifyResourceUpdated(resourceURI(up, uri))
+		}
+	case string(mcp.MethodNotificationProgress):
+		route, ok := p.progress.lookup(params["progressToken"])
+		if !ok {
+			return
+		}
+		forwarded := maps.Clone(params)
+		forwarded["progressToken"] = route.token
+		_ = p.server.SendNotificationToClient(route.ctx, n.Method, forwarded)
+	case string(mcp.MethodNotificationMessage):
+		level, _ := params["level"].(string)
+		logger, _ := params["logger"].(string)
+		if logger == "" {
+			logger = up.name
+		}
+		message := mcp.NewLoggingMessageNotification(mcp.LoggingLevel(level), logger, params["data"])
+		// Upstream logs usually belong to the call being served; send them
+		// to its client when it can be told apart.
+		if ctx, ok := up.soleCaller(); ok {
+			_ = p.server.SendLogMessageToClient(ctx, message)
+			return
+		}
+		p.broadcastLog(message)
+	}
+}
+
+// broadcastLog sends a log message to every client whose log level, set
+// with logging/setLevel, admits it.
+func (p *Proxy) broadcastLog(message mcp.LoggingMessageNotification) {
+	for _, id := range p.server.SessionsMatching(nil) {
+		_ = p.server.SendLogMessageToSpecificClient(id, message)
+	}
+}
+
+// subscribe subscribes to the resource uri of up when the first client
+// subscribes to it through the proxy.
+func (up *upstream) subscribe(ctx context.Context, uri string) {
+	if caps := up.caps(); caps.Resources == nil || !caps.Resources.Subscribe {
+		return
+	}
+	up.mu.Lock()
+	up.subscriptions[uri]++
+	first := up.subscriptions[uri] == 1
+	up.mu.Unlock()
+	if first {
+		request := mcp.SubscribeRequest{}
+		request.Params.URI = uri
+		_ = up.client().Subscribe(ctx, request)
+	}
+}
+
+// unsubscribe unsubscribes from the resource uri of up when the last client
+// subscribed through the proxy unsubscribes.
+func (up *upstream) unsubscribe(ctx context.Context, uri string) {
+	up.mu.Lock()
+	if up.subscriptions[uri] == 0 {
+		up.mu.Unlock()
+		return
+	}
+	up.subscriptions[uri]--
+	last := up.subscriptions[uri] == 0
+	if last {
+		delete(up.subscriptions, uri)
+	}
+	up.mu.Unlock()
+	if last {
+		request := mcp.UnsubscribeRequest{}
+		request.Params.URI = uri
+		_ = up.client().Unsubscribe(ctx, request)
+	}
+}
@@ -0,0 +1,527 @@
+// Package proxy aggregates several upstream MCP servers behind a single
+// MCPServer, the building block of gateway deployments. The proxy connects
+// to every upstream as a client and re-exposes its tools, prompts and
+// resources under namespaced names:
+//
+//	s := server.NewMCPServer("gateway", "1.0.0")
+//	github, _ := transport.NewStreamableHTTP("http://github-mcp:8080/mcp")
+//	jira, _ := transport.NewStreamableHTTP("http://jira-mcp:8080/mcp")
+//	p, err := proxy.New(ctx, s, []proxy.Upstream{
+//		{Name: "github", Transport: github},
+//		{Name: "jira", Transport: jira},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer p.Close()
+//	server.NewStreamableHTTPServer(s).Start(":8080")
+//
+// The tool search_issues of the upstream named github is exposed as
+// github_search_issues, and so is every prompt. Resource URIs and URI
+// templates are prefixed with the upstream name and a plus sign, so
+// file:///README.md becomes github+file:///README.md.
+//
+// Calls are forwarded to the upstream that owns the name, and traffic in
+// the other direction is routed back to the client whose call caused it:
+// progress notifications reach the caller that supplied the progress
+// token, and sampling, elicitation and roots requests made by an upstream
+// while it serves a call are sent to the client that made the call.
+// List-changed notifications of an upstream resynchronize its entries,
+// resource update notifications reach the clients subscribed to the
+// namespaced URI, and log messages reach the client whose call is in
+// flight, or, when that cannot be told, every client whose log level
+// admits them.
+//
+// An upstream can list backup transports to standby replicas of its server.
+// The proxy keeps them connected and pings every connection periodically;
+// when the active one stops answering it fails over to the first healthy
+// connection in order of preference, the primary first, and resynchronizes
+// the upstream's entries. Connections that stopped answering are re-established
+// with a new session as soon as their server is back.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultSeparator joins the upstream name and the name of a tool or
+// prompt.
+const DefaultSeparator = "_"
+
+// DefaultHealthCheckInterval is how often the proxy pings its upstreams
+// unless WithHealthCheckInterval sets otherwise.
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// resourceSeparator joins the upstream name and a resource URI. A plus sign
+// is valid in a URI scheme, so the namespaced URI is still a URI.
+const resourceSeparator = "+"
+
+// ErrNoDownstream is returned to an upstream that sends a sampling,
+// elicitation or roots request the proxy cannot attribute to a client.
+var ErrNoDownstream = errors.New("proxy: no client to route the request to")
+
+// Upstream describes an MCP server the proxy connects to.
+type Upstream struct {
+	// Name namespaces the upstream's tools, prompts and resources. It must
+	// be unique among the upstreams of a proxy.
+	Name string
+	// Transport connects to the upstream. It is started by New and closed
+	// by Proxy.Close.
+	Transport transport.Interface
+	// Backups connect to standby replicas of the upstream, in order of
+	// preference. They are connected by New as well, so that they are ready
+	// to take over when Transport fails, and closed by Proxy.Close.
+	Backups []transport.Interface
+	// ClientOptions configure the client connected to the upstream. The
+	// proxy installs its own sampling, elicitation and roots handlers.
+	ClientOptions []client.ClientOption
+}
+
+// Option configures a Proxy.
+type Option func(*Proxy)
+
+// WithSeparator sets the string joining the upstream name and the name of
+// a tool or prompt. The default is "_".
+func WithSeparator(sep string) Option {
+	return func(p *Proxy) {
+		p.separator = sep
+	}
+}
+
+// WithClientInfo sets the implementation the proxy reports to upstreams
+// when it initializes. The default is mcp-go-proxy.
+func WithClientInfo(info mcp.Implementation) Option {
+	return func(p *Proxy) {
+		p.clientInfo = info
+	}
+}
+
+// WithHealthCheckInterval sets how often the proxy pings every connection
+// to its upstreams, re-establishes the ones that stopped answering and fails
+// over to a healthy one. Each ping, reconnection and resynchronization waits
+// at most d. The default is DefaultHealthCheckInterval; zero disables health
+// checks, and with them failover.
+func WithHealthCheckInterval(d time.Duration) Option {
+	return func(p *Proxy) {
+		p.healthInterval = d
+	}
+}
+
+// Proxy forwards the tools, prompts and resources of upstream MCP servers
+// through an MCPServer.
+type Proxy struct {
+	server     *server.MCPServer
+	separator  string
+	clientInfo mcp.Implementation
+	upstreams  []*upstream
+
+	progress progressRouter
+
+	healthInterval time.Duration
+	done           chan struct{}
+	closeOnce      sync.Once
+	checking       sync.WaitGroup
+}
+
+// New connects to every upstream, registers their tools, prompts, resources
+// and resource templates with s, and keeps them in sync until Close is
+// called. ctx bounds the connection and the initial listing only. An
+// upstream whose primary cannot be reached is served by its first backup
+// that can; if none can, New closes the upstreams it has connected and
+// returns the error.
+func New(ctx context.Context, s *server.MCPServer, upstreams []Upstream, opts ...Option) (*Proxy, error) {
+	p := &Proxy{
+		server:         s,
+		separator:      DefaultSeparator,
+		clientInfo:     mcp.Implementation{Name: "mcp-go-proxy", Version: "1.0.0"},
+		progress:       progressRouter{routes: make(map[string]progressRoute)},
+		healthInterval: DefaultHealthCheckInterval,
+		done:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	seen := make(map[string]bool, len(upstreams))
+	for _, u := range upstreams {
+		if u.Name == "" {
+			return nil, errors.New("proxy: upstream name is empty")
+		}
+		if seen[u.Name] {
+			return nil, fmt.Errorf("proxy: duplicate upstream %q", u.Name)
+		}
+		seen[u.Name] = true
+	}
+
+	for _, u := range upstreams {
+		up, err := p.connect(ctx, u)
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("proxy: upstream %q: %w", u.Name, err)
+		}
+		p.upstreams = append(p.upstreams, up)
+	}
+
+	if hooks := s.GetHooks(); hooks != nil {
+		hooks.AddAfterSubscribe(func(ctx context.Context, _ any, request *mcp.SubscribeRequest, _ *mcp.EmptyResult) {
+			if up, uri, ok := p.resolveURI(request.Params.URI); ok {
+				up.subscribe(ctx, uri)
+			}
+		})
+		hooks.AddAfterUnsubscribe(func(ctx context.Context, _ any, request *mcp.UnsubscribeRequest, _ *mcp.EmptyResult) {
+			if up, uri, ok := p.resolveURI(request.Params.URI); ok {
+				up.unsubscribe(ctx, uri)
+			}
+		})
+	}
+
+	if p.healthInterval > 0 {
+		p.checking.Add(1)
+		go p.healthCheck()
+	}
+	return p, nil
+}
+
+// Close stops the health checks and disconnects from every upstream. The
+// entries registered by the proxy stay on the server; calls to them fail
+// once the upstream is closed.
+func (p *Proxy) Close() error {
+	p.closeOnce.Do(func() { close(p.done) })
+	p.checking.Wait()
+	var errs []error
+	for _, up := range p.upstreams {
+		for _, m := range up.members {
+			if err := m.client.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("upstream %q: %w", up.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Client returns the client connected to the named upstream, or nil if
+// there is none, for calls the proxy does not forward itself. After a
+// failover it returns the client of the backup.
+func (p *Proxy) Client(name string) *client.Client {
+	for _, up := range p.upstreams {
+		if up.name == name {
+			return up.client()
+		}
+	}
+	return nil
+}
+
+// toolName returns the name under which the tool or prompt name of up is
+// exposed.
+func (p *Proxy) toolName(up *upstream, name string) string {
+	return up.name + p.separator + name
+}
+
+// resourceURI returns the URI under which the resource uri of up is
+// exposed.
+func resourceURI(up *upstream, uri string) string {
+	return up.name + resourceSeparator + uri
+}
+
+// resolveURI returns the upstream that owns the exposed resource uri and
+// the URI of the resource on that upstream.
+func (p *Proxy) resolveURI(uri string) (*upstream, string, bool) {
+	for _, up := range p.upstreams {
+		if rest, ok := strings.CutPrefix(uri, up.name+resourceSeparator); ok {
+			return up, rest, true
+		}
+	}
+	return nil, "", false
+}
+
+// upstream is a connected Upstream.
+type upstream struct {
+	name    string
+	members []*member // the primary, then the backups
+
+	// syncMu serializes the resynchronizations of the upstream's entries.
+	syncMu    sync.Mutex
+	tools     []string
+	prompts   []string
+	resources []string
+	templates []string
+
+	mu     sync.Mutex
+	active *member
+	// unreachable is set by the health check while no member answers.
+	unreachable   bool
+	calls         map[*call]struct{}
+	subscriptions map[string]int // upstream URI -> subscribed clients
+}
+
+// client returns the client of the member serving up.
+func (up *upstream) client() *client.Client {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	return up.active.client
+}
+
+// caps returns the capabilities of the member serving up.
+func (up *upstream) caps() mcp.ServerCapabilities {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	return up.active.caps
+}
+
+// connect starts and initializes the clients of u and registers the
+// entries of the first member that could be initialized.
+func (p *Proxy) connect(ctx context.Context, u Upstream) (*upstream, error) {
+	up := &upstream{
+		name:          u.Name,
+		calls:         make(map[*call]struct{}),
+		subscriptions: make(map[string]int),
+	}
+	r := &router{proxy: p, upstream: up}
+	opts := append([]client.ClientOption{
+		client.WithSamplingHandler(r),
+		client.WithElicitationHandler(r),
+		client.WithRootsHandler(r),
+	}, u.ClientOptions...)
+	for _, t := range append([]transport.Interface{u.Transport}, u.Backups...) {
+		m := &member{client: client.NewClient(t, opts...)}
+		m.client.OnNotification(func(n mcp.JSONRPCNotification) {
+			// Standby members are kept connected but not listened to.
+			if up.isActive(m) {
+				p.handleNotification(up, n)
+			}
+		})
+		up.members = append(up.members, m)
+	}
+	closeAll := func() {
+		for _, m := range up.members {
+			_ = m.client.Close()
+		}
+	}
+
+	var primaryErr error
+	for _, m := range up.members {
+		err := p.initialize(ctx, up, m)
+		if err == nil && up.active == nil {
+			up.active = m
+		}
+		if err != nil && primaryErr == nil {
+			primaryErr = err
+		}
+	}
+	if up.active == nil {
+		closeAll()
+		return nil, primaryErr
+	}
+
+	for _, sync := range []func(context.Context, *upstream) error{
+		p.syncTools, p.syncPrompts, p.syncResources, p.syncResourceTemplates,
+	} {
+		if err := sync(ctx, up); err != nil {
+			closeAll()
+			return nil, err
+		}
+	}
+	return up, nil
+}
+
+// resync reruns sync in the background after a list-changed notification.
+func (p *Proxy) resync(up *upstream, sync func(context.Context, *upstream) error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+		defer cancel()
+		if err := sync(ctx, up); err != nil {
+			p.logError(up, "proxy: resynchronizing upstream: "+err.Error())
+		}
+	}()
+}
+
+// logError sends message to the clients as an error log message of up.
+func (p *Proxy) logError(up *upstream, message string) {
+	p.log(up, mcp.LoggingLevelError, message)
+}
+
+// log sends message to the clients as a log message of up.
+func (p *Proxy) log(up *upstream, level mcp.LoggingLevel, message string) {
+	p.broadcastLog(mcp.NewLoggingMessageNotification(level, up.name, message))
+}
+
+// stale returns the names in previous that are not in current.
+func stale(previous, current []string) []string {
+	keep := make(map[string]bool, len(current))
+	for _, name := range current {
+		keep[name] = true
+	}
+	var removed []string
+	for _, name := range previous {
+		if !keep[name] {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// The sync functions register the entries of the member serving up and
+// remove the ones it no longer lists. A member without the capability lists
+// nothing, which matters when a backup without it took over.
+
+func (p *Proxy) syncTools(ctx context.Context, up *upstream) error {
+	up.syncMu.Lock()
+	defer up.syncMu.Unlock()
+
+	var upstreamTools []mcp.Tool
+	if up.caps().Tools != nil {
+		var err error
+		if upstreamTools, err = up.client().ListToolsAll(ctx); err != nil {
+			return err
+		}
+	}
+	tools := make([]server.ServerTool, 0, len(upstreamTools))
+	for _, tool := range upstreamTools {
+		name := tool.Name
+		tool.Name = p.toolName(up, name)
+		tool.Namespace = up.name
+		tools = append(tools, server.ServerTool{Tool: tool, Handler: p.forwardTool(up, name)})
+	}
+	names := p.registerTools(up, tools)
+	if removed := stale(up.tools, names); len(removed) > 0 {
+		p.server.DeleteTools(removed...)
+	}
+	up.tools = names
+	return nil
+}
+
+// registerTools registers the tools of up and returns the names the server
+// registered them under, which the collision policy of the server may have
+// changed. A tool the policy rejects is left out and reported to the
+// clients, so that one conflicting tool does not hide the others.
+func (p *Proxy) registerTools(up *upstream, tools []server.ServerTool) []string {
+	if len(tools) == 0 {
+		return nil
+	}
+	names, err := p.server.RegisterTools(tools...)
+	if err == nil {
+		return names
+	}
+	// The batch is registered atomically; retry tool by tool to keep the
+	// ones that do not collide.
+	names = make([]string, 0, len(tools))
+	for _, tool := range tools {
+		registered, err := p.server.RegisterTools(tool)
+		if err != nil {
+			p.logError(up, "proxy: registering tool "+tool.Tool.Name+": "+err.Error())
+			continue
+		}
+		names = append(names, registered...)
+	}
+	return names
+}
+
+func (p *Proxy) syncPrompts(ctx context.Context, up *upstream) error {
+	up.syncMu.Lock()
+	defer up.syncMu.Unlock()
+
+	var upstreamPrompts []mcp.Prompt
+	if up.caps().Prompts != nil {
+		var err error
+		if upstreamPrompts, err = up.client().ListPromptsAll(ctx); err != nil {
+			return err
+		}
+	}
+	prompts := make([]server.ServerPrompt, 0, len(upstreamPrompts))
+	names := make([]string, 0, len(upstreamPrompts))
+	for _, prompt := range upstreamPrompts {
+		name := prompt.Name
+		prompt.Name = p.toolName(up, name)
+		prompts = append(prompts, server.ServerPrompt{Prompt: prompt, Handler: p.forwardPrompt(up, name)})
+		names = append(names, prompt.Name)
+	}
+	if removed := stale(up.prompts, names); len(removed) > 0 {
+		p.server.DeletePrompts(removed...)
+	}
+	if len(prompts) > 0 {
+		p.server.AddPrompts(prompts...)
+	}
+	up.prompts = names
+	return nil
+}
+
+func (p *Proxy) syncResources(ctx context.Context, up *upstream) error {
+	up.syncMu.Lock()
+	defer up.syncMu.Unlock()
+
+	var upstreamResources []mcp.Resource
+	if up.caps().Resources != nil {
+		var err error
+		if upstreamResources, err = up.client().ListResourcesAll(ctx); err != nil {
+			return err
+		}
+	}
+	resources := make([]server.ServerResource, 0, len(upstreamResources))
+	uris := make([]string, 0, len(upstreamResources))
+	for _, resource := range upstreamResources {
+		resource.URI = resourceURI(up, resource.URI)
+		resources = append(resources, server.ServerResource{Resource: resource, Handler: p.forwardResource(up)})
+		uris = append(uris, resource.URI)
+	}
+	if removed := stale(up.resources, uris); len(removed) > 0 {
+		p.server.DeleteResources(removed...)
+	}
+	if len(resources) > 0 {
+		p.server.AddResources(resources...)
+	}
+	up.resources = uris
+	return nil
+}
+
+func (p *Proxy) syncResourceTemplates(ctx context.Context, up *upstream) error {
+	up.syncMu.Lock()
+	defer up.syncMu.Unlock()
+
+	var upstreamTemplates []mcp.ResourceTemplate
+	if up.caps().Resources != nil {
+		var err error
+		if upstreamTemplates, err = up.client().ListResourceTemplatesAll(ctx); err != nil {
+			return err
+		}
+	}
+	templates := make([]server.ServerResourceTemplate, 0, len(upstreamTemplates))
+	raws := make([]string, 0, len(upstreamTemplates))
+	for _, template := range upstreamTemplates {
+		if template.URITemplate == nil {
+			continue
+		}
+		uriTemplate := &mcp.URITemplate{}
+		// Unmarshaling reports an invalid template as an error where
+		// mcp.NewResourceTemplate would panic.
+		raw := resourceURI(up, template.URITemplate.Raw())
+		quoted, _ := json.Marshal(raw)
+		if err := uriTemplate.UnmarshalJSON(quoted); err != nil {
+			return fmt.Errorf("resource template %s: %w", raw, err)
+		}
+		template.URITemplate = uriTemplate
+		templates = append(templates, server.ServerResourceTemplate{
+			Template: template,
+			Handler:  server.ResourceTemplateHandlerFunc(p.forwardResource(up)),
+		})
+		raws = append(raws, raw)
+	}
+	if removed := stale(up.templates, raws); len(removed) > 0 {
+		p.server.DeleteResourceTemplates(removed...)
+	}
+	if len(templates) > 0 {
+		p.server.AddResourceTemplates(templates...)
+	}
+	up.templates = raws
+	return nil
+}
@@ -0,0 +1,576 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/server/servertest"
+)
+
+type samplingHandler struct{}
+
+func (samplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: mcp.NewTextContent("sampled"),
+		},
+		Model: "test-model",
+	}, nil
+}
+
+// newUpstream serves an MCP server with a tool, a prompt, a resource and a
+// resource template over streamable HTTP, configured by opts.
+func newUpstream(t *testing.T, name string, opts ...server.ServerOption) (*server.MCPServer, Upstream) {
+	t.Helper()
+	s := newUpstreamServer(name, opts...)
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(s))
+	t.Cleanup(ts.Close)
+	tr, err := transport.NewStreamableHTTP(ts.URL+"/mcp", transport.WithContinuousListening())
+	require.NoError(t, err)
+	return s, Upstream{Name: name, Transport: tr}
+}
+
+// newCallStreamUpstream serves an upstream like newUpstream, but without a
+// listening stream, so what it sends while handling a call arrives on the
+// stream of the call, ahead of the result.
+func newCallStreamUpstream(t *testing.T, name string, opts ...server.ServerOption) (*server.MCPServer, Upstream) {
+	t.Helper()
+	s := newUpstreamServer(name, opts...)
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(s))
+	t.Cleanup(ts.Close)
+	tr, err := transport.NewStreamableHTTP(ts.URL + "/mcp")
+	require.NoError(t, err)
+	return s, Upstream{Name: name, Transport: tr}
+}
+
+// newUpstreamServer returns the MCP server served by newUpstream.
+func newUpstreamServer(name string, opts ...server.ServerOption) *server.MCPServer {
+	s := server.NewMCPServer(name, "1.0.0", append([]server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+		server.WithResourceCapabilities(true, true),
+	}, opts...)...)
+	s.EnableSampling()
+	s.AddTool(mcp.NewTool("echo", mcp.WithString("text")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(name + ":" + request.GetString("text", "")), nil
+	})
+	s.AddTool(mcp.NewTool("work"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if meta := request.Params.Meta; meta != nil && meta.ProgressToken != nil {
+			_ = server.ServerFromContext(ctx).SendNotificationToClient(ctx, string(mcp.MethodNotificationProgress), map[string]any{
+				"progressToken": meta.ProgressToken,
+				"progress":      1,
+				"total":         2,
+			})
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+	s.AddTool(mcp.NewTool("ask"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := server.ServerFromContext(ctx).RequestSampling(ctx, mcp.CreateMessageRequest{
+			CreateMessageParams: mcp.CreateMessageParams{
+				Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("question")}},
+				MaxTokens: 10,
+			},
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(result.Content.(mcp.TextContent).Text), nil
+	})
+	s.AddPrompt(mcp.NewPrompt("greet"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("greeting", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("hello from "+name)),
+		}), nil
+	})
+	s.AddResource(mcp.NewResource("file:///README.md", "readme"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "# " + name}}, nil
+	})
+	s.AddResourceTemplate(mcp.NewResourceTemplate("file:///docs/{page}", "docs"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "page"}}, nil
+	})
+	return s
+}
+
+// replica serves an MCP server over streamable HTTP like newUpstream, and
+// can be taken down and restarted.
+type replica struct {
+	mu      sync.Mutex
+	handler http.Handler // nil while down
+	server  *server.MCPServer
+}
+
+func newReplica(t *testing.T, name string) (*replica, transport.Interface) {
+	t.Helper()
+	r := &replica{server: newUpstreamServer(name)}
+	r.restart()
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	tr, err := transport.NewStreamableHTTP(ts.URL + "/mcp")
+	require.NoError(t, err)
+	return r, tr
+}
+
+func (r *replica) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	handler := r.handler
+	r.mu.Unlock()
+	if handler == nil {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+		return
+	}
+	handler.ServeHTTP(w, req)
+}
+
+func (r *replica) stop() {
+	r.mu.Lock()
+	r.handler = nil
+	r.mu.Unlock()
+}
+
+// restart brings the replica up without the sessions it had.
+func (r *replica) restart() {
+	r.mu.Lock()
+	r.handler = server.NewStreamableHTTPServer(r.server)
+	r.mu.Unlock()
+}
+
+// connect serves s over streamable HTTP and returns an initialized client.
+func connect(t *testing.T, s *server.MCPServer, opts ...client.ClientOption) *client.Client {
+	t.Helper()
+	ts := httptest.NewServer(server.NewStreamableHTTPServer(s))
+	t.Cleanup(ts.Close)
+	tr, err := transport.NewStreamableHTTP(ts.URL+"/mcp", transport.WithContinuousListening())
+	require.NoError(t, err)
+	c := client.NewClient(tr, opts...)
+	t.Cleanup(func() { _ = c.Close() })
+	require.NoError(t, c.Start(context.Background()))
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "downstream", Version: "1.0.0"}
+	_, err = c.Initialize(context.Background(), request)
+	require.NoError(t, err)
+	return c
+}
+
+func callText(t *testing.T, c *client.Client, name string, args map[string]any) string {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := c.CallTool(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+	require.Len(t, result.Content, 1)
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestProxy(t *testing.T) {
+	ctx := context.Background()
+	_, github := newUpstream(t, "github")
+	_, jira := newUpstream(t, "jira")
+
+	s := server.NewMCPServer("gateway", "1.0.0", server.WithResourceCapabilities(true, true))
+	p, err := New(ctx, s, []Upstream{github, jira})
+	require.NoError(t, err)
+	defer p.Close()
+	assert.NotNil(t, p.Client("github"))
+	assert.Nil(t, p.Client("gitlab"))
+
+	c := connect(t, s)
+
+	tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"github_echo", "github_work", "github_ask",
+		"jira_echo", "jira_work", "jira_ask",
+	}, names)
+
+	assert.Equal(t, "github:hi", callText(t, c, "github_echo", map[string]any{"text": "hi"}))
+	assert.Equal(t, "jira:hi", callText(t, c, "jira_echo", map[string]any{"text": "hi"}))
+
+	prompt := mcp.GetPromptRequest{}
+	prompt.Params.Name = "jira_greet"
+	promptResult, err := c.GetPrompt(ctx, prompt)
+	require.NoError(t, err)
+	require.Len(t, promptResult.Messages, 1)
+	assert.Equal(t, "hello from jira", promptResult.Messages[0].Content.(mcp.TextContent).Text)
+
+	resources, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+	require.NoError(t, err)
+	var uris []string
+	for _, resource := range resources.Resources {
+		uris = append(uris, resource.URI)
+	}
+	assert.ElementsMatch(t, []string{"github+file:///README.md", "jira+file:///README.md"}, uris)
+
+	read := mcp.ReadResourceRequest{}
+	read.Params.URI = "github+file:///README.md"
+	readResult, err := c.ReadResource(ctx, read)
+	require.NoError(t, err)
+	require.Len(t, readResult.Contents, 1)
+	content := readResult.Contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, "github+file:///README.md", content.URI)
+	assert.Equal(t, "# github", content.Text)
+
+	read.Params.URI = "jira+file:///docs/intro"
+	readResult, err = c.ReadResource(ctx, read)
+	require.NoError(t, err)
+	require.Len(t, readResult.Contents, 1)
+	assert.Equal(t, "jira+file:///docs/intro", readResult.Contents[0].(mcp.TextResourceContents).URI)
+}
+
+func TestProxy_PaginatedUpstream(t *testing.T) {
+	ctx := context.Background()
+	upstreamServer, github := newUpstream(t, "github", server.WithPaginationLimit(1))
+	fixtures, err := servertest.LoadFixtures("testdata/fixtures")
+	require.NoError(t, err)
+	fixtures.Register(upstreamServer)
+	upstreamServer.AddResourceTemplate(mcp.NewResourceTemplate("file:///src/{path}", "src"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	})
+
+	s := server.NewMCPServer("gateway", "1.0.0")
+	p, err := New(ctx, s, []Upstream{github})
+	require.NoError(t, err)
+	defer p.Close()
+
+	assert.Len(t, s.ListTools(), 3)
+	c := connect(t, s)
+	prompts, err := c.ListPromptsAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, prompts, 2)
+	resources, err := c.ListResourcesAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, resources, 2)
+	templates, err := c.ListResourceTemplatesAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, templates, 2)
+}
+
+func TestProxy_Progress(t *testing.T) {
+	ctx := context.Background()
+	_, github := newCallStreamUpstream(t, "github")
+
+	s := server.NewMCPServer("gateway", "1.0.0")
+	p, err := New(ctx, s, []Upstream{github})
+	require.NoError(t, err)
+	defer p.Close()
+
+	c := connect(t, s)
+	var mu sync.Mutex
+	var progress []mcp.JSONRPCNotification
+	c.OnNotification(func(n mcp.JSONRPCNotification) {
+		if n.Method == string(mcp.MethodNotificationProgress) {
+			mu.Lock()
+			progress = append(progress, n)
+			mu.Unlock()
+		}
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "github_work"
+	request.Params.Meta = &mcp.Meta{ProgressToken: "downstream-token"}
+	_, err = c.CallTool(ctx, request)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(progress) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "downstream-token", progress[0].Params.AdditionalFields["progressToken"])
+	assert.EqualValues(t, 1, progress[0].Params.AdditionalFields["progress"])
+}
+
+func TestProxy_Sampling(t *testing.T) {
+	_, github := newUpstream(t, "github")
+
+	s := server.NewMCPServer("gateway", "1.0.0")
+	p, err := New(context.Background(), s, []Upstream{github})
+	require.NoError(t, err)
+	defer p.Close()
+
+	c := connect(t, s, client.WithSamplingHandler(samplingHandler{}))
+	assert.Equal(t, "sampled", callText(t, c, "github_ask", nil))
+}
+
+// testSession is a downstream client session whose notifications the test
+// reads.
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) SessionID() string                                   { return "a" }
+
+func TestProxy_DropsProgressAfterCall(t *testing.T) {
+	s := server.NewMCPServer("gateway", "1.0.0")
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 1)}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	p := &Proxy{server: s, progress: progressRouter{routes: make(map[string]progressRoute)}}
+	up := &upstream{name: "github", calls: make(map[*call]struct{})}
+
+	token, release := p.progress.route(s.WithContext(context.Background(), session), "downstream-token")
+	progress := func() mcp.JSONRPCNotification {
+		return mcp.JSONRPCNotification{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Notification: mcp.Notification{
+				Method: string(mcp.MethodNotificationProgress),
+				Params: mcp.NotificationParams{AdditionalFields: map[string]any{"progressToken": token, "progress": 1}},
+			},
+		}
+	}
+
+	p.handleNotification(up, progress())
+	select {
+	case n := <-session.notifications:
+		assert.Equal(t, "downstream-token", n.Params.AdditionalFields["progressToken"])
+	default:
+		t.Fatal("progress during the call was not forwarded")
+	}
+
+	release()
+	p.handleNotification(up, progress())
+	select {
+	case n := <-session.notifications:
+		t.Fatalf("progress after the call was forwarded: %v", n)
+	default:
+	}
+}
+
+func TestProxy_RoutesLogMessages(t *testing.T) {
+	ctx := context.Background()
+	// The log sent during the call must arrive while the call is in flight;
+	// the one sent outside a call needs a listening stream.
+	upstreamServer, github := newCallStreamUpstream(t, "github", server.WithLogging())
+	gitlabServer, gitlab := newUpstream(t, "gitlab", server.WithLogging())
+	upstreamServer.AddTool(mcp.NewTool("chatty"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		err := server.ServerFromContext(ctx).SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(mcp.LoggingLevelError, "", "working"))
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	s := server.NewMCPServer("gateway", "1.0.0", server.WithLogging())
+	p, err := New(ctx, s, []Upstream{github, gitlab})
+	require.NoError(t, err)
+	defer p.Close()
+
+	logs := func(c *client.Client) func() []string {
+		var mu sync.Mutex
+		var data []string
+		c.OnNotification(func(n mcp.JSONRPCNotification) {
+			if n.Method == string(mcp.MethodNotificationMessage) {
+				mu.Lock()
+				data = append(data, n.Params.AdditionalFields["logger"].(string)+": "+n.Params.AdditionalFields["data"].(string))
+				mu.Unlock()
+			}
+		})
+		return func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]string(nil), data...)
+		}
+	}
+	caller, other, quiet := connect(t, s), connect(t, s), connect(t, s)
+	callerLogs, otherLogs, quietLogs := logs(caller), logs(other), logs(quiet)
+	setLevel := mcp.SetLevelRequest{}
+	setLevel.Params.Level = mcp.LoggingLevelEmergency
+	require.NoError(t, quiet.SetLevel(ctx, setLevel))
+
+	assert.Equal(t, "done", callText(t, caller, "github_chatty", nil))
+	require.Eventually(t, func() bool { return len(callerLogs()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"github: working"}, callerLogs())
+
+	// Without a call in flight the message goes to every client whose
+	// level admits it.
+	gitlabServer.SendNotificationToAllClients(string(mcp.MethodNotificationMessage), map[string]any{
+		"level": mcp.LoggingLevelError,
+		"data":  "restarting",
+	})
+	require.Eventually(t, func() bool {
+		return len(otherLogs()) == 1 && len(callerLogs()) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"gitlab: restarting"}, otherLogs())
+	assert.Equal(t, []string{"github: working", "gitlab: restarting"}, callerLogs())
+	assert.Empty(t, quietLogs())
+}
+
+func TestUpstream_SoleCaller(t *testing.T) {
+	s := server.NewMCPServer("gateway", "1.0.0")
+	sessionCtx := func(id string) context.Context {
+		return s.WithContext(context.Background(), server.NewInProcessSession(id, nil))
+	}
+	up := &upstream{calls: make(map[*call]struct{})}
+
+	_, endA := up.begin(sessionCtx("a"))
+	_, endA2 := up.begin(sessionCtx("a"))
+	_, ok := up.soleCaller()
+	assert.True(t, ok, "calls of one client")
+
+	_, endB := up.begin(sessionCtx("b"))
+	_, ok = up.soleCaller()
+	assert.False(t, ok, "calls of two clients")
+	endA()
+	endA2()
+	endB()
+
+	_, endAnon := up.begin(context.Background())
+	_, ok = up.soleCaller()
+	assert.False(t, ok, "a call without a session cannot be attributed")
+	endAnon()
+}
+
+func TestProxy_ResyncsOnListChanged(t *testing.T) {
+	ctx := context.Background()
+	upstreamServer, github := newUpstream(t, "github")
+
+	s := server.NewMCPServer("gateway", "1.0.0", server.WithToolCapabilities(true))
+	p, err := New(ctx, s, []Upstream{github})
+	require.NoError(t, err)
+	defer p.Close()
+
+	upstreamServer.DeleteTools("work")
+	upstreamServer.AddTool(mcp.NewTool("search"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("found"), nil
+	})
+	require.Eventually(t, func() bool {
+		return s.GetTool("github_search") != nil && s.GetTool("github_work") == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.NotNil(t, s.GetTool("github_echo"))
+}
+
+func TestProxy_ToolCollisions(t *testing.T) {
+	ctx := context.Background()
+	local := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("local"), nil
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		upstreamServer, github := newUpstream(t, "github")
+		s := server.NewMCPServer("gateway", "1.0.0",
+			server.WithToolCapabilities(true),
+			server.WithToolCollisionPolicy(server.ToolCollisionPrefix),
+		)
+		s.AddTool(mcp.NewTool("github_echo"), local)
+		p, err := New(ctx, s, []Upstream{github})
+		require.NoError(t, err)
+		defer p.Close()
+
+		c := connect(t, s)
+		assert.Equal(t, "local", callText(t, c, "github_echo", nil))
+		assert.Equal(t, "github:hi", callText(t, c, "github_github_echo", map[string]any{"text": "hi"}))
+
+		// The prefixed name is the one removed when the upstream drops the tool.
+		upstreamServer.DeleteTools("echo")
+		require.Eventually(t, func() bool {
+			return s.GetTool("github_github_echo") == nil
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.NotNil(t, s.GetTool("github_echo"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		upstreamServer, github := newUpstream(t, "github")
+		s := server.NewMCPServer("gateway", "1.0.0",
+			server.WithToolCapabilities(true),
+			server.WithToolCollisionPolicy(server.ToolCollisionError),
+		)
+		s.AddTool(mcp.NewTool("github_echo"), local)
+		p, err := New(ctx, s, []Upstream{github})
+		require.NoError(t, err)
+		defer p.Close()
+
+		c := connect(t, s)
+		assert.Equal(t, "local", callText(t, c, "github_echo", nil))
+		assert.NotNil(t, s.GetTool("github_work"))
+
+		// A resynchronization hitting the collision again keeps running.
+		upstreamServer.AddTool(mcp.NewTool("search"), local)
+		require.Eventually(t, func() bool {
+			return s.GetTool("github_search") != nil
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "local", callText(t, c, "github_echo", nil))
+	})
+}
+
+func TestProxy_Failover(t *testing.T) {
+	ctx := context.Background()
+	primary, primaryTransport := newReplica(t, "primary")
+	backup, backupTransport := newReplica(t, "backup")
+
+	s := server.NewMCPServer("gateway", "1.0.0", server.WithToolCapabilities(true))
+	p, err := New(ctx, s, []Upstream{{
+		Name:      "github",
+		Transport: primaryTransport,
+		Backups:   []transport.Interface{backupTransport},
+	}}, WithHealthCheckInterval(20*time.Millisecond))
+	require.NoError(t, err)
+	defer p.Close()
+
+	c := connect(t, s)
+	assert.Equal(t, "primary:hi", callText(t, c, "github_echo", map[string]any{"text": "hi"}))
+	primaryClient := p.Client("github")
+
+	// The backup takes over with its own entries.
+	backup.server.DeleteTools("work")
+	primary.stop()
+	require.Eventually(t, func() bool {
+		return p.Client("github") != primaryClient && s.GetTool("github_work") == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "backup:hi", callText(t, c, "github_echo", map[string]any{"text": "hi"}))
+
+	// The primary comes back without its session and takes over again once
+	// the backup fails.
+	primary.restart()
+	backup.stop()
+	require.Eventually(t, func() bool {
+		return p.Client("github") == primaryClient && s.GetTool("github_work") != nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "primary:hi", callText(t, c, "github_echo", map[string]any{"text": "hi"}))
+}
+
+func TestProxy_StartsOnBackup(t *testing.T) {
+	ctx := context.Background()
+	primary, primaryTransport := newReplica(t, "primary")
+	_, backupTransport := newReplica(t, "backup")
+	primary.stop()
+
+	s := server.NewMCPServer("gateway", "1.0.0")
+	p, err := New(ctx, s, []Upstream{{
+		Name:      "github",
+		Transport: primaryTransport,
+		Backups:   []transport.Interface{backupTransport},
+	}}, WithHealthCheckInterval(0))
+	require.NoError(t, err)
+	defer p.Close()
+
+	c := connect(t, s)
+	assert.Equal(t, "backup:hi", callText(t, c, "github_echo", map[string]any{"text": "hi"}))
+}
+
+func TestNew_InvalidUpstreams(t *testing.T) {
+	s := server.NewMCPServer("gateway", "1.0.0")
+	_, err := New(context.Background(), s, []Upstream{{}})
+	assert.Error(t, err)
+
+	_, github := newUpstream(t, "github")
+	_, err = New(context.Background(), s, []Upstream{github, {Name: "github"}})
+	assert.ErrorContains(t, err, "duplicate upstream")
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrRateLimited is wrapped by the errors of requests rejected by
+// WithRateLimiter.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitGroup is a group of methods sharing one rate limit.
+type RateLimitGroup string
+
+const (
+	// RateLimitTools covers the tools/* requests of the client.
+	RateLimitTools RateLimitGroup = "tools"
+	// RateLimitResources covers the resources/* requests of the client.
+	RateLimitResources RateLimitGroup = "resources"
+	// RateLimitPrompts covers the prompts/* requests of the client.
+	RateLimitPrompts RateLimitGroup = "prompts"
+	// RateLimitSampling covers the sampling requests the server sends with
+	// RequestSampling.
+	RateLimitSampling RateLimitGroup = "sampling"
+)
+
+// rateLimitGroup returns the group of a client request method, or "" for
+// methods that are never limited, such as initialize and ping.
+func rateLimitGroup(method string) RateLimitGroup {
+	prefix, _, _ := strings.Cut(method, "/")
+	switch group := RateLimitGroup(prefix); group {
+	case RateLimitTools, RateLimitResources, RateLimitPrompts:
+		return group
+	}
+	return ""
+}
+
+// RateLimiter admits or rejects requests. Implementations must be safe for
+// concurrent use.
+type RateLimiter interface {
+	// Allow reports whether one more request may proceed now and, if not,
+	// how long the caller should wait before retrying.
+	Allow() (ok bool, retryAfter time.Duration)
+}
+
+// RateLimiterFactory returns the limiter for a method group and key, such
+// as a session ID. It is called once per group and key; returning nil
+// leaves that group unlimited for the key.
+type RateLimiterFactory func(group RateLimitGroup, key string) RateLimiter
+
+// RateLimit is a token bucket budget: Burst requests may pass at once,
+// refilled at Rate requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// TokenBucketLimits returns a RateLimiterFactory giving every key its own
+// token bucket with the budget of the group. Groups missing from limits are
+// not limited.
+func TokenBucketLimits(limits map[RateLimitGroup]RateLimit) RateLimiterFactory {
+	return func(group RateLimitGroup, _ string) RateLimiter {
+		limit, ok := limits[group]
+		if !ok {
+			return nil
+		}
+		return NewTokenBucket(limit.Rate, limit.Burst)
+	}
+}
+
+// NewTokenBucket returns a RateLimiter that allows burst requests at once
+// and then rate requests per second.
+func NewTokenBucket(rate float64, burst int) RateLimiter {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if b.rate <= 0 {
+		return false, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// RateLimitKeyFunc returns the key requests are limited by. Requests with
+// the same key share a limiter for each group.
+type RateLimitKeyFunc func(ctx context.Context) string
+
+// RateLimitBySession keys rate limits by session ID. It is the default.
+func RateLimitBySession(ctx context.Context) string {
+	if session := ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// RateLimitByClient keys rate limits by the name the client gave in its
+// initialize request, so that all sessions of one client application share
+// a budget. Sessions that do not track client info fall back to their
+// session ID.
+//
+// The name is whatever the client claims, so it can be spoofed: a client
+// can exhaust the budget of another application by taking its name, or
+// escape its own by changing names. Use it to share budgets between
+// cooperating clients, and RateLimitByIdentity to enforce limits on
+// untrusted ones.
+func RateLimitByClient(ctx context.Context) string {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return ""
+	}
+	if withInfo, ok := session.(SessionWithClientInfo); ok {
+		if name := withInfo.GetClientInfo().Name; name != "" {
+			return "client:" + name
+		}
+	}
+	return session.SessionID()
+}
+
+// RateLimitByIdentity keys rate limits by the subject of the caller's
+// identity (see WithAuthenticator), so that a caller shares one budget
+// across sessions. Unauthenticated requests fall back to their session ID.
+func RateLimitByIdentity(ctx context.Context) string {
+	if identity, ok := IdentityFromContext(ctx); ok && identity.Subject != "" {
+		return "identity:" + identity.Subject
+	}
+	return RateLimitBySession(ctx)
+}
+
+// RateLimitOption configures WithRateLimiter.
+type RateLimitOption func(*rateLimiters)
+
+// DefaultRateLimitIdleTTL is how long WithRateLimiter keeps the limiters of
+// a key after its last request.
+const DefaultRateLimitIdleTTL = 10 * time.Minute
+
+// WithRateLimitIdleTTL sets how long the limiters of a key are kept after
+// its last request. Limiters keyed by session are dropped with the
+// session, but those keyed by client or identity are only dropped once
+// idle, so that keys that are never seen again do not accumulate. Evicting
+// a limiter resets its budget, so ttl should exceed the time a limiter
+// takes to recover, such as Burst/Rate for a token bucket. A non-positive
+// ttl keeps limiters until their session ends. Defaults to
+// DefaultRateLimitIdleTTL.
+func WithRateLimitIdleTTL(ttl time.Duration) RateLimitOption {
+	return func(r *rateLimiters) {
+		r.idleTTL = ttl
+	}
+}
+
+// WithRateLimitKey sets the function that keys rate limits. The default is
+// RateLimitBySession.
+func WithRateLimitKey(key RateLimitKeyFunc) RateLimitOption {
+	return func(r *rateLimiters) {
+		r.key = key
+	}
+}
+
+// RateLimitError reports a request rejected by WithRateLimiter. It wraps
+// ErrRateLimited. Client requests that exceed their limit fail with the
+// mcp.RATE_LIMITED error code and the error as data; RequestSampling
+// returns it, and a tool handler returning it fails the tools/call request
+// the same way.
+type RateLimitError struct {
+	Group RateLimitGroup
+	Key   string
+	// RetryAfter is how long to wait before retrying; 0 if unknown.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s for %s, retry after %s", ErrRateLimited, e.Group, e.RetryAfter.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("%s for %s", ErrRateLimited, e.Group)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// MarshalJSON encodes the error data of mcp.RATE_LIMITED errors. The key is
+// not included, and retryAfter is in whole seconds, rounded up.
+func (e *RateLimitError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Group      RateLimitGroup `json:"group"`
+		RetryAfter int64          `json:"retryAfter"`
+	}{e.Group, int64(math.Ceil(e.RetryAfter.Seconds()))})
+}
+
+// rateLimiters holds the limiters created by the factory, per group and key.
+type rateLimiters struct {
+	factory  RateLimiterFactory
+	key      RateLimitKeyFunc
+	idleTTL  time.Duration
+	now      func() time.Time
+	limiters sync.Map     // rateLimiterKey -> *rateLimiterEntry
+	swept    atomic.Int64 // unix nanos of the last eviction of idle limiters
+}
+
+type rateLimiterEntry struct {
+	limiter RateLimiter // nil for unlimited
+	used    atomic.Int64
+}
+
+type rateLimiterKey struct {
+	group RateLimitGroup
+	key   string
+}
+
+// WithRateLimiter limits the rate of client requests per method group and
+// key, with limiters from factory; see TokenBucketLimits. Requests of the
+// tools, resources and prompts groups are limited; other requests, such as
+// initialize and ping, always pass. Sampling requests the server sends with
+// RequestSampling are limited as the RateLimitSampling group.
+//
+// Requests are keyed by session unless WithRateLimitKey says otherwise.
+// Requests without a key, such as those of stateless servers, share one
+// limiter per group.
+func WithRateLimiter(factory RateLimiterFactory, opts ...RateLimitOption) ServerOption {
+	return func(s *MCPServer) {
+		r := &rateLimiters{factory: factory, key: RateLimitBySession, idleTTL: DefaultRateLimitIdleTTL, now: s.now}
+		for _, opt := range opts {
+			opt(r)
+		}
+		s.rateLimiters = r
+		s.requestMiddlewares = append(s.requestMiddlewares, r.middleware)
+	}
+}
+
+// allow returns a *RateLimitError if the request of group in ctx exceeds
+// its limit. A nil receiver allows everything.
+func (r *rateLimiters) allow(ctx context.Context, group RateLimitGroup) error {
+	if r == nil || group == "" {
+		return nil
+	}
+	now := r.now()
+	r.evictIdle(now)
+	k := rateLimiterKey{group: group, key: r.key(ctx)}
+	v, ok := r.limiters.Load(k)
+	if !ok {
+		v, _ = r.limiters.LoadOrStore(k, &rateLimiterEntry{limiter: r.factory(group, k.key)})
+	}
+	entry := v.(*rateLimiterEntry)
+	entry.used.Store(now.UnixNano())
+	if entry.limiter == nil {
+		return nil
+	}
+	if ok, retryAfter := entry.limiter.Allow(); !ok {
+		return &RateLimitError{Group: group, Key: k.key, RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (r *rateLimiters) middleware(next RequestHandler) RequestHandler {
+	return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+		var limitErr *RateLimitError
+		if err := r.allow(ctx, rateLimitGroup(request.Method)); errors.As(err, &limitErr) {
+			return mcp.NewJSONRPCError(request.ID, mcp.RATE_LIMITED, err.Error(), limitErr)
+		}
+		return next(ctx, request)
+	}
+}
+
+// evictIdle drops the limiters unused for the idle TTL, at most once per
+// TTL.
+func (r *rateLimiters) evictIdle(now time.Time) {
+	if r.idleTTL <= 0 {
+		return
+	}
+	swept := r.swept.Load()
+	if now.UnixNano()-swept < int64(r.idleTTL) || !r.swept.CompareAndSwap(swept, now.UnixNano()) {
+		return
+	}
+	cutoff := now.Add(-r.idleTTL).UnixNano()
+	r.limiters.Range(func(k, v any) bool {
+		if v.(*rateLimiterEntry).used.Load() < cutoff {
+			r.limiters.CompareAndDelete(k, v)
+		}
+		return true
+	})
+}
+
+// forget drops the limiters of a key, once its session is gone.
+func (r *rateLimiters) forget(key string) {
+	if r == nil {
+		return
+	}
+	r.limiters.Range(func(k, _ any) bool {
+		if k.(rateLimiterKey).key == key {
+			r.limiters.Delete(k)
+		}
+		return true
+	})
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRateLimiter(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0",
+		WithToolCapabilities(false),
+		WithRateLimiter(TokenBucketLimits(map[RateLimitGroup]RateLimit{
+			RateLimitTools: {Rate: 1, Burst: 2},
+		})),
+	)
+	s.AddTool(mcp.NewTool("echo"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(session ClientSession, method string) mcp.JSONRPCMessage {
+		ctx := s.WithContext(t.Context(), session)
+		return s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"`+method+`","params":{"name":"echo"}}`))
+	}
+	a := fakeSession{sessionID: "a", initialized: true}
+	b := fakeSession{sessionID: "b", initialized: true}
+	require.NoError(t, s.RegisterSession(t.Context(), a))
+
+	require.IsType(t, mcp.JSONRPCResponse{}, call(a, "tools/call"))
+	require.IsType(t, mcp.JSONRPCResponse{}, call(a, "tools/list"))
+
+	resp := call(a, "tools/call")
+	require.IsType(t, mcp.JSONRPCError{}, resp)
+	rpcErr := resp.(mcp.JSONRPCError).Error
+	assert.Equal(t, mcp.RATE_LIMITED, rpcErr.Code)
+	data, err := json.Marshal(rpcErr.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"group":"tools","retryAfter":1}`, string(data))
+
+	assert.IsType(t, mcp.JSONRPCResponse{}, call(a, "ping"), "ungrouped methods are not limited")
+	assert.IsType(t, mcp.JSONRPCResponse{}, call(b, "tools/call"), "sessions have their own budget")
+
+	s.UnregisterSession(t.Context(), "a")
+	assert.IsType(t, mcp.JSONRPCResponse{}, call(a, "tools/call"), "limiters are dropped with the session")
+}
+
+func TestWithRateLimiter_Sampling(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0",
+		WithRateLimiter(TokenBucketLimits(map[RateLimitGroup]RateLimit{
+			RateLimitSampling: {Rate: 0, Burst: 1},
+		})),
+	)
+	s.EnableSampling()
+	session := &mockSamplingSession{
+		mockSession: mockSession{sessionID: "s"},
+		result:      &mcp.CreateMessageResult{Model: "test-model"},
+	}
+	ctx := s.WithContext(t.Context(), session)
+
+	_, err := s.RequestSampling(ctx, mcp.CreateMessageRequest{})
+	require.NoError(t, err)
+	_, err = s.RequestSampling(ctx, mcp.CreateMessageRequest{})
+	var limitErr *RateLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, RateLimitSampling, limitErr.Group)
+	assert.Equal(t, "s", limitErr.Key)
+}
+
+func TestRateLimitByClient(t *testing.T) {
+	session := &sessionTestClientWithClientInfo{sessionID: "s1"}
+	ctx := context.WithValue(t.Context(), clientSessionKey{}, session)
+	assert.Equal(t, "s1", RateLimitByClient(ctx))
+
+	session.SetClientInfo(mcp.Implementation{Name: "inspector"})
+	assert.Equal(t, "client:inspector", RateLimitByClient(ctx))
+	assert.Empty(t, RateLimitByClient(t.Context()))
+}
+
+func TestRateLimitByIdentity(t *testing.T) {
+	ctx := context.WithValue(t.Context(), clientSessionKey{}, &sessionTestClientWithClientInfo{sessionID: "s1"})
+	assert.Equal(t, "s1", RateLimitByIdentity(ctx))
+
+	ctx = context.WithValue(ctx, identityKey{}, Identity{Subject: "alice"})
+	assert.Equal(t, "identity:alice", RateLimitByIdentity(ctx))
+}
+
+func TestWithRateLimiter_EvictsIdleKeys(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewMCPServer("test", "1.0.0",
+		WithClock(clock),
+		WithRateLimiter(TokenBucketLimits(map[RateLimitGroup]RateLimit{
+			RateLimitTools: {Rate: 0.01, Burst: 1},
+		}), WithRateLimitKey(RateLimitByIdentity), WithRateLimitIdleTTL(time.Minute)),
+	)
+	allow := func(subject string) error {
+		ctx := context.WithValue(t.Context(), identityKey{}, Identity{Subject: subject})
+		return s.rateLimiters.allow(ctx, RateLimitTools)
+	}
+	keys := func() []string {
+		var keys []string
+		s.rateLimiters.limiters.Range(func(k, _ any) bool {
+			keys = append(keys, k.(rateLimiterKey).key)
+			return true
+		})
+		return keys
+	}
+
+	require.NoError(t, allow("alice"))
+	require.NoError(t, allow("bob"))
+	clock.Advance(30 * time.Second)
+	require.ErrorIs(t, allow("alice"), ErrRateLimited)
+	assert.ElementsMatch(t, []string{"identity:alice", "identity:bob"}, keys())
+
+	clock.Advance(45 * time.Second)
+	require.ErrorIs(t, allow("alice"), ErrRateLimited)
+	assert.Equal(t, []string{"identity:alice"}, keys(), "keys idle for the TTL are evicted")
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := NewTokenBucket(10, 1)
+	ok, _ := b.Allow()
+	require.True(t, ok)
+	ok, retryAfter := b.Allow()
+	require.False(t, ok)
+	assert.InDelta(t, 100*time.Millisecond, retryAfter, float64(10*time.Millisecond))
+
+	time.Sleep(retryAfter)
+	ok, _ = b.Allow()
+	assert.True(t, ok)
+}
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReplayRecord is a captured tool call, along with the outcome recorded at
+// the time when it is known.
+type ReplayRecord struct {
+	SessionID string
+	Timestamp time.Time
+	Request   mcp.CallToolRequest
+	// Result is the recorded result, if the call succeeded and its response
+	// was captured.
+	Result *mcp.CallToolResult
+	// Error is the recorded JSON-RPC error, if the call failed and its
+	// response was captured.
+	Error *mcp.JSONRPCErrorDetails
+}
+
+// ReplayReport describes the outcome of replaying a ReplayRecord.
+type ReplayReport struct {
+	Record ReplayRecord
+	// DryRun reports whether the tool handler was skipped.
+	DryRun bool
+	// Result is the replayed result. It is nil on dry runs and on errors.
+	Result *mcp.CallToolResult
+	// Error is the JSON-RPC error the server answered with, or, on dry
+	// runs, the error it would have answered with before calling the
+	// handler.
+	Error    *mcp.JSONRPCErrorDetails
+	Duration time.Duration
+	// Diverged reports whether the replayed outcome differs from the
+	// recorded one. It is always false on dry runs and when no outcome was
+	// recorded.
+	Diverged bool
+}
+
+// ReplayOption configures ReplayToolCall.
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	dryRun  bool
+	compare func(recorded, replayed *mcp.CallToolResult) bool
+}
+
+// WithReplayDryRun only checks that the call would reach its handler: the
+// tool exists, passes the tool filters, and its arguments match the input
+// schema. The handler is not called, so dry runs have no side effects.
+func WithReplayDryRun() ReplayOption {
+	return func(c *replayConfig) {
+		c.dryRun = true
+	}
+}
+
+// WithReplayComparator sets the function deciding whether a replayed
+// result matches the recorded one. The default compares the JSON encoding of
+// the content, structured content and error flag.
+func WithReplayComparator(compare func(recorded, replayed *mcp.CallToolResult) bool) ReplayOption {
+	return func(c *replayConfig) {
+		c.compare = compare
+	}
+}
+
+// ReplayToolCall re-invokes a captured tool call against the server, for
+// example to debug a reported failure or to regression-test handler changes
+// against real traffic. The call goes through the same path as a call from a
+// client, including request middleware, hooks and validation; pass a context
+// carrying a ClientSession (see WithContext) when handlers depend on one.
+// Task-augmented calls are waited for until the task completes.
+func (s *MCPServer) ReplayToolCall(ctx context.Context, record ReplayRecord, opts ...ReplayOption) ReplayReport {
+	cfg := replayConfig{compare: defaultShadowCompare}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	report := ReplayReport{Record: record, DryRun: cfg.dryRun}
+	start := s.now()
+	if cfg.dryRun {
+		report.Error = s.checkToolCall(ctx, record.Request)
+		report.Duration = s.now().Sub(start)
+		return report
+	}
+
+	report.Result, report.Error = s.replayToolCall(ctx, record.Request)
+	report.Duration = s.now().Sub(start)
+	switch {
+	case record.Result != nil:
+		report.Diverged = report.Error != nil || !cfg.compare(record.Result, report.Result)
+	case record.Error != nil:
+		report.Diverged = report.Error == nil || report.Error.Code != record.Error.Code
+	}
+	return report
+}
+
+// replayToolCall sends request through HandleMessage and returns its
+// result, waiting for task-augmented calls to complete.
+func (s *MCPServer) replayToolCall(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, *mcp.JSONRPCErrorDetails) {
+	message, err := json.Marshal(mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId("replay"),
+		Request: mcp.Request{Method: string(mcp.MethodToolsCall)},
+		Params:  request.Params,
+	})
+	if err != nil {
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.INVALID_REQUEST, Message: err.Error()}
+	}
+
+	switch resp := s.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCError:
+		return nil, &resp.Error
+	case mcp.JSONRPCResponse:
+		switch result := resp.Result.(type) {
+		case *mcp.CallToolResult:
+			return result, nil
+		case *mcp.CreateTaskResult:
+			return s.awaitReplayedTask(ctx, result.Task.TaskId)
+		}
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: fmt.Sprintf("unexpected tools/call result %T", resp.Result)}
+	default:
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: fmt.Sprintf("unexpected tools/call response %T", resp)}
+	}
+}
+
+// awaitReplayedTask waits for a task started by a replayed call and returns
+// its outcome as a tool call result.
+func (s *MCPServer) awaitReplayedTask(ctx context.Context, taskID string) (*mcp.CallToolResult, *mcp.JSONRPCErrorDetails) {
+	entry, err := s.getTaskEntry(ctx, taskID)
+	if err != nil {
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: err.Error()}
+	}
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.REQUEST_INTERRUPTED, Message: ctx.Err().Error()}
+	}
+
+	s.tasksMu.RLock()
+	result, resultErr := entry.result, entry.resultErr
+	s.tasksMu.RUnlock()
+	if quotaErr, ok := samplingQuotaData(resultErr); ok {
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.SAMPLING_QUOTA_EXCEEDED, Message: resultErr.Error(), Data: quotaErr}
+	}
+	if resultErr != nil {
+		return nil, &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: resultErr.Error()}
+	}
+	switch result := result.(type) {
+	case *mcp.CallToolResult:
+		return result, nil
+	case *mcp.CreateTaskResult:
+		return &mcp.CallToolResult{
+			Result:            result.Result,
+			Content:           result.Content,
+			StructuredContent: result.StructuredContent,
+			IsError:           result.IsError,
+		}, nil
+	}
+	return nil, &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: fmt.Sprintf("unexpected task result %T", result)}
+}
+
+// checkToolCall performs the checks handleToolCall runs before calling the
+// handler, returning the error the call would fail with.
+func (s *MCPServer) checkToolCall(ctx context.Context, request mcp.CallToolRequest) *mcp.JSONRPCErrorDetails {
+	name := request.Params.Name
+	var tool mcp.Tool
+	var ok, sessionTool bool
+	if session, isToolSession := ClientSessionFromContext(ctx).(SessionWithTools); isToolSession {
+		var serverTool ServerTool
+		serverTool, ok = session.GetSessionTools()[name]
+		tool, sessionTool = serverTool.Tool, ok
+	}
+	if !ok {
+		s.toolsMu.RLock()
+		if serverTool, found := s.tools[name]; found {
+			tool, ok = serverTool.Tool, true
+		} else if taskTool, found := s.taskTools[name]; found {
+			tool, ok = taskTool.Tool, true
+		}
+		s.toolsMu.RUnlock()
+	}
+	if !ok || !s.passesToolFilters(ctx, tool) {
+		err := fmt.Errorf("tool '%s' not found: %w", name, ErrToolNotFound)
+		return &mcp.JSONRPCErrorDetails{Code: mcp.INVALID_PARAMS, Message: err.Error()}
+	}
+
+	if !sessionTool {
+		if err := s.toolDisabledError(nil, name); err != nil {
+			details := err.ToJSONRPCError().Error
+			return &details
+		}
+	}
+
+	if tool.Execution != nil && tool.Execution.TaskSupport == mcp.TaskSupportRequired && request.Params.Task == nil {
+		return &mcp.JSONRPCErrorDetails{Code: mcp.METHOD_NOT_FOUND, Message: fmt.Sprintf("tool '%s' requires task augmentation", name)}
+	}
+
+	// Dry runs always validate the arguments, even when the server does not
+	// enforce input schemas.
+	validator := s.inputValidator
+	if validator == nil {
+		validator = newInputSchemaValidator()
+	}
+	if _, err := validator.validate(tool, request.Params.Arguments); err != nil {
+		return &mcp.JSONRPCErrorDetails{Code: mcp.INVALID_PARAMS, Message: err.Error()}
+	}
+	return nil
+}
+
+// ReadReplayRecords reads the tool calls of a transcript, one JSON message
+// per line. Each line is either a DebugMessage, as streamed by the debug
+// endpoint, or a bare JSON-RPC message. Responses are matched to their
+// requests by session and request ID to fill in the recorded outcome; other
+// messages are ignored.
+func ReadReplayRecords(r io.Reader) ([]ReplayRecord, error) {
+	type pendingKey struct {
+		sessionID string
+		id        string
+	}
+	var records []ReplayRecord
+	pending := make(map[pendingKey]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+		var debug DebugMessage
+		if err := json.Unmarshal(data, &debug); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if debug.Message == nil {
+			// A bare JSON-RPC message.
+			debug = DebugMessage{Message: json.RawMessage(data)}
+		}
+
+		var msg struct {
+			ID     *mcp.RequestId           `json:"id"`
+			Method string                   `json:"method"`
+			Params json.RawMessage          `json:"params"`
+			Result json.RawMessage          `json:"result"`
+			Error  *mcp.JSONRPCErrorDetails `json:"error"`
+		}
+		if err := json.Unmarshal(debug.Message, &msg); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if msg.ID == nil {
+			continue
+		}
+		key := pendingKey{sessionID: debug.SessionID, id: msg.ID.String()}
+
+		switch {
+		case msg.Method == string(mcp.MethodToolsCall) && debug.Direction != DebugDirectionServerToClient:
+			record := ReplayRecord{SessionID: debug.SessionID, Timestamp: debug.Timestamp}
+			record.Request.Method = msg.Method
+			if err := json.Unmarshal(msg.Params, &record.Request.Params); err != nil {
+				return nil, fmt.Errorf("line %d: invalid tools/call params: %w", line, err)
+			}
+			pending[key] = len(records)
+			records = append(records, record)
+		case msg.Method == "" && debug.Direction != DebugDirectionClientToServer:
+			i, ok := pending[key]
+			if !ok {
+				continue
+			}
+			delete(pending, key)
+			if msg.Error != nil {
+				records[i].Error = msg.Error
+				continue
+			}
+			// Task-augmented calls answer with a task rather than a
+			// result; their outcome is left unrecorded.
+			if result, err := mcp.ParseCallToolResult(&msg.Result); err == nil {
+				records[i].Result = result
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("transcript line too long: %w", err)
+		}
+		return nil, err
+	}
+	return records, nil
+}
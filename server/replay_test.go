@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReplayRecords(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"sessionId":"s1","direction":"client-to-server","timestamp":"2025-01-02T03:04:05Z","message":{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"greet","arguments":{"name":"Ada"}}}}`,
+		`{"sessionId":"s1","direction":"client-to-server","timestamp":"2025-01-02T03:04:06Z","message":{"jsonrpc":"2.0","id":2,"method":"tools/list"}}`,
+		`{"sessionId":"s2","direction":"client-to-server","timestamp":"2025-01-02T03:04:07Z","message":{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"greet","arguments":{}}}}`,
+		`{"sessionId":"s2","direction":"server-to-client","timestamp":"2025-01-02T03:04:08Z","message":{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"missing name"}}}`,
+		`{"sessionId":"s1","direction":"server-to-client","timestamp":"2025-01-02T03:04:09Z","message":{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"Hello, Ada"}]}}}`,
+		``,
+		`{"jsonrpc":"2.0","id":"x","method":"tools/call","params":{"name":"other"}}`,
+	}, "\n")
+
+	records, err := ReadReplayRecords(strings.NewReader(transcript))
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "s1", records[0].SessionID)
+	assert.Equal(t, "greet", records[0].Request.Params.Name)
+	assert.Equal(t, map[string]any{"name": "Ada"}, records[0].Request.Params.Arguments)
+	require.NotNil(t, records[0].Result)
+	assert.Equal(t, "Hello, Ada", records[0].Result.Content[0].(mcp.TextContent).Text)
+	assert.Nil(t, records[0].Error)
+
+	assert.Equal(t, "s2", records[1].SessionID)
+	require.NotNil(t, records[1].Error)
+	assert.Equal(t, mcp.INVALID_PARAMS, records[1].Error.Code)
+
+	assert.Equal(t, "other", records[2].Request.Params.Name)
+	assert.Nil(t, records[2].Result)
+	assert.Nil(t, records[2].Error)
+
+	_, err = ReadReplayRecords(strings.NewReader("not json"))
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestMCPServer_ReplayToolCall(t *testing.T) {
+	calls := 0
+	server := NewMCPServer("test", "1.0.0", WithToolCapabilities(false))
+	server.AddTool(
+		mcp.NewTool("greet", mcp.WithString("name", mcp.Required())),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls++
+			return mcp.NewToolResultText("Hi, " + request.GetString("name", "")), nil
+		},
+	)
+	greet := func(args map[string]any) mcp.CallToolRequest {
+		var request mcp.CallToolRequest
+		request.Params.Name = "greet"
+		request.Params.Arguments = args
+		return request
+	}
+
+	tests := []struct {
+		name         string
+		record       ReplayRecord
+		opts         []ReplayOption
+		wantCalls    int
+		wantErrCode  int
+		wantDiverged bool
+	}{
+		{
+			name:      "matching result",
+			record:    ReplayRecord{Request: greet(map[string]any{"name": "Ada"}), Result: mcp.NewToolResultText("Hi, Ada")},
+			wantCalls: 1,
+		},
+		{
+			name:         "diverging result",
+			record:       ReplayRecord{Request: greet(map[string]any{"name": "Ada"}), Result: mcp.NewToolResultText("Hello, Ada")},
+			wantCalls:    1,
+			wantDiverged: true,
+		},
+		{
+			name:         "recorded error now succeeds",
+			record:       ReplayRecord{Request: greet(map[string]any{"name": "Ada"}), Error: &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR}},
+			wantCalls:    1,
+			wantDiverged: true,
+		},
+		{
+			name:      "nothing recorded",
+			record:    ReplayRecord{Request: greet(map[string]any{"name": "Ada"})},
+			wantCalls: 1,
+		},
+		{
+			name:        "unknown tool",
+			record:      ReplayRecord{Request: mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "gone"}}, Error: &mcp.JSONRPCErrorDetails{Code: mcp.INVALID_PARAMS}},
+			wantErrCode: mcp.INVALID_PARAMS,
+		},
+		{
+			name:   "dry run",
+			record: ReplayRecord{Request: greet(map[string]any{"name": "Ada"}), Result: mcp.NewToolResultText("Hello, Ada")},
+			opts:   []ReplayOption{WithReplayDryRun()},
+		},
+		{
+			name:        "dry run with invalid arguments",
+			record:      ReplayRecord{Request: greet(map[string]any{})},
+			opts:        []ReplayOption{WithReplayDryRun()},
+			wantErrCode: mcp.INVALID_PARAMS,
+		},
+		{
+			name:        "dry run with unknown tool",
+			record:      ReplayRecord{Request: mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "gone"}}},
+			opts:        []ReplayOption{WithReplayDryRun()},
+			wantErrCode: mcp.INVALID_PARAMS,
+		},
+		{
+			name:   "custom comparator",
+			record: ReplayRecord{Request: greet(map[string]any{"name": "Ada"}), Result: mcp.NewToolResultText("Hello, Ada")},
+			opts: []ReplayOption{WithReplayComparator(func(recorded, replayed *mcp.CallToolResult) bool {
+				return recorded.IsError == replayed.IsError
+			})},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			report := server.ReplayToolCall(context.Background(), tt.record, tt.opts...)
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantDiverged, report.Diverged)
+			if tt.wantErrCode != 0 {
+				require.NotNil(t, report.Error)
+				assert.Equal(t, tt.wantErrCode, report.Error.Code)
+				assert.Nil(t, report.Result)
+				return
+			}
+			assert.Nil(t, report.Error)
+			if tt.wantCalls > 0 {
+				require.NotNil(t, report.Result)
+				assert.Equal(t, "Hi, Ada", report.Result.Content[0].(mcp.TextContent).Text)
+			}
+		})
+	}
+}
+
+func TestMCPServer_ReplayToolCall_Task(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true), WithToolCapabilities(false))
+	server.AddTaskTool(
+		mcp.NewTool("build", mcp.WithTaskSupport(mcp.TaskSupportRequired)),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CreateTaskResult, error) {
+			return &mcp.CreateTaskResult{Content: []mcp.Content{mcp.NewTextContent("built")}}, nil
+		},
+	)
+
+	var request mcp.CallToolRequest
+	request.Params.Name = "build"
+	request.Params.Task = &mcp.TaskParams{}
+	report := server.ReplayToolCall(context.Background(), ReplayRecord{Request: request, Result: mcp.NewToolResultText("built")})
+	require.Nil(t, report.Error)
+	require.NotNil(t, report.Result)
+	assert.False(t, report.Diverged)
+}
@@ -11,13 +11,16 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-// HandleMessage processes an incoming JSON-RPC message and returns an appropriate response
-func (s *MCPServer) HandleMessage(
+// handleMessage processes an incoming JSON-RPC message and returns an
+// appropriate response. HandleMessage calls it through the request middleware
+// chain.
+func (s *MCPServer) handleMessage(
 	ctx context.Context,
 	message json.RawMessage,
 ) (resp mcp.JSONRPCMessage) {
 	// Add server to context
 	ctx = context.WithValue(ctx, serverKey{}, s)
+	defer func() { s.recordOutcome(resp) }()
 	var err *requestError
 
 	var baseMessage struct {
@@ -108,7 +111,7 @@ func (s *MCPServer) HandleMessage(
 	ctx, endSpan := s.startMessageSpan(ctx, headers, string(baseMessage.Method))
 	defer func() { endSpan(resp) }()
 
-	endLog := s.startMessageLog(ctx, headers, string(baseMessage.Method))
+	ctx, endLog := s.startMessageLog(ctx, headers, string(baseMessage.Method), baseMessage.ID)
 	defer func() { endLog(resp) }()
 
 	switch baseMessage.Method {
@@ -123,6 +126,7 @@ func (s *MCPServer) HandleMessage(
 			}
 		} else {
 			request.Header = headers
+			request.Request.Params.Meta = initializeMeta(message)
 			s.hooks.beforeInitialize(ctx, baseMessage.ID, &request)
 			result, err = s.handleInitialize(ctx, baseMessage.ID, request)
 		}
@@ -516,6 +520,58 @@ func (s *MCPServer) HandleMessage(
 		}
 		s.hooks.afterCancelTask(ctx, baseMessage.ID, &request, result)
 		return createResponse(baseMessage.ID, *result)
+	case mcp.MethodTasksSubscribe:
+		var request mcp.SubscribeTasksRequest
+		var result *mcp.EmptyResult
+		if s.capabilities.tasks == nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("tasks %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			s.hooks.beforeSubscribeTasks(ctx, baseMessage.ID, &request)
+			result, err = s.handleSubscribeTasks(ctx, baseMessage.ID, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterSubscribeTasks(ctx, baseMessage.ID, &request, result)
+		return createResponse(baseMessage.ID, *result)
+	case mcp.MethodTasksClaim:
+		var request mcp.ClaimTaskRequest
+		var result *mcp.ClaimTaskResult
+		if s.capabilities.tasks == nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("tasks %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   baseMessage.ID,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			s.hooks.beforeClaimTask(ctx, baseMessage.ID, &request)
+			result, err = s.handleClaimTask(ctx, baseMessage.ID, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterClaimTask(ctx, baseMessage.ID, &request, result)
+		return createResponse(baseMessage.ID, *result)
 	case mcp.MethodCompletionComplete:
 		var request mcp.CompleteRequest
 		var result *mcp.CompleteResult
@@ -550,3 +606,26 @@ func (s *MCPServer) HandleMessage(
 		)
 	}
 }
+
+// knownMethods are the request methods the server implements.
+var knownMethods = map[string]bool{
+	string(mcp.MethodInitialize):             true,
+	string(mcp.MethodPing):                   true,
+	string(mcp.MethodSetLogLevel):            true,
+	string(mcp.MethodResourcesList):          true,
+	string(mcp.MethodResourcesTemplatesList): true,
+	string(mcp.MethodResourcesRead):          true,
+	string(mcp.MethodResourcesSubscribe):     true,
+	string(mcp.MethodResourcesUnsubscribe):   true,
+	string(mcp.MethodPromptsList):            true,
+	string(mcp.MethodPromptsGet):             true,
+	string(mcp.MethodToolsList):              true,
+	string(mcp.MethodToolsCall):              true,
+	string(mcp.MethodTasksGet):               true,
+	string(mcp.MethodTasksList):              true,
+	string(mcp.MethodTasksResult):            true,
+	string(mcp.MethodTasksCancel):            true,
+	string(mcp.MethodTasksSubscribe):         true,
+	string(mcp.MethodTasksClaim):             true,
+	string(mcp.MethodCompletionComplete):     true,
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RequestHandler handles an incoming JSON-RPC request and returns the
+// response to send back to the client.
+type RequestHandler func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage
+
+// RequestMiddleware wraps a RequestHandler.
+type RequestMiddleware func(next RequestHandler) RequestHandler
+
+// WithRequestMiddleware adds a middleware that wraps the handling of every
+// incoming JSON-RPC request, whatever its method: initialize, tools,
+// resources, prompts, tasks, and so on. It is the single place to implement
+// cross-cutting concerns such as authorization, logging or panic recovery.
+//
+// A middleware may inspect or modify the request before calling next, or
+// return its own response without calling next, for example a
+// mcp.NewJSONRPCError. request.Params holds the raw JSON of the params, a
+// json.RawMessage, so numbers keep their precision; to change them, decode
+// them and set request.Params to the new value. A request that no
+// middleware changed is handled from the bytes it arrived as. Notifications
+// and responses from the client do not pass through request middleware.
+//
+// Middleware is applied in the order added (outermost first), matching
+// net/http convention.
+func WithRequestMiddleware(mw RequestMiddleware) ServerOption {
+	return func(s *MCPServer) {
+		s.requestMiddlewares = append(s.requestMiddlewares, mw)
+	}
+}
+
+// HandleMessage processes an incoming JSON-RPC message and returns an
+// appropriate response.
+func (s *MCPServer) HandleMessage(
+	ctx context.Context,
+	message json.RawMessage,
+) mcp.JSONRPCMessage {
+	if len(s.requestMiddlewares) == 0 {
+		return s.handleMessage(ctx, message)
+	}
+
+	var envelope struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      mcp.RequestId   `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if json.Unmarshal(message, &envelope) != nil ||
+		envelope.ID.IsNil() ||
+		envelope.Result != nil ||
+		envelope.Method == "" {
+		// Let handleMessage report malformed messages and process
+		// notifications and responses.
+		return s.handleMessage(ctx, message)
+	}
+
+	request := &mcp.JSONRPCRequest{JSONRPC: envelope.JSONRPC, ID: envelope.ID}
+	request.Method = envelope.Method
+	if envelope.Params != nil {
+		request.Params = envelope.Params
+	}
+	handler := RequestHandler(func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+		if request.Method == envelope.Method &&
+			request.ID == envelope.ID &&
+			sameParams(request.Params, envelope.Params) {
+			return s.handleMessage(ctx, message)
+		}
+		data, err := json.Marshal(request)
+		if err != nil {
+			return mcp.NewJSONRPCError(request.ID, mcp.INVALID_REQUEST, err.Error(), nil)
+		}
+		return s.handleMessage(ctx, data)
+	})
+	for i := len(s.requestMiddlewares) - 1; i >= 0; i-- {
+		handler = s.requestMiddlewares[i](handler)
+	}
+	return handler(ctx, request)
+}
+
+// sameParams reports whether params still holds the raw params a request
+// arrived with.
+func sameParams(params any, raw json.RawMessage) bool {
+	if params == nil {
+		return raw == nil
+	}
+	current, ok := params.(json.RawMessage)
+	return ok && bytes.Equal(current, raw)
+}
+
+// decodeRequestParams decodes the params of request into v, whether they are
+// still the raw params the request arrived with or were set by a middleware.
+func decodeRequestParams(request *mcp.JSONRPCRequest, v any) error {
+	data, ok := request.Params.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(request.Params); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequestMiddleware(t *testing.T) {
+	var seen []string
+	record := func(name string) RequestMiddleware {
+		return func(next RequestHandler) RequestHandler {
+			return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+				seen = append(seen, name+":"+request.Method)
+				return next(ctx, request)
+			}
+		}
+	}
+	denyPrompts := func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+			if request.Method == string(mcp.MethodPromptsList) {
+				return mcp.NewJSONRPCError(request.ID, mcp.INVALID_REQUEST, "forbidden", nil)
+			}
+			return next(ctx, request)
+		}
+	}
+	renameTool := func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+			var params map[string]any
+			if raw, ok := request.Params.(json.RawMessage); ok && json.Unmarshal(raw, &params) == nil && params["name"] == "old" {
+				params["name"] = "new"
+				request.Params = params
+			}
+			return next(ctx, request)
+		}
+	}
+	recoverPanics := func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, request *mcp.JSONRPCRequest) (resp mcp.JSONRPCMessage) {
+			defer func() {
+				if r := recover(); r != nil {
+					resp = mcp.NewJSONRPCError(request.ID, mcp.INTERNAL_ERROR, fmt.Sprint(r), nil)
+				}
+			}()
+			return next(ctx, request)
+		}
+	}
+
+	s := NewMCPServer("test", "1.0.0",
+		WithToolCapabilities(true),
+		WithPromptCapabilities(true),
+		WithRequestMiddleware(recoverPanics),
+		WithRequestMiddleware(record("outer")),
+		WithRequestMiddleware(record("inner")),
+		WithRequestMiddleware(denyPrompts),
+		WithRequestMiddleware(renameTool),
+	)
+	s.AddTool(mcp.NewTool("new"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("called new"), nil
+	})
+	s.AddTool(mcp.NewTool("id"), func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			ID int64 `json:"id"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(fmt.Sprint(args.ID)), nil
+	})
+	s.AddTool(mcp.NewTool("boom"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("kaboom")
+	})
+
+	t.Run("wraps every method in order", func(t *testing.T) {
+		seen = nil
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		assert.IsType(t, mcp.JSONRPCResponse{}, resp)
+		assert.Equal(t, []string{"outer:ping", "inner:ping"}, seen)
+	})
+
+	t.Run("can short-circuit", func(t *testing.T) {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`))
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok, "unexpected response %#v", resp)
+		assert.Equal(t, "forbidden", errResp.Error.Message)
+		assert.Equal(t, mcp.NewRequestId(int64(2)), errResp.ID)
+	})
+
+	t.Run("can mutate params", func(t *testing.T) {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"old"}}`))
+		result, ok := resp.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", resp)
+		callResult := result.Result.(*mcp.CallToolResult)
+		assert.Equal(t, "called new", callResult.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("keeps large integers", func(t *testing.T) {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"id","arguments":{"id":9007199254740993}}}`))
+		result, ok := resp.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", resp)
+		callResult := result.Result.(*mcp.CallToolResult)
+		assert.Equal(t, "9007199254740993", callResult.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("can recover panics", func(t *testing.T) {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"boom"}}`))
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok, "unexpected response %#v", resp)
+		assert.Equal(t, "kaboom", errResp.Error.Message)
+	})
+
+	t.Run("notifications bypass middleware", func(t *testing.T) {
+		seen = nil
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+		assert.Nil(t, resp)
+		assert.Empty(t, seen)
+	})
+
+	t.Run("malformed messages are still rejected", func(t *testing.T) {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"1.0","id":4,"method":"ping"}`))
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok, "unexpected response %#v", resp)
+		assert.Equal(t, mcp.INVALID_REQUEST, errResp.Error.Code)
+	})
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// mappedFileLinger is how long a mapping stays open after its last reader is
+// closed, so that the chunks of one read and concurrent clients share it.
+var mappedFileLinger = 30 * time.Second
+
+// AddFileResource registers a resource streamed from the local file at path,
+// for large immutable artifacts such as datasets, archives and build
+// outputs. The file is served in chunks as with AddResourceStream.
+//
+// Where the platform supports it, the file is memory-mapped read-only
+// instead of read into buffers: all clients reading the file share one
+// mapping, whose pages the kernel can drop under memory pressure, so
+// concurrent reads of the same artifact do not multiply the server's memory
+// use. The mapping is released shortly after its last reader finishes, and
+// is replaced when the file's size or modification time changes. Elsewhere
+// the file is read with ordinary file I/O.
+//
+// The file must not be modified in place while it is served: accessing
+// pages past the end of a mapping whose file was truncated crashes the
+// process with SIGBUS on most platforms, which no size check can rule out.
+// Replace files by renaming a new file over them instead. Serve files that
+// grow or are rewritten in place, such as logs, with AddResourceStream and
+// os.Open.
+func (s *MCPServer) AddFileResource(resource mcp.Resource, path string) {
+	s.AddResourceStream(resource, func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, error) {
+		return s.mappedFiles.open(path)
+	})
+}
+
+// mappedFileCache shares the mappings of the files served by
+// AddFileResource. The zero value is ready to use.
+type mappedFileCache struct {
+	mu    sync.Mutex
+	files map[string]*mappedFile
+}
+
+// mappedFile is a read-only mapping of a file, released when no reader uses
+// it for mappedFileLinger.
+type mappedFile struct {
+	cache   *mappedFileCache
+	path    string
+	size    int64
+	modTime time.Time
+	data    []byte
+	refs    int
+	linger  *time.Timer
+}
+
+// mappedFileReader reads a mapped file. Closing it releases its reference
+// to the mapping.
+type mappedFileReader struct {
+	*bytes.Reader
+	file *mappedFile
+	once sync.Once
+}
+
+// Close releases the reader's reference to the mapping.
+func (r *mappedFileReader) Close() error {
+	r.once.Do(r.file.release)
+	return nil
+}
+
+// open returns a reader of the file at path, sharing the current mapping of
+// the file if it has one. If the file cannot be mapped, it returns the open
+// *os.File.
+func (c *mappedFileCache) open(path string) (io.Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m, ok := c.files[path]; ok && m.size == info.Size() && m.modTime.Equal(info.ModTime()) {
+		_ = f.Close()
+		return m.acquire(), nil
+	}
+
+	data, err := mmapFile(f, info.Size())
+	if err != nil {
+		// Fall back to reading the file.
+		return f, nil
+	}
+	_ = f.Close()
+	m := &mappedFile{cache: c, path: path, size: info.Size(), modTime: info.ModTime(), data: data}
+	if old, ok := c.files[path]; ok {
+		// The file changed: readers of the old mapping keep it until they
+		// are done, new readers get the new one.
+		old.detach()
+	}
+	if c.files == nil {
+		c.files = make(map[string]*mappedFile)
+	}
+	c.files[path] = m
+	return m.acquire(), nil
+}
+
+// acquire returns a new reader of m. c.mu must be held.
+func (m *mappedFile) acquire() *mappedFileReader {
+	m.refs++
+	if m.linger != nil {
+		m.linger.Stop()
+		m.linger = nil
+	}
+	return &mappedFileReader{Reader: bytes.NewReader(m.data), file: m}
+}
+
+// release drops a reference to m, unmapping it once it has been unused for
+// mappedFileLinger.
+func (m *mappedFile) release() {
+	c := m.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m.refs--
+	if m.refs > 0 {
+		return
+	}
+	if c.files[m.path] != m {
+		m.unmap()
+		return
+	}
+	m.linger = time.AfterFunc(mappedFileLinger, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if m.refs == 0 && c.files[m.path] == m {
+			delete(c.files, m.path)
+			m.unmap()
+		}
+	})
+}
+
+// detach removes m from the cache, unmapping it now if it has no readers.
+// c.mu must be held.
+func (m *mappedFile) detach() {
+	delete(m.cache.files, m.path)
+	if m.linger != nil {
+		m.linger.Stop()
+		m.linger = nil
+	}
+	if m.refs == 0 {
+		m.unmap()
+	}
+}
+
+func (m *mappedFile) unmap() {
+	if m.data != nil {
+		_ = munmapFile(m.data)
+		m.data = nil
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package server
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile is not supported on this platform; AddFileResource reads files
+// instead.
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	return nil, errors.New("memory-mapped files are not supported on this platform")
+}
+
+func munmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFileResource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+	content := strings.Repeat("0123456789", 5)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	s := NewMCPServer("test", "1.0.0", WithResourceChunkSize(16))
+	s.AddFileResource(mcp.NewResource("file:///artifact.bin", "artifact"), path)
+	ctx := s.WithContext(context.Background(), fakeSession{sessionID: "s1", initialized: true})
+
+	readAll := func(t *testing.T) string {
+		t.Helper()
+		var got []byte
+		var cursor mcp.Cursor
+		for chunks := 0; ; chunks++ {
+			require.Less(t, chunks, 10, "too many chunks")
+			params, err := json.Marshal(mcp.ReadResourceParams{URI: "file:///artifact.bin", Cursor: cursor})
+			require.NoError(t, err)
+			resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":`+string(params)+`}`))
+			result := resp.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult)
+			data, err := base64.StdEncoding.DecodeString(result.Contents[0].(mcp.BlobResourceContents).Blob)
+			require.NoError(t, err)
+			got = append(got, data...)
+			if cursor = result.NextCursor; cursor == "" {
+				return string(got)
+			}
+		}
+	}
+
+	assert.Equal(t, content, readAll(t))
+
+	t.Run("readers share one mapping", func(t *testing.T) {
+		r1, err := s.mappedFiles.open(path)
+		require.NoError(t, err)
+		r2, err := s.mappedFiles.open(path)
+		require.NoError(t, err)
+		m1, ok := r1.(*mappedFileReader)
+		if !ok {
+			t.Skip("memory mapping is not supported on this platform")
+		}
+		m2 := r2.(*mappedFileReader)
+		assert.Same(t, m1.file, m2.file)
+
+		buf := make([]byte, 4)
+		_, err = m2.ReadAt(buf, 10)
+		require.NoError(t, err)
+		assert.Equal(t, "0123", string(buf))
+
+		require.NoError(t, m1.Close())
+		require.NoError(t, m1.Close(), "closing twice releases once")
+		assert.Equal(t, 1, m1.file.refs)
+		require.NoError(t, m2.Close())
+		assert.Equal(t, 0, m1.file.refs)
+	})
+
+	t.Run("changed file is mapped again", func(t *testing.T) {
+		r, err := s.mappedFiles.open(path)
+		require.NoError(t, err)
+		old, ok := r.(*mappedFileReader)
+		if !ok {
+			t.Skip("memory mapping is not supported on this platform")
+		}
+		replacement := filepath.Join(filepath.Dir(path), "new.bin")
+		require.NoError(t, os.WriteFile(replacement, []byte("replaced"), 0o600))
+		require.NoError(t, os.Rename(replacement, path))
+
+		assert.Equal(t, "replaced", readAll(t))
+		data, err := io.ReadAll(old)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data), "open readers keep the old mapping")
+		require.NoError(t, old.Close())
+		assert.Nil(t, old.file.data, "the old mapping is released with its last reader")
+	})
+
+	t.Run("released after linger", func(t *testing.T) {
+		defer func(d time.Duration) { mappedFileLinger = d }(mappedFileLinger)
+		mappedFileLinger = time.Millisecond
+
+		r, err := s.mappedFiles.open(path)
+		require.NoError(t, err)
+		m, ok := r.(*mappedFileReader)
+		if !ok {
+			t.Skip("memory mapping is not supported on this platform")
+		}
+		require.NoError(t, m.Close())
+		assert.Eventually(t, func() bool {
+			s.mappedFiles.mu.Lock()
+			defer s.mappedFiles.mu.Unlock()
+			return len(s.mappedFiles.files) == 0 && m.file.data == nil
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := s.mappedFiles.open(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,27 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package server
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps size bytes of f read-only.
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	if size == 0 {
+		// Empty files cannot be mapped.
+		return []byte{}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, syscall.EFBIG
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func munmapFile(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return syscall.Munmap(data)
+}
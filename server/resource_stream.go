@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultResourceChunkSize is the number of bytes of a streamed resource
+// returned by each resources/read.
+const DefaultResourceChunkSize = 1 << 20
+
+// resourceStreamIdleTimeout is how long a non-seekable resource stream is
+// kept open waiting for the client to read its next chunk.
+const resourceStreamIdleTimeout = 5 * time.Minute
+
+// ErrResourceStreamExpired is returned when a client continues reading a
+// streamed resource whose reader is no longer open, because the client took
+// too long or its cursor does not match the stream. The client has to read
+// the resource again from the start.
+var ErrResourceStreamExpired = errors.New("resource stream expired")
+
+// ResourceStreamHandlerFunc returns the content of a resource as a reader,
+// for resources too large to hold in memory. If the reader implements
+// io.Closer, it is closed once the content has been read or the stream is
+// abandoned.
+//
+// Readers implementing io.Seeker, such as *os.File, are opened again for
+// every chunk and seeked to the chunk's offset, so reading needs no server
+// state. Other readers are kept open between chunks for a few minutes.
+type ResourceStreamHandlerFunc func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, error)
+
+// WithResourceChunkSize sets the number of bytes of a streamed resource
+// returned by each resources/read. Defaults to DefaultResourceChunkSize.
+func WithResourceChunkSize(size int) ServerOption {
+	return func(s *MCPServer) {
+		s.resourceChunkSize = size
+	}
+}
+
+// AddResourceStream registers a resource whose content is streamed from the
+// reader returned by handler. Each resources/read returns one chunk of the
+// content as a blob, together with a nextCursor to pass in the cursor
+// parameter of the next resources/read; the last chunk has no nextCursor.
+// Chunked reads are an extension to the MCP specification: clients unaware
+// of them only receive the first chunk. client.ReadResourceStream follows
+// the cursors and exposes the content as an io.ReadCloser.
+//
+// The blob's MIME type is the resource's, or application/octet-stream if it
+// has none.
+func (s *MCPServer) AddResourceStream(resource mcp.Resource, handler ResourceStreamHandlerFunc) {
+	s.AddResource(resource, s.chunkedResourceHandler(resource, handler))
+}
+
+// resourceCursor is the decoded form of a streamed resource's cursor.
+type resourceCursor struct {
+	Stream string `json:"s,omitempty"` // ID of an open non-seekable stream
+	Offset int64  `json:"o"`
+}
+
+func (c resourceCursor) encode() mcp.Cursor {
+	data, _ := json.Marshal(c)
+	return mcp.Cursor(base64.RawURLEncoding.EncodeToString(data))
+}
+
+func decodeResourceCursor(cursor mcp.Cursor) (resourceCursor, error) {
+	var c resourceCursor
+	if cursor == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(string(cursor))
+	if err != nil {
+		return c, fmt.Errorf("invalid resource cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return c, errors.New("invalid resource cursor")
+	}
+	return c, nil
+}
+
+// openResourceStream is a non-seekable reader kept open between chunks.
+type openResourceStream struct {
+	reader    io.Reader
+	uri       string
+	sessionID string
+	offset    int64
+	timer     *time.Timer
+}
+
+// resourceCursorKey carries the *mcp.Cursor that a chunked resource handler
+// sets to the cursor of the next chunk.
+type resourceCursorKey struct{}
+
+// chunkedResourceHandler adapts handler to a ResourceHandlerFunc returning
+// one chunk per call.
+func (s *MCPServer) chunkedResourceHandler(resource mcp.Resource, handler ResourceStreamHandlerFunc) ResourceHandlerFunc {
+	mimeType := resource.MIMEType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		cursor, err := decodeResourceCursor(request.Params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := s.resumeResourceStream(ctx, request, cursor, handler)
+		if err != nil {
+			return nil, err
+		}
+
+		chunkSize := s.resourceChunkSize
+		if chunkSize <= 0 {
+			chunkSize = DefaultResourceChunkSize
+		}
+		buf := make([]byte, chunkSize)
+		n, err := io.ReadFull(reader, buf)
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			closeReader(reader)
+		case err != nil:
+			closeReader(reader)
+			return nil, fmt.Errorf("failed to read resource %s: %w", request.Params.URI, err)
+		default:
+			next := resourceCursor{Offset: cursor.Offset + int64(n)}
+			if _, ok := reader.(io.Seeker); ok {
+				closeReader(reader)
+			} else {
+				next.Stream = s.keepResourceStream(ctx, request.Params.URI, reader, next.Offset)
+			}
+			if nextCursor, ok := ctx.Value(resourceCursorKey{}).(*mcp.Cursor); ok {
+				*nextCursor = next.encode()
+			}
+		}
+
+		return []mcp.ResourceContents{mcp.BlobResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: mimeType,
+			Blob:     base64.StdEncoding.EncodeToString(buf[:n]),
+		}}, nil
+	}
+}
+
+// resumeResourceStream returns the reader positioned at cursor: the open
+// stream the cursor names, or a new reader from handler.
+func (s *MCPServer) resumeResourceStream(
+	ctx context.Context,
+	request mcp.ReadResourceRequest,
+	cursor resourceCursor,
+	handler ResourceStreamHandlerFunc,
+) (io.Reader, error) {
+	if cursor.Stream != "" {
+		value, ok := s.resourceStreams.LoadAndDelete(cursor.Stream)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrResourceStreamExpired, request.Params.URI)
+		}
+		stream := value.(*openResourceStream)
+		stream.timer.Stop()
+		if stream.uri != request.Params.URI || stream.sessionID != getSessionID(ctx) || stream.offset != cursor.Offset {
+			closeReader(stream.reader)
+			return nil, fmt.Errorf("%w: %s", ErrResourceStreamExpired, request.Params.URI)
+		}
+		return stream.reader, nil
+	}
+
+	reader, err := handler(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Offset == 0 {
+		return reader, nil
+	}
+	if seeker, ok := reader.(io.Seeker); ok {
+		_, err = seeker.Seek(cursor.Offset, io.SeekStart)
+	} else {
+		_, err = io.CopyN(io.Discard, reader, cursor.Offset)
+	}
+	if err != nil {
+		closeReader(reader)
+		return nil, fmt.Errorf("failed to seek resource %s: %w", request.Params.URI, err)
+	}
+	return reader, nil
+}
+
+// keepResourceStream keeps reader open for the next chunk and returns the
+// ID naming it in the cursor.
+func (s *MCPServer) keepResourceStream(ctx context.Context, uri string, reader io.Reader, offset int64) string {
+	id := s.newID()
+	stream := &openResourceStream{
+		reader:    reader,
+		uri:       uri,
+		sessionID: getSessionID(ctx),
+		offset:    offset,
+	}
+	stream.timer = time.AfterFunc(resourceStreamIdleTimeout, func() {
+		if s.resourceStreams.CompareAndDelete(id, stream) {
+			closeReader(reader)
+		}
+	})
+	s.resourceStreams.Store(id, stream)
+	return id
+}
+
+// closeResourceStreams closes the streams left open by a session.
+func (s *MCPServer) closeResourceStreams(sessionID string) {
+	s.resourceStreams.Range(func(key, value any) bool {
+		stream := value.(*openResourceStream)
+		if stream.sessionID == sessionID && s.resourceStreams.CompareAndDelete(key, stream) {
+			stream.timer.Stop()
+			closeReader(stream.reader)
+		}
+		return true
+	})
+}
+
+func closeReader(reader io.Reader) {
+	if closer, ok := reader.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
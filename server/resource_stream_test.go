@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onceReader is a non-seekable reader that records whether it was closed.
+type onceReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *onceReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestAddResourceStream(t *testing.T) {
+	content := strings.Repeat("0123456789", 3) // 30 bytes, 4 chunks of 8
+
+	var opened []*onceReader
+	s := NewMCPServer("test", "1.0.0", WithResourceChunkSize(8))
+	s.AddResourceStream(mcp.NewResource("file:///seekable", "seekable"), func(context.Context, mcp.ReadResourceRequest) (io.Reader, error) {
+		return bytes.NewReader([]byte(content)), nil
+	})
+	s.AddResourceStream(mcp.NewResource("file:///pipe", "pipe", mcp.WithMIMEType("text/plain")), func(context.Context, mcp.ReadResourceRequest) (io.Reader, error) {
+		r := &onceReader{Reader: strings.NewReader(content)}
+		opened = append(opened, r)
+		return r, nil
+	})
+
+	session := fakeSession{sessionID: "s1", notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+	ctx := s.WithContext(context.Background(), session)
+
+	read := func(t *testing.T, ctx context.Context, uri string, cursor mcp.Cursor) (*mcp.ReadResourceResult, *mcp.JSONRPCErrorDetails) {
+		t.Helper()
+		params, err := json.Marshal(mcp.ReadResourceParams{URI: uri, Cursor: cursor})
+		require.NoError(t, err)
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":`+string(params)+`}`))
+		if errResp, ok := resp.(mcp.JSONRPCError); ok {
+			return nil, &errResp.Error
+		}
+		result := resp.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult)
+		return &result, nil
+	}
+
+	readAll := func(t *testing.T, uri string) (string, string) {
+		t.Helper()
+		var got []byte
+		var mimeType string
+		var cursor mcp.Cursor
+		for chunks := 0; ; chunks++ {
+			require.Less(t, chunks, 10, "too many chunks")
+			result, errDetails := read(t, ctx, uri, cursor)
+			require.Nil(t, errDetails)
+			require.Len(t, result.Contents, 1)
+			blob := result.Contents[0].(mcp.BlobResourceContents)
+			mimeType = blob.MIMEType
+			data, err := base64.StdEncoding.DecodeString(blob.Blob)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(data), 8)
+			got = append(got, data...)
+			if cursor = result.NextCursor; cursor == "" {
+				return string(got), mimeType
+			}
+		}
+	}
+
+	t.Run("seekable", func(t *testing.T) {
+		got, mimeType := readAll(t, "file:///seekable")
+		assert.Equal(t, content, got)
+		assert.Equal(t, "application/octet-stream", mimeType)
+	})
+
+	t.Run("non-seekable", func(t *testing.T) {
+		opened = nil
+		got, mimeType := readAll(t, "file:///pipe")
+		assert.Equal(t, content, got)
+		assert.Equal(t, "text/plain", mimeType)
+		require.Len(t, opened, 1, "the reader is kept open between chunks")
+		assert.True(t, opened[0].closed)
+	})
+
+	t.Run("cursor of another session", func(t *testing.T) {
+		result, errDetails := read(t, ctx, "file:///pipe", "")
+		require.Nil(t, errDetails)
+		other := s.WithContext(context.Background(), fakeSession{sessionID: "s2", initialized: true})
+		_, errDetails = read(t, other, "file:///pipe", result.NextCursor)
+		require.NotNil(t, errDetails)
+		assert.Contains(t, errDetails.Message, ErrResourceStreamExpired.Error())
+
+		_, errDetails = read(t, ctx, "file:///pipe", result.NextCursor)
+		require.NotNil(t, errDetails, "a rejected cursor closes the stream")
+	})
+
+	t.Run("closed with the session", func(t *testing.T) {
+		opened = nil
+		require.NoError(t, s.RegisterSession(context.Background(), session))
+		_, errDetails := read(t, ctx, "file:///pipe", "")
+		require.Nil(t, errDetails)
+		s.UnregisterSession(context.Background(), session.SessionID())
+		require.Len(t, opened, 1)
+		assert.True(t, opened[0].closed)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, errDetails := read(t, ctx, "file:///seekable", "not a cursor!")
+		require.NotNil(t, errDetails)
+		assert.Contains(t, errDetails.Message, "invalid resource cursor")
+	})
+}
@@ -267,3 +267,34 @@ func TestMCPServer_Subscribe_HooksFire(t *testing.T) {
 	assert.Equal(t, 1, beforeUnsubscribe)
 	assert.Equal(t, 1, afterUnsubscribe)
 }
+
+func TestMCPServer_NotifyResourceUpdated(t *testing.T) {
+	srv := NewMCPServer("test", "0.0.1", WithResourceCapabilities(true, false))
+	subscriber := newSessionWithSubscriptions("subscriber")
+	other := newSessionWithSubscriptions("other")
+	for _, session := range []*sessionWithSubscriptions{subscriber, other} {
+		session.Initialize()
+		require.NoError(t, srv.RegisterSession(t.Context(), session))
+	}
+
+	msg, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/subscribe",
+		"params":  map[string]string{"uri": "file:///a"},
+	})
+	require.NoError(t, err)
+	srv.HandleMessage(srv.WithContext(t.Context(), subscriber), msg)
+
+	srv.NotifyResourceUpdated("file:///a")
+	srv.NotifyResourceUpdated("file:///b")
+
+	require.Len(t, subscriber.notificationChannel, 1)
+	notification := <-subscriber.notificationChannel
+	assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+	assert.Equal(t, "file:///a", notification.Params.AdditionalFields["uri"])
+	assert.Empty(t, other.notificationChannel)
+
+	srv.UnregisterSession(t.Context(), subscriber.sessionID)
+	assert.Empty(t, srv.resourceSubscribers.uris)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resourceSubscribers records which sessions subscribed to which resources
+// with resources/subscribe.
+type resourceSubscribers struct {
+	mu   sync.RWMutex
+	uris map[string]map[string]struct{} // URI -> session IDs
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated for uri to
+// every session that subscribed to it with resources/subscribe. Resource
+// providers call it when the content behind a resource changes; sessions
+// that did not subscribe are not notified.
+func (s *MCPServer) NotifyResourceUpdated(uri string) {
+	s.resourceSubscribers.mu.RLock()
+	sessionIDs := make([]string, 0, len(s.resourceSubscribers.uris[uri]))
+	for sessionID := range s.resourceSubscribers.uris[uri] {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	s.resourceSubscribers.mu.RUnlock()
+
+	for _, sessionID := range sessionIDs {
+		// Blocked channels are reported through the OnError hook.
+		_ = s.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{
+			"uri": uri,
+		})
+	}
+}
+
+// trackResourceSubscription records a resources/subscribe or
+// resources/unsubscribe request of the session in ctx.
+func (s *MCPServer) trackResourceSubscription(ctx context.Context, uri string, subscribed bool) {
+	s.setResourceSubscription(getSessionID(ctx), uri, subscribed)
+}
+
+// setResourceSubscription records whether the session sessionID is
+// subscribed to uri.
+func (s *MCPServer) setResourceSubscription(sessionID, uri string, subscribed bool) {
+	if sessionID == "" {
+		return
+	}
+
+	subs := &s.resourceSubscribers
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	if !subscribed {
+		delete(subs.uris[uri], sessionID)
+		if len(subs.uris[uri]) == 0 {
+			delete(subs.uris, uri)
+		}
+		return
+	}
+	if subs.uris == nil {
+		subs.uris = make(map[string]map[string]struct{})
+	}
+	if subs.uris[uri] == nil {
+		subs.uris[uri] = make(map[string]struct{})
+	}
+	subs.uris[uri][sessionID] = struct{}{}
+}
+
+// dropResourceSubscriptions forgets the subscriptions of an ended session.
+func (s *MCPServer) dropResourceSubscriptions(sessionID string) {
+	subs := &s.resourceSubscribers
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	for uri, sessions := range subs.uris {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(subs.uris, uri)
+		}
+	}
+}
+
+// resourceSubscriptions returns the URIs the session sessionID subscribed
+// to, sorted.
+func (s *MCPServer) resourceSubscriptions(sessionID string) []string {
+	subs := &s.resourceSubscribers
+	subs.mu.RLock()
+	defer subs.mu.RUnlock()
+	var uris []string
+	for uri, sessions := range subs.uris {
+		if _, ok := sessions[sessionID]; ok {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+	return uris
+}
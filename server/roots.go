@@ -17,6 +17,7 @@ var (
 // RequestRoots sends an list roots request to the client.
 // The client must have declared roots capability during initialization.
 // The session must implement SessionWithRoots to support this operation.
+// With WithRootsCache, a cached list is returned without contacting the client.
 func (s *MCPServer) RequestRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
 	session := ClientSessionFromContext(ctx)
 	if session == nil {
@@ -25,7 +26,18 @@ func (s *MCPServer) RequestRoots(ctx context.Context, request mcp.ListRootsReque
 
 	// Check if the session supports roots requests
 	if rootsSession, ok := session.(SessionWithRoots); ok {
-		return rootsSession.ListRoots(ctx, request)
+		if result, ok := s.cachedRoots(session); ok {
+			return result, nil
+		}
+		s.hooks.beforeServerRequest(ctx, session, mcp.MethodListRoots, request.Params)
+		result, err := rootsSession.ListRoots(ctx, request)
+		s.hooks.afterServerRequest(ctx, session, mcp.MethodListRoots, request.Params, resultOrNil(result, err), err)
+		if err != nil {
+			s.reportDropped(ctx, session, mcp.MethodListRoots, request.Params, err)
+			return result, err
+		}
+		s.cacheRoots(session, result)
+		return result, nil
 	}
 
 	return nil, ErrRootsNotSupported
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// rootsCache holds the roots most recently listed by each session.
+type rootsCache struct {
+	ttl     time.Duration
+	entries sync.Map // session ID -> rootsCacheEntry
+}
+
+type rootsCacheEntry struct {
+	result  *mcp.ListRootsResult
+	expires time.Time // zero when the entry only expires on list_changed
+}
+
+// WithRootsCache caches the result of RequestRoots per session, so tools that
+// need the client's roots on every call do not pay a round-trip each time.
+// A cached list is dropped when the client sends
+// notifications/roots/list_changed, when the session ends, and after ttl has
+// elapsed. A non-positive ttl keeps the list until one of the other two
+// happens.
+func WithRootsCache(ttl time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.rootsCache = &rootsCache{ttl: ttl}
+	}
+}
+
+// cachedRoots returns the roots cached for session, if any.
+func (s *MCPServer) cachedRoots(session ClientSession) (*mcp.ListRootsResult, bool) {
+	if s.rootsCache == nil {
+		return nil, false
+	}
+	value, ok := s.rootsCache.entries.Load(session.SessionID())
+	if !ok {
+		return nil, false
+	}
+	entry := value.(rootsCacheEntry)
+	if !entry.expires.IsZero() && !s.now().Before(entry.expires) {
+		s.rootsCache.entries.CompareAndDelete(session.SessionID(), entry)
+		return nil, false
+	}
+	return cloneRoots(entry.result), true
+}
+
+// cacheRoots stores the roots listed by session.
+func (s *MCPServer) cacheRoots(session ClientSession, result *mcp.ListRootsResult) {
+	if s.rootsCache == nil || result == nil {
+		return
+	}
+	entry := rootsCacheEntry{result: cloneRoots(result)}
+	if s.rootsCache.ttl > 0 {
+		entry.expires = s.now().Add(s.rootsCache.ttl)
+	}
+	s.rootsCache.entries.Store(session.SessionID(), entry)
+}
+
+// invalidateRoots drops the cached roots of the session in ctx.
+func (s *MCPServer) invalidateRoots(ctx context.Context) {
+	if s.rootsCache == nil {
+		return
+	}
+	if session := ClientSessionFromContext(ctx); session != nil {
+		s.rootsCache.entries.Delete(session.SessionID())
+	}
+}
+
+// cloneRoots copies result so callers cannot modify the cached list.
+func cloneRoots(result *mcp.ListRootsResult) *mcp.ListRootsResult {
+	clone := *result
+	clone.Roots = slices.Clone(result.Roots)
+	return &clone
+}
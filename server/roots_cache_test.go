@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRootsSession counts the roots requests that reach the client.
+type countingRootsSession struct {
+	mockRootsSession
+	calls atomic.Int32
+}
+
+func (m *countingRootsSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	m.calls.Add(1)
+	return m.mockRootsSession.ListRoots(ctx, request)
+}
+
+func TestWithRootsCache(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	s := NewMCPServer("test", "1.0.0", WithClock(clock), WithRootsCache(time.Minute))
+	session := &countingRootsSession{mockRootsSession: mockRootsSession{
+		sessionID: "roots",
+		result:    &mcp.ListRootsResult{Roots: []mcp.Root{{Name: "repo", URI: "file:///repo"}}},
+	}}
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+	ctx := s.WithContext(t.Context(), session)
+
+	listRoots := func() *mcp.ListRootsResult {
+		t.Helper()
+		result, err := s.RequestRoots(ctx, mcp.ListRootsRequest{})
+		require.NoError(t, err)
+		return result
+	}
+
+	first := listRoots()
+	first.Roots[0].Name = "modified"
+	assert.Equal(t, "repo", listRoots().Roots[0].Name, "cached list must not be shared with callers")
+	assert.EqualValues(t, 1, session.calls.Load())
+
+	t.Run("list_changed invalidates", func(t *testing.T) {
+		s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`))
+		listRoots()
+		assert.EqualValues(t, 2, session.calls.Load())
+	})
+
+	t.Run("ttl expires", func(t *testing.T) {
+		clock.Advance(59 * time.Second)
+		listRoots()
+		assert.EqualValues(t, 2, session.calls.Load())
+		clock.Advance(time.Second)
+		listRoots()
+		assert.EqualValues(t, 3, session.calls.Load())
+	})
+
+	t.Run("session end clears", func(t *testing.T) {
+		s.UnregisterSession(t.Context(), session.sessionID)
+		_, ok := s.rootsCache.entries.Load(session.sessionID)
+		assert.False(t, ok)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		failing := &countingRootsSession{mockRootsSession: mockRootsSession{sessionID: "failing", err: assert.AnError}}
+		failingCtx := s.WithContext(t.Context(), failing)
+		for range 2 {
+			_, err := s.RequestRoots(failingCtx, mcp.ListRootsRequest{})
+			assert.ErrorIs(t, err, assert.AnError)
+		}
+		assert.EqualValues(t, 2, failing.calls.Load())
+	})
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RootsChange describes the roots of a session after the client reported
+// that they changed.
+type RootsChange struct {
+	// Roots is the complete list the client returned.
+	Roots []mcp.Root
+	// Added lists the roots whose URI was not in the previous list. The
+	// first change seen for a session reports every root as added.
+	Added []mcp.Root
+	// Removed lists the previous roots whose URI is no longer listed.
+	Removed []mcp.Root
+}
+
+// RootsChangedFunc is called with the roots of session after it sent
+// notifications/roots/list_changed.
+type RootsChangedFunc func(ctx context.Context, session ClientSession, change RootsChange)
+
+// rootsWatch remembers the roots last fetched for each session, so changes
+// can be reported as a diff.
+type rootsWatch struct {
+	handlers []RootsChangedFunc
+	sessions sync.Map // session ID -> *watchedRoots
+}
+
+// rootsRefreshTimeout bounds the RequestRoots call made for a roots change,
+// so a client that never answers does not hold up the session's later
+// changes.
+const rootsRefreshTimeout = 30 * time.Second
+
+type watchedRoots struct {
+	// ctx is canceled when the session unregisters, abandoning its
+	// refetches.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// mu serializes the refetches of a session so handlers see its changes
+	// in order.
+	mu    sync.Mutex
+	roots []mcp.Root
+	known bool
+}
+
+// OnRootsChanged registers a handler called whenever a client sends
+// notifications/roots/list_changed. The server requests the new list once,
+// with RequestRoots, and passes it to every handler along with what was
+// added and removed, so tools depending on roots do not each have to
+// register a notification handler and refetch.
+//
+// Handlers run outside the request loop of the session. If the roots
+// cannot be listed within 30 seconds, or the session ends first, the change
+// is skipped and the handlers are not called.
+func OnRootsChanged(handler RootsChangedFunc) ServerOption {
+	return func(s *MCPServer) {
+		if s.rootsWatch == nil {
+			s.rootsWatch = &rootsWatch{}
+		}
+		s.rootsWatch.handlers = append(s.rootsWatch.handlers, handler)
+	}
+}
+
+// refreshRoots fetches the roots of the session in ctx and reports the
+// change to the OnRootsChanged handlers.
+func (s *MCPServer) refreshRoots(ctx context.Context) {
+	if s.rootsWatch == nil {
+		return
+	}
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	if _, ok := session.(SessionWithRoots); !ok {
+		return
+	}
+	watched := s.rootsWatch.watch(session.SessionID())
+	if _, ok := s.sessions.Load(session.SessionID()); !ok {
+		// The session unregistered meanwhile; don't keep its entry.
+		s.rootsWatch.forget(session.SessionID())
+		return
+	}
+
+	// Listing the roots is a request to the client, whose response may be
+	// delivered by the very loop that is handling this notification, so
+	// it must outlive the notification but not the session.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(watched.ctx, cancel)
+	go func() {
+		defer cancel()
+		defer stop()
+		watched.mu.Lock()
+		defer watched.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		requestCtx, cancelRequest := context.WithTimeout(ctx, rootsRefreshTimeout)
+		result, err := s.RequestRoots(requestCtx, mcp.ListRootsRequest{})
+		cancelRequest()
+		if err != nil || result == nil {
+			return
+		}
+
+		change := diffRoots(watched.roots, result.Roots, watched.known)
+		watched.roots, watched.known = change.Roots, true
+		for _, handler := range s.rootsWatch.handlers {
+			handler(ctx, session, change)
+		}
+	}()
+}
+
+// watch returns the state of the session with the given ID, creating it on
+// first use.
+func (w *rootsWatch) watch(sessionID string) *watchedRoots {
+	if value, ok := w.sessions.Load(sessionID); ok {
+		return value.(*watchedRoots)
+	}
+	watched := &watchedRoots{}
+	watched.ctx, watched.cancel = context.WithCancel(context.Background())
+	value, loaded := w.sessions.LoadOrStore(sessionID, watched)
+	if loaded {
+		watched.cancel()
+	}
+	return value.(*watchedRoots)
+}
+
+// forget drops the state of a session and abandons its refetches.
+func (w *rootsWatch) forget(sessionID string) {
+	if w == nil {
+		return
+	}
+	if value, ok := w.sessions.LoadAndDelete(sessionID); ok {
+		value.(*watchedRoots).cancel()
+	}
+}
+
+// diffRoots compares two lists of roots by URI.
+func diffRoots(previous, current []mcp.Root, known bool) RootsChange {
+	change := RootsChange{Roots: current}
+	if !known {
+		change.Added = current
+		return change
+	}
+	before := make(map[string]bool, len(previous))
+	for _, root := range previous {
+		before[root.URI] = true
+	}
+	after := make(map[string]bool, len(current))
+	for _, root := range current {
+		after[root.URI] = true
+		if !before[root.URI] {
+			change.Added = append(change.Added, root)
+		}
+	}
+	for _, root := range previous {
+		if !after[root.URI] {
+			change.Removed = append(change.Removed, root)
+		}
+	}
+	return change
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changingRootsSession lists roots that tests can replace concurrently.
+type changingRootsSession struct {
+	mockRootsSession
+	mu    sync.Mutex
+	roots []mcp.Root
+}
+
+func (m *changingRootsSession) setRoots(roots ...mcp.Root) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roots = roots
+}
+
+func (m *changingRootsSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &mcp.ListRootsResult{Roots: m.roots}, nil
+}
+
+func TestOnRootsChanged(t *testing.T) {
+	changes := make(chan RootsChange, 10)
+	s := NewMCPServer("test", "1.0.0",
+		WithRootsCache(0),
+		OnRootsChanged(func(ctx context.Context, session ClientSession, change RootsChange) {
+			assert.Equal(t, "roots", session.SessionID())
+			assert.Same(t, session, ClientSessionFromContext(ctx))
+			changes <- change
+		}),
+	)
+	session := &changingRootsSession{mockRootsSession: mockRootsSession{sessionID: "roots"}}
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+	ctx := s.WithContext(t.Context(), session)
+
+	repo := mcp.Root{Name: "repo", URI: "file:///repo"}
+	docs := mcp.Root{Name: "docs", URI: "file:///docs"}
+	tmp := mcp.Root{Name: "tmp", URI: "file:///tmp"}
+
+	listChanged := func(t *testing.T) RootsChange {
+		t.Helper()
+		s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`))
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(time.Second):
+			t.Fatal("roots change was not reported")
+			return RootsChange{}
+		}
+	}
+
+	session.setRoots(repo, docs)
+	change := listChanged(t)
+	assert.Equal(t, []mcp.Root{repo, docs}, change.Roots)
+	assert.Equal(t, []mcp.Root{repo, docs}, change.Added, "first change reports every root as added")
+	assert.Empty(t, change.Removed)
+
+	session.setRoots(repo, tmp)
+	change = listChanged(t)
+	assert.Equal(t, []mcp.Root{repo, tmp}, change.Roots)
+	assert.Equal(t, []mcp.Root{tmp}, change.Added)
+	assert.Equal(t, []mcp.Root{docs}, change.Removed)
+
+	result, err := s.RequestRoots(ctx, mcp.ListRootsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.Root{repo, tmp}, result.Roots, "refetched roots are cached")
+
+	t.Run("failed refetch is skipped", func(t *testing.T) {
+		session.mu.Lock()
+		session.err = errors.New("client gone")
+		session.mu.Unlock()
+		s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`))
+		select {
+		case change := <-changes:
+			t.Fatalf("unexpected change %+v", change)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+// blockingRootsSession answers roots requests only once they are canceled.
+type blockingRootsSession struct {
+	mockRootsSession
+	called   chan struct{}
+	canceled chan error
+}
+
+func (m *blockingRootsSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	close(m.called)
+	<-ctx.Done()
+	m.canceled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestOnRootsChanged_CanceledOnUnregister(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0",
+		OnRootsChanged(func(ctx context.Context, session ClientSession, change RootsChange) {
+			t.Errorf("unexpected change %+v", change)
+		}),
+	)
+	session := &blockingRootsSession{
+		mockRootsSession: mockRootsSession{sessionID: "roots"},
+		called:           make(chan struct{}),
+		canceled:         make(chan error, 1),
+	}
+	require.NoError(t, s.RegisterSession(t.Context(), session))
+	ctx := s.WithContext(t.Context(), session)
+
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`))
+	select {
+	case <-session.called:
+	case <-time.After(time.Second):
+		t.Fatal("roots were not requested")
+	}
+	s.UnregisterSession(t.Context(), "roots")
+	select {
+	case err := <-session.canceled:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("roots request outlived the session")
+	}
+	_, ok := s.rootsWatch.sessions.Load("roots")
+	assert.False(t, ok)
+}
@@ -24,18 +24,62 @@ func (s *MCPServer) RequestSampling(ctx context.Context, request mcp.CreateMessa
 	if session == nil {
 		return nil, fmt.Errorf("no active session")
 	}
+	if err := s.rateLimiters.allow(ctx, RateLimitSampling); err != nil {
+		return nil, err
+	}
+	reserved := int64(max(request.MaxTokens, 0))
+	if err := s.reserveSampling(session.SessionID(), reserved); err != nil {
+		return nil, err
+	}
+	request.CreateMessageParams.Meta = s.injectMeta(ctx, request.CreateMessageParams.Meta)
 
 	// Check if the session supports sampling requests
 	if samplingSession, ok := session.(SessionWithSampling); ok {
-		return samplingSession.RequestSampling(ctx, request)
+		s.hooks.beforeServerRequest(ctx, session, mcp.MethodSamplingCreateMessage, request.CreateMessageParams)
+		result, err := samplingSession.RequestSampling(ctx, request)
+		s.hooks.afterServerRequest(ctx, session, mcp.MethodSamplingCreateMessage, request.CreateMessageParams, resultOrNil(result, err), err)
+		if err != nil {
+			s.reportDropped(ctx, session, mcp.MethodSamplingCreateMessage, request.CreateMessageParams, err)
+		}
+		s.settleSampling(ctx, session.SessionID(), reserved, result, err)
+		return result, err
 	}
 
 	// Check for inprocess sampling handler in context
 	if handler := InProcessSamplingHandlerFromContext(ctx); handler != nil {
-		return handler.CreateMessage(ctx, request)
+		s.hooks.beforeServerRequest(ctx, session, mcp.MethodSamplingCreateMessage, request.CreateMessageParams)
+		result, err := handler.CreateMessage(ctx, request)
+		s.hooks.afterServerRequest(ctx, session, mcp.MethodSamplingCreateMessage, request.CreateMessageParams, resultOrNil(result, err), err)
+		s.settleSampling(ctx, session.SessionID(), reserved, result, err)
+		return result, err
 	}
 
-	return nil, fmt.Errorf("session does not support sampling")
+	err := fmt.Errorf("session does not support sampling")
+	s.settleSampling(ctx, session.SessionID(), reserved, nil, err)
+	return nil, err
+}
+
+// notifyRequestCancelled tells the client, through the session's
+// notification channel, that the server no longer waits for the response
+// to its request id, so that the client can stop working on it. It does
+// nothing if the channel is full.
+func notifyRequestCancelled(session ClientSession, id any, reason error) {
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: string(mcp.MethodNotificationCancelled),
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{
+					"requestId": id,
+					"reason":    reason.Error(),
+				},
+			},
+		},
+	}
+	select {
+	case session.NotificationChannel() <- notification:
+	default:
+	}
 }
 
 // SessionWithSampling extends ClientSession to support sampling requests.
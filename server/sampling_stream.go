@@ -0,0 +1,252 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultSamplingStreamIdleTimeout is how long RequestSamplingStream waits
+// for the next delta before giving up.
+const DefaultSamplingStreamIdleTimeout = time.Minute
+
+var (
+	// ErrSamplingStreamStalled is returned by RequestSamplingStream when the
+	// client sends neither a delta nor the result for the idle timeout.
+	ErrSamplingStreamStalled = errors.New("sampling stream stalled")
+	// ErrSamplingStreamGap is returned by RequestSamplingStream when a delta
+	// of the stream never arrives, or the client sends more deltas than its
+	// result announces.
+	ErrSamplingStreamGap = errors.New("sampling stream has a gap")
+)
+
+// WithSamplingStreamIdleTimeout sets how long RequestSamplingStream waits
+// for the next delta, or for a delta missing from the sequence, before it
+// cancels the sampling request and fails. A non-positive timeout waits as
+// long as the request's context allows. Defaults to
+// DefaultSamplingStreamIdleTimeout.
+func WithSamplingStreamIdleTimeout(timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.samplingStreamIdleTimeout = timeout
+	}
+}
+
+// samplingStream buffers the deltas of a streamed sampling request until
+// RequestSamplingStream yields them in order.
+type samplingStream struct {
+	mu      sync.Mutex
+	next    int // index of the next delta to yield
+	pending map[int]*mcp.CreateMessageResult
+	ready   chan struct{}
+}
+
+// add buffers a delta and wakes up the consumer. Duplicates are ignored.
+func (st *samplingStream) add(index int, delta *mcp.CreateMessageResult) {
+	st.mu.Lock()
+	if index >= st.next {
+		st.pending[index] = delta
+	}
+	st.mu.Unlock()
+	select {
+	case st.ready <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the buffered deltas that can be yielded in order and the
+// number of deltas taken so far.
+func (st *samplingStream) take() ([]*mcp.CreateMessageResult, int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var deltas []*mcp.CreateMessageResult
+	for {
+		delta, ok := st.pending[st.next]
+		if !ok {
+			return deltas, st.next
+		}
+		delete(st.pending, st.next)
+		deltas = append(deltas, delta)
+		st.next++
+	}
+}
+
+// buffered reports whether deltas beyond a missing one are waiting, and the
+// index of the missing delta.
+func (st *samplingStream) buffered() (missing int, ok bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.next, len(st.pending) > 0
+}
+
+// beyond reports whether a delta at or after index count is buffered.
+func (st *samplingStream) beyond(count int) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for index := range st.pending {
+		if index >= count {
+			return true
+		}
+	}
+	return false
+}
+
+// samplingStreamKey scopes stream IDs to the session that owns them.
+func samplingStreamKey(sessionID, streamID string) string {
+	return sessionID + ":" + streamID
+}
+
+// RequestSamplingStream sends a sampling request that asks the client to
+// stream its result, so long completions can be consumed inside a tool
+// handler while they are generated. The iterator yields the partial results
+// in order; each carries only the new content, and the last one also carries
+// the model and stop reason. Clients that do not stream answer with a single
+// complete result, which is yielded as the only delta.
+//
+// If the request fails, the iterator yields a single (nil, error) pair and
+// stops. Breaking out of the loop cancels the request, and so does a client
+// that sends nothing for the idle timeout (see
+// WithSamplingStreamIdleTimeout), which fails with ErrSamplingStreamStalled,
+// or ErrSamplingStreamGap if a delta is missing from the sequence.
+//
+// Example:
+//
+//	for delta, err := range s.RequestSamplingStream(ctx, request) {
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    if text, ok := delta.Content.(mcp.TextContent); ok {
+//	        builder.WriteString(text.Text)
+//	    }
+//	}
+func (s *MCPServer) RequestSamplingStream(ctx context.Context, request mcp.CreateMessageRequest) iter.Seq2[*mcp.CreateMessageResult, error] {
+	return func(yield func(*mcp.CreateMessageResult, error) bool) {
+		session := ClientSessionFromContext(ctx)
+		if session == nil {
+			yield(nil, fmt.Errorf("no active session"))
+			return
+		}
+
+		streamID := s.newID()
+		stream := &samplingStream{
+			pending: make(map[int]*mcp.CreateMessageResult),
+			ready:   make(chan struct{}, 1),
+		}
+		key := samplingStreamKey(session.SessionID(), streamID)
+		s.samplingStreams.Store(key, stream)
+		defer s.samplingStreams.Delete(key)
+
+		meta := &mcp.Meta{AdditionalFields: map[string]any{}}
+		if request.CreateMessageParams.Meta != nil {
+			meta.ProgressToken = request.CreateMessageParams.Meta.ProgressToken
+			maps.Copy(meta.AdditionalFields, request.CreateMessageParams.Meta.AdditionalFields)
+		}
+		meta.AdditionalFields[mcp.SamplingStreamMetaKey] = streamID
+		request.CreateMessageParams.Meta = meta
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		type response struct {
+			result *mcp.CreateMessageResult
+			err    error
+		}
+		done := make(chan response, 1)
+		go func() {
+			result, err := s.RequestSampling(ctx, request)
+			done <- response{result, err}
+		}()
+
+		// idle fires when nothing arrived for the idle timeout; every delta
+		// and the result restart it.
+		var idle <-chan time.Time
+		restartIdle := func() {}
+		idleTimeout := s.samplingStreamIdleTimeout
+		if idleTimeout > 0 {
+			timer := time.NewTimer(idleTimeout)
+			defer timer.Stop()
+			idle = timer.C
+			restartIdle = func() { timer.Reset(idleTimeout) }
+		}
+
+		// The latest delta is held back until the next one or the final
+		// result arrives, so the stop reason can be attached to it.
+		var held, final *mcp.CreateMessageResult
+		for {
+			deltas, taken := stream.take()
+			for _, delta := range deltas {
+				if held != nil && !yield(held, nil) {
+					return
+				}
+				held = delta
+			}
+			// Deltas may arrive after the result on transports that deliver
+			// messages out of order.
+			if final != nil {
+				expected := mcp.SamplingStreamDeltas(final)
+				if taken > expected || stream.beyond(expected) {
+					yield(nil, fmt.Errorf("%w: more than the %d deltas announced", ErrSamplingStreamGap, expected))
+					return
+				}
+				if taken >= expected {
+					break
+				}
+			}
+
+			select {
+			case <-stream.ready:
+				restartIdle()
+			case r := <-done:
+				restartIdle()
+				if r.err != nil {
+					yield(nil, r.err)
+					return
+				}
+				if r.result == nil {
+					yield(nil, fmt.Errorf("empty sampling result"))
+					return
+				}
+				final = r.result
+			case <-idle:
+				if missing, ok := stream.buffered(); ok || final != nil {
+					yield(nil, fmt.Errorf("%w: delta %d missing after %s", ErrSamplingStreamGap, missing, idleTimeout))
+				} else {
+					yield(nil, fmt.Errorf("%w: nothing received for %s", ErrSamplingStreamStalled, idleTimeout))
+				}
+				return
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			}
+		}
+
+		if held == nil {
+			yield(final, nil)
+			return
+		}
+		held.Model = final.Model
+		held.StopReason = final.StopReason
+		if held.Role == "" {
+			held.Role = final.Role
+		}
+		yield(held, nil)
+	}
+}
+
+// handleSamplingDelta routes a notifications/sampling/delta notification to
+// the stream it belongs to.
+func (s *MCPServer) handleSamplingDelta(ctx context.Context, notification mcp.JSONRPCNotification) {
+	streamID, index, delta, err := mcp.ParseSamplingDeltaNotification(notification)
+	if err != nil {
+		return
+	}
+	value, ok := s.samplingStreams.Load(samplingStreamKey(getSessionID(ctx), streamID))
+	if !ok {
+		return
+	}
+	value.(*samplingStream).add(index, delta)
+}
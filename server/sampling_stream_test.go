@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lateDeltaSamplingSession answers a streamed sampling request first and
+// delivers its deltas afterwards in reverse order, as an HTTP client may.
+type lateDeltaSamplingSession struct {
+	mockSession
+	server *MCPServer
+	words  []string
+}
+
+func (m *lateDeltaSamplingSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	streamID := mcp.SamplingStreamID(request)
+	go func() {
+		ctx := m.server.WithContext(context.Background(), m)
+		for i := len(m.words) - 1; i >= 0; i-- {
+			delta := mcp.CreateMessageResult{SamplingMessage: mcp.SamplingMessage{Content: mcp.NewTextContent(m.words[i])}}
+			data, _ := json.Marshal(mcp.NewSamplingDeltaNotification(streamID, i, delta))
+			m.server.HandleMessage(ctx, data)
+		}
+	}()
+
+	result := &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("ignored")},
+		Model:           "test-model",
+		StopReason:      "maxTokens",
+	}
+	if streamID != "" {
+		mcp.SetSamplingStreamDeltas(result, len(m.words))
+	}
+	return result, nil
+}
+
+func TestMCPServer_RequestSamplingStream(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	s.EnableSampling()
+	session := &lateDeltaSamplingSession{mockSession: mockSession{sessionID: "s1"}, server: s, words: []string{"a", "b", "c"}}
+	ctx := s.WithContext(t.Context(), session)
+	request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{MaxTokens: 10}}
+
+	var texts []string
+	var last *mcp.CreateMessageResult
+	for delta, err := range s.RequestSamplingStream(ctx, request) {
+		require.NoError(t, err)
+		texts = append(texts, delta.Content.(mcp.TextContent).Text)
+		last = delta
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, texts)
+	assert.Equal(t, "test-model", last.Model)
+	assert.Equal(t, "maxTokens", last.StopReason)
+	assert.Equal(t, mcp.RoleAssistant, last.Role)
+
+	// Breaking out early stops the stream and unregisters it.
+	for range s.RequestSamplingStream(ctx, request) {
+		break
+	}
+	s.samplingStreams.Range(func(key, _ any) bool {
+		t.Errorf("stream %v still registered", key)
+		return true
+	})
+}
+
+func TestMCPServer_RequestSamplingStream_Errors(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{MaxTokens: 10}}
+
+	for _, err := range s.RequestSamplingStream(t.Context(), request) {
+		assert.EqualError(t, err, "no active session")
+	}
+
+	ctx := s.WithContext(t.Context(), &mockSession{sessionID: "s1"})
+	var count int
+	for delta, err := range s.RequestSamplingStream(ctx, request) {
+		count++
+		assert.Nil(t, delta)
+		assert.EqualError(t, err, "session does not support sampling")
+	}
+	assert.Equal(t, 1, count)
+}
+
+// scriptedSamplingSession sends the deltas at the given indices, then
+// answers with a result announcing count deltas, or never answers if count
+// is negative.
+type scriptedSamplingSession struct {
+	mockSession
+	server  *MCPServer
+	indices []int
+	count   int
+}
+
+func (m *scriptedSamplingSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	streamID := mcp.SamplingStreamID(request)
+	notifyCtx := m.server.WithContext(context.Background(), m)
+	for _, index := range m.indices {
+		delta := mcp.CreateMessageResult{SamplingMessage: mcp.SamplingMessage{Content: mcp.NewTextContent("x")}}
+		data, _ := json.Marshal(mcp.NewSamplingDeltaNotification(streamID, index, delta))
+		m.server.HandleMessage(notifyCtx, data)
+	}
+	if m.count < 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	result := &mcp.CreateMessageResult{Model: "test-model", StopReason: "endTurn"}
+	mcp.SetSamplingStreamDeltas(result, m.count)
+	return result, nil
+}
+
+func TestMCPServer_RequestSamplingStream_IdleTimeout(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithSamplingStreamIdleTimeout(50*time.Millisecond))
+	request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{MaxTokens: 10}}
+
+	tests := []struct {
+		name    string
+		indices []int
+		count   int
+		yielded int
+		err     error
+	}{
+		{name: "stalled", indices: []int{0}, count: -1, yielded: 0, err: ErrSamplingStreamStalled},
+		{name: "missing delta before the result", indices: []int{0, 2}, count: -1, yielded: 0, err: ErrSamplingStreamGap},
+		{name: "missing delta after the result", indices: []int{0, 2}, count: 3, yielded: 0, err: ErrSamplingStreamGap},
+		{name: "more deltas than announced", indices: []int{0, 1, 2}, count: 2, yielded: 2, err: ErrSamplingStreamGap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &scriptedSamplingSession{mockSession: mockSession{sessionID: tt.name}, server: s, indices: tt.indices, count: tt.count}
+			ctx := s.WithContext(t.Context(), session)
+
+			start := time.Now()
+			var yielded int
+			var err error
+			for delta, deltaErr := range s.RequestSamplingStream(ctx, request) {
+				if deltaErr != nil {
+					err = deltaErr
+					break
+				}
+				require.NotNil(t, delta)
+				yielded++
+			}
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.yielded, yielded)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrSamplingQuotaExceeded is returned by RequestSampling when the session
+// used up its sampling token budget.
+var ErrSamplingQuotaExceeded = errors.New("sampling token budget exceeded")
+
+// SamplingQuotaError reports a sampling request rejected because the session
+// used up its token budget. It wraps ErrSamplingQuotaExceeded. A tool
+// handler returning it fails the tools/call request, or the tasks/result
+// request of a task, with the mcp.SAMPLING_QUOTA_EXCEEDED error code, with
+// the error as data.
+type SamplingQuotaError struct {
+	SessionID string `json:"sessionId"`
+	Budget    int64  `json:"budget"`
+	Used      int64  `json:"used"`
+	// Reserved counts the tokens reserved by the session's sampling
+	// requests still in flight.
+	Reserved int64 `json:"reserved,omitempty"`
+	// Requested is the MaxTokens of the rejected request.
+	Requested int64 `json:"requested,omitempty"`
+}
+
+func (e *SamplingQuotaError) Error() string {
+	return fmt.Sprintf("%s: session %s used %d of %d tokens", ErrSamplingQuotaExceeded, e.SessionID, e.Used+e.Reserved, e.Budget)
+}
+
+func (e *SamplingQuotaError) Unwrap() error {
+	return ErrSamplingQuotaExceeded
+}
+
+// SamplingUsageReport is the sampling token usage of a session.
+type SamplingUsageReport struct {
+	// Usage is the total usage of the session.
+	Usage mcp.SamplingUsage `json:"usage"`
+	// Requests counts the sampling requests that reported usage.
+	Requests int64 `json:"requests"`
+	// ByTool breaks Usage down by the tool whose call made the requests.
+	// Requests made outside a tool call are not included.
+	ByTool map[string]mcp.SamplingUsage `json:"byTool,omitempty"`
+	// Budget is the session's token budget; 0 means unlimited.
+	Budget int64 `json:"budget"`
+}
+
+// WithSamplingBudget limits the tokens each session may consume through
+// sampling, as reported by clients in the mcp.SamplingUsageMetaKey _meta of
+// their results. Each RequestSampling call reserves the MaxTokens of its
+// request until the result arrives, and fails with a *SamplingQuotaError
+// when the tokens consumed and reserved by the session leave no room for
+// it, so concurrent requests cannot overrun the budget together. A result
+// that reports no usage is charged its MaxTokens. Override the budget of
+// single sessions with SetSamplingBudget.
+//
+// Usage is accounted whether or not a budget is set; see
+// SessionSamplingUsage and ServerStats.
+func WithSamplingBudget(tokens int64) ServerOption {
+	return func(s *MCPServer) {
+		s.samplingUsage.defaultBudget = tokens
+	}
+}
+
+// samplingAccounting aggregates sampling token usage per session and tool.
+type samplingAccounting struct {
+	defaultBudget int64
+
+	mu       sync.Mutex
+	sessions map[string]*samplingAccount
+	total    mcp.SamplingUsage
+	byTool   map[string]mcp.SamplingUsage
+	rejected int64 // requests rejected for exceeding a budget
+}
+
+// samplingAccount is the sampling usage of one session.
+type samplingAccount struct {
+	report    SamplingUsageReport
+	hasBudget bool  // report.Budget overrides the default budget
+	reserved  int64 // tokens reserved by requests in flight
+	// unreported counts the tokens charged to the budget for results that
+	// reported no usage.
+	unreported int64
+}
+
+// charged returns the tokens counted against the budget of account.
+func (a *samplingAccount) charged() int64 {
+	return a.report.Usage.TotalTokens() + a.unreported
+}
+
+// account returns the account of the session, creating it if needed. The
+// caller must hold mu.
+func (a *samplingAccounting) account(sessionID string) *samplingAccount {
+	if a.sessions == nil {
+		a.sessions = make(map[string]*samplingAccount)
+	}
+	account, ok := a.sessions[sessionID]
+	if !ok {
+		account = &samplingAccount{}
+		a.sessions[sessionID] = account
+	}
+	return account
+}
+
+// budget returns the budget of account. The caller must hold mu.
+func (a *samplingAccounting) budget(account *samplingAccount) int64 {
+	if account.hasBudget {
+		return account.report.Budget
+	}
+	return a.defaultBudget
+}
+
+// SetSamplingBudget sets the sampling token budget of the session with the
+// given ID, overriding WithSamplingBudget. A non-positive budget removes the
+// limit for the session.
+func (s *MCPServer) SetSamplingBudget(sessionID string, tokens int64) {
+	s.samplingUsage.mu.Lock()
+	defer s.samplingUsage.mu.Unlock()
+	account := s.samplingUsage.account(sessionID)
+	account.report.Budget = max(tokens, 0)
+	account.hasBudget = true
+}
+
+// SessionSamplingUsage returns the sampling token usage of the session with
+// the given ID.
+func (s *MCPServer) SessionSamplingUsage(sessionID string) SamplingUsageReport {
+	s.samplingUsage.mu.Lock()
+	defer s.samplingUsage.mu.Unlock()
+	account, ok := s.samplingUsage.sessions[sessionID]
+	if !ok {
+		return SamplingUsageReport{Budget: s.samplingUsage.defaultBudget}
+	}
+	report := account.report
+	report.ByTool = maps.Clone(account.report.ByTool)
+	report.Budget = s.samplingUsage.budget(account)
+	return report
+}
+
+// reserveSampling reserves maxTokens of the budget of the session with the
+// given ID for a sampling request, or returns a *SamplingQuotaError if they
+// do not fit. Every request reserves at least one token, so a session that
+// used up its budget is rejected even for requests without MaxTokens.
+func (s *MCPServer) reserveSampling(sessionID string, maxTokens int64) error {
+	a := &s.samplingUsage
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	account := a.account(sessionID)
+	budget := a.budget(account)
+	if budget > 0 && account.charged()+account.reserved+max(maxTokens, 1) > budget {
+		a.rejected++
+		return &SamplingQuotaError{
+			SessionID: sessionID,
+			Budget:    budget,
+			Used:      account.charged(),
+			Reserved:  account.reserved,
+			Requested: maxTokens,
+		}
+	}
+	account.reserved += maxTokens
+	return nil
+}
+
+// settleSampling releases the reservation of a sampling request and
+// accounts for the usage its result reports. A result that reports none is
+// charged the reserved tokens; a failed request is charged nothing.
+func (s *MCPServer) settleSampling(ctx context.Context, sessionID string, reserved int64, result *mcp.CreateMessageResult, err error) {
+	if err != nil {
+		result = nil
+	}
+	var usage mcp.SamplingUsage
+	reported := false
+	if result != nil {
+		usage, reported = mcp.SamplingUsageFromResult(result)
+	}
+	call, _ := ctx.Value(samplingCallKey{}).(*samplingCall)
+
+	a := &s.samplingUsage
+	a.mu.Lock()
+	// The account is gone if the session closed during the request.
+	account, ok := a.sessions[sessionID]
+	if ok {
+		account.reserved -= reserved
+		if result != nil && !reported {
+			account.unreported += reserved
+		}
+	}
+	if !reported {
+		a.mu.Unlock()
+		return
+	}
+	if !ok {
+		account = &samplingAccount{}
+	}
+	account.report.Usage = account.report.Usage.Add(usage)
+	account.report.Requests++
+	a.total = a.total.Add(usage)
+	if call != nil {
+		if account.report.ByTool == nil {
+			account.report.ByTool = make(map[string]mcp.SamplingUsage)
+		}
+		account.report.ByTool[call.tool] = account.report.ByTool[call.tool].Add(usage)
+		if a.byTool == nil {
+			a.byTool = make(map[string]mcp.SamplingUsage)
+		}
+		a.byTool[call.tool] = a.byTool[call.tool].Add(usage)
+	}
+	a.mu.Unlock()
+
+	if call != nil {
+		call.add(usage)
+	}
+}
+
+// dropSamplingAccount forgets the usage of a closed session. Server-wide
+// totals keep it.
+func (s *MCPServer) dropSamplingAccount(sessionID string) {
+	s.samplingUsage.mu.Lock()
+	delete(s.samplingUsage.sessions, sessionID)
+	s.samplingUsage.mu.Unlock()
+}
+
+// samplingTotals returns the server-wide usage, in total and by tool.
+func (s *MCPServer) samplingTotals() (mcp.SamplingUsage, map[string]mcp.SamplingUsage) {
+	s.samplingUsage.mu.Lock()
+	defer s.samplingUsage.mu.Unlock()
+	return s.samplingUsage.total, maps.Clone(s.samplingUsage.byTool)
+}
+
+// samplingRejections returns how many sampling requests were rejected for
+// exceeding a budget.
+func (s *MCPServer) samplingRejections() int64 {
+	s.samplingUsage.mu.Lock()
+	defer s.samplingUsage.mu.Unlock()
+	return s.samplingUsage.rejected
+}
+
+// samplingQuotaData returns the *SamplingQuotaError wrapped by err, as the
+// data of the mcp.SAMPLING_QUOTA_EXCEEDED error it is reported with.
+func samplingQuotaData(err error) (*SamplingQuotaError, bool) {
+	var quotaErr *SamplingQuotaError
+	if errors.As(err, &quotaErr) {
+		return quotaErr, true
+	}
+	return nil, false
+}
+
+// samplingCall accumulates the sampling usage of one tool call.
+type samplingCall struct {
+	tool string
+
+	mu    sync.Mutex
+	usage mcp.SamplingUsage
+	used  bool
+}
+
+type samplingCallKey struct{}
+
+// withSamplingCall attributes the sampling requests made with the returned
+// context to a call of the named tool.
+func withSamplingCall(ctx context.Context, tool string) (context.Context, *samplingCall) {
+	call := &samplingCall{tool: tool}
+	return context.WithValue(ctx, samplingCallKey{}, call), call
+}
+
+func (c *samplingCall) add(usage mcp.SamplingUsage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage = c.usage.Add(usage)
+	c.used = true
+}
+
+// annotate records the call's usage in the _meta of result, if the call
+// made sampling requests that reported usage.
+func (c *samplingCall) annotate(result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.used && result != nil {
+		mcp.SetToolSamplingUsage(result, c.usage)
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingUsageAccounting(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithStatsResource(0), WithSamplingBudget(100))
+	s.EnableSampling()
+	s.AddTool(mcp.NewTool("summarize"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := s.RequestSampling(ctx, mcp.CreateMessageRequest{}); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("summary"), nil
+	})
+
+	session := &mockSamplingSession{
+		mockSession: mockSession{sessionID: "s1"},
+		result: &mcp.CreateMessageResult{
+			Result: mcp.Result{Meta: mcp.NewMetaFromMap(map[string]any{
+				// As decoded from the client's JSON response.
+				mcp.SamplingUsageMetaKey: map[string]any{"inputTokens": float64(30), "outputTokens": float64(20)},
+			})},
+			Model: "test-model",
+		},
+	}
+	ctx := s.WithContext(context.Background(), session)
+	call := func() mcp.JSONRPCMessage {
+		return s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"summarize"}}`))
+	}
+
+	for range 2 {
+		resp, ok := call().(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result := resp.Result.(*mcp.CallToolResult)
+		usage, ok := mcp.SamplingUsageFromMeta(result.Meta)
+		require.True(t, ok, "tool results carry the usage of the call")
+		assert.Equal(t, mcp.SamplingUsage{InputTokens: 30, OutputTokens: 20}, usage)
+	}
+
+	t.Run("budget exhausted", func(t *testing.T) {
+		errResp, ok := call().(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.SAMPLING_QUOTA_EXCEEDED, errResp.Error.Code)
+		assert.Equal(t, &SamplingQuotaError{SessionID: "s1", Budget: 100, Used: 100}, errResp.Error.Data)
+
+		_, err := s.RequestSampling(ctx, mcp.CreateMessageRequest{})
+		assert.ErrorIs(t, err, ErrSamplingQuotaExceeded)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		report := s.SessionSamplingUsage("s1")
+		assert.Equal(t, SamplingUsageReport{
+			Usage:    mcp.SamplingUsage{InputTokens: 60, OutputTokens: 40},
+			Requests: 2,
+			ByTool:   map[string]mcp.SamplingUsage{"summarize": {InputTokens: 60, OutputTokens: 40}},
+			Budget:   100,
+		}, report)
+
+		stats := s.Stats()
+		assert.Equal(t, int64(100), stats.SamplingUsage.TotalTokens())
+		assert.Equal(t, int64(100), stats.SamplingUsageByTool["summarize"].TotalTokens())
+	})
+
+	t.Run("per-session budget", func(t *testing.T) {
+		s.SetSamplingBudget("s1", 0)
+		assert.IsType(t, mcp.JSONRPCResponse{}, call())
+		assert.Equal(t, int64(0), s.SessionSamplingUsage("s1").Budget)
+
+		s.SetSamplingBudget("s1", 1000)
+		assert.IsType(t, mcp.JSONRPCResponse{}, call())
+	})
+}
+
+// blockingSamplingSession answers sampling requests with result once
+// release is closed.
+type blockingSamplingSession struct {
+	mockSession
+	started chan struct{}
+	release chan struct{}
+	result  *mcp.CreateMessageResult
+}
+
+func (m *blockingSamplingSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	m.started <- struct{}{}
+	<-m.release
+	return m.result, nil
+}
+
+func TestSamplingBudget_Reservation(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithMetrics(), WithSamplingBudget(100))
+	s.EnableSampling()
+	session := &blockingSamplingSession{
+		mockSession: mockSession{sessionID: "s1"},
+		started:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+		// Reports no usage, so it is charged its MaxTokens.
+		result: &mcp.CreateMessageResult{Model: "test-model"},
+	}
+	ctx := s.WithContext(context.Background(), session)
+	request := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{MaxTokens: 60}}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.RequestSampling(ctx, request)
+		done <- err
+	}()
+	<-session.started
+
+	// The request in flight holds 60 tokens, so another one does not fit.
+	_, err := s.RequestSampling(ctx, request)
+	var quotaErr *SamplingQuotaError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, &SamplingQuotaError{SessionID: "s1", Budget: 100, Reserved: 60, Requested: 60}, quotaErr)
+
+	close(session.release)
+	require.NoError(t, <-done)
+	_, err = s.RequestSampling(ctx, mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{MaxTokens: 50}})
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, int64(60), quotaErr.Used, "a result without usage is charged its MaxTokens")
+	assert.Equal(t, int64(0), quotaErr.Reserved)
+
+	var out strings.Builder
+	require.NoError(t, s.Metrics().Write(&out))
+	assert.Contains(t, out.String(), "mcp_server_sampling_budget_rejections_total 2\n")
+	assert.Contains(t, out.String(), `mcp_server_sampling_tokens_total{type="input"} 0`+"\n")
+}
+
+func TestSamplingBudget_TaskResult(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true))
+	session := fakeSession{sessionID: "s1", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	ctx := s.WithContext(context.Background(), session)
+
+	_, err := s.createTask(ctx, "task-1", "summarize", nil, nil)
+	require.NoError(t, err)
+	entry, err := s.getTaskEntry(ctx, "task-1")
+	require.NoError(t, err)
+	quotaErr := &SamplingQuotaError{SessionID: "s1", Budget: 100, Used: 100}
+	s.completeTask(entry, nil, fmt.Errorf("summarize: %w", quotaErr))
+
+	resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/result","params":{"taskId":"task-1"}}`))
+	errResp, ok := resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.SAMPLING_QUOTA_EXCEEDED, errResp.Error.Code)
+	assert.Equal(t, quotaErr, errResp.Error.Data)
+}
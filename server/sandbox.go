@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrSandboxTimeout is reported when a tool call exceeds the timeout of its
+// sandbox profile.
+var ErrSandboxTimeout = errors.New("tool exceeded sandbox timeout")
+
+// SandboxEnforcer runs a tool call under the limits of its sandbox profile.
+// Implementations map profiles to real enforcement such as rlimits,
+// containers or WASM runtimes, and must call next (or an equivalent
+// isolated execution of the tool) to produce the result.
+type SandboxEnforcer interface {
+	Enforce(ctx context.Context, profile mcp.SandboxProfile, request mcp.CallToolRequest, next ToolHandlerFunc) (*mcp.CallToolResult, error)
+}
+
+// SandboxEnforcerFunc is an adapter to allow the use of ordinary functions
+// as SandboxEnforcer.
+type SandboxEnforcerFunc func(ctx context.Context, profile mcp.SandboxProfile, request mcp.CallToolRequest, next ToolHandlerFunc) (*mcp.CallToolResult, error)
+
+// Enforce calls f.
+func (f SandboxEnforcerFunc) Enforce(ctx context.Context, profile mcp.SandboxProfile, request mcp.CallToolRequest, next ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	return f(ctx, profile, request, next)
+}
+
+// TimeoutEnforcer is a SandboxEnforcer that applies only the profile's
+// timeout, by canceling the handler's context. Other limits are left to the
+// deployment. A handler still running at the deadline gets a tool error
+// result.
+func TimeoutEnforcer() SandboxEnforcer {
+	return SandboxEnforcerFunc(func(ctx context.Context, profile mcp.SandboxProfile, request mcp.CallToolRequest, next ToolHandlerFunc) (*mcp.CallToolResult, error) {
+		if profile.Timeout() <= 0 {
+			return next(ctx, request)
+		}
+		ctx, cancel := context.WithTimeout(ctx, profile.Timeout())
+		defer cancel()
+
+		type outcome struct {
+			result *mcp.CallToolResult
+			err    error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			result, err := next(ctx, request)
+			done <- outcome{result, err}
+		}()
+		select {
+		case o := <-done:
+			return o.result, o.err
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return mcp.NewToolResultError(fmt.Sprintf("%v (%s)", ErrSandboxTimeout, profile.Timeout())), nil
+			}
+			return nil, ctx.Err()
+		}
+	})
+}
+
+// WithSandboxEnforcer runs every call of a tool declaring a sandbox profile
+// (see mcp.WithSandboxProfile) through enforcer. Tools without a profile are
+// called directly.
+func WithSandboxEnforcer(enforcer SandboxEnforcer) ServerOption {
+	return func(s *MCPServer) {
+		s.Use(func(next ToolHandlerFunc) ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				tool, ok := s.lookupTool(ctx, request.Params.Name)
+				if !ok {
+					return next(ctx, request)
+				}
+				profile, ok := mcp.SandboxProfileFromTool(tool)
+				if !ok {
+					return next(ctx, request)
+				}
+				return enforcer.Enforce(ctx, profile, request, next)
+			}
+		})
+	}
+}
+
+// lookupTool finds a tool by name among the session's tools and then the
+// server's tools.
+func (s *MCPServer) lookupTool(ctx context.Context, name string) (mcp.Tool, bool) {
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithTools); ok {
+		if tool, ok := session.GetSessionTools()[name]; ok {
+			return tool.Tool, true
+		}
+	}
+	if tool := s.GetTool(name); tool != nil {
+		return tool.Tool, true
+	}
+	return mcp.Tool{}, false
+}
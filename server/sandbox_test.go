@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSandboxEnforcer(t *testing.T) {
+	var enforced []mcp.SandboxProfile
+	recorder := SandboxEnforcerFunc(func(ctx context.Context, profile mcp.SandboxProfile, request mcp.CallToolRequest, next ToolHandlerFunc) (*mcp.CallToolResult, error) {
+		enforced = append(enforced, profile)
+		return TimeoutEnforcer().Enforce(ctx, profile, request, next)
+	})
+	server := NewMCPServer("test", "1.0.0", WithSandboxEnforcer(recorder))
+
+	server.AddTool(mcp.NewTool("plain"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("plain"), nil
+	})
+	server.AddTool(mcp.NewTool("fast", mcp.WithSandboxProfile(mcp.SandboxProfile{Name: "fast", TimeoutMillis: 1000})),
+		func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("fast"), nil
+		})
+	server.AddTool(mcp.NewTool("slow", mcp.WithSandboxProfile(mcp.SandboxProfile{Name: "slow", TimeoutMillis: 10})),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			return mcp.NewToolResultText("slow"), nil
+		})
+
+	call := func(name string) *mcp.CallToolResult {
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+name+`"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", response)
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		require.True(t, ok)
+		return result
+	}
+
+	assert.False(t, call("plain").IsError)
+	assert.Empty(t, enforced, "tools without a profile bypass the enforcer")
+
+	result := call("fast")
+	assert.False(t, result.IsError)
+	assert.Equal(t, "fast", result.Content[0].(mcp.TextContent).Text)
+
+	result = call("slow")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, ErrSandboxTimeout.Error())
+
+	require.Len(t, enforced, 2)
+	assert.Equal(t, "fast", enforced[0].Name)
+	assert.Equal(t, "slow", enforced[1].Name)
+}
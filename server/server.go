@@ -15,8 +15,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
-
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/tracing"
 )
@@ -29,14 +27,16 @@ type resourceEntry struct {
 
 // resourceTemplateEntry holds both a template and its handler
 type resourceTemplateEntry struct {
-	template mcp.ResourceTemplate
-	handler  ResourceTemplateHandlerFunc
+	template    mcp.ResourceTemplate
+	handler     ResourceTemplateHandlerFunc
+	completions map[string]TemplateCompleterFunc
 }
 
 // taskEntry holds task state and associated data
 type taskEntry struct {
 	task       mcp.Task
 	sessionID  string
+	owner      string             // Authenticated identity that created this task, if known
 	toolName   string             // Name of the tool that created this task
 	createdAt  time.Time          // When the task was created (for metrics)
 	result     any                // The actual result once completed
@@ -44,6 +44,10 @@ type taskEntry struct {
 	cancelFunc context.CancelFunc // Function to cancel the task
 	done       chan struct{}      // Channel to signal task completion
 	completed  bool               // Whether the task has been completed (guards done channel closure)
+	detached   bool               // Loaded from the task store; not running in this process
+
+	deadline      time.Time   // When the task fails if still running; zero if none
+	deadlineTimer *time.Timer // Enforces deadline
 }
 
 // ServerOption is a function that configures an MCPServer.
@@ -69,6 +73,9 @@ type TaskToolHandlerFunc func(ctx context.Context, request mcp.CallToolRequest)
 // ToolHandlerMiddleware is a middleware function that wraps a ToolHandlerFunc.
 type ToolHandlerMiddleware func(ToolHandlerFunc) ToolHandlerFunc
 
+// ToolMiddleware is an alias of ToolHandlerMiddleware.
+type ToolMiddleware = ToolHandlerMiddleware
+
 // ResourceHandlerMiddleware is a middleware function that wraps a ResourceHandlerFunc.
 type ResourceHandlerMiddleware func(ResourceHandlerFunc) ResourceHandlerFunc
 
@@ -79,6 +86,10 @@ type ResourceHandlerMiddleware func(ResourceHandlerFunc) ResourceHandlerFunc
 // requested tool to keep the call-time access check off the full-list hot path.
 type ToolFilterFunc func(ctx context.Context, tools []mcp.Tool) []mcp.Tool
 
+// SessionToolFilterFunc is a ToolFilterFunc that also receives the session
+// making the request, or nil if the request has no session.
+type SessionToolFilterFunc func(ctx context.Context, session ClientSession, tools []mcp.Tool) []mcp.Tool
+
 // PromptHandlerMiddleware is a middleware function that wraps a PromptHandlerFunc.
 type PromptHandlerMiddleware func(PromptHandlerFunc) PromptHandlerFunc
 
@@ -118,6 +129,9 @@ type ServerResource struct {
 type ServerResourceTemplate struct {
 	Template mcp.ResourceTemplate
 	Handler  ResourceTemplateHandlerFunc
+	// Completions maps template variables to their completers; see
+	// WithTemplateCompletion.
+	Completions map[string]TemplateCompleterFunc
 }
 
 // serverKey is the context key for storing the server instance
@@ -161,6 +175,7 @@ type requestError struct {
 	id   any
 	code int
 	err  error
+	data any
 }
 
 func (e *requestError) Error() string {
@@ -171,7 +186,7 @@ func (e *requestError) ToJSONRPCError() mcp.JSONRPCError {
 	return mcp.JSONRPCError{
 		JSONRPC: mcp.JSONRPC_VERSION,
 		ID:      mcp.NewRequestId(e.id),
-		Error:   mcp.NewJSONRPCErrorDetails(e.code, e.err.Error(), nil),
+		Error:   mcp.NewJSONRPCErrorDetails(e.code, e.err.Error(), e.data),
 	}
 }
 
@@ -197,6 +212,7 @@ type MCPServer struct {
 	toolFiltersMu          sync.RWMutex
 	promptFiltersMu        sync.RWMutex
 	tasksMu                sync.RWMutex
+	instructionsMu         sync.RWMutex
 
 	name                       string
 	version                    string
@@ -208,6 +224,7 @@ type MCPServer struct {
 	promptHandlers             map[string]PromptHandlerFunc
 	tools                      map[string]ServerTool
 	taskTools                  map[string]ServerTaskTool
+	disabledTools              map[string]string
 	toolHandlerMiddlewares     []ToolHandlerMiddleware
 	resourceHandlerMiddlewares []ResourceHandlerMiddleware
 	promptHandlerMiddlewares   []PromptHandlerMiddleware
@@ -222,23 +239,84 @@ type MCPServer struct {
 	hooks                      *Hooks
 	taskHooks                  *TaskHooks
 	tasks                      map[string]*taskEntry
+	taskStore                  TaskStore
+	sessionScopedTasks         bool
+	taskSubscriptionsEnabled   bool
+	taskOwner                  TaskOwnerFunc
+	resourceChunkSize          int
+	resourceStreams            sync.Map // stream ID -> *openResourceStream
+	mappedFiles                mappedFileCache
+	latencyHints               mcp.LatencyHints
+	samplingUsage              samplingAccounting
+	elicitationTimeout         time.Duration
+	elicitationReminder        time.Duration
+	elicitationCancelOnExpiry  bool
+	elicitationDedup           *elicitationDeduper
+	urlElicitationTTL          time.Duration
+	urlElicitations            urlElicitationRegistry
+	stats                      *serverStats
+	metrics                    *Metrics
+	taskSubscriptions          sync.Map             // session ID -> taskSubscription
 	expiredTasks               map[string]time.Time // Tracks recently expired task IDs with expiration timestamp
 	maxConcurrentTasks         *int                 // Optional limit on concurrent running tasks
 	activeTasks                int                  // Current count of running (non-terminal) tasks
 	inflightCancels            sync.Map             // Maps request ID -> context.CancelFunc for in-flight requests
+	samplingStreams            sync.Map             // Maps session-scoped stream ID -> *samplingStream
+	samplingStreamIdleTimeout  time.Duration
+	requestDroppedHooks        []OnRequestDroppedHookFunc
+	resourceSubscribers        resourceSubscribers
+	taskScheduler              *taskScheduler
+	taskTimeouts               map[string]time.Duration // tool name ("" for all) -> task timeout
+	rootsCache                 *rootsCache
+	rootsWatch                 *rootsWatch
+	workspaceDirs              []string
+	workspaceAccess            WorkspaceAccessFunc
 	inputValidator             *inputSchemaValidator
+	inputValidationAsError     bool
 	outputValidator            *outputSchemaValidator
+	skipTaskResultValidation   bool
 	strictInputSchemaDefault   bool
+	toolCollisionPolicy        ToolCollisionPolicy
 	tracer                     tracing.Tracer
 	propagator                 tracing.Propagator
 	metaPropagator             tracing.MetaPropagator
 	requestLogger              *slog.Logger
+	errorBudget                *ErrorBudget
+	requestMiddlewares         []RequestMiddleware
+	faultInjector              *faultInjector
+	clock                      Clock
+	idSource                   IDSource
+	logRateLimits              map[mcp.LoggingLevel]LogRateLimit
+	logLimiters                sync.Map // session ID -> *logLimiter
+	extensions                 map[string]any
+	rateLimiters               *rateLimiters
+	authenticator              Authenticator
+	progressThrottle           *progressThrottle
+	sessionLabelers            []SessionLabeler
+	sessionLabels              sync.Map // session ID -> map[string]string
+	diagnosticsMu              sync.RWMutex
+	requirements               []Requirement
+	lastDiagnostics            *DiagnosticsReport
+}
+
+// WithPaginationLimit sets the pagination limit for the server. It is
+// equivalent to WithPageSize.
+func WithPaginationLimit(limit int) ServerOption {
+	return WithPageSize(limit)
 }
 
-// WithPaginationLimit sets the pagination limit for the server.
-func WithPaginationLimit(limit int) ServerOption {
+// WithPageSize paginates tools/list, resources/list,
+// resources/templates/list, prompts/list and tasks/list, returning at most
+// n items per response along with a nextCursor for the following page.
+// A non-positive n returns every item in a single response, which is the
+// default.
+func WithPageSize(n int) ServerOption {
 	return func(s *MCPServer) {
-		s.paginationLimit = &limit
+		if n <= 0 {
+			s.paginationLimit = nil
+			return
+		}
+		s.paginationLimit = &n
 	}
 }
 
@@ -254,6 +332,7 @@ type serverCapabilities struct {
 	tasks        *taskCapabilities
 	completions  *bool
 	experimental map[string]any
+	encodings    *mcp.EncodingOffer
 }
 
 // resourceCapabilities defines the supported resource-related features
@@ -316,6 +395,28 @@ func WithToolHandlerMiddleware(
 	}
 }
 
+// WithToolMiddleware adds one or more middlewares that wrap every registered
+// tool handler, including session tools and tools run as tasks. Use it for
+// cross-cutting behavior such as argument validation, timing, audit logging
+// or result redaction. Middleware is applied in the order given (outermost
+// first), after any registered earlier.
+func WithToolMiddleware(mw ...ToolMiddleware) ServerOption {
+	return func(s *MCPServer) {
+		s.Use(mw...)
+	}
+}
+
+// ChainToolMiddleware composes middlewares into one, the first being the
+// outermost.
+func ChainToolMiddleware(mw ...ToolMiddleware) ToolMiddleware {
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+}
+
 // Use adds one or more tool handler middlewares to the server.
 // Middleware is applied in the order added (outermost first), matching net/http convention.
 func (s *MCPServer) Use(mw ...ToolHandlerMiddleware) {
@@ -324,6 +425,13 @@ func (s *MCPServer) Use(mw ...ToolHandlerMiddleware) {
 	s.toolMiddlewareMu.Unlock()
 }
 
+// wrapToolHandler applies the registered tool middlewares to handler.
+func (s *MCPServer) wrapToolHandler(handler ToolHandlerFunc) ToolHandlerFunc {
+	s.toolMiddlewareMu.RLock()
+	defer s.toolMiddlewareMu.RUnlock()
+	return ChainToolMiddleware(s.toolHandlerMiddlewares...)(handler)
+}
+
 // WithResourceHandlerMiddleware allows adding a middleware for the
 // resource handler call chain.
 func WithResourceHandlerMiddleware(
@@ -370,6 +478,16 @@ func WithToolFilter(
 	}
 }
 
+// WithSessionToolFilter adds a filter like WithToolFilter that receives the
+// requesting session, so multi-tenant servers can expose a different subset
+// of the shared tool registry to each authenticated user. The filter is
+// evaluated at tools/list and enforced at tools/call.
+func WithSessionToolFilter(toolFilter SessionToolFilterFunc) ServerOption {
+	return WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		return toolFilter(ctx, ClientSessionFromContext(ctx), tools)
+	})
+}
+
 // WithPromptHandlerMiddleware allows adding a middleware for the
 // prompt handler call chain.
 func WithPromptHandlerMiddleware(
@@ -439,6 +557,20 @@ func WithInputSchemaValidation() ServerOption {
 	}
 }
 
+// WithSchemaValidation enables server-side validation of tool call
+// arguments against each tool's declared inputSchema, like
+// [WithInputSchemaValidation], but rejects invalid calls with a JSON-RPC
+// INVALID_PARAMS error instead of a tool execution error. The error data is
+// a [SchemaValidationError] listing one message per offending field, so
+// handlers can rely on their arguments matching the schema without
+// hand-rolling type assertions.
+func WithSchemaValidation() ServerOption {
+	return func(s *MCPServer) {
+		WithInputSchemaValidation()(s)
+		s.inputValidationAsError = true
+	}
+}
+
 // WithStrictInputSchemaDefault sets additionalProperties:false on every
 // registered tool's input schema when the tool author has not configured the
 // field explicitly. Tools published by such a server reject unknown property
@@ -490,6 +622,17 @@ func WithOutputSchemaValidation() ServerOption {
 	}
 }
 
+// WithTaskResultValidation sets whether task results are checked against the
+// tool's declared output schema before they are stored. It is enabled by
+// default, independently of WithOutputSchemaValidation, so that clients can
+// decode task results with mcp.ParseTaskResultAs. A result that does not
+// conform, or that has no structured content, is stored as a tool error.
+func WithTaskResultValidation(enabled bool) ServerOption {
+	return func(s *MCPServer) {
+		s.skipTaskResultValidation = !enabled
+	}
+}
+
 // WithHooks allows adding hooks that will be called before or after
 // either [all] requests or before / after specific request methods, or else
 // prior to returning an error to the client.
@@ -515,6 +658,23 @@ func WithTaskHooks(taskHooks *TaskHooks) ServerOption {
 	}
 }
 
+// WithSessionScopedTasks makes every task private to the client session that
+// created it, as required for multi-tenant deployments. tasks/get,
+// tasks/result, tasks/cancel and tasks/list from any other session behave as
+// if the task did not exist, requests without a session only see tasks
+// created without one, and task status notifications are only sent to the
+// owning session.
+//
+// Without this option, isolation is best effort: tasks created or queried
+// outside a session are visible to everyone. Scoping relies on session IDs,
+// so stateless streamable HTTP servers, whose requests all share the empty
+// session ID, cannot separate their clients this way.
+func WithSessionScopedTasks() ServerOption {
+	return func(s *MCPServer) {
+		s.sessionScopedTasks = true
+	}
+}
+
 // WithMaxConcurrentTasks sets a limit on the maximum number of concurrent running tasks.
 // When this limit is reached, attempts to create new tasks will fail with an error.
 // If not set (or set to 0), there is no limit on concurrent tasks.
@@ -584,6 +744,24 @@ func WithInstructions(instructions string) ServerOption {
 	}
 }
 
+// SetInstructions replaces the server instructions returned in the initialize
+// response. MCP has no notification for instruction changes, so sessions that
+// are already initialized keep the instructions they were given and only see
+// the new text after they reconnect and initialize again.
+func (s *MCPServer) SetInstructions(instructions string) {
+	s.instructionsMu.Lock()
+	defer s.instructionsMu.Unlock()
+	s.instructions = instructions
+}
+
+// Instructions returns the server instructions currently advertised in the
+// initialize response.
+func (s *MCPServer) Instructions() string {
+	s.instructionsMu.RLock()
+	defer s.instructionsMu.RUnlock()
+	return s.instructions
+}
+
 // WithCompletions enables the completion capability
 func WithCompletions() ServerOption {
 	return func(s *MCPServer) {
@@ -648,6 +826,7 @@ func NewMCPServer(
 		promptHandlers:             make(map[string]PromptHandlerFunc),
 		tools:                      make(map[string]ServerTool),
 		taskTools:                  make(map[string]ServerTaskTool),
+		disabledTools:              make(map[string]string),
 		toolHandlerMiddlewares:     make([]ToolHandlerMiddleware, 0),
 		resourceHandlerMiddlewares: make([]ResourceHandlerMiddleware, 0),
 		promptHandlerMiddlewares:   make([]PromptHandlerMiddleware, 0),
@@ -655,6 +834,7 @@ func NewMCPServer(
 		version:                    version,
 		notificationHandlers:       make(map[string]NotificationHandlerFunc),
 		tasks:                      make(map[string]*taskEntry),
+		taskStore:                  NewInMemoryTaskStore(),
 		expiredTasks:               make(map[string]time.Time),
 		promptCompletionProvider:   &DefaultPromptCompletionProvider{},
 		resourceCompletionProvider: &DefaultResourceCompletionProvider{},
@@ -669,19 +849,30 @@ func NewMCPServer(
 			tasks:       nil,
 			completions: nil,
 		},
-		tracer:     tracing.NoopTracer(),
-		propagator: tracing.NoopPropagator(),
+		tracer:                    tracing.NoopTracer(),
+		propagator:                tracing.NoopPropagator(),
+		samplingStreamIdleTimeout: DefaultSamplingStreamIdleTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.stats != nil {
+		s.stats.startedAt = s.now()
+		s.registerStatsResource()
+	}
+	if len(s.requirements) > 0 {
+		s.CheckRequirements(context.Background())
+	}
 
 	return s
 }
 
 // GenerateInProcessSessionID generates a unique session ID for inprocess clients
 func (s *MCPServer) GenerateInProcessSessionID() string {
+	if s.idSource != nil {
+		return "inprocess-" + s.newID()
+	}
 	return GenerateInProcessSessionID()
 }
 
@@ -776,14 +967,21 @@ func (s *MCPServer) AddResourceTemplates(resourceTemplates ...ServerResourceTemp
 	s.implicitlyRegisterResourceCapabilities()
 
 	s.resourcesMu.Lock()
+	completions := false
 	for _, entry := range resourceTemplates {
 		s.resourceTemplates[entry.Template.URITemplate.Raw()] = resourceTemplateEntry{
-			template: entry.Template,
-			handler:  entry.Handler,
+			template:    entry.Template,
+			handler:     entry.Handler,
+			completions: entry.Completions,
 		}
+		completions = completions || len(entry.Completions) > 0
 	}
 	s.resourcesMu.Unlock()
 
+	if completions {
+		s.implicitlyRegisterCompletionCapabilities()
+	}
+
 	// When the list of available resources changes, servers that declared the listChanged capability SHOULD send a notification
 	if s.capabilities.resources.listChanged {
 		// Send notification to all initialized sessions
@@ -799,12 +997,35 @@ func (s *MCPServer) SetResourceTemplates(templates ...ServerResourceTemplate) {
 	s.AddResourceTemplates(templates...)
 }
 
+// DeleteResourceTemplates removes resource templates from the server
+func (s *MCPServer) DeleteResourceTemplates(uriTemplates ...string) {
+	s.resourcesMu.Lock()
+	var exists bool
+	for _, uriTemplate := range uriTemplates {
+		if _, ok := s.resourceTemplates[uriTemplate]; ok {
+			delete(s.resourceTemplates, uriTemplate)
+			exists = true
+		}
+	}
+	s.resourcesMu.Unlock()
+
+	// Send notification to all initialized sessions if listChanged capability is enabled and we actually remove a template
+	if exists && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
+		s.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+	}
+}
+
 // AddResourceTemplate registers a new resource template and its handler
 func (s *MCPServer) AddResourceTemplate(
 	template mcp.ResourceTemplate,
 	handler ResourceTemplateHandlerFunc,
+	opts ...ResourceTemplateOption,
 ) {
-	s.AddResourceTemplates(ServerResourceTemplate{Template: template, Handler: handler})
+	entry := ServerResourceTemplate{Template: template, Handler: handler}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	s.AddResourceTemplates(entry)
 }
 
 // AddPrompts registers multiple prompts at once
@@ -921,6 +1142,13 @@ func (s *MCPServer) implicitlyRegisterResourceCapabilities() {
 	)
 }
 
+func (s *MCPServer) implicitlyRegisterCompletionCapabilities() {
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.completions != nil && *s.capabilities.completions },
+		func() { s.capabilities.completions = mcp.ToBoolPtr(true) },
+	)
+}
+
 func (s *MCPServer) implicitlyRegisterPromptCapabilities() {
 	s.implicitlyRegisterCapabilities(
 		func() bool { return s.capabilities.prompts != nil },
@@ -943,71 +1171,162 @@ func (s *MCPServer) implicitlyRegisterCapabilities(check func() bool, register f
 	s.capabilitiesMu.Unlock()
 }
 
-// AddTools registers multiple tools at once
+// AddTools registers multiple tools at once. It panics if the collision
+// policy rejects one of them; use RegisterTools to handle that as an error.
 func (s *MCPServer) AddTools(tools ...ServerTool) {
+	if _, err := s.RegisterTools(tools...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterTools registers multiple tools at once and returns the names they
+// were registered under, which differ from the tools' names when the
+// ToolCollisionPrefix policy prefixed them. If a tool is rejected, because
+// of the collision policy or because a task tool has its name, it returns an
+// error wrapping ErrToolNameCollision and registers none of the tools.
+func (s *MCPServer) RegisterTools(tools ...ServerTool) ([]string, error) {
 	s.implicitlyRegisterToolCapabilities()
 
 	s.toolsMu.Lock()
-	for _, entry := range tools {
-		name := entry.Tool.Name
-		// Check for collision with task tools
-		if _, exists := s.taskTools[name]; exists {
-			s.toolsMu.Unlock()
-			panic(fmt.Sprintf("tool name '%s' already registered as task tool", name))
-		}
-		s.applyStrictInputSchemaDefault(&entry.Tool)
-		s.tools[name] = entry
-	}
+	names, replaced, err := s.registerTools(tools)
 	s.toolsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.inputValidator.invalidate(replaced...)
+	s.outputValidator.invalidate(replaced...)
 
 	// When the list of available tools changes, servers that declared the listChanged capability SHOULD send a notification.
 	if s.capabilities.tools.listChanged {
 		// Send notification to all initialized sessions
 		s.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
 	}
+	return names, nil
 }
 
-// AddTaskTools registers multiple task tools at once
+// registerTools adds tools to s.tools according to the collision policy and
+// returns the names they were registered under and the names of the tools
+// they replaced. If a tool is rejected, it undoes the registration of the
+// others. The caller must hold toolsMu.
+func (s *MCPServer) registerTools(tools []ServerTool) ([]string, []string, error) {
+	names := make([]string, 0, len(tools))
+	replaced := make([]string, 0, len(tools))
+	var undo []func()
+	for _, entry := range tools {
+		name, err := s.toolName(entry.Tool)
+		if err == nil {
+			// Check for collision with task tools
+			if _, exists := s.taskTools[name]; exists {
+				err = fmt.Errorf("%w: tool name '%s' already registered as task tool", ErrToolNameCollision, name)
+			}
+		}
+		if err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+			return nil, nil, err
+		}
+		previous, exists := s.tools[name]
+		if exists {
+			replaced = append(replaced, name)
+		}
+		undo = append(undo, func() {
+			if exists {
+				s.tools[name] = previous
+			} else {
+				delete(s.tools, name)
+			}
+		})
+		entry.Tool.Name = name
+		s.applyStrictInputSchemaDefault(&entry.Tool)
+		s.tools[name] = entry
+		names = append(names, name)
+	}
+	return names, replaced, nil
+}
+
+// AddTaskTools registers multiple task tools at once. It panics if the
+// collision policy rejects one of them; use RegisterTaskTools to handle that
+// as an error.
 func (s *MCPServer) AddTaskTools(taskTools ...ServerTaskTool) {
+	if _, err := s.RegisterTaskTools(taskTools...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterTaskTools is RegisterTools for task tools.
+func (s *MCPServer) RegisterTaskTools(taskTools ...ServerTaskTool) ([]string, error) {
 	s.implicitlyRegisterToolCapabilities()
 
 	s.toolsMu.Lock()
+	names := make([]string, 0, len(taskTools))
+	replaced := make([]string, 0, len(taskTools))
+	var undo []func()
 	for _, entry := range taskTools {
-		name := entry.Tool.Name
-		// Check for collision with regular tools
-		if _, exists := s.tools[name]; exists {
+		name, err := s.toolName(entry.Tool)
+		if err == nil {
+			// Check for collision with regular tools
+			if _, exists := s.tools[name]; exists {
+				err = fmt.Errorf("%w: task tool name '%s' already registered as regular tool", ErrToolNameCollision, name)
+			}
+		}
+		if err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
 			s.toolsMu.Unlock()
-			panic(fmt.Sprintf("task tool name '%s' already registered as regular tool", name))
+			return nil, err
 		}
+		previous, exists := s.taskTools[name]
+		if exists {
+			replaced = append(replaced, name)
+		}
+		undo = append(undo, func() {
+			if exists {
+				s.taskTools[name] = previous
+			} else {
+				delete(s.taskTools, name)
+			}
+		})
+		entry.Tool.Name = name
 		s.applyStrictInputSchemaDefault(&entry.Tool)
 		s.taskTools[name] = entry
+		names = append(names, name)
 	}
 	s.toolsMu.Unlock()
+	s.inputValidator.invalidate(replaced...)
+	s.outputValidator.invalidate(replaced...)
 
 	// When the list of available tools changes, servers that declared the listChanged capability SHOULD send a notification.
 	if s.capabilities.tools.listChanged {
 		// Send notification to all initialized sessions
 		s.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
 	}
+	return names, nil
 }
 
-// SetTools replaces all existing tools with the provided list
+// SetTools replaces all existing tools with the provided list. The tools are
+// registered as by AddTools, under the collision policy, against the task
+// tools and each other; if one is rejected, SetTools panics with an error
+// wrapping ErrToolNameCollision and leaves the existing tools in place.
 func (s *MCPServer) SetTools(tools ...ServerTool) {
 	s.implicitlyRegisterToolCapabilities()
 
 	s.toolsMu.Lock()
-	newTools := make(map[string]ServerTool, len(tools))
-	for _, entry := range tools {
-		name := entry.Tool.Name
-		// Check for collision with task tools
-		if _, exists := s.taskTools[name]; exists {
-			s.toolsMu.Unlock()
-			panic(fmt.Sprintf("tool name '%s' already registered as task tool", name))
+	previous := s.tools
+	s.tools = make(map[string]ServerTool, len(tools))
+	if _, _, err := s.registerTools(tools); err != nil {
+		s.tools = previous
+		s.toolsMu.Unlock()
+		panic(err)
+	}
+	for name := range s.disabledTools {
+		if _, ok := s.tools[name]; !ok {
+			if _, ok := s.taskTools[name]; !ok {
+				delete(s.disabledTools, name)
+			}
 		}
-		s.applyStrictInputSchemaDefault(&entry.Tool)
-		newTools[name] = entry
 	}
-	s.tools = newTools
 	s.toolsMu.Unlock()
 	s.inputValidator.invalidateAll()
 	s.outputValidator.invalidateAll()
@@ -1053,6 +1372,7 @@ func (s *MCPServer) DeleteTools(names ...string) {
 	for _, name := range names {
 		if _, ok := s.tools[name]; ok {
 			delete(s.tools, name)
+			delete(s.disabledTools, name)
 			exists = true
 		}
 	}
@@ -1145,6 +1465,14 @@ func (s *MCPServer) handleInitialize(
 			tasksCapability.Cancel = &struct{}{}
 		}
 
+		if s.taskSubscriptionsEnabled {
+			tasksCapability.Subscribe = &struct{}{}
+		}
+
+		if s.taskOwner != nil {
+			tasksCapability.Claim = &struct{}{}
+		}
+
 		if s.capabilities.tasks.toolCallTasks {
 			tasksCapability.Requests = &mcp.TaskRequestsCapability{
 				Tools: &struct {
@@ -1177,18 +1505,24 @@ func (s *MCPServer) handleInitialize(
 			Icons:       s.implementation.Icons,
 		},
 		Capabilities: capabilities,
-		Instructions: s.instructions,
+		Instructions: s.Instructions(),
 	}
 
 	if session := ClientSessionFromContext(ctx); session != nil {
 		session.Initialize()
+		setConnectionState(session, mcp.ConnectionReady, nil)
 
 		// Store client info if the session supports it
 		if sessionWithClientInfo, ok := session.(SessionWithClientInfo); ok {
 			sessionWithClientInfo.SetClientInfo(request.Params.ClientInfo)
 			sessionWithClientInfo.SetClientCapabilities(request.Params.Capabilities)
 		}
+
+		s.negotiateEncoding(session, request, &result.Capabilities)
+		s.applySessionLabels(ctx, session, request)
 	}
+	s.negotiateExtensions(ClientSessionFromContext(ctx), request, &result)
+	s.addLatencyHints(&result)
 
 	return &result, nil
 }
@@ -1220,7 +1554,8 @@ func (s *MCPServer) handlePing(
 // handleSubscribe processes a resources/subscribe request. Servers that opt in
 // to the resources.subscribe capability via WithResourceCapabilities must
 // accept this request; otherwise it is rejected as unsupported. The default
-// implementation only validates input and acknowledges the request. Users that
+// implementation validates input, records the subscription for
+// NotifyResourceUpdated and acknowledges the request. Users that
 // need to react to subscriptions (for example to track which sessions should
 // receive notifications/resources/updated) should register Hooks.AddBeforeSubscribe
 // or Hooks.AddAfterSubscribe, or implement an optional SessionWithResourceSubscriptions
@@ -1250,6 +1585,8 @@ func (s *MCPServer) handleSubscribe(
 			subs.SubscribeToResource(request.Params.URI)
 		}
 	}
+	s.trackStatsSubscription(ctx, request.Params.URI, true)
+	s.trackResourceSubscription(ctx, request.Params.URI, true)
 
 	return &mcp.EmptyResult{}, nil
 }
@@ -1284,6 +1621,8 @@ func (s *MCPServer) handleUnsubscribe(
 			subs.UnsubscribeFromResource(request.Params.URI)
 		}
 	}
+	s.trackStatsSubscription(ctx, request.Params.URI, false)
+	s.trackResourceSubscription(ctx, request.Params.URI, false)
 
 	return &mcp.EmptyResult{}, nil
 }
@@ -1529,6 +1868,9 @@ func (s *MCPServer) handleReadResource(
 		}
 		s.resourceMiddlewareMu.RUnlock()
 
+		// Streamed resources report where their next chunk starts
+		var nextCursor mcp.Cursor
+		ctx = context.WithValue(ctx, resourceCursorKey{}, &nextCursor)
 		contents, err := finalHandler(ctx, request)
 		if err != nil {
 			return nil, &requestError{
@@ -1537,7 +1879,7 @@ func (s *MCPServer) handleReadResource(
 				err:  err,
 			}
 		}
-		return &mcp.ReadResourceResult{Contents: contents}, nil
+		return &mcp.ReadResourceResult{Contents: contents, NextCursor: nextCursor}, nil
 	}
 
 	// If no direct handler found, try matching against templates
@@ -1784,9 +2126,9 @@ func (s *MCPServer) filteredTools(ctx context.Context) []mcp.Tool {
 	// Add tools in sorted order
 	for _, name := range toolNames {
 		if tool, ok := s.tools[name]; ok {
-			tools = append(tools, tool.Tool)
+			tools = append(tools, s.withToolAvailability(tool.Tool))
 		} else if taskTool, ok := s.taskTools[name]; ok {
-			tools = append(tools, taskTool.Tool)
+			tools = append(tools, s.withToolAvailability(taskTool.Tool))
 		}
 	}
 	s.toolsMu.RUnlock()
@@ -1914,6 +2256,7 @@ func (s *MCPServer) handleToolCall(
 	}
 
 	// If not found in session tools, check global tools
+	sessionTool := ok
 	if !ok {
 		s.toolsMu.RLock()
 		tool, ok = s.tools[request.Params.Name]
@@ -1952,6 +2295,12 @@ func (s *MCPServer) handleToolCall(
 		}
 	}
 
+	if !sessionTool {
+		if err := s.toolDisabledError(id, request.Params.Name); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate task support requirements
 	if tool.Tool.Execution != nil && tool.Tool.Execution.TaskSupport == mcp.TaskSupportRequired {
 		if request.Params.Task == nil {
@@ -1986,32 +2335,45 @@ func (s *MCPServer) handleToolCall(
 	// Validate the incoming arguments against the tool's input schema, when
 	// schema validation has been enabled via WithInputSchemaValidation. A
 	// validation failure is returned as a SEP-1303 tool execution error so the
-	// model receives feedback in its context window and can self-correct.
+	// model receives feedback in its context window and can self-correct,
+	// unless WithSchemaValidation asked for an INVALID_PARAMS error instead.
 	if s.inputValidator != nil {
 		if _, err := s.inputValidator.validate(tool.Tool, request.Params.Arguments); err != nil {
+			if s.inputValidationAsError {
+				return nil, validationRequestError(id, err)
+			}
 			return validationToolResult(err), nil
 		}
 	}
 
-	finalHandler := tool.Handler
-
-	s.toolMiddlewareMu.RLock()
-	mw := s.toolHandlerMiddlewares
-
-	// Apply middlewares in reverse order
-	for i := len(mw) - 1; i >= 0; i-- {
-		finalHandler = mw[i](finalHandler)
-	}
-	s.toolMiddlewareMu.RUnlock()
-
-	result, err := finalHandler(ctx, request)
+	ctx = s.withToolResultWriter(ctx, request)
+	ctx, sampling := withSamplingCall(ctx, request.Params.Name)
+	result, err := s.wrapToolHandler(tool.Handler)(ctx, request)
 	if err != nil {
+		if quotaErr, ok := samplingQuotaData(err); ok {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.SAMPLING_QUOTA_EXCEEDED,
+				err:  err,
+				data: quotaErr,
+			}
+		}
+		var limitErr *RateLimitError
+		if errors.As(err, &limitErr) {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.RATE_LIMITED,
+				err:  err,
+				data: limitErr,
+			}
+		}
 		return nil, &requestError{
 			id:   id,
 			code: mcp.INTERNAL_ERROR,
 			err:  err,
 		}
 	}
+	sampling.annotate(result)
 
 	// Validate the tool's StructuredContent against its declared output
 	// schema when output schema validation has been enabled via
@@ -2076,8 +2438,8 @@ func (s *MCPServer) handleTaskAugmentedToolCall(
 	// would need to surface validation failures through tasks/result rather
 	// than the create-task response; that's deferred to a follow-up.
 
-	// Generate task ID (UUID v4)
-	taskID := uuid.New().String()
+	// Generate task ID (UUID v4 unless WithIDSource is set)
+	taskID := s.newID()
 
 	// Extract TTL from task params
 	var ttl *int64
@@ -2095,13 +2457,18 @@ func (s *MCPServer) handleTaskAugmentedToolCall(
 		}
 	}
 
-	// Execute tool asynchronously
+	if deadline, ok := s.taskDeadline(request.Params.Name, entry.createdAt, request.Params.Task); ok {
+		s.enforceTaskDeadline(entry, deadline)
+	}
+
+	// Execute tool asynchronously, once a worker is free with WithTaskWorkers
 	// For regular tools being used as tasks, we need different execution logic
+	priority := mcp.TaskPriority(request.Params.Task)
 	if hasTaskHandler {
-		go s.executeTaskTool(ctx, entry, toolToUse, request)
+		s.startTask(entry, priority, func() { s.executeTaskTool(ctx, entry, toolToUse, request) })
 	} else {
 		// Execute regular tool wrapped as a task
-		go s.executeRegularToolAsTask(ctx, entry, regularTool, request)
+		s.startTask(entry, priority, func() { s.executeRegularToolAsTask(ctx, entry, regularTool, request) })
 	}
 
 	// Return CreateTaskResult immediately with task as top-level field
@@ -2131,7 +2498,7 @@ func (s *MCPServer) executeTaskTool(
 	}()
 
 	// Create cancellable context for this task execution
-	taskCtx, cancel := context.WithCancel(ctx)
+	taskCtx, cancel := context.WithCancel(s.withTaskProgress(ctx, entry))
 	defer cancel()
 
 	// Store cancel func in entry so it can be cancelled via tasks/cancel
@@ -2140,6 +2507,7 @@ func (s *MCPServer) executeTaskTool(
 	s.tasksMu.Unlock()
 
 	// Execute the task tool handler
+	taskCtx, _ = withSamplingCall(taskCtx, request.Params.Name)
 	result, err := taskTool.Handler(taskCtx, request)
 
 	if err != nil {
@@ -2155,7 +2523,7 @@ func (s *MCPServer) executeTaskTool(
 			if !alreadyCancelled {
 				// Handler detected cancellation before tasks/cancel was called
 				// Mark as cancelled with the context error message
-				cancelledAt := time.Now()
+				cancelledAt := s.now()
 				duration := cancelledAt.Sub(entry.createdAt)
 
 				s.tasksMu.Lock()
@@ -2169,7 +2537,7 @@ func (s *MCPServer) executeTaskTool(
 					// Decrement active tasks counter
 					s.activeTasks--
 
-					s.sendTaskStatusNotification(entry.task)
+					s.sendTaskStatusNotification(entry.sessionID, entry.task)
 
 					// Fire task cancellation hook
 					if s.taskHooks != nil {
@@ -2187,6 +2555,7 @@ func (s *MCPServer) executeTaskTool(
 					}
 				}
 				s.tasksMu.Unlock()
+				s.persistTask(entry)
 			}
 			return
 		}
@@ -2199,14 +2568,13 @@ func (s *MCPServer) executeTaskTool(
 	// Task succeeded - store the CreateTaskResult
 	// Note: The actual result will be retrieved later via tasks/result
 	//
-	// Validate the StructuredContent against the tool's declared output
-	// schema when WithOutputSchemaValidation is enabled. If validation fails,
-	// persist a tool execution error in place of the bad result so the
-	// client cannot retrieve a result that violates the schema via
-	// tasks/result. handleTaskResult accepts both *CallToolResult and
-	// *CreateTaskResult, so storing a *CallToolResult here is safe.
-	if s.outputValidator != nil {
-		if _, vErr := s.outputValidator.validateCreateTaskResult(taskTool.Tool, result); vErr != nil {
+	// Enforce the tool's declared output schema on the StructuredContent.
+	// If validation fails, persist a tool execution error in place of the
+	// bad result so the client cannot retrieve a result that violates the
+	// schema via tasks/result. handleTaskResult accepts both *CallToolResult
+	// and *CreateTaskResult, so storing a *CallToolResult here is safe.
+	if result != nil {
+		if vErr := s.validateTaskResult(taskTool.Tool, result.StructuredContent, result.IsError); vErr != nil {
 			s.completeTask(entry, validationToolResult(vErr), nil)
 			return
 		}
@@ -2223,7 +2591,7 @@ func (s *MCPServer) executeRegularToolAsTask(
 	request mcp.CallToolRequest,
 ) {
 	// Create cancellable context for this task execution
-	taskCtx, cancel := context.WithCancel(ctx)
+	taskCtx, cancel := context.WithCancel(s.withTaskProgress(ctx, entry))
 	defer cancel()
 
 	// Store cancel func in entry so it can be cancelled via tasks/cancel
@@ -2232,16 +2600,8 @@ func (s *MCPServer) executeRegularToolAsTask(
 	s.tasksMu.Unlock()
 
 	// Execute the regular tool handler with middleware applied
-	finalHandler := regularTool.Handler
-
-	s.toolMiddlewareMu.RLock()
-	mw := s.toolHandlerMiddlewares
-	for i := len(mw) - 1; i >= 0; i-- {
-		finalHandler = mw[i](finalHandler)
-	}
-	s.toolMiddlewareMu.RUnlock()
-
-	result, err := finalHandler(taskCtx, request)
+	taskCtx, _ = withSamplingCall(taskCtx, request.Params.Name)
+	result, err := s.wrapToolHandler(regularTool.Handler)(taskCtx, request)
 
 	if err != nil {
 		// If the error is due to context cancellation, don't mark as failed.
@@ -2256,7 +2616,7 @@ func (s *MCPServer) executeRegularToolAsTask(
 			if !alreadyCancelled {
 				// Handler detected cancellation before tasks/cancel was called
 				// Mark as cancelled with the context error message
-				cancelledAt := time.Now()
+				cancelledAt := s.now()
 				duration := cancelledAt.Sub(entry.createdAt)
 
 				s.tasksMu.Lock()
@@ -2270,7 +2630,7 @@ func (s *MCPServer) executeRegularToolAsTask(
 					// Decrement active tasks counter
 					s.activeTasks--
 
-					s.sendTaskStatusNotification(entry.task)
+					s.sendTaskStatusNotification(entry.sessionID, entry.task)
 
 					// Fire task cancellation hook
 					if s.taskHooks != nil {
@@ -2288,6 +2648,7 @@ func (s *MCPServer) executeRegularToolAsTask(
 					}
 				}
 				s.tasksMu.Unlock()
+				s.persistTask(entry)
 			}
 			return
 		}
@@ -2300,13 +2661,12 @@ func (s *MCPServer) executeRegularToolAsTask(
 	// Task succeeded - store the CallToolResult directly
 	// When retrieved via tasks/result, this will be returned to the client
 	//
-	// Mirror the synchronous path: validate the result's StructuredContent
-	// against the tool's declared output schema when
-	// WithOutputSchemaValidation is enabled. A validation failure replaces
-	// the result with a tool execution error so the bad payload never
-	// reaches the client via tasks/result.
-	if s.outputValidator != nil {
-		if _, vErr := s.outputValidator.validate(regularTool.Tool, result); vErr != nil {
+	// Enforce the tool's declared output schema on the result's
+	// StructuredContent. A validation failure replaces the result with a
+	// tool execution error so the bad payload never reaches the client via
+	// tasks/result.
+	if result != nil {
+		if vErr := s.validateTaskResult(regularTool.Tool, result.StructuredContent, result.IsError); vErr != nil {
 			s.completeTask(entry, validationToolResult(vErr), nil)
 			return
 		}
@@ -2330,6 +2690,14 @@ func (s *MCPServer) handleNotification(
 		}
 		return nil
 	}
+	if notification.Method == string(mcp.MethodNotificationSamplingDelta) {
+		s.handleSamplingDelta(ctx, notification)
+		return nil
+	}
+	if notification.Method == mcp.MethodNotificationRootsListChanged {
+		s.invalidateRoots(ctx)
+		s.refreshRoots(ctx)
+	}
 
 	s.notificationHandlersMu.RLock()
 	handler, ok := s.notificationHandlers[notification.Method]
@@ -2350,6 +2718,15 @@ func inflightKey(ctx context.Context, requestID any) string {
 	return fmt.Sprintf(":%v", requestID)
 }
 
+// resultOrNil returns result as an untyped nil when the request failed or
+// returned nothing, so hooks can compare it with nil.
+func resultOrNil[T any](result *T, err error) any {
+	if err != nil || result == nil {
+		return nil
+	}
+	return result
+}
+
 func createResponse(id any, result any) mcp.JSONRPCMessage {
 	return mcp.NewJSONRPCResultResponse(mcp.NewRequestId(id), result)
 }
@@ -2395,7 +2772,22 @@ func (s *MCPServer) handleListTasks(
 	id any,
 	request mcp.ListTasksRequest,
 ) (*mcp.ListTasksResult, *requestError) {
-	tasks := s.listTasks(ctx)
+	filter, err := newTaskFilter(request.Filter)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  err,
+		}
+	}
+	tasks, err := s.listTasks(ctx, filter)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  err,
+		}
+	}
 
 	// Sort tasks by TaskId for consistent pagination
 	sort.Slice(tasks, func(i, j int) bool {
@@ -2432,7 +2824,7 @@ func (s *MCPServer) handleTaskResult(
 	id any,
 	request mcp.TaskResultRequest,
 ) (*mcp.TaskResultResult, *requestError) {
-	task, done, err := s.getTask(ctx, request.Params.TaskId)
+	entry, err := s.getTaskEntry(ctx, request.Params.TaskId)
 	if err != nil {
 		return nil, &requestError{
 			id:   id,
@@ -2441,27 +2833,37 @@ func (s *MCPServer) handleTaskResult(
 		}
 	}
 
-	// Wait for task completion if not terminal
+	s.tasksMu.RLock()
+	task, done := entry.task, entry.done
+	s.tasksMu.RUnlock()
+
+	// Wait for task completion if not terminal. Tasks running in another
+	// process are followed through the task store.
 	if !task.Status.IsTerminal() {
-		select {
-		case <-done:
-			// Task completed
-		case <-ctx.Done():
+		if entry.detached {
+			entry, err = s.awaitStoredTask(ctx, request.Params.TaskId, task.PollInterval)
+		} else {
+			select {
+			case <-done:
+				// Task completed; re-fetch the entry to get the final result
+				entry, err = s.getTaskEntry(ctx, request.Params.TaskId)
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		if ctx.Err() != nil {
 			return nil, &requestError{
 				id:   id,
 				code: mcp.REQUEST_INTERRUPTED,
 				err:  ctx.Err(),
 			}
 		}
-	}
-
-	// Re-fetch the task entry to get the final result/error under lock
-	entry, err := s.getTaskEntry(ctx, request.Params.TaskId)
-	if err != nil {
-		return nil, &requestError{
-			id:   id,
-			code: mcp.INVALID_PARAMS,
-			err:  err,
+		if err != nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INVALID_PARAMS,
+				err:  err,
+			}
 		}
 	}
 
@@ -2470,9 +2872,29 @@ func (s *MCPServer) handleTaskResult(
 	storedResult := entry.result
 	resultErr := entry.resultErr
 	taskID := entry.task.TaskId
+	deadline := entry.deadline
 	s.tasksMu.RUnlock()
 
 	// Return error if task failed
+	if errors.Is(resultErr, ErrTaskDeadlineExceeded) {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.REQUEST_INTERRUPTED,
+			err:  resultErr,
+			data: TaskDeadlineError{
+				Reason:   "deadline_exceeded",
+				Deadline: deadline.UTC().Format(time.RFC3339Nano),
+			},
+		}
+	}
+	if quotaErr, ok := samplingQuotaData(resultErr); ok {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.SAMPLING_QUOTA_EXCEEDED,
+			err:  resultErr,
+			data: quotaErr,
+		}
+	}
 	if resultErr != nil {
 		return nil, &requestError{
 			id:   id,
@@ -2565,7 +2987,7 @@ func (s *MCPServer) handleComplete(
 			request.Params.Context,
 		)
 	case mcp.ResourceReference:
-		completion, err = s.resourceCompletionProvider.CompleteResourceArgument(
+		completion, err = s.completeResourceArgument(
 			ctx,
 			ref.URI,
 			request.Params.Argument,
@@ -2613,11 +3035,14 @@ func (s *MCPServer) createTask(ctx context.Context, taskID string, toolName stri
 		opts = append(opts, mcp.WithTaskPollInterval(*pollInterval))
 	}
 	task := mcp.NewTask(taskID, opts...)
-	createdAt := time.Now()
+	createdAt := s.now()
+	task.CreatedAt = createdAt.UTC().Format(time.RFC3339)
+	task.LastUpdatedAt = task.CreatedAt
 
 	entry := &taskEntry{
 		task:      task,
 		sessionID: getSessionID(ctx),
+		owner:     s.taskOwnerOf(ctx),
 		toolName:  toolName,
 		createdAt: createdAt,
 		done:      make(chan struct{}),
@@ -2625,11 +3050,11 @@ func (s *MCPServer) createTask(ctx context.Context, taskID string, toolName stri
 
 	// Single critical section for check + increment + insert
 	s.tasksMu.Lock()
-	defer s.tasksMu.Unlock()
 
 	// Check concurrent task limit
 	if s.maxConcurrentTasks != nil && *s.maxConcurrentTasks > 0 {
 		if s.activeTasks >= *s.maxConcurrentTasks {
+			s.tasksMu.Unlock()
 			return nil, fmt.Errorf("max concurrent tasks limit reached (%d)", *s.maxConcurrentTasks)
 		}
 	}
@@ -2637,6 +3062,17 @@ func (s *MCPServer) createTask(ctx context.Context, taskID string, toolName stri
 	// Increment active task counter and insert task atomically
 	s.activeTasks++
 	s.tasks[taskID] = entry
+	record := entry.record()
+	s.tasksMu.Unlock()
+
+	// Persist the task before it starts so status updates always find it
+	if err := s.taskStore.Create(ctx, record); err != nil {
+		s.tasksMu.Lock()
+		s.activeTasks--
+		delete(s.tasks, taskID)
+		s.tasksMu.Unlock()
+		return nil, fmt.Errorf("failed to store task: %w", err)
+	}
 
 	// Fire task created hook
 	if s.taskHooks != nil {
@@ -2661,34 +3097,19 @@ func (s *MCPServer) createTask(ctx context.Context, taskID string, toolName stri
 // getTask retrieves a task by ID, checking session isolation if applicable.
 // Returns a copy of the task and the done channel for waiting on completion.
 func (s *MCPServer) getTask(ctx context.Context, taskID string) (mcp.Task, chan struct{}, error) {
-	s.tasksMu.RLock()
-	entry, exists := s.tasks[taskID]
-	if !exists {
-		// Check if this task was recently expired
-		if _, wasExpired := s.expiredTasks[taskID]; wasExpired {
-			s.tasksMu.RUnlock()
-			return mcp.Task{}, nil, fmt.Errorf("task has expired")
-		}
-		s.tasksMu.RUnlock()
-		return mcp.Task{}, nil, fmt.Errorf("task not found")
-	}
-
-	// Verify session isolation
-	sessionID := getSessionID(ctx)
-	if entry.sessionID != "" && sessionID != "" && entry.sessionID != sessionID {
-		s.tasksMu.RUnlock()
-		return mcp.Task{}, nil, fmt.Errorf("task not found")
+	entry, err := s.getTaskEntry(ctx, taskID)
+	if err != nil {
+		return mcp.Task{}, nil, err
 	}
 
 	// Return a copy of the task and the done channel
-	taskCopy := entry.task
-	done := entry.done
-	s.tasksMu.RUnlock()
-
-	return taskCopy, done, nil
+	s.tasksMu.RLock()
+	defer s.tasksMu.RUnlock()
+	return entry.task, entry.done, nil
 }
 
 // getTaskEntry retrieves the raw task entry for internal use (requires caller to handle synchronization).
+// Tasks this server is not running are loaded, detached, from the task store.
 func (s *MCPServer) getTaskEntry(ctx context.Context, taskID string) (*taskEntry, error) {
 	s.tasksMu.RLock()
 	entry, exists := s.tasks[taskID]
@@ -2696,42 +3117,88 @@ func (s *MCPServer) getTaskEntry(ctx context.Context, taskID string) (*taskEntry
 		// Check if this task was recently expired
 		if _, wasExpired := s.expiredTasks[taskID]; wasExpired {
 			s.tasksMu.RUnlock()
-			return nil, fmt.Errorf("task has expired")
+			return nil, ErrTaskExpired
+		}
+		s.tasksMu.RUnlock()
+
+		var err error
+		if entry, err = s.loadStoredTask(ctx, taskID); err != nil {
+			if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrTaskExpired) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to load task: %w", err)
 		}
+	} else {
 		s.tasksMu.RUnlock()
-		return nil, fmt.Errorf("task not found")
 	}
-	s.tasksMu.RUnlock()
 
 	// Verify session isolation
-	sessionID := getSessionID(ctx)
-	if entry.sessionID != "" && sessionID != "" && entry.sessionID != sessionID {
-		return nil, fmt.Errorf("task not found")
+	if !s.taskVisibleTo(entry.sessionID, getSessionID(ctx)) {
+		return nil, ErrTaskNotFound
 	}
 
 	return entry, nil
 }
 
-// listTasks returns copies of all tasks for the current session.
-func (s *MCPServer) listTasks(ctx context.Context) []mcp.Task {
+// listTasks returns copies of the tasks for the current session matching
+// filter, as recorded in the task store.
+func (s *MCPServer) listTasks(ctx context.Context, filter taskFilter) ([]mcp.Task, error) {
 	sessionID := getSessionID(ctx)
 
-	s.tasksMu.RLock()
-	defer s.tasksMu.RUnlock()
+	records, err := s.taskStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
 
+	now := s.now()
 	var tasks []mcp.Task
-	for _, entry := range s.tasks {
+	for _, record := range records {
+		if record.expired(now) {
+			continue
+		}
 		// Filter by session if applicable
-		if sessionID == "" || entry.sessionID == "" || entry.sessionID == sessionID {
-			tasks = append(tasks, entry.task)
+		if s.taskVisibleTo(record.SessionID, sessionID) && filter.matches(record) {
+			tasks = append(tasks, record.Task)
+		}
+	}
+
+	return tasks, nil
+}
+
+// taskFilter holds the parsed tasks/list filters.
+type taskFilter struct {
+	statuses     []mcp.TaskStatus
+	toolName     string
+	createdAfter time.Time
+}
+
+// newTaskFilter parses the filters of a tasks/list request.
+func newTaskFilter(requested mcp.TaskFilter) (taskFilter, error) {
+	filter := taskFilter{statuses: requested.Status, toolName: requested.ToolName}
+	if requested.CreatedAfter != "" {
+		createdAfter, err := time.Parse(time.RFC3339, requested.CreatedAfter)
+		if err != nil {
+			return taskFilter{}, fmt.Errorf("invalid createdAfter: %w", err)
 		}
+		filter.createdAfter = createdAfter
 	}
+	return filter, nil
+}
 
-	return tasks
+// matches reports whether record passes every filter.
+func (f taskFilter) matches(record TaskRecord) bool {
+	if len(f.statuses) > 0 && !slices.Contains(f.statuses, record.Task.Status) {
+		return false
+	}
+	if f.toolName != "" && record.ToolName != f.toolName {
+		return false
+	}
+	return f.createdAfter.IsZero() || record.CreatedAt.After(f.createdAfter)
 }
 
 // completeTask marks a task as completed with the given result.
 func (s *MCPServer) completeTask(entry *taskEntry, result any, err error) {
+	defer s.persistTask(entry)
 	s.tasksMu.Lock()
 	defer s.tasksMu.Unlock()
 
@@ -2740,7 +3207,7 @@ func (s *MCPServer) completeTask(entry *taskEntry, result any, err error) {
 		return
 	}
 
-	completedAt := time.Now()
+	completedAt := s.now()
 	duration := completedAt.Sub(entry.createdAt)
 
 	if err != nil {
@@ -2758,12 +3225,15 @@ func (s *MCPServer) completeTask(entry *taskEntry, result any, err error) {
 	// Mark as completed and signal
 	entry.completed = true
 	close(entry.done)
+	if entry.deadlineTimer != nil {
+		entry.deadlineTimer.Stop()
+	}
 
 	// Decrement active tasks counter
 	s.activeTasks--
 
 	// Send task status notification
-	s.sendTaskStatusNotification(entry.task)
+	s.sendTaskStatusNotification(entry.sessionID, entry.task)
 
 	// Fire task hooks
 	if s.taskHooks != nil {
@@ -2794,6 +3264,7 @@ func (s *MCPServer) cancelTask(ctx context.Context, taskID string) error {
 		return err
 	}
 
+	defer s.persistTask(entry)
 	s.tasksMu.Lock()
 	defer s.tasksMu.Unlock()
 
@@ -2806,8 +3277,11 @@ func (s *MCPServer) cancelTask(ctx context.Context, taskID string) error {
 	if entry.cancelFunc != nil {
 		entry.cancelFunc()
 	}
+	if entry.deadlineTimer != nil {
+		entry.deadlineTimer.Stop()
+	}
 
-	cancelledAt := time.Now()
+	cancelledAt := s.now()
 	duration := cancelledAt.Sub(entry.createdAt)
 
 	entry.task.Status = mcp.TaskStatusCancelled
@@ -2819,11 +3293,13 @@ func (s *MCPServer) cancelTask(ctx context.Context, taskID string) error {
 	entry.completed = true
 	close(entry.done)
 
-	// Decrement active tasks counter
-	s.activeTasks--
+	// Decrement active tasks counter; detached tasks run elsewhere
+	if !entry.detached {
+		s.activeTasks--
+	}
 
 	// Send task status notification
-	s.sendTaskStatusNotification(entry.task)
+	s.sendTaskStatusNotification(entry.sessionID, entry.task)
 
 	// Fire task cancellation hook
 	if s.taskHooks != nil {
@@ -2850,8 +3326,9 @@ func (s *MCPServer) scheduleTaskCleanup(taskID string, ttlMs int64) {
 
 	s.tasksMu.Lock()
 	delete(s.tasks, taskID)
-	s.expiredTasks[taskID] = time.Now()
+	s.expiredTasks[taskID] = s.now()
 	s.tasksMu.Unlock()
+	s.deleteStoredTask(taskID)
 
 	// Remove tombstone after 5 minutes.
 	time.AfterFunc(5*time.Minute, func() {
@@ -2862,7 +3339,7 @@ func (s *MCPServer) scheduleTaskCleanup(taskID string, ttlMs int64) {
 }
 
 // sendTaskStatusNotification sends a notification when a task's status changes.
-func (s *MCPServer) sendTaskStatusNotification(task mcp.Task) {
+func (s *MCPServer) sendTaskStatusNotification(ownerSessionID string, task mcp.Task) {
 	// Convert task to map[string]any for notification params
 	taskMap := map[string]any{
 		"taskId":        task.TaskId,
@@ -2881,7 +3358,38 @@ func (s *MCPServer) sendTaskStatusNotification(task mcp.Task) {
 		taskMap["pollInterval"] = *task.PollInterval
 	}
 
-	s.SendNotificationToAllClients(mcp.MethodNotificationTasksStatus, taskMap)
+	// Scoped tasks are private to their owner: don't leak their IDs and
+	// status to other sessions.
+	if s.sessionScopedTasks {
+		if ownerSessionID != "" && s.wantsTaskStatus(ownerSessionID, ownerSessionID, task.TaskId) {
+			_ = s.SendNotificationToSpecificClient(ownerSessionID, mcp.MethodNotificationTasksStatus, taskMap)
+		}
+		return
+	}
+	if !s.taskSubscriptionsEnabled {
+		s.SendNotificationToAllClients(mcp.MethodNotificationTasksStatus, taskMap)
+		return
+	}
+	s.routeTaskStatusNotification(ownerSessionID, task.TaskId, mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: mcp.MethodNotificationTasksStatus,
+			Params: mcp.NotificationParams{
+				AdditionalFields: taskMap,
+			},
+		},
+	})
+}
+
+// taskVisibleTo reports whether a task owned by ownerSessionID may be seen
+// by a request from sessionID. By default, tasks without an owner and
+// requests without a session bypass the check; with WithSessionScopedTasks
+// the session IDs must match exactly.
+func (s *MCPServer) taskVisibleTo(ownerSessionID, sessionID string) bool {
+	if s.sessionScopedTasks {
+		return ownerSessionID == sessionID
+	}
+	return ownerSessionID == "" || sessionID == "" || ownerSessionID == sessionID
 }
 
 // getSessionID extracts the session ID from the context.
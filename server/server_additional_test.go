@@ -219,6 +219,53 @@ func TestMCPServer_ConcurrentOperations(t *testing.T) {
 	})
 }
 
+func TestMCPServer_WithPageSize(t *testing.T) {
+	listTools := func(server *MCPServer, cursor mcp.Cursor) mcp.ListToolsResult {
+		response := server.HandleMessage(t.Context(), fmt.Appendf(nil,
+			`{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"cursor":%q}}`, cursor))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result, ok := resp.Result.(mcp.ListToolsResult)
+		require.True(t, ok)
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		pageSize int
+		pages    []int
+	}{
+		{name: "paginated", pageSize: 3, pages: []int{3, 3, 1}},
+		{name: "zero disables pagination", pageSize: 0, pages: []int{7}},
+		{name: "negative disables pagination", pageSize: -1, pages: []int{7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", WithPageSize(tt.pageSize))
+			for i := range 7 {
+				server.AddTool(mcp.NewTool(fmt.Sprintf("tool-%d", i)), nil)
+			}
+
+			var pages []int
+			var names []string
+			var cursor mcp.Cursor
+			for {
+				result := listTools(server, cursor)
+				pages = append(pages, len(result.Tools))
+				for _, tool := range result.Tools {
+					names = append(names, tool.Name)
+				}
+				if result.NextCursor == "" {
+					break
+				}
+				cursor = result.NextCursor
+			}
+			assert.Equal(t, tt.pages, pages)
+			assert.Len(t, names, 7)
+		})
+	}
+}
+
 // TestMCPServer_PaginationEdgeCases tests pagination boundary conditions
 func TestMCPServer_PaginationEdgeCases(t *testing.T) {
 	t.Run("malformed cursor - invalid base64", func(t *testing.T) {
@@ -1853,6 +1853,21 @@ func TestMCPServer_Instructions(t *testing.T) {
 	}
 }
 
+func TestMCPServer_SetInstructions(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithInstructions("initial"))
+	assert.Equal(t, "initial", server.Instructions())
+
+	server.SetInstructions("updated")
+	assert.Equal(t, "updated", server.Instructions())
+
+	response := server.HandleMessage(t.Context(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	initResult, ok := resp.Result.(mcp.InitializeResult)
+	require.True(t, ok)
+	assert.Equal(t, "updated", initResult.Instructions)
+}
+
 func TestMCPServer_ResourceTemplates(t *testing.T) {
 	server := NewMCPServer("test-server", "1.0.0",
 		WithResourceCapabilities(true, true),
@@ -2395,6 +2410,80 @@ func TestMCPServer_SessionHooks_NilHooks(t *testing.T) {
 	server.UnregisterSession(ctx, testSession.SessionID())
 }
 
+func TestMCPServer_OutgoingHooks(t *testing.T) {
+	type sent struct {
+		method string
+		err    error
+	}
+	var (
+		before    []string
+		after     []sent
+		requested []mcp.MCPMethod
+		answered  []any
+		failed    []error
+	)
+	hooks := &Hooks{}
+	hooks.AddBeforeSendNotification(func(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification) {
+		before = append(before, notification.Method)
+	})
+	hooks.AddAfterSendNotification(func(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification, err error) {
+		after = append(after, sent{notification.Method, err})
+	})
+	hooks.AddBeforeServerRequest(func(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any) {
+		requested = append(requested, method)
+	})
+	hooks.AddAfterServerRequest(func(ctx context.Context, session ClientSession, method mcp.MCPMethod, params any, result any, err error) {
+		answered = append(answered, result)
+		failed = append(failed, err)
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	t.Run("notifications", func(t *testing.T) {
+		session := &fakeSession{
+			sessionID:           "notified",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 1),
+			initialized:         true,
+		}
+		require.NoError(t, server.RegisterSession(t.Context(), session))
+		defer server.UnregisterSession(t.Context(), session.SessionID())
+		ctx := server.WithContext(t.Context(), session)
+
+		require.NoError(t, server.SendNotificationToClient(ctx, "notifications/first", nil))
+		assert.ErrorIs(t, server.SendNotificationToClient(ctx, "notifications/second", nil), ErrNotificationChannelBlocked)
+		<-session.notificationChannel
+		server.SendNotificationToAllClients("notifications/broadcast", nil)
+
+		assert.Equal(t, []string{"notifications/first", "notifications/second", "notifications/broadcast"}, before)
+		assert.Equal(t, []sent{
+			{"notifications/first", nil},
+			{"notifications/second", ErrNotificationChannelBlocked},
+			{"notifications/broadcast", nil},
+		}, after)
+	})
+
+	t.Run("server requests", func(t *testing.T) {
+		samplingResult := &mcp.CreateMessageResult{Model: "test"}
+		sampling := &mockSamplingSession{mockSession: mockSession{sessionID: "sampling"}, result: samplingResult}
+		_, err := server.RequestSampling(server.WithContext(t.Context(), sampling), mcp.CreateMessageRequest{})
+		require.NoError(t, err)
+
+		elicitation := &mockElicitationSession{sessionID: "elicitation", result: mcp.NewElicitationDeclineResult()}
+		_, err = server.RequestElicitation(server.WithContext(t.Context(), elicitation), mcp.ElicitationRequest{
+			Params: mcp.ElicitationParams{Message: "Name?", RequestedSchema: map[string]any{"type": "object"}},
+		})
+		require.NoError(t, err)
+
+		rootsErr := errors.New("no roots")
+		roots := &mockRootsSession{sessionID: "roots", err: rootsErr}
+		_, err = server.RequestRoots(server.WithContext(t.Context(), roots), mcp.ListRootsRequest{})
+		require.ErrorIs(t, err, rootsErr)
+
+		assert.Equal(t, []mcp.MCPMethod{mcp.MethodSamplingCreateMessage, mcp.MethodElicitationCreate, mcp.MethodListRoots}, requested)
+		assert.Equal(t, []any{samplingResult, elicitation.result, nil}, answered)
+		assert.Equal(t, []error{nil, nil, rootsErr}, failed)
+	})
+}
+
 func TestMCPServer_WithRecover(t *testing.T) {
 	panicToolHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		panic("test panic")
@@ -4923,6 +5012,41 @@ func TestMCPServer_Use_MultipleMiddlewaresExecuteInOrder(t *testing.T) {
 	assert.Equal(t, []string{"first", "second"}, order)
 }
 
+func TestWithToolMiddleware(t *testing.T) {
+	var order []string
+	label := func(name string) ToolMiddleware {
+		return func(next ToolHandlerFunc) ToolHandlerFunc {
+			return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+	redact := func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err == nil {
+				result = mcp.NewToolResultText("[redacted]")
+			}
+			return result, err
+		}
+	}
+
+	s := NewMCPServer("test", "1.0.0",
+		WithToolHandlerMiddleware(label("registered-first")),
+		WithToolMiddleware(label("a"), ChainToolMiddleware(label("b"), label("c")), redact),
+	)
+	s.AddTool(mcp.NewTool("secret"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("password"), nil
+	})
+
+	resp := s.HandleMessage(t.Context(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"secret","arguments":{}}}`)) //nolint:lll
+	result, ok := resp.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	assert.Equal(t, []string{"registered-first", "a", "b", "c"}, order)
+	assert.Equal(t, "[redacted]", result.Result.(*mcp.CallToolResult).Content[0].(mcp.TextContent).Text)
+}
+
 func TestMCPServer_Use_ConcurrentSafe(t *testing.T) {
 	s := NewMCPServer("test", "1.0.0")
 	s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
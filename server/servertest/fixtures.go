@@ -0,0 +1,264 @@
+// Package servertest provides helpers for tests and demos of MCP servers.
+package servertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Fixtures holds the resources and prompts read by LoadFixtures, ready to be
+// added to an MCPServer, or to an mcptest.Server with AddFixtures.
+type Fixtures struct {
+	Resources []server.ServerResource
+	Prompts   []server.ServerPrompt
+}
+
+// resourceFrontMatter is the front matter of a resource fixture.
+type resourceFrontMatter struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+}
+
+// promptFrontMatter is the front matter of a prompt fixture.
+type promptFrontMatter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Role        string `json:"role"`
+	Arguments   []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Required    bool   `json:"required"`
+	} `json:"arguments"`
+}
+
+// LoadFixtures reads resources and prompts from the resources and prompts
+// subdirectories of dir. Either may be missing. See LoadFixturesFS for the
+// file format.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	return LoadFixturesFS(os.DirFS(dir))
+}
+
+// LoadFixturesFS reads resources and prompts from fsys, which can be an
+// embed.FS for demos that ship their fixtures in the binary.
+//
+// Every file under resources/ becomes a resource whose contents are the file
+// body, and every file under prompts/ becomes a prompt with a single message.
+// A file may start with front matter, a JSON object between two "---"
+// lines:
+//
+//	---
+//	{"uri": "docs://readme", "name": "README", "mimeType": "text/markdown"}
+//	---
+//	# My project
+//
+// Resources default to the URI fixture:///<path below resources/>, the file
+// name and the MIME type of the extension. Bodies that are not valid UTF-8
+// are served as blobs.
+//
+// Resources accept uri, name, description and mimeType. Prompts accept name
+// (defaults to the file name without extension), description, role (user or
+// assistant, defaults to user) and arguments, a list of objects with name,
+// description and required. Unknown keys are an error. The body is a text/template
+// executed with the prompt's arguments, as in "Review {{.language}} code".
+func LoadFixturesFS(fsys fs.FS) (*Fixtures, error) {
+	fixtures := &Fixtures{}
+	err := walkFixtures(fsys, "resources", func(name string, data []byte) error {
+		resource, err := loadResourceFixture(name, data)
+		if err != nil {
+			return err
+		}
+		fixtures.Resources = append(fixtures.Resources, resource)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = walkFixtures(fsys, "prompts", func(name string, data []byte) error {
+		prompt, err := loadPromptFixture(name, data)
+		if err != nil {
+			return err
+		}
+		fixtures.Prompts = append(fixtures.Prompts, prompt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// Register adds the fixtures to s.
+func (f *Fixtures) Register(s *server.MCPServer) {
+	if len(f.Resources) > 0 {
+		s.AddResources(f.Resources...)
+	}
+	if len(f.Prompts) > 0 {
+		s.AddPrompts(f.Prompts...)
+	}
+}
+
+// walkFixtures calls fn with the path below root and the contents of every
+// regular file under root in fsys. A missing root is not an error.
+func walkFixtures(fsys fs.FS, root string, fn func(name string, data []byte) error) error {
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		if err := fn(strings.TrimPrefix(p, root+"/"), data); err != nil {
+			return fmt.Errorf("fixture %s: %w", p, err)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// splitFrontMatter separates the front matter of a fixture from its body. A
+// file without front matter is all body.
+func splitFrontMatter(data []byte) (frontMatter, body []byte) {
+	rest, ok := bytes.CutPrefix(data, []byte("---\n"))
+	if !ok {
+		if rest, ok = bytes.CutPrefix(data, []byte("---\r\n")); !ok {
+			return nil, data
+		}
+	}
+	for offset := 0; offset < len(rest); {
+		line, _, _ := bytes.Cut(rest[offset:], []byte("\n"))
+		end := offset + len(line) + 1
+		if string(bytes.TrimRight(line, "\r")) == "---" {
+			return rest[:offset], rest[min(end, len(rest)):]
+		}
+		offset = end
+	}
+	return nil, data
+}
+
+// decodeFrontMatter decodes the JSON front matter of a fixture into v. Empty
+// front matter leaves v unchanged.
+func decodeFrontMatter(frontMatter []byte, v any) error {
+	if len(bytes.TrimSpace(frontMatter)) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(frontMatter))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid front matter: %w", err)
+	}
+	return nil
+}
+
+func loadResourceFixture(name string, data []byte) (server.ServerResource, error) {
+	frontMatter, body := splitFrontMatter(data)
+	var meta resourceFrontMatter
+	if err := decodeFrontMatter(frontMatter, &meta); err != nil {
+		return server.ServerResource{}, err
+	}
+	if meta.URI == "" {
+		meta.URI = "fixture:///" + name
+	}
+	if meta.Name == "" {
+		meta.Name = path.Base(name)
+	}
+	if meta.MIMEType == "" {
+		meta.MIMEType, _, _ = strings.Cut(mime.TypeByExtension(path.Ext(name)), ";")
+	}
+
+	var contents mcp.ResourceContents
+	if utf8.Valid(body) {
+		contents = mcp.TextResourceContents{URI: meta.URI, MIMEType: meta.MIMEType, Text: string(body)}
+	} else {
+		contents = mcp.BlobResourceContents{URI: meta.URI, MIMEType: meta.MIMEType, Blob: base64.StdEncoding.EncodeToString(body)}
+	}
+	resource := mcp.NewResource(meta.URI, meta.Name,
+		mcp.WithResourceDescription(meta.Description),
+		mcp.WithMIMEType(meta.MIMEType),
+	)
+	return server.ServerResource{
+		Resource: resource,
+		Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{contents}, nil
+		},
+	}, nil
+}
+
+func loadPromptFixture(name string, data []byte) (server.ServerPrompt, error) {
+	frontMatter, body := splitFrontMatter(data)
+	var meta promptFrontMatter
+	if err := decodeFrontMatter(frontMatter, &meta); err != nil {
+		return server.ServerPrompt{}, err
+	}
+	if meta.Name == "" {
+		meta.Name = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	role := mcp.RoleUser
+	switch meta.Role {
+	case "", string(mcp.RoleUser):
+	case string(mcp.RoleAssistant):
+		role = mcp.RoleAssistant
+	default:
+		return server.ServerPrompt{}, fmt.Errorf("invalid role %q", meta.Role)
+	}
+	tmpl, err := template.New(meta.Name).Option("missingkey=zero").Parse(string(body))
+	if err != nil {
+		return server.ServerPrompt{}, fmt.Errorf("invalid template: %w", err)
+	}
+
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(meta.Description)}
+	var required []string
+	for _, arg := range meta.Arguments {
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+		if arg.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+			required = append(required, arg.Name)
+		}
+		opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+	prompt := mcp.NewPrompt(meta.Name, opts...)
+
+	return server.ServerPrompt{
+		Prompt: prompt,
+		Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			args := request.Params.Arguments
+			if args == nil {
+				args = map[string]string{}
+			}
+			for _, name := range required {
+				if _, ok := args[name]; !ok {
+					return nil, fmt.Errorf("missing required argument %q", name)
+				}
+			}
+			var text strings.Builder
+			if err := tmpl.Execute(&text, args); err != nil {
+				return nil, err
+			}
+			return mcp.NewGetPromptResult(meta.Description, []mcp.PromptMessage{
+				mcp.NewPromptMessage(role, mcp.NewTextContent(text.String())),
+			}), nil
+		},
+	}, nil
+}
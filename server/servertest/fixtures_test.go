@@ -0,0 +1,72 @@
+package servertest_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/mcptest"
+	"github.com/mark3labs/mcp-go/server/servertest"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := servertest.LoadFixtures("testdata")
+	require.NoError(t, err)
+
+	srv := mcptest.NewUnstartedServer(t)
+	srv.AddFixtures(fixtures)
+	require.NoError(t, srv.Start(t.Context()))
+	defer srv.Close()
+	c := srv.Client()
+
+	resources, err := c.ListResources(t.Context(), mcp.ListResourcesRequest{})
+	require.NoError(t, err)
+	require.Len(t, resources.Resources, 2)
+	byURI := map[string]mcp.Resource{}
+	for _, r := range resources.Resources {
+		byURI[r.URI] = r
+	}
+	assert.Equal(t, "README", byURI["docs://readme"].Name)
+	assert.Equal(t, "Project overview", byURI["docs://readme"].Description)
+	assert.Equal(t, "application/json", byURI["fixture:///data.json"].MIMEType)
+
+	var read mcp.ReadResourceRequest
+	read.Params.URI = "docs://readme"
+	contents, err := c.ReadResource(t.Context(), read)
+	require.NoError(t, err)
+	require.Len(t, contents.Contents, 1)
+	text, ok := contents.Contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, "# Example\n\nAn example project.\n", text.Text)
+
+	var get mcp.GetPromptRequest
+	get.Params.Name = "review"
+	get.Params.Arguments = map[string]string{"language": "Go", "focus": "errors"}
+	prompt, err := c.GetPrompt(t.Context(), get)
+	require.NoError(t, err)
+	require.Len(t, prompt.Messages, 1)
+	assert.Equal(t, mcp.RoleUser, prompt.Messages[0].Role)
+	assert.Equal(t, "Review this Go change, focusing on errors.\n", prompt.Messages[0].Content.(mcp.TextContent).Text)
+
+	get.Params.Arguments = map[string]string{"focus": "errors"}
+	_, err = c.GetPrompt(t.Context(), get)
+	assert.ErrorContains(t, err, `missing required argument "language"`)
+}
+
+func TestLoadFixtures_MissingDirectories(t *testing.T) {
+	fixtures, err := servertest.LoadFixtures(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, fixtures.Resources)
+	assert.Empty(t, fixtures.Prompts)
+}
+
+func TestLoadFixturesFS_InvalidFrontMatter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"resources/notes.txt": {Data: []byte("---\n{\"url\": \"docs://notes\"}\n---\nnotes\n")},
+	}
+	_, err := servertest.LoadFixturesFS(fsys)
+	assert.ErrorContains(t, err, `unknown field "url"`)
+}
@@ -3,7 +3,6 @@ package server
 import (
 	"context"
 	"fmt"
-	"log"
 	"maps"
 	"net/url"
 
@@ -134,6 +133,20 @@ type SessionWithStreamableHTTPConfig interface {
 	UpgradeToSSEWhenReceiveNotification()
 }
 
+// SessionWithEncoding is an extension of ClientSession whose transport can
+// apply a wire encoding negotiated at initialize time. Only sessions
+// implementing it are offered an encoding by WithEncodingNegotiation.
+type SessionWithEncoding interface {
+	ClientSession
+	// SupportedEncodings returns the compression schemes and codecs the
+	// session's transport can apply.
+	SupportedEncodings() mcp.EncodingOffer
+	// SetEncoding records the encoding negotiated with the client
+	SetEncoding(encoding mcp.EncodingSelection)
+	// Encoding returns the negotiated encoding, or mcp.DefaultEncoding
+	Encoding() mcp.EncodingSelection
+}
+
 // clientSessionKey is the context key for storing current client notification channel.
 type clientSessionKey struct{}
 
@@ -162,6 +175,11 @@ func (s *MCPServer) RegisterSession(
 	if _, exists := s.sessions.LoadOrStore(sessionID, session); exists {
 		return ErrSessionExists
 	}
+	if session.Initialized() {
+		setConnectionState(session, mcp.ConnectionReady, nil)
+	} else {
+		setConnectionState(session, mcp.ConnectionConnecting, nil)
+	}
 	s.hooks.RegisterSession(ctx, session)
 	return nil
 }
@@ -194,19 +212,31 @@ func (s *MCPServer) SendLogMessageToClient(ctx context.Context, notification mcp
 	if !notification.Params.Level.ShouldSendTo(sessionLogging.GetLogLevel()) {
 		return nil
 	}
+	if s.logRateLimits != nil {
+		return s.sendRateLimitedLog(session.SessionID(), notification, func(n mcp.JSONRPCNotification) error {
+			return s.sendNotificationCore(ctx, session, n)
+		})
+	}
 	return s.sendNotificationCore(ctx, session, s.buildLogNotification(notification))
 }
 
 func (s *MCPServer) sendNotificationToAllClients(notification mcp.JSONRPCNotification) {
 	s.sessions.Range(func(k, v any) bool {
 		if session, ok := v.(ClientSession); ok && session.Initialized() {
+			if s.faultInjector.dropNotification(notification.Method) {
+				return true
+			}
 			if sessionWithStreamableHTTPConfig, ok := session.(SessionWithStreamableHTTPConfig); ok {
 				sessionWithStreamableHTTPConfig.UpgradeToSSEWhenReceiveNotification()
 			}
+			s.hooks.beforeSendNotification(context.Background(), session, notification)
 			select {
 			case session.NotificationChannel() <- notification:
 				// Successfully sent notification
+				s.hooks.afterSendNotification(context.Background(), session, notification, nil)
 			default:
+				s.metrics.notificationDropped(notification.Method)
+				s.hooks.afterSendNotification(context.Background(), session, notification, ErrNotificationChannelBlocked)
 				// Channel is blocked, if there's an error hook, use it
 				if s.hooks != nil && len(s.hooks.OnError) > 0 {
 					err := ErrNotificationChannelBlocked
@@ -215,7 +245,7 @@ func (s *MCPServer) sendNotificationToAllClients(notification mcp.JSONRPCNotific
 					go func(sessionID string, hooks *Hooks) {
 						defer func() {
 							if r := recover(); r != nil {
-								log.Printf("mcp-go: panic in OnError hook (notification blocked, session %s): %v", sessionID, r)
+								s.sessionLogger(sessionID).Error("mcp-go: panic in OnError hook", "event", "notification blocked", "panic", r)
 							}
 						}()
 						ctx := context.Background()
@@ -233,35 +263,7 @@ func (s *MCPServer) sendNotificationToAllClients(notification mcp.JSONRPCNotific
 }
 
 func (s *MCPServer) sendNotificationToSpecificClient(session ClientSession, notification mcp.JSONRPCNotification) error {
-	// upgrades the client-server communication to SSE stream when the server sends notifications to the client
-	if sessionWithStreamableHTTPConfig, ok := session.(SessionWithStreamableHTTPConfig); ok {
-		sessionWithStreamableHTTPConfig.UpgradeToSSEWhenReceiveNotification()
-	}
-	select {
-	case session.NotificationChannel() <- notification:
-		return nil
-	default:
-		// Channel is blocked, if there's an error hook, use it
-		if s.hooks != nil && len(s.hooks.OnError) > 0 {
-			err := ErrNotificationChannelBlocked
-			ctx := context.Background()
-			// Copy hooks pointer to local variable to avoid race condition
-			hooks := s.hooks
-			go func(sID string, hooks *Hooks) {
-				defer func() {
-					if r := recover(); r != nil {
-						log.Printf("mcp-go: panic in OnError hook (notification blocked, session %s): %v", sID, r)
-					}
-				}()
-				// Use the error hook to report the blocked channel
-				hooks.onError(ctx, nil, "notification", map[string]any{
-					"method":    notification.Method,
-					"sessionID": sID,
-				}, fmt.Errorf("notification channel blocked for session %s: %w", sID, err))
-			}(session.SessionID(), hooks)
-		}
-		return ErrNotificationChannelBlocked
-	}
+	return s.sendNotificationCore(context.Background(), session, notification)
 }
 
 func (s *MCPServer) SendLogMessageToSpecificClient(sessionID string, notification mcp.LoggingMessageNotification) error {
@@ -280,6 +282,11 @@ func (s *MCPServer) SendLogMessageToSpecificClient(sessionID string, notificatio
 	if !notification.Params.Level.ShouldSendTo(sessionLogging.GetLogLevel()) {
 		return nil
 	}
+	if s.logRateLimits != nil {
+		return s.sendRateLimitedLog(sessionID, notification, func(n mcp.JSONRPCNotification) error {
+			return s.sendNotificationToSpecificClient(session, n)
+		})
+	}
 	return s.sendNotificationToSpecificClient(session, s.buildLogNotification(notification))
 }
 
@@ -292,7 +299,23 @@ func (s *MCPServer) UnregisterSession(
 	if !ok {
 		return
 	}
+	s.dropLogLimiter(sessionID)
+	s.rateLimiters.forget(sessionID)
+	s.progressThrottle.forget(sessionID)
+	s.sessionLabels.Delete(sessionID)
+	s.taskSubscriptions.Delete(sessionID)
+	s.dropResourceSubscriptions(sessionID)
+	s.closeResourceStreams(sessionID)
+	s.dropSamplingAccount(sessionID)
+	if s.rootsCache != nil {
+		s.rootsCache.entries.Delete(sessionID)
+	}
+	s.rootsWatch.forget(sessionID)
+	if s.stats != nil {
+		s.stats.subscribers.Delete(sessionID)
+	}
 	if session, ok := sessionValue.(ClientSession); ok {
+		setConnectionState(session, mcp.ConnectionClosed, nil)
 		s.hooks.UnregisterSession(ctx, session)
 	}
 }
@@ -320,14 +343,23 @@ func (s *MCPServer) sendNotificationCore(
 	session ClientSession,
 	notification mcp.JSONRPCNotification,
 ) error {
+	if s.faultInjector.dropNotification(notification.Method) {
+		return nil
+	}
 	// upgrades the client-server communication to SSE stream when the server sends notifications to the client
 	if sessionWithStreamableHTTPConfig, ok := session.(SessionWithStreamableHTTPConfig); ok {
 		sessionWithStreamableHTTPConfig.UpgradeToSSEWhenReceiveNotification()
 	}
+	s.hooks.beforeSendNotification(ctx, session, notification)
 	select {
 	case session.NotificationChannel() <- notification:
+		recoverConnection(session)
+		s.hooks.afterSendNotification(ctx, session, notification, nil)
 		return nil
 	default:
+		s.metrics.notificationDropped(notification.Method)
+		degradeConnection(session, ErrNotificationChannelBlocked)
+		s.hooks.afterSendNotification(ctx, session, notification, ErrNotificationChannelBlocked)
 		// Channel is blocked, if there's an error hook, use it
 		if s.hooks != nil && len(s.hooks.OnError) > 0 {
 			method := notification.Method
@@ -337,7 +369,7 @@ func (s *MCPServer) sendNotificationCore(
 			go func(sessionID string, hooks *Hooks) {
 				defer func() {
 					if r := recover(); r != nil {
-						log.Printf("mcp-go: panic in OnError hook (notification blocked, session %s): %v", sessionID, r)
+						s.sessionLogger(sessionID).Error("mcp-go: panic in OnError hook", "event", "notification blocked", "panic", r)
 					}
 				}()
 				// Use the error hook to report the blocked channel
@@ -370,6 +402,11 @@ func (s *MCPServer) SendNotificationToClient(
 			},
 		},
 	}
+	if s.progressThrottle != nil && method == string(mcp.MethodNotificationProgress) {
+		return s.progressThrottle.send(ctx, session.SessionID(), notification, func(n mcp.JSONRPCNotification) error {
+			return s.sendNotificationCore(ctx, session, n)
+		})
+	}
 	return s.sendNotificationCore(ctx, session, notification)
 }
 
@@ -396,6 +433,11 @@ func (s *MCPServer) SendNotificationToSpecificClient(
 			},
 		},
 	}
+	if s.progressThrottle != nil && method == string(mcp.MethodNotificationProgress) {
+		return s.progressThrottle.send(context.Background(), sessionID, notification, func(n mcp.JSONRPCNotification) error {
+			return s.sendNotificationToSpecificClient(session, n)
+		})
+	}
 	return s.sendNotificationToSpecificClient(session, notification)
 }
 
@@ -453,7 +495,7 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 				go func(sID string, hooks *Hooks) {
 					defer func() {
 						if r := recover(); r != nil {
-							log.Printf("mcp-go: panic in OnError hook (tools added, session %s): %v", sID, r)
+							s.sessionLogger(sID).Error("mcp-go: panic in OnError hook", "event", "tools added", "panic", r)
 						}
 					}()
 					ctx := context.Background()
@@ -518,7 +560,7 @@ func (s *MCPServer) DeleteSessionTools(sessionID string, names ...string) error
 				go func(sID string, hooks *Hooks) {
 					defer func() {
 						if r := recover(); r != nil {
-							log.Printf("mcp-go: panic in OnError hook (tools deleted, session %s): %v", sID, r)
+							s.sessionLogger(sID).Error("mcp-go: panic in OnError hook", "event", "tools deleted", "panic", r)
 						}
 					}()
 					ctx := context.Background()
@@ -601,7 +643,7 @@ func (s *MCPServer) AddSessionResources(sessionID string, resources ...ServerRes
 				go func(sID string, hooks *Hooks) {
 					defer func() {
 						if r := recover(); r != nil {
-							log.Printf("mcp-go: panic in OnError hook (resources added, session %s): %v", sID, r)
+							s.sessionLogger(sID).Error("mcp-go: panic in OnError hook", "event", "resources added", "panic", r)
 						}
 					}()
 					ctx := context.Background()
@@ -676,7 +718,7 @@ func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) err
 				go func(sID string, hooks *Hooks) {
 					defer func() {
 						if r := recover(); r != nil {
-							log.Printf("mcp-go: panic in OnError hook (resources deleted, session %s): %v", sID, r)
+							s.sessionLogger(sID).Error("mcp-go: panic in OnError hook", "event", "resources deleted", "panic", r)
 						}
 					}()
 					ctx := context.Background()
@@ -741,6 +783,9 @@ func (s *MCPServer) AddSessionResourceTemplates(sessionID string, templates ...S
 			return fmt.Errorf("resource template name cannot be empty")
 		}
 		newTemplates[raw] = t
+		if len(t.Completions) > 0 {
+			s.implicitlyRegisterCompletionCapabilities()
+		}
 	}
 
 	// Set the new templates (this method must handle thread-safety)
@@ -755,7 +800,7 @@ func (s *MCPServer) AddSessionResourceTemplates(sessionID string, templates ...S
 				go func(sID string, hooks *Hooks) {
 					defer func() {
 						if r := recover(); r != nil {
-							log.Printf("mcp-go: panic in OnError hook (resource templates added, session %s): %v", sID, r)
+							s.sessionLogger(sID).Error("mcp-go: panic in OnError hook", "event", "resource templates added", "panic", r)
 						}
 					}()
 					ctx := context.Background()
@@ -815,7 +860,7 @@ func (s *MCPServer) DeleteSessionResourceTemplates(sessionID string, uriTemplate
 					go func(sID string, hooks *Hooks) {
 						defer func() {
 							if r := recover(); r != nil {
-								log.Printf("mcp-go: panic in OnError hook (resource templates deleted, session %s): %v", sID, r)
+								s.sessionLogger(sID).Error("mcp-go: panic in OnError hook", "event", "resource templates deleted", "panic", r)
 							}
 						}()
 						ctx := context.Background()
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minHMACKeyLength is the minimum accepted key length for HMACSessionIdManager.
+const minHMACKeyLength = 32
+
+// DefaultHMACSessionMaxAge is the lifetime of the session IDs issued by an
+// HMACSessionIdManager whose MaxAge is zero.
+const DefaultHMACSessionMaxAge = 24 * time.Hour
+
+// hmacPruneInterval is how often Terminate drops expired IDs from the set of
+// terminated IDs.
+const hmacPruneInterval = time.Minute
+
+// ErrHMACKeyTooShort is returned by NewHMACSessionIdManager when a key is
+// shorter than 32 bytes.
+var ErrHMACKeyTooShort = errors.New("hmac session id key must be at least 32 bytes")
+
+// HMACSessionIdManager issues session IDs that carry an HMAC-SHA256 signature,
+// so any replica sharing the key can verify that an ID was issued by the
+// deployment without shared storage. Forged or tampered IDs are rejected.
+//
+// IDs have the form "mcp-session-<uuid>.<expiry>.<signature>", where expiry
+// is the Unix time after which the ID is no longer accepted; clients then
+// initialize a new session. Terminated IDs are remembered in memory by the
+// instance that terminated them until they expire; deployments that need
+// cluster-wide termination should wrap this manager with a shared store.
+type HMACSessionIdManager struct {
+	// MaxAge is the lifetime of issued IDs. Zero means
+	// DefaultHMACSessionMaxAge. Set it before the manager is used.
+	MaxAge time.Duration
+
+	key        []byte
+	verifyKeys [][]byte
+	now        func() time.Time
+
+	mu         sync.Mutex
+	terminated map[string]time.Time // session ID -> expiry
+	lastPrune  time.Time
+}
+
+// NewHMACSessionIdManager creates an HMACSessionIdManager that signs new IDs
+// with key. IDs signed with any of previousKeys are still accepted, which
+// allows keys to be rotated without invalidating live sessions.
+func NewHMACSessionIdManager(key []byte, previousKeys ...[]byte) (*HMACSessionIdManager, error) {
+	verifyKeys := make([][]byte, 0, len(previousKeys)+1)
+	for _, k := range append([][]byte{key}, previousKeys...) {
+		if len(k) < minHMACKeyLength {
+			return nil, ErrHMACKeyTooShort
+		}
+		verifyKeys = append(verifyKeys, append([]byte(nil), k...))
+	}
+	return &HMACSessionIdManager{
+		key:        verifyKeys[0],
+		verifyKeys: verifyKeys,
+		now:        time.Now,
+		terminated: make(map[string]time.Time),
+	}, nil
+}
+
+// Generate returns a new signed session ID.
+func (m *HMACSessionIdManager) Generate() string {
+	maxAge := m.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultHMACSessionMaxAge
+	}
+	expiry := m.now().Add(maxAge).Unix()
+	payload := idPrefix + uuid.New().String() + "." + strconv.FormatInt(expiry, 10)
+	return payload + "." + m.sign(m.key, payload)
+}
+
+// Validate checks the signature and expiry of sessionID and reports whether
+// it has been terminated on this instance.
+func (m *HMACSessionIdManager) Validate(sessionID string) (isTerminated bool, err error) {
+	if _, ok := m.verify(sessionID); !ok {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, isTerminated = m.terminated[sessionID]
+	return isTerminated, nil
+}
+
+// Terminate marks a validly signed session ID as terminated.
+func (m *HMACSessionIdManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	expiry, ok := m.verify(sessionID)
+	if !ok {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.terminated[sessionID] = expiry
+	if now := m.now(); now.Sub(m.lastPrune) >= hmacPruneInterval {
+		// Expired IDs fail validation anyway, so they need not be kept.
+		for id, expiry := range m.terminated {
+			if now.After(expiry) {
+				delete(m.terminated, id)
+			}
+		}
+		m.lastPrune = now
+	}
+	return false, nil
+}
+
+func (m *HMACSessionIdManager) sign(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify reports whether sessionID carries a valid signature and has not
+// expired, and returns its expiry.
+func (m *HMACSessionIdManager) verify(sessionID string) (time.Time, bool) {
+	dot := strings.LastIndexByte(sessionID, '.')
+	if dot < 0 {
+		return time.Time{}, false
+	}
+	payload, signature := sessionID[:dot], sessionID[dot+1:]
+	id, expiryText, ok := strings.Cut(payload, ".")
+	if !ok || !strings.HasPrefix(id, idPrefix) {
+		return time.Time{}, false
+	}
+	if _, err := uuid.Parse(id[len(idPrefix):]); err != nil {
+		return time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(expiryText, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	valid := false
+	for _, key := range m.verifyKeys {
+		if hmac.Equal([]byte(signature), []byte(m.sign(key, payload))) {
+			valid = true
+			break
+		}
+	}
+	expiry := time.Unix(unix, 0)
+	if !valid || m.now().After(expiry) {
+		return time.Time{}, false
+	}
+	return expiry, true
+}
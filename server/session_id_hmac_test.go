@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACSessionIdManager(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+	otherKey := bytes.Repeat([]byte("o"), 32)
+
+	t.Run("rejects short keys", func(t *testing.T) {
+		_, err := NewHMACSessionIdManager([]byte("short"))
+		assert.ErrorIs(t, err, ErrHMACKeyTooShort)
+
+		_, err = NewHMACSessionIdManager(key, []byte("short"))
+		assert.ErrorIs(t, err, ErrHMACKeyTooShort)
+	})
+
+	t.Run("generated IDs validate on another replica with the same key", func(t *testing.T) {
+		issuer, err := NewHMACSessionIdManager(key)
+		require.NoError(t, err)
+		replica, err := NewHMACSessionIdManager(key)
+		require.NoError(t, err)
+
+		sessionID := issuer.Generate()
+		assert.True(t, strings.HasPrefix(sessionID, idPrefix))
+
+		isTerminated, err := replica.Validate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, isTerminated)
+	})
+
+	t.Run("rejects forged and tampered IDs", func(t *testing.T) {
+		manager, err := NewHMACSessionIdManager(key)
+		require.NoError(t, err)
+		forger, err := NewHMACSessionIdManager(otherKey)
+		require.NoError(t, err)
+
+		valid := manager.Generate()
+		tests := []string{
+			"",
+			"mcp-session-ffffffff-ffff-ffff-ffff-ffffffffffff",
+			forger.Generate(),
+			valid[:len(valid)-2] + "xx",
+			strings.Replace(valid, idPrefix, "mcp-session-0", 1),
+		}
+		for _, sessionID := range tests {
+			_, err := manager.Validate(sessionID)
+			assert.Error(t, err, sessionID)
+		}
+	})
+
+	t.Run("accepts IDs signed with a previous key", func(t *testing.T) {
+		old, err := NewHMACSessionIdManager(otherKey)
+		require.NoError(t, err)
+		rotated, err := NewHMACSessionIdManager(key, otherKey)
+		require.NoError(t, err)
+
+		_, err = rotated.Validate(old.Generate())
+		assert.NoError(t, err)
+	})
+
+	t.Run("terminate", func(t *testing.T) {
+		manager, err := NewHMACSessionIdManager(key)
+		require.NoError(t, err)
+		sessionID := manager.Generate()
+
+		isNotAllowed, err := manager.Terminate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, isNotAllowed)
+
+		isTerminated, err := manager.Validate(sessionID)
+		require.NoError(t, err)
+		assert.True(t, isTerminated)
+	})
+
+	t.Run("IDs expire and terminated IDs are pruned", func(t *testing.T) {
+		manager, err := NewHMACSessionIdManager(key)
+		require.NoError(t, err)
+		manager.MaxAge = time.Hour
+		clock := NewManualClock(time.Unix(1_700_000_000, 0))
+		manager.now = clock.Now
+
+		expiring := manager.Generate()
+		_, err = manager.Terminate(expiring)
+		require.NoError(t, err)
+
+		clock.Advance(59 * time.Minute)
+		live := manager.Generate()
+		_, err = manager.Validate(expiring)
+		require.NoError(t, err)
+
+		clock.Advance(2 * time.Minute)
+		_, err = manager.Validate(expiring)
+		assert.Error(t, err, "expired IDs are rejected")
+		_, err = manager.Terminate(expiring)
+		assert.Error(t, err)
+
+		_, err = manager.Terminate(live)
+		require.NoError(t, err)
+		manager.mu.Lock()
+		assert.Equal(t, []string{live}, slices.Collect(maps.Keys(manager.terminated)))
+		manager.mu.Unlock()
+	})
+}
+
+func TestStreamableHTTP_WithHMACSessionIdManager(t *testing.T) {
+	manager, err := NewHMACSessionIdManager(bytes.Repeat([]byte("k"), 32))
+	require.NoError(t, err)
+
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	server := NewTestStreamableHTTPServer(mcpServer, WithSessionIdManager(manager))
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	_, err = manager.Validate(sessionID)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderKeySessionID, sessionID+"x")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStreamableHTTP_WithSessionSigningKey(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+
+	assert.PanicsWithValue(t, ErrHMACKeyTooShort, func() { WithSessionSigningKey([]byte("short")) })
+
+	for name, opts := range map[string][]StreamableHTTPOption{
+		"default":  {WithSessionSigningKey(key)},
+		"stateful": {WithSessionSigningKey(key), WithStateful(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := NewStreamableHTTPServer(mcpServer, opts...)
+			assert.IsType(t, &HMACSessionIdManager{}, server.sessionIdManager)
+		})
+	}
+
+	t.Run("explicit managers take precedence", func(t *testing.T) {
+		server := NewStreamableHTTPServer(mcpServer, WithSessionIdManager(&InsecureStatefulSessionIdManager{}), WithSessionSigningKey(key))
+		assert.IsType(t, &InsecureStatefulSessionIdManager{}, server.sessionIdManager)
+
+		server = NewStreamableHTTPServer(mcpServer, WithSessionSigningKey(key), WithStateLess(true))
+		assert.IsType(t, &StatelessSessionIdManager{}, server.sessionIdManager)
+	})
+}
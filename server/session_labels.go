@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionLabeler computes labels for a session when it initializes. ctx is
+// the context of the initialize request, so it carries the HTTP headers on
+// HTTP transports and anything added by WithHTTPContextFunc, such as
+// authentication claims.
+type SessionLabeler func(ctx context.Context, session ClientSession, request mcp.InitializeRequest) map[string]string
+
+// WithSessionLabeler attaches labels computed by labelers to every session
+// at initialize time. When several labelers set the same label, the last one
+// wins. Labels scope broadcasts (SendNotificationToSessionsMatching), can be
+// queried with SessionLabels and SessionsMatching, and are available to
+// handlers, hooks and metrics through SessionLabelsFromContext.
+func WithSessionLabeler(labelers ...SessionLabeler) ServerOption {
+	return func(s *MCPServer) {
+		s.sessionLabelers = append(s.sessionLabelers, labelers...)
+	}
+}
+
+// HeaderLabels returns a SessionLabeler that copies HTTP request headers
+// into labels. headerToLabel maps header names to label names. Headers that
+// are absent are skipped.
+func HeaderLabels(headerToLabel map[string]string) SessionLabeler {
+	return func(ctx context.Context, _ ClientSession, _ mcp.InitializeRequest) map[string]string {
+		headers, _ := ctx.Value(requestHeader).(http.Header)
+		labels := make(map[string]string, len(headerToLabel))
+		for header, label := range headerToLabel {
+			if v := headers.Get(header); v != "" {
+				labels[label] = v
+			}
+		}
+		return labels
+	}
+}
+
+// ClientInfoLabels is a SessionLabeler that labels sessions with the name
+// and version the client reported in initialize, as "client.name" and
+// "client.version".
+func ClientInfoLabels(_ context.Context, _ ClientSession, request mcp.InitializeRequest) map[string]string {
+	info := request.Params.ClientInfo
+	labels := make(map[string]string, 2)
+	if info.Name != "" {
+		labels["client.name"] = info.Name
+	}
+	if info.Version != "" {
+		labels["client.version"] = info.Version
+	}
+	return labels
+}
+
+// applySessionLabels runs the configured labelers for a session being
+// initialized.
+func (s *MCPServer) applySessionLabels(ctx context.Context, session ClientSession, request mcp.InitializeRequest) {
+	if len(s.sessionLabelers) == 0 {
+		return
+	}
+	labels := make(map[string]string)
+	for _, labeler := range s.sessionLabelers {
+		maps.Copy(labels, labeler(ctx, session, request))
+	}
+	s.sessionLabels.Store(session.SessionID(), labels)
+}
+
+// SetSessionLabels replaces the labels of a registered session.
+func (s *MCPServer) SetSessionLabels(sessionID string, labels map[string]string) error {
+	if _, ok := s.sessions.Load(sessionID); !ok {
+		return ErrSessionNotFound
+	}
+	s.sessionLabels.Store(sessionID, maps.Clone(labels))
+	return nil
+}
+
+// SessionLabels returns a copy of the labels of a session, or nil if it has
+// none.
+func (s *MCPServer) SessionLabels(sessionID string) map[string]string {
+	labels, ok := s.sessionLabels.Load(sessionID)
+	if !ok {
+		return nil
+	}
+	return maps.Clone(labels.(map[string]string))
+}
+
+// SessionLabelsFromContext returns the labels of the session serving ctx,
+// or nil if it has none.
+func SessionLabelsFromContext(ctx context.Context) map[string]string {
+	session := ClientSessionFromContext(ctx)
+	server := ServerFromContext(ctx)
+	if session == nil || server == nil {
+		return nil
+	}
+	return server.SessionLabels(session.SessionID())
+}
+
+// SessionsMatching returns the IDs of the registered sessions carrying every
+// label in selector. An empty selector matches every session.
+func (s *MCPServer) SessionsMatching(selector map[string]string) []string {
+	var ids []string
+	s.sessions.Range(func(key, _ any) bool {
+		id := key.(string)
+		if labelsMatch(s.SessionLabels(id), selector) {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	return ids
+}
+
+// SendNotificationToSessionsMatching sends a notification to every
+// initialized session carrying all the labels in selector. Errors for
+// individual sessions, such as blocked notification channels, are joined in
+// the returned error; the notification is still sent to the other sessions.
+func (s *MCPServer) SendNotificationToSessionsMatching(
+	selector map[string]string,
+	method string,
+	params map[string]any,
+) error {
+	var errs []error
+	for _, id := range s.SessionsMatching(selector) {
+		err := s.SendNotificationToSpecificClient(id, method, params)
+		if err != nil && !errors.Is(err, ErrSessionNotInitialized) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func labelsMatch(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
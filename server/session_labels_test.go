@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionLabels(t *testing.T) {
+	var handlerLabels map[string]string
+	server := NewMCPServer("test", "1.0.0",
+		WithSessionLabeler(
+			HeaderLabels(map[string]string{"X-Tenant": "tenant"}),
+			ClientInfoLabels,
+			func(ctx context.Context, _ ClientSession, _ mcp.InitializeRequest) map[string]string {
+				return map[string]string{"tier": "free"}
+			},
+		),
+	)
+	server.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		handlerLabels = SessionLabelsFromContext(ctx)
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	sessions := map[string]*sessionTestClient{}
+	initialize := func(id, tenant, clientName string) {
+		session := &sessionTestClient{sessionID: id, notificationChannel: make(chan mcp.JSONRPCNotification, 10)}
+		sessions[id] = session
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		ctx := server.WithContext(context.Background(), session)
+		ctx = context.WithValue(ctx, requestHeader, http.Header{"X-Tenant": []string{tenant}})
+		msg, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0", "id": 1, "method": "initialize",
+			"params": map[string]any{
+				"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+				"clientInfo":      map[string]any{"name": clientName, "version": "1.0"},
+			},
+		})
+		_, ok := server.HandleMessage(ctx, msg).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+	}
+	initialize("a", "acme", "cli")
+	initialize("b", "acme", "ide")
+	initialize("c", "globex", "cli")
+
+	assert.Equal(t, map[string]string{
+		"tenant": "acme", "client.name": "cli", "client.version": "1.0", "tier": "free",
+	}, server.SessionLabels("a"))
+
+	assert.ElementsMatch(t, []string{"a", "b"}, server.SessionsMatching(map[string]string{"tenant": "acme"}))
+	assert.ElementsMatch(t, []string{"a", "c"}, server.SessionsMatching(map[string]string{"client.name": "cli"}))
+	assert.Len(t, server.SessionsMatching(nil), 3)
+
+	t.Run("scoped broadcast", func(t *testing.T) {
+		require.NoError(t, server.SendNotificationToSessionsMatching(
+			map[string]string{"tenant": "acme", "client.name": "ide"}, "notifications/test", map[string]any{"x": 1}))
+		assert.Len(t, sessions["a"].notificationChannel, 0)
+		assert.Len(t, sessions["b"].notificationChannel, 1)
+		assert.Len(t, sessions["c"].notificationChannel, 0)
+	})
+
+	t.Run("labels in handler context", func(t *testing.T) {
+		ctx := server.WithContext(context.Background(), sessions["c"])
+		server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"whoami"}}`))
+		assert.Equal(t, "globex", handlerLabels["tenant"])
+	})
+
+	t.Run("set and release", func(t *testing.T) {
+		require.NoError(t, server.SetSessionLabels("c", map[string]string{"tenant": "initech"}))
+		assert.Equal(t, []string{"c"}, server.SessionsMatching(map[string]string{"tenant": "initech"}))
+		assert.ErrorIs(t, server.SetSessionLabels("missing", nil), ErrSessionNotFound)
+
+		server.UnregisterSession(context.Background(), "c")
+		assert.Nil(t, server.SessionLabels("c"))
+	})
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sessionSnapshotVersion is the format version written by ExportSession.
+// ImportSession rejects snapshots with a newer version than it understands.
+const sessionSnapshotVersion = 1
+
+var (
+	// ErrUnsupportedSnapshotVersion is returned by ImportSession when the
+	// snapshot was written by a newer, incompatible version of the server.
+	ErrUnsupportedSnapshotVersion = errors.New("unsupported session snapshot version")
+	// ErrUnresolvedSessionTool is returned by ImportSession when a session
+	// tool in the snapshot could not be bound to a handler.
+	ErrUnresolvedSessionTool = errors.New("unresolved session tool")
+)
+
+// SessionSnapshot is the durable, transport-independent state of a client
+// session. It is produced by ExportSession and consumed by ImportSession so
+// that long-lived sessions can be moved between server instances, e.g. during
+// a blue/green deploy.
+//
+// Handlers are not serializable, so session tools are captured by definition
+// only and re-bound to handlers on import through a SessionToolResolver.
+type SessionSnapshot struct {
+	Version            int                    `json:"version"`
+	SessionID          string                 `json:"sessionId"`
+	Subject            string                 `json:"subject,omitempty"`
+	ClientInfo         mcp.Implementation     `json:"clientInfo"`
+	ClientCapabilities mcp.ClientCapabilities `json:"clientCapabilities"`
+	Extensions         mcp.Extensions         `json:"extensions,omitempty"`
+	LogLevel           mcp.LoggingLevel       `json:"logLevel,omitempty"`
+	Subscriptions      []string               `json:"subscriptions,omitempty"`
+	Labels             map[string]string      `json:"labels,omitempty"`
+	Roots              *mcp.ListRootsResult   `json:"roots,omitempty"`
+	RootsExpire        *time.Time             `json:"rootsExpire,omitempty"`
+	Tools              []mcp.Tool             `json:"tools,omitempty"`
+	TaskIDs            []string               `json:"taskIds,omitempty"`
+}
+
+// SessionToolResolver binds a session tool definition restored from a
+// snapshot to its handler. It returns false if the tool is unknown to the
+// importing instance.
+type SessionToolResolver func(tool mcp.Tool) (ToolHandlerFunc, bool)
+
+// ExportSession captures the durable state of the registered session with the
+// given ID and returns it as an opaque JSON blob suitable for ImportSession.
+//
+// Besides the session's labels, the roots cached by WithRootsCache and the
+// resource subscriptions the server records for NotifyResourceUpdated, only
+// state exposed through the optional session interfaces is captured: the
+// bound subject (SessionWithSubject), client info (SessionWithClientInfo),
+// negotiated extensions (SessionWithExtensions), log level
+// (SessionWithLogging), resource subscriptions
+// (SessionWithResourceSubscriptions) and session tools (SessionWithTools).
+// References to tasks owned by the session are included so they can be
+// re-associated with the session on import.
+func (s *MCPServer) ExportSession(sessionID string) ([]byte, error) {
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	session, ok := value.(ClientSession)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	snapshot := SessionSnapshot{
+		Version:   sessionSnapshotVersion,
+		SessionID: session.SessionID(),
+	}
+
+	if bound, ok := session.(SessionWithSubject); ok {
+		snapshot.Subject = bound.Subject()
+	}
+	if info, ok := session.(SessionWithClientInfo); ok {
+		snapshot.ClientInfo = info.GetClientInfo()
+		snapshot.ClientCapabilities = info.GetClientCapabilities()
+	}
+	if ext, ok := session.(SessionWithExtensions); ok {
+		snapshot.Extensions = ext.Extensions()
+	}
+	if logging, ok := session.(SessionWithLogging); ok {
+		snapshot.LogLevel = logging.GetLogLevel()
+	}
+	// Subscriptions are known to the server, for NotifyResourceUpdated,
+	// and possibly to the session.
+	snapshot.Subscriptions = s.resourceSubscriptions(sessionID)
+	if subs, ok := session.(SessionWithResourceSubscriptions); ok {
+		for _, uri := range subs.SubscribedResources() {
+			if !slices.Contains(snapshot.Subscriptions, uri) {
+				snapshot.Subscriptions = append(snapshot.Subscriptions, uri)
+			}
+		}
+		sort.Strings(snapshot.Subscriptions)
+	}
+	snapshot.Labels = s.SessionLabels(sessionID)
+	if s.rootsCache != nil {
+		if value, ok := s.rootsCache.entries.Load(sessionID); ok {
+			entry := value.(rootsCacheEntry)
+			if entry.expires.IsZero() || s.now().Before(entry.expires) {
+				snapshot.Roots = entry.result
+				if !entry.expires.IsZero() {
+					snapshot.RootsExpire = &entry.expires
+				}
+			}
+		}
+	}
+	if toolSession, ok := session.(SessionWithTools); ok {
+		for _, tool := range toolSession.GetSessionTools() {
+			snapshot.Tools = append(snapshot.Tools, tool.Tool)
+		}
+		sort.Slice(snapshot.Tools, func(i, j int) bool {
+			return snapshot.Tools[i].Name < snapshot.Tools[j].Name
+		})
+	}
+
+	s.tasksMu.RLock()
+	for taskID, entry := range s.tasks {
+		if entry.sessionID == sessionID {
+			snapshot.TaskIDs = append(snapshot.TaskIDs, taskID)
+		}
+	}
+	s.tasksMu.RUnlock()
+	sort.Strings(snapshot.TaskIDs)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// ImportSession restores state previously produced by ExportSession onto the
+// given session. The session does not have to be registered yet and may carry
+// a different ID than the exported one; any tasks known to this server that
+// belonged to the exported session are re-associated with it, and so are its
+// resource subscriptions, labels and, with WithRootsCache, cached roots.
+//
+// Session tools are re-bound through resolve. If any tool cannot be resolved,
+// ImportSession returns an error wrapping ErrUnresolvedSessionTool and leaves
+// the session untouched. Snapshot state the session has no interface for is
+// ignored.
+func (s *MCPServer) ImportSession(session ClientSession, data []byte, resolve SessionToolResolver) error {
+	var snapshot SessionSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal session snapshot: %w", err)
+	}
+	if snapshot.Version > sessionSnapshotVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSnapshotVersion, snapshot.Version)
+	}
+
+	toolSession, hasTools := session.(SessionWithTools)
+	var tools map[string]ServerTool
+	if hasTools && len(snapshot.Tools) > 0 {
+		tools = make(map[string]ServerTool, len(snapshot.Tools))
+		for _, tool := range snapshot.Tools {
+			var handler ToolHandlerFunc
+			ok := false
+			if resolve != nil {
+				handler, ok = resolve(tool)
+			}
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrUnresolvedSessionTool, tool.Name)
+			}
+			tools[tool.Name] = ServerTool{Tool: tool, Handler: handler}
+		}
+	}
+
+	if bound, ok := session.(SessionWithSubject); ok && snapshot.Subject != "" {
+		if err := bound.SetSubject(snapshot.Subject); err != nil {
+			return fmt.Errorf("failed to bind session subject: %w", err)
+		}
+	}
+	if info, ok := session.(SessionWithClientInfo); ok {
+		info.SetClientInfo(snapshot.ClientInfo)
+		info.SetClientCapabilities(snapshot.ClientCapabilities)
+	}
+	if ext, ok := session.(SessionWithExtensions); ok && snapshot.Extensions != nil {
+		ext.SetExtensions(snapshot.Extensions)
+	}
+	if logging, ok := session.(SessionWithLogging); ok && snapshot.LogLevel != "" {
+		logging.SetLogLevel(snapshot.LogLevel)
+	}
+	for _, uri := range snapshot.Subscriptions {
+		s.setResourceSubscription(session.SessionID(), uri, true)
+	}
+	if snapshot.Labels != nil {
+		s.sessionLabels.Store(session.SessionID(), maps.Clone(snapshot.Labels))
+	}
+	if s.rootsCache != nil && snapshot.Roots != nil {
+		entry := rootsCacheEntry{result: cloneRoots(snapshot.Roots)}
+		if snapshot.RootsExpire != nil {
+			entry.expires = *snapshot.RootsExpire
+		}
+		if entry.expires.IsZero() || s.now().Before(entry.expires) {
+			s.rootsCache.entries.Store(session.SessionID(), entry)
+		}
+	}
+	if subs, ok := session.(SessionWithResourceSubscriptions); ok {
+		for _, uri := range snapshot.Subscriptions {
+			subs.SubscribeToResource(uri)
+		}
+	}
+	if tools != nil {
+		toolSession.SetSessionTools(tools)
+	}
+
+	if len(snapshot.TaskIDs) > 0 {
+		newID := session.SessionID()
+		var moved []*taskEntry
+		s.tasksMu.Lock()
+		for _, taskID := range snapshot.TaskIDs {
+			if entry, ok := s.tasks[taskID]; ok && entry.sessionID == snapshot.SessionID {
+				entry.sessionID = newID
+				moved = append(moved, entry)
+			}
+		}
+		s.tasksMu.Unlock()
+		for _, entry := range moved {
+			s.persistTask(entry)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migratableTestSession combines the optional session interfaces captured by
+// ExportSession so a full round trip can be exercised.
+type migratableTestSession struct {
+	*sessionWithSubscriptions
+	sessionTestClientWithLogging
+	clientInfoStore
+	tools   sessionTestClientWithTools
+	subject string
+}
+
+func newMigratableTestSession(id string) *migratableTestSession {
+	s := &migratableTestSession{sessionWithSubscriptions: newSessionWithSubscriptions(id)}
+	s.sessionTestClientWithLogging.loggingLevel.Store(mcp.LoggingLevelError)
+	return s
+}
+
+func (s *migratableTestSession) SessionID() string { return s.sessionWithSubscriptions.SessionID() }
+func (s *migratableTestSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.sessionWithSubscriptions.NotificationChannel()
+}
+func (s *migratableTestSession) Initialize()       { s.sessionWithSubscriptions.Initialize() }
+func (s *migratableTestSession) Initialized() bool { return s.sessionWithSubscriptions.Initialized() }
+func (s *migratableTestSession) GetSessionTools() map[string]ServerTool {
+	return s.tools.GetSessionTools()
+}
+func (s *migratableTestSession) SetSessionTools(tools map[string]ServerTool) {
+	s.tools.SetSessionTools(tools)
+}
+
+func (s *migratableTestSession) Subject() string { return s.subject }
+func (s *migratableTestSession) SetSubject(subject string) error {
+	if s.subject == "" {
+		s.subject = subject
+	}
+	return nil
+}
+
+var (
+	_ SessionWithSubject               = (*migratableTestSession)(nil)
+	_ SessionWithTools                 = (*migratableTestSession)(nil)
+	_ SessionWithLogging               = (*migratableTestSession)(nil)
+	_ SessionWithClientInfo            = (*migratableTestSession)(nil)
+	_ SessionWithResourceSubscriptions = (*migratableTestSession)(nil)
+)
+
+func TestMCPServer_ExportImportSession(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	source := NewMCPServer("source", "1.0.0", WithToolCapabilities(true), WithRootsCache(time.Hour))
+	old := newMigratableTestSession("old-session")
+	old.Initialize()
+	require.NoError(t, source.RegisterSession(context.Background(), old))
+	require.NoError(t, source.SetSessionLabels("old-session", map[string]string{"tenant": "acme"}))
+	roots := &mcp.ListRootsResult{Roots: []mcp.Root{{URI: "file:///workspace", Name: "workspace"}}}
+	source.cacheRoots(old, roots)
+
+	require.NoError(t, old.SetSubject("alice"))
+	old.SetClientInfo(mcp.Implementation{Name: "agent", Version: "2.0.0"})
+	old.SetLogLevel(mcp.LoggingLevelDebug)
+	old.SubscribeToResource("file:///b")
+	old.SubscribeToResource("file:///a")
+	old.SetSessionTools(map[string]ServerTool{
+		"session_tool": {Tool: mcp.NewTool("session_tool"), Handler: handler},
+	})
+
+	ctx := source.WithContext(context.Background(), old)
+	_, err := source.createTask(ctx, "task-1", "session_tool", nil, nil)
+	require.NoError(t, err)
+
+	blob, err := source.ExportSession("old-session")
+	require.NoError(t, err)
+
+	var snapshot SessionSnapshot
+	require.NoError(t, json.Unmarshal(blob, &snapshot))
+	assert.Equal(t, sessionSnapshotVersion, snapshot.Version)
+	assert.Equal(t, "old-session", snapshot.SessionID)
+	assert.Equal(t, "alice", snapshot.Subject)
+	assert.Equal(t, []string{"file:///a", "file:///b"}, snapshot.Subscriptions)
+	assert.Equal(t, []string{"task-1"}, snapshot.TaskIDs)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, snapshot.Labels)
+	assert.Equal(t, roots, snapshot.Roots)
+	require.NotNil(t, snapshot.RootsExpire)
+	require.Len(t, snapshot.Tools, 1)
+	assert.Equal(t, "session_tool", snapshot.Tools[0].Name)
+
+	// Importing on the same server re-associates tasks with the new session.
+	migrated := newMigratableTestSession("new-session")
+	err = source.ImportSession(migrated, blob, func(tool mcp.Tool) (ToolHandlerFunc, bool) {
+		return handler, tool.Name == "session_tool"
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "alice", migrated.Subject())
+	assert.Equal(t, "agent", migrated.GetClientInfo().Name)
+	assert.Equal(t, mcp.LoggingLevelDebug, migrated.GetLogLevel())
+	assert.True(t, migrated.IsSubscribedToResource("file:///a"))
+	assert.True(t, migrated.IsSubscribedToResource("file:///b"))
+	tools := migrated.GetSessionTools()
+	require.Contains(t, tools, "session_tool")
+	assert.NotNil(t, tools["session_tool"].Handler)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, source.SessionLabels("new-session"))
+	cached, ok := source.cachedRoots(migrated)
+	require.True(t, ok)
+	assert.Equal(t, roots, cached)
+
+	entry, err := source.getTaskEntry(source.WithContext(context.Background(), migrated), "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, "new-session", entry.sessionID)
+}
+
+func TestMCPServer_ExportImportSession_ResourceSubscriptions(t *testing.T) {
+	source := NewMCPServer("source", "1.0.0", WithResourceCapabilities(true, false))
+	old := &mockSession{sessionID: "old-session"}
+	require.NoError(t, source.RegisterSession(context.Background(), old))
+	response := source.HandleMessage(source.WithContext(context.Background(), old),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file:///a"}}`))
+	require.IsType(t, mcp.JSONRPCResponse{}, response)
+
+	blob, err := source.ExportSession("old-session")
+	require.NoError(t, err)
+	var snapshot SessionSnapshot
+	require.NoError(t, json.Unmarshal(blob, &snapshot))
+	assert.Equal(t, []string{"file:///a"}, snapshot.Subscriptions)
+
+	// The target server notifies the imported session of updates.
+	target := NewMCPServer("target", "1.0.0", WithResourceCapabilities(true, false))
+	migrated := fakeSession{sessionID: "new-session", notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+	require.NoError(t, target.ImportSession(migrated, blob, nil))
+	require.NoError(t, target.RegisterSession(context.Background(), migrated))
+	target.NotifyResourceUpdated("file:///a")
+	select {
+	case notification := <-migrated.notificationChannel:
+		assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+	default:
+		t.Fatal("the imported session was not notified")
+	}
+}
+
+func TestMCPServer_ExportSession_NotFound(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0")
+	_, err := server.ExportSession("missing")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func TestMCPServer_ImportSession_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		blob    string
+		resolve SessionToolResolver
+		wantErr error
+	}{
+		{
+			name:    "newer version",
+			blob:    `{"version":99,"sessionId":"s"}`,
+			wantErr: ErrUnsupportedSnapshotVersion,
+		},
+		{
+			name:    "unresolved tool",
+			blob:    `{"version":1,"sessionId":"s","logLevel":"debug","tools":[{"name":"gone","inputSchema":{"type":"object"}}]}`,
+			resolve: func(mcp.Tool) (ToolHandlerFunc, bool) { return nil, false },
+			wantErr: ErrUnresolvedSessionTool,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test", "1.0.0")
+			session := newMigratableTestSession("target")
+			err := server.ImportSession(session, []byte(tt.blob), tt.resolve)
+			require.ErrorIs(t, err, tt.wantErr)
+			// A failed import must not partially apply state.
+			assert.Equal(t, mcp.LoggingLevelError, session.GetLogLevel())
+			assert.Empty(t, session.GetSessionTools())
+		})
+	}
+}
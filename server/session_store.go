@@ -0,0 +1,585 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionStore shares streamable HTTP session state between server replicas.
+// It validates session IDs issued by any replica and routes messages to the
+// replica holding the session's listening (GET) stream, so notifications,
+// sampling/elicitation/roots requests and their responses reach the right
+// place regardless of which replica the load balancer picked for a request.
+//
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Create records a new live session.
+	Create(ctx context.Context, sessionID string) error
+	// Validate reports whether sessionID is known, and whether it has been
+	// terminated. Unknown IDs return an error.
+	Validate(ctx context.Context, sessionID string) (isTerminated bool, err error)
+	// Terminate marks sessionID as terminated.
+	Terminate(ctx context.Context, sessionID string) error
+	// Publish sends message to the subscribers of sessionID. It returns an
+	// error wrapping ErrNoSessionSubscriber if nobody received it.
+	Publish(ctx context.Context, sessionID string, message []byte) error
+	// Subscribe returns a channel receiving messages published for sessionID
+	// until ctx is done, at which point the channel is closed.
+	Subscribe(ctx context.Context, sessionID string) (<-chan []byte, error)
+}
+
+// ErrUnknownSession is returned by SessionStore implementations when
+// validating a session ID that was never created.
+var ErrUnknownSession = errors.New("unknown session")
+
+// ErrNoSessionSubscriber is returned by SessionStore implementations when a
+// published message reached no subscriber, such as when no replica holds a
+// listening stream for the session.
+var ErrNoSessionSubscriber = errors.New("no subscriber for session")
+
+// WithSessionStore makes the server validate session IDs against store and
+// route notifications and client responses through it, which allows several
+// replicas to serve the same sessions. Passing nil uses an in-memory store,
+// which is only suitable for a single replica.
+//
+// It replaces any session id manager set with WithSessionIdManager,
+// WithSessionIdManagerResolver, WithStateLess or WithStateful; whichever is
+// applied last takes effect.
+func WithSessionStore(store SessionStore) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		if store == nil {
+			store = NewInMemorySessionStore()
+		}
+		s.sessionStore = store
+		s.sessionIdManagerResolver = NewDefaultSessionIdManagerResolver(&storeSessionIdManager{store: store})
+	}
+}
+
+// storeSessionIdManager adapts a SessionStore to the
+// FallibleSessionIdManager interface, so that a failing store rejects new
+// sessions instead of making the server stateless.
+type storeSessionIdManager struct {
+	store SessionStore
+}
+
+func (m *storeSessionIdManager) GenerateSessionID(ctx context.Context) (string, error) {
+	sessionID := idPrefix + uuid.New().String()
+	if err := m.store.Create(ctx, sessionID); err != nil {
+		return "", fmt.Errorf("failed to create session in store: %w", err)
+	}
+	return sessionID, nil
+}
+
+// Generate is only used by callers unaware of FallibleSessionIdManager. If
+// the store fails, the returned ID is unknown to it and fails validation.
+func (m *storeSessionIdManager) Generate() string {
+	sessionID, err := m.GenerateSessionID(context.Background())
+	if err != nil {
+		return idPrefix + uuid.New().String()
+	}
+	return sessionID
+}
+
+func (m *storeSessionIdManager) Validate(sessionID string) (isTerminated bool, err error) {
+	return m.store.Validate(context.Background(), sessionID)
+}
+
+func (m *storeSessionIdManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	return false, m.store.Terminate(context.Background(), sessionID)
+}
+
+// sessionStoreMessage is the envelope published through a SessionStore.
+type sessionStoreMessage struct {
+	Notification *mcp.JSONRPCNotification `json:"notification,omitempty"`
+	Request      *sessionStoreRequest     `json:"request,omitempty"`
+	Response     *sessionStoreResponse    `json:"response,omitempty"`
+}
+
+// sessionStoreRequest is a sampling, elicitation or roots request made on a
+// replica that does not hold the session's listening stream. The replica
+// holding it sends the request to the client and publishes the response
+// under ReplyTo.
+type sessionStoreRequest struct {
+	RequestID int64           `json:"requestId"`
+	ReplyTo   string          `json:"replyTo"`
+	Method    mcp.MCPMethod   `json:"method"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+type sessionStoreResponse struct {
+	RequestID int64           `json:"requestId"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// SendNotificationToSession delivers a notification to the session's
+// listening stream. If the stream is held by another replica, the
+// notification is published through the configured SessionStore, and the
+// error wraps ErrNoSessionSubscriber if no replica holds it.
+func (s *StreamableHTTPServer) SendNotificationToSession(ctx context.Context, sessionID string, notification mcp.JSONRPCNotification) error {
+	if value, ok := s.activeSessions.Load(sessionID); ok {
+		session := value.(*streamableHttpSession)
+		select {
+		case session.notificationChannel <- notification:
+			return nil
+		default:
+			return ErrNotificationChannelBlocked
+		}
+	}
+	if s.sessionStore == nil {
+		return ErrSessionNotFound
+	}
+	return s.publishToSessionStore(ctx, sessionID, sessionStoreMessage{Notification: &notification})
+}
+
+func (s *StreamableHTTPServer) publishToSessionStore(ctx context.Context, sessionID string, message sessionStoreMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session store message: %w", err)
+	}
+	return s.sessionStore.Publish(ctx, sessionID, data)
+}
+
+// forwardSessionStoreMessages delivers messages published for the session by
+// other replicas until ctx is done.
+func (s *StreamableHTTPServer) forwardSessionStoreMessages(ctx context.Context, session *streamableHttpSession) {
+	messages, err := s.sessionStore.Subscribe(ctx, session.sessionID)
+	if err != nil {
+		s.logger.Error("Failed to subscribe to session store", "session", session.sessionID, "err", err)
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				s.logger.Error("panic in session store subscriber", "panic", r)
+			}
+		}()
+		for data := range messages {
+			var message sessionStoreMessage
+			if err := json.Unmarshal(data, &message); err != nil {
+				s.logger.Error("Failed to decode session store message", "session", session.sessionID, "err", err)
+				continue
+			}
+			if message.Notification != nil {
+				select {
+				case session.notificationChannel <- *message.Notification:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if message.Request != nil {
+				go s.serveRoutedRequest(ctx, session, *message.Request)
+			}
+			if message.Response != nil {
+				s.deliverRoutedResponse(session, *message.Response)
+			}
+		}
+	}()
+}
+
+// deliverRoutedResponse hands a response published through the store to the
+// request of session waiting for it.
+func (s *StreamableHTTPServer) deliverRoutedResponse(session *streamableHttpSession, routed sessionStoreResponse) {
+	response := samplingResponseItem{requestID: routed.RequestID, result: routed.Result}
+	if routed.Error != "" {
+		response.err = errors.New(routed.Error)
+	}
+	if ch, ok := session.samplingRequests.Load(response.requestID); ok {
+		select {
+		case ch.(chan samplingResponseItem) <- response:
+		default:
+			s.logger.Error("Failed to deliver routed response", "session", session.sessionID, "request", response.requestID)
+		}
+	}
+}
+
+// serveRoutedRequest sends a request published by another replica to the
+// client over session's listening stream and publishes the response back.
+func (s *StreamableHTTPServer) serveRoutedRequest(ctx context.Context, session *streamableHttpSession, request sessionStoreRequest) {
+	var (
+		result any
+		err    error
+	)
+	switch request.Method {
+	case mcp.MethodSamplingCreateMessage:
+		forwarded := mcp.CreateMessageRequest{}
+		if err = json.Unmarshal(request.Params, &forwarded.CreateMessageParams); err == nil {
+			result, err = session.RequestSampling(ctx, forwarded)
+		}
+	case mcp.MethodElicitationCreate:
+		forwarded := mcp.ElicitationRequest{}
+		if err = json.Unmarshal(request.Params, &forwarded.Params); err == nil {
+			result, err = session.RequestElicitation(ctx, forwarded)
+		}
+	case mcp.MethodListRoots:
+		result, err = session.ListRoots(ctx, mcp.ListRootsRequest{})
+	default:
+		err = fmt.Errorf("unsupported routed request %s", request.Method)
+	}
+	response := &sessionStoreResponse{RequestID: request.RequestID}
+	if err == nil {
+		response.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	if err := s.publishToSessionStore(context.WithoutCancel(ctx), request.ReplyTo, sessionStoreMessage{Response: response}); err != nil {
+		s.logger.Error("Failed to publish routed response", "session", session.sessionID, "request", request.RequestID, "err", err)
+	}
+}
+
+// routeSessionRequests publishes the sampling, elicitation and roots
+// requests made on session, a POST-scoped session of a replica that does
+// not hold the session's listening stream, to the replica that does, until
+// ctx is done. Responses come back on an inbox of this request only.
+func (s *StreamableHTTPServer) routeSessionRequests(ctx context.Context, session *streamableHttpSession) {
+	var inbox string
+	route := func(requestID int64, method mcp.MCPMethod, params any, sent *atomic.Bool, response chan samplingResponseItem) {
+		err := s.publishRoutedRequest(ctx, session, &inbox, requestID, method, params)
+		if err != nil {
+			if errors.Is(err, ErrNoSessionSubscriber) {
+				err = undeliverable(fmt.Errorf("%w: %w", ErrNoClientStream, err))
+			}
+			response <- samplingResponseItem{requestID: requestID, err: err}
+			return
+		}
+		sent.Store(true)
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				s.logger.Error("panic in session store request router", "panic", r)
+			}
+		}()
+		for {
+			select {
+			case request := <-session.samplingRequestChan:
+				route(request.requestID, mcp.MethodSamplingCreateMessage, request.request.CreateMessageParams, request.sent, request.response)
+			case request := <-session.elicitationRequestChan:
+				route(request.requestID, mcp.MethodElicitationCreate, request.request.Params, request.sent, request.response)
+			case request := <-session.rootsRequestChan:
+				route(request.requestID, mcp.MethodListRoots, nil, request.sent, request.response)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// publishRoutedRequest publishes a request of session through the store,
+// subscribing to the inbox for its response first if needed.
+func (s *StreamableHTTPServer) publishRoutedRequest(ctx context.Context, session *streamableHttpSession, inbox *string, requestID int64, method mcp.MCPMethod, params any) error {
+	if *inbox == "" {
+		name := session.sessionID + ":reply:" + uuid.New().String()
+		responses, err := s.sessionStore.Subscribe(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to session store: %w", err)
+		}
+		*inbox = name
+		go func() {
+			for data := range responses {
+				var message sessionStoreMessage
+				if err := json.Unmarshal(data, &message); err != nil || message.Response == nil {
+					continue
+				}
+				s.deliverRoutedResponse(session, *message.Response)
+			}
+		}()
+	}
+	request := &sessionStoreRequest{RequestID: requestID, ReplyTo: *inbox, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal routed request: %w", err)
+		}
+		request.Params = data
+	}
+	return s.publishToSessionStore(ctx, session.sessionID, sessionStoreMessage{Request: request})
+}
+
+// InMemorySessionStore is a SessionStore for a single server process.
+type InMemorySessionStore struct {
+	mu          sync.Mutex
+	sessions    map[string]bool   // sessionID -> terminated
+	subjects    map[string]string // sessionID -> subject the session is bound to
+	subscribers map[string][]chan []byte
+}
+
+// NewInMemorySessionStore creates an empty InMemorySessionStore.
+func NewInMemorySessionStore() *InMemorySessionStore {
+	return &InMemorySessionStore{
+		sessions:    make(map[string]bool),
+		subjects:    make(map[string]string),
+		subscribers: make(map[string][]chan []byte),
+	}
+}
+
+// Create records a new live session.
+func (s *InMemorySessionStore) Create(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = false
+	return nil
+}
+
+// Validate reports whether sessionID is known and whether it is terminated.
+func (s *InMemorySessionStore) Validate(_ context.Context, sessionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	terminated, ok := s.sessions[sessionID]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnknownSession, sessionID)
+	}
+	return terminated, nil
+}
+
+// Terminate marks sessionID as terminated. Unknown IDs are ignored.
+func (s *InMemorySessionStore) Terminate(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[sessionID]; ok {
+		s.sessions[sessionID] = true
+	}
+	return nil
+}
+
+// BindSubject binds sessionID to subject.
+func (s *InMemorySessionStore) BindSubject(_ context.Context, sessionID, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects[sessionID] = subject
+	return nil
+}
+
+// Subject returns the subject sessionID is bound to.
+func (s *InMemorySessionStore) Subject(_ context.Context, sessionID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subject, ok := s.subjects[sessionID]
+	return subject, ok, nil
+}
+
+var (
+	_ SessionSubjectStore = (*InMemorySessionStore)(nil)
+	_ SessionSubjectStore = (*RedisSessionStore)(nil)
+)
+
+// Publish delivers message to current subscribers of sessionID. Messages
+// for subscribers that are not keeping up are dropped; if no subscriber got
+// the message, the error wraps ErrNoSessionSubscriber.
+func (s *InMemorySessionStore) Publish(_ context.Context, sessionID string, message []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivered := 0
+	for _, ch := range s.subscribers[sessionID] {
+		select {
+		case ch <- message:
+			delivered++
+		default:
+		}
+	}
+	if delivered == 0 {
+		return fmt.Errorf("%w: %s", ErrNoSessionSubscriber, sessionID)
+	}
+	return nil
+}
+
+// Subscribe returns a channel receiving messages for sessionID until ctx is
+// done.
+func (s *InMemorySessionStore) Subscribe(ctx context.Context, sessionID string) (<-chan []byte, error) {
+	ch := make(chan []byte, 16)
+	s.mu.Lock()
+	s.subscribers[sessionID] = append(s.subscribers[sessionID], ch)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		subs := s.subscribers[sessionID]
+		for i, sub := range subs {
+			if sub == ch {
+				subs = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(subs) == 0 {
+			delete(s.subscribers, sessionID)
+		} else {
+			s.subscribers[sessionID] = subs
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// RedisClient is the subset of a Redis client used by RedisSessionStore. It
+// keeps this module free of a Redis dependency; adapting a client such as
+// github.com/redis/go-redis takes a few lines:
+//
+//	type goRedis struct{ *redis.Client }
+//
+//	func (c goRedis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+//		return c.Client.Set(ctx, key, value, ttl).Err()
+//	}
+//	func (c goRedis) Get(ctx context.Context, key string) (string, bool, error) {
+//		v, err := c.Client.Get(ctx, key).Result()
+//		if errors.Is(err, redis.Nil) {
+//			return "", false, nil
+//		}
+//		return v, err == nil, err
+//	}
+//	func (c goRedis) Publish(ctx context.Context, channel string, message []byte) (int64, error) {
+//		return c.Client.Publish(ctx, channel, message).Result()
+//	}
+//	func (c goRedis) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+//		sub := c.Client.Subscribe(ctx, channel)
+//		out := make(chan []byte)
+//		go func() {
+//			defer close(out)
+//			defer sub.Close()
+//			for msg := range sub.Channel() {
+//				select {
+//				case out <- []byte(msg.Payload):
+//				case <-ctx.Done():
+//					return
+//				}
+//			}
+//		}()
+//		go func() { <-ctx.Done(); sub.Close() }()
+//		return out, nil
+//	}
+type RedisClient interface {
+	// Set stores value under key with the given expiration (0 means none).
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// Get returns the value under key and whether it exists.
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	// Publish publishes message on channel and returns the number of
+	// subscribers that received it.
+	Publish(ctx context.Context, channel string, message []byte) (receivers int64, err error)
+	// Subscribe returns messages published on channel until ctx is done,
+	// at which point the returned channel must be closed.
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+const (
+	redisSessionActive     = "active"
+	redisSessionTerminated = "terminated"
+	// redisSessionBound prefixes the subject in the record of an active
+	// session bound to one.
+	redisSessionBound = "active:"
+)
+
+// RedisSessionStore is a SessionStore backed by Redis keys and pub/sub, for
+// deployments running several replicas behind a load balancer.
+type RedisSessionStore struct {
+	client    RedisClient
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// RedisSessionStoreOption configures a RedisSessionStore.
+type RedisSessionStoreOption func(*RedisSessionStore)
+
+// WithRedisKeyPrefix sets the prefix for keys and channels. Defaults to
+// "mcp:session:".
+func WithRedisKeyPrefix(prefix string) RedisSessionStoreOption {
+	return func(s *RedisSessionStore) {
+		s.keyPrefix = prefix
+	}
+}
+
+// WithRedisSessionTTL expires session records after ttl without activity.
+// Every validation, which happens on each request of the session, restarts
+// the countdown. Defaults to 24h; zero keeps them forever.
+func WithRedisSessionTTL(ttl time.Duration) RedisSessionStoreOption {
+	return func(s *RedisSessionStore) {
+		s.ttl = ttl
+	}
+}
+
+// NewRedisSessionStore creates a RedisSessionStore using client.
+func NewRedisSessionStore(client RedisClient, opts ...RedisSessionStoreOption) *RedisSessionStore {
+	s := &RedisSessionStore{
+		client:    client,
+		keyPrefix: "mcp:session:",
+		ttl:       24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create records a new live session.
+func (s *RedisSessionStore) Create(ctx context.Context, sessionID string) error {
+	return s.client.Set(ctx, s.keyPrefix+sessionID, redisSessionActive, s.ttl)
+}
+
+// Validate reports whether sessionID is known and whether it is terminated.
+func (s *RedisSessionStore) Validate(ctx context.Context, sessionID string) (bool, error) {
+	value, ok, err := s.client.Get(ctx, s.keyPrefix+sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up session: %w", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnknownSession, sessionID)
+	}
+	if s.ttl > 0 {
+		// Keep sessions in use alive. Failing to do so only shortens the
+		// session's life, so the request may proceed.
+		_ = s.client.Set(ctx, s.keyPrefix+sessionID, value, s.ttl)
+	}
+	return value == redisSessionTerminated, nil
+}
+
+// Terminate marks sessionID as terminated.
+func (s *RedisSessionStore) Terminate(ctx context.Context, sessionID string) error {
+	return s.client.Set(ctx, s.keyPrefix+sessionID, redisSessionTerminated, s.ttl)
+}
+
+// BindSubject binds sessionID to subject, storing it in the session's record.
+func (s *RedisSessionStore) BindSubject(ctx context.Context, sessionID, subject string) error {
+	return s.client.Set(ctx, s.keyPrefix+sessionID, redisSessionBound+subject, s.ttl)
+}
+
+// Subject returns the subject sessionID is bound to.
+func (s *RedisSessionStore) Subject(ctx context.Context, sessionID string) (string, bool, error) {
+	value, ok, err := s.client.Get(ctx, s.keyPrefix+sessionID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up session: %w", err)
+	}
+	if !ok {
+		return "", false, nil
+	}
+	subject, bound := strings.CutPrefix(value, redisSessionBound)
+	return subject, bound, nil
+}
+
+// Publish publishes message on the session's channel. If no replica is
+// subscribed, the error wraps ErrNoSessionSubscriber.
+func (s *RedisSessionStore) Publish(ctx context.Context, sessionID string, message []byte) error {
+	receivers, err := s.client.Publish(ctx, s.keyPrefix+"events:"+sessionID, message)
+	if err != nil {
+		return err
+	}
+	if receivers == 0 {
+		return fmt.Errorf("%w: %s", ErrNoSessionSubscriber, sessionID)
+	}
+	return nil
+}
+
+// Subscribe subscribes to the session's channel.
+func (s *RedisSessionStore) Subscribe(ctx context.Context, sessionID string) (<-chan []byte, error) {
+	return s.client.Subscribe(ctx, s.keyPrefix+"events:"+sessionID)
+}
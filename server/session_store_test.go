@@ -0,0 +1,397 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedisClient implements RedisClient on top of an InMemorySessionStore
+// for pub/sub and a map for keys.
+type fakeRedisClient struct {
+	mu     sync.Mutex
+	values map[string]string
+	pubsub *InMemorySessionStore
+}
+
+func newFakeRedisClient() *fakeRedisClient {
+	return &fakeRedisClient{values: map[string]string{}, pubsub: NewInMemorySessionStore()}
+}
+
+func (c *fakeRedisClient) Set(_ context.Context, key, value string, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *fakeRedisClient) Get(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	return value, ok, nil
+}
+
+func (c *fakeRedisClient) Publish(ctx context.Context, channel string, message []byte) (int64, error) {
+	if err := c.pubsub.Publish(ctx, channel, message); err != nil {
+		if errors.Is(err, ErrNoSessionSubscriber) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (c *fakeRedisClient) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	return c.pubsub.Subscribe(ctx, channel)
+}
+
+func TestSessionStores(t *testing.T) {
+	tests := []struct {
+		name  string
+		store SessionStore
+	}{
+		{name: "in-memory", store: NewInMemorySessionStore()},
+		{name: "redis", store: NewRedisSessionStore(newFakeRedisClient())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_, err := tt.store.Validate(ctx, "missing")
+			assert.ErrorIs(t, err, ErrUnknownSession)
+
+			require.NoError(t, tt.store.Create(ctx, "s1"))
+			isTerminated, err := tt.store.Validate(ctx, "s1")
+			require.NoError(t, err)
+			assert.False(t, isTerminated)
+
+			subjects := tt.store.(SessionSubjectStore)
+			_, bound, err := subjects.Subject(ctx, "s1")
+			require.NoError(t, err)
+			assert.False(t, bound)
+			require.NoError(t, subjects.BindSubject(ctx, "s1", "alice"))
+			subject, bound, err := subjects.Subject(ctx, "s1")
+			require.NoError(t, err)
+			assert.True(t, bound)
+			assert.Equal(t, "alice", subject)
+			isTerminated, err = tt.store.Validate(ctx, "s1")
+			require.NoError(t, err)
+			assert.False(t, isTerminated, "binding a subject keeps the session active")
+
+			subCtx, cancel := context.WithCancel(ctx)
+			messages, err := tt.store.Subscribe(subCtx, "s1")
+			require.NoError(t, err)
+			require.NoError(t, tt.store.Publish(ctx, "s1", []byte("hello")))
+			select {
+			case msg := <-messages:
+				assert.Equal(t, "hello", string(msg))
+			case <-time.After(time.Second):
+				t.Fatal("message not delivered")
+			}
+			cancel()
+			for range messages {
+			}
+
+			require.NoError(t, tt.store.Terminate(ctx, "s1"))
+			isTerminated, err = tt.store.Validate(ctx, "s1")
+			require.NoError(t, err)
+			assert.True(t, isTerminated)
+		})
+	}
+}
+
+func TestStreamableHTTP_SessionStoreAcrossReplicas(t *testing.T) {
+	store := NewInMemorySessionStore()
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+
+	replicaA := NewStreamableHTTPServer(mcpServer, WithSessionStore(store))
+	replicaB := NewStreamableHTTPServer(NewMCPServer("test-mcp-server", "1.0"), WithSessionStore(store))
+	serverA := httptest.NewServer(replicaA)
+	defer serverA.Close()
+
+	// Initialize on replica A.
+	resp, err := postJSON(serverA.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	// Replica B accepts the session ID issued by A.
+	_, err = replicaB.resolveSessionIdManager(nil).Validate(sessionID)
+	require.NoError(t, err)
+
+	// Open a listening stream on replica A.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverA.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(HeaderKeySessionID, sessionID)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	// A notification sent from replica B reaches the stream on replica A.
+	notification := mcp.JSONRPCNotification{
+		JSONRPC:      mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{Method: "notifications/test"},
+	}
+	require.Eventually(t, func() bool {
+		_, ok := replicaA.activeSessions.Load(sessionID)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, replicaB.SendNotificationToSession(context.Background(), sessionID, notification))
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stream.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	for {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "stream closed before notification arrived")
+			if strings.Contains(line, "notifications/test") {
+				return
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("notification not routed to replica A")
+		}
+	}
+}
+
+func TestStreamableHTTP_SessionStoreSharesSubjects(t *testing.T) {
+	store := NewInMemorySessionStore()
+	authenticator := WithAuthenticator(APIKeyAuthenticator("X-API-Key", map[string]Identity{
+		"key-alice": {Subject: "alice"},
+		"key-bob":   {Subject: "bob"},
+	}))
+	serverA := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0", authenticator), WithSessionStore(store)))
+	defer serverA.Close()
+	serverB := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0", authenticator), WithSessionStore(store)))
+	defer serverB.Close()
+
+	post := func(url, key, sessionID string, body any) *http.Response {
+		t.Helper()
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(string(data)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		if sessionID != "" {
+			req.Header.Set(HeaderKeySessionID, sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := post(serverA.URL, "key-alice", "", initRequest)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	ping := map[string]any{"jsonrpc": "2.0", "id": 2, "method": "ping"}
+	resp = post(serverB.URL, "key-bob", sessionID, ping)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "the replica that did not create the session rejects other subjects")
+	resp = post(serverB.URL, "key-alice", sessionID, ping)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestStreamableHTTP_SendNotificationToSession_WithoutStore(t *testing.T) {
+	server := NewStreamableHTTPServer(NewMCPServer("test", "1.0"))
+	err := server.SendNotificationToSession(context.Background(), "missing", mcp.JSONRPCNotification{})
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+// unavailableSessionStore fails to create sessions, like a store whose
+// backend is down.
+type unavailableSessionStore struct {
+	*InMemorySessionStore
+}
+
+func (unavailableSessionStore) Create(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func TestStreamableHTTP_SessionStoreUnavailable(t *testing.T) {
+	server := httptest.NewServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0"),
+		WithSessionStore(unavailableSessionStore{NewInMemorySessionStore()})))
+	defer server.Close()
+
+	// The server must not fall back to stateless mode.
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(HeaderKeySessionID))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestSessionStores_PublishWithoutSubscriber(t *testing.T) {
+	tests := []struct {
+		name  string
+		store SessionStore
+	}{
+		{name: "in-memory", store: NewInMemorySessionStore()},
+		{name: "redis", store: NewRedisSessionStore(newFakeRedisClient())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.store.Publish(context.Background(), "s1", []byte("hello"))
+			assert.ErrorIs(t, err, ErrNoSessionSubscriber)
+		})
+	}
+}
+
+func TestStreamableHTTP_SessionStoreRoutesSampling(t *testing.T) {
+	store := NewInMemorySessionStore()
+
+	mcpA := NewMCPServer("test-mcp-server", "1.0")
+	mcpA.EnableSampling()
+	mcpB := NewMCPServer("test-mcp-server", "1.0")
+	mcpB.EnableSampling()
+	mcpB.AddTool(mcp.NewTool("ask"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := mcpB.RequestSampling(ctx, mcp.CreateMessageRequest{
+			CreateMessageParams: mcp.CreateMessageParams{
+				Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("hi")}},
+				MaxTokens: 10,
+			},
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(result.Content.(mcp.TextContent).Text), nil
+	})
+
+	replicaA := NewStreamableHTTPServer(mcpA, WithSessionStore(store))
+	serverA := httptest.NewServer(replicaA)
+	defer serverA.Close()
+	serverB := httptest.NewServer(NewStreamableHTTPServer(mcpB, WithSessionStore(store)))
+	defer serverB.Close()
+
+	resp, err := postJSON(serverA.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverA.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(HeaderKeySessionID, sessionID)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Eventually(t, func() bool {
+		_, ok := replicaA.activeSessions.Load(sessionID)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	// The tool runs on replica B, which has no listening stream.
+	toolResult := make(chan string, 1)
+	go func() {
+		resp, err := postSessionJSON(serverB.URL, sessionID, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      2,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "ask"},
+		})
+		if err != nil {
+			toolResult <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		toolResult <- string(body)
+	}()
+
+	// The request reaches the client over replica A's stream.
+	scanner := bufio.NewScanner(stream.Body)
+	var request mcp.JSONRPCRequest
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if ok && strings.Contains(data, string(mcp.MethodSamplingCreateMessage)) {
+			require.NoError(t, json.Unmarshal([]byte(data), &request))
+			break
+		}
+	}
+	require.Equal(t, mcp.MethodSamplingCreateMessage, mcp.MCPMethod(request.Method))
+
+	// The client answers on replica B.
+	resp, err = postSessionJSON(serverB.URL, sessionID, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result": map[string]any{
+			"role":    "assistant",
+			"content": map[string]any{"type": "text", "text": "hello from the client"},
+			"model":   "test",
+		},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	select {
+	case body := <-toolResult:
+		assert.Contains(t, body, "hello from the client")
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool did not receive the sampling result")
+	}
+}
+
+func TestStreamableHTTP_SessionStoreRoutedSamplingWithoutStream(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.EnableSampling()
+	mcpServer.AddTool(mcp.NewTool("ask"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, err := mcpServer.RequestSampling(ctx, mcp.CreateMessageRequest{})
+		if !errors.Is(err, ErrNoClientStream) || !errors.Is(err, ErrRequestUndeliverable) {
+			return mcp.NewToolResultError(fmt.Sprintf("unexpected error: %v", err)), nil
+		}
+		return mcp.NewToolResultText("undeliverable"), nil
+	})
+	store := NewInMemorySessionStore()
+	require.NoError(t, store.Create(context.Background(), "s1"))
+	server := httptest.NewServer(NewStreamableHTTPServer(mcpServer, WithSessionStore(store)))
+	defer server.Close()
+
+	resp, err := postSessionJSON(server.URL, "s1", map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "ask"},
+	})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"text":"undeliverable"`)
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ShadowUpstream receives duplicated tool calls. *client.Client satisfies
+// this interface, so a shadow can be any MCP server reachable by a client.
+type ShadowUpstream interface {
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// ShadowComparison describes the outcome of one shadowed tool call.
+type ShadowComparison struct {
+	Request       mcp.CallToolRequest
+	Primary       *mcp.CallToolResult
+	PrimaryErr    error
+	Shadow        *mcp.CallToolResult
+	ShadowErr     error
+	ShadowLatency time.Duration
+	// Diverged reports whether the shadow result differs from the primary
+	// one according to the configured comparator.
+	Diverged bool
+}
+
+// ShadowStats is a snapshot of ShadowMiddleware counters.
+type ShadowStats struct {
+	// Shadowed is the number of calls duplicated to the shadow upstream.
+	Shadowed int64
+	// Matched is the number of shadowed calls whose results agreed.
+	Matched int64
+	// Diverged is the number of shadowed calls whose results differed,
+	// including calls where only one side returned an error.
+	Diverged int64
+	// ShadowErrors is the number of shadowed calls that failed on the shadow.
+	ShadowErrors int64
+}
+
+// ShadowMiddleware duplicates a sample of tool calls to a shadow upstream,
+// typically a new version of a server under migration, and compares the
+// results asynchronously. The primary response is returned unchanged and is
+// never delayed by the shadow.
+type ShadowMiddleware struct {
+	upstream   ShadowUpstream
+	percentage float64
+	timeout    time.Duration
+	compare    func(primary, shadow *mcp.CallToolResult) bool
+	report     func(ShadowComparison)
+	sample     func() float64
+
+	shadowed     atomic.Int64
+	matched      atomic.Int64
+	diverged     atomic.Int64
+	shadowErrors atomic.Int64
+}
+
+// ShadowOption configures a ShadowMiddleware.
+type ShadowOption func(*ShadowMiddleware)
+
+// WithShadowPercentage sets the share of tool calls, from 0 to 100, that are
+// duplicated to the shadow upstream. The default is 100.
+func WithShadowPercentage(percentage float64) ShadowOption {
+	return func(m *ShadowMiddleware) {
+		m.percentage = min(max(percentage, 0), 100)
+	}
+}
+
+// WithShadowTimeout bounds each shadow call. The default is 30 seconds.
+func WithShadowTimeout(timeout time.Duration) ShadowOption {
+	return func(m *ShadowMiddleware) {
+		m.timeout = timeout
+	}
+}
+
+// WithShadowComparator sets the function deciding whether a shadow result
+// matches the primary one. The default compares the JSON encoding of the
+// content, structured content and error flag.
+func WithShadowComparator(compare func(primary, shadow *mcp.CallToolResult) bool) ShadowOption {
+	return func(m *ShadowMiddleware) {
+		m.compare = compare
+	}
+}
+
+// WithShadowReporter registers a callback invoked after every shadowed call,
+// for example to log divergences or export metrics. It runs on the shadow
+// goroutine.
+func WithShadowReporter(report func(ShadowComparison)) ShadowOption {
+	return func(m *ShadowMiddleware) {
+		m.report = report
+	}
+}
+
+// NewShadowMiddleware creates a ShadowMiddleware sending duplicated calls to
+// upstream. Install it with WithToolHandlerMiddleware(m.Middleware).
+func NewShadowMiddleware(upstream ShadowUpstream, opts ...ShadowOption) *ShadowMiddleware {
+	m := &ShadowMiddleware{
+		upstream:   upstream,
+		percentage: 100,
+		timeout:    30 * time.Second,
+		compare:    defaultShadowCompare,
+		sample:     func() float64 { return rand.Float64() * 100 },
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Middleware is the ToolHandlerMiddleware performing the shadowing.
+func (m *ShadowMiddleware) Middleware(next ToolHandlerFunc) ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if m.percentage > 0 && m.sample() < m.percentage {
+			m.shadowed.Add(1)
+			// Detach from the request context so the shadow call is not
+			// canceled when the primary response is written.
+			go m.shadow(context.WithoutCancel(ctx), request, result, err)
+		}
+		return result, err
+	}
+}
+
+// Stats returns a snapshot of the shadowing counters.
+func (m *ShadowMiddleware) Stats() ShadowStats {
+	return ShadowStats{
+		Shadowed:     m.shadowed.Load(),
+		Matched:      m.matched.Load(),
+		Diverged:     m.diverged.Load(),
+		ShadowErrors: m.shadowErrors.Load(),
+	}
+}
+
+func (m *ShadowMiddleware) shadow(ctx context.Context, request mcp.CallToolRequest, primary *mcp.CallToolResult, primaryErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.shadowErrors.Add(1)
+		}
+	}()
+
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	shadow, shadowErr := m.upstream.CallTool(ctx, request)
+	comparison := ShadowComparison{
+		Request:       request,
+		Primary:       primary,
+		PrimaryErr:    primaryErr,
+		Shadow:        shadow,
+		ShadowErr:     shadowErr,
+		ShadowLatency: time.Since(start),
+	}
+
+	switch {
+	case shadowErr != nil:
+		m.shadowErrors.Add(1)
+		comparison.Diverged = primaryErr == nil
+	case primaryErr != nil:
+		comparison.Diverged = true
+	default:
+		comparison.Diverged = !m.compare(primary, shadow)
+	}
+
+	if comparison.Diverged {
+		m.diverged.Add(1)
+	} else {
+		m.matched.Add(1)
+	}
+	if m.report != nil {
+		m.report(comparison)
+	}
+}
+
+func defaultShadowCompare(primary, shadow *mcp.CallToolResult) bool {
+	if primary == nil || shadow == nil {
+		return primary == shadow
+	}
+	// Compare the wire form so results decoded by a client compare equal to
+	// results built in-process.
+	encode := func(r *mcp.CallToolResult) ([]byte, error) {
+		return json.Marshal(struct {
+			Content           []mcp.Content `json:"content"`
+			StructuredContent any           `json:"structuredContent"`
+			IsError           bool          `json:"isError"`
+		}{r.Content, r.StructuredContent, r.IsError})
+	}
+	a, errA := encode(primary)
+	b, errB := encode(shadow)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
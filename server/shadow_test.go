@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shadowUpstreamFunc func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+func (f shadowUpstreamFunc) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return f(ctx, request)
+}
+
+func TestShadowMiddleware(t *testing.T) {
+	primary := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("v1"), nil
+	}
+
+	tests := []struct {
+		name       string
+		upstream   shadowUpstreamFunc
+		percentage float64
+		want       ShadowStats
+	}{
+		{
+			name: "matching results",
+			upstream: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("v1"), nil
+			},
+			percentage: 100,
+			want:       ShadowStats{Shadowed: 1, Matched: 1},
+		},
+		{
+			name: "diverging results",
+			upstream: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("v2"), nil
+			},
+			percentage: 100,
+			want:       ShadowStats{Shadowed: 1, Diverged: 1},
+		},
+		{
+			name: "shadow error",
+			upstream: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return nil, errors.New("boom")
+			},
+			percentage: 100,
+			want:       ShadowStats{Shadowed: 1, Diverged: 1, ShadowErrors: 1},
+		},
+		{
+			name: "not sampled",
+			upstream: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				t.Error("shadow must not be called")
+				return nil, nil
+			},
+			percentage: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported := make(chan ShadowComparison, 1)
+			m := NewShadowMiddleware(tt.upstream,
+				WithShadowPercentage(tt.percentage),
+				WithShadowReporter(func(c ShadowComparison) { reported <- c }),
+			)
+
+			result, err := m.Middleware(primary)(context.Background(), mcp.CallToolRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, "v1", result.Content[0].(mcp.TextContent).Text)
+
+			if tt.want.Shadowed > 0 {
+				select {
+				case c := <-reported:
+					assert.Equal(t, tt.want.Diverged > 0, c.Diverged)
+				case <-time.After(time.Second):
+					t.Fatal("shadow comparison not reported")
+				}
+			}
+			assert.Equal(t, tt.want, m.Stats())
+		})
+	}
+}
+
+func TestShadowMiddleware_DoesNotInheritCancellation(t *testing.T) {
+	shadowCtxErr := make(chan error, 1)
+	m := NewShadowMiddleware(shadowUpstreamFunc(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		shadowCtxErr <- ctx.Err()
+		return mcp.NewToolResultText("ok"), nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	next := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cancel()
+		return mcp.NewToolResultText("ok"), nil
+	}
+	_, err := m.Middleware(next)(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+
+	select {
+	case err := <-shadowCtxErr:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("shadow call not made")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -16,12 +15,15 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/mark3labs/mcp-go/internal/bandwidth"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // sseSession represents an active SSE connection.
 type sseSession struct {
-	clientInfoStore // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	clientInfoStore      // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	extensionStore       // provides Extension, Extensions and SetExtensions via method promotion
+	connectionStateStore // provides ConnectionState, OnConnectionStateChange and SetConnectionState via method promotion
 
 	done                chan struct{}
 	doneOnce            sync.Once
@@ -34,6 +36,10 @@ type sseSession struct {
 	tools               sync.Map // stores session-specific tools
 	resources           sync.Map // stores session-specific resources
 	resourceTemplates   sync.Map // stores session-specific resource templates
+	readLimiter         *bandwidth.Limiter
+	// subject is the Identity.Subject of the request that opened the
+	// session, if WithAuthenticator is set.
+	subject string
 }
 
 // closeDone safely closes the session's done channel exactly once,
@@ -161,6 +167,8 @@ var (
 	_ SessionWithResourceTemplates = (*sseSession)(nil)
 	_ SessionWithLogging           = (*sseSession)(nil)
 	_ SessionWithClientInfo        = (*sseSession)(nil)
+	_ SessionWithExtensions        = (*sseSession)(nil)
+	_ SessionWithConnectionState   = (*sseSession)(nil)
 )
 
 // SSEServer implements a Server-Sent Events (SSE) based MCP server.
@@ -194,10 +202,15 @@ type SSEServer struct {
 	// WithSSECORS.
 	corsConfig *CORSConfig
 
-	// disableLocalhostProtection, when true, turns off the automatic DNS
-	// rebinding protection applied to requests arriving over loopback
-	// connections. See WithSSEDisableLocalhostProtection.
-	disableLocalhostProtection bool
+	// origins validates the Host and Origin headers of requests. See
+	// WithSSEDisableLocalhostProtection, WithSSEAllowedHosts and
+	// WithSSEAllowedOrigins.
+	origins originGuard
+
+	// readLimit and writeLimit cap the bytes per second of each session.
+	// See WithSSEBandwidthLimit.
+	readLimit  int
+	writeLimit int
 
 	mu sync.RWMutex
 }
@@ -367,9 +380,12 @@ func WithSSECORS(opts ...CORSOption) SSEOption {
 //
 // By default, requests arriving over a loopback connection (127.0.0.1,
 // [::1]) whose Host header is not a localhost value are rejected with 403
-// Forbidden. This protects local MCP servers against DNS rebinding attacks,
-// where a malicious website rebinds its own domain to 127.0.0.1 to make a
-// victim's browser issue requests against a local server. The protection
+// Forbidden, as are requests carrying an Origin header that is neither a
+// localhost origin nor allowed by the CORS configuration. This protects
+// local MCP servers against DNS rebinding attacks, where a malicious website
+// rebinds its own domain to 127.0.0.1 to make a victim's browser issue
+// requests against a local server, and against cross-site requests from
+// other websites. The protection
 // applies regardless of whether the server listens on localhost specifically
 // or on 0.0.0.0, and never affects requests arriving via non-loopback
 // addresses.
@@ -377,12 +393,32 @@ func WithSSECORS(opts ...CORSOption) SSEOption {
 // Disable it only if you understand the security implications, for example
 // when a reverse proxy on the same host forwards requests via localhost
 // while preserving the original Host header. In that case, prefer
-// configuring the proxy to rewrite the Host header to localhost instead.
+// configuring the proxy to rewrite the Host header to localhost, or listing
+// the public host names with WithSSEAllowedHosts. Allow lists set with
+// WithSSEAllowedHosts and WithSSEAllowedOrigins are enforced even when the
+// protection is disabled.
 //
 // See https://modelcontextprotocol.io/specification/2025-11-25/basic/security_best_practices#local-mcp-server-compromise
 func WithSSEDisableLocalhostProtection(disable bool) SSEOption {
 	return func(s *SSEServer) {
-		s.disableLocalhostProtection = disable
+		s.origins.disableLocalhostProtection = disable
+	}
+}
+
+// WithSSEAllowedHosts restricts the Host header of requests to hosts. See
+// WithAllowedHosts.
+func WithSSEAllowedHosts(hosts ...string) SSEOption {
+	return func(s *SSEServer) {
+		s.origins.allowedHosts = append(s.origins.allowedHosts[:0:0], hosts...)
+	}
+}
+
+// WithSSEAllowedOrigins restricts the Origin header of requests to origins.
+// Origins allowed by WithSSECORS are accepted as well. See
+// WithAllowedOrigins.
+func WithSSEAllowedOrigins(origins ...string) SSEOption {
+	return func(s *SSEServer) {
+		s.origins.allowedOrigins = append(s.origins.allowedOrigins[:0:0], origins...)
 	}
 }
 
@@ -403,6 +439,18 @@ func WithSessionIDGenerator(fn SessionIDGenFunc) SSEOption {
 	}
 }
 
+// WithSSEBandwidthLimit caps the bytes per second each session may send to
+// the server in messages and receive on its event stream, for example when
+// MCP shares a constrained link with other traffic. Events beyond the limit
+// wait, so a tool streaming a large output slows down its own session only.
+// Zero leaves a direction unlimited.
+func WithSSEBandwidthLimit(readBytesPerSecond, writeBytesPerSecond int) SSEOption {
+	return func(s *SSEServer) {
+		s.readLimit = readBytesPerSecond
+		s.writeLimit = writeBytesPerSecond
+	}
+}
+
 // NewSSEServer creates a new SSE server instance with the given MCP server and options.
 func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 	s := &SSEServer{
@@ -413,6 +461,9 @@ func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 		keepAlive:                    false,
 		keepAliveInterval:            10 * time.Second,
 		sessionIDGenFunc: func(ctx context.Context, r *http.Request) (string, error) {
+			if server != nil {
+				return server.newID(), nil
+			}
 			return uuid.New().String(), nil
 		},
 	}
@@ -493,6 +544,11 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	ctx, ok := s.server.authenticate(r.Context(), newHTTPResponseWriterAdapter(w), r, protectedResourceMetadataURL(s.protectedResourceMetadata))
+	if !ok {
+		return
+	}
+	r = r.WithContext(ctx)
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -526,7 +582,12 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		eventQueue:          make(chan string, 100), // Buffer for events
 		sessionID:           sessionID,
 		notificationChannel: make(chan mcp.JSONRPCNotification, 100),
+		readLimiter:         bandwidth.NewLimiter(s.readLimit),
 	}
+	if identity, ok := IdentityFromContext(r.Context()); ok {
+		session.subject = identity.Subject
+	}
+	out := bandwidth.NewWriter(r.Context(), w, bandwidth.NewLimiter(s.writeLimit))
 
 	s.sessions.Store(sessionID, session)
 	defer s.sessions.Delete(sessionID)
@@ -540,6 +601,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer s.server.UnregisterSession(r.Context(), sessionID)
+	defer s.server.metrics.trackSSEConnection("sse")()
 
 	// Start notification handler for this session
 	go func() {
@@ -600,7 +662,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 	if s.appendQueryToMessageEndpoint && len(r.URL.RawQuery) > 0 {
 		endpoint += "&" + r.URL.RawQuery
 	}
-	fmt.Fprintf(w, "event: endpoint\ndata: %s\r\n\r\n", endpoint)
+	fmt.Fprintf(out, "event: endpoint\ndata: %s\r\n\r\n", endpoint)
 	flusher.Flush()
 
 	// Main event loop - this runs in the HTTP handler goroutine
@@ -608,7 +670,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		select {
 		case event := <-session.eventQueue:
 			// Write the event to the response
-			fmt.Fprint(w, event)
+			fmt.Fprint(out, event)
 			flusher.Flush()
 		case <-r.Context().Done():
 			session.closeDone()
@@ -643,6 +705,11 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		s.writeJSONRPCError(w, nil, mcp.INVALID_REQUEST, "Method not allowed")
 		return
 	}
+	authCtx, ok := s.server.authenticate(r.Context(), newHTTPResponseWriterAdapter(w), r, protectedResourceMetadataURL(s.protectedResourceMetadata))
+	if !ok {
+		return
+	}
+	r = r.WithContext(authCtx)
 
 	sessionID := r.URL.Query().Get("sessionId")
 	if sessionID == "" {
@@ -655,6 +722,10 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	session := sessionI.(*sseSession)
+	if s.server.authenticator != nil && !ownsSession(r.Context(), session.subject) {
+		http.Error(w, errSessionOwner, http.StatusForbidden)
+		return
+	}
 
 	// Set the client context before handling the message
 	ctx := s.server.WithContext(r.Context(), session)
@@ -664,7 +735,8 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 
 	// Parse message as raw JSON
 	var rawMessage json.RawMessage
-	if err := json.NewDecoder(r.Body).Decode(&rawMessage); err != nil {
+	body := bandwidth.NewReader(r.Context(), r.Body, session.readLimiter)
+	if err := json.NewDecoder(body).Decode(&rawMessage); err != nil {
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "Parse error")
 		return
 	}
@@ -684,7 +756,7 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		defer cancel()
 		defer func() {
 			if r := recover(); r != nil {
-				log.Printf("panic recovered in SSE message handler for session %s: %v", sessionID, r)
+				s.server.sessionLogger(sessionID).Error("panic recovered in SSE message handler", "panic", r)
 				// Send error response so the client doesn't hang waiting.
 				errResp := createErrorResponse(nil, mcp.INTERNAL_ERROR, fmt.Sprintf("internal panic: %v", r))
 				if eventData, err := json.Marshal(errResp); err == nil {
@@ -705,7 +777,7 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 			var message string
 			if eventData, err := json.Marshal(response); err != nil {
 				// If there is an error marshalling the response, send a generic error response
-				log.Printf("failed to marshal response: %v", err)
+				s.server.sessionLogger(sessionID).Error("failed to marshal response", "error", err)
 				message = "event: message\ndata: {\"error\": \"internal error\",\"jsonrpc\": \"2.0\", \"id\": null}\n\n"
 			} else {
 				message = fmt.Sprintf("event: message\ndata: %s\n\n", eventData)
@@ -719,7 +791,7 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 				// Session is closed, don't try to queue
 			default:
 				// Queue is full, log this situation
-				log.Printf("Event queue full for session %s", sessionID)
+				s.server.sessionLogger(sessionID).Warn("event queue full, response dropped")
 			}
 		}
 	}(messageCtx)
@@ -737,7 +809,7 @@ func (s *SSEServer) writeJSONRPCError(
 		// by writeJSONRPCError, so we cannot escalate to a different HTTP
 		// status here without producing a malformed response. Log instead,
 		// matching the streamable HTTP transport's behavior.
-		log.Printf("Failed to encode response: %v", err)
+		s.server.logger().Error("failed to encode response", "error", err)
 	})
 }
 
@@ -885,14 +957,11 @@ func (s *SSEServer) MessageHandler() http.Handler {
 // portion is a no-op when CORS is disabled.
 func (s *SSEServer) withCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !s.disableLocalhostProtection && rejectDNSRebinding(w, r) {
+		if s.origins.reject(w, r, s.corsConfig) {
 			return
 		}
-		if s.corsConfig.enabled() {
-			if s.corsConfig.handlePreflight(w, r) {
-				return
-			}
-			s.corsConfig.applySimple(w, r)
+		if s.corsConfig.handle(w, r) {
+			return
 		}
 		next.ServeHTTP(w, r)
 	})
@@ -905,18 +974,15 @@ func (s *SSEServer) withCORS(next http.Handler) http.Handler {
 // configured Access-Control-* headers before being dispatched to the SSE or
 // message handlers.
 //
-// Requests arriving over a loopback connection with a non-localhost Host
-// header are rejected with 403 Forbidden to protect against DNS rebinding
-// attacks, unless WithSSEDisableLocalhostProtection is set.
+// Requests arriving over a loopback connection with a non-localhost Host or
+// Origin header are rejected with 403 Forbidden to protect against DNS
+// rebinding attacks, unless WithSSEDisableLocalhostProtection is set.
 func (s *SSEServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if !s.disableLocalhostProtection && rejectDNSRebinding(w, r) {
+	if s.origins.reject(w, r, s.corsConfig) {
 		return
 	}
-	if s.corsConfig.enabled() {
-		if s.corsConfig.handlePreflight(w, r) {
-			return
-		}
-		s.corsConfig.applySimple(w, r)
+	if s.corsConfig.handle(w, r) {
+		return
 	}
 	if s.dynamicBasePathFunc != nil {
 		http.Error(
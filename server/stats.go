@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// StatsResourceURI is the URI of the resource registered by
+// WithStatsResource.
+const StatsResourceURI = "stats://server"
+
+// ServerStats is a snapshot of the server's runtime statistics.
+type ServerStats struct {
+	StartedAt     time.Time `json:"startedAt"`
+	UptimeSeconds float64   `json:"uptimeSeconds"`
+	// Sessions is the number of registered client sessions.
+	Sessions int `json:"sessions"`
+	// Requests counts the requests handled since the server started, and
+	// Errors those answered with a JSON-RPC error. RequestsByMethod counts
+	// requests for methods the server does not implement under "other".
+	Requests         int64            `json:"requests"`
+	Errors           int64            `json:"errors"`
+	RequestsByMethod map[string]int64 `json:"requestsByMethod"`
+	// Tasks counts the tasks held by this server by status.
+	Tasks map[mcp.TaskStatus]int `json:"tasks"`
+	// DroppedRequests counts the server-initiated requests that could not
+	// be delivered to the client, by method.
+	DroppedRequests map[string]int64 `json:"droppedRequests"`
+	// SamplingUsage is the sampling token usage reported by clients since
+	// the server started, in total and by the tool whose call made the
+	// requests.
+	SamplingUsage       mcp.SamplingUsage            `json:"samplingUsage"`
+	SamplingUsageByTool map[string]mcp.SamplingUsage `json:"samplingUsageByTool,omitempty"`
+}
+
+// serverStats collects the counters behind ServerStats and tracks which
+// sessions subscribed to the stats resource.
+type serverStats struct {
+	updateInterval time.Duration
+
+	mu           sync.Mutex
+	startedAt    time.Time
+	requests     int64
+	errors       int64
+	byMethod     map[string]int64
+	dropped      map[string]int64
+	lastNotified time.Time
+
+	subscribers sync.Map // session ID -> struct{}
+}
+
+// WithStatsResource exposes the server's runtime statistics (uptime,
+// sessions, request and error counts, tasks by status) as the JSON resource
+// stats://server, so any MCP host can monitor the server without separate
+// HTTP scraping.
+//
+// It enables resource subscriptions: clients that subscribe to the resource
+// receive notifications/resources/updated as the statistics change, at most
+// once per updateInterval. A zero updateInterval disables update
+// notifications.
+func WithStatsResource(updateInterval time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.stats = &serverStats{
+			updateInterval: updateInterval,
+			byMethod:       make(map[string]int64),
+			dropped:        make(map[string]int64),
+		}
+		s.requestMiddlewares = append(s.requestMiddlewares, s.statsMiddleware)
+	}
+}
+
+// registerStatsResource registers the stats resource. NewMCPServer calls it
+// after applying every option, so options that reset the resource
+// capabilities cannot undo it.
+func (s *MCPServer) registerStatsResource() {
+	s.AddResource(
+		mcp.NewResource(StatsResourceURI, "Server statistics",
+			mcp.WithResourceDescription("Uptime, sessions, request counts and task statistics of the server"),
+			mcp.WithMIMEType("application/json"),
+		),
+		func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			data, err := json.Marshal(s.Stats())
+			if err != nil {
+				return nil, err
+			}
+			return []mcp.ResourceContents{mcp.TextResourceContents{
+				URI:      StatsResourceURI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			}}, nil
+		},
+	)
+	s.capabilities.resources.subscribe = true
+}
+
+// Stats returns a snapshot of the server's runtime statistics. It returns
+// the zero value unless the server was created with WithStatsResource.
+func (s *MCPServer) Stats() ServerStats {
+	if s.stats == nil {
+		return ServerStats{}
+	}
+	s.stats.mu.Lock()
+	stats := ServerStats{
+		StartedAt:        s.stats.startedAt,
+		UptimeSeconds:    s.now().Sub(s.stats.startedAt).Seconds(),
+		Requests:         s.stats.requests,
+		Errors:           s.stats.errors,
+		RequestsByMethod: maps.Clone(s.stats.byMethod),
+		DroppedRequests:  maps.Clone(s.stats.dropped),
+	}
+	s.stats.mu.Unlock()
+	stats.SamplingUsage, stats.SamplingUsageByTool = s.samplingTotals()
+
+	stats.Sessions = s.sessionCount()
+	stats.Tasks = s.taskCounts()
+	return stats
+}
+
+// sessionCount returns the number of registered client sessions.
+func (s *MCPServer) sessionCount() int {
+	n := 0
+	s.sessions.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// taskCounts returns the number of tasks held by the server by status.
+func (s *MCPServer) taskCounts() map[mcp.TaskStatus]int {
+	counts := make(map[mcp.TaskStatus]int)
+	s.tasksMu.RLock()
+	for _, entry := range s.tasks {
+		counts[entry.task.Status]++
+	}
+	s.tasksMu.RUnlock()
+	return counts
+}
+
+// statsMiddleware counts requests and notifies stats subscribers.
+func (s *MCPServer) statsMiddleware(next RequestHandler) RequestHandler {
+	return func(ctx context.Context, request *mcp.JSONRPCRequest) mcp.JSONRPCMessage {
+		resp := next(ctx, request)
+		_, isError := resp.(mcp.JSONRPCError)
+
+		s.stats.mu.Lock()
+		s.stats.requests++
+		s.stats.byMethod[methodLabel(request.Method)]++
+		if isError {
+			s.stats.errors++
+		}
+		notify := s.stats.updateInterval > 0 && s.now().Sub(s.stats.lastNotified) >= s.stats.updateInterval
+		if notify {
+			s.stats.lastNotified = s.now()
+		}
+		s.stats.mu.Unlock()
+
+		if notify {
+			s.notifyStatsSubscribers()
+		}
+		return resp
+	}
+}
+
+// notifyStatsSubscribers sends notifications/resources/updated for the
+// stats resource to every subscribed session.
+func (s *MCPServer) notifyStatsSubscribers() {
+	s.stats.subscribers.Range(func(key, _ any) bool {
+		// Blocked channels are reported through the OnError hook.
+		_ = s.SendNotificationToSpecificClient(key.(string), mcp.MethodNotificationResourceUpdated, map[string]any{
+			"uri": StatsResourceURI,
+		})
+		return true
+	})
+}
+
+// trackStatsSubscription records a resources/subscribe or
+// resources/unsubscribe request for the stats resource.
+func (s *MCPServer) trackStatsSubscription(ctx context.Context, uri string, subscribed bool) {
+	if s.stats == nil || uri != StatsResourceURI {
+		return
+	}
+	sessionID := getSessionID(ctx)
+	if sessionID == "" {
+		return
+	}
+	if subscribed {
+		s.stats.subscribers.Store(sessionID, struct{}{})
+	} else {
+		s.stats.subscribers.Delete(sessionID)
+	}
+}
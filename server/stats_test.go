@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStatsResource(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewMCPServer("test", "1.0.0", WithClock(clock), WithStatsResource(time.Minute))
+
+	notifications := make(chan mcp.JSONRPCNotification, 10)
+	session := &fakeSession{sessionID: "monitor", notificationChannel: notifications, initialized: true}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	ctx := s.WithContext(context.Background(), session)
+
+	readStats := func() ServerStats {
+		t.Helper()
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"stats://server"}}`))
+		result, ok := resp.(mcp.JSONRPCResponse)
+		require.True(t, ok, "got %T", resp)
+		contents := result.Result.(mcp.ReadResourceResult).Contents
+		require.Len(t, contents, 1)
+		var stats ServerStats
+		require.NoError(t, json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &stats))
+		return stats
+	}
+
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"missing"}}`))
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":30,"method":"junk"}`))
+	clock.Advance(90 * time.Second)
+
+	stats := readStats()
+	assert.Equal(t, 90.0, stats.UptimeSeconds)
+	assert.Equal(t, 1, stats.Sessions)
+	// The read itself is counted once it completes.
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(2), stats.Errors)
+	assert.Equal(t, map[string]int64{"ping": 1, "tools/call": 1, "other": 1}, stats.RequestsByMethod)
+	assert.Equal(t, int64(4), s.Stats().Requests)
+	assert.Empty(t, notifications, "no updates without a subscription")
+
+	clock.Advance(time.Minute)
+	resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":4,"method":"resources/subscribe","params":{"uri":"stats://server"}}`))
+	require.IsType(t, mcp.JSONRPCResponse{}, resp)
+	require.Len(t, notifications, 1)
+	notification := <-notifications
+	assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+	assert.Equal(t, StatsResourceURI, notification.Params.AdditionalFields["uri"])
+
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":5,"method":"ping"}`))
+	assert.Empty(t, notifications, "updates are rate limited")
+
+	clock.Advance(time.Minute)
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":6,"method":"ping"}`))
+	assert.Len(t, notifications, 1)
+	<-notifications
+
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":7,"method":"resources/unsubscribe","params":{"uri":"stats://server"}}`))
+	clock.Advance(time.Minute)
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":8,"method":"ping"}`))
+	assert.Empty(t, notifications)
+}
+
+func TestMCPServer_Stats_Disabled(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0")
+	assert.Equal(t, ServerStats{}, s.Stats())
+}
+
+func TestWithStatsResource_BeforeResourceCapabilities(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithStatsResource(0), WithResourceCapabilities(false, true))
+
+	assert.Contains(t, s.ListResources(), StatsResourceURI)
+	assert.True(t, s.capabilities.resources.subscribe)
+	assert.True(t, s.capabilities.resources.listChanged)
+}
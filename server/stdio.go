@@ -1,18 +1,20 @@
 package server
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
 	"sync/atomic"
 	"syscall"
 
+	"github.com/mark3labs/mcp-go/internal/bandwidth"
+	"github.com/mark3labs/mcp-go/internal/framing"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -36,6 +38,10 @@ type StdioServer struct {
 	workerPoolSize int
 	queueSize      int
 	writeMu        sync.Mutex // Protects concurrent writes
+	readLimit      int
+	writeLimit     int
+	framing        Framing
+	session        *stdioSession
 }
 
 // toolCallWork represents a queued tool call request
@@ -90,10 +96,64 @@ func WithQueueSize(size int) StdioOption {
 	}
 }
 
-// stdioSession is a static client session, since stdio has only one client.
+// WithStdioBandwidthLimit caps the bytes per second the server reads from
+// stdin and writes to stdout, for example when MCP shares a constrained link
+// with other traffic. Writes beyond the limit wait, so a tool streaming a
+// large output slows down instead of starving other traffic. Zero leaves a
+// direction unlimited.
+func WithStdioBandwidthLimit(readBytesPerSecond, writeBytesPerSecond int) StdioOption {
+	return func(s *StdioServer) {
+		s.readLimit = readBytesPerSecond
+		s.writeLimit = writeBytesPerSecond
+	}
+}
+
+// Framing selects how the JSON-RPC messages exchanged with a client are
+// delimited on the byte stream.
+type Framing int
+
+const (
+	// FramingNewline ends every message with a newline, as the MCP stdio
+	// transport specifies. It is the default.
+	FramingNewline Framing = iota
+	// FramingContentLength precedes every message with LSP-style headers:
+	// a Content-Length header giving the size of the message in bytes,
+	// followed by an empty line. Messages may then span several lines.
+	FramingContentLength
+	// FramingAuto uses the framing of the client, detected from its first
+	// message, so one server binary works with hosts of either kind.
+	FramingAuto
+)
+
+// mode returns the framing package mode of f.
+func (f Framing) mode() framing.Mode {
+	switch f {
+	case FramingContentLength:
+		return framing.ContentLength
+	case FramingAuto:
+		return framing.Auto
+	default:
+		return framing.Newline
+	}
+}
+
+// WithFraming sets how messages are delimited on the stream. The default
+// is FramingNewline.
+func WithFraming(f Framing) StdioOption {
+	return func(s *StdioServer) {
+		s.framing = f
+	}
+}
+
+// stdioSession is the client session of a stdio stream. The process's
+// standard streams have a single, static session; every connection served by
+// a StreamServer has its own.
 type stdioSession struct {
-	clientInfoStore // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	clientInfoStore      // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	extensionStore       // provides Extension, Extensions and SetExtensions via method promotion
+	connectionStateStore // provides ConnectionState, OnConnectionStateChange and SetConnectionState via method promotion
 
+	id                  string
 	notifications       chan mcp.JSONRPCNotification
 	initialized         atomic.Bool
 	loggingLevel        atomic.Value
@@ -125,7 +185,10 @@ type rootsResponse struct {
 }
 
 func (s *stdioSession) SessionID() string {
-	return "stdio"
+	if s.id == "" {
+		return "stdio"
+	}
+	return s.id
 }
 
 func (s *stdioSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
@@ -161,7 +224,7 @@ func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMe
 	s.mu.RUnlock()
 
 	if writer == nil {
-		return nil, fmt.Errorf("no writer available for sending requests")
+		return nil, undeliverable(fmt.Errorf("no writer available for sending requests: %w", ErrSessionClosed))
 	}
 
 	// Generate a unique request ID
@@ -202,12 +265,13 @@ func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMe
 	requestBytes = append(requestBytes, '\n')
 
 	if _, err := writer.Write(requestBytes); err != nil {
-		return nil, fmt.Errorf("failed to write sampling request: %w", err)
+		return nil, undeliverable(fmt.Errorf("failed to write sampling request: %w", err))
 	}
 
 	// Wait for the response or context cancellation
 	select {
 	case <-ctx.Done():
+		notifyRequestCancelled(s, id, ctx.Err())
 		return nil, ctx.Err()
 	case response := <-responseChan:
 		if response.err != nil {
@@ -224,7 +288,7 @@ func (s *stdioSession) ListRoots(ctx context.Context, request mcp.ListRootsReque
 	s.mu.RUnlock()
 
 	if writer == nil {
-		return nil, fmt.Errorf("no writer available for sending requests")
+		return nil, undeliverable(fmt.Errorf("no writer available for sending requests: %w", ErrSessionClosed))
 	}
 
 	// Generate a unique request ID
@@ -263,7 +327,7 @@ func (s *stdioSession) ListRoots(ctx context.Context, request mcp.ListRootsReque
 	requestBytes = append(requestBytes, '\n')
 
 	if _, err := writer.Write(requestBytes); err != nil {
-		return nil, fmt.Errorf("failed to write list roots request: %w", err)
+		return nil, undeliverable(fmt.Errorf("failed to write list roots request: %w", err))
 	}
 
 	// Wait for the response or context cancellation
@@ -285,7 +349,7 @@ func (s *stdioSession) RequestElicitation(ctx context.Context, request mcp.Elici
 	s.mu.RUnlock()
 
 	if writer == nil {
-		return nil, fmt.Errorf("no writer available for sending requests")
+		return nil, undeliverable(fmt.Errorf("no writer available for sending requests: %w", ErrSessionClosed))
 	}
 
 	// Generate a unique request ID
@@ -326,7 +390,7 @@ func (s *stdioSession) RequestElicitation(ctx context.Context, request mcp.Elici
 	requestBytes = append(requestBytes, '\n')
 
 	if _, err := writer.Write(requestBytes); err != nil {
-		return nil, fmt.Errorf("failed to write elicitation request: %w", err)
+		return nil, undeliverable(fmt.Errorf("failed to write elicitation request: %w", err))
 	}
 
 	// Wait for the response or context cancellation
@@ -349,34 +413,52 @@ func (s *stdioSession) SetWriter(writer io.Writer) {
 }
 
 var (
-	_ ClientSession          = (*stdioSession)(nil)
-	_ SessionWithLogging     = (*stdioSession)(nil)
-	_ SessionWithClientInfo  = (*stdioSession)(nil)
-	_ SessionWithSampling    = (*stdioSession)(nil)
-	_ SessionWithElicitation = (*stdioSession)(nil)
-	_ SessionWithRoots       = (*stdioSession)(nil)
+	_ ClientSession              = (*stdioSession)(nil)
+	_ SessionWithLogging         = (*stdioSession)(nil)
+	_ SessionWithClientInfo      = (*stdioSession)(nil)
+	_ SessionWithExtensions      = (*stdioSession)(nil)
+	_ SessionWithConnectionState = (*stdioSession)(nil)
+	_ SessionWithSampling        = (*stdioSession)(nil)
+	_ SessionWithElicitation     = (*stdioSession)(nil)
+	_ SessionWithRoots           = (*stdioSession)(nil)
 )
 
-var stdioSessionInstance = stdioSession{
-	notifications:       make(chan mcp.JSONRPCNotification, 100),
-	pendingRequests:     make(map[int64]chan *samplingResponse),
-	pendingElicitations: make(map[int64]chan *elicitationResponse),
-	pendingRoots:        make(map[int64]chan *rootsResponse),
-}
-
-// NewStdioServer creates a new stdio server wrapper around an MCPServer.
-// It initializes the server with a default error logger that discards all output.
-func NewStdioServer(server *MCPServer) *StdioServer {
-	return &StdioServer{
-		server: server,
-		errLogger: log.New(
-			os.Stderr,
-			"",
-			log.LstdFlags,
-		), // Default to discarding logs
+var stdioSessionInstance = newStdioSession("")
+
+// newStdioSession creates a session with the given ID, or the ID "stdio" if
+// it is empty.
+func newStdioSession(id string) *stdioSession {
+	return &stdioSession{
+		id:                  id,
+		notifications:       make(chan mcp.JSONRPCNotification, 100),
+		pendingRequests:     make(map[int64]chan *samplingResponse),
+		pendingElicitations: make(map[int64]chan *elicitationResponse),
+		pendingRoots:        make(map[int64]chan *rootsResponse),
+	}
+}
+
+// NewStdioServer creates a new stdio server wrapper around an MCPServer,
+// configured with opts.
+// Errors are logged to stderr, through the logger installed with WithLogger
+// if there is one, unless another logger is set with WithErrorLogger.
+func NewStdioServer(server *MCPServer, opts ...StdioOption) *StdioServer {
+	errLogger := log.New(os.Stderr, "", log.LstdFlags)
+	if server.requestLogger != nil {
+		// Route errors through the server's structured logger, which the
+		// caller has pointed away from stdout.
+		errLogger = slog.NewLogLogger(server.requestLogger.Handler(), slog.LevelError)
+	}
+	s := &StdioServer{
+		server:         server,
+		errLogger:      errLogger,
 		workerPoolSize: 5,   // Default worker pool size
 		queueSize:      100, // Default queue size
+		session:        stdioSessionInstance,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // SetErrorLogger configures where error messages from the StdioServer are logged.
@@ -398,7 +480,7 @@ func (s *StdioServer) SetContextFunc(fn StdioContextFunc) {
 func (s *StdioServer) handleNotifications(ctx context.Context, stdout io.Writer) {
 	for {
 		select {
-		case notification := <-stdioSessionInstance.notifications:
+		case notification := <-s.session.notifications:
 			if err := s.writeResponse(notification, stdout); err != nil {
 				s.errLogger.Printf("Error writing notification: %v", err)
 			}
@@ -414,13 +496,13 @@ func (s *StdioServer) handleNotifications(ctx context.Context, stdout io.Writer)
 // - The context is cancelled (returns context.Err())
 // - EOF is encountered (returns nil)
 // - An error occurs while reading or processing messages (returns the error)
-func (s *StdioServer) processInputStream(ctx context.Context, reader *bufio.Reader, stdout io.Writer) error {
+func (s *StdioServer) processInputStream(ctx context.Context, reader *framing.Reader, stdout io.Writer) error {
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		line, err := s.readNextLine(ctx, reader)
+		line, err := s.readNextMessage(ctx, reader)
 		if err != nil {
 			if err == io.EOF {
 				return nil
@@ -472,12 +554,12 @@ func (s *StdioServer) toolCallWorker(ctx context.Context) {
 	}
 }
 
-// readNextLine reads a single line from the input reader in a context-aware manner.
+// readNextMessage reads a single message from the input reader in a context-aware manner.
 // It uses channels to make the read operation cancellable via context.
-// Returns the read line and any error encountered. If the context is cancelled,
+// Returns the read message and any error encountered. If the context is cancelled,
 // returns an empty string and the context's error. EOF is returned when the input
 // stream is closed.
-func (s *StdioServer) readNextLine(ctx context.Context, reader *bufio.Reader) (string, error) {
+func (s *StdioServer) readNextMessage(ctx context.Context, reader *framing.Reader) (string, error) {
 	type result struct {
 		line string
 		err  error
@@ -486,8 +568,8 @@ func (s *StdioServer) readNextLine(ctx context.Context, reader *bufio.Reader) (s
 	resultCh := make(chan result, 1)
 
 	go func() {
-		line, err := reader.ReadString('\n')
-		resultCh <- result{line: line, err: err}
+		message, err := reader.ReadMessage()
+		resultCh <- result{line: string(message), err: err}
 	}()
 
 	select {
@@ -509,23 +591,32 @@ func (s *StdioServer) Listen(
 	// Initialize the tool call queue
 	s.toolCallQueue = make(chan *toolCallWork, s.queueSize)
 
+	stdin = bandwidth.NewReader(ctx, stdin, bandwidth.NewLimiter(s.readLimit))
+	stdout = bandwidth.NewWriter(ctx, stdout, bandwidth.NewLimiter(s.writeLimit))
+	// Every write below is one complete message, which the framing writer
+	// frames as a whole, in the framing of the client with FramingAuto.
+	reader := framing.NewReader(stdin, s.framing.mode())
+	if s.framing == FramingAuto {
+		stdout = framing.NewReplyWriter(stdout, reader)
+	} else {
+		stdout = framing.NewWriter(stdout, s.framing.mode())
+	}
+
 	// Set a static client context since stdio only has one client
-	if err := s.server.RegisterSession(ctx, &stdioSessionInstance); err != nil {
+	if err := s.server.RegisterSession(ctx, s.session); err != nil {
 		return fmt.Errorf("register session: %w", err)
 	}
-	defer s.server.UnregisterSession(ctx, stdioSessionInstance.SessionID())
-	ctx = s.server.WithContext(ctx, &stdioSessionInstance)
+	defer s.server.UnregisterSession(ctx, s.session.SessionID())
+	ctx = s.server.WithContext(ctx, s.session)
 
 	// Set the writer for sending requests to the client
-	stdioSessionInstance.SetWriter(stdout)
+	s.session.SetWriter(stdout)
 
 	// Add in any custom context.
 	if s.contextFunc != nil {
 		ctx = s.contextFunc(ctx)
 	}
 
-	reader := bufio.NewReader(stdin)
-
 	// Start worker pool for tool calls
 	for i := 0; i < s.workerPoolSize; i++ {
 		s.workerWg.Add(1)
@@ -622,7 +713,7 @@ func (s *StdioServer) processMessage(
 // handleSamplingResponse checks if the message is a response to a sampling request
 // and routes it to the appropriate pending request channel.
 func (s *StdioServer) handleSamplingResponse(rawMessage json.RawMessage) bool {
-	return stdioSessionInstance.handleSamplingResponse(rawMessage)
+	return s.session.handleSamplingResponse(rawMessage)
 }
 
 // handleSamplingResponse handles incoming sampling responses for this session
@@ -692,7 +783,7 @@ func (s *stdioSession) handleSamplingResponse(rawMessage json.RawMessage) bool {
 // handleElicitationResponse checks if the message is a response to an elicitation request
 // and routes it to the appropriate pending request channel.
 func (s *StdioServer) handleElicitationResponse(rawMessage json.RawMessage) bool {
-	return stdioSessionInstance.handleElicitationResponse(rawMessage)
+	return s.session.handleElicitationResponse(rawMessage)
 }
 
 // handleElicitationResponse handles incoming elicitation responses for this session
@@ -753,7 +844,7 @@ func (s *stdioSession) handleElicitationResponse(rawMessage json.RawMessage) boo
 // handleListRootsResponse checks if the message is a response to an list roots request
 // and routes it to the appropriate pending request channel.
 func (s *StdioServer) handleListRootsResponse(rawMessage json.RawMessage) bool {
-	return stdioSessionInstance.handleListRootsResponse(rawMessage)
+	return s.session.handleListRootsResponse(rawMessage)
 }
 
 // handleListRootsResponse handles incoming list root responses for this session
@@ -838,11 +929,7 @@ func (s *StdioServer) writeResponse(
 // It sets up signal handling for graceful shutdown on SIGTERM and SIGINT.
 // Returns an error if the server encounters any issues during operation.
 func ServeStdio(server *MCPServer, opts ...StdioOption) error {
-	s := NewStdioServer(server)
-
-	for _, opt := range opts {
-		opt(s)
-	}
+	s := NewStdioServer(server, opts...)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"io"
+)
+
+// StreamServer serves MCP over byte streams the caller manages, such as
+// pipes, TTYs, Unix sockets or TCP connections, the way StdioServer serves
+// the process's standard streams. Each stream passed to Serve is a client
+// session of its own, so one StreamServer can serve many connections at
+// once:
+//
+//	ss := server.NewStreamServer(s, server.WithFraming(server.FramingContentLength))
+//	for {
+//	    conn, err := listener.Accept()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    go ss.Serve(ctx, conn)
+//	}
+type StreamServer struct {
+	server *MCPServer
+	opts   []StdioOption
+}
+
+// NewStreamServer creates a StreamServer for server. The options configure
+// every stream it serves; a context function set with WithStdioContextFunc
+// is called once per stream.
+func NewStreamServer(server *MCPServer, opts ...StdioOption) *StreamServer {
+	return &StreamServer{server: server, opts: opts}
+}
+
+// Serve handles the JSON-RPC messages read from conn in a new client
+// session, whose ID comes from the server's IDSource, writing responses,
+// notifications and server-initiated requests back to it. It returns when
+// conn reaches EOF, fails, or ctx is done, and closes conn before returning.
+func (s *StreamServer) Serve(ctx context.Context, conn io.ReadWriteCloser) error {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stdio := NewStdioServer(s.server, s.opts...)
+	stdio.session = newStdioSession(s.server.newID())
+	return stdio.Listen(ctx, conn, conn)
+}
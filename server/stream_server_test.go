@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/internal/framing"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// streamClient exchanges framed messages with a StreamServer.
+type streamClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *framing.Reader
+	writer io.Writer
+}
+
+func newStreamClient(t *testing.T, ss *StreamServer, mode framing.Mode) (*streamClient, chan error) {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- ss.Serve(context.Background(), serverConn) }()
+	t.Cleanup(func() { _ = clientConn.Close() })
+	return &streamClient{
+		t:      t,
+		conn:   clientConn,
+		reader: framing.NewReader(clientConn, mode),
+		writer: framing.NewWriter(clientConn, mode),
+	}, done
+}
+
+func (c *streamClient) send(message string) {
+	c.t.Helper()
+	_, err := c.writer.Write([]byte(message + "\n"))
+	require.NoError(c.t, err)
+}
+
+func (c *streamClient) receive() map[string]any {
+	c.t.Helper()
+	require.NoError(c.t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	message, err := c.reader.ReadMessage()
+	require.NoError(c.t, err)
+	var response map[string]any
+	require.NoError(c.t, json.Unmarshal(message, &response))
+	return response
+}
+
+func (c *streamClient) callSessionTool() string {
+	c.t.Helper()
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"session"}}`)
+	response := c.receive()
+	require.Contains(c.t, response, "result", response)
+	var result mcp.CallToolResult
+	raw, _ := json.Marshal(response["result"])
+	require.NoError(c.t, json.Unmarshal(raw, &result))
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestStreamServer(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("session"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(ClientSessionFromContext(ctx).SessionID()), nil
+	})
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"%s","clientInfo":{"name":"test","version":"1.0.0"}}}`
+
+	newline, newlineDone := newStreamClient(t, NewStreamServer(mcpServer), framing.Newline)
+	contentLength, contentLengthDone := newStreamClient(t, NewStreamServer(mcpServer, WithFraming(FramingContentLength)), framing.ContentLength)
+
+	newline.send(fmt.Sprintf(initialize, mcp.LATEST_PROTOCOL_VERSION))
+	assert.Contains(t, newline.receive(), "result")
+	// Content-Length framing allows messages spanning several lines.
+	contentLength.send(fmt.Sprintf("{\n  \"jsonrpc\": \"2.0\",\n  \"id\": 1,\n  \"method\": \"initialize\",\n  \"params\": {\"protocolVersion\": %q}\n}", mcp.LATEST_PROTOCOL_VERSION))
+	assert.Contains(t, contentLength.receive(), "result")
+
+	first, second := newline.callSessionTool(), contentLength.callSessionTool()
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, "stdio", first)
+	assert.Equal(t, 2, mcpServer.sessionCount())
+
+	// Notifications reach only the session they are sent to.
+	require.NoError(t, mcpServer.SendNotificationToSpecificClient(second, "notifications/test", map[string]any{"n": 1}))
+	assert.Equal(t, "notifications/test", contentLength.receive()["method"])
+
+	require.NoError(t, newline.conn.Close())
+	select {
+	case err := <-newlineDone:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the connection closed")
+	}
+	assert.Equal(t, 1, mcpServer.sessionCount())
+	assert.Equal(t, second, contentLength.callSessionTool())
+
+	require.NoError(t, contentLength.conn.Close())
+	select {
+	case <-contentLengthDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the connection closed")
+	}
+}
+
+func TestStreamServer_IDSource(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0", WithIDSource(NewSequentialIDSource("conn")))
+	mcpServer.AddTool(mcp.NewTool("session"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(ClientSessionFromContext(ctx).SessionID()), nil
+	})
+	client, _ := newStreamClient(t, NewStreamServer(mcpServer), framing.Newline)
+	client.send(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":%q}}`, mcp.LATEST_PROTOCOL_VERSION))
+	assert.Contains(t, client.receive(), "result")
+	assert.Equal(t, "conn-1", client.callSessionTool())
+}
@@ -3,7 +3,9 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -53,7 +55,8 @@ func WithStateLess(stateLess bool) StreamableHTTPOption {
 }
 
 // WithSessionIdManager sets a custom session id generator for the server.
-// By default, the server uses StatelessGeneratingSessionIdManager (generates IDs but no local validation).
+// By default, the server uses StatelessGeneratingSessionIdManager (generates IDs but no local validation),
+// or HMACSessionIdManager if WithSessionSigningKey is set.
 // Note: Options are applied in order; the last one wins. If combined with
 // WithStateLess or WithSessionIdManagerResolver, whichever is applied last takes effect.
 func WithSessionIdManager(manager SessionIdManager) StreamableHTTPOption {
@@ -80,16 +83,34 @@ func WithSessionIdManagerResolver(resolver SessionIdManagerResolver) StreamableH
 	}
 }
 
-// WithStateful enables stateful session management using InsecureStatefulSessionIdManager.
+// WithStateful enables stateful session management using InsecureStatefulSessionIdManager,
+// or HMACSessionIdManager if WithSessionSigningKey is set.
 // This requires sticky sessions in multi-instance deployments.
 func WithStateful(stateful bool) StreamableHTTPOption {
 	return func(s *StreamableHTTPServer) {
 		if stateful {
 			s.sessionIdManagerResolver = NewDefaultSessionIdManagerResolver(&InsecureStatefulSessionIdManager{})
+			s.signableResolver = s.sessionIdManagerResolver
 		}
 	}
 }
 
+// WithSessionSigningKey makes HMACSessionIdManager, signing with key and
+// accepting IDs signed with previousKeys, the default session id manager. It
+// replaces the default StatelessGeneratingSessionIdManager and the
+// InsecureStatefulSessionIdManager enabled by WithStateful; managers set with
+// WithSessionIdManager, WithSessionIdManagerResolver, WithSessionStore or
+// WithStateLess take precedence. It panics if a key is shorter than 32 bytes.
+func WithSessionSigningKey(key []byte, previousKeys ...[]byte) StreamableHTTPOption {
+	manager, err := NewHMACSessionIdManager(key, previousKeys...)
+	if err != nil {
+		panic(err)
+	}
+	return func(s *StreamableHTTPServer) {
+		s.signedSessionIdManager = manager
+	}
+}
+
 // WithHeartbeatInterval sets the heartbeat interval. Positive interval means the
 // server will send a heartbeat to the client through the GET connection, to keep
 // the connection alive from being closed by the network infrastructure (e.g.
@@ -111,14 +132,41 @@ func WithDisableStreaming(disable bool) StreamableHTTPOption {
 	}
 }
 
+// DefaultMaxRequestBodySize is the largest POST body the streamable HTTP
+// server accepts unless WithMaxRequestBodySize sets otherwise.
+const DefaultMaxRequestBodySize int64 = 10 << 20
+
+// WithMaxRequestBodySize sets the largest POST body, in bytes, the server
+// accepts. The limit applies to gzip-compressed bodies once decompressed.
+// Larger bodies are rejected with 413 Request Entity Too Large. The default
+// is DefaultMaxRequestBodySize.
+func WithMaxRequestBodySize(size int64) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.maxBodySize = size
+	}
+}
+
+// WithGzipRequests makes the server accept gzip-compressed POST bodies from
+// every client. Without it, a gzip body is only accepted on a session that
+// negotiated gzip compression (see WithEncodingNegotiation), and is
+// otherwise rejected with 415 Unsupported Media Type.
+func WithGzipRequests(accept bool) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.gzipRequests = accept
+	}
+}
+
 // WithDisableLocalhostProtection disables the automatic DNS rebinding
 // protection of the streamable HTTP server.
 //
 // By default, requests arriving over a loopback connection (127.0.0.1,
 // [::1]) whose Host header is not a localhost value are rejected with 403
-// Forbidden. This protects local MCP servers against DNS rebinding attacks,
-// where a malicious website rebinds its own domain to 127.0.0.1 to make a
-// victim's browser issue requests against a local server. The protection
+// Forbidden, as are requests carrying an Origin header that is neither a
+// localhost origin nor allowed by the CORS configuration. This protects
+// local MCP servers against DNS rebinding attacks, where a malicious website
+// rebinds its own domain to 127.0.0.1 to make a victim's browser issue
+// requests against a local server, and against cross-site requests from
+// other websites. The protection
 // applies regardless of whether the server listens on localhost specifically
 // or on 0.0.0.0, and never affects requests arriving via non-loopback
 // addresses.
@@ -126,12 +174,44 @@ func WithDisableStreaming(disable bool) StreamableHTTPOption {
 // Disable it only if you understand the security implications, for example
 // when a reverse proxy on the same host forwards requests via localhost
 // while preserving the original Host header. In that case, prefer
-// configuring the proxy to rewrite the Host header to localhost instead.
+// configuring the proxy to rewrite the Host header to localhost, or listing
+// the public host names with WithAllowedHosts. Allow lists set with
+// WithAllowedHosts and WithAllowedOrigins are enforced even when the
+// protection is disabled.
 //
 // See https://modelcontextprotocol.io/specification/2025-11-25/basic/security_best_practices#local-mcp-server-compromise
 func WithDisableLocalhostProtection(disable bool) StreamableHTTPOption {
 	return func(s *StreamableHTTPServer) {
-		s.disableLocalhostProtection = disable
+		s.origins.disableLocalhostProtection = disable
+	}
+}
+
+// WithAllowedHosts restricts the Host header of requests to hosts, on every
+// connection. Entries without a port match any port. Requests for other
+// hosts are rejected with 403 Forbidden. Use it when the server is reachable
+// under a known set of names, for example behind a reverse proxy.
+//
+// The list replaces the localhost check applied by default to loopback
+// connections, so include "localhost" and "127.0.0.1" to keep accepting
+// local requests.
+func WithAllowedHosts(hosts ...string) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.origins.allowedHosts = append(s.origins.allowedHosts[:0:0], hosts...)
+	}
+}
+
+// WithAllowedOrigins restricts the Origin header of requests to origins, on
+// every connection, such as "https://app.example.com". Requests from other
+// origins are rejected with 403 Forbidden; requests without an Origin
+// header, which browsers always send on cross-site requests, are accepted.
+// Origins allowed by WithStreamableHTTPCORS are accepted as well.
+//
+// Without it, requests over loopback connections are only accepted from
+// loopback origins, such as http://localhost:3000, unless
+// WithDisableLocalhostProtection is set.
+func WithAllowedOrigins(origins ...string) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.origins.allowedOrigins = append(s.origins.allowedOrigins[:0:0], origins...)
 	}
 }
 
@@ -157,7 +237,8 @@ func WithStreamableHTTPServer(srv *http.Server) StreamableHTTPOption {
 // events emitted by the HTTP server (panics in goroutines, SSE event-write
 // errors, session expiry, etc.). It is renamed from WithLogger so that
 // server-level structured logging — see WithLogger — can carry that name on
-// the MCPServer. A nil logger falls back to slog.Default().
+// the MCPServer. It defaults to the logger installed with WithLogger, and a
+// nil logger falls back to slog.Default().
 func WithStreamableHTTPLogger(logger *slog.Logger) StreamableHTTPOption {
 	return func(s *StreamableHTTPServer) {
 		if logger == nil {
@@ -238,6 +319,30 @@ func WithStreamableHTTPCORS(opts ...CORSOption) StreamableHTTPOption {
 	}
 }
 
+// WithCORS lets browser applications served from allowedOrigins call the
+// server directly, without a fronting proxy. It is a shorthand for
+// WithStreamableHTTPCORS with a strict default: preflight requests are
+// answered for allowedOrigins, the MCP headers (Content-Type,
+// Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID and Authorization)
+// are allowed along with allowedHeaders, Mcp-Session-Id is exposed to
+// scripts, and requests from any other origin are rejected with 403
+// Forbidden (see CORSConfig.StrictOrigin). An empty allowedOrigins therefore
+// rejects every browser request. Browsers cache preflight responses for
+// maxAge; zero leaves it to the browser.
+//
+//	srv := server.NewStreamableHTTPServer(mcpServer,
+//	    server.WithCORS([]string{"https://app.example.com"}, nil, 10*time.Minute),
+//	)
+func WithCORS(allowedOrigins, allowedHeaders []string, maxAge time.Duration) StreamableHTTPOption {
+	headers := []string{"Content-Type", HeaderKeySessionID, HeaderKeyProtocolVersion, "Last-Event-ID", "Authorization"}
+	return WithStreamableHTTPCORS(
+		WithCORSAllowedOrigins(allowedOrigins...),
+		WithCORSAllowedHeaders(append(headers, allowedHeaders...)...),
+		WithCORSMaxAge(int(maxAge/time.Second)),
+		WithCORSStrictOrigin(),
+	)
+}
+
 // StreamableHTTPServer implements a Streamable-http based MCP server.
 // It communicates with clients over HTTP protocol, supporting both direct HTTP responses, and SSE streams.
 // https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#streamable-http
@@ -259,8 +364,8 @@ func WithStreamableHTTPCORS(opts ...CORSOption) StreamableHTTPOption {
 // not trigger the session registration. So the methods like `SendNotificationToSpecificClient`
 // or `hooks.onRegisterSession` will not be triggered for POST messages.
 //
-// The current implementation does not support the following features from the specification:
-//   - Stream Resumability
+// Stream resumability is available for the listening (GET) stream when an
+// EventStore is configured with WithEventStore.
 type StreamableHTTPServer struct {
 	server                   *MCPServer
 	sessionTools             *sessionToolsStore
@@ -268,6 +373,8 @@ type StreamableHTTPServer struct {
 	sessionResourceTemplates *sessionResourceTemplatesStore
 	sessionRequestIDs        sync.Map // sessionId --> last requestID(*atomic.Int64)
 	activeSessions           sync.Map // sessionId --> *streamableHttpSession (for sampling responses)
+	detachedSessions         sync.Map // sessionId --> *detachedSession awaiting reconnect
+	redeliveryWindow         time.Duration
 
 	httpServer *http.Server
 	mu         sync.RWMutex
@@ -281,14 +388,31 @@ type StreamableHTTPServer struct {
 	sessionLogLevels         *sessionLogLevelsStore
 	disableStreaming         bool
 
-	// disableLocalhostProtection, when true, turns off the automatic DNS
-	// rebinding protection applied to requests arriving over loopback
-	// connections. See WithDisableLocalhostProtection.
-	disableLocalhostProtection bool
+	// maxBodySize caps POST bodies, decompressed or not; gzipRequests
+	// accepts gzip bodies on sessions that did not negotiate gzip. See
+	// WithMaxRequestBodySize and WithGzipRequests.
+	maxBodySize  int64
+	gzipRequests bool
+
+	// signedSessionIdManager, set by WithSessionSigningKey, replaces
+	// signableResolver: the default resolver, or the one set by
+	// WithStateful.
+	signedSessionIdManager *HMACSessionIdManager
+	signableResolver       SessionIdManagerResolver
+
+	// origins validates the Host and Origin headers of requests. See
+	// WithDisableLocalhostProtection, WithAllowedHosts and
+	// WithAllowedOrigins.
+	origins originGuard
 
 	tlsCertFile string
 	tlsKeyFile  string
 
+	clientCAs           *x509.CertPool
+	sessionCertificates sync.Map // sessionId --> *x509.Certificate the session was initialized with
+
+	sessionSubjects *sessionSubjectsStore // sessionId --> Identity.Subject of the request that created the session
+
 	sessionIdleTTL    time.Duration
 	sessionLastActive sync.Map // sessionID → *atomic.Int64 (unix nanos)
 	sweeperCancel     context.CancelFunc
@@ -304,19 +428,54 @@ type StreamableHTTPServer struct {
 	// the server emit CORS headers and answer preflight requests. See
 	// WithStreamableHTTPCORS.
 	corsConfig *CORSConfig
+
+	// eventStore, when non-nil, records listening stream events for
+	// Last-Event-ID replay. See WithEventStore.
+	eventStore EventStore
+
+	// sessionStore, when non-nil, shares session validity and routes
+	// messages between replicas. See WithSessionStore.
+	sessionStore SessionStore
+
+	// debugTap, when non-nil, mirrors session traffic to the debug
+	// endpoint at debugPath. See WithDebugEndpoint.
+	debugTap       *debugTap
+	debugPath      string
+	debugAuthorize DebugAuthorizer
+
+	// health, when non-nil, serves the liveness and readiness endpoints.
+	// See WithHealthEndpoints. shuttingDown makes the server unready once
+	// Shutdown is called.
+	health       *healthEndpoints
+	shuttingDown atomic.Bool
+
+	// readLimit and writeLimit cap the bytes per second of each session,
+	// whose limiters are kept in sessionBandwidth. See
+	// WithStreamableHTTPBandwidthLimit.
+	readLimit        int
+	writeLimit       int
+	sessionBandwidth sync.Map // session ID -> *sessionBandwidth
+
+	// httpCache, when non-nil, removes session mechanics and adds HTTP
+	// caching headers to responses. See WithHTTPCaching.
+	httpCache *HTTPCacheConfig
 }
 
 // NewStreamableHTTPServer creates a new streamable-http server instance
 func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *StreamableHTTPServer {
+	defaultResolver := NewDefaultSessionIdManagerResolver(&StatelessGeneratingSessionIdManager{})
 	s := &StreamableHTTPServer{
 		server:                   server,
 		sessionTools:             newSessionToolsStore(),
 		sessionLogLevels:         newSessionLogLevelsStore(),
 		endpointPath:             "/mcp",
-		sessionIdManagerResolver: NewDefaultSessionIdManagerResolver(&StatelessGeneratingSessionIdManager{}),
-		logger:                   slog.Default(),
+		sessionIdManagerResolver: defaultResolver,
+		signableResolver:         defaultResolver,
+		logger:                   server.logger(),
 		sessionResources:         newSessionResourcesStore(),
 		sessionResourceTemplates: newSessionResourceTemplatesStore(),
+		maxBodySize:              DefaultMaxRequestBodySize,
+		sessionSubjects:          &sessionSubjectsStore{},
 	}
 
 	// Apply all options
@@ -324,6 +483,16 @@ func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *S
 		opt(s)
 	}
 
+	// Share the subjects of sessions through the store when it can.
+	if store, ok := s.sessionStore.(SessionSubjectStore); ok {
+		s.sessionSubjects.shared = store
+	}
+
+	// A signing key makes the HMAC manager the default.
+	if s.signedSessionIdManager != nil && s.sessionIdManagerResolver == s.signableResolver {
+		s.sessionIdManagerResolver = NewDefaultSessionIdManagerResolver(s.signedSessionIdManager)
+	}
+
 	// Cache the session ID manager for use in non-request contexts (sweeper).
 	// DefaultSessionIdManagerResolver always returns the same manager,
 	// so resolving it once at startup is semantically identical.
@@ -331,6 +500,16 @@ func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *S
 		s.sessionIdManager = r.manager
 	}
 
+	// Let the built-in managers mint IDs from the server's IDSource.
+	if server != nil && server.idSource != nil {
+		switch m := s.sessionIdManager.(type) {
+		case *StatelessGeneratingSessionIdManager:
+			m.newID = server.newID
+		case *InsecureStatefulSessionIdManager:
+			m.newID = server.newID
+		}
+	}
+
 	if s.sessionIdleTTL > 0 {
 		ctx, cancel := context.WithCancel(context.Background())
 		s.sweeperCancel = cancel
@@ -351,26 +530,30 @@ func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *S
 // requests are answered directly and simple cross-origin responses are
 // decorated with the configured Access-Control-* headers before dispatch.
 //
-// Requests arriving over a loopback connection with a non-localhost Host
-// header are rejected with 403 Forbidden to protect against DNS rebinding
-// attacks, unless WithDisableLocalhostProtection is set.
+// Requests arriving over a loopback connection with a non-localhost Host or
+// Origin header are rejected with 403 Forbidden to protect against DNS
+// rebinding attacks, unless WithDisableLocalhostProtection is set.
 //
 // ServeHTTP is the conventional net/http entry point; for non-net/http HTTP
 // frameworks (fasthttp, fiber, etc.), see Handle.
 func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if !s.disableLocalhostProtection && rejectDNSRebinding(w, r) {
+	if s.origins.reject(w, r, s.corsConfig) {
 		return
 	}
-	if s.corsConfig.enabled() {
-		if s.corsConfig.handlePreflight(w, r) {
-			return
-		}
-		s.corsConfig.applySimple(w, r)
+	if s.corsConfig.handle(w, r) {
+		return
 	}
 	if s.protectedResourceMetadataHandler != nil && r.URL.Path == s.protectedResourceMetadataPath {
 		s.protectedResourceMetadataHandler.ServeHTTP(w, r)
 		return
 	}
+	if s.debugTap != nil && r.URL.Path == s.debugPath {
+		s.handleDebugStream(w, r)
+		return
+	}
+	if s.serveHealth(w, r) {
+		return
+	}
 
 	// Read the request body up-front so the transport-agnostic core never
 	// needs to keep an io.Reader alive across SSE upgrades. Body errors are
@@ -379,7 +562,8 @@ func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	var body []byte
 	var bodyErr error
 	if r.Method == http.MethodPost && r.Body != nil {
-		body, bodyErr = io.ReadAll(r.Body)
+		// One byte past the limit tells handlePost the body is too large.
+		body, bodyErr = io.ReadAll(io.LimitReader(r.Body, s.maxBodySize+1))
 	}
 
 	hr := &HTTPRequest{
@@ -390,12 +574,27 @@ func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		Context:  r.Context(),
 		original: r,
 	}
-	hw := newHTTPResponseWriterAdapter(w)
+	var hw HTTPResponseWriter = newHTTPResponseWriterAdapter(w)
+
+	certCtx, ok := s.authenticateClientCertificate(hr.Context, hw, r)
+	if !ok {
+		return
+	}
+	hr.Context = certCtx
 
 	if bodyErr != nil && r.Method == http.MethodPost {
 		s.writeJSONRPCError(hw, nil, mcp.PARSE_ERROR, fmt.Sprintf("read request body error: %v", bodyErr))
 		return
 	}
+	ctx, ok := s.server.authenticate(hr.Context, hw, r, protectedResourceMetadataURL(s.protectedResourceMetadata))
+	if !ok || !s.authorizeSession(ctx, hw, hr.Header.Get(HeaderKeySessionID)) {
+		return
+	}
+	hr.Context = ctx
+	hw, err := s.throttle(hw, hr)
+	if err != nil {
+		return
+	}
 
 	switch r.Method {
 	case http.MethodPost:
@@ -421,6 +620,17 @@ func (s *StreamableHTTPServer) Start(addr string) error {
 		if s.protectedResourceMetadataHandler != nil && s.protectedResourceMetadataPath != s.endpointPath {
 			mux.Handle(s.protectedResourceMetadataPath, s.protectedResourceMetadataHandler)
 		}
+		if s.debugTap != nil && s.debugPath != s.endpointPath {
+			mux.Handle(s.debugPath, s)
+		}
+		if s.health != nil {
+			if path := s.health.livenessPath; path != "" && path != s.endpointPath {
+				mux.Handle(path, s)
+			}
+			if path := s.health.readinessPath; path != "" && path != s.endpointPath && path != s.health.livenessPath {
+				mux.Handle(path, s)
+			}
+		}
 		s.httpServer = &http.Server{
 			Addr:    addr,
 			Handler: mux,
@@ -435,6 +645,9 @@ func (s *StreamableHTTPServer) Start(addr string) error {
 	srv := s.httpServer
 	s.mu.Unlock()
 
+	if s.clientCAs != nil && s.tlsCertFile == "" {
+		return fmt.Errorf("WithClientCAs requires WithTLSCert")
+	}
 	if s.tlsCertFile != "" || s.tlsKeyFile != "" {
 		if s.tlsCertFile == "" || s.tlsKeyFile == "" {
 			return fmt.Errorf("both TLS cert and key must be provided")
@@ -445,6 +658,7 @@ func (s *StreamableHTTPServer) Start(addr string) error {
 		if _, err := os.Stat(s.tlsKeyFile); err != nil {
 			return fmt.Errorf("failed to find TLS key file: %w", err)
 		}
+		srv.TLSConfig = s.clientTLSConfig(srv.TLSConfig)
 		return srv.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
 	}
 
@@ -454,6 +668,7 @@ func (s *StreamableHTTPServer) Start(addr string) error {
 // Shutdown gracefully stops the server, closing all active sessions
 // and shutting down the HTTP server.
 func (s *StreamableHTTPServer) Shutdown(ctx context.Context) error {
+	s.shuttingDown.Store(true)
 	if s.sweeperCancel != nil {
 		s.sweeperCancel()
 	}
@@ -483,6 +698,25 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 
 	// Body has already been buffered by the caller (ServeHTTP or Handle).
 	rawData := r.Body
+	if int64(len(rawData)) > s.maxBodySize {
+		writeHTTPError(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if strings.EqualFold(r.header().Get("Content-Encoding"), mcp.CompressionGzip) {
+		if !s.acceptsGzipBody(r) {
+			writeHTTPError(w, "gzip request bodies require negotiated gzip compression", http.StatusUnsupportedMediaType)
+			return
+		}
+		rawData, err = gunzip(rawData, s.maxBodySize)
+		if errors.Is(err, errBodyTooLarge) {
+			writeHTTPError(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, fmt.Sprintf("invalid gzip request body: %v", err))
+			return
+		}
+	}
 	// First, try to parse as a response (sampling responses don't have a method field)
 	var jsonMessage struct {
 		ID     json.RawMessage `json:"id"`
@@ -522,6 +756,7 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 
 	// Handle sampling responses separately
 	if isSamplingResponse {
+		s.debugPublish(r.header().Get(HeaderKeySessionID), DebugDirectionClientToServer, rawData)
 		if err := s.handleSamplingResponse(w, r, jsonMessage); err != nil {
 			s.logger.Error("Failed to handle sampling response", "err", err)
 			// HTTP Status code is already set in handleSamplingResponse, just return here
@@ -536,7 +771,19 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 	sessionIdManager := s.resolveSessionIdManager(r)
 	if isInitializeRequest {
 		// generate a new one for initialize request
-		sessionID = sessionIdManager.Generate()
+		var err error
+		sessionID, err = generateSessionIDWith(r.ctx(), sessionIdManager)
+		if err != nil {
+			s.logger.Error("Failed to create session", "err", err)
+			writeHTTPError(w, "Failed to create session", http.StatusInternalServerError)
+			return
+		}
+		s.bindClientCertificate(r.ctx(), sessionID)
+		if err := s.bindSessionSubject(r.ctx(), sessionID); err != nil {
+			s.logger.Error("Failed to bind session subject", "err", err)
+			writeHTTPError(w, "Failed to create session", http.StatusInternalServerError)
+			return
+		}
 	} else {
 		// Get session ID from header.
 		// Stateful servers need the client to carry the session ID.
@@ -553,6 +800,7 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 	}
 
 	s.touchSession(sessionID)
+	s.debugPublish(sessionID, DebugDirectionClientToServer, rawData)
 
 	// For non-initialize requests, try to reuse existing registered session
 	var session *streamableHttpSession
@@ -575,9 +823,12 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 	}
 
 	// Create ephemeral session if no persistent session exists
-	if session == nil {
+	ephemeral := session == nil
+	if ephemeral {
 		session = newStreamableHttpSession(sessionID, s.sessionTools, s.sessionResources, s.sessionResourceTemplates, s.sessionLogLevels)
+		session.subjects = s.sessionSubjects
 	}
+	session.setClientCertificate(s.sessionClientCertificate(r.ctx(), sessionID))
 
 	// Set the client context before handling the message
 	ctx := s.server.WithContext(r.ctx(), session)
@@ -589,16 +840,27 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 	mu := sync.Mutex{}
 	upgradedHeader := false
 	done := make(chan struct{})
+	forwarderDone := make(chan struct{})
 
 	ctx = context.WithValue(ctx, requestHeader, r.header())
 
+	// The listening stream of the session may be held by another replica;
+	// send the requests made to the client there.
+	if ephemeral && !isInitializeRequest && s.sessionStore != nil {
+		routeCtx, stopRouting := context.WithCancel(ctx)
+		defer stopRouting()
+		s.routeSessionRequests(routeCtx, session)
+	}
+
 	// SSE upgrades require a streaming-capable response writer; if the
 	// underlying transport can't stream, we still attempt the request but
 	// any notifications will be buffered and flushed at the end as a single
-	// application/json response (the upgrade simply won't fire).
-	canStream := w.CanStream()
+	// application/json response (the upgrade simply won't fire). Cacheable
+	// responses are never streamed.
+	canStream := w.CanStream() && s.httpCache == nil
 
 	go func() {
+		defer close(forwarderDone)
 		defer func() {
 			if r := recover(); r != nil {
 				s.logger.Error("panic in notification forwarder", "panic", r)
@@ -610,12 +872,6 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 				func() {
 					mu.Lock()
 					defer mu.Unlock()
-					// if the done chan is closed, as the request is terminated, just return
-					select {
-					case <-done:
-						return
-					default:
-					}
 					if !canStream {
 						// Without streaming we can't deliver notifications mid-flight;
 						// they will be dropped on the floor here. The final response
@@ -637,6 +893,7 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 						s.logger.Error("Failed to write SSE event", "err", err)
 						return
 					}
+					s.debugPublish(sessionID, DebugDirectionServerToClient, nt)
 				}()
 			case <-done:
 				return
@@ -648,14 +905,14 @@ func (s *StreamableHTTPServer) handlePost(w HTTPResponseWriter, r *HTTPRequest)
 
 	// Process message through MCPServer
 	response := s.server.HandleMessage(ctx, rawData)
+	// Stop the forwarder and wait for it, so a notification it has already
+	// taken from the channel is written before the response rather than
+	// dropped.
+	close(done)
+	<-forwarderDone
 	if response == nil {
-		mu.Lock()
-		close(done)
 		if !upgradedHeader {
-			mu.Unlock()
 			w.WriteHeader(http.StatusAccepted)
-		} else {
-			mu.Unlock()
 		}
 		return
 	}
@@ -680,18 +937,18 @@ drainLoop:
 			if err := writeSSEEvent(w, nt); err != nil {
 				s.logger.Error("Failed to write SSE event during drain", "err", err)
 			}
+			s.debugPublish(sessionID, DebugDirectionServerToClient, nt)
 			w.Flush()
 		default:
 			break drainLoop
 		}
 	}
 
-	// close the done chan before unlocking to signal the goroutine to stop
-	close(done)
 	mu.Unlock()
 	if ctx.Err() != nil {
 		return
 	}
+	s.debugPublish(sessionID, DebugDirectionServerToClient, response)
 	// If client-server communication already upgraded to SSE stream
 	// Also check upgradedHeader: a notification during HandleMessage processing
 	// may have already written SSE headers on this response, so we must continue
@@ -713,9 +970,10 @@ drainLoop:
 			// send the session ID back to the client
 			w.Header().Set(HeaderKeySessionID, sessionID)
 		}
-		w.WriteHeader(http.StatusOK)
-		err := json.NewEncoder(w).Encode(response)
-		if err != nil {
+		if s.httpCache != nil && s.writeCacheHeaders(w, r, jsonMessage.Method, rawData, response) {
+			return
+		}
+		if err := writeEncodedJSON(w, r, session.Encoding(), response); err != nil {
 			s.logger.Error("Failed to write response", "err", err)
 		}
 	}
@@ -739,6 +997,10 @@ drainLoop:
 func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 	// get request is for listening to notifications
 	// https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#listening-for-messages-from-the-server
+	if s.httpCache != nil {
+		s.handleCacheableGet(w, r)
+		return
+	}
 	if s.disableStreaming {
 		s.logger.Info("Rejected GET request: streaming is disabled", "session", r.header().Get(HeaderKeySessionID))
 		writeHTTPError(w, "Streaming is disabled on this server", http.StatusMethodNotAllowed)
@@ -758,16 +1020,48 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 	// If no session ID is provided by the client, generate one using the configured SessionIdManager
 	// so that custom session id generators are honored consistently across POST/GET flows.
 	if sessionID == "" {
-		sessionIdManager := s.resolveSessionIdManager(r)
-		sessionID = sessionIdManager.Generate()
+		var err error
+		sessionID, err = generateSessionIDWith(r.ctx(), s.resolveSessionIdManager(r))
+		if err != nil {
+			s.logger.Error("Failed to create session", "err", err)
+			writeHTTPError(w, "Failed to create session", http.StatusInternalServerError)
+			return
+		}
+		s.bindClientCertificate(r.ctx(), sessionID)
+		if err := s.bindSessionSubject(r.ctx(), sessionID); err != nil {
+			s.logger.Error("Failed to bind session subject", "err", err)
+			writeHTTPError(w, "Failed to create session", http.StatusInternalServerError)
+			return
+		}
 	}
 
 	// Get or create session atomically to prevent TOCTOU races
 	// where concurrent GETs could both create and register duplicate sessions
 	var session *streamableHttpSession
-	newSession := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionResources, s.sessionResourceTemplates, s.sessionLogLevels)
+	// A client reconnecting within the redelivery window resumes its
+	// previous session, including the requests it has not answered yet.
+	newSession := s.reattachSession(sessionID)
+	if newSession == nil {
+		newSession = newStreamableHttpSession(sessionID, s.sessionTools, s.sessionResources, s.sessionResourceTemplates, s.sessionLogLevels)
+		newSession.subjects = s.sessionSubjects
+	}
 	actual, loaded := s.activeSessions.LoadOrStore(sessionID, newSession)
 	session = actual.(*streamableHttpSession)
+	session.setClientCertificate(s.sessionClientCertificate(r.ctx(), sessionID))
+	if loaded && actual != newSession {
+		newSession.failUnanswered(undeliverable(ErrSessionClosed))
+	}
+
+	// Only one listening stream at a time resends the unanswered requests
+	// and detaches the session when it ends, whether the session was
+	// created by this GET or by an initialize POST.
+	redelivers := session.listening.CompareAndSwap(false, true)
+	if redelivers {
+		defer func() {
+			session.listening.Store(false)
+			s.detachSession(session)
+		}()
+	}
 
 	if !loaded {
 		// We created a new session, need to register it
@@ -781,6 +1075,10 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 		defer s.sessionRequestIDs.Delete(sessionID)
 	}
 
+	if s.sessionStore != nil {
+		s.forwardSessionStoreMessages(r.ctx(), session)
+	}
+
 	s.touchSession(sessionID)
 
 	// Set the client context before handling the message
@@ -790,6 +1088,11 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 	w.WriteHeader(http.StatusOK)
 
 	w.Flush()
+	defer s.server.metrics.trackSSEConnection("streamable_http")()
+
+	if lastEventID := r.header().Get(HeaderKeyLastEventID); lastEventID != "" && s.eventStore != nil {
+		s.replayStreamEvents(r.ctx(), w, sessionID, lastEventID)
+	}
 
 	// Start notification handler for this session
 	done := make(chan struct{})
@@ -802,6 +1105,16 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 				s.logger.Error("panic in SSE notification writer", "panic", r)
 			}
 		}()
+		// Send the requests left unanswered by a dropped stream again.
+		if redelivers {
+			for _, request := range session.unansweredRequests() {
+				select {
+				case writeChan <- request:
+				case <-done:
+					return
+				}
+			}
+		}
 		for {
 			select {
 			case nt := <-session.notificationChannel:
@@ -822,6 +1135,7 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 				}
 				select {
 				case writeChan <- jsonrpcRequest:
+					session.markSent(samplingReq.requestID, samplingReq.sent, jsonrpcRequest)
 				case <-done:
 					return
 				}
@@ -837,6 +1151,7 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 				}
 				select {
 				case writeChan <- jsonrpcRequest:
+					session.markSent(elicitationReq.requestID, elicitationReq.sent, jsonrpcRequest)
 				case <-done:
 					return
 				}
@@ -851,6 +1166,7 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 				}
 				select {
 				case writeChan <- jsonrpcRequest:
+					session.markSent(rootsReq.requestID, rootsReq.sent, jsonrpcRequest)
 				case <-done:
 					return
 				}
@@ -904,10 +1220,11 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 			if data == nil {
 				continue
 			}
-			if err := writeSSEEvent(w, data); err != nil {
+			if err := s.writeStreamEvent(ctx, w, sessionID, data); err != nil {
 				s.logger.Error("Failed to write SSE event", "err", err)
 				return
 			}
+			s.debugPublish(sessionID, DebugDirectionServerToClient, data)
 			w.Flush()
 			s.touchSession(sessionID)
 		case <-ctx.Done():
@@ -918,6 +1235,10 @@ func (s *StreamableHTTPServer) handleGet(w HTTPResponseWriter, r *HTTPRequest) {
 
 func (s *StreamableHTTPServer) handleDelete(w HTTPResponseWriter, r *HTTPRequest) {
 	// delete request terminate the session
+	if s.httpCache != nil {
+		writeHTTPError(w, "Sessions are disabled on this server", http.StatusMethodNotAllowed)
+		return
+	}
 	sessionID := r.header().Get(HeaderKeySessionID)
 	sessionIdManager := s.resolveSessionIdManager(r)
 	notAllowed, err := sessionIdManager.Terminate(sessionID)
@@ -1021,6 +1342,22 @@ func (s *StreamableHTTPServer) handleSamplingResponse(w HTTPResponseWriter, r *H
 func (s *StreamableHTTPServer) deliverSamplingResponse(w HTTPResponseWriter, sessionID string, response samplingResponseItem) error {
 	// Look up the active session
 	sessionInterface, ok := s.activeSessions.Load(sessionID)
+	if !ok && s.sessionStore != nil {
+		// The listening stream may be held by another replica.
+		routed := &sessionStoreResponse{RequestID: response.requestID, Result: response.result}
+		if response.err != nil {
+			routed.Error = response.err.Error()
+		}
+		if err := s.publishToSessionStore(context.Background(), sessionID, sessionStoreMessage{Response: routed}); err != nil {
+			if errors.Is(err, ErrNoSessionSubscriber) {
+				writeHTTPError(w, "No active session found for the given session ID", http.StatusNotFound)
+			} else {
+				writeHTTPError(w, "Failed to route response", http.StatusInternalServerError)
+			}
+			return fmt.Errorf("failed to route response for session %s: %w", sessionID, err)
+		}
+		return nil
+	}
 	if !ok {
 		writeHTTPError(w, "No active session found for the given session ID", http.StatusNotFound)
 		return fmt.Errorf("no active session found for session %s", sessionID)
@@ -1086,6 +1423,12 @@ func (s *StreamableHTTPServer) touchSession(sessionID string) {
 	actual.(*atomic.Int64).Store(now)
 }
 
+// hasSession reports whether sessionID is an active session of s.
+func (s *StreamableHTTPServer) hasSession(sessionID string) bool {
+	_, ok := s.activeSessions.Load(sessionID)
+	return ok
+}
+
 // cleanupSessionState removes all per-session transport state for the given session ID.
 func (s *StreamableHTTPServer) cleanupSessionState(ctx context.Context, sessionID string) {
 	// Unregister first to stop notification routing before deleting data.
@@ -1097,6 +1440,13 @@ func (s *StreamableHTTPServer) cleanupSessionState(ctx context.Context, sessionI
 	s.sessionLogLevels.delete(sessionID)
 	s.sessionRequestIDs.Delete(sessionID)
 	s.sessionLastActive.Delete(sessionID)
+	s.sessionBandwidth.Delete(sessionID)
+	s.sessionCertificates.Delete(sessionID)
+	s.sessionSubjects.delete(sessionID)
+	s.dropDetachedSession(sessionID)
+	if store, ok := s.eventStore.(interface{ DeleteStream(string) }); ok {
+		store.DeleteStream(sessionID)
+	}
 }
 
 // startSessionSweeper launches a background goroutine that periodically removes
@@ -1302,6 +1652,7 @@ func (s *sessionToolsStore) delete(sessionID string) {
 // Sampling support types for HTTP transport
 type samplingRequestItem struct {
 	requestID int64
+	sent      *atomic.Bool // set once a stream to the client picked the request up
 	request   mcp.CreateMessageRequest
 	response  chan samplingResponseItem
 }
@@ -1315,6 +1666,7 @@ type samplingResponseItem struct {
 // Elicitation support types for HTTP transport
 type elicitationRequestItem struct {
 	requestID int64
+	sent      *atomic.Bool // set once a stream to the client picked the request up
 	request   mcp.ElicitationRequest
 	response  chan samplingResponseItem
 }
@@ -1322,6 +1674,7 @@ type elicitationRequestItem struct {
 // Roots support types for HTTP transport
 type rootsRequestItem struct {
 	requestID int64
+	sent      *atomic.Bool // set once a stream to the client picked the request up
 	request   mcp.ListRootsRequest
 	response  chan samplingResponseItem
 }
@@ -1330,9 +1683,13 @@ type rootsRequestItem struct {
 // When in POST handlers(request/notification), it's ephemeral, and only exists in the life of the request handler.
 // When in GET handlers(listening), it's a real session, and will be registered in the MCP server.
 type streamableHttpSession struct {
-	clientInfoStore // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	clientInfoStore      // provides Get/SetClientInfo and Get/SetClientCapabilities via method promotion
+	extensionStore       // provides Extension, Extensions and SetExtensions via method promotion
+	connectionStateStore // provides ConnectionState, OnConnectionStateChange and SetConnectionState via method promotion
+	clientCertificateStore
 
 	sessionID           string
+	subjects            *sessionSubjectsStore
 	notificationChannel chan mcp.JSONRPCNotification // server -> client notifications
 	tools               *sessionToolsStore
 	resources           *sessionResourcesStore
@@ -1346,7 +1703,11 @@ type streamableHttpSession struct {
 	rootsRequestChan       chan rootsRequestItem       // server -> client list roots requests
 
 	samplingRequests sync.Map     // requestID -> pending sampling request context
+	unanswered       sync.Map     // requestID -> mcp.JSONRPCRequest sent on the listening stream
+	listening        atomic.Bool  // set while a listening stream owns redelivery
 	requestIDCounter atomic.Int64 // for generating unique request IDs
+
+	encoding atomic.Value // mcp.EncodingSelection negotiated at initialize
 }
 
 func newStreamableHttpSession(sessionID string, toolStore *sessionToolsStore, resourcesStore *sessionResourcesStore, templatesStore *sessionResourceTemplatesStore, levels *sessionLogLevelsStore) *streamableHttpSession {
@@ -1392,6 +1753,24 @@ func (s *streamableHttpSession) GetLogLevel() mcp.LoggingLevel {
 
 var _ ClientSession = (*streamableHttpSession)(nil)
 
+// Subject returns the subject of the identity the session is bound to.
+func (s *streamableHttpSession) Subject() string {
+	if s.subjects == nil {
+		return ""
+	}
+	subject, _ := s.subjects.get(s.sessionID)
+	return subject
+}
+
+// SetSubject binds the session to subject, sharing the binding with the
+// other replicas through the session store when it supports it.
+func (s *streamableHttpSession) SetSubject(subject string) error {
+	if s.subjects == nil {
+		return nil
+	}
+	return s.subjects.bind(context.Background(), s.sessionID, subject)
+}
+
 func (s *streamableHttpSession) GetSessionTools() map[string]ServerTool {
 	return s.tools.get(s.sessionID)
 }
@@ -1422,6 +1801,9 @@ var (
 	_ SessionWithResourceTemplates = (*streamableHttpSession)(nil)
 	_ SessionWithLogging           = (*streamableHttpSession)(nil)
 	_ SessionWithClientInfo        = (*streamableHttpSession)(nil)
+	_ SessionWithExtensions        = (*streamableHttpSession)(nil)
+	_ SessionWithConnectionState   = (*streamableHttpSession)(nil)
+	_ SessionWithSubject           = (*streamableHttpSession)(nil)
 )
 
 func (s *streamableHttpSession) UpgradeToSSEWhenReceiveNotification() {
@@ -1430,10 +1812,31 @@ func (s *streamableHttpSession) UpgradeToSSEWhenReceiveNotification() {
 
 var _ SessionWithStreamableHTTPConfig = (*streamableHttpSession)(nil)
 
+func (s *streamableHttpSession) SupportedEncodings() mcp.EncodingOffer {
+	return mcp.EncodingOffer{
+		Compression: []string{mcp.CompressionGzip, mcp.CompressionIdentity},
+		Codecs:      []string{mcp.CodecJSON},
+	}
+}
+
+func (s *streamableHttpSession) SetEncoding(encoding mcp.EncodingSelection) {
+	s.encoding.Store(encoding)
+}
+
+func (s *streamableHttpSession) Encoding() mcp.EncodingSelection {
+	if encoding, ok := s.encoding.Load().(mcp.EncodingSelection); ok {
+		return encoding
+	}
+	return mcp.DefaultEncoding
+}
+
+var _ SessionWithEncoding = (*streamableHttpSession)(nil)
+
 // RequestSampling implements SessionWithSampling interface for HTTP transport
 func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
 	// Generate unique request ID
 	requestID := s.requestIDCounter.Add(1)
+	sent := new(atomic.Bool)
 
 	// Create response channel for this specific request
 	responseChan := make(chan samplingResponseItem, 1)
@@ -1441,6 +1844,7 @@ func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp
 	// Create the sampling request item
 	samplingRequest := samplingRequestItem{
 		requestID: requestID,
+		sent:      sent,
 		request:   request,
 		response:  responseChan,
 	}
@@ -1448,6 +1852,7 @@ func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp
 	// Store the pending request
 	s.samplingRequests.Store(requestID, responseChan)
 	defer s.samplingRequests.Delete(requestID)
+	defer s.unanswered.Delete(requestID)
 
 	// Send the sampling request via the channel (non-blocking)
 	select {
@@ -1456,7 +1861,7 @@ func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
-		return nil, fmt.Errorf("sampling request queue is full - server overloaded")
+		return nil, undeliverable(fmt.Errorf("sampling %w - server overloaded", ErrRequestQueueFull))
 	}
 
 	// Wait for response or context cancellation
@@ -1482,6 +1887,10 @@ func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp
 
 		return &result, nil
 	case <-ctx.Done():
+		if !sent.Load() {
+			return nil, fmt.Errorf("%w: %w: %w", ErrRequestUndeliverable, ErrNoClientStream, ctx.Err())
+		}
+		notifyRequestCancelled(s, requestID, ctx.Err())
 		return nil, ctx.Err()
 	}
 }
@@ -1491,6 +1900,7 @@ func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp
 func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
 	// Generate unique request ID
 	requestID := s.requestIDCounter.Add(1)
+	sent := new(atomic.Bool)
 
 	// Create response channel for this specific request
 	responseChan := make(chan samplingResponseItem, 1)
@@ -1498,6 +1908,7 @@ func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListR
 	// Create the roots request item
 	rootsRequest := rootsRequestItem{
 		requestID: requestID,
+		sent:      sent,
 		request:   request,
 		response:  responseChan,
 	}
@@ -1505,6 +1916,7 @@ func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListR
 	// Store the pending request
 	s.samplingRequests.Store(requestID, responseChan)
 	defer s.samplingRequests.Delete(requestID)
+	defer s.unanswered.Delete(requestID)
 
 	// Send the list roots request via the channel (non-blocking)
 	select {
@@ -1513,7 +1925,7 @@ func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListR
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
-		return nil, fmt.Errorf("list roots request queue is full - server overloaded")
+		return nil, undeliverable(fmt.Errorf("list roots %w - server overloaded", ErrRequestQueueFull))
 	}
 
 	// Wait for response or context cancellation
@@ -1528,6 +1940,9 @@ func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListR
 		}
 		return &result, nil
 	case <-ctx.Done():
+		if !sent.Load() {
+			return nil, fmt.Errorf("%w: %w: %w", ErrRequestUndeliverable, ErrNoClientStream, ctx.Err())
+		}
 		return nil, ctx.Err()
 	}
 }
@@ -1536,6 +1951,7 @@ func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListR
 func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
 	// Generate unique request ID
 	requestID := s.requestIDCounter.Add(1)
+	sent := new(atomic.Bool)
 
 	// Create response channel for this specific request
 	responseChan := make(chan samplingResponseItem, 1)
@@ -1543,6 +1959,7 @@ func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request
 	// Create the sampling request item
 	elicitationRequest := elicitationRequestItem{
 		requestID: requestID,
+		sent:      sent,
 		request:   request,
 		response:  responseChan,
 	}
@@ -1550,6 +1967,7 @@ func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request
 	// Store the pending request
 	s.samplingRequests.Store(requestID, responseChan)
 	defer s.samplingRequests.Delete(requestID)
+	defer s.unanswered.Delete(requestID)
 
 	// Send the sampling request via the channel (non-blocking)
 	select {
@@ -1558,7 +1976,7 @@ func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
-		return nil, fmt.Errorf("elicitation request queue is full - server overloaded")
+		return nil, undeliverable(fmt.Errorf("elicitation %w - server overloaded", ErrRequestQueueFull))
 	}
 
 	// Wait for response or context cancellation
@@ -1573,6 +1991,9 @@ func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request
 		}
 		return &result, nil
 	case <-ctx.Done():
+		if !sent.Load() {
+			return nil, fmt.Errorf("%w: %w: %w", ErrRequestUndeliverable, ErrNoClientStream, ctx.Err())
+		}
 		return nil, ctx.Err()
 	}
 }
@@ -1590,7 +2011,16 @@ type SessionIdManagerResolver interface {
 	ResolveSessionIdManager(r *http.Request) SessionIdManager
 }
 
+// SessionIdManager generates, validates and terminates the Mcp-Session-Id
+// values used by StreamableHTTPServer. Implement it to back session IDs with
+// JWTs, a shared store such as Redis, or any other scheme, and install it with
+// WithSessionIdManager. Implementations must be safe for concurrent use.
+//
+// Built-in implementations are StatelessSessionIdManager,
+// StatelessGeneratingSessionIdManager, InsecureStatefulSessionIdManager and
+// HMACSessionIdManager.
 type SessionIdManager interface {
+	// Generate returns a new session ID. An empty string disables sessions.
 	Generate() string
 	// Validate checks if a session ID is valid and not terminated.
 	// Returns isTerminated=true if the ID is valid but belongs to a terminated session.
@@ -1602,6 +2032,26 @@ type SessionIdManager interface {
 	Terminate(sessionID string) (isNotAllowed bool, err error)
 }
 
+// FallibleSessionIdManager is implemented by SessionIdManagers whose ID
+// generation can fail, such as those backed by a shared store. The transport
+// generates IDs with GenerateSessionID instead of Generate and rejects the
+// request if it fails, rather than treating an empty ID as stateless.
+type FallibleSessionIdManager interface {
+	SessionIdManager
+	// GenerateSessionID returns a new session ID, or an error if none could
+	// be created.
+	GenerateSessionID(ctx context.Context) (string, error)
+}
+
+// generateSessionIDWith returns a new session ID from manager, using
+// GenerateSessionID if the manager implements FallibleSessionIdManager.
+func generateSessionIDWith(ctx context.Context, manager SessionIdManager) (string, error) {
+	if fallible, ok := manager.(FallibleSessionIdManager); ok {
+		return fallible.GenerateSessionID(ctx)
+	}
+	return manager.Generate(), nil
+}
+
 // DefaultSessionIdManagerResolver is a simple resolver that returns the same SessionIdManager for all requests
 type DefaultSessionIdManagerResolver struct {
 	manager SessionIdManager
@@ -1638,18 +2088,17 @@ func (s *StatelessSessionIdManager) Terminate(sessionID string) (isNotAllowed bo
 
 // StatelessGeneratingSessionIdManager generates session IDs but doesn't validate them locally.
 // This allows session IDs to be generated for clients while working across multiple instances.
-type StatelessGeneratingSessionIdManager struct{}
+type StatelessGeneratingSessionIdManager struct {
+	newID func() string // set from the MCPServer's IDSource, if any
+}
 
 func (s *StatelessGeneratingSessionIdManager) Generate() string {
-	return idPrefix + uuid.New().String()
+	return generateSessionID(s.newID)
 }
 
 func (s *StatelessGeneratingSessionIdManager) Validate(sessionID string) (isTerminated bool, err error) {
 	// Only validate format, not existence - allows cross-instance operation
-	if !strings.HasPrefix(sessionID, idPrefix) {
-		return false, fmt.Errorf("invalid session id: %s", sessionID)
-	}
-	if _, err := uuid.Parse(sessionID[len(idPrefix):]); err != nil {
+	if !validSessionIDFormat(sessionID, s.newID) {
 		return false, fmt.Errorf("invalid session id: %s", sessionID)
 	}
 	return false, nil
@@ -1666,21 +2115,42 @@ func (s *StatelessGeneratingSessionIdManager) Terminate(sessionID string) (isNot
 type InsecureStatefulSessionIdManager struct {
 	sessions   sync.Map
 	terminated sync.Map
+	newID      func() string // set from the MCPServer's IDSource, if any
 }
 
 const idPrefix = "mcp-session-"
 
+// generateSessionID returns a new prefixed session ID, using newID for the
+// suffix when set and a random UUID otherwise.
+func generateSessionID(newID func() string) string {
+	if newID != nil {
+		return idPrefix + newID()
+	}
+	return idPrefix + uuid.New().String()
+}
+
+// validSessionIDFormat reports whether sessionID has the format produced by
+// generateSessionID. IDs from a custom source are only checked for the
+// prefix.
+func validSessionIDFormat(sessionID string, newID func() string) bool {
+	if !strings.HasPrefix(sessionID, idPrefix) {
+		return false
+	}
+	if newID != nil {
+		return len(sessionID) > len(idPrefix)
+	}
+	_, err := uuid.Parse(sessionID[len(idPrefix):])
+	return err == nil
+}
+
 func (s *InsecureStatefulSessionIdManager) Generate() string {
-	sessionID := idPrefix + uuid.New().String()
+	sessionID := generateSessionID(s.newID)
 	s.sessions.Store(sessionID, true)
 	return sessionID
 }
 
 func (s *InsecureStatefulSessionIdManager) Validate(sessionID string) (isTerminated bool, err error) {
-	if !strings.HasPrefix(sessionID, idPrefix) {
-		return false, fmt.Errorf("invalid session id: %s", sessionID)
-	}
-	if _, err := uuid.Parse(sessionID[len(idPrefix):]); err != nil {
+	if !validSessionIDFormat(sessionID, s.newID) {
 		return false, fmt.Errorf("invalid session id: %s", sessionID)
 	}
 	if _, exists := s.terminated.Load(sessionID); exists {
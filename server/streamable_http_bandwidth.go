@@ -0,0 +1,80 @@
+package server
+
+import (
+	"io"
+
+	"github.com/mark3labs/mcp-go/internal/bandwidth"
+)
+
+// WithStreamableHTTPBandwidthLimit caps the bytes per second each session
+// may send to the server in request bodies and receive in responses and
+// SSE streams, for example when MCP shares a constrained link with other
+// traffic. Responses beyond the limit are paced, so a tool streaming a
+// large output slows down its own session only. Limits apply once the
+// request is authenticated. Requests without a session ID, such as
+// initialize, and requests naming a session this server does not hold are
+// limited individually. Zero leaves a direction unlimited.
+func WithStreamableHTTPBandwidthLimit(readBytesPerSecond, writeBytesPerSecond int) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.readLimit = readBytesPerSecond
+		s.writeLimit = writeBytesPerSecond
+	}
+}
+
+// sessionBandwidth holds the limiters shared by the requests of a session.
+type sessionBandwidth struct {
+	read  *bandwidth.Limiter
+	write *bandwidth.Limiter
+}
+
+// throttle applies the bandwidth limits of the request's session: it waits
+// until the request body fits the read limit and returns a writer that
+// paces the response. The error is that of the request context when the
+// client goes away while waiting.
+func (s *StreamableHTTPServer) throttle(w HTTPResponseWriter, r *HTTPRequest) (HTTPResponseWriter, error) {
+	if s.readLimit <= 0 && s.writeLimit <= 0 {
+		return w, nil
+	}
+	limits := &sessionBandwidth{
+		read:  bandwidth.NewLimiter(s.readLimit),
+		write: bandwidth.NewLimiter(s.writeLimit),
+	}
+	// Only sessions registered with the server share limits, so that
+	// made-up session IDs cannot grow the map: its entries are removed when
+	// the session is cleaned up.
+	if sessionID := r.header().Get(HeaderKeySessionID); sessionID != "" && s.sessionRegistered(sessionID) {
+		actual, _ := s.sessionBandwidth.LoadOrStore(sessionID, limits)
+		limits = actual.(*sessionBandwidth)
+		if !s.sessionRegistered(sessionID) {
+			// The session was cleaned up meanwhile.
+			s.sessionBandwidth.Delete(sessionID)
+		}
+	}
+	if err := limits.read.WaitN(r.ctx(), len(r.Body)); err != nil {
+		return nil, err
+	}
+	if limits.write == nil {
+		return w, nil
+	}
+	return &throttledResponseWriter{
+		HTTPResponseWriter: w,
+		out:                bandwidth.NewWriter(r.ctx(), w, limits.write),
+	}, nil
+}
+
+// throttledResponseWriter paces the bytes written to an HTTPResponseWriter.
+type throttledResponseWriter struct {
+	HTTPResponseWriter
+	out io.Writer
+}
+
+func (w *throttledResponseWriter) Write(p []byte) (int, error) {
+	return w.out.Write(p)
+}
+
+// sessionRegistered reports whether sessionID is a session registered with
+// the server.
+func (s *StreamableHTTPServer) sessionRegistered(sessionID string) bool {
+	_, ok := s.server.sessions.Load(sessionID)
+	return ok
+}
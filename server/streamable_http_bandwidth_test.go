@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamableHTTP_BandwidthLimit(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("dump"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("x", 4000)), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer, WithStreamableHTTPBandwidthLimit(0, 5000))
+	defer server.Close()
+
+	call := func(t *testing.T, sessionID string) time.Duration {
+		t.Helper()
+		start := time.Now()
+		resp, err := postSessionJSON(server.URL, sessionID, map[string]any{
+			"jsonrpc": "2.0", "id": 2, "method": "tools/call",
+			"params": map[string]any{"name": "dump"},
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Greater(t, len(body), 4000)
+		return time.Since(start)
+	}
+	initialize := func(t *testing.T) string {
+		t.Helper()
+		resp, err := postJSON(server.URL, initRequest)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.Header.Get(HeaderKeySessionID)
+	}
+
+	first := initialize(t)
+	// The first response fits in the one second burst, the second has to
+	// wait for the session's budget to refill.
+	assert.Less(t, call(t, first), 300*time.Millisecond)
+	assert.GreaterOrEqual(t, call(t, first), 500*time.Millisecond)
+
+	second := initialize(t)
+	assert.Less(t, call(t, second), 300*time.Millisecond, "sessions have their own budget")
+}
+
+func TestStreamableHTTP_BandwidthLimitUnknownSessions(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	streamable := NewStreamableHTTPServer(mcpServer, WithStreamableHTTPBandwidthLimit(5000, 5000))
+	server := httptest.NewServer(streamable)
+	defer server.Close()
+
+	for i := range 20 {
+		resp, err := postSessionJSON(server.URL, fmt.Sprintf("made-up-%d", i), map[string]any{
+			"jsonrpc": "2.0", "id": 1, "method": "ping",
+		})
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+	entries := 0
+	streamable.sessionBandwidth.Range(func(any, any) bool {
+		entries++
+		return true
+	})
+	assert.Zero(t, entries, "session IDs the server does not hold get no limiter")
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HTTPCacheConfig configures WithHTTPCaching.
+type HTTPCacheConfig struct {
+	// ListMaxAge is how long caches may reuse responses to initialize,
+	// tools/list, resources/list, resources/templates/list and
+	// prompts/list. Zero marks them no-store.
+	ListMaxAge time.Duration
+}
+
+// WithHTTPCaching makes the server suitable for fronting with an HTTP cache
+// such as a CDN. It is meant for anonymous, read-only servers whose answers
+// do not depend on who asks.
+//
+// Session mechanics are removed entirely: no Mcp-Session-Id is issued or
+// checked, DELETE is answered with 405 Method Not Allowed, and responses are
+// always a single application/json body, never an SSE stream, so
+// notifications sent while handling a request are dropped. This replaces
+// any session ID manager set by earlier options.
+//
+// Successful responses carry an ETag and a Cache-Control header: list
+// methods use config.ListMaxAge, tools/call uses the tool's
+// mcp.ToolCacheability when the result is not an error, and everything else
+// is no-store. The ETag covers only the result, so it does not depend on
+// the request ID, and list results are sorted, so identical requests get
+// identical results. A request whose If-None-Match matches the response's
+// ETag is answered with 304 Not Modified.
+//
+// Responses to POST requests echo the caller's request ID, so they are only
+// ever marked private. Shared caches are served by the GET form instead:
+//
+//	GET /mcp?method=tools/call&params={"name":"forecast","arguments":{"city":"Paris"}}
+//
+// The method query parameter names a list method or tools/call, and params
+// holds the JSON params, if any. Only tools declaring an
+// mcp.ToolCacheability can be called this way. The request is handled with
+// the fixed ID 0, so every client gets the same response, which is marked
+// public unless the tool's cacheability is private, the request has an
+// Authorization header or a client certificate, or the server has an
+// authenticator set with WithAuthenticator. GET requests without a method parameter are
+// answered with 405 Method Not Allowed.
+func WithHTTPCaching(config HTTPCacheConfig) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.httpCache = &config
+		s.disableStreaming = true
+		s.sessionIdManagerResolver = NewDefaultSessionIdManagerResolver(&StatelessSessionIdManager{})
+	}
+}
+
+// cacheableListMethods are the methods whose responses use ListMaxAge.
+var cacheableListMethods = map[mcp.MCPMethod]bool{
+	mcp.MethodInitialize:             true,
+	mcp.MethodToolsList:              true,
+	mcp.MethodResourcesList:          true,
+	mcp.MethodResourcesTemplatesList: true,
+	mcp.MethodPromptsList:            true,
+}
+
+// cacheRequestID is the JSON-RPC ID of the requests made with the GET form
+// of WithHTTPCaching.
+var cacheRequestID = mcp.NewRequestId(int64(0))
+
+// handleCacheableGet serves the GET form of WithHTTPCaching by handling the
+// request named by the query parameters like a POST with ID cacheRequestID.
+func (s *StreamableHTTPServer) handleCacheableGet(w HTTPResponseWriter, r *HTTPRequest) {
+	var query url.Values
+	if r.URL != nil {
+		query = r.URL.Query()
+	}
+	method := mcp.MCPMethod(query.Get("method"))
+	if method == "" {
+		writeHTTPError(w, "Streaming is disabled on this server", http.StatusMethodNotAllowed)
+		return
+	}
+	var params json.RawMessage
+	if raw := query.Get("params"); raw != "" {
+		if !json.Valid([]byte(raw)) {
+			writeHTTPError(w, "Invalid params: must be JSON", http.StatusBadRequest)
+			return
+		}
+		params = json.RawMessage(raw)
+	}
+	if !s.cacheableOverGet(method, params) {
+		writeHTTPErrorf(w, http.StatusBadRequest, "Method %s cannot be requested with GET", method)
+		return
+	}
+	body, err := json.Marshal(mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      cacheRequestID,
+		Params:  params,
+		Request: mcp.Request{Method: string(method)},
+	})
+	if err != nil {
+		writeHTTPError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	header := r.header().Clone()
+	header.Set("Content-Type", "application/json")
+	header.Del("Content-Encoding")
+	s.handlePost(w, &HTTPRequest{
+		Method:    http.MethodPost,
+		URL:       r.URL,
+		Header:    header,
+		Body:      body,
+		Context:   r.Context,
+		original:  r.original,
+		shareable: true,
+	})
+}
+
+// cacheableOverGet reports whether method may be requested with the GET
+// form: list methods always can, tools/call only for tools that declare
+// their cacheability.
+func (s *StreamableHTTPServer) cacheableOverGet(method mcp.MCPMethod, params json.RawMessage) bool {
+	if cacheableListMethods[method] {
+		return true
+	}
+	if method != mcp.MethodToolsCall || params == nil {
+		return false
+	}
+	var call struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return false
+	}
+	tool := s.server.GetTool(call.Name)
+	if tool == nil {
+		return false
+	}
+	_, ok := mcp.ToolCacheabilityFromTool(tool.Tool)
+	return ok
+}
+
+// writeCacheHeaders sets the Cache-Control, ETag and Vary headers of the
+// response to a request for method. It reports true when it answered the
+// request with 304 Not Modified, in which case no body must be written.
+func (s *StreamableHTTPServer) writeCacheHeaders(w HTTPResponseWriter, r *HTTPRequest, method mcp.MCPMethod, rawData []byte, response mcp.JSONRPCMessage) bool {
+	maxAge, private := s.cacheLifetime(method, rawData, response)
+	if maxAge <= 0 {
+		w.Header().Set("Cache-Control", "no-store")
+		return false
+	}
+	// The ETag covers the result alone, so it stays the same whatever ID
+	// the client chose.
+	result, err := json.Marshal(response.(mcp.JSONRPCResponse).Result)
+	if err != nil {
+		w.Header().Set("Cache-Control", "no-store")
+		return false
+	}
+
+	visibility := "public"
+	if private || !r.shareable || s.callerIdentified(r) {
+		visibility = "private"
+	}
+	sum := sha256.Sum256(result)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int64(maxAge/time.Second)))
+	w.Header().Set("ETag", etag)
+	w.Header().Add("Vary", "Authorization")
+
+	if etagMatches(r.header().Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// callerIdentified reports whether r may carry credentials the response
+// depends on: an Authorization header, a client certificate, or anything
+// the server's authenticator looks at, which can be any header.
+func (s *StreamableHTTPServer) callerIdentified(r *HTTPRequest) bool {
+	if s.server.authenticator != nil || r.header().Get("Authorization") != "" {
+		return true
+	}
+	return r.original != nil && r.original.TLS != nil && len(r.original.TLS.PeerCertificates) > 0
+}
+
+// cacheLifetime returns how long the response to a request for method may be
+// reused, and whether only the calling client may reuse it.
+func (s *StreamableHTTPServer) cacheLifetime(method mcp.MCPMethod, rawData []byte, response mcp.JSONRPCMessage) (time.Duration, bool) {
+	result, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		return 0, false
+	}
+	if cacheableListMethods[method] {
+		return s.httpCache.ListMaxAge, false
+	}
+	if method != mcp.MethodToolsCall {
+		return 0, false
+	}
+	switch r := result.Result.(type) {
+	case *mcp.CallToolResult:
+		if r == nil || r.IsError {
+			return 0, false
+		}
+	case mcp.CallToolResult:
+		if r.IsError {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	var request struct {
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(rawData, &request); err != nil {
+		return 0, false
+	}
+	tool := s.server.GetTool(request.Params.Name)
+	if tool == nil {
+		return 0, false
+	}
+	cacheability, ok := mcp.ToolCacheabilityFromTool(tool.Tool)
+	if !ok {
+		return 0, false
+	}
+	return cacheability.MaxAge(), cacheability.Private
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamableHTTP_HTTPCaching(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("forecast", mcp.WithCacheability(5*time.Minute)),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			_ = ServerFromContext(ctx).SendNotificationToClient(ctx, "notifications/message", nil)
+			return mcp.NewToolResultText("sunny"), nil
+		})
+	mcpServer.AddTool(mcp.NewTool("roll"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("4"), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("broken", mcp.WithCacheability(time.Minute)),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("upstream down"), nil
+		})
+	server := NewTestStreamableHTTPServer(mcpServer, WithHTTPCaching(HTTPCacheConfig{ListMaxAge: time.Minute}))
+	defer server.Close()
+
+	post := func(t *testing.T, body any, header http.Header) (*http.Response, []byte) {
+		t.Helper()
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, respBody
+	}
+	call := func(name string) map[string]any {
+		return map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": map[string]any{"name": name}}
+	}
+
+	t.Run("initialize issues no session", func(t *testing.T) {
+		resp, _ := post(t, initRequest, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(HeaderKeySessionID))
+		assert.Equal(t, "private, max-age=60", resp.Header.Get("Cache-Control"))
+	})
+
+	t.Run("list responses are identical and revalidate", func(t *testing.T) {
+		list := map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}
+		first, firstBody := post(t, list, nil)
+		second, secondBody := post(t, list, nil)
+		assert.Equal(t, firstBody, secondBody)
+		etag := first.Header.Get("ETag")
+		require.NotEmpty(t, etag)
+		assert.Equal(t, etag, second.Header.Get("ETag"))
+
+		other, _ := post(t, map[string]any{"jsonrpc": "2.0", "id": "other", "method": "tools/list"}, nil)
+		assert.Equal(t, etag, other.Header.Get("ETag"), "the ETag must not depend on the request ID")
+
+		resp, body := post(t, list, http.Header{"If-None-Match": {etag}})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Empty(t, body)
+	})
+
+	t.Run("tool cacheability", func(t *testing.T) {
+		resp, body := post(t, call("forecast"), nil)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "notifications must not upgrade to SSE")
+		assert.Contains(t, string(body), "sunny")
+		assert.Equal(t, "private, max-age=300", resp.Header.Get("Cache-Control"))
+
+		resp, _ = post(t, call("forecast"), http.Header{"Authorization": {"Bearer abc"}})
+		assert.Equal(t, "private, max-age=300", resp.Header.Get("Cache-Control"))
+
+		resp, _ = post(t, call("roll"), nil)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		assert.Empty(t, resp.Header.Get("ETag"))
+
+		resp, _ = post(t, call("broken"), nil)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+	})
+
+	get := func(t *testing.T, query url.Values, header http.Header) (*http.Response, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+"?"+query.Encode(), nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	t.Run("GET form is public", func(t *testing.T) {
+		resp, body := get(t, url.Values{"method": {"tools/list"}}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+		assert.Contains(t, string(body), `"id":0`)
+		postResp, _ := post(t, map[string]any{"jsonrpc": "2.0", "id": 7, "method": "tools/list"}, nil)
+		assert.Equal(t, postResp.Header.Get("ETag"), resp.Header.Get("ETag"))
+
+		query := url.Values{"method": {"tools/call"}, "params": {`{"name":"forecast"}`}}
+		resp, body = get(t, query, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, string(body), "sunny")
+		assert.Equal(t, "public, max-age=300", resp.Header.Get("Cache-Control"))
+
+		resp, _ = get(t, query, http.Header{"Authorization": {"Bearer abc"}})
+		assert.Equal(t, "private, max-age=300", resp.Header.Get("Cache-Control"))
+
+		resp, body = get(t, query, http.Header{"If-None-Match": {resp.Header.Get("ETag")}})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Empty(t, body)
+	})
+
+	t.Run("GET form rejects other requests", func(t *testing.T) {
+		for _, query := range []url.Values{
+			{"method": {"tools/call"}, "params": {`{"name":"roll"}`}},
+			{"method": {"tools/call"}},
+			{"method": {"logging/setLevel"}, "params": {`{"level":"debug"}`}},
+			{"method": {"tools/list"}, "params": {`{`}},
+		} {
+			resp, _ := get(t, query, nil)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+
+	t.Run("streaming GET and DELETE are not allowed", func(t *testing.T) {
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			req, err := http.NewRequest(method, server.URL, nil)
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode, method)
+		}
+	})
+}
+
+func TestStreamableHTTP_HTTPCaching_Authenticated(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0", WithAuthenticator(APIKeyAuthenticator("X-API-Key", map[string]Identity{
+		"key-a": {Subject: "a"},
+	})))
+	mcpServer.AddTool(mcp.NewTool("forecast", mcp.WithCacheability(5*time.Minute)),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("sunny"), nil
+		})
+	server := NewTestStreamableHTTPServer(mcpServer, WithHTTPCaching(HTTPCacheConfig{ListMaxAge: time.Minute}))
+	defer server.Close()
+
+	for _, query := range []url.Values{
+		{"method": {"tools/list"}},
+		{"method": {"tools/call"}, "params": {`{"name":"forecast"}`}},
+	} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"?"+query.Encode(), nil)
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "key-a")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Cache-Control"), "private,"), resp.Header.Get("Cache-Control"))
+	}
+}
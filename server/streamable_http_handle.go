@@ -37,6 +37,10 @@ type HTTPRequest struct {
 	// request from asHTTPRequest cannot reconstruct. Nil when the request
 	// entered through Handle.
 	original *http.Request
+	// shareable marks the requests of the GET form of WithHTTPCaching,
+	// whose responses do not depend on the caller and may be stored by
+	// shared caches.
+	shareable bool
 }
 
 // context returns Context, defaulting to context.Background() when nil so
@@ -73,11 +77,16 @@ func (r *HTTPRequest) asHTTPRequest() *http.Request {
 	if u == nil {
 		u = &url.URL{}
 	}
+	header := r.header()
+	host := u.Host
+	if host == "" {
+		host = header.Get("Host")
+	}
 	req := &http.Request{
 		Method: r.Method,
 		URL:    u,
-		Header: r.header(),
-		Host:   u.Host,
+		Header: header,
+		Host:   host,
 	}
 	return req.WithContext(r.ctx())
 }
@@ -175,10 +184,12 @@ func writeHTTPErrorf(w HTTPResponseWriter, code int, format string, args ...any)
 //
 //   - WithStreamableHTTPCORS is NOT applied. Frameworks should use their
 //     native CORS middleware.
-//   - DNS rebinding protection is NOT applied, because Handle has no access
-//     to the connection's local address. Adapters for localhost-reachable
-//     servers should validate the Host header themselves (reject non-loopback
-//     Host values on loopback connections with 403 Forbidden).
+//   - DNS rebinding protection is NOT applied by default, because Handle has
+//     no access to the connection's local address. Adapters for
+//     localhost-reachable servers should store it in r.Context under
+//     http.LocalAddrContextKey, or configure WithAllowedHosts and
+//     WithAllowedOrigins, which Handle always enforces. The Host header is
+//     taken from r.URL.Host, or from r.Header when the URL has no host.
 //   - WithProtectedResourceMetadata is NOT applied. The caller should mount
 //     the metadata route separately if needed (see ProtectedResourceMetadataHandler).
 //   - WithHTTPContextFunc is honored for backwards compatibility; it receives
@@ -192,6 +203,24 @@ func (s *StreamableHTTPServer) Handle(w HTTPResponseWriter, r *HTTPRequest) {
 		writeHTTPError(w, "nil request", http.StatusBadRequest)
 		return
 	}
+	// ServeHTTP has already validated the requests it forwards.
+	if r.original == nil && s.origins.reject(w, r.asHTTPRequest(), s.corsConfig) {
+		return
+	}
+	ctx, ok := s.authenticateClientCertificate(r.ctx(), w, r.asHTTPRequest())
+	if !ok {
+		return
+	}
+	r.Context = ctx
+	ctx, ok = s.server.authenticate(r.ctx(), w, r.asHTTPRequest(), protectedResourceMetadataURL(s.protectedResourceMetadata))
+	if !ok || !s.authorizeSession(ctx, w, r.header().Get(HeaderKeySessionID)) {
+		return
+	}
+	r.Context = ctx
+	w, err := s.throttle(w, r)
+	if err != nil {
+		return
+	}
 	switch r.Method {
 	case http.MethodPost:
 		s.handlePost(w, r)
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// WithClientCAs requires clients of the streamable HTTP server to present a
+// TLS client certificate issued by one of the authorities in pool (mutual
+// TLS). Start asks for and verifies the certificate during the handshake,
+// and requires WithTLSCert. When the server is mounted as an http.Handler
+// instead, every request's peer certificate is verified against pool and
+// requests without a valid one are rejected with 401 Unauthorized; requests
+// served with Handle carry no TLS state and are always rejected.
+//
+// The verified certificate is available to handlers with
+// ClientCertificateFromContext, and to hooks through sessions implementing
+// SessionWithClientCertificate. A session is bound to the certificate it
+// was created with: requests for it presenting another certificate, or
+// none, are rejected with 403 Forbidden, so a leaked session ID is useless
+// without the client's key. Sessions are bound the same way without
+// WithClientCAs when the TLS server verified an optional client
+// certificate.
+func WithClientCAs(pool *x509.CertPool) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.clientCAs = pool
+	}
+}
+
+// SessionWithClientCertificate is implemented by sessions of clients that
+// authenticated with a TLS client certificate.
+type SessionWithClientCertificate interface {
+	ClientSession
+	// ClientCertificate returns the verified client certificate of the
+	// session, or nil if the client presented none.
+	ClientCertificate() *x509.Certificate
+}
+
+// clientCertificateStore implements SessionWithClientCertificate for
+// embedding in session types. The zero value holds no certificate.
+type clientCertificateStore struct {
+	clientCertificate atomic.Pointer[x509.Certificate]
+}
+
+func (s *clientCertificateStore) ClientCertificate() *x509.Certificate {
+	return s.clientCertificate.Load()
+}
+
+// setClientCertificate records cert unless the session already has one: a
+// session keeps the certificate it was bound to.
+func (s *clientCertificateStore) setClientCertificate(cert *x509.Certificate) {
+	if cert != nil {
+		s.clientCertificate.CompareAndSwap(nil, cert)
+	}
+}
+
+type clientCertificateKey struct{}
+
+// ClientCertificateFromContext returns the verified TLS client certificate
+// of the request being handled, and false if the client presented none.
+func ClientCertificateFromContext(ctx context.Context) (*x509.Certificate, bool) {
+	cert, ok := ctx.Value(clientCertificateKey{}).(*x509.Certificate)
+	return cert, ok
+}
+
+// errNoClientCertificate is returned by verifyClientCertificate when a
+// request has no client certificate.
+var errNoClientCertificate = errors.New("client certificate required")
+
+// verifyClientCertificate returns the verified client certificate of r. With
+// a pool, the peer certificate is verified against it; without one, only a
+// certificate the TLS server already verified is returned.
+func verifyClientCertificate(r *http.Request, pool *x509.CertPool) (*x509.Certificate, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, errNoClientCertificate
+	}
+	if pool == nil {
+		if len(r.TLS.VerifiedChains) == 0 {
+			return nil, errNoClientCertificate
+		}
+		return r.TLS.VerifiedChains[0][0], nil
+	}
+	leaf := r.TLS.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range r.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaf, nil
+}
+
+// authenticateClientCertificate stores the verified client certificate of r
+// in ctx and checks it against the certificate the requested session is
+// bound to. It writes the error response and returns false if the request
+// must be rejected.
+func (s *StreamableHTTPServer) authenticateClientCertificate(ctx context.Context, w HTTPResponseWriter, r *http.Request) (context.Context, bool) {
+	cert, err := verifyClientCertificate(r, s.clientCAs)
+	if err != nil && s.clientCAs != nil {
+		writeHTTPError(w, "Invalid client certificate", http.StatusUnauthorized)
+		return ctx, false
+	}
+	if sessionID := r.Header.Get(HeaderKeySessionID); sessionID != "" {
+		// Without WithClientCAs a certificate is optional, but a session
+		// bound to one still requires it.
+		if bound := s.boundClientCertificate(sessionID); bound != nil && (cert == nil || !bound.Equal(cert)) {
+			writeHTTPError(w, "Session belongs to another client certificate", http.StatusForbidden)
+			return ctx, false
+		}
+	}
+	if cert == nil {
+		return ctx, true
+	}
+	return context.WithValue(ctx, clientCertificateKey{}, cert), true
+}
+
+// bindClientCertificate binds sessionID to the client certificate of the
+// request creating the session. A session already bound to a certificate
+// keeps it.
+func (s *StreamableHTTPServer) bindClientCertificate(ctx context.Context, sessionID string) {
+	if cert, ok := ClientCertificateFromContext(ctx); ok && sessionID != "" {
+		s.sessionCertificates.LoadOrStore(sessionID, cert)
+	}
+}
+
+// boundClientCertificate returns the client certificate sessionID is bound
+// to, or nil if it is bound to none.
+func (s *StreamableHTTPServer) boundClientCertificate(sessionID string) *x509.Certificate {
+	if bound, ok := s.sessionCertificates.Load(sessionID); ok {
+		return bound.(*x509.Certificate)
+	}
+	return nil
+}
+
+// sessionClientCertificate returns the certificate to record on the session
+// sessionID: the one it is bound to, or, for a stateless request without a
+// session ID, the certificate of the request.
+func (s *StreamableHTTPServer) sessionClientCertificate(ctx context.Context, sessionID string) *x509.Certificate {
+	if sessionID == "" {
+		cert, _ := ClientCertificateFromContext(ctx)
+		return cert
+	}
+	return s.boundClientCertificate(sessionID)
+}
+
+// clientTLSConfig returns the TLS configuration Start serves with, adding
+// client certificate verification to base when WithClientCAs is set.
+func (s *StreamableHTTPServer) clientTLSConfig(base *tls.Config) *tls.Config {
+	if s.clientCAs == nil {
+		return base
+	}
+	var config *tls.Config
+	if base != nil {
+		config = base.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	config.ClientCAs = s.clientCAs
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config
+}
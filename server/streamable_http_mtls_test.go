@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCertificate issues a certificate for commonName signed by parent, or a
+// self-signed CA certificate when parent is nil.
+func testCertificate(t *testing.T, commonName string, parent *tls.Certificate, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+	}
+	signer, signerKey := template, any(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestStreamableHTTP_ClientCAs(t *testing.T) {
+	ca := testCertificate(t, "test CA", nil, x509.ExtKeyUsageAny)
+	alice := testCertificate(t, "alice", &ca, x509.ExtKeyUsageClientAuth)
+	bob := testCertificate(t, "bob", &ca, x509.ExtKeyUsageClientAuth)
+	stranger := testCertificate(t, "stranger", nil, x509.ExtKeyUsageClientAuth)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	var sessionCert *x509.Certificate
+	hooks := &Hooks{}
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		if session, ok := ClientSessionFromContext(ctx).(SessionWithClientCertificate); ok {
+			sessionCert = session.ClientCertificate()
+		}
+	})
+	mcpServer := NewMCPServer("test", "1.0.0", WithHooks(hooks))
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cert, _ := ClientCertificateFromContext(ctx)
+		return mcp.NewToolResultText(cert.Subject.CommonName), nil
+	})
+
+	ts := httptest.NewUnstartedServer(NewStreamableHTTPServer(mcpServer, WithClientCAs(pool)))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	post := func(t *testing.T, cert *tls.Certificate, sessionID, body string) *http.Response {
+		t.Helper()
+		transport := ts.Client().Transport.(*http.Transport).Clone()
+		if cert != nil {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{*cert}
+		}
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set(HeaderKeySessionID, sessionID)
+		}
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0.0"}}}`
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"whoami"}}`
+
+	resp := post(t, &alice, "", initialize)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	require.NotEmpty(t, sessionID)
+	require.NotNil(t, sessionCert)
+	assert.Equal(t, "alice", sessionCert.Subject.CommonName)
+
+	resp = post(t, &alice, sessionID, call)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var result struct {
+		Result mcp.CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Result.Content, 1)
+	assert.Equal(t, "alice", result.Result.Content[0].(mcp.TextContent).Text)
+
+	assert.Equal(t, http.StatusForbidden, post(t, &bob, sessionID, call).StatusCode, "the session is bound to alice")
+	assert.Equal(t, http.StatusUnauthorized, post(t, nil, "", initialize).StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, post(t, &stranger, "", initialize).StatusCode)
+}
+
+func TestStreamableHTTP_ClientCAsRequiresTLS(t *testing.T) {
+	s := NewStreamableHTTPServer(NewMCPServer("test", "1.0.0"), WithClientCAs(x509.NewCertPool()))
+	assert.EqualError(t, s.Start("127.0.0.1:0"), "WithClientCAs requires WithTLSCert")
+}
+
+func TestStreamableHTTP_OptionalClientCertificates(t *testing.T) {
+	ca := testCertificate(t, "test CA", nil, x509.ExtKeyUsageAny)
+	alice := testCertificate(t, "alice", &ca, x509.ExtKeyUsageClientAuth)
+	bob := testCertificate(t, "bob", &ca, x509.ExtKeyUsageClientAuth)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	var registered atomic.Value
+	hooks := &Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session ClientSession) {
+		registered.Store(session.SessionID())
+	})
+
+	// Without WithClientCAs, the TLS server verifies certificates when
+	// given, and the transport binds sessions to them.
+	ts := httptest.NewUnstartedServer(NewStreamableHTTPServer(NewMCPServer("test", "1.0.0", WithHooks(hooks))))
+	ts.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: pool}
+	ts.StartTLS()
+	defer ts.Close()
+
+	request := func(t *testing.T, ctx context.Context, method string, cert *tls.Certificate, sessionID, body string) *http.Response {
+		t.Helper()
+		transport := ts.Client().Transport.(*http.Transport).Clone()
+		if cert != nil {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{*cert}
+		}
+		req, err := http.NewRequestWithContext(ctx, method, ts.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set(HeaderKeySessionID, sessionID)
+		}
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+
+	t.Run("session created by initialize", func(t *testing.T) {
+		initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0.0"}}}`
+		resp := request(t, context.Background(), http.MethodPost, &alice, "", initialize)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		sessionID := resp.Header.Get(HeaderKeySessionID)
+
+		assert.Equal(t, http.StatusOK, request(t, context.Background(), http.MethodPost, &alice, sessionID, ping).StatusCode)
+		assert.Equal(t, http.StatusForbidden, request(t, context.Background(), http.MethodPost, &bob, sessionID, ping).StatusCode)
+		assert.Equal(t, http.StatusForbidden, request(t, context.Background(), http.MethodPost, nil, sessionID, ping).StatusCode,
+			"a session bound to a certificate requires it")
+		assert.Equal(t, http.StatusOK, request(t, context.Background(), http.MethodPost, &alice, sessionID, ping).StatusCode,
+			"the binding is not replaced")
+	})
+
+	t.Run("session created by GET", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		resp := request(t, ctx, http.MethodGet, &alice, "", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		sessionID, _ := registered.Load().(string)
+		require.NotEmpty(t, sessionID)
+
+		assert.Equal(t, http.StatusForbidden, request(t, context.Background(), http.MethodPost, &bob, sessionID, ping).StatusCode)
+		assert.Equal(t, http.StatusForbidden, request(t, context.Background(), http.MethodPost, nil, sessionID, ping).StatusCode)
+	})
+}
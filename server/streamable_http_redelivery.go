@@ -0,0 +1,122 @@
+package server
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithRequestRedelivery makes server-to-client requests (sampling,
+// elicitation, roots) survive a dropped listening stream. When the GET stream
+// of a session drops while requests sent on it are still unanswered, the
+// session is kept for window; if the client reconnects with the same session
+// ID in time, the unanswered requests are sent again with their original IDs
+// and the awaiting handlers receive the responses as if nothing happened.
+// Otherwise the requests fail with ErrRequestUndeliverable and
+// ErrSessionClosed once the window passes. A zero window, the default,
+// disables redelivery.
+func WithRequestRedelivery(window time.Duration) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.redeliveryWindow = window
+	}
+}
+
+// detachedSession is a session whose listening stream dropped while it had
+// unanswered requests, waiting for the client to reconnect.
+type detachedSession struct {
+	session *streamableHttpSession
+	timer   *time.Timer
+}
+
+// markSent records that the listening stream picked up request, so it can be
+// sent again if the stream drops before the client answers.
+func (s *streamableHttpSession) markSent(requestID int64, sent *atomic.Bool, request mcp.JSONRPCRequest) {
+	if sent != nil {
+		sent.Store(true)
+	}
+	s.unanswered.Store(requestID, request)
+}
+
+// unansweredRequests returns the requests sent to the client that are still
+// awaiting a response, in the order they were issued.
+func (s *streamableHttpSession) unansweredRequests() []mcp.JSONRPCRequest {
+	var ids []int64
+	s.unanswered.Range(func(key, _ any) bool {
+		ids = append(ids, key.(int64))
+		return true
+	})
+	slices.Sort(ids)
+
+	requests := make([]mcp.JSONRPCRequest, 0, len(ids))
+	for _, id := range ids {
+		if request, ok := s.unanswered.Load(id); ok {
+			requests = append(requests, request.(mcp.JSONRPCRequest))
+		}
+	}
+	return requests
+}
+
+// failUnanswered fails the handlers awaiting the unanswered requests with
+// err.
+func (s *streamableHttpSession) failUnanswered(err error) {
+	s.unanswered.Range(func(key, _ any) bool {
+		if ch, ok := s.samplingRequests.Load(key); ok {
+			select {
+			case ch.(chan samplingResponseItem) <- samplingResponseItem{requestID: key.(int64), err: err}:
+			default:
+			}
+		}
+		return true
+	})
+}
+
+// detachSession keeps session for the redelivery window if it has
+// unanswered requests when its listening stream ends. Without a window, the
+// requests are not sent again, but their handlers keep waiting for answers
+// posted by the client.
+func (s *StreamableHTTPServer) detachSession(session *streamableHttpSession) {
+	if len(session.unansweredRequests()) == 0 {
+		return
+	}
+	if s.redeliveryWindow <= 0 {
+		session.unanswered.Clear()
+		return
+	}
+
+	detached := &detachedSession{session: session}
+	detached.timer = time.AfterFunc(s.redeliveryWindow, func() {
+		if s.detachedSessions.CompareAndDelete(session.sessionID, detached) {
+			session.failUnanswered(undeliverable(ErrSessionClosed))
+		}
+	})
+	if previous, loaded := s.detachedSessions.Swap(session.sessionID, detached); loaded {
+		s.discardDetached(previous.(*detachedSession))
+	}
+}
+
+// reattachSession returns the detached session with the given ID, if the
+// client reconnected within the redelivery window.
+func (s *StreamableHTTPServer) reattachSession(sessionID string) *streamableHttpSession {
+	value, ok := s.detachedSessions.LoadAndDelete(sessionID)
+	if !ok {
+		return nil
+	}
+	detached := value.(*detachedSession)
+	detached.timer.Stop()
+	return detached.session
+}
+
+// dropDetachedSession fails the unanswered requests of a detached session,
+// for example when the client deletes the session.
+func (s *StreamableHTTPServer) dropDetachedSession(sessionID string) {
+	if value, ok := s.detachedSessions.LoadAndDelete(sessionID); ok {
+		s.discardDetached(value.(*detachedSession))
+	}
+}
+
+func (s *StreamableHTTPServer) discardDetached(detached *detachedSession) {
+	detached.timer.Stop()
+	detached.session.failUnanswered(undeliverable(ErrSessionClosed))
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamableHTTP_RequestRedelivery(t *testing.T) {
+	const getSessionID = "mcp-session-22222222-2222-2222-2222-222222222222"
+
+	setup := func(t *testing.T, window time.Duration) (*MCPServer, string) {
+		mcpServer := NewMCPServer("test-mcp-server", "1.0", WithElicitation())
+		server := httptest.NewServer(NewStreamableHTTPServer(mcpServer, WithRequestRedelivery(window)))
+		t.Cleanup(server.Close)
+		return mcpServer, server.URL
+	}
+
+	openStream := func(t *testing.T, ctx context.Context, url, sessionID string) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderKeySessionID, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp
+	}
+
+	// initialize creates a session with an initialize POST.
+	initialize := func(t *testing.T, url string) string {
+		resp, err := postJSON(url, initRequest)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		sessionID := resp.Header.Get(HeaderKeySessionID)
+		require.NotEmpty(t, sessionID)
+		return sessionID
+	}
+
+	// elicit issues an elicitation request on the session once its stream
+	// is registered and returns the channel receiving the outcome.
+	elicit := func(t *testing.T, mcpServer *MCPServer, sessionID string) <-chan error {
+		var session ClientSession
+		require.Eventually(t, func() bool {
+			value, ok := mcpServer.sessions.Load(sessionID)
+			if ok {
+				session = value.(ClientSession)
+			}
+			return ok
+		}, time.Second, 10*time.Millisecond)
+
+		done := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(mcpServer.WithContext(context.Background(), session), 5*time.Second)
+			defer cancel()
+			_, err := mcpServer.RequestElicitation(ctx, mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+				Message:         "Name?",
+				RequestedSchema: map[string]any{"type": "object"},
+			}})
+			done <- err
+		}()
+		return done
+	}
+
+	readRequest := func(t *testing.T, resp *http.Response) mcp.JSONRPCRequest {
+		_, data := readSSEEvents(t, bufio.NewScanner(resp.Body), 1)
+		var request mcp.JSONRPCRequest
+		require.NoError(t, json.Unmarshal([]byte(data[0]), &request))
+		require.Equal(t, string(mcp.MethodElicitationCreate), request.Method)
+		return request
+	}
+
+	answer := func(t *testing.T, url, sessionID string, id mcp.RequestId) {
+		resp, err := postSessionJSON(url, sessionID, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result":  map[string]any{"action": "accept", "content": map[string]any{"name": "Ada"}},
+		})
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	}
+
+	waitDone := func(t *testing.T, done <-chan error) error {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(2 * time.Second):
+			t.Fatal("elicitation did not complete")
+			return nil
+		}
+	}
+
+	waitDetached := func(t *testing.T, mcpServer *MCPServer, sessionID string) {
+		require.Eventually(t, func() bool {
+			_, ok := mcpServer.sessions.Load(sessionID)
+			return !ok
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	t.Run("reconnect receives the unanswered request", func(t *testing.T) {
+		mcpServer, url := setup(t, time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := openStream(t, ctx, url, getSessionID)
+		done := elicit(t, mcpServer, getSessionID)
+		first := readRequest(t, resp)
+		cancel()
+		resp.Body.Close()
+		waitDetached(t, mcpServer, getSessionID)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		resp = openStream(t, ctx, url, getSessionID)
+		defer resp.Body.Close()
+		again := readRequest(t, resp)
+		assert.Equal(t, first.ID, again.ID)
+
+		answer(t, url, getSessionID, again.ID)
+		assert.NoError(t, waitDone(t, done))
+	})
+
+	t.Run("window expires", func(t *testing.T) {
+		mcpServer, url := setup(t, 50*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := openStream(t, ctx, url, getSessionID)
+		done := elicit(t, mcpServer, getSessionID)
+		readRequest(t, resp)
+		cancel()
+		resp.Body.Close()
+
+		err := waitDone(t, done)
+		assert.ErrorIs(t, err, ErrRequestUndeliverable)
+		assert.ErrorIs(t, err, ErrSessionClosed)
+	})
+
+	t.Run("session created by initialize", func(t *testing.T) {
+		mcpServer, url := setup(t, time.Minute)
+		sessionID := initialize(t, url)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := openStream(t, ctx, url, sessionID)
+		done := elicit(t, mcpServer, sessionID)
+		first := readRequest(t, resp)
+		cancel()
+		resp.Body.Close()
+
+		value, _ := mcpServer.sessions.Load(sessionID)
+		session := value.(*streamableHttpSession)
+		require.Eventually(t, func() bool {
+			return !session.listening.Load()
+		}, time.Second, 10*time.Millisecond)
+
+		// Two listening streams reconnect; only the first one resends the
+		// request.
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		resp = openStream(t, ctx, url, sessionID)
+		defer resp.Body.Close()
+		again := readRequest(t, resp)
+		assert.Equal(t, first.ID, again.ID)
+		second := openStream(t, ctx, url, sessionID)
+		defer second.Body.Close()
+		events := make(chan struct{})
+		go func() {
+			if bufio.NewScanner(second.Body).Scan() {
+				close(events)
+			}
+		}()
+
+		answer(t, url, sessionID, again.ID)
+		assert.NoError(t, waitDone(t, done))
+		select {
+		case <-events:
+			t.Fatal("the second stream resent the request")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("session created by initialize, window expires", func(t *testing.T) {
+		mcpServer, url := setup(t, 50*time.Millisecond)
+		sessionID := initialize(t, url)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := openStream(t, ctx, url, sessionID)
+		done := elicit(t, mcpServer, sessionID)
+		readRequest(t, resp)
+		cancel()
+		resp.Body.Close()
+
+		err := waitDone(t, done)
+		assert.ErrorIs(t, err, ErrRequestUndeliverable)
+		assert.ErrorIs(t, err, ErrSessionClosed)
+	})
+
+	t.Run("session created by initialize, no window", func(t *testing.T) {
+		mcpServer, url := setup(t, 0)
+		sessionID := initialize(t, url)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := openStream(t, ctx, url, sessionID)
+		done := elicit(t, mcpServer, sessionID)
+		first := readRequest(t, resp)
+		cancel()
+		resp.Body.Close()
+
+		value, _ := mcpServer.sessions.Load(sessionID)
+		session := value.(*streamableHttpSession)
+		require.Eventually(t, func() bool {
+			return !session.listening.Load()
+		}, time.Second, 10*time.Millisecond)
+		assert.Empty(t, session.unansweredRequests(), "nothing is resent without a window")
+
+		// The request is still answered by a late response.
+		answer(t, url, sessionID, first.ID)
+		assert.NoError(t, waitDone(t, done))
+	})
+}
@@ -42,6 +42,23 @@ type OnTaskCancelledHookFunc func(ctx context.Context, metrics TaskMetrics)
 // Use this for general monitoring or when you need to track all state changes.
 type OnTaskStatusChangedHookFunc func(ctx context.Context, metrics TaskMetrics)
 
+// TaskTransfer describes a task whose ownership moved from one session to
+// another through tasks/claim.
+type TaskTransfer struct {
+	TaskID        string         // Unique identifier for the task
+	ToolName      string         // Name of the tool that created the task
+	Status        mcp.TaskStatus // Status of the task when it was transferred
+	Owner         string         // Authenticated identity owning the task
+	FromSessionID string         // Session that owned the task before the transfer
+	ToSessionID   string         // Session that claimed the task
+	TransferredAt time.Time      // When the task was transferred
+}
+
+// OnTaskTransferredHookFunc is called when a session claims a task created by
+// another session of the same user.
+// Use this to keep an audit trail of ownership transfers.
+type OnTaskTransferredHookFunc func(ctx context.Context, transfer TaskTransfer)
+
 // TaskHooks contains lifecycle hooks for task execution.
 // These hooks enable observability and monitoring of task-augmented tools.
 type TaskHooks struct {
@@ -50,6 +67,7 @@ type TaskHooks struct {
 	OnTaskFailed        []OnTaskFailedHookFunc
 	OnTaskCancelled     []OnTaskCancelledHookFunc
 	OnTaskStatusChanged []OnTaskStatusChangedHookFunc
+	OnTaskTransferred   []OnTaskTransferredHookFunc
 }
 
 // AddOnTaskCreated registers a hook for task creation events.
@@ -77,6 +95,11 @@ func (h *TaskHooks) AddOnTaskStatusChanged(hook OnTaskStatusChangedHookFunc) {
 	h.OnTaskStatusChanged = append(h.OnTaskStatusChanged, hook)
 }
 
+// AddOnTaskTransferred registers a hook for task ownership transfers.
+func (h *TaskHooks) AddOnTaskTransferred(hook OnTaskTransferredHookFunc) {
+	h.OnTaskTransferred = append(h.OnTaskTransferred, hook)
+}
+
 // taskCreated calls all registered task creation hooks.
 func (h *TaskHooks) taskCreated(ctx context.Context, metrics TaskMetrics) {
 	if h == nil {
@@ -134,3 +157,13 @@ func (h *TaskHooks) taskStatusChanged(ctx context.Context, metrics TaskMetrics)
 		hook(ctx, metrics)
 	}
 }
+
+// taskTransferred calls all registered task transfer hooks.
+func (h *TaskHooks) taskTransferred(ctx context.Context, transfer TaskTransfer) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.OnTaskTransferred {
+		hook(ctx, transfer)
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrTaskClaimDenied is returned by tasks/claim when the request does not
+// carry an authenticated identity.
+var ErrTaskClaimDenied = errors.New("task claim requires an authenticated identity")
+
+// TaskOwnerFunc returns the authenticated identity behind a request, such
+// as a user ID taken from a verified token, or "" if the request is not
+// authenticated.
+type TaskOwnerFunc func(ctx context.Context) string
+
+// WithTaskOwnership records the identity returned by owner on every task
+// and lets clients take over tasks created by another session of the same
+// identity with tasks/claim. A user who restarts their host gets a new
+// session, and with it loses access to the tasks of the previous one;
+// claiming a task moves it to the new session, which can then poll it,
+// fetch its result and receive its status notifications again.
+//
+// The server advertises the tasks.claim capability, which is an extension
+// to the MCP specification, and reports every transfer to the
+// OnTaskTransferred task hooks. Tasks created without an identity cannot be
+// claimed. It requires WithTaskCapabilities.
+func WithTaskOwnership(owner TaskOwnerFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.taskOwner = owner
+	}
+}
+
+// taskOwnerOf returns the identity of the request in ctx, or "" if task
+// ownership is not enabled.
+func (s *MCPServer) taskOwnerOf(ctx context.Context) string {
+	if s.taskOwner == nil {
+		return ""
+	}
+	return s.taskOwner(ctx)
+}
+
+// handleClaimTask handles tasks/claim requests.
+func (s *MCPServer) handleClaimTask(
+	ctx context.Context,
+	id any,
+	request mcp.ClaimTaskRequest,
+) (*mcp.ClaimTaskResult, *requestError) {
+	if s.taskOwner == nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("tasks claim %w", ErrUnsupported),
+		}
+	}
+	if getSessionID(ctx) == "" {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  errors.New("tasks/claim requires a session"),
+		}
+	}
+
+	task, err := s.claimTask(ctx, request.Params.TaskId)
+	if err != nil {
+		code := mcp.INVALID_PARAMS
+		if errors.Is(err, ErrTaskClaimDenied) {
+			code = mcp.INVALID_REQUEST
+		}
+		return nil, &requestError{
+			id:   id,
+			code: code,
+			err:  err,
+		}
+	}
+
+	result := mcp.NewClaimTaskResult(task)
+	return &result, nil
+}
+
+// claimTask moves the task with the given ID to the session in ctx if the
+// task was created by the same identity. Tasks of other identities are
+// reported as not found, so their IDs do not leak.
+func (s *MCPServer) claimTask(ctx context.Context, taskID string) (mcp.Task, error) {
+	owner := s.taskOwnerOf(ctx)
+	if owner == "" {
+		return mcp.Task{}, ErrTaskClaimDenied
+	}
+	sessionID := getSessionID(ctx)
+
+	s.tasksMu.RLock()
+	entry, exists := s.tasks[taskID]
+	_, wasExpired := s.expiredTasks[taskID]
+	s.tasksMu.RUnlock()
+	if !exists {
+		if wasExpired {
+			return mcp.Task{}, ErrTaskExpired
+		}
+		var err error
+		if entry, err = s.loadStoredTask(ctx, taskID); err != nil {
+			if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrTaskExpired) {
+				return mcp.Task{}, err
+			}
+			return mcp.Task{}, fmt.Errorf("failed to load task: %w", err)
+		}
+	}
+
+	s.tasksMu.Lock()
+	if entry.owner != owner {
+		s.tasksMu.Unlock()
+		return mcp.Task{}, ErrTaskNotFound
+	}
+	previous := entry.sessionID
+	entry.sessionID = sessionID
+	task := entry.task
+	s.tasksMu.Unlock()
+
+	if previous == sessionID {
+		return task, nil
+	}
+	s.persistTask(entry)
+
+	s.taskHooks.taskTransferred(ctx, TaskTransfer{
+		TaskID:        taskID,
+		ToolName:      entry.toolName,
+		Status:        task.Status,
+		Owner:         owner,
+		FromSessionID: previous,
+		ToSessionID:   sessionID,
+		TransferredAt: s.now(),
+	})
+	return task, nil
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testUserKey struct{}
+
+func withTestUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, testUserKey{}, user)
+}
+
+func testUser(ctx context.Context) string {
+	user, _ := ctx.Value(testUserKey{}).(string)
+	return user
+}
+
+func TestTaskOwnership_Capability(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true))
+	resp := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/claim","params":{"taskId":"t"}}`))
+	errResp, ok := resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.METHOD_NOT_FOUND, errResp.Error.Code)
+
+	server = NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true), WithTaskOwnership(testUser))
+	resp = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-11-25","capabilities":{},"clientInfo":{"name":"c","version":"1"}}}`))
+	result := resp.(mcp.JSONRPCResponse).Result.(mcp.InitializeResult)
+	require.NotNil(t, result.Capabilities.Tasks)
+	assert.NotNil(t, result.Capabilities.Tasks.Claim)
+
+	resp = server.HandleMessage(withTestUser(context.Background(), "alice"), []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/claim","params":{"taskId":"t"}}`))
+	errResp, ok = resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.INVALID_REQUEST, errResp.Error.Code, "a session is required")
+}
+
+func TestTaskOwnership_Claim(t *testing.T) {
+	var transfers []TaskTransfer
+	hooks := &TaskHooks{}
+	hooks.AddOnTaskTransferred(func(_ context.Context, transfer TaskTransfer) {
+		transfers = append(transfers, transfer)
+	})
+	store := NewInMemoryTaskStore()
+	server := NewMCPServer("test", "1.0.0",
+		WithTaskCapabilities(true, true, true),
+		WithSessionScopedTasks(),
+		WithTaskStore(store),
+		WithTaskHooks(hooks),
+		WithTaskOwnership(testUser),
+	)
+
+	sessionCtx := func(sessionID, user string) context.Context {
+		session := fakeSession{sessionID: sessionID, notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		return withTestUser(server.WithContext(context.Background(), session), user)
+	}
+	oldCtx := sessionCtx("old", "alice")
+	newCtx := sessionCtx("new", "alice")
+
+	_, err := server.createTask(oldCtx, "alice-task", "build", nil, nil)
+	require.NoError(t, err)
+	_, err = server.createTask(sessionCtx("anonymous", ""), "anon-task", "build", nil, nil)
+	require.NoError(t, err)
+
+	claim := func(ctx context.Context, taskID string) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/claim","params":{"taskId":"`+taskID+`"}}`))
+	}
+	get := func(ctx context.Context, taskID string) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":{"taskId":"`+taskID+`"}}`))
+	}
+
+	t.Run("denied", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			ctx    context.Context
+			taskID string
+			code   int
+		}{
+			{name: "other user", ctx: sessionCtx("bob", "bob"), taskID: "alice-task", code: mcp.INVALID_PARAMS},
+			{name: "unauthenticated", ctx: sessionCtx("nobody", ""), taskID: "alice-task", code: mcp.INVALID_REQUEST},
+			{name: "task without owner", ctx: newCtx, taskID: "anon-task", code: mcp.INVALID_PARAMS},
+			{name: "unknown task", ctx: newCtx, taskID: "missing", code: mcp.INVALID_PARAMS},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				errResp, ok := claim(tt.ctx, tt.taskID).(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, tt.code, errResp.Error.Code)
+			})
+		}
+		assert.Empty(t, transfers)
+	})
+
+	t.Run("claim moves the task to the new session", func(t *testing.T) {
+		require.IsType(t, mcp.JSONRPCError{}, get(newCtx, "alice-task"))
+
+		resp, ok := claim(newCtx, "alice-task").(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		data, err := json.Marshal(resp.Result)
+		require.NoError(t, err)
+		raw := json.RawMessage(data)
+		result, err := mcp.ParseClaimTaskResult(&raw)
+		require.NoError(t, err)
+		assert.Equal(t, "alice-task", result.TaskId)
+		assert.Equal(t, mcp.TaskStatusWorking, result.Status)
+
+		assert.IsType(t, mcp.JSONRPCResponse{}, get(newCtx, "alice-task"))
+		assert.IsType(t, mcp.JSONRPCError{}, get(oldCtx, "alice-task"))
+
+		record, err := store.Get(context.Background(), "alice-task")
+		require.NoError(t, err)
+		assert.Equal(t, "new", record.SessionID)
+		assert.Equal(t, "alice", record.Owner)
+
+		require.Len(t, transfers, 1)
+		assert.Equal(t, "alice-task", transfers[0].TaskID)
+		assert.Equal(t, "build", transfers[0].ToolName)
+		assert.Equal(t, "alice", transfers[0].Owner)
+		assert.Equal(t, "old", transfers[0].FromSessionID)
+		assert.Equal(t, "new", transfers[0].ToSessionID)
+	})
+
+	t.Run("claiming an owned task is a no-op", func(t *testing.T) {
+		assert.IsType(t, mcp.JSONRPCResponse{}, claim(newCtx, "alice-task"))
+		assert.Len(t, transfers, 1)
+	})
+
+	t.Run("stored tasks can be claimed", func(t *testing.T) {
+		restarted := NewMCPServer("test", "1.0.0",
+			WithTaskCapabilities(true, true, true),
+			WithSessionScopedTasks(),
+			WithTaskStore(store),
+			WithTaskOwnership(testUser),
+		)
+		session := fakeSession{sessionID: "after-restart", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		ctx := withTestUser(restarted.WithContext(context.Background(), session), "alice")
+
+		task, err := restarted.claimTask(ctx, "alice-task")
+		require.NoError(t, err)
+		assert.Equal(t, "alice-task", task.TaskId)
+		record, err := store.Get(context.Background(), "alice-task")
+		require.NoError(t, err)
+		assert.Equal(t, "after-restart", record.SessionID)
+	})
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type taskProgressKey struct{}
+
+// TaskProgress publishes the intermediate progress of a running task. Each
+// update replaces the task's statusMessage, so pollers see it in tasks/get
+// and tasks/list, and emits notifications/tasks/status.
+type TaskProgress struct {
+	server *MCPServer
+	entry  *taskEntry
+}
+
+// TaskProgressFromContext returns the progress reporter of the task a task
+// tool handler is running. Tool handlers also get one when a client invokes
+// them as a task. Outside of a task, updates fail with ErrNotInTask.
+func TaskProgressFromContext(ctx context.Context) *TaskProgress {
+	if p, ok := ctx.Value(taskProgressKey{}).(*TaskProgress); ok {
+		return p
+	}
+	return &TaskProgress{}
+}
+
+// withTaskProgress attaches the progress reporter of entry to ctx.
+func (s *MCPServer) withTaskProgress(ctx context.Context, entry *taskEntry) context.Context {
+	return context.WithValue(ctx, taskProgressKey{}, &TaskProgress{server: s, entry: entry})
+}
+
+// Update reports progress as a percentage between 0 and 100 and a
+// human-readable message. A negative percent reports the message alone.
+// Updates to a task that has already reached a terminal status are
+// rejected.
+func (p *TaskProgress) Update(percent float64, message string) error {
+	if p == nil || p.server == nil {
+		return ErrNotInTask
+	}
+	statusMessage := message
+	if percent >= 0 {
+		statusMessage = fmt.Sprintf("%.0f%%", min(percent, 100))
+		if message != "" {
+			statusMessage += " " + message
+		}
+	}
+
+	s := p.server
+	s.tasksMu.Lock()
+	if p.entry.completed {
+		status := p.entry.task.Status
+		s.tasksMu.Unlock()
+		return fmt.Errorf("cannot update task in terminal status: %s", status)
+	}
+	p.entry.task.StatusMessage = statusMessage
+	p.entry.task.LastUpdatedAt = p.server.now().UTC().Format(time.RFC3339)
+	task := p.entry.task
+	s.tasksMu.Unlock()
+
+	s.persistTask(p.entry)
+	s.sendTaskStatusNotification(p.entry.sessionID, task)
+	return nil
+}
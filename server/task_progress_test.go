@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskProgressFromContext(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true), WithToolCapabilities(false))
+	session := fakeSession{
+		sessionID:           "s1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	ctx := server.WithContext(context.Background(), session)
+
+	updated := make(chan struct{})
+	release := make(chan struct{})
+	var progress *TaskProgress
+	server.AddTaskTool(
+		mcp.NewTool("build", mcp.WithTaskSupport(mcp.TaskSupportRequired)),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CreateTaskResult, error) {
+			progress = TaskProgressFromContext(ctx)
+			if err := progress.Update(42.4, "compiling"); err != nil {
+				return nil, err
+			}
+			close(updated)
+			<-release
+			return &mcp.CreateTaskResult{}, nil
+		},
+	)
+
+	resp, ok := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"build","task":{}}}`)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	taskID := resp.Result.(*mcp.CreateTaskResult).Task.TaskId
+	<-updated
+
+	task, _, err := server.getTask(ctx, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.TaskStatusWorking, task.Status)
+	assert.Equal(t, "42% compiling", task.StatusMessage)
+
+	select {
+	case n := <-session.notificationChannel:
+		assert.Equal(t, string(mcp.MethodNotificationTasksStatus), n.Method)
+		assert.Equal(t, "42% compiling", n.Params.AdditionalFields["statusMessage"])
+	case <-time.After(time.Second):
+		t.Fatal("no status notification")
+	}
+
+	close(release)
+	require.Eventually(t, func() bool {
+		task, _, _ := server.getTask(ctx, taskID)
+		return task.Status == mcp.TaskStatusCompleted
+	}, time.Second, 10*time.Millisecond)
+	assert.ErrorContains(t, progress.Update(100, "done"), "terminal status")
+}
+
+func TestTaskProgress_MessageOnlyAndOutsideTask(t *testing.T) {
+	assert.ErrorIs(t, TaskProgressFromContext(context.Background()).Update(10, "x"), ErrNotInTask)
+
+	server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true))
+	entry, err := server.createTask(context.Background(), "t1", "tool", nil, nil)
+	require.NoError(t, err)
+	progress := TaskProgressFromContext(server.withTaskProgress(context.Background(), entry))
+
+	require.NoError(t, progress.Update(-1, "waiting for lock"))
+	task, _, err := server.getTask(context.Background(), "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "waiting for lock", task.StatusMessage)
+
+	record, err := server.taskStore.Get(context.Background(), "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "waiting for lock", record.Task.StatusMessage, "progress is persisted")
+}
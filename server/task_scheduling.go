@@ -0,0 +1,173 @@
+package server
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrTaskDeadlineExceeded is the error of a task that did not finish by its
+// deadline. tasks/result reports it as a REQUEST_INTERRUPTED error whose data
+// is a TaskDeadlineError.
+var ErrTaskDeadlineExceeded = errors.New("task deadline exceeded")
+
+// TaskDeadlineError is the error data of tasks/result for a task that missed
+// its deadline.
+type TaskDeadlineError struct {
+	Reason   string `json:"reason"`
+	Deadline string `json:"deadline"`
+}
+
+// WithTaskWorkers limits the number of tasks executing at once to n. Further
+// tasks are accepted and stay in the working status until a worker is free;
+// queued tasks start in order of the priority requested with
+// mcp.WithTaskScheduling, then in the order they were created. Without this
+// option every task starts immediately.
+//
+// Unlike WithMaxConcurrentTasks, which rejects tasks over the limit, this
+// option queues them.
+func WithTaskWorkers(n int) ServerOption {
+	return func(s *MCPServer) {
+		if n > 0 {
+			s.taskScheduler = &taskScheduler{workers: n}
+		}
+	}
+}
+
+// WithTaskTimeout fails tasks of the named tools, or of every tool when no
+// name is given, that have not finished within timeout of their creation.
+// Time spent queued counts. A deadline requested by the client with
+// mcp.WithTaskScheduling applies when it is earlier. Per-tool timeouts take
+// precedence over the one for every tool.
+func WithTaskTimeout(timeout time.Duration, toolNames ...string) ServerOption {
+	return func(s *MCPServer) {
+		if s.taskTimeouts == nil {
+			s.taskTimeouts = make(map[string]time.Duration)
+		}
+		if len(toolNames) == 0 {
+			s.taskTimeouts[""] = timeout
+		}
+		for _, name := range toolNames {
+			s.taskTimeouts[name] = timeout
+		}
+	}
+}
+
+// taskDeadline returns the deadline of a task of toolName created at
+// createdAt, combining the tool's timeout with the one requested in params.
+func (s *MCPServer) taskDeadline(toolName string, createdAt time.Time, params *mcp.TaskParams) (time.Time, bool) {
+	deadline, ok := mcp.TaskDeadline(params)
+	timeout, hasTimeout := s.taskTimeouts[toolName]
+	if !hasTimeout {
+		timeout, hasTimeout = s.taskTimeouts[""]
+	}
+	if hasTimeout && timeout > 0 {
+		if toolDeadline := createdAt.Add(timeout); !ok || toolDeadline.Before(deadline) {
+			deadline, ok = toolDeadline, true
+		}
+	}
+	return deadline, ok
+}
+
+// enforceTaskDeadline fails entry if it is still running at deadline.
+func (s *MCPServer) enforceTaskDeadline(entry *taskEntry, deadline time.Time) {
+	s.tasksMu.Lock()
+	defer s.tasksMu.Unlock()
+	entry.deadline = deadline
+	entry.deadlineTimer = time.AfterFunc(deadline.Sub(s.now()), func() {
+		s.tasksMu.Lock()
+		cancel := entry.cancelFunc
+		s.tasksMu.Unlock()
+		s.completeTask(entry, nil, fmt.Errorf("%w at %s", ErrTaskDeadlineExceeded, deadline.UTC().Format(time.RFC3339)))
+		if cancel != nil {
+			cancel()
+		}
+	})
+}
+
+// startTask runs the task with the given priority, now or once a worker is
+// free. Tasks that finished while queued, because they were cancelled or
+// missed their deadline, are skipped.
+func (s *MCPServer) startTask(entry *taskEntry, priority int, run func()) {
+	start := func() {
+		s.tasksMu.RLock()
+		completed := entry.completed
+		s.tasksMu.RUnlock()
+		if !completed {
+			run()
+		}
+	}
+	if s.taskScheduler == nil {
+		go start()
+		return
+	}
+	s.taskScheduler.submit(priority, start)
+}
+
+// taskScheduler runs at most workers tasks at once, starting queued tasks by
+// priority.
+type taskScheduler struct {
+	workers int
+
+	mu      sync.Mutex
+	running int
+	seq     uint64
+	queue   taskQueue
+}
+
+func (q *taskScheduler) submit(priority int, run func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.running < q.workers {
+		q.running++
+		go q.work(run)
+		return
+	}
+	q.seq++
+	heap.Push(&q.queue, queuedTask{priority: priority, seq: q.seq, run: run})
+}
+
+// work runs tasks until the queue is empty.
+func (q *taskScheduler) work(run func()) {
+	for run != nil {
+		run()
+
+		q.mu.Lock()
+		if q.queue.Len() == 0 {
+			q.running--
+			run = nil
+		} else {
+			run = heap.Pop(&q.queue).(queuedTask).run
+		}
+		q.mu.Unlock()
+	}
+}
+
+type queuedTask struct {
+	priority int
+	seq      uint64
+	run      func()
+}
+
+// taskQueue is a heap of queued tasks, highest priority first, then oldest.
+type taskQueue []queuedTask
+
+func (q taskQueue) Len() int { return len(q) }
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q taskQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *taskQueue) Push(x any)   { *q = append(*q, x.(queuedTask)) }
+func (q *taskQueue) Pop() any {
+	old := *q
+	task := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return task
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTaskWorkers_PriorityOrder(t *testing.T) {
+	srv := NewMCPServer("test", "1.0.0",
+		WithTaskCapabilities(true, true, true),
+		WithTaskWorkers(1),
+	)
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var started []string
+	srv.AddTool(mcp.NewTool("job",
+		mcp.WithString("name"),
+		mcp.WithTaskSupport(mcp.TaskSupportOptional),
+	), func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("name", "")
+		mu.Lock()
+		started = append(started, name)
+		mu.Unlock()
+		if name == "first" {
+			<-release
+		}
+		return mcp.NewToolResultText(name), nil
+	})
+
+	ctx := t.Context()
+	var taskIDs []string
+	submit := func(name string, priority int) {
+		res, reqErr := srv.handleToolCall(ctx, name, mcp.CallToolRequest{Params: mcp.CallToolParams{
+			Name:      "job",
+			Arguments: map[string]any{"name": name},
+			Task:      &mcp.TaskParams{Meta: mcp.WithTaskScheduling(priority, time.Time{})},
+		}})
+		require.Nil(t, reqErr)
+		taskIDs = append(taskIDs, res.(*mcp.CreateTaskResult).Task.TaskId)
+	}
+
+	submit("first", 0)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(started) == 1
+	}, time.Second, 5*time.Millisecond)
+	submit("low", 1)
+	submit("high", 5)
+	submit("high-later", 5)
+	close(release)
+
+	for _, id := range taskIDs {
+		assert.Equal(t, mcp.TaskStatusCompleted, waitForTaskTerminal(t, srv, ctx, id))
+	}
+	assert.Equal(t, []string{"first", "high", "high-later", "low"}, started)
+}
+
+func TestTaskDeadline(t *testing.T) {
+	srv := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true))
+	srv.AddTool(mcp.NewTool("wait", mcp.WithTaskSupport(mcp.TaskSupportOptional)),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	ctx := t.Context()
+	deadline := time.Now().Add(50 * time.Millisecond)
+	res, reqErr := srv.handleToolCall(ctx, 1, mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name: "wait",
+		Task: &mcp.TaskParams{Meta: mcp.WithTaskScheduling(0, deadline)},
+	}})
+	require.Nil(t, reqErr)
+	taskID := res.(*mcp.CreateTaskResult).Task.TaskId
+
+	assert.Equal(t, mcp.TaskStatusFailed, waitForTaskTerminal(t, srv, ctx, taskID))
+	_, resErr := srv.handleTaskResult(ctx, 2, mcp.TaskResultRequest{Params: mcp.TaskResultParams{TaskId: taskID}})
+	require.NotNil(t, resErr)
+	assert.Equal(t, mcp.REQUEST_INTERRUPTED, resErr.code)
+	assert.ErrorIs(t, resErr.err, ErrTaskDeadlineExceeded)
+	assert.Equal(t, TaskDeadlineError{
+		Reason:   "deadline_exceeded",
+		Deadline: deadline.UTC().Format(time.RFC3339Nano),
+	}, resErr.data)
+}
+
+func TestWithTaskTimeout(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := NewMCPServer("test", "1.0.0",
+		WithTaskTimeout(time.Hour),
+		WithTaskTimeout(time.Minute, "quick"),
+	)
+
+	tests := []struct {
+		name      string
+		tool      string
+		requested time.Time
+		want      time.Time
+	}{
+		{name: "server default", tool: "any", want: created.Add(time.Hour)},
+		{name: "per tool", tool: "quick", want: created.Add(time.Minute)},
+		{name: "earlier client deadline", tool: "quick", requested: created.Add(time.Second), want: created.Add(time.Second)},
+		{name: "later client deadline", tool: "any", requested: created.Add(2 * time.Hour), want: created.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &mcp.TaskParams{Meta: mcp.WithTaskScheduling(0, tt.requested)}
+			got, ok := srv.taskDeadline(tt.tool, created, params)
+			require.True(t, ok)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+
+	_, ok := NewMCPServer("test", "1.0.0").taskDeadline("any", created, nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TaskRecord is the durable state of a task, as kept by a TaskStore.
+type TaskRecord struct {
+	Task      mcp.Task `json:"task"`
+	SessionID string   `json:"sessionId,omitempty"`
+	// Owner is the authenticated identity that created the task, recorded
+	// with WithTaskOwnership.
+	Owner     string    `json:"owner,omitempty"`
+	ToolName  string    `json:"toolName"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Result is the tool result of a completed task.
+	Result *mcp.CallToolResult `json:"result,omitempty"`
+	// Error is the error message of a failed task.
+	Error string `json:"error,omitempty"`
+}
+
+// expired reports whether the record's TTL has elapsed at now.
+func (r TaskRecord) expired(now time.Time) bool {
+	if r.Task.TTL == nil || *r.Task.TTL <= 0 {
+		return false
+	}
+	return now.After(r.CreatedAt.Add(time.Duration(*r.Task.TTL) * time.Millisecond))
+}
+
+// TaskStore persists tasks so that task status and results outlive the
+// server process and can be shared between replicas. The server writes
+// every task through to the store when it is created, changes status and
+// expires, and falls back to the store for tasks it is not running itself,
+// such as tasks created before a restart.
+//
+// Tasks that were still running when their process stopped keep their last
+// status until their TTL expires.
+//
+// Implementations must be safe for concurrent use.
+type TaskStore interface {
+	// Create records a new task.
+	Create(ctx context.Context, record TaskRecord) error
+	// Get returns the task with the given ID, or an error wrapping
+	// ErrTaskNotFound.
+	Get(ctx context.Context, taskID string) (TaskRecord, error)
+	// List returns every stored task.
+	List(ctx context.Context) ([]TaskRecord, error)
+	// Update replaces the stored state of an existing task, or returns an
+	// error wrapping ErrTaskNotFound.
+	Update(ctx context.Context, record TaskRecord) error
+	// Delete removes a task. Deleting an unknown task is not an error.
+	Delete(ctx context.Context, taskID string) error
+}
+
+// WithTaskStore persists tasks in store. Passing nil uses an in-memory
+// store, the default, which loses tasks when the process exits.
+func WithTaskStore(store TaskStore) ServerOption {
+	return func(s *MCPServer) {
+		if store == nil {
+			store = NewInMemoryTaskStore()
+		}
+		s.taskStore = store
+	}
+}
+
+// InMemoryTaskStore is a TaskStore for a single server process.
+type InMemoryTaskStore struct {
+	mu      sync.RWMutex
+	records map[string]TaskRecord
+}
+
+// NewInMemoryTaskStore creates an empty InMemoryTaskStore.
+func NewInMemoryTaskStore() *InMemoryTaskStore {
+	return &InMemoryTaskStore{records: make(map[string]TaskRecord)}
+}
+
+// Create records a new task.
+func (s *InMemoryTaskStore) Create(_ context.Context, record TaskRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Task.TaskId] = record
+	return nil
+}
+
+// Get returns the task with the given ID.
+func (s *InMemoryTaskStore) Get(_ context.Context, taskID string) (TaskRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[taskID]
+	if !ok {
+		return TaskRecord{}, fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	}
+	return record, nil
+}
+
+// List returns every stored task, ordered by task ID.
+func (s *InMemoryTaskStore) List(_ context.Context) ([]TaskRecord, error) {
+	s.mu.RLock()
+	records := make([]TaskRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	s.mu.RUnlock()
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Task.TaskId < records[j].Task.TaskId
+	})
+	return records, nil
+}
+
+// Update replaces the stored state of an existing task.
+func (s *InMemoryTaskStore) Update(_ context.Context, record TaskRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[record.Task.TaskId]; !ok {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, record.Task.TaskId)
+	}
+	s.records[record.Task.TaskId] = record
+	return nil
+}
+
+// Delete removes a task.
+func (s *InMemoryTaskStore) Delete(_ context.Context, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, taskID)
+	return nil
+}
+
+// record returns the durable state of the entry. The caller must hold
+// tasksMu.
+func (e *taskEntry) record() TaskRecord {
+	record := TaskRecord{
+		Task:      e.task,
+		SessionID: e.sessionID,
+		Owner:     e.owner,
+		ToolName:  e.toolName,
+		CreatedAt: e.createdAt,
+	}
+	switch result := e.result.(type) {
+	case *mcp.CallToolResult:
+		record.Result = result
+	case *mcp.CreateTaskResult:
+		record.Result = &mcp.CallToolResult{
+			Result:            mcp.Result{Meta: result.Meta},
+			Content:           result.Content,
+			StructuredContent: result.StructuredContent,
+			IsError:           result.IsError,
+		}
+	}
+	if e.resultErr != nil {
+		record.Error = e.resultErr.Error()
+	}
+	return record
+}
+
+// taskStoreTimeout bounds the writes the server makes to the task store in
+// the background, so a slow store cannot pile up goroutines.
+const taskStoreTimeout = 10 * time.Second
+
+// persistTask writes the current state of entry through to the task store.
+// The caller must not hold tasksMu. Write failures are logged but not fatal
+// to the task, which keeps running from memory.
+func (s *MCPServer) persistTask(entry *taskEntry) {
+	s.tasksMu.RLock()
+	record := entry.record()
+	s.tasksMu.RUnlock()
+	ctx, cancel := context.WithTimeout(context.Background(), taskStoreTimeout)
+	defer cancel()
+	if err := s.taskStore.Update(ctx, record); err != nil {
+		s.logger().Warn("mcp-go: failed to persist task", "task", record.Task.TaskId, "status", record.Task.Status, "error", err)
+	}
+}
+
+// deleteStoredTask removes an expired task from the task store, logging
+// failures: the task is already gone from memory, and the store drops it
+// on its next read once its TTL has elapsed.
+func (s *MCPServer) deleteStoredTask(taskID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskStoreTimeout)
+	defer cancel()
+	if err := s.taskStore.Delete(ctx, taskID); err != nil {
+		s.logger().Warn("mcp-go: failed to delete expired task", "task", taskID, "error", err)
+	}
+}
+
+// loadStoredTask returns a detached entry for a task that this server is not
+// running, as recorded in the task store.
+func (s *MCPServer) loadStoredTask(ctx context.Context, taskID string) (*taskEntry, error) {
+	record, err := s.taskStore.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if record.expired(s.now()) {
+		_ = s.taskStore.Delete(ctx, taskID)
+		return nil, ErrTaskExpired
+	}
+	entry := &taskEntry{
+		task:      record.Task,
+		sessionID: record.SessionID,
+		owner:     record.Owner,
+		toolName:  record.ToolName,
+		createdAt: record.CreatedAt,
+		done:      make(chan struct{}),
+		completed: record.Task.Status.IsTerminal(),
+		detached:  true,
+	}
+	if record.Result != nil {
+		entry.result = record.Result
+	}
+	if record.Error != "" {
+		entry.resultErr = fmt.Errorf("%s", record.Error)
+	}
+	if entry.completed {
+		close(entry.done)
+	}
+	return entry, nil
+}
+
+// awaitStoredTask polls the task store until a detached task reaches a
+// terminal status or ctx is done.
+func (s *MCPServer) awaitStoredTask(ctx context.Context, taskID string, pollInterval *int64) (*taskEntry, error) {
+	interval := time.Second
+	if pollInterval != nil && *pollInterval > 0 {
+		interval = time.Duration(*pollInterval) * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		entry, err := s.getTaskEntry(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if entry.completed {
+			return entry, nil
+		}
+	}
+}
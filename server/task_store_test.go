@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryTaskStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryTaskStore()
+
+	_, err := store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+	assert.ErrorIs(t, store.Update(ctx, TaskRecord{Task: mcp.NewTask("missing")}), ErrTaskNotFound)
+
+	for _, id := range []string{"b", "a"} {
+		require.NoError(t, store.Create(ctx, TaskRecord{Task: mcp.NewTask(id), ToolName: "tool"}))
+	}
+	record := TaskRecord{Task: mcp.NewTask("a"), Error: "boom"}
+	record.Task.Status = mcp.TaskStatusFailed
+	require.NoError(t, store.Update(ctx, record))
+
+	got, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, mcp.TaskStatusFailed, got.Task.Status)
+	assert.Equal(t, "boom", got.Error)
+
+	records, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "a", records[0].Task.TaskId)
+
+	require.NoError(t, store.Delete(ctx, "a"))
+	require.NoError(t, store.Delete(ctx, "a"))
+	records, _ = store.List(ctx)
+	assert.Len(t, records, 1)
+}
+
+func newTaskStoreServer(store TaskStore) *MCPServer {
+	s := NewMCPServer("test", "1.0.0",
+		WithTaskCapabilities(true, true, true),
+		WithToolCapabilities(false),
+		WithTaskStore(store),
+	)
+	s.AddTool(mcp.NewTool("echo", mcp.WithTaskSupport(mcp.TaskSupportOptional)),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("echo: " + request.GetString("text", "")), nil
+		})
+	return s
+}
+
+func taskRequest(t *testing.T, s *MCPServer, id int, method string, params map[string]any) mcp.JSONRPCResponse {
+	t.Helper()
+	msg, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	require.NoError(t, err)
+	resp, ok := s.HandleMessage(context.Background(), msg).(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a result for %s", method)
+	return resp
+}
+
+func TestWithTaskStore_SurvivesRestart(t *testing.T) {
+	store := NewInMemoryTaskStore()
+	first := newTaskStoreServer(store)
+
+	resp := taskRequest(t, first, 1, "tools/call", map[string]any{
+		"name":      "echo",
+		"arguments": map[string]any{"text": "hi"},
+		"task":      map[string]any{"ttl": 60000},
+	})
+	taskID := resp.Result.(*mcp.CreateTaskResult).Task.TaskId
+	require.Eventually(t, func() bool {
+		record, err := store.Get(context.Background(), taskID)
+		return err == nil && record.Task.Status == mcp.TaskStatusCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	// A new server process sharing the store still knows the task.
+	second := newTaskStoreServer(store)
+
+	got := taskRequest(t, second, 2, "tasks/get", map[string]any{"taskId": taskID}).Result.(mcp.GetTaskResult)
+	assert.Equal(t, mcp.TaskStatusCompleted, got.Status)
+
+	list := taskRequest(t, second, 3, "tasks/list", nil).Result.(mcp.ListTasksResult)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, taskID, list.Tasks[0].TaskId)
+
+	result := taskRequest(t, second, 4, "tasks/result", map[string]any{"taskId": taskID}).Result.(mcp.TaskResultResult)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "echo: hi", result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestWithTaskStore_FollowsTasksRunningElsewhere(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryTaskStore()
+	poll := int64(10)
+	task := mcp.NewTask("remote", mcp.WithTaskPollInterval(poll))
+	require.NoError(t, store.Create(ctx, TaskRecord{Task: task, ToolName: "echo", CreatedAt: time.Now()}))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		task.Status = mcp.TaskStatusCompleted
+		_ = store.Update(ctx, TaskRecord{Task: task, ToolName: "echo", Result: mcp.NewToolResultText("done")})
+	}()
+
+	s := newTaskStoreServer(store)
+	result := taskRequest(t, s, 1, "tasks/result", map[string]any{"taskId": "remote"}).Result.(mcp.TaskResultResult)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "done", result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestWithTaskStore_ExpiredRecords(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryTaskStore()
+	task := mcp.NewTask("old", mcp.WithTaskTTL(1000))
+	require.NoError(t, store.Create(ctx, TaskRecord{Task: task, CreatedAt: time.Now().Add(-time.Hour)}))
+
+	s := newTaskStoreServer(store)
+	list := taskRequest(t, s, 1, "tasks/list", nil).Result.(mcp.ListTasksResult)
+	assert.Empty(t, list.Tasks)
+
+	_, err := s.getTaskEntry(ctx, "old")
+	assert.ErrorIs(t, err, ErrTaskExpired)
+	_, err = store.Get(ctx, "old")
+	assert.ErrorIs(t, err, ErrTaskNotFound, "expired records are deleted on access")
+}
+
+type failingTaskStore struct{ *InMemoryTaskStore }
+
+func (failingTaskStore) Create(context.Context, TaskRecord) error {
+	return fmt.Errorf("database unavailable")
+}
+
+func TestWithTaskStore_CreateFailure(t *testing.T) {
+	s := newTaskStoreServer(failingTaskStore{NewInMemoryTaskStore()})
+	_, err := s.createTask(context.Background(), "t1", "echo", nil, nil)
+	require.ErrorContains(t, err, "database unavailable")
+
+	s.tasksMu.RLock()
+	defer s.tasksMu.RUnlock()
+	assert.Empty(t, s.tasks)
+	assert.Zero(t, s.activeTasks)
+}
+
+// unreliableTaskStore fails writes after creation, recording whether they
+// were given a deadline.
+type unreliableTaskStore struct {
+	*InMemoryTaskStore
+	deadlines chan bool
+}
+
+func (s unreliableTaskStore) Update(ctx context.Context, _ TaskRecord) error {
+	_, ok := ctx.Deadline()
+	s.deadlines <- ok
+	return fmt.Errorf("database unavailable")
+}
+
+func (s unreliableTaskStore) Delete(ctx context.Context, _ string) error {
+	_, ok := ctx.Deadline()
+	s.deadlines <- ok
+	return fmt.Errorf("database unavailable")
+}
+
+func TestWithTaskStore_WriteFailuresAreLogged(t *testing.T) {
+	var buf bytes.Buffer
+	store := unreliableTaskStore{NewInMemoryTaskStore(), make(chan bool, 10)}
+	s := NewMCPServer("test", "1.0.0", WithTaskStore(store), WithLogger(newLoggerCapturingTo(&buf)))
+
+	entry, err := s.createTask(context.Background(), "t1", "echo", nil, nil)
+	require.NoError(t, err)
+	s.persistTask(entry)
+	s.deleteStoredTask("t1")
+
+	assert.True(t, <-store.deadlines, "update has a deadline")
+	assert.True(t, <-store.deadlines, "delete has a deadline")
+	output := buf.String()
+	assert.Contains(t, output, "failed to persist task")
+	assert.Contains(t, output, "failed to delete expired task")
+	assert.Contains(t, output, "database unavailable")
+}
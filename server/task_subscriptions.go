@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// taskSubscription is a session's filter on task status notifications, set
+// with tasks/subscribe.
+type taskSubscription struct {
+	scope   mcp.TaskSubscriptionScope
+	taskIDs map[string]struct{}
+}
+
+// WithTaskSubscriptions lets clients choose which notifications/tasks/status
+// notifications they receive with tasks/subscribe: all visible tasks (the
+// default), only the tasks they created, none, plus any specific task IDs.
+// The server advertises the tasks.subscribe capability, which is an
+// extension to the MCP specification, and routes each status change only to
+// the sessions that asked for it. It requires WithTaskCapabilities.
+func WithTaskSubscriptions() ServerOption {
+	return func(s *MCPServer) {
+		s.taskSubscriptionsEnabled = true
+	}
+}
+
+// handleSubscribeTasks handles tasks/subscribe requests.
+func (s *MCPServer) handleSubscribeTasks(
+	ctx context.Context,
+	id any,
+	request mcp.SubscribeTasksRequest,
+) (*mcp.EmptyResult, *requestError) {
+	if !s.taskSubscriptionsEnabled {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("tasks subscribe %w", ErrUnsupported),
+		}
+	}
+	sessionID := getSessionID(ctx)
+	if sessionID == "" {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  errors.New("tasks/subscribe requires a session"),
+		}
+	}
+
+	sub := taskSubscription{scope: request.Params.Scope, taskIDs: make(map[string]struct{}, len(request.Params.TaskIds))}
+	switch sub.scope {
+	case "":
+		sub.scope = mcp.TaskSubscriptionAll
+	case mcp.TaskSubscriptionAll, mcp.TaskSubscriptionOwn, mcp.TaskSubscriptionNone:
+	default:
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("invalid task subscription scope: %q", sub.scope),
+		}
+	}
+	for _, taskID := range request.Params.TaskIds {
+		sub.taskIDs[taskID] = struct{}{}
+	}
+	s.taskSubscriptions.Store(sessionID, sub)
+
+	return &mcp.EmptyResult{}, nil
+}
+
+// wantsTaskStatus reports whether the session with the given ID should be
+// notified of a status change of a task owned by ownerSessionID. Sessions
+// that never subscribed are notified of every task, as without
+// WithTaskSubscriptions; subscribed ones only of tasks visible to them.
+func (s *MCPServer) wantsTaskStatus(sessionID, ownerSessionID, taskID string) bool {
+	value, ok := s.taskSubscriptions.Load(sessionID)
+	if !ok {
+		return true
+	}
+	sub := value.(taskSubscription)
+	if _, listed := sub.taskIDs[taskID]; listed && s.taskVisibleTo(ownerSessionID, sessionID) {
+		return true
+	}
+	switch sub.scope {
+	case mcp.TaskSubscriptionOwn:
+		return ownerSessionID != "" && ownerSessionID == sessionID
+	case mcp.TaskSubscriptionNone:
+		return false
+	default:
+		return s.taskVisibleTo(ownerSessionID, sessionID)
+	}
+}
+
+// routeTaskStatusNotification sends a task status notification to every
+// initialized session whose subscription selects the task.
+func (s *MCPServer) routeTaskStatusNotification(ownerSessionID, taskID string, notification mcp.JSONRPCNotification) {
+	s.sessions.Range(func(_, v any) bool {
+		session, ok := v.(ClientSession)
+		if !ok || !session.Initialized() {
+			return true
+		}
+		if s.wantsTaskStatus(session.SessionID(), ownerSessionID, taskID) {
+			// Blocked channels are reported through the OnError hook.
+			_ = s.sendNotificationToSpecificClient(session, notification)
+		}
+		return true
+	})
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSubscriptions_Capability(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true))
+	resp := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/subscribe","params":{}}`))
+	errResp, ok := resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.METHOD_NOT_FOUND, errResp.Error.Code)
+
+	server = NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true), WithTaskSubscriptions())
+	resp = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-11-25","capabilities":{},"clientInfo":{"name":"c","version":"1"}}}`))
+	result := resp.(mcp.JSONRPCResponse).Result.(mcp.InitializeResult)
+	require.NotNil(t, result.Capabilities.Tasks)
+	assert.NotNil(t, result.Capabilities.Tasks.Subscribe)
+
+	resp = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/subscribe","params":{}}`))
+	errResp, ok = resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.INVALID_REQUEST, errResp.Error.Code, "a session is required")
+}
+
+func TestTaskSubscriptions_Routing(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		want   []string // task IDs notified to the subscriber, in order
+	}{
+		{name: "no subscription", want: []string{"own-task", "other-task", "anon-task"}},
+		{name: "all", params: `{"scope":"all"}`, want: []string{"own-task", "anon-task"}},
+		{name: "default scope", params: `{}`, want: []string{"own-task", "anon-task"}},
+		{name: "own", params: `{"scope":"own"}`, want: []string{"own-task"}},
+		{name: "none", params: `{"scope":"none"}`, want: nil},
+		{name: "specific tasks", params: `{"scope":"none","taskIds":["anon-task","other-task"]}`, want: []string{"anon-task"}},
+		{name: "own plus specific", params: `{"scope":"own","taskIds":["anon-task"]}`, want: []string{"own-task", "anon-task"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true), WithTaskSubscriptions())
+			sessions := map[string]fakeSession{}
+			ctxs := map[string]context.Context{"": context.Background()}
+			for _, id := range []string{"subscriber", "other"} {
+				sessions[id] = fakeSession{
+					sessionID:           id,
+					notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+					initialized:         true,
+				}
+				require.NoError(t, server.RegisterSession(context.Background(), sessions[id]))
+				ctxs[id] = server.WithContext(context.Background(), sessions[id])
+			}
+
+			if tt.params != "" {
+				resp := server.HandleMessage(ctxs["subscriber"], []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/subscribe","params":`+tt.params+`}`))
+				require.IsType(t, mcp.JSONRPCResponse{}, resp)
+			}
+
+			for taskID, owner := range map[string]string{"own-task": "subscriber", "other-task": "other", "anon-task": ""} {
+				_, err := server.createTask(ctxs[owner], taskID, "tool", nil, nil)
+				require.NoError(t, err)
+			}
+			for _, taskID := range []string{"own-task", "other-task", "anon-task"} {
+				entry, err := server.getTaskEntry(context.Background(), taskID)
+				require.NoError(t, err)
+				server.completeTask(entry, &mcp.CallToolResult{}, nil)
+			}
+
+			var got []string
+			for len(sessions["subscriber"].notificationChannel) > 0 {
+				n := <-sessions["subscriber"].notificationChannel
+				got = append(got, n.Params.AdditionalFields["taskId"].(string))
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, sessions["other"].notificationChannel, 3, "unsubscribed sessions get everything")
+		})
+	}
+
+	t.Run("invalid scope", func(t *testing.T) {
+		server := NewMCPServer("test", "1.0.0", WithTaskCapabilities(true, true, true), WithTaskSubscriptions())
+		session := fakeSession{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		resp := server.HandleMessage(server.WithContext(context.Background(), session), []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/subscribe","params":{"scope":"mine"}}`))
+		errResp, ok := resp.(mcp.JSONRPCError)
+		require.True(t, ok, "got %T", resp)
+		assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+	})
+}
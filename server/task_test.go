@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -1793,3 +1794,141 @@ func TestTaskResultEndToEnd(t *testing.T) {
 	require.True(t, ok, "Related task should be a map")
 	assert.Equal(t, taskID, relatedTaskMap["taskId"], "Related task ID should match")
 }
+
+func TestMCPServer_SessionScopedTasks(t *testing.T) {
+	newSession := func(id string) fakeSession {
+		return fakeSession{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		opts        []ServerOption
+		wantVisible map[string][]string // caller session ID -> visible task IDs
+	}{
+		{
+			name: "default best-effort isolation",
+			wantVisible: map[string][]string{
+				"alice": {"alice-task", "anon-task"},
+				"bob":   {"anon-task"},
+				"":      {"alice-task", "anon-task"},
+			},
+		},
+		{
+			name: "scoped",
+			opts: []ServerOption{WithSessionScopedTasks()},
+			wantVisible: map[string][]string{
+				"alice": {"alice-task"},
+				"bob":   nil,
+				"":      {"anon-task"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0",
+				append([]ServerOption{WithTaskCapabilities(true, true, true)}, tt.opts...)...)
+			ctxs := map[string]context.Context{"": t.Context()}
+			sessions := map[string]fakeSession{}
+			for _, id := range []string{"alice", "bob"} {
+				sessions[id] = newSession(id)
+				require.NoError(t, server.RegisterSession(t.Context(), sessions[id]))
+				ctxs[id] = server.WithContext(t.Context(), sessions[id])
+			}
+
+			_, err := server.createTask(ctxs["alice"], "alice-task", "tool", nil, nil)
+			require.NoError(t, err)
+			_, err = server.createTask(ctxs[""], "anon-task", "tool", nil, nil)
+			require.NoError(t, err)
+
+			for caller, want := range tt.wantVisible {
+				tasks, err := server.listTasks(ctxs[caller], taskFilter{})
+				require.NoError(t, err)
+				var got []string
+				for _, task := range tasks {
+					got = append(got, task.TaskId)
+				}
+				assert.ElementsMatch(t, want, got, "tasks/list from %q", caller)
+
+				for _, taskID := range []string{"alice-task", "anon-task"} {
+					_, _, err := server.getTask(ctxs[caller], taskID)
+					if slices.Contains(want, taskID) {
+						assert.NoError(t, err, "tasks/get %s from %q", taskID, caller)
+					} else {
+						assert.ErrorIs(t, err, ErrTaskNotFound, "tasks/get %s from %q", taskID, caller)
+					}
+				}
+			}
+
+			if len(tt.opts) > 0 {
+				response := server.HandleMessage(ctxs["bob"], []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/cancel","params":{"taskId":"alice-task"}}`))
+				_, isError := response.(mcp.JSONRPCError)
+				assert.True(t, isError, "bob must not cancel alice's task")
+
+				entry, err := server.getTaskEntry(ctxs["alice"], "alice-task")
+				require.NoError(t, err)
+				server.completeTask(entry, &mcp.CallToolResult{}, nil)
+				assert.Len(t, sessions["alice"].notificationChannel, 1, "owner is notified")
+				assert.Empty(t, sessions["bob"].notificationChannel, "other sessions are not notified")
+			}
+		})
+	}
+}
+
+func TestMCPServer_HandleListTasksFilters(t *testing.T) {
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	server := NewMCPServer("test-server", "1.0.0",
+		WithTaskCapabilities(true, true, true),
+		WithClock(clock),
+	)
+	ctx := t.Context()
+	for _, task := range []struct{ id, tool string }{
+		{"task-1", "build"},
+		{"task-2", "deploy"},
+		{"task-3", "build"},
+	} {
+		_, err := server.createTask(ctx, task.id, task.tool, nil, nil)
+		require.NoError(t, err)
+		clock.Advance(time.Hour)
+	}
+	entry, err := server.getTaskEntry(ctx, "task-1")
+	require.NoError(t, err)
+	server.completeTask(entry, &mcp.CallToolResult{}, nil)
+
+	tests := []struct {
+		name    string
+		params  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filters", params: `{}`, want: []string{"task-1", "task-2", "task-3"}},
+		{name: "status", params: `{"status":["working"]}`, want: []string{"task-2", "task-3"}},
+		{name: "several statuses", params: `{"status":["completed","failed"]}`, want: []string{"task-1"}},
+		{name: "tool name", params: `{"toolName":"build"}`, want: []string{"task-1", "task-3"}},
+		{name: "created after", params: `{"createdAfter":"2025-01-01T00:30:00Z"}`, want: []string{"task-2", "task-3"}},
+		{name: "combined", params: `{"toolName":"build","status":["working"],"createdAfter":"2025-01-01T00:30:00Z"}`, want: []string{"task-3"}},
+		{name: "invalid created after", params: `{"createdAfter":"yesterday"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/list","params":`+tt.params+`}`))
+			if tt.wantErr {
+				errResp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "Expected JSONRPCError, got %T", response)
+				assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "Expected JSONRPCResponse, got %T", response)
+			var got []string
+			for _, task := range resp.Result.(mcp.ListTasksResult).Tasks {
+				got = append(got, task.TaskId)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
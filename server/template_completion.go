@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues is the maximum number of values a completion may
+// carry, per the MCP specification.
+const maxCompletionValues = 100
+
+// TemplateCompleterFunc suggests values for a variable of a resource
+// template. argument holds the variable name and the value typed so far;
+// context holds the variables the user already filled in.
+type TemplateCompleterFunc func(ctx context.Context, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error)
+
+// ResourceTemplateOption configures a resource template registered with
+// AddResourceTemplate.
+type ResourceTemplateOption func(*ServerResourceTemplate)
+
+// WithTemplateCompletion makes completion/complete requests for the
+// variable argName of the template return the suggestions of completer.
+// Registering a completer enables the completions capability.
+//
+// Requests for variables without a completer fall back to the
+// ResourceCompletionProvider.
+func WithTemplateCompletion(argName string, completer TemplateCompleterFunc) ResourceTemplateOption {
+	return func(t *ServerResourceTemplate) {
+		if t.Completions == nil {
+			t.Completions = make(map[string]TemplateCompleterFunc)
+		}
+		t.Completions[argName] = completer
+	}
+}
+
+// CompleteFromValues returns a TemplateCompleterFunc suggesting the values
+// that start with what the user typed so far, in order.
+func CompleteFromValues(values ...string) TemplateCompleterFunc {
+	return func(ctx context.Context, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+		suggestions := []string{}
+		for _, value := range values {
+			if strings.HasPrefix(value, argument.Value) {
+				suggestions = append(suggestions, value)
+			}
+		}
+		return &mcp.Completion{Values: suggestions}, nil
+	}
+}
+
+// completeResourceArgument completes a variable of the resource template
+// uri with the completer registered for it, falling back to the
+// ResourceCompletionProvider.
+func (s *MCPServer) completeResourceArgument(
+	ctx context.Context,
+	uri string,
+	argument mcp.CompleteArgument,
+	context mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	completer := s.templateCompleter(ctx, uri, argument.Name)
+	if completer == nil {
+		return s.resourceCompletionProvider.CompleteResourceArgument(ctx, uri, argument, context)
+	}
+
+	completion, err := completer(ctx, argument, context)
+	if err != nil || completion == nil {
+		return completion, err
+	}
+	if len(completion.Values) > maxCompletionValues {
+		if completion.Total == 0 {
+			completion.Total = len(completion.Values)
+		}
+		completion.Values = completion.Values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	return completion, nil
+}
+
+// templateCompleter returns the completer of the variable argName of the
+// resource template uri, preferring the templates of the current session.
+func (s *MCPServer) templateCompleter(ctx context.Context, uri string, argName string) TemplateCompleterFunc {
+	if session := ClientSessionFromContext(ctx); session != nil {
+		if sessionWithTemplates, ok := session.(SessionWithResourceTemplates); ok {
+			if template, ok := sessionWithTemplates.GetSessionResourceTemplates()[uri]; ok {
+				if completer := template.Completions[argName]; completer != nil {
+					return completer
+				}
+			}
+		}
+	}
+
+	s.resourcesMu.RLock()
+	defer s.resourcesMu.RUnlock()
+	return s.resourceTemplates[uri].completions[argName]
+}
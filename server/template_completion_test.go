@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_TemplateCompletion(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0",
+		WithResourceCompletionProvider(resourceCompletionProviderFunc(
+			func(ctx context.Context, uri string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+				return &mcp.Completion{Values: []string{"fallback"}}, nil
+			},
+		)),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("db://{table}/{column}", "Columns"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		},
+		WithTemplateCompletion("table", CompleteFromValues("users", "orders", "uploads")),
+		WithTemplateCompletion("column", func(ctx context.Context, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+			if context.Arguments["table"] != "users" {
+				return &mcp.Completion{Values: []string{}}, nil
+			}
+			values := make([]string, 150)
+			for i := range values {
+				values[i] = fmt.Sprintf("col%d", i)
+			}
+			return &mcp.Completion{Values: values}, nil
+		}),
+	)
+
+	complete := func(t *testing.T, uri, argument, value string, arguments map[string]string) mcp.CompleteResult {
+		t.Helper()
+		var params mcp.CompleteParams
+		params.Ref = mcp.ResourceReference{Type: "ref/resource", URI: uri}
+		params.Argument = mcp.CompleteArgument{Name: argument, Value: value}
+		params.Context.Arguments = arguments
+		message, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "completion/complete", "params": params})
+		require.NoError(t, err)
+
+		resp, ok := s.HandleMessage(t.Context(), message).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result, ok := resp.Result.(mcp.CompleteResult)
+		require.True(t, ok)
+		return result
+	}
+
+	t.Run("completions capability is enabled", func(t *testing.T) {
+		require.NotNil(t, s.capabilities.completions)
+		assert.True(t, *s.capabilities.completions)
+	})
+
+	t.Run("prefix match", func(t *testing.T) {
+		result := complete(t, "db://{table}/{column}", "table", "u", nil)
+		assert.Equal(t, []string{"users", "uploads"}, result.Completion.Values)
+	})
+
+	t.Run("context arguments", func(t *testing.T) {
+		result := complete(t, "db://{table}/{column}", "column", "", map[string]string{"table": "orders"})
+		assert.Empty(t, result.Completion.Values)
+	})
+
+	t.Run("values are capped", func(t *testing.T) {
+		result := complete(t, "db://{table}/{column}", "column", "", map[string]string{"table": "users"})
+		assert.Len(t, result.Completion.Values, 100)
+		assert.Equal(t, 150, result.Completion.Total)
+		assert.True(t, result.Completion.HasMore)
+	})
+
+	t.Run("falls back to the provider", func(t *testing.T) {
+		result := complete(t, "db://{table}/{column}", "schema", "", nil)
+		assert.Equal(t, []string{"fallback"}, result.Completion.Values)
+
+		result = complete(t, "other://{id}", "id", "", nil)
+		assert.Equal(t, []string{"fallback"}, result.Completion.Values)
+	})
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DisableTool marks a registered tool as temporarily unavailable. Unlike
+// DeleteTools, the tool stays listed, so hosts do not see it vanish
+// mid-session: tools/list publishes the reason under
+// mcp.ToolAvailabilityMetaKey, and calls fail with mcp.TOOL_DISABLED until
+// EnableTool is called. Disabling an already disabled tool updates the
+// reason. Per-session tools are not affected.
+func (s *MCPServer) DisableTool(name, reason string) error {
+	return s.setToolAvailability(name, mcp.ToolAvailability{Disabled: true, Reason: reason})
+}
+
+// EnableTool makes a tool disabled with DisableTool callable again.
+func (s *MCPServer) EnableTool(name string) error {
+	return s.setToolAvailability(name, mcp.ToolAvailability{})
+}
+
+func (s *MCPServer) setToolAvailability(name string, availability mcp.ToolAvailability) error {
+	s.toolsMu.Lock()
+	_, isTool := s.tools[name]
+	_, isTaskTool := s.taskTools[name]
+	if !isTool && !isTaskTool {
+		s.toolsMu.Unlock()
+		return fmt.Errorf("tool '%s' not found: %w", name, ErrToolNotFound)
+	}
+	previous, wasDisabled := s.disabledTools[name]
+	if availability.Disabled {
+		s.disabledTools[name] = availability.Reason
+	} else {
+		delete(s.disabledTools, name)
+	}
+	changed := availability.Disabled != wasDisabled || availability.Reason != previous
+	s.toolsMu.Unlock()
+
+	// The tool's _meta changed, so hosts should refresh their listing.
+	if changed && s.capabilities.tools != nil && s.capabilities.tools.listChanged {
+		s.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+	}
+	return nil
+}
+
+// withToolAvailability returns tool with its availability published in
+// _meta if it is disabled. The caller must hold toolsMu. The registered
+// tool's Meta is copied rather than modified.
+func (s *MCPServer) withToolAvailability(tool mcp.Tool) mcp.Tool {
+	reason, disabled := s.disabledTools[tool.Name]
+	if !disabled {
+		return tool
+	}
+	meta := &mcp.Meta{AdditionalFields: make(map[string]any)}
+	if tool.Meta != nil {
+		meta.ProgressToken = tool.Meta.ProgressToken
+		for k, v := range tool.Meta.AdditionalFields {
+			meta.AdditionalFields[k] = v
+		}
+	}
+	meta.AdditionalFields[mcp.ToolAvailabilityMetaKey] = mcp.ToolAvailability{Disabled: true, Reason: reason}
+	tool.Meta = meta
+	return tool
+}
+
+// toolDisabledError returns the error for a call to a disabled tool, or nil
+// if the tool is enabled.
+func (s *MCPServer) toolDisabledError(id any, name string) *requestError {
+	s.toolsMu.RLock()
+	reason, disabled := s.disabledTools[name]
+	s.toolsMu.RUnlock()
+	if !disabled {
+		return nil
+	}
+	err := fmt.Errorf("tool '%s' is temporarily disabled: %w", name, ErrToolDisabled)
+	if reason != "" {
+		err = fmt.Errorf("tool '%s' is temporarily disabled (%s): %w", name, reason, ErrToolDisabled)
+	}
+	return &requestError{
+		id:   id,
+		code: mcp.TOOL_DISABLED,
+		err:  err,
+		data: mcp.ToolAvailability{Disabled: true, Reason: reason},
+	}
+}
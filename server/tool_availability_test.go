@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_DisableTool(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithToolCapabilities(true))
+	s.AddTool(
+		mcp.NewTool("search", mcp.WithSandboxProfile(mcp.SandboxProfile{Name: "restricted"})),
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		},
+	)
+
+	ctx := context.Background()
+	listTools := func() mcp.Tool {
+		t.Helper()
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		result, ok := resp.(mcp.JSONRPCResponse)
+		require.True(t, ok, "got %T", resp)
+		tools := result.Result.(mcp.ListToolsResult).Tools
+		require.Len(t, tools, 1)
+		return tools[0]
+	}
+	callTool := func() mcp.JSONRPCMessage {
+		return s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search"}}`))
+	}
+
+	assert.ErrorIs(t, s.DisableTool("missing", "gone"), ErrToolNotFound)
+	assert.ErrorIs(t, s.EnableTool("missing"), ErrToolNotFound)
+
+	require.NoError(t, s.DisableTool("search", "index rebuild"))
+
+	tool := listTools()
+	assert.Equal(t, mcp.ToolAvailability{Disabled: true, Reason: "index rebuild"}, mcp.ToolAvailabilityFromTool(tool))
+	_, hasSandbox := mcp.SandboxProfileFromTool(tool)
+	assert.True(t, hasSandbox, "existing _meta fields are kept")
+	assert.Equal(t, mcp.ToolAvailability{}, mcp.ToolAvailabilityFromTool(s.GetTool("search").Tool), "registered tool is not modified")
+
+	resp := callTool()
+	errResp, ok := resp.(mcp.JSONRPCError)
+	require.True(t, ok, "got %T", resp)
+	assert.Equal(t, mcp.TOOL_DISABLED, errResp.Error.Code)
+	assert.Contains(t, errResp.Error.Message, "index rebuild")
+	data, err := json.Marshal(errResp.Error.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"disabled":true,"reason":"index rebuild"}`, string(data))
+
+	record := ReplayRecord{Request: mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "search"}}}
+	report := s.ReplayToolCall(ctx, record, WithReplayDryRun())
+	require.NotNil(t, report.Error)
+	assert.Equal(t, mcp.TOOL_DISABLED, report.Error.Code)
+
+	require.NoError(t, s.EnableTool("search"))
+	assert.Equal(t, mcp.ToolAvailability{}, mcp.ToolAvailabilityFromTool(listTools()))
+	assert.IsType(t, mcp.JSONRPCResponse{}, callTool())
+}
+
+func TestMCPServer_DisableTool_ListChanged(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithToolCapabilities(true))
+	s.AddTool(mcp.NewTool("search"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	notifications := make(chan mcp.JSONRPCNotification, 10)
+	require.NoError(t, s.RegisterSession(context.Background(), &fakeSession{
+		sessionID:           "s1",
+		notificationChannel: notifications,
+		initialized:         true,
+	}))
+
+	require.NoError(t, s.DisableTool("search", "maintenance"))
+	require.NoError(t, s.DisableTool("search", "maintenance"))
+	require.NoError(t, s.DisableTool("search", "still maintenance"))
+	require.NoError(t, s.EnableTool("search"))
+	require.NoError(t, s.EnableTool("search"))
+
+	assert.Len(t, notifications, 3)
+	for len(notifications) > 0 {
+		assert.Equal(t, mcp.MethodNotificationToolsListChanged, (<-notifications).Method)
+	}
+
+	require.NoError(t, s.DisableTool("search", "maintenance"))
+	<-notifications
+	s.DeleteTools("search")
+	s.AddTool(mcp.NewTool("search"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	assert.Empty(t, s.disabledTools, "deleting a tool clears its disabled state")
+}
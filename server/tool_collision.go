@@ -0,0 +1,95 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrToolNameCollision is wrapped by the errors RegisterTools and
+// RegisterTaskTools return, and AddTool, AddTaskTool and SetTools panic
+// with, when a tool is rejected because its name is already registered.
+var ErrToolNameCollision = errors.New("tool name already registered")
+
+// ToolCollisionPolicy decides what AddTool, AddTaskTool and SetTools do
+// with a tool whose name is already registered by a tool of another
+// namespace.
+type ToolCollisionPolicy int
+
+const (
+	// ToolCollisionReplace replaces the registered tool, the last
+	// registration winning. It is the default.
+	ToolCollisionReplace ToolCollisionPolicy = iota
+	// ToolCollisionError rejects the new tool: RegisterTools returns an
+	// error wrapping ErrToolNameCollision, and AddTool panics with it, like
+	// http.ServeMux.Handle does for a duplicate pattern.
+	ToolCollisionError
+	// ToolCollisionPrefix registers the new tool under its qualified name,
+	// its namespace and name joined by mcp.ToolNamespaceSeparator, keeping
+	// the registered tool. A tool without a namespace, or whose qualified
+	// name is taken as well, is rejected as with ToolCollisionError.
+	ToolCollisionPrefix
+)
+
+// WithToolCollisionPolicy sets what happens when a tool is added under a name
+// that is already registered, which is easy to run into when composing tool
+// sets from plugins, proxied servers and application code:
+//
+//	s := server.NewMCPServer("app", "1.0.0",
+//	    server.WithToolCollisionPolicy(server.ToolCollisionPrefix),
+//	)
+//	s.AddTool(mcp.NewTool("search"), searchDocs)
+//	s.AddTool(mcp.NewTool("search", mcp.WithToolNamespace("github")), searchIssues)
+//	// Tools: search, github_search
+//
+// The policy applies to tools of different namespaces only, with tools that
+// have no namespace each counting as their own: re-adding a tool with the
+// namespace it was registered with updates it whatever the policy, so a
+// source can refresh its tool set. Session tools are not affected and keep
+// overriding the server's tools of the same name.
+func WithToolCollisionPolicy(policy ToolCollisionPolicy) ServerOption {
+	return func(s *MCPServer) {
+		s.toolCollisionPolicy = policy
+	}
+}
+
+// registeredTool returns the tool or task tool registered under name. The
+// caller must hold toolsMu.
+func (s *MCPServer) registeredTool(name string) (mcp.Tool, bool) {
+	if entry, ok := s.tools[name]; ok {
+		return entry.Tool, true
+	}
+	if entry, ok := s.taskTools[name]; ok {
+		return entry.Tool, true
+	}
+	return mcp.Tool{}, false
+}
+
+// toolName returns the name to register tool under according to the
+// collision policy. The caller must hold toolsMu.
+func (s *MCPServer) toolName(tool mcp.Tool) (string, error) {
+	registered, ok := s.registeredTool(tool.Name)
+	if !ok || sameToolNamespace(registered, tool) {
+		return tool.Name, nil
+	}
+	switch s.toolCollisionPolicy {
+	case ToolCollisionError:
+		return "", fmt.Errorf("%w: %s", ErrToolNameCollision, tool.Name)
+	case ToolCollisionPrefix:
+		if tool.Namespace == "" {
+			return "", fmt.Errorf("%w: %s has no namespace to prefix it with", ErrToolNameCollision, tool.Name)
+		}
+		name := tool.QualifiedName()
+		if registered, ok := s.registeredTool(name); ok && !sameToolNamespace(registered, tool) {
+			return "", fmt.Errorf("%w: %s", ErrToolNameCollision, name)
+		}
+		return name, nil
+	}
+	return tool.Name, nil
+}
+
+// sameToolNamespace reports whether a and b belong to the same namespace.
+func sameToolNamespace(a, b mcp.Tool) bool {
+	return a.Namespace != "" && a.Namespace == b.Namespace
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func textHandler(text string) ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+func callToolText(t *testing.T, s *MCPServer, name string) string {
+	t.Helper()
+	tool := s.GetTool(name)
+	require.NotNil(t, tool, name)
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func assertCollisionPanic(t *testing.T, f func()) {
+	t.Helper()
+	defer func() {
+		err, ok := recover().(error)
+		require.True(t, ok, "expected a panic with an error")
+		assert.True(t, errors.Is(err, ErrToolNameCollision), err)
+	}()
+	f()
+}
+
+func TestToolCollisionPolicy(t *testing.T) {
+	t.Run("replace by default", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0")
+		s.AddTool(mcp.NewTool("search"), textHandler("docs"))
+		s.AddTool(mcp.NewTool("search", mcp.WithToolNamespace("github")), textHandler("issues"))
+		assert.Len(t, s.ListTools(), 1)
+		assert.Equal(t, "issues", callToolText(t, s, "search"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithToolCollisionPolicy(ToolCollisionError))
+		s.AddTool(mcp.NewTool("search"), textHandler("docs"))
+		assertCollisionPanic(t, func() {
+			s.AddTool(mcp.NewTool("search"), textHandler("other"))
+		})
+		assertCollisionPanic(t, func() {
+			s.AddTaskTool(mcp.NewTool("search", mcp.WithToolNamespace("github")), nil)
+		})
+		assert.Equal(t, "docs", callToolText(t, s, "search"))
+
+		// A namespace may update its own tools.
+		s.AddTool(mcp.NewTool("status", mcp.WithToolNamespace("github")), textHandler("v1"))
+		s.AddTool(mcp.NewTool("status", mcp.WithToolNamespace("github")), textHandler("v2"))
+		assert.Equal(t, "v2", callToolText(t, s, "status"))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithToolCollisionPolicy(ToolCollisionPrefix))
+		s.AddTool(mcp.NewTool("search"), textHandler("docs"))
+		s.AddTools(
+			ServerTool{Tool: mcp.NewTool("search", mcp.WithToolNamespace("github")), Handler: textHandler("issues")},
+			ServerTool{Tool: mcp.NewTool("search", mcp.WithToolNamespace("jira")), Handler: textHandler("tickets")},
+		)
+		assert.Equal(t, "docs", callToolText(t, s, "search"))
+		assert.Equal(t, "issues", callToolText(t, s, "github_search"))
+		assert.Equal(t, "tickets", callToolText(t, s, "jira_search"))
+		assert.Equal(t, "github_search", s.GetTool("github_search").Tool.Name)
+
+		s.AddTool(mcp.NewTool("search", mcp.WithToolNamespace("github")), textHandler("issues v2"))
+		assert.Equal(t, "issues v2", callToolText(t, s, "github_search"))
+		assert.Len(t, s.ListTools(), 3)
+
+		assertCollisionPanic(t, func() {
+			s.AddTool(mcp.NewTool("search"), textHandler("other"))
+		})
+	})
+}
+
+func TestMCPServer_RegisterTools(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithToolCollisionPolicy(ToolCollisionPrefix))
+	names, err := s.RegisterTools(
+		ServerTool{Tool: mcp.NewTool("search"), Handler: textHandler("docs")},
+		ServerTool{Tool: mcp.NewTool("search", mcp.WithToolNamespace("github")), Handler: textHandler("issues")},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search", "github_search"}, names)
+
+	// A rejected tool fails the whole call, leaving the tools unchanged.
+	_, err = s.RegisterTools(
+		ServerTool{Tool: mcp.NewTool("status", mcp.WithToolNamespace("jira")), Handler: textHandler("jira")},
+		ServerTool{Tool: mcp.NewTool("search", mcp.WithToolNamespace("jira")), Handler: textHandler("tickets")},
+		ServerTool{Tool: mcp.NewTool("search"), Handler: textHandler("other")},
+	)
+	assert.ErrorIs(t, err, ErrToolNameCollision)
+	assert.Len(t, s.ListTools(), 2)
+	assert.Nil(t, s.GetTool("status"))
+	assert.Nil(t, s.GetTool("jira_search"))
+	assert.Equal(t, "docs", callToolText(t, s, "search"))
+
+	s.AddTaskTool(mcp.NewTool("report"), nil)
+	_, err = s.RegisterTools(ServerTool{Tool: mcp.NewTool("report"), Handler: textHandler("report")})
+	assert.ErrorIs(t, err, ErrToolNameCollision)
+	_, err = s.RegisterTaskTools(ServerTaskTool{Tool: mcp.NewTool("search")})
+	assert.ErrorIs(t, err, ErrToolNameCollision)
+}
+
+func TestMCPServer_SetTools_CollisionPolicy(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithToolCollisionPolicy(ToolCollisionPrefix))
+	s.AddTool(mcp.NewTool("stale"), textHandler("stale"))
+	s.SetTools(
+		ServerTool{Tool: mcp.NewTool("search"), Handler: textHandler("docs")},
+		ServerTool{Tool: mcp.NewTool("search", mcp.WithToolNamespace("github")), Handler: textHandler("issues")},
+	)
+	assert.Nil(t, s.GetTool("stale"))
+	assert.Equal(t, "docs", callToolText(t, s, "search"))
+	assert.Equal(t, "issues", callToolText(t, s, "github_search"))
+
+	// A rejected tool leaves the previous tools in place.
+	assertCollisionPanic(t, func() {
+		s.SetTools(
+			ServerTool{Tool: mcp.NewTool("status"), Handler: textHandler("status")},
+			ServerTool{Tool: mcp.NewTool("status"), Handler: textHandler("other")},
+		)
+	})
+	assert.Len(t, s.ListTools(), 2)
+	assert.Nil(t, s.GetTool("status"))
+
+	s.AddTaskTool(mcp.NewTool("report"), nil)
+	assertCollisionPanic(t, func() {
+		s.SetTools(ServerTool{Tool: mcp.NewTool("report"), Handler: textHandler("report")})
+	})
+	assert.Equal(t, "docs", callToolText(t, s, "search"))
+}
+
+func TestTool_QualifiedName(t *testing.T) {
+	assert.Equal(t, "search", mcp.NewTool("search").QualifiedName())
+	assert.Equal(t, "github_search", mcp.NewTool("search", mcp.WithToolNamespace("github")).QualifiedName())
+}
@@ -738,3 +738,70 @@ func TestPromptFilterGetTimeEnforcement(t *testing.T) {
 		})
 	}
 }
+
+func TestWithSessionToolFilter(t *testing.T) {
+	tenantTools := map[string][]string{
+		"alice": {"search", "admin"},
+		"bob":   {"search"},
+	}
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(true),
+		WithSessionToolFilter(func(ctx context.Context, session ClientSession, tools []mcp.Tool) []mcp.Tool {
+			if session == nil {
+				return nil
+			}
+			allowed := tenantTools[session.SessionID()]
+			var filtered []mcp.Tool
+			for _, tool := range tools {
+				for _, name := range allowed {
+					if tool.Name == name {
+						filtered = append(filtered, tool)
+					}
+				}
+			}
+			return filtered
+		}),
+	)
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	server.AddTool(mcp.NewTool("search"), handler)
+	server.AddTool(mcp.NewTool("admin"), handler)
+
+	listTools := func(ctx context.Context) []string {
+		resp := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		result, ok := resp.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", resp)
+		var names []string
+		for _, tool := range result.Result.(mcp.ListToolsResult).Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	callTool := func(ctx context.Context, name string) bool {
+		resp := server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"`+name+`"}}`))
+		_, ok := resp.(mcp.JSONRPCResponse)
+		return ok
+	}
+
+	sessionCtx := func(id string) context.Context {
+		session := &sessionTestClientWithTools{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		}
+		require.NoError(t, server.RegisterSession(t.Context(), session))
+		return server.WithContext(t.Context(), session)
+	}
+	alice := sessionCtx("alice")
+	bob := sessionCtx("bob")
+
+	assert.ElementsMatch(t, []string{"search", "admin"}, listTools(alice))
+	assert.Equal(t, []string{"search"}, listTools(bob))
+	assert.Empty(t, listTools(t.Context()))
+
+	assert.True(t, callTool(alice, "admin"))
+	assert.True(t, callTool(bob, "search"))
+	assert.False(t, callTool(bob, "admin"))
+	assert.False(t, callTool(t.Context(), "search"))
+}
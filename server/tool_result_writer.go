@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type toolResultWriterKey struct{}
+
+// ToolResultWriterFromContext returns the writer a tool handler can use to
+// stream partial output to the client before returning its final result.
+// Outside of a tool call, or when the request has no session, writes fail
+// with ErrNotificationNotInitialized.
+//
+// On the streamable HTTP transport each chunk upgrades the response to an
+// SSE stream and is flushed to the client immediately.
+func ToolResultWriterFromContext(ctx context.Context) mcp.ToolResultWriter {
+	if w, ok := ctx.Value(toolResultWriterKey{}).(*toolResultWriter); ok {
+		return w
+	}
+	return &toolResultWriter{ctx: ctx}
+}
+
+// withToolResultWriter attaches a ToolResultWriter for request to ctx.
+func (s *MCPServer) withToolResultWriter(ctx context.Context, request mcp.CallToolRequest) context.Context {
+	w := &toolResultWriter{ctx: ctx, server: s}
+	if request.Params.Meta != nil {
+		w.progressToken = request.Params.Meta.ProgressToken
+	}
+	return context.WithValue(ctx, toolResultWriterKey{}, w)
+}
+
+type toolResultWriter struct {
+	ctx           context.Context
+	server        *MCPServer
+	progressToken mcp.ProgressToken
+
+	mu       sync.Mutex
+	sequence int
+}
+
+func (w *toolResultWriter) Write(p []byte) (int, error) {
+	if err := w.WriteContent(mcp.NewTextContent(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *toolResultWriter) WriteContent(content ...mcp.Content) error {
+	if w.server == nil {
+		return ErrNotificationNotInitialized
+	}
+	// Hold the lock while sending so chunks arrive in sequence order.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sequence++
+	params := map[string]any{
+		"sequence": w.sequence,
+		"content":  content,
+	}
+	if w.progressToken != nil {
+		params["progressToken"] = w.progressToken
+	}
+	return w.server.SendNotificationToClient(w.ctx, string(mcp.MethodNotificationToolResultChunk), params)
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolResultWriter_StreamableHTTP(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0", WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("generate"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		w := ToolResultWriterFromContext(ctx)
+		if _, err := fmt.Fprint(w, "Hello, "); err != nil {
+			return nil, err
+		}
+		if err := w.WriteContent(mcp.NewTextContent("world")); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("Hello, world"), nil
+	})
+	server := httptest.NewServer(NewStreamableHTTPServer(mcpServer))
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	resp.Body.Close()
+
+	resp, err = postSessionJSON(server.URL, sessionID, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":  "generate",
+			"_meta": map[string]any{"progressToken": "tok"},
+		},
+	})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var messages []map[string]any
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			var msg map[string]any
+			require.NoError(t, json.Unmarshal([]byte(data), &msg))
+			messages = append(messages, msg)
+		}
+	}
+	require.Len(t, messages, 3)
+
+	var texts []string
+	for i, msg := range messages[:2] {
+		var notification mcp.JSONRPCNotification
+		data, _ := json.Marshal(msg)
+		require.NoError(t, json.Unmarshal(data, &notification))
+		chunk, err := mcp.ParseToolResultChunk(notification)
+		require.NoError(t, err)
+		assert.Equal(t, i+1, chunk.Sequence)
+		assert.Equal(t, "tok", chunk.ProgressToken)
+		texts = append(texts, chunk.Content[0].(mcp.TextContent).Text)
+	}
+	assert.Equal(t, []string{"Hello, ", "world"}, texts)
+	assert.Contains(t, messages[2], "result", "the final result follows the chunks")
+}
+
+func TestToolResultWriterFromContext_NoToolCall(t *testing.T) {
+	w := ToolResultWriterFromContext(context.Background())
+	_, err := w.Write([]byte("x"))
+	assert.ErrorIs(t, err, ErrNotificationNotInitialized)
+}
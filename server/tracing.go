@@ -13,6 +13,7 @@ const (
 	attrToolName        = "mcp.tool.name"
 	attrSessionID       = "mcp.session.id"
 	attrProtocolVersion = "mcp.protocol.version"
+	attrErrorClass      = "mcp.error.class"
 )
 
 // WithTracer installs a tracer on the server. Spans:
@@ -21,7 +22,8 @@ const (
 //   - tool.<name> (internal) around every tool handler invocation
 //
 // Span attributes: mcp.method, mcp.tool.name (tools/call), mcp.session.id
-// (when set), mcp.protocol.version (from the Mcp-Protocol-Version header).
+// (when set), mcp.protocol.version (from the Mcp-Protocol-Version header),
+// mcp.error.class (client_error|server_error, on failed methods).
 //
 // For end-to-end propagation install a Propagator with WithPropagator as
 // well. Tool-handler panics surface in span status only when paired with
@@ -50,8 +52,10 @@ func WithPropagator(p tracing.Propagator) ServerOption {
 }
 
 // WithMetaPropagator installs a propagator that extracts trace context from
-// the MCP _meta property bag of inbound requests (per SEP-414). It covers
-// all transports including stdio, where HTTP headers are unavailable.
+// the MCP _meta property bag of inbound requests (per SEP-414) into the
+// handler context, and injects the handler's trace context into the _meta
+// of server-initiated sampling and elicitation requests. It covers all
+// transports including stdio, where HTTP headers are unavailable.
 // A nil propagator is treated as a no-op.
 func WithMetaPropagator(p tracing.MetaPropagator) ServerOption {
 	if p == nil {
@@ -72,6 +76,17 @@ func (s *MCPServer) extractMeta(ctx context.Context, meta *mcp.Meta) context.Con
 	return p.ExtractMeta(ctx, meta)
 }
 
+// injectMeta writes the trace context of ctx into the _meta bag of a
+// server-initiated request, so client handlers join the server's trace.
+// It returns meta unchanged when no MetaPropagator is installed.
+func (s *MCPServer) injectMeta(ctx context.Context, meta *mcp.Meta) *mcp.Meta {
+	p := s.metaPropagator
+	if p == nil {
+		return meta
+	}
+	return p.InjectMeta(ctx, meta)
+}
+
 func (s *MCPServer) startMessageSpan(
 	ctx context.Context,
 	headers http.Header,
@@ -101,6 +116,7 @@ func (s *MCPServer) startMessageSpan(
 
 	return ctx, func(resp mcp.JSONRPCMessage) {
 		if e, ok := resp.(mcp.JSONRPCError); ok {
+			span.SetAttributes(tracing.String(attrErrorClass, string(ClassifyCode(e.Error.Code))))
 			span.SetStatus(tracing.StatusError, e.Error.Message)
 		}
 		span.End()
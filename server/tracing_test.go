@@ -94,3 +94,78 @@ func TestWithTracer_RegistersToolMiddleware(t *testing.T) {
 	assert.Contains(t, tr.spans, "mcp.tools/call")
 	assert.Contains(t, tr.spans, "tool.echo")
 }
+
+// traceIDMetaPropagator carries a trace ID stored in the context under
+// traceIDKey through the "traceid" _meta field.
+type traceIDMetaPropagator struct{}
+
+type traceIDKey struct{}
+
+func (traceIDMetaPropagator) InjectMeta(ctx context.Context, meta *mcp.Meta) *mcp.Meta {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	if id == "" {
+		return meta
+	}
+	if meta == nil {
+		meta = &mcp.Meta{}
+	}
+	if meta.AdditionalFields == nil {
+		meta.AdditionalFields = map[string]any{}
+	}
+	meta.AdditionalFields["traceid"] = id
+	return meta
+}
+
+func (traceIDMetaPropagator) ExtractMeta(ctx context.Context, meta *mcp.Meta) context.Context {
+	if meta == nil {
+		return ctx
+	}
+	if id, ok := meta.AdditionalFields["traceid"].(string); ok {
+		return context.WithValue(ctx, traceIDKey{}, id)
+	}
+	return ctx
+}
+
+type capturingSamplingSession struct {
+	mockSession
+	sampling    mcp.CreateMessageRequest
+	elicitation mcp.ElicitationRequest
+}
+
+func (c *capturingSamplingSession) RequestSampling(_ context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	c.sampling = request
+	return &mcp.CreateMessageResult{}, nil
+}
+
+func (c *capturingSamplingSession) RequestElicitation(_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	c.elicitation = request
+	return &mcp.ElicitationResult{}, nil
+}
+
+func TestWithMetaPropagator_BridgesHandlerContextToServerRequests(t *testing.T) {
+	s := NewMCPServer("trace-srv", "1.0", WithMetaPropagator(traceIDMetaPropagator{}))
+	session := &capturingSamplingSession{mockSession: mockSession{sessionID: "s1"}}
+
+	var handlerTraceID string
+	s.AddTool(mcp.NewTool("delegate"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		handlerTraceID, _ = ctx.Value(traceIDKey{}).(string)
+		if _, err := s.RequestSampling(ctx, mcp.CreateMessageRequest{}); err != nil {
+			return nil, err
+		}
+		if _, err := s.RequestElicitation(ctx, mcp.ElicitationRequest{Params: mcp.ElicitationParams{Message: "ok?", RequestedSchema: map[string]any{"type": "object"}}}); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	ctx := s.WithContext(t.Context(), session)
+	resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delegate","_meta":{"traceid":"abc123"}}}`))
+	_, ok := resp.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", resp)
+
+	assert.Equal(t, "abc123", handlerTraceID, "inbound _meta is extracted into the handler context")
+	require.NotNil(t, session.sampling.Meta)
+	assert.Equal(t, "abc123", session.sampling.Meta.AdditionalFields["traceid"])
+	require.NotNil(t, session.elicitation.Params.Meta)
+	assert.Equal(t, "abc123", session.elicitation.Params.Meta.AdditionalFields["traceid"])
+}
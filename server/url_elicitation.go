@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultURLElicitationTTL is how long URL mode elicitations are tracked
+// unless configured with WithURLElicitationTTL.
+const DefaultURLElicitationTTL = 10 * time.Minute
+
+var (
+	// ErrElicitationNotFound is returned when an elicitation ID is not
+	// tracked, for example because it already expired.
+	ErrElicitationNotFound = errors.New("elicitation not found")
+	// ErrElicitationExpired is returned by AwaitElicitation when the
+	// elicitation was not completed within its TTL.
+	ErrElicitationExpired = errors.New("elicitation expired")
+	// ErrElicitationNotAccepted is returned by AwaitElicitation when the
+	// user declined or cancelled the URL mode elicitation.
+	ErrElicitationNotAccepted = errors.New("elicitation not accepted")
+)
+
+// ElicitationNotAcceptedError reports that the user declined or cancelled
+// a URL mode elicitation, with the reason the client gave, if any. It
+// matches ErrElicitationNotAccepted with errors.Is.
+type ElicitationNotAcceptedError struct {
+	Action mcp.ElicitationResponseAction
+	Reason mcp.ElicitationDeclineReason
+}
+
+// Error renders the action the user chose and its reason.
+func (e *ElicitationNotAcceptedError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s: user chose %s", ErrElicitationNotAccepted, e.Action)
+	}
+	return fmt.Sprintf("%s: user chose %s (%s)", ErrElicitationNotAccepted, e.Action, e.Reason)
+}
+
+// Unwrap returns ErrElicitationNotAccepted.
+func (e *ElicitationNotAcceptedError) Unwrap() error {
+	return ErrElicitationNotAccepted
+}
+
+// urlElicitation is a URL mode elicitation waiting for its out-of-band
+// interaction to complete.
+type urlElicitation struct {
+	sessionID string
+	expiresAt time.Time
+	done      chan struct{}
+	completed bool
+	payload   any
+	err       error
+}
+
+// urlElicitationRegistry correlates URL mode elicitations with their
+// completion callbacks.
+type urlElicitationRegistry struct {
+	mu      sync.Mutex
+	entries map[string]*urlElicitation
+}
+
+// WithURLElicitationTTL sets how long RequestURLElicitation tracks an
+// elicitation for CompleteElicitation and AwaitElicitation. Defaults to
+// DefaultURLElicitationTTL.
+func WithURLElicitationTTL(ttl time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.urlElicitationTTL = ttl
+	}
+}
+
+// trackURLElicitation starts tracking an elicitation requested from the
+// session with the given ID, dropping expired ones.
+func (s *MCPServer) trackURLElicitation(elicitationID, sessionID string) {
+	ttl := s.urlElicitationTTL
+	if ttl <= 0 {
+		ttl = DefaultURLElicitationTTL
+	}
+	now := s.now()
+
+	r := &s.urlElicitations
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[string]*urlElicitation)
+	}
+	for id, entry := range r.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(r.entries, id)
+		}
+	}
+	r.entries[elicitationID] = &urlElicitation{
+		sessionID: sessionID,
+		expiresAt: now.Add(ttl),
+		done:      make(chan struct{}),
+	}
+}
+
+// finishURLElicitation resolves a tracked elicitation and returns the
+// session it was requested from.
+func (s *MCPServer) finishURLElicitation(elicitationID string, payload any, err error) (string, error) {
+	r := &s.urlElicitations
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[elicitationID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrElicitationNotFound, elicitationID)
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(r.entries, elicitationID)
+		return "", fmt.Errorf("%w: %s", ErrElicitationExpired, elicitationID)
+	}
+	if entry.completed {
+		return entry.sessionID, nil
+	}
+	entry.completed = true
+	entry.payload, entry.err = payload, err
+	close(entry.done)
+	return entry.sessionID, nil
+}
+
+// CompleteElicitation records that the out-of-band interaction of a URL
+// mode elicitation started with RequestURLElicitation has finished, for
+// example from the HTTP handler of the browser callback. It wakes up
+// AwaitElicitation with payload and sends notifications/elicitation/complete
+// to the session that requested the elicitation. Completing an elicitation
+// twice keeps the first payload.
+//
+// It returns ErrElicitationNotFound for unknown IDs and ErrElicitationExpired
+// once the elicitation's TTL has passed.
+func (s *MCPServer) CompleteElicitation(elicitationID string, payload any) error {
+	sessionID, err := s.finishURLElicitation(elicitationID, payload, nil)
+	if err != nil {
+		return err
+	}
+	if value, ok := s.sessions.Load(sessionID); ok {
+		if session, ok := value.(ClientSession); ok {
+			// The client may have disconnected; the waiting handler still
+			// receives the payload.
+			_ = s.SendElicitationComplete(context.Background(), session, elicitationID)
+		}
+	}
+	return nil
+}
+
+// AwaitElicitation waits until CompleteElicitation is called for a URL
+// mode elicitation started with RequestURLElicitation and returns its
+// payload. It returns an *ElicitationNotAcceptedError, matching
+// ErrElicitationNotAccepted, if the user declined or cancelled the request,
+// ErrElicitationExpired if the TTL passes first, ErrElicitationNotFound for
+// unknown IDs, and the context's error if ctx is done first.
+func (s *MCPServer) AwaitElicitation(ctx context.Context, elicitationID string) (any, error) {
+	r := &s.urlElicitations
+	r.mu.Lock()
+	entry, ok := r.entries[elicitationID]
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrElicitationNotFound, elicitationID)
+	}
+
+	select {
+	case <-entry.done:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return entry.payload, entry.err
+	default:
+	}
+
+	timer := time.NewTimer(entry.expiresAt.Sub(s.now()))
+	defer timer.Stop()
+	select {
+	case <-entry.done:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return entry.payload, entry.err
+	case <-timer.C:
+		r.mu.Lock()
+		delete(r.entries, elicitationID)
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrElicitationExpired, elicitationID)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func acceptingElicitationSession(sessionID string, action mcp.ElicitationResponseAction) *mockElicitationSession {
+	return &mockElicitationSession{
+		sessionID: sessionID,
+		result:    &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{Action: action}},
+	}
+}
+
+func TestMCPServer_AwaitElicitation(t *testing.T) {
+	s := NewMCPServer("test", "1.0.0", WithElicitation())
+	session := acceptingElicitationSession("browser", mcp.ElicitationResponseActionAccept)
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	ctx := context.Background()
+
+	_, err := s.RequestURLElicitation(ctx, session, "auth-1", "https://example.com/auth", "Sign in")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	var payload any
+	var awaitErr error
+	go func() {
+		defer close(done)
+		payload, awaitErr = s.AwaitElicitation(ctx, "auth-1")
+	}()
+
+	require.NoError(t, s.CompleteElicitation("auth-1", map[string]string{"token": "abc"}))
+	<-done
+	require.NoError(t, awaitErr)
+	assert.Equal(t, map[string]string{"token": "abc"}, payload)
+
+	select {
+	case notification := <-session.notifyChan:
+		assert.Equal(t, string(mcp.MethodNotificationElicitationComplete), notification.Method)
+	default:
+		t.Fatal("expected an elicitation complete notification")
+	}
+
+	// Completing twice keeps the first payload.
+	require.NoError(t, s.CompleteElicitation("auth-1", "second"))
+	payload, err = s.AwaitElicitation(ctx, "auth-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": "abc"}, payload)
+
+	assert.ErrorIs(t, s.CompleteElicitation("unknown", nil), ErrElicitationNotFound)
+	_, err = s.AwaitElicitation(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrElicitationNotFound)
+}
+
+func TestMCPServer_AwaitElicitation_Failures(t *testing.T) {
+	t.Run("declined", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation())
+		session := acceptingElicitationSession("browser", mcp.ElicitationResponseActionDecline)
+		_, err := s.RequestURLElicitation(context.Background(), session, "auth-1", "https://example.com/auth", "Sign in")
+		require.NoError(t, err)
+
+		_, err = s.AwaitElicitation(context.Background(), "auth-1")
+		assert.ErrorIs(t, err, ErrElicitationNotAccepted)
+	})
+
+	t.Run("declined with reason", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation())
+		session := &mockElicitationSession{
+			sessionID: "browser",
+			result:    mcp.NewElicitationDeclineResultWithReason(mcp.ElicitationDeclineReasonPrivacy),
+		}
+		result, err := s.RequestURLElicitation(context.Background(), session, "auth-1", "https://example.com/auth", "Sign in")
+		require.NoError(t, err)
+		assert.Equal(t, mcp.ElicitationDeclineReasonPrivacy, result.Reason)
+
+		_, err = s.AwaitElicitation(context.Background(), "auth-1")
+		var notAccepted *ElicitationNotAcceptedError
+		require.ErrorAs(t, err, &notAccepted)
+		assert.ErrorIs(t, err, ErrElicitationNotAccepted)
+		assert.Equal(t, mcp.ElicitationResponseActionDecline, notAccepted.Action)
+		assert.Equal(t, mcp.ElicitationDeclineReasonPrivacy, notAccepted.Reason)
+		assert.EqualError(t, err, "elicitation not accepted: user chose decline (privacy)")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation(), WithURLElicitationTTL(20*time.Millisecond))
+		session := acceptingElicitationSession("browser", mcp.ElicitationResponseActionAccept)
+		_, err := s.RequestURLElicitation(context.Background(), session, "auth-1", "https://example.com/auth", "Sign in")
+		require.NoError(t, err)
+
+		_, err = s.AwaitElicitation(context.Background(), "auth-1")
+		assert.ErrorIs(t, err, ErrElicitationExpired)
+		assert.ErrorIs(t, s.CompleteElicitation("auth-1", nil), ErrElicitationNotFound)
+	})
+
+	t.Run("expired before completion", func(t *testing.T) {
+		clock := NewManualClock(time.Now())
+		s := NewMCPServer("test", "1.0.0", WithElicitation(), WithClock(clock), WithURLElicitationTTL(time.Minute))
+		session := acceptingElicitationSession("browser", mcp.ElicitationResponseActionAccept)
+		_, err := s.RequestURLElicitation(context.Background(), session, "auth-1", "https://example.com/auth", "Sign in")
+		require.NoError(t, err)
+
+		clock.Advance(time.Minute)
+		assert.ErrorIs(t, s.CompleteElicitation("auth-1", nil), ErrElicitationExpired)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		s := NewMCPServer("test", "1.0.0", WithElicitation())
+		session := acceptingElicitationSession("browser", mcp.ElicitationResponseActionAccept)
+		_, err := s.RequestURLElicitation(context.Background(), session, "auth-1", "https://example.com/auth", "Sign in")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = s.AwaitElicitation(ctx, "auth-1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
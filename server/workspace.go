@@ -0,0 +1,348 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/internal/fileuri"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrOutsideWorkspace is returned when a path does not lie inside any root
+// of a Workspace.
+var ErrOutsideWorkspace = errors.New("path is outside the workspace roots")
+
+// WorkspaceRoot is a file:// root of the client with its local path.
+type WorkspaceRoot struct {
+	Name string
+	URI  string
+	// Path is the absolute local path of the root, with symbolic links
+	// resolved when WithWorkspaceDirs bounds the roots.
+	Path string
+	// Meta holds the fields of the root's _meta, if any.
+	Meta map[string]any
+}
+
+// WorkspaceAccessFunc decides whether a tool may access relPath, a
+// slash-separated path relative to root. For a server resource read with
+// Workspace.ReadResource by a URI other than file://, root is the zero
+// WorkspaceRoot and relPath the URI. Returning an error denies access and
+// is reported to the caller.
+type WorkspaceAccessFunc func(ctx context.Context, root WorkspaceRoot, relPath string) error
+
+// WithWorkspaceDirs bounds every Workspace to dirs. The roots a client
+// lists are intersected with them: a root inside one of dirs is kept, a root
+// containing some of dirs is narrowed to them, and any other root is left
+// out, so a client declaring file:/// as a root reaches no more than dirs,
+// and no directory at all if dirs is empty. Symbolic links are resolved
+// before the comparison. Without this option, the client's roots are
+// trusted as they are.
+func WithWorkspaceDirs(dirs ...string) ServerOption {
+	return func(s *MCPServer) {
+		s.workspaceDirs = make([]string, 0, len(dirs))
+		for _, dir := range dirs {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				continue
+			}
+			if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+				abs = resolved
+			}
+			s.workspaceDirs = append(s.workspaceDirs, abs)
+		}
+	}
+}
+
+// WithWorkspaceAccess installs a check that every Workspace consults before
+// opening, reading or listing a path, for example to hide secrets or honour
+// per-root permissions carried in the root's metadata.
+func WithWorkspaceAccess(check WorkspaceAccessFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.workspaceAccess = check
+	}
+}
+
+// Workspace gives tools access to the files below the roots of a session.
+// Every path is confined to the roots: relative paths are resolved against
+// each root in order, absolute paths and file:// URIs must lie inside one,
+// and symbolic links cannot escape them.
+type Workspace struct {
+	ctx    context.Context
+	server *MCPServer
+	roots  []WorkspaceRoot
+	access WorkspaceAccessFunc
+}
+
+// workspaceLocation is a path inside a workspace root.
+type workspaceLocation struct {
+	root    WorkspaceRoot
+	relPath string // slash-separated, "." for the root itself
+}
+
+// Workspace returns the workspace of the session in ctx, built from the
+// roots it lists with RequestRoots. Roots that are not file:// URIs are
+// left out, and with WithWorkspaceDirs the roots are intersected with the
+// server's directories. With WithRootsCache, the roots are not requested
+// again until they change.
+func (s *MCPServer) Workspace(ctx context.Context) (*Workspace, error) {
+	result, err := s.RequestRoots(ctx, mcp.ListRootsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	ws := &Workspace{ctx: ctx, server: s, access: s.workspaceAccess}
+	for _, root := range result.Roots {
+		rootPath, ok := fileuri.ToPath(root.URI)
+		if !ok {
+			continue
+		}
+		wr := WorkspaceRoot{Name: root.Name, URI: root.URI, Path: rootPath}
+		if root.Meta != nil {
+			wr.Meta = root.Meta.AdditionalFields
+		}
+		if s.workspaceDirs == nil {
+			ws.roots = append(ws.roots, wr)
+			continue
+		}
+		ws.roots = append(ws.roots, boundRoot(wr, s.workspaceDirs)...)
+	}
+	return ws, nil
+}
+
+// boundRoot intersects root with dirs: it returns root itself if it lies
+// inside one of dirs, and a root for each of dirs inside root otherwise.
+func boundRoot(root WorkspaceRoot, dirs []string) []WorkspaceRoot {
+	resolved, err := filepath.EvalSymlinks(root.Path)
+	if err != nil {
+		return nil
+	}
+	root.Path = resolved
+	for _, dir := range dirs {
+		if within(dir, resolved) {
+			return []WorkspaceRoot{root}
+		}
+	}
+	var narrowed []WorkspaceRoot
+	for _, dir := range dirs {
+		if within(resolved, dir) {
+			wr := root
+			wr.URI, wr.Path = fileuri.FromPath(dir), dir
+			narrowed = append(narrowed, wr)
+		}
+	}
+	return narrowed
+}
+
+// within reports whether p is dir or lies below it.
+func within(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && (rel == "." || filepath.IsLocal(rel))
+}
+
+// Roots returns the roots of the workspace.
+func (ws *Workspace) Roots() []WorkspaceRoot {
+	return slices.Clone(ws.roots)
+}
+
+// Resolve returns the absolute local path of name, the first existing one
+// if name is relative and several roots contain it. The path is checked
+// through the root, so it fails if a symbolic link leads out of the root;
+// prefer Open and ReadFile, which keep that guarantee while the file is
+// used.
+func (ws *Workspace) Resolve(name string) (string, error) {
+	loc, err := ws.find(name)
+	if err != nil {
+		return "", err
+	}
+	root, err := os.OpenRoot(loc.root.Path)
+	if err != nil {
+		return "", err
+	}
+	defer root.Close()
+	if _, err := root.Stat(filepath.FromSlash(loc.relPath)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	return loc.path(), nil
+}
+
+// Open opens the file name for reading.
+func (ws *Workspace) Open(name string) (*os.File, error) {
+	loc, err := ws.find(name)
+	if err != nil {
+		return nil, err
+	}
+	root, err := os.OpenRoot(loc.root.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	return root.Open(filepath.FromSlash(loc.relPath))
+}
+
+// ReadFile reads the file name.
+func (ws *Workspace) ReadFile(name string) ([]byte, error) {
+	loc, err := ws.find(name)
+	if err != nil {
+		return nil, err
+	}
+	return loc.readFile()
+}
+
+// ReadResource reads name as resource contents, so tools can embed
+// workspace files in their results. URIs other than file:// are read from
+// the resources registered on the server, once the WithWorkspaceAccess
+// check allows them. A file in the workspace is read
+// through the server's resource or template for its file:// URI if there is
+// one, for example one registered by the fsresource provider, and from the
+// root otherwise, as text if it is valid UTF-8 and as a blob if not.
+func (ws *Workspace) ReadResource(name string) ([]mcp.ResourceContents, error) {
+	if !strings.HasPrefix(name, "file://") && strings.Contains(name, "://") {
+		if ws.access != nil {
+			if err := ws.access(ws.ctx, WorkspaceRoot{}, name); err != nil {
+				return nil, err
+			}
+		}
+		return ws.readServerResource(name)
+	}
+	loc, err := ws.find(name)
+	if err != nil {
+		return nil, err
+	}
+	uri := fileuri.FromPath(loc.path())
+	contents, err := ws.readServerResource(uri)
+	if !errors.Is(err, ErrResourceNotFound) {
+		return contents, err
+	}
+	data, err := loc.readFile()
+	if err != nil {
+		return nil, err
+	}
+	mimeType, _, _ := mime.ParseMediaType(mime.TypeByExtension(path.Ext(loc.relPath)))
+	if mimeType == "" {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	if utf8.Valid(data) && !strings.ContainsRune(string(data), 0) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}}, nil
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}}, nil
+}
+
+// readServerResource reads uri through the resources and templates of the
+// server, as a resources/read request of the session would.
+func (ws *Workspace) readServerResource(uri string) ([]mcp.ResourceContents, error) {
+	request := mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: uri}}
+	result, reqErr := ws.server.handleReadResource(ws.ctx, nil, request)
+	if reqErr != nil {
+		return nil, reqErr.err
+	}
+	return result.Contents, nil
+}
+
+// Glob returns the absolute paths of the files matching pattern in every
+// root, using the syntax of path.Match with slash-separated patterns
+// relative to the roots. Paths denied by WithWorkspaceAccess are left out.
+func (ws *Workspace) Glob(pattern string) ([]string, error) {
+	var matches []string
+	for _, wr := range ws.roots {
+		root, err := os.OpenRoot(wr.Path)
+		if err != nil {
+			continue
+		}
+		found, err := fs.Glob(root.FS(), pattern)
+		root.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, relPath := range found {
+			if ws.access != nil && ws.access(ws.ctx, wr, relPath) != nil {
+				continue
+			}
+			matches = append(matches, filepath.Join(wr.Path, filepath.FromSlash(relPath)))
+		}
+	}
+	return matches, nil
+}
+
+// find locates name in the workspace and checks access to it.
+func (ws *Workspace) find(name string) (workspaceLocation, error) {
+	candidates, err := ws.locate(name)
+	if err != nil {
+		return workspaceLocation{}, err
+	}
+	loc := candidates[0]
+	for _, candidate := range candidates {
+		if _, err := os.Lstat(candidate.path()); err == nil {
+			loc = candidate
+			break
+		}
+	}
+	if ws.access != nil {
+		if err := ws.access(ws.ctx, loc.root, loc.relPath); err != nil {
+			return workspaceLocation{}, err
+		}
+	}
+	return loc, nil
+}
+
+// locate returns the locations name may refer to: the innermost root
+// containing an absolute path or URI, or every root for a relative path.
+func (ws *Workspace) locate(name string) ([]workspaceLocation, error) {
+	if strings.HasPrefix(name, "file://") {
+		p, ok := fileuri.ToPath(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrOutsideWorkspace, name)
+		}
+		name = p
+	}
+
+	if filepath.IsAbs(name) {
+		var best *workspaceLocation
+		for _, wr := range ws.roots {
+			rel, err := filepath.Rel(wr.Path, filepath.Clean(name))
+			if err != nil || !filepath.IsLocal(rel) && rel != "." {
+				continue
+			}
+			if best == nil || len(wr.Path) > len(best.root.Path) {
+				best = &workspaceLocation{root: wr, relPath: filepath.ToSlash(rel)}
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("%w: %s", ErrOutsideWorkspace, name)
+		}
+		return []workspaceLocation{*best}, nil
+	}
+
+	if !filepath.IsLocal(name) || len(ws.roots) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrOutsideWorkspace, name)
+	}
+	relPath := filepath.ToSlash(filepath.Clean(name))
+	locations := make([]workspaceLocation, len(ws.roots))
+	for i, wr := range ws.roots {
+		locations[i] = workspaceLocation{root: wr, relPath: relPath}
+	}
+	return locations, nil
+}
+
+func (loc workspaceLocation) path() string {
+	return filepath.Join(loc.root.Path, filepath.FromSlash(loc.relPath))
+}
+
+// readFile reads the file at loc without following links out of its root.
+func (loc workspaceLocation) readFile() ([]byte, error) {
+	root, err := os.OpenRoot(loc.root.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	return root.ReadFile(filepath.FromSlash(loc.relPath))
+}
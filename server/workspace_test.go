@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/internal/fileuri"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_Workspace(t *testing.T) {
+	repo, docs, outside := t.TempDir(), t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(repo, "main.go"):          "package main\n",
+		filepath.Join(repo, "pkg", "a.go"):      "package pkg\n",
+		filepath.Join(repo, ".env"):             "SECRET=1\n",
+		filepath.Join(repo, "logo.bin"):         "\x00\x01\x02",
+		filepath.Join(docs, "guide.md"):         "# Guide\n",
+		filepath.Join(docs, "main.go"):          "package docs\n",
+		filepath.Join(outside, "passwords.txt"): "hunter2\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+	}
+	require.NoError(t, os.Symlink(outside, filepath.Join(repo, "escape")))
+
+	errDenied := errors.New("denied")
+	s := NewMCPServer("test", "1.0.0",
+		WithWorkspaceAccess(func(ctx context.Context, root WorkspaceRoot, relPath string) error {
+			if strings.HasPrefix(filepath.Base(relPath), ".") || strings.HasPrefix(relPath, "secret://") {
+				return errDenied
+			}
+			return nil
+		}),
+	)
+	session := &mockRootsSession{sessionID: "ws", result: &mcp.ListRootsResult{Roots: []mcp.Root{
+		{Name: "repo", URI: fileuri.FromPath(repo), Meta: &mcp.Meta{AdditionalFields: map[string]any{"readOnly": true}}},
+		{Name: "docs", URI: fileuri.FromPath(docs)},
+		{Name: "remote", URI: "https://example.com/repo"},
+	}}}
+	ws, err := s.Workspace(s.WithContext(t.Context(), session))
+	require.NoError(t, err)
+
+	t.Run("roots", func(t *testing.T) {
+		roots := ws.Roots()
+		require.Len(t, roots, 2, "non-file roots are left out")
+		assert.Equal(t, repo, roots[0].Path)
+		assert.Equal(t, map[string]any{"readOnly": true}, roots[0].Meta)
+		assert.Equal(t, docs, roots[1].Path)
+	})
+
+	t.Run("relative paths use the first root containing them", func(t *testing.T) {
+		data, err := ws.ReadFile("main.go")
+		require.NoError(t, err)
+		assert.Equal(t, "package main\n", string(data))
+
+		f, err := ws.Open("guide.md")
+		require.NoError(t, err)
+		defer f.Close()
+		data, err = io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "# Guide\n", string(data))
+	})
+
+	t.Run("absolute paths and uris", func(t *testing.T) {
+		data, err := ws.ReadFile(filepath.Join(docs, "main.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "package docs\n", string(data))
+
+		resolved, err := ws.Resolve(fileuri.FromPath(filepath.Join(repo, "pkg", "a.go")))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(repo, "pkg", "a.go"), resolved)
+
+		_, err = ws.Resolve("escape/passwords.txt")
+		assert.Error(t, err, "symbolic links cannot leave the root")
+	})
+
+	t.Run("paths outside the roots are rejected", func(t *testing.T) {
+		for _, name := range []string{
+			"../" + filepath.Base(outside) + "/passwords.txt",
+			filepath.Join(outside, "passwords.txt"),
+			fileuri.FromPath(filepath.Join(outside, "passwords.txt")),
+		} {
+			_, err := ws.ReadFile(name)
+			assert.ErrorIs(t, err, ErrOutsideWorkspace, name)
+		}
+
+		_, err := ws.ReadFile("escape/passwords.txt")
+		assert.Error(t, err, "symbolic links cannot leave the root")
+	})
+
+	t.Run("access check", func(t *testing.T) {
+		_, err := ws.ReadFile(".env")
+		assert.ErrorIs(t, err, errDenied)
+
+		matches, err := ws.Glob("*")
+		require.NoError(t, err)
+		assert.NotContains(t, matches, filepath.Join(repo, ".env"))
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		matches, err := ws.Glob("*.go")
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(repo, "main.go"), filepath.Join(docs, "main.go")}, matches)
+
+		matches, err = ws.Glob("pkg/*.go")
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(repo, "pkg", "a.go")}, matches)
+	})
+
+	t.Run("read resource", func(t *testing.T) {
+		contents, err := ws.ReadResource("guide.md")
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		text, ok := contents[0].(mcp.TextResourceContents)
+		require.True(t, ok)
+		assert.Equal(t, fileuri.FromPath(filepath.Join(docs, "guide.md")), text.URI)
+		assert.Equal(t, "# Guide\n", text.Text)
+
+		contents, err = ws.ReadResource("logo.bin")
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		_, ok = contents[0].(mcp.BlobResourceContents)
+		assert.True(t, ok)
+	})
+
+	t.Run("read resource through the server's resources", func(t *testing.T) {
+		mainURI := fileuri.FromPath(filepath.Join(repo, "main.go"))
+		s.AddResource(mcp.NewResource(mainURI, "main.go"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "text/x-go", Text: "served"}}, nil
+		})
+		s.AddResource(mcp.NewResource("config://app", "app"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "debug=true"}}, nil
+		})
+		s.AddResource(mcp.NewResource("secret://token", "token"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "s3cr3t"}}, nil
+		})
+		s.AddResource(mcp.NewResource(fileuri.FromPath(filepath.Join(outside, "passwords.txt")), "passwords"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "hunter2"}}, nil
+		})
+
+		contents, err := ws.ReadResource("main.go")
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		assert.Equal(t, mcp.TextResourceContents{URI: mainURI, MIMEType: "text/x-go", Text: "served"}, contents[0])
+
+		contents, err = ws.ReadResource("config://app")
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		assert.Equal(t, "debug=true", contents[0].(mcp.TextResourceContents).Text)
+
+		_, err = ws.ReadResource("secret://token")
+		assert.ErrorIs(t, err, errDenied, "server resources are subject to the access check")
+
+		_, err = ws.ReadResource(fileuri.FromPath(filepath.Join(outside, "passwords.txt")))
+		assert.ErrorIs(t, err, ErrOutsideWorkspace, "file resources outside the roots stay out of reach")
+
+		_, err = ws.ReadResource("config://missing")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
+
+func TestMCPServer_WorkspaceDirs(t *testing.T) {
+	base := t.TempDir()
+	project, other := filepath.Join(base, "project"), filepath.Join(base, "other")
+	for _, dir := range []string{filepath.Join(project, "src"), other} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(other, "secret.txt"), []byte("secret"), 0o644))
+	require.NoError(t, os.Symlink(other, filepath.Join(project, "link")))
+
+	workspace := func(t *testing.T, dirs []string, roots ...mcp.Root) *Workspace {
+		s := NewMCPServer("test", "1.0.0", WithWorkspaceDirs(dirs...))
+		session := &mockRootsSession{sessionID: "ws", result: &mcp.ListRootsResult{Roots: roots}}
+		ws, err := s.Workspace(s.WithContext(t.Context(), session))
+		require.NoError(t, err)
+		return ws
+	}
+	paths := func(ws *Workspace) []string {
+		var paths []string
+		for _, root := range ws.Roots() {
+			paths = append(paths, root.Path)
+		}
+		return paths
+	}
+
+	t.Run("roots containing the directories are narrowed", func(t *testing.T) {
+		ws := workspace(t, []string{project}, mcp.Root{Name: "everything", URI: "file:///"})
+		roots := ws.Roots()
+		require.Len(t, roots, 1)
+		assert.Equal(t, "everything", roots[0].Name)
+		assert.Equal(t, project, roots[0].Path)
+		assert.Equal(t, fileuri.FromPath(project), roots[0].URI)
+
+		_, err := ws.ReadFile(filepath.Join(other, "secret.txt"))
+		assert.ErrorIs(t, err, ErrOutsideWorkspace)
+	})
+
+	t.Run("roots inside the directories are kept", func(t *testing.T) {
+		ws := workspace(t, []string{project}, mcp.Root{URI: fileuri.FromPath(filepath.Join(project, "src"))})
+		assert.Equal(t, []string{filepath.Join(project, "src")}, paths(ws))
+	})
+
+	t.Run("other roots are left out", func(t *testing.T) {
+		ws := workspace(t, []string{project},
+			mcp.Root{URI: fileuri.FromPath(other)},
+			mcp.Root{URI: fileuri.FromPath(filepath.Join(project, "link"))},
+		)
+		assert.Empty(t, ws.Roots(), "a root linking out of the directories is resolved first")
+	})
+
+	t.Run("no directories allow nothing", func(t *testing.T) {
+		ws := workspace(t, nil, mcp.Root{URI: "file:///"})
+		assert.Empty(t, ws.Roots())
+	})
+}